@@ -16,8 +16,18 @@ func newAdminCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	}
 
 	cmd.AddCommand(newAdminCleanupCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminPurgeCmd(cfg, jsonOutput))
 	cmd.AddCommand(newAdminGCBlobsCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminBlobUsageCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminRecomputeCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminMaintainCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminReindexCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminMaintenanceModeCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminBackupCmd(cfg, jsonOutput))
 	cmd.AddCommand(newAdminUserCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminTokenCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminAuditCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminMigrateCmd(cfg, jsonOutput))
 	return cmd
 }
 
@@ -82,6 +92,67 @@ func newAdminCleanupCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	return cmd
 }
 
+func newAdminPurgeCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var (
+		olderThan int
+		dryRun    bool
+		force     bool
+		project   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Permanently remove old tombstoned tasks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if olderThan <= 0 {
+				return fmt.Errorf("--older-than must be > 0")
+			}
+			if !force && !dryRun {
+				dryRun = true
+			}
+
+			return withClient(cfg, func(client *api.Client) error {
+				req := api.CleanupRequest{
+					OlderThanDays: olderThan,
+					DryRun:        dryRun,
+					Project:       project,
+				}
+				resp, err := client.AdminPurge(cmd.Context(), req, force)
+				if err != nil {
+					return err
+				}
+
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+
+				if resp.DryRun {
+					if err := writePlain("dry run: %d tombstoned tasks would be removed\n", resp.Count); err != nil {
+						return err
+					}
+				} else {
+					if err := writePlain("removed %d tombstoned tasks\n", resp.Count); err != nil {
+						return err
+					}
+				}
+				for _, id := range resp.TaskIDs {
+					if err := writePlain("  %s\n", id); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&olderThan, "older-than", 0, "purge tasks deleted more than N days ago (required)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be removed without deleting")
+	cmd.Flags().BoolVar(&force, "force", false, "actually delete tasks (required for non-dry-run)")
+	cmd.Flags().StringVar(&project, "project", "", "optional project scope for purge (e.g. gr)")
+
+	return cmd
+}
+
 func newAdminGCBlobsCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	var (
 		dryRun    bool
@@ -120,3 +191,134 @@ func newAdminGCBlobsCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	cmd.Flags().IntVar(&batchSize, "batch-size", 0, "apply-mode batch size (default: server attachment gc batch size)")
 	return cmd
 }
+
+func newAdminBlobUsageCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "blob-usage",
+		Short: "Summarize managed blob storage and dedup savings",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.AdminBlobUsage(cmd.Context(), limit)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				if err := writePlain("blobs=%d total_bytes=%d unreferenced=%d unreferenced_bytes=%d\n", resp.BlobCount, resp.TotalBytes, resp.UnreferencedCount, resp.UnreferencedBytes); err != nil {
+					return err
+				}
+				if err := writePlain("managed_attachments=%d attachments_per_blob=%.2f dedup_saved_bytes=%d\n", resp.ManagedAttachmentCount, resp.AttachmentsPerBlob, resp.DedupSavedBytes); err != nil {
+					return err
+				}
+				for _, blob := range resp.Largest {
+					if err := writePlain("%s %d bytes, %d attachments\n", blob.SHA256, blob.SizeBytes, blob.AttachmentCount); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 0, "number of largest blobs to list (default 10, max 100)")
+	return cmd
+}
+
+func newAdminMaintainCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var req api.MaintenanceRequest
+
+	cmd := &cobra.Command{
+		Use:   "maintain",
+		Short: "Check database integrity and optionally VACUUM or rebuild the search index",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.AdminMaintenance(cmd.Context(), req)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					if err := writeJSON(resp); err != nil {
+						return err
+					}
+				} else if err := writeMaintenanceResult(resp); err != nil {
+					return err
+				}
+				if !resp.IntegrityOK {
+					return fmt.Errorf("integrity check failed with %d problem(s)", len(resp.IntegrityErrors))
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&req.Vacuum, "vacuum", false, "compact the database file with VACUUM")
+	cmd.Flags().BoolVar(&req.RebuildFTS, "rebuild-fts", false, "rebuild the full-text search index from tasks")
+	return cmd
+}
+
+func writeMaintenanceResult(resp api.MaintenanceResponse) error {
+	if resp.IntegrityOK {
+		if err := writePlain("integrity: ok\n"); err != nil {
+			return err
+		}
+	} else {
+		if err := writePlain("integrity: FAILED (vacuum and rebuild skipped)\n"); err != nil {
+			return err
+		}
+		for _, problem := range resp.IntegrityErrors {
+			if err := writePlain("  %s\n", problem); err != nil {
+				return err
+			}
+		}
+	}
+	if resp.FTSRebuilt {
+		if err := writePlain("search index rebuilt: %d rows\n", resp.FTSRows); err != nil {
+			return err
+		}
+	}
+	if resp.Vacuumed {
+		if err := writePlain("vacuumed: %d -> %d bytes\n", resp.SizeBeforeBytes, resp.SizeAfterBytes); err != nil {
+			return err
+		}
+	}
+	return writePlain("size: %d bytes, free pages: %d\n", resp.SizeAfterBytes, resp.FreePagesAfter)
+}
+
+func newAdminRecomputeCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var (
+		dryRun bool
+		apply  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "recompute",
+		Short: "Recompute store counts and repair search index drift",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !apply && !dryRun {
+				dryRun = true
+			}
+
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.AdminRecompute(cmd.Context(), api.RecomputeRequest{DryRun: !apply}, apply)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				mode := "dry run"
+				if !resp.DryRun {
+					mode = "applied"
+				}
+				return writePlain("%s: tasks=%d fts_rows=%d missing=%d orphaned=%d stale=%d corrected=%d\n", mode, resp.TaskRows, resp.FTSRows, len(resp.FTSMissing), len(resp.FTSOrphaned), len(resp.FTSStale), resp.Corrected)
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report drift without repairing")
+	cmd.Flags().BoolVar(&apply, "apply", false, "repair detected drift")
+	return cmd
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newAdminAuditCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var query api.AdminAuditQuery
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show the admin audit log (newest first)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				entries, err := client.AdminAuditList(cmd.Context(), query)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(map[string]any{"count": len(entries), "entries": entries})
+				}
+				if len(entries) == 0 {
+					return writePlain("no admin audit entries\n")
+				}
+				if err := writePlain("TIME\tACTION\tACTOR\tAFFECTED\n"); err != nil {
+					return err
+				}
+				for _, entry := range entries {
+					actor := entry.Actor
+					if actor == "" {
+						actor = "-"
+					}
+					affected := strconv.Itoa(entry.Affected)
+					if entry.DryRun {
+						affected += " (dry run)"
+					}
+					if err := writePlain("%s\t%s\t%s\t%s\n", entry.CreatedAt.Format(time.RFC3339), entry.Action, actor, affected); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&query.Action, "action", "", "filter by action (e.g. cleanup, token.create)")
+	cmd.Flags().StringVar(&query.Actor, "actor", "", "filter by actor")
+	cmd.Flags().StringVar(&query.Since, "since", "", "only entries at or after this time (RFC3339 or YYYY-MM-DD)")
+	cmd.Flags().IntVar(&query.Limit, "limit", 0, "max entries to return")
+	cmd.Flags().IntVar(&query.Offset, "offset", 0, "entries to skip")
+	return cmd
+}
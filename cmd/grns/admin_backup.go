@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newAdminBackupCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Take a consistent online snapshot of the database",
+		Long: "Take a consistent online snapshot of the database using SQLite's backup API.\n\n" +
+			"With --out the snapshot is downloaded to a local file. Without it the server\n" +
+			"writes the snapshot into its configured backup.dir.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				if out == "" {
+					resp, err := client.AdminBackup(cmd.Context())
+					if err != nil {
+						return err
+					}
+					if *jsonOutput {
+						return writeJSON(resp)
+					}
+					return writePlain("backup written on server: %s (%d bytes)\n", resp.Path, resp.SizeBytes)
+				}
+
+				size, err := downloadBackup(cmd, client, out)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(map[string]any{"path": out, "size_bytes": size})
+				}
+				return writePlain("backup saved to %s (%d bytes)\n", out, size)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&out, "out", "", "download the snapshot to this local file (must not exist)")
+	return cmd
+}
+
+// downloadBackup writes the snapshot beside out and renames it into place,
+// so an interrupted download never leaves a partial file at out.
+func downloadBackup(cmd *cobra.Command, client *api.Client, out string) (int64, error) {
+	if _, err := os.Stat(out); err == nil {
+		return 0, fmt.Errorf("%s already exists", out)
+	} else if !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	partial := out + ".partial"
+	file, err := os.OpenFile(partial, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	size, err := client.AdminBackupDownload(cmd.Context(), file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(partial, out)
+	}
+	if err != nil {
+		_ = os.Remove(partial)
+		return 0, err
+	}
+	return size, nil
+}
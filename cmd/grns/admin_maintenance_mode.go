@@ -0,0 +1,107 @@
+package main
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newAdminMaintenanceModeCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance-mode",
+		Short: "Pause or resume writes while backups or migrations run",
+	}
+	cmd.AddCommand(newAdminMaintenanceModeOnCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminMaintenanceModeOffCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminMaintenanceModeStatusCmd(cfg, jsonOutput))
+	return cmd
+}
+
+func newAdminMaintenanceModeOnCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var (
+		queue bool
+		req   api.MaintenanceModeRequest
+	)
+
+	cmd := &cobra.Command{
+		Use:   "on",
+		Short: "Reject writes with 503, or queue them briefly with --queue",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.Enabled = true
+			if queue {
+				req.Mode = "queue"
+			}
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.AdminSetMaintenanceMode(cmd.Context(), req)
+				if err != nil {
+					return err
+				}
+				return writeMaintenanceMode(resp, *jsonOutput)
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&queue, "queue", false, "hold writes until maintenance ends or --queue-timeout passes")
+	cmd.Flags().StringVar(&req.Reason, "reason", "", "reason reported to rejected writers")
+	cmd.Flags().IntVar(&req.RetryAfterSeconds, "retry-after", 0, "Retry-After seconds sent with rejections (default 30)")
+	cmd.Flags().IntVar(&req.QueueTimeoutSeconds, "queue-timeout", 0, "seconds a queued write waits before it is rejected (default 5)")
+	return cmd
+}
+
+func newAdminMaintenanceModeOffCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "off",
+		Short: "Resume writes and release queued ones",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.AdminSetMaintenanceMode(cmd.Context(), api.MaintenanceModeRequest{})
+				if err != nil {
+					return err
+				}
+				return writeMaintenanceMode(resp, *jsonOutput)
+			})
+		},
+	}
+}
+
+func newAdminMaintenanceModeStatusCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether writes are paused",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.AdminMaintenanceMode(cmd.Context())
+				if err != nil {
+					return err
+				}
+				return writeMaintenanceMode(resp, *jsonOutput)
+			})
+		},
+	}
+}
+
+func writeMaintenanceMode(resp api.MaintenanceModeResponse, jsonOutput bool) error {
+	if jsonOutput {
+		return writeJSON(resp)
+	}
+	if !resp.Enabled {
+		return writePlain("maintenance mode: off\n")
+	}
+	if err := writePlain("maintenance mode: on (%s, retry after %ds)\n", resp.Mode, resp.RetryAfterSeconds); err != nil {
+		return err
+	}
+	if resp.Reason != "" {
+		if err := writePlain("reason: %s\n", resp.Reason); err != nil {
+			return err
+		}
+	}
+	if resp.Since != nil {
+		if err := writePlain("since: %s\n", resp.Since.Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+	return writePlain("queued writes: %d\n", resp.Queued)
+}
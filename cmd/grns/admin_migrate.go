@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/config"
+	"grns/internal/store"
+)
+
+func newAdminMigrateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var (
+		target int
+		dryRun bool
+		force  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate --to N",
+		Short: "Move the database schema to version N, rolling back newer migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("to") {
+				return fmt.Errorf("--to is required")
+			}
+
+			db, err := openRawDB(cfg)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			plan, err := store.MigrateTo(db, target, true)
+			if err != nil {
+				return fmt.Errorf("plan migrations: %w", err)
+			}
+			rollback := len(plan) > 0 && plan[0].Direction == store.MigrationDown
+			if rollback && !dryRun && !force {
+				return fmt.Errorf("rolling back drops tables and columns added by newer migrations; back up the database, stop the server, and pass --force (or preview with --dry-run)")
+			}
+
+			steps := plan
+			if !dryRun {
+				steps, err = store.MigrateTo(db, target, false)
+				if err != nil {
+					return fmt.Errorf("migrate to %d: %w", target, err)
+				}
+			}
+
+			if *jsonOutput {
+				return writeJSON(map[string]any{"target_version": target, "dry_run": dryRun, "steps": steps})
+			}
+			if len(steps) == 0 {
+				return writePlain("schema already at version %d\n", target)
+			}
+			prefix := ""
+			if dryRun {
+				prefix = "dry run: "
+			}
+			for _, step := range steps {
+				if err := writePlain("%s%s %d: %s\n", prefix, step.Direction, step.Version, step.Description); err != nil {
+					return err
+				}
+			}
+			return writePlain("%sschema at version %d\n", prefix, target)
+		},
+	}
+
+	cmd.Flags().IntVar(&target, "to", 0, "target schema version (required)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the migration steps without applying them")
+	cmd.Flags().BoolVar(&force, "force", false, "allow rolling back migrations (required for non-dry-run rollback)")
+	return cmd
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+// reindexPollInterval is how often --wait checks reindex progress.
+const reindexPollInterval = time.Second
+
+func newAdminReindexCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var (
+		req        api.ReindexRequest
+		wait       bool
+		statusOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reindex",
+		Short: "Rebuild the search index in the background without blocking writes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				var (
+					status api.ReindexStatus
+					err    error
+				)
+				if statusOnly {
+					status, err = client.AdminReindexStatus(cmd.Context())
+				} else {
+					status, err = client.AdminStartReindex(cmd.Context(), req)
+				}
+				if err != nil {
+					return err
+				}
+				for wait && status.State == api.ReindexStateRunning {
+					if !*jsonOutput {
+						if err := writePlain("reindexing: %d/%d tasks\n", status.Processed, status.Total); err != nil {
+							return err
+						}
+					}
+					select {
+					case <-cmd.Context().Done():
+						return cmd.Context().Err()
+					case <-time.After(reindexPollInterval):
+					}
+					if status, err = client.AdminReindexStatus(cmd.Context()); err != nil {
+						return err
+					}
+				}
+				if *jsonOutput {
+					if err := writeJSON(status); err != nil {
+						return err
+					}
+				} else if err := writeReindexStatus(status); err != nil {
+					return err
+				}
+				if status.State == api.ReindexStateFailed {
+					return fmt.Errorf("reindex failed: %s", status.Error)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&req.BatchSize, "batch-size", 0, "tasks reindexed per transaction (default 500)")
+	cmd.Flags().BoolVar(&wait, "wait", false, "poll until the reindex finishes")
+	cmd.Flags().BoolVar(&statusOnly, "status", false, "show progress of the current or last reindex instead of starting one")
+	return cmd
+}
+
+func writeReindexStatus(status api.ReindexStatus) error {
+	switch status.State {
+	case api.ReindexStateRunning:
+		return writePlain("reindex running: %d/%d tasks\n", status.Processed, status.Total)
+	case api.ReindexStateDone:
+		return writePlain("reindex done: %d tasks, %d orphaned rows removed\n", status.Processed, status.Orphaned)
+	case api.ReindexStateFailed:
+		return writePlain("reindex failed after %d tasks: %s\n", status.Processed, status.Error)
+	default:
+		return writePlain("no reindex has run\n")
+	}
+}
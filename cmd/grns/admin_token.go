@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newAdminTokenCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage named API tokens",
+	}
+	cmd.AddCommand(newAdminTokenCreateCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminTokenListCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminTokenRotateCmd(cfg, jsonOutput))
+	cmd.AddCommand(newAdminTokenDeleteCmd(cfg, jsonOutput))
+	return cmd
+}
+
+func newAdminTokenCreateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var scopes []string
+	var expiresIn time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Issue one named API token (the secret is shown once)",
+		Args:  requireExactlyArgs(1, "name is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := api.AdminTokenCreateRequest{Name: args[0], Scopes: scopes}
+			if expiresIn < 0 {
+				return fmt.Errorf("--expires-in must be positive")
+			}
+			if expiresIn > 0 {
+				expiresAt := time.Now().UTC().Add(expiresIn)
+				req.ExpiresAt = &expiresAt
+			}
+
+			return withClient(cfg, func(client *api.Client) error {
+				created, err := client.AdminTokenCreate(cmd.Context(), req)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(created)
+				}
+				return writeAdminTokenSecret("created", created)
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&scopes, "scope", nil, "token scopes: read, write, admin (default read,write)")
+	cmd.Flags().DurationVar(&expiresIn, "expires-in", 0, "token lifetime, e.g. 720h (default never expires)")
+	return cmd
+}
+
+func newAdminTokenListCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List named API tokens",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				tokens, err := client.AdminTokenList(cmd.Context())
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(map[string]any{"count": len(tokens), "tokens": tokens})
+				}
+				if len(tokens) == 0 {
+					return writePlain("no api tokens configured\n")
+				}
+				if err := writePlain("NAME\tSCOPES\tEXPIRES\tID\n"); err != nil {
+					return err
+				}
+				for _, token := range tokens {
+					expires := "never"
+					if token.ExpiresAt != nil {
+						expires = token.ExpiresAt.Format(time.RFC3339)
+					}
+					if token.Expired {
+						expires += " (expired)"
+					}
+					if err := writePlain("%s\t%s\t%s\t%s\n", token.Name, strings.Join(token.Scopes, ","), expires, token.ID); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func newAdminTokenRotateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate <token-id>",
+		Short: "Replace a token's secret; the old secret stops working immediately",
+		Args:  requireExactlyArgs(1, "token id is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				rotated, err := client.AdminTokenRotate(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(rotated)
+				}
+				return writeAdminTokenSecret("rotated", rotated)
+			})
+		},
+	}
+}
+
+func newAdminTokenDeleteCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:     "delete <token-id>",
+		Aliases: []string{"rm"},
+		Short:   "Revoke one API token",
+		Args:    requireExactlyArgs(1, "token id is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.AdminTokenDelete(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				return writePlain("revoked api token %s\n", resp.ID)
+			})
+		},
+	}
+}
+
+func writeAdminTokenSecret(action string, token api.AdminToken) error {
+	return writePlain("%s api token %s (%s)\ntoken: %s\nstore this secret now; it will not be shown again\n", action, token.Name, token.ID, token.Token)
+}
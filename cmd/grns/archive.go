@@ -0,0 +1,183 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+// A project archive is a gzipped tar holding blobs/<sha256> for every managed
+// attachment blob, followed by tasks.ndjson with the export records. Blobs come
+// first so an import can upload them before the records that reference them.
+const (
+	archiveTasksEntry = "tasks.ndjson"
+	archiveBlobPrefix = "blobs/"
+)
+
+type archiveBlob struct {
+	attachmentID string
+	sha256       string
+	sizeBytes    int64
+}
+
+// writeExportArchive exports the project into a new archive at path. The
+// archive is written next to path and renamed into place once complete.
+func writeExportArchive(ctx context.Context, client *api.Client, path string) (err error) {
+	records, err := os.CreateTemp("", "grns-export-*.ndjson")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(records.Name())
+	defer records.Close()
+
+	if err := client.Export(ctx, records); err != nil {
+		return err
+	}
+	if _, err := records.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	blobs, err := exportedBlobs(records)
+	if err != nil {
+		return err
+	}
+	recordsSize, err := records.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := records.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	partial := path + ".partial"
+	out, err := os.Create(partial)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		out.Close()
+		if err != nil {
+			os.Remove(partial)
+		}
+	}()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	now := time.Now().UTC()
+	for _, blob := range blobs {
+		if err := tw.WriteHeader(&tar.Header{Name: archiveBlobPrefix + blob.sha256, Mode: 0o644, Size: blob.sizeBytes, ModTime: now}); err != nil {
+			return err
+		}
+		if err := client.GetAttachmentContent(ctx, blob.attachmentID, tw); err != nil {
+			return fmt.Errorf("attachment %s: %w", blob.attachmentID, err)
+		}
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: archiveTasksEntry, Mode: 0o644, Size: recordsSize, ModTime: now}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, records); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(partial, path)
+}
+
+// exportedBlobs lists each managed blob referenced by the export records once.
+func exportedBlobs(records io.Reader) ([]archiveBlob, error) {
+	var blobs []archiveBlob
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(records)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec struct {
+			Attachments []api.AttachmentRecord `json:"attachments"`
+		}
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("reading export: %w", err)
+		}
+		for _, attachment := range rec.Attachments {
+			if attachment.SourceType != string(models.AttachmentSourceManagedBlob) || attachment.SHA256 == "" || seen[attachment.SHA256] {
+				continue
+			}
+			seen[attachment.SHA256] = true
+			blobs = append(blobs, archiveBlob{attachmentID: attachment.ID, sha256: attachment.SHA256, sizeBytes: attachment.SizeBytes})
+		}
+	}
+	return blobs, scanner.Err()
+}
+
+// importArchive uploads the archive's blobs and then passes tasks.ndjson to
+// importRecords. Dry runs upload nothing.
+func importArchive(ctx context.Context, client *api.Client, path string, dryRun bool, importRecords func(io.Reader) (api.ImportResponse, error)) (api.ImportResponse, error) {
+	var resp api.ImportResponse
+	f, err := os.Open(path)
+	if err != nil {
+		return resp, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return resp, fmt.Errorf("reading archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	imported := false
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return resp, fmt.Errorf("reading archive: %w", err)
+		}
+		if imported {
+			return resp, fmt.Errorf("archive has %s after %s", header.Name, archiveTasksEntry)
+		}
+
+		switch {
+		case header.Name == archiveTasksEntry:
+			resp, err = importRecords(tr)
+			if err != nil {
+				return resp, err
+			}
+			imported = true
+		case strings.HasPrefix(header.Name, archiveBlobPrefix):
+			if dryRun {
+				continue
+			}
+			sha := strings.TrimPrefix(header.Name, archiveBlobPrefix)
+			if _, err := client.UploadBlob(ctx, tr, sha); err != nil {
+				return resp, fmt.Errorf("blob %s: %w", sha, err)
+			}
+		default:
+			return resp, fmt.Errorf("unexpected archive entry %q", header.Name)
+		}
+	}
+	if !imported {
+		return resp, fmt.Errorf("archive has no %s", archiveTasksEntry)
+	}
+	return resp, nil
+}
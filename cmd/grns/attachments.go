@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -36,6 +40,7 @@ func newAttachCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 		newAttachAddLinkCmd(cfg, jsonOutput),
 		newAttachListCmd(cfg, jsonOutput),
 		newAttachShowCmd(cfg, jsonOutput),
+		newAttachUpdateCmd(cfg, jsonOutput),
 		newAttachGetCmd(cfg),
 		newAttachRemoveCmd(cfg, jsonOutput),
 	)
@@ -174,10 +179,68 @@ func newAttachShowCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	}
 }
 
+func newAttachUpdateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var (
+		title        string
+		labels       []string
+		metaJSON     string
+		expiresAt    string
+		clearExpires bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update <attachment-id>",
+		Short: "Update an attachment's title, labels, meta, or expiry",
+		Args:  requireExactlyArgs(1, "attachment id is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := api.AttachmentUpdateRequest{ClearExpiresAt: clearExpires}
+			if cmd.Flags().Changed("title") {
+				req.Title = &title
+			}
+			if cmd.Flags().Changed("label") {
+				req.Labels = &labels
+			}
+			if cmd.Flags().Changed("meta") {
+				meta := map[string]any{}
+				if err := json.Unmarshal([]byte(metaJSON), &meta); err != nil {
+					return fmt.Errorf("invalid --meta JSON object: %w", err)
+				}
+				req.Meta = &meta
+			}
+			if expiresAt != "" {
+				parsed, err := parseOptionalAttachmentTime(expiresAt)
+				if err != nil {
+					return err
+				}
+				req.ExpiresAt = parsed
+			}
+			if req.Title == nil && req.Labels == nil && req.Meta == nil && req.ExpiresAt == nil && !req.ClearExpiresAt {
+				return errors.New("nothing to update; pass --title, --label, --meta, --expires-at, or --clear-expires")
+			}
+
+			return withClient(cfg, func(client *api.Client) error {
+				attachment, err := client.UpdateAttachment(cmd.Context(), args[0], req)
+				if err != nil {
+					return err
+				}
+				return writeAttachment(attachment, *jsonOutput)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&title, "title", "", "new title (empty clears it)")
+	cmd.Flags().StringSliceVar(&labels, "label", nil, "replace labels (repeatable; --label= clears them)")
+	cmd.Flags().StringVar(&metaJSON, "meta", "", "replace meta with a JSON object ('{}' clears it)")
+	cmd.Flags().StringVar(&expiresAt, "expires-at", "", "expiry time (RFC3339 or YYYY-MM-DD)")
+	cmd.Flags().BoolVar(&clearExpires, "clear-expires", false, "remove the expiry")
+	return cmd
+}
+
 func newAttachGetCmd(cfg *config.Config) *cobra.Command {
 	var (
 		outPath string
 		force   bool
+		resume  bool
 	)
 
 	cmd := &cobra.Command{
@@ -188,13 +251,23 @@ func newAttachGetCmd(cfg *config.Config) *cobra.Command {
 			if strings.TrimSpace(outPath) == "" {
 				return fmt.Errorf("--output is required")
 			}
-			if !force {
+			if force && resume {
+				return fmt.Errorf("--force and --resume are mutually exclusive")
+			}
+			if !force && !resume {
 				if _, err := os.Stat(outPath); err == nil {
-					return fmt.Errorf("output file exists (use --force to overwrite)")
+					return fmt.Errorf("output file exists (use --force to overwrite or --resume to continue)")
 				}
 			}
 
 			return withClient(cfg, func(client *api.Client) error {
+				if resume {
+					if err := resumeAttachmentDownload(cmd.Context(), client, args[0], outPath); err != nil {
+						return err
+					}
+					return writePlain("%s\n", outPath)
+				}
+
 				f, err := os.OpenFile(outPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
 				if err != nil {
 					return err
@@ -211,9 +284,41 @@ func newAttachGetCmd(cfg *config.Config) *cobra.Command {
 
 	cmd.Flags().StringVarP(&outPath, "output", "o", "", "output path")
 	cmd.Flags().BoolVar(&force, "force", false, "overwrite output path if it exists")
+	cmd.Flags().BoolVar(&resume, "resume", false, "continue a partial download in the output path")
 	return cmd
 }
 
+// resumeAttachmentDownload appends the rest of an attachment to the partial
+// file at path. If the server sends the whole content instead, the file is
+// rewritten from the start.
+func resumeAttachmentDownload(ctx context.Context, client *api.Client, attachmentID, path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	body, start, err := client.OpenAttachmentContent(ctx, attachmentID, info.Size())
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if err := f.Truncate(start); err != nil {
+		return err
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 func newAttachRemoveCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	return &cobra.Command{
 		Use:   "rm <attachment-id>",
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newClaimCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var (
+		req         api.TaskClaimRequest
+		priorityMax int
+		lease       time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "claim",
+		Short: "Claim the next ready task under a lease",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("priority-max") {
+				req.MaxPriority = &priorityMax
+			}
+			if lease > 0 {
+				req.LeaseSeconds = int(lease / time.Second)
+			}
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.ClaimNextTask(cmd.Context(), req)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				if err := writeTaskDetail(resp.Task); err != nil {
+					return err
+				}
+				return writePlain("lease_expires_at: %s\n", formatTime(resp.Lease.ExpiresAt))
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&req.Actor, "actor", "", "agent or user claiming the task (defaults to the request actor)")
+	cmd.Flags().StringVar(&req.Order, "order", "", "pick order (updated_at|priority|score)")
+	cmd.Flags().StringSliceVar(&req.Types, "type", nil, "only claim these types (repeatable)")
+	cmd.Flags().StringSliceVar(&req.Labels, "label", nil, "only claim tasks with all these labels (repeatable)")
+	cmd.Flags().IntVar(&priorityMax, "priority-max", 0, "only claim tasks at or above this priority")
+	cmd.Flags().DurationVar(&lease, "lease", 0, "lease duration (default 30m)")
+	return cmd
+}
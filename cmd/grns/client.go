@@ -1,12 +1,22 @@
 package main
 
 import (
+	"context"
+
 	"grns/internal/api"
 	"grns/internal/config"
 )
 
 func withClient(cfg *config.Config, fn func(*api.Client) error) error {
-	client := api.NewClient(cfg.APIURL)
+	apiURL := cfg.APIURL
+	if cfg.Server.Embedded {
+		embeddedURL, err := ensureEmbeddedServer(context.Background(), cfg)
+		if err != nil {
+			return err
+		}
+		apiURL = embeddedURL
+	}
+	client := api.NewClient(apiURL)
 	client.SetProject(cfg.ProjectPrefix)
 	return fn(client)
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -10,22 +11,38 @@ import (
 )
 
 type closeCmdOptions struct {
-	commit string
-	repo   string
+	commit   string
+	repo     string
+	filter   []string
+	query    string
+	force    bool
+	prMerged bool
 }
 
 func newCloseCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	opts := &closeCmdOptions{}
 	cmd := &cobra.Command{
 		Use:   "close <id> [<id>...]",
-		Short: "Close tasks",
-		Args:  requireAtLeastOneID,
+		Short: "Close tasks by ID, or every open task matching --filter/-q",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(opts.filter) > 0 || opts.query != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("ids cannot be combined with --filter or -q")
+				}
+				return nil
+			}
+			return requireAtLeastOneID(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return runCloseByFilter(cmd, cfg, opts, jsonOutput)
+			}
 			return withClient(cfg, func(client *api.Client) error {
 				resp, err := client.CloseTasks(cmd.Context(), api.TaskCloseRequest{
-					IDs:    args,
-					Commit: strings.TrimSpace(opts.commit),
-					Repo:   strings.TrimSpace(opts.repo),
+					IDs:      args,
+					Commit:   strings.TrimSpace(opts.commit),
+					Repo:     strings.TrimSpace(opts.repo),
+					PRMerged: opts.prMerged,
 				})
 				if err != nil {
 					return err
@@ -40,5 +57,56 @@ func newCloseCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 
 	cmd.Flags().StringVar(&opts.commit, "commit", "", "git commit hash to annotate closed tasks")
 	cmd.Flags().StringVar(&opts.repo, "repo", "", "repository slug (host/owner/repo) for close annotation")
+	cmd.Flags().StringArrayVar(&opts.filter, "filter", nil, "close open tasks matching list filter key=value, e.g. label=spike (repeatable)")
+	cmd.Flags().StringVarP(&opts.query, "query", "q", "", "close open tasks matching a search expression, e.g. 'label:spike updated<2026-01-01'")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "actually close tasks matched by a filter (without it, only a preview is shown)")
+	cmd.Flags().BoolVar(&opts.prMerged, "pr-merged", false, "assert linked pull requests have merged when workflow.require_merged_pr is on")
 	return cmd
 }
+
+// runCloseByFilter previews the tasks a filter matches and closes them only
+// with --force.
+func runCloseByFilter(cmd *cobra.Command, cfg *config.Config, opts *closeCmdOptions, jsonOutput *bool) error {
+	if opts.commit != "" || opts.repo != "" {
+		return fmt.Errorf("--commit cannot be combined with --filter or -q")
+	}
+	filter := map[string]string{}
+	for _, pair := range opts.filter {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid --filter %q, expected key=value", pair)
+		}
+		filter[strings.TrimSpace(key)] = value
+	}
+	if opts.query != "" {
+		filter["q"] = opts.query
+	}
+
+	return withClient(cfg, func(client *api.Client) error {
+		req := api.TaskCloseByFilterRequest{Filter: filter, DryRun: !opts.force, PRMerged: opts.prMerged}
+		resp, err := client.CloseTasksByFilter(cmd.Context(), req, opts.force)
+		if err != nil {
+			return err
+		}
+		if *jsonOutput {
+			return writeJSON(resp)
+		}
+
+		if resp.DryRun {
+			if err := writePlain("dry run: %d open tasks would be closed\n", resp.Count); err != nil {
+				return err
+			}
+		} else if err := writePlain("closed %d tasks\n", resp.Count); err != nil {
+			return err
+		}
+		for _, id := range resp.IDs {
+			if err := writePlain("  %s\n", id); err != nil {
+				return err
+			}
+		}
+		if resp.DryRun && resp.Count > 0 {
+			return writePlain("re-run with --force to close them\n")
+		}
+		return nil
+	})
+}
@@ -25,6 +25,7 @@ type createCmdOptions struct {
 	design             string
 	acceptanceCriteria string
 	sourceRepo         string
+	milestoneID        string
 	labels             []string
 	deps               string
 	filePath           string
@@ -107,6 +108,9 @@ func buildCreateRequest(cmd *cobra.Command, opts *createCmdOptions, args []strin
 	if opts.sourceRepo != "" {
 		req.SourceRepo = &opts.sourceRepo
 	}
+	if opts.milestoneID != "" {
+		req.MilestoneID = &opts.milestoneID
+	}
 	if len(opts.labels) > 0 {
 		req.Labels = opts.labels
 	}
@@ -140,6 +144,7 @@ func bindCreateFlags(cmd *cobra.Command, opts *createCmdOptions) {
 	cmd.Flags().StringVar(&opts.design, "design", "", "design")
 	cmd.Flags().StringVar(&opts.acceptanceCriteria, "acceptance", "", "acceptance criteria")
 	cmd.Flags().StringVar(&opts.sourceRepo, "source-repo", "", "source repository")
+	cmd.Flags().StringVar(&opts.milestoneID, "milestone", "", "milestone id")
 	cmd.Flags().StringSliceVarP(&opts.labels, "label", "l", nil, "labels")
 	cmd.Flags().StringSliceVar(&opts.labels, "labels", nil, "labels")
 	cmd.Flags().StringVar(&opts.deps, "deps", "", "dependencies")
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+	"grns/internal/models"
+)
+
+func newCustomFieldCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	cmd := &cobra.Command{Use: "custom-field", Short: "Manage the project's custom field schema"}
+	cmd.AddCommand(
+		newCustomFieldSetCmd(cfg, jsonOutput),
+		newCustomFieldListCmd(cfg, jsonOutput),
+		newCustomFieldRemoveCmd(cfg, jsonOutput),
+	)
+	return cmd
+}
+
+func newCustomFieldSetCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var req api.CustomFieldRequest
+	var values string
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Create or replace a custom field definition",
+		Args:  requireExactlyArgs(1, "name is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.Values = splitCommaList(values)
+			return withClient(cfg, func(client *api.Client) error {
+				field, err := client.PutCustomField(cmd.Context(), args[0], req)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(field)
+				}
+				return writePlain("%s\n", formatCustomField(field))
+			})
+		},
+	}
+	cmd.Flags().StringVar(&req.Type, "type", string(models.CustomFieldString), "value type (string|number|bool)")
+	cmd.Flags().BoolVar(&req.Required, "required", false, "require the field on create and on custom updates")
+	cmd.Flags().StringVar(&values, "values", "", "allowed values for a string field (comma-separated)")
+	return cmd
+}
+
+func newCustomFieldListCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "List custom field definitions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				fields, err := client.ListCustomFields(cmd.Context())
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(fields)
+				}
+				for _, field := range fields {
+					if err := writePlain("%s\n", formatCustomField(field)); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func newCustomFieldRemoveCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Delete a custom field definition (task values are kept)",
+		Args:  requireExactlyArgs(1, "name is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.DeleteCustomField(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				return writePlain("%s\n", args[0])
+			})
+		},
+	}
+}
+
+func formatCustomField(field models.CustomField) string {
+	line := field.Name + " [" + field.Type + "]"
+	if field.Required {
+		line += " required"
+	}
+	if len(field.Values) > 0 {
+		line += " (" + strings.Join(field.Values, "|") + ")"
+	}
+	return line
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newDeleteCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <id> [<id>...]",
+		Short: "Soft-delete tasks (mark as tombstone)",
+		Args:  requireAtLeastOneID,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIDsMutation(cfg, *jsonOutput, cmd.Context(), args,
+				func(ctx context.Context, client *api.Client, ids []string) (any, error) {
+					for _, id := range ids {
+						if _, err := client.DeleteTask(ctx, id); err != nil {
+							return nil, err
+						}
+					}
+					return map[string]any{"ids": ids}, nil
+				},
+			)
+		},
+	}
+
+	return cmd
+}
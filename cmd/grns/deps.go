@@ -2,6 +2,8 @@ package main
 
 import (
 	"errors"
+	"net/url"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -19,6 +21,7 @@ func newDepCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	depCmd.AddCommand(
 		newDepAddCmd(cfg, jsonOutput),
 		newDepTreeCmd(cfg, jsonOutput),
+		newDepGraphCmd(cfg, jsonOutput),
 	)
 	return depCmd
 }
@@ -54,11 +57,21 @@ func newDepAddCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 }
 
 func newDepTreeCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
-	return &cobra.Command{
+	var format string
+	cmd := &cobra.Command{
 		Use:   "tree <id>",
 		Short: "Show full dependency tree for a task",
 		Args:  requireAtLeastArgs(1, "task id is required"),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case "", "text":
+			case "dot", "mermaid":
+				return withClient(cfg, func(client *api.Client) error {
+					return client.DependencyTreeText(cmd.Context(), args[0], format, os.Stdout)
+				})
+			default:
+				return errors.New("--graph-format must be text, dot, or mermaid")
+			}
 			return withClient(cfg, func(client *api.Client) error {
 				resp, err := client.DependencyTree(cmd.Context(), args[0])
 				if err != nil {
@@ -71,6 +84,44 @@ func newDepTreeCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 			})
 		},
 	}
+	cmd.Flags().StringVar(&format, "graph-format", "text", "tree rendering: text|dot|mermaid (--format json|template renders the tree JSON instead)")
+	return cmd
+}
+
+func newDepGraphCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var format, status, label, taskType string
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export the dependency graph as GraphViz DOT or JSON",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			setIfNotEmpty(query, "status", status)
+			setIfNotEmpty(query, "label", label)
+			setIfNotEmpty(query, "type", taskType)
+			if *jsonOutput {
+				format = "json"
+			}
+			return withClient(cfg, func(client *api.Client) error {
+				switch format {
+				case "dot":
+					return client.DependencyGraphDOT(cmd.Context(), query, os.Stdout)
+				case "json":
+					resp, err := client.DependencyGraph(cmd.Context(), query)
+					if err != nil {
+						return err
+					}
+					return writeJSON(resp)
+				default:
+					return errors.New("--graph-format must be dot or json")
+				}
+			})
+		},
+	}
+	cmd.Flags().StringVar(&format, "graph-format", "dot", "graph rendering: dot|json (--format json|template implies json)")
+	cmd.Flags().StringVar(&status, "status", "", "filter by status (comma-separated)")
+	cmd.Flags().StringVar(&label, "label", "", "filter by labels (comma-separated, AND)")
+	cmd.Flags().StringVar(&taskType, "type", "", "filter by type (comma-separated)")
+	return cmd
 }
 
 func writeDependencyTree(rootID string, nodes []models.DepTreeNode) error {
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+const defaultEditor = "vi"
+
+// editDocument is the YAML front matter of a task edit document. The long
+// text fields follow it as Markdown sections.
+type editDocument struct {
+	Title         string `yaml:"title"`
+	Status        string `yaml:"status"`
+	Type          string `yaml:"type"`
+	Priority      int    `yaml:"priority"`
+	Assignee      string `yaml:"assignee"`
+	SpecID        string `yaml:"spec_id"`
+	ParentID      string `yaml:"parent_id"`
+	MilestoneID   string `yaml:"milestone_id"`
+	SourceRepo    string `yaml:"source_repo"`
+	BlockedReason string `yaml:"blocked_reason"`
+	BlockedOn     string `yaml:"blocked_on"`
+
+	Description        string `yaml:"-"`
+	Design             string `yaml:"-"`
+	AcceptanceCriteria string `yaml:"-"`
+	Notes              string `yaml:"-"`
+}
+
+// editSections are the Markdown headings of the long fields, in document order.
+var editSections = []string{"Description", "Design", "Acceptance Criteria", "Notes"}
+
+var editSectionRegex = regexp.MustCompile(`^## (Description|Design|Acceptance Criteria|Notes)\s*$`)
+
+func newEditCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Edit a task in $EDITOR",
+		Long: "Open the task as a Markdown document in $VISUAL or $EDITOR (default vi): short fields " +
+			"as YAML front matter, description, design, acceptance criteria, and notes as sections.\n\n" +
+			"Only fields that changed in the editor are sent, so concurrent updates to other fields " +
+			"are kept. If the document cannot be parsed or the update fails, the edited file is kept " +
+			"and its path is printed.",
+		Args: requireExactlyArgs(1, "task id is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				task, err := client.GetTask(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				path, original, err := writeEditFile(editDocumentFromTask(task))
+				if err != nil {
+					return err
+				}
+				if err := runEditor(cmd.Context(), path); err != nil {
+					return fmt.Errorf("%w (edits kept in %s)", err, path)
+				}
+
+				req, err := readEditFile(path, original)
+				if err != nil {
+					return fmt.Errorf("%w (edits kept in %s)", err, path)
+				}
+				if !hasTaskUpdateFields(req) {
+					_ = os.Remove(path)
+					return writePlain("no changes\n")
+				}
+				resp, err := client.UpdateTask(cmd.Context(), task.ID, req)
+				if err != nil {
+					return fmt.Errorf("%w (edits kept in %s)", err, path)
+				}
+				_ = os.Remove(path)
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				return writePlain("%s\n", resp.ID)
+			})
+		},
+	}
+	return cmd
+}
+
+func editDocumentFromTask(task api.TaskResponse) editDocument {
+	return editDocument{
+		Title:              task.Title,
+		Status:             task.Status,
+		Type:               task.Type,
+		Priority:           task.Priority,
+		Assignee:           task.Assignee,
+		SpecID:             task.SpecID,
+		ParentID:           task.ParentID,
+		MilestoneID:        task.MilestoneID,
+		SourceRepo:         task.SourceRepo,
+		BlockedReason:      task.BlockedReason,
+		BlockedOn:          task.BlockedOn,
+		Description:        task.Description,
+		Design:             task.Design,
+		AcceptanceCriteria: task.AcceptanceCriteria,
+		Notes:              task.Notes,
+	}
+}
+
+// writeEditFile writes doc to a temporary file. It returns the document as
+// parsed back from that file, so text that does not survive the round trip,
+// such as a section heading inside a description, is not mistaken for an edit.
+func writeEditFile(doc editDocument) (string, editDocument, error) {
+	data, err := renderEditDocument(doc)
+	if err != nil {
+		return "", doc, err
+	}
+	baseline, err := parseEditDocument(string(data))
+	if err != nil {
+		return "", doc, err
+	}
+	file, err := os.CreateTemp("", "grns-edit-*.md")
+	if err != nil {
+		return "", doc, err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return "", doc, err
+	}
+	return file.Name(), baseline, file.Close()
+}
+
+func readEditFile(path string, original editDocument) (api.TaskUpdateRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return api.TaskUpdateRequest{}, err
+	}
+	edited, err := parseEditDocument(string(data))
+	if err != nil {
+		return api.TaskUpdateRequest{}, err
+	}
+	return diffEditDocuments(original, edited), nil
+}
+
+// runEditor opens path in $VISUAL, $EDITOR, or vi. The variable may carry
+// arguments, as in "code --wait".
+func runEditor(ctx context.Context, path string) error {
+	editor := strings.TrimSpace(os.Getenv("VISUAL"))
+	if editor == "" {
+		editor = strings.TrimSpace(os.Getenv("EDITOR"))
+	}
+	if editor == "" {
+		editor = defaultEditor
+	}
+	fields := strings.Fields(editor)
+	cmd := exec.CommandContext(ctx, fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s: %w", fields[0], err)
+	}
+	return nil
+}
+
+func renderEditDocument(doc editDocument) ([]byte, error) {
+	front, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(front)
+	buf.WriteString("---\n")
+	for i, body := range []string{doc.Description, doc.Design, doc.AcceptanceCriteria, doc.Notes} {
+		fmt.Fprintf(&buf, "\n## %s\n\n", editSections[i])
+		if body != "" {
+			buf.WriteString(body)
+			buf.WriteString("\n")
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// parseEditDocument reads a document written by renderEditDocument. Section
+// bodies are trimmed of surrounding blank lines; a missing section parses as
+// empty.
+func parseEditDocument(input string) (editDocument, error) {
+	var doc editDocument
+	lines := strings.Split(input, "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[0]) != "---" {
+		return doc, fmt.Errorf("edit document must start with --- front matter")
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return doc, fmt.Errorf("front matter not closed")
+	}
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "\n")), &doc); err != nil {
+		return doc, fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	sections := map[string][]string{}
+	current := ""
+	for _, line := range lines[end+1:] {
+		if match := editSectionRegex.FindStringSubmatch(line); match != nil {
+			current = match[1]
+			continue
+		}
+		if current != "" {
+			sections[current] = append(sections[current], line)
+		} else if strings.TrimSpace(line) != "" {
+			return doc, fmt.Errorf("text before the first section: %q", line)
+		}
+	}
+	body := func(name string) string {
+		return strings.Trim(strings.Join(sections[name], "\n"), "\n")
+	}
+	doc.Description = body("Description")
+	doc.Design = body("Design")
+	doc.AcceptanceCriteria = body("Acceptance Criteria")
+	doc.Notes = body("Notes")
+	return doc, nil
+}
+
+// diffEditDocuments sets the update fields that differ between original and edited.
+func diffEditDocuments(original, edited editDocument) api.TaskUpdateRequest {
+	req := api.TaskUpdateRequest{}
+	setString := func(dst **string, before, after string) {
+		if before != after {
+			*dst = &after
+		}
+	}
+	setString(&req.Title, original.Title, edited.Title)
+	setString(&req.Status, original.Status, edited.Status)
+	setString(&req.Type, original.Type, edited.Type)
+	if original.Priority != edited.Priority {
+		req.Priority = &edited.Priority
+	}
+	setString(&req.Assignee, original.Assignee, edited.Assignee)
+	setString(&req.SpecID, original.SpecID, edited.SpecID)
+	setString(&req.ParentID, original.ParentID, edited.ParentID)
+	setString(&req.MilestoneID, original.MilestoneID, edited.MilestoneID)
+	setString(&req.SourceRepo, original.SourceRepo, edited.SourceRepo)
+	setString(&req.BlockedReason, original.BlockedReason, edited.BlockedReason)
+	setString(&req.BlockedOn, original.BlockedOn, edited.BlockedOn)
+	setString(&req.Description, original.Description, edited.Description)
+	setString(&req.Design, original.Design, edited.Design)
+	setString(&req.AcceptanceCriteria, original.AcceptanceCriteria, edited.AcceptanceCriteria)
+	setString(&req.Notes, original.Notes, edited.Notes)
+	return req
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEditDocumentRoundTripAndDiff(t *testing.T) {
+	original := editDocument{
+		Title:       "Fix login",
+		Status:      "open",
+		Type:        "bug",
+		Priority:    2,
+		Description: "First paragraph.\n\nSecond paragraph.",
+		Design:      "## Notes is a heading here",
+	}
+	data, err := renderEditDocument(original)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	baseline, err := parseEditDocument(string(data))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if baseline.Description != original.Description || baseline.Title != original.Title {
+		t.Fatalf("round trip lost fields: %#v", baseline)
+	}
+	if req := diffEditDocuments(baseline, baseline); hasTaskUpdateFields(req) {
+		t.Fatalf("expected no changes for an untouched document, got %#v", req)
+	}
+
+	edited := strings.Replace(string(data), "priority: 2", "priority: 1", 1)
+	edited = strings.Replace(edited, "Second paragraph.", "Second paragraph, edited.\n\nThird.", 1)
+	parsed, err := parseEditDocument(edited)
+	if err != nil {
+		t.Fatalf("parse edited: %v", err)
+	}
+	req := diffEditDocuments(baseline, parsed)
+	if req.Priority == nil || *req.Priority != 1 || req.Description == nil || *req.Description != "First paragraph.\n\nSecond paragraph, edited.\n\nThird." {
+		t.Fatalf("unexpected update: %#v", req)
+	}
+	if req.Title != nil || req.Design != nil || req.Notes != nil || req.Status != nil {
+		t.Fatalf("unchanged fields were sent: %#v", req)
+	}
+
+	if _, err := parseEditDocument("no front matter"); err == nil {
+		t.Fatal("expected error without front matter")
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/config"
+	"grns/internal/store"
+)
+
+const (
+	embeddedStartTimeout = 10 * time.Second
+	embeddedStopTimeout  = 15 * time.Second
+	embeddedPollInterval = 100 * time.Millisecond
+)
+
+// embeddedPaths are the managed server's socket, pid file, and log, kept next
+// to the database so each database gets its own server.
+type embeddedPaths struct {
+	socket string
+	pid    string
+	log    string
+}
+
+func embeddedPathsFor(cfg *config.Config) (embeddedPaths, error) {
+	if cfg.DBPath == "" || store.IsMemoryPath(cfg.DBPath) {
+		return embeddedPaths{}, fmt.Errorf("the embedded server needs an on-disk database (db_path)")
+	}
+	return embeddedPaths{socket: cfg.DBPath + ".sock", pid: cfg.DBPath + ".pid", log: cfg.DBPath + ".log"}, nil
+}
+
+func (p embeddedPaths) apiURL() string {
+	return "unix://" + p.socket
+}
+
+// ensureEmbeddedServer returns the managed server's API URL, starting the
+// server first unless it already answers its health check.
+func ensureEmbeddedServer(ctx context.Context, cfg *config.Config) (string, error) {
+	paths, err := embeddedPathsFor(cfg)
+	if err != nil {
+		return "", err
+	}
+	client := api.NewClient(paths.apiURL())
+	if client.Ping(ctx) == nil {
+		return paths.apiURL(), nil
+	}
+	if err := startEmbeddedServer(cfg, paths); err != nil {
+		return "", fmt.Errorf("start embedded server: %w", err)
+	}
+	if !waitForHealth(ctx, client, true, embeddedStartTimeout) {
+		return "", fmt.Errorf("embedded server did not become healthy within %s; see %s", embeddedStartTimeout, paths.log)
+	}
+	return paths.apiURL(), nil
+}
+
+// startEmbeddedServer runs "grns srv" detached from the terminal, logging to
+// paths.log. The child writes its own pid file once it is listening, so a
+// child that loses a start race to another one never overwrites it.
+func startEmbeddedServer(cfg *config.Config, paths embeddedPaths) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(paths.log, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, "srv", "--listen", paths.apiURL(), "--pid-file", paths.pid)
+	cmd.Env = append(os.Environ(), "GRNS_DB="+cfg.DBPath)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// stopEmbeddedServer sends SIGTERM to the managed server and waits until its
+// socket stops answering.
+func stopEmbeddedServer(ctx context.Context, cfg *config.Config) error {
+	paths, err := embeddedPathsFor(cfg)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(paths.pid)
+	if errors.Is(err, os.ErrNotExist) {
+		return writePlain("embedded server is not running\n")
+	}
+	if err != nil {
+		return err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return fmt.Errorf("invalid pid file %s", paths.pid)
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		if errors.Is(err, os.ErrProcessDone) {
+			_ = os.Remove(paths.pid)
+			return writePlain("embedded server is not running\n")
+		}
+		return fmt.Errorf("stop embedded server (pid %d): %w", pid, err)
+	}
+	if !waitForHealth(ctx, api.NewClient(paths.apiURL()), false, embeddedStopTimeout) {
+		return fmt.Errorf("embedded server (pid %d) did not stop within %s", pid, embeddedStopTimeout)
+	}
+	return writePlain("stopped embedded server (pid %d)\n", pid)
+}
+
+// waitForHealth polls the server until its health check passes (healthy) or
+// fails (!healthy), reporting whether that happened before timeout.
+func waitForHealth(ctx context.Context, client *api.Client, healthy bool, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if (client.Ping(ctx) == nil) == healthy {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(embeddedPollInterval):
+		}
+	}
+}
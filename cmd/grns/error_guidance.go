@@ -23,11 +23,17 @@ func formatCLIError(err error) []string {
 			lines = append(lines, "hint: verify GRNS_API_TOKEN and GRNS_ADMIN_TOKEN configuration.")
 		case "resource_exhausted":
 			lines = append(lines, "hint: retry shortly or reduce concurrent heavy requests (import/export/search).")
+		case "wip_limit_exceeded":
+			lines = append(lines, "hint: finish or hand off in-progress work first, or pass --force to override the wip limit.")
+		case "pr_not_merged":
+			lines = append(lines, "hint: merge the pull request, or pass --pr-merged to grns close if it landed another way.")
+		case "maintenance_mode":
+			lines = append(lines, "hint: the server is paused for maintenance; retry later or check grns admin maintenance-mode status.")
 		}
 		if apiErr.Code == "" {
 			lines = append(lines, "hint: verify GRNS_API_URL points to a grns server.")
 		}
-		if apiErr.Status >= 500 {
+		if apiErr.Status >= 500 && apiErr.Code != "maintenance_mode" {
 			lines = append(lines, "hint: server returned an internal error; check server logs for details.")
 		}
 		return uniqueLines(lines)
@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -12,16 +14,38 @@ import (
 
 func newExportCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	var outputPath string
+	var since string
+	var cursorFile string
+	var archivePath string
 
 	cmd := &cobra.Command{
 		Use:   "export",
 		Short: "Export all tasks as JSONL",
+		Long: "Export all tasks as JSONL.\n\n" +
+			"With --since, only tasks changed after a cursor or timestamp are exported, including " +
+			"tombstoned (deleted) tasks. The cursor for the next run is printed to stderr, or kept in " +
+			"--cursor-file, which is read as --since when present.\n\n" +
+			"With --archive, write a .tar.gz project archive instead: the task records plus every " +
+			"attachment blob they reference, for grns import --archive on another server.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if jsonOutput != nil && *jsonOutput {
 				return fmt.Errorf("export always emits NDJSON; remove --json")
 			}
+			if archivePath != "" && (outputPath != "" || since != "" || cursorFile != "") {
+				return fmt.Errorf("--archive exports the whole project; it cannot be combined with --output, --since or --cursor-file")
+			}
+			if since == "" && cursorFile != "" {
+				stored, err := readExportCursor(cursorFile)
+				if err != nil {
+					return err
+				}
+				since = stored
+			}
 			return withClient(cfg, func(client *api.Client) error {
-				w := os.Stdout
+				if archivePath != "" {
+					return writeExportArchive(cmd.Context(), client, archivePath)
+				}
+				var w io.Writer = os.Stdout
 				if outputPath != "" {
 					f, err := os.Create(outputPath)
 					if err != nil {
@@ -30,12 +54,52 @@ func newExportCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 					defer f.Close()
 					w = f
 				}
-				return client.Export(cmd.Context(), w)
+				if since == "" {
+					return client.Export(cmd.Context(), w)
+				}
+
+				next, err := client.ExportChanges(cmd.Context(), w, since)
+				if err != nil {
+					return err
+				}
+				if cursorFile != "" {
+					return writeExportCursor(cursorFile, next)
+				}
+				_, err = fmt.Fprintf(os.Stderr, "next cursor: %s\n", next)
+				return err
 			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "output file (default: stdout)")
+	cmd.Flags().StringVar(&since, "since", "", "only tasks changed after this cursor or time (RFC3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&archivePath, "archive", "", "write a project archive (.tar.gz) with attachment blobs to this path")
+	cmd.Flags().StringVar(&cursorFile, "cursor-file", "", "read --since from and save the next cursor to this file (starts from 0 when missing)")
 
 	return cmd
 }
+
+// readExportCursor returns the stored cursor, or "0" (every task) when the file does not exist yet.
+func readExportCursor(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "0", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	cursor := strings.TrimSpace(string(data))
+	if cursor == "" {
+		return "0", nil
+	}
+	return cursor, nil
+}
+
+// writeExportCursor replaces the cursor file only after the export succeeded.
+func writeExportCursor(path, cursor string) error {
+	partial := path + ".partial"
+	if err := os.WriteFile(partial, []byte(cursor+"\n"), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(partial, path)
+}
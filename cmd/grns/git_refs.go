@@ -26,6 +26,8 @@ func newGitCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 		newGitAddCmd(cfg, jsonOutput),
 		newGitListCmd(cfg, jsonOutput),
 		newGitRemoveCmd(cfg, jsonOutput),
+		newGitScanCmd(cfg, jsonOutput),
+		newGitResolveCmd(cfg, jsonOutput),
 	)
 	return cmd
 }
@@ -123,6 +125,29 @@ func newGitRemoveCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	}
 }
 
+func newGitResolveCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resolve <git-ref-id>",
+		Short: "Resolve a git reference against its hosting provider",
+		Long: "Ask GitHub or GitLab which commit a branch, tag, or commit ref points to, and which " +
+			"pull requests contain it. The commit is stored as resolved_commit and the pull request " +
+			"URLs under meta.pull_requests.",
+		Args: requireExactlyArgs(1, "git ref id is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				ref, err := client.ResolveTaskGitRef(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(ref)
+				}
+				return writeTaskGitRef(ref)
+			})
+		},
+	}
+}
+
 func writeTaskGitRef(ref models.TaskGitRef) error {
 	lines := []string{
 		fmt.Sprintf("id: %s", ref.ID),
@@ -138,5 +163,10 @@ func writeTaskGitRef(ref models.TaskGitRef) error {
 	if ref.Note != "" {
 		lines = append(lines, fmt.Sprintf("note: %s", ref.Note))
 	}
+	if pulls, ok := ref.Meta["pull_requests"].([]any); ok {
+		for _, pull := range pulls {
+			lines = append(lines, fmt.Sprintf("pull_request: %v", pull))
+		}
+	}
 	return writePlain("%s\n", strings.Join(lines, "\n"))
 }
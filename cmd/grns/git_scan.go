@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newGitScanCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var (
+		repo     string
+		maxCount int
+		dryRun   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scan [<revision-range>]",
+		Short: "Link tasks mentioned in commit messages",
+		Long: "Read commits from the local git repository (default range HEAD) and add a " +
+			"mentioned_by commit ref to every task whose id appears in a commit message.\n\n" +
+			"The repo defaults to the origin remote. Refs that already exist are skipped, so " +
+			"scanning the same range again is safe.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			revisionRange := "HEAD"
+			if len(args) == 1 {
+				revisionRange = args[0]
+			}
+			if maxCount <= 0 {
+				return fmt.Errorf("--max-count must be positive")
+			}
+			if strings.TrimSpace(repo) == "" {
+				remote, err := runGit(cmd.Context(), "remote", "get-url", "origin")
+				if err != nil {
+					return fmt.Errorf("--repo is required when origin is not set: %w", err)
+				}
+				repo = strings.TrimSpace(remote)
+			}
+			commits, err := readGitCommits(cmd.Context(), revisionRange, maxCount)
+			if err != nil {
+				return err
+			}
+			if len(commits) == 0 {
+				return fmt.Errorf("no commits in %s", revisionRange)
+			}
+
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.ScanGitRefs(cmd.Context(), api.GitRefScanRequest{Repo: repo, Commits: commits, DryRun: dryRun})
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				return writeGitScanResponse(resp)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "repository slug or remote URL (default: origin remote)")
+	cmd.Flags().IntVarP(&maxCount, "max-count", "n", 500, "scan at most this many commits")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report the refs that would be created without creating them")
+	return cmd
+}
+
+// readGitCommits returns the sha and full message of up to maxCount commits in revisionRange.
+func readGitCommits(ctx context.Context, revisionRange string, maxCount int) ([]api.GitRefScanCommit, error) {
+	out, err := runGit(ctx, "log", "--format=%H%x00%B%x1e", "-n", strconv.Itoa(maxCount), revisionRange, "--")
+	if err != nil {
+		return nil, err
+	}
+	commits := []api.GitRefScanCommit{}
+	for record := range strings.SplitSeq(out, "\x1e") {
+		sha, message, ok := strings.Cut(strings.TrimLeft(record, "\n"), "\x00")
+		if !ok {
+			continue
+		}
+		commits = append(commits, api.GitRefScanCommit{SHA: sha, Message: strings.TrimSpace(message)})
+	}
+	return commits, nil
+}
+
+func runGit(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+func writeGitScanResponse(resp api.GitRefScanResponse) error {
+	verb := "created"
+	if resp.DryRun {
+		verb = "would create"
+	}
+	for _, ref := range resp.Created {
+		if err := writePlain("%s %s %s\n", ref.TaskID, ref.Relation, ref.ObjectValue); err != nil {
+			return err
+		}
+	}
+	if len(resp.UnknownTaskIDs) > 0 {
+		if err := writePlain("unknown task ids: %s\n", strings.Join(resp.UnknownTaskIDs, ", ")); err != nil {
+			return err
+		}
+	}
+	return writePlain("commits: %d, %s: %d, existing: %d\n", resp.CommitsScanned, verb, len(resp.Created), resp.Existing)
+}
@@ -5,68 +5,66 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 
 	"grns/internal/api"
 	"grns/internal/config"
+	"grns/internal/models"
 )
 
 func newImportCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	var (
 		inputPath      string
+		archivePath    string
 		dryRun         bool
 		dedupe         string
 		orphanHandling string
 		atomic         bool
+		lenient        bool
 		stream         bool
+		saveReport     bool
+		conflictsFile  string
+		remapIDs       bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "import",
 		Short: "Import tasks from a JSONL file",
+		Long: "Import tasks from a JSONL file.\n\n" +
+			"With --archive, import a project archive written by grns export --archive: blobs are " +
+			"uploaded first, then the task records (with attachments and git refs) are streamed.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if inputPath == "" {
-				return errors.New("--input is required")
+			if (inputPath == "") == (archivePath == "") {
+				return errors.New("exactly one of --input or --archive is required")
 			}
 
 			return withClient(cfg, func(client *api.Client) error {
-				f, err := os.Open(inputPath)
-				if err != nil {
-					return err
+				importStream := func(records io.Reader) (api.ImportResponse, error) {
+					return client.ImportStream(cmd.Context(), records, dryRun, dedupe, orphanHandling, atomic, lenient, saveReport, remapIDs)
 				}
-				defer f.Close()
 
 				var (
 					resp      api.ImportResponse
 					importErr error
 				)
-				if stream {
-					resp, importErr = client.ImportStream(cmd.Context(), f, dryRun, dedupe, orphanHandling, atomic)
-				} else {
-					// Preserve existing import semantics by default.
-					var records []api.TaskImportRecord
-					scanner := bufio.NewScanner(f)
-					scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024)
-					lineNum := 0
-					for scanner.Scan() {
-						lineNum++
-						line := scanner.Bytes()
-						if len(line) == 0 {
-							continue
-						}
-						var rec api.TaskImportRecord
-						if err := json.Unmarshal(line, &rec); err != nil {
-							return fmt.Errorf("line %d: %w", lineNum, err)
-						}
-						records = append(records, rec)
+				switch {
+				case archivePath != "":
+					resp, importErr = importArchive(cmd.Context(), client, archivePath, dryRun, importStream)
+				case stream:
+					f, err := os.Open(inputPath)
+					if err != nil {
+						return err
 					}
-					if err := scanner.Err(); err != nil {
-						return fmt.Errorf("reading input: %w", err)
-					}
-					if len(records) == 0 {
-						return errors.New("no records found in input file")
+					defer f.Close()
+					resp, importErr = importStream(f)
+				default:
+					// Preserve existing import semantics by default.
+					records, err := readImportRecords(inputPath)
+					if err != nil {
+						return err
 					}
 					resp, importErr = client.Import(cmd.Context(), api.ImportRequest{
 						Tasks:          records,
@@ -74,32 +72,132 @@ func newImportCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 						Dedupe:         dedupe,
 						OrphanHandling: orphanHandling,
 						Atomic:         atomic,
+						Lenient:        lenient,
+						SaveReport:     saveReport,
+						RemapIDs:       remapIDs,
 					})
 				}
 				if importErr != nil {
 					return importErr
 				}
-
-				if *jsonOutput {
-					return writeJSON(resp)
-				}
-
-				if resp.ApplyMode != "" {
-					return writePlain("created: %d, updated: %d, skipped: %d, errors: %d (mode=%s, checkpoints=%d)\n",
-						resp.Created, resp.Updated, resp.Skipped, resp.Errors, resp.ApplyMode, resp.AppliedChunks)
+				if err := writeConflictsFile(conflictsFile, resp.Conflicts); err != nil {
+					return err
 				}
-				return writePlain("created: %d, updated: %d, skipped: %d, errors: %d\n",
-					resp.Created, resp.Updated, resp.Skipped, resp.Errors)
+				return writeImportResponse(resp, *jsonOutput)
 			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&inputPath, "input", "i", "", "input JSONL file")
+	cmd.Flags().StringVar(&archivePath, "archive", "", "project archive (.tar.gz) written by grns export --archive")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "preview without making changes")
-	cmd.Flags().StringVar(&dedupe, "dedupe", "skip", "dedupe mode: skip|overwrite|error")
+	cmd.Flags().StringVar(&dedupe, "dedupe", "skip", "dedupe mode: skip|overwrite|merge|error")
 	cmd.Flags().StringVar(&orphanHandling, "orphan-handling", "allow", "orphan dep handling: allow|skip|strict")
-	cmd.Flags().BoolVar(&atomic, "atomic", false, "apply each import request/chunk in a single DB transaction")
+	cmd.Flags().BoolVar(&atomic, "atomic", false, "apply the whole import in one DB transaction; any record error rolls everything back")
+	cmd.Flags().BoolVar(&lenient, "lenient", false, "coerce recoverable validation failures to defaults and report warnings")
 	cmd.Flags().BoolVar(&stream, "stream", false, "use streaming import endpoint for large files")
+	cmd.Flags().BoolVar(&saveReport, "save-report", false, "keep the conflict report on the server and print its id")
+	cmd.Flags().BoolVar(&remapIDs, "remap-ids", false, "give imported tasks new ids under this project and rewrite their references")
+	cmd.Flags().StringVar(&conflictsFile, "conflicts-file", "", "write the per-record conflict report as JSON to this file")
 
 	return cmd
 }
+
+// readImportRecords loads every JSONL record of path for a single import request.
+func readImportRecords(path string) ([]api.TaskImportRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []api.TaskImportRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec api.TaskImportRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading input: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("no records found in input file")
+	}
+	return records, nil
+}
+
+// writeConflictsFile writes the import's conflicts as a JSON array to path.
+// An empty path writes nothing; no conflicts write an empty array.
+func writeConflictsFile(path string, conflicts []models.ImportConflict) error {
+	if path == "" {
+		return nil
+	}
+	if conflicts == nil {
+		conflicts = []models.ImportConflict{}
+	}
+	data, err := json.MarshalIndent(conflicts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// writeImportResponse prints the import summary. A rolled-back atomic import is
+// reported as an error after its messages.
+func writeImportResponse(resp api.ImportResponse, jsonOutput bool) error {
+	if jsonOutput {
+		if err := writeJSON(resp); err != nil {
+			return err
+		}
+		if resp.RolledBack {
+			return fmt.Errorf("atomic import rolled back: %d record errors, nothing was imported", resp.Errors)
+		}
+		return nil
+	}
+
+	for _, warning := range resp.Warnings {
+		if err := writePlain("warning: %s\n", warning); err != nil {
+			return err
+		}
+	}
+	if resp.ReportID > 0 {
+		if err := writePlain("conflict report: %d (%d conflicts)\n", resp.ReportID, len(resp.Conflicts)); err != nil {
+			return err
+		}
+	}
+	if len(resp.IDMap) > 0 {
+		if err := writePlain("remapped ids: %d (use --json for the old to new map)\n", len(resp.IDMap)); err != nil {
+			return err
+		}
+	}
+	if resp.AttachmentsCreated > 0 || resp.GitRefsCreated > 0 {
+		if err := writePlain("attachments: %d, git refs: %d\n", resp.AttachmentsCreated, resp.GitRefsCreated); err != nil {
+			return err
+		}
+	}
+
+	if resp.RolledBack {
+		for _, message := range resp.Messages {
+			if err := writePlain("error: %s\n", message); err != nil {
+				return err
+			}
+		}
+		return fmt.Errorf("atomic import rolled back: %d record errors, nothing was imported", resp.Errors)
+	}
+	if resp.ApplyMode != "" {
+		return writePlain("created: %d, updated: %d, skipped: %d, errors: %d (mode=%s, checkpoints=%d)\n",
+			resp.Created, resp.Updated, resp.Skipped, resp.Errors, resp.ApplyMode, resp.AppliedChunks)
+	}
+	return writePlain("created: %d, updated: %d, skipped: %d, errors: %d\n",
+		resp.Created, resp.Updated, resp.Skipped, resp.Errors)
+}
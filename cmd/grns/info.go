@@ -2,6 +2,7 @@ package main
 
 import (
 	"sort"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -15,7 +16,7 @@ func newInfoCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 		Short: "Show database and project info",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return withClient(cfg, func(client *api.Client) error {
-				resp, err := client.GetInfo(cmd.Context())
+				resp, err := client.GetProjectInfo(cmd.Context())
 				if err != nil {
 					return err
 				}
@@ -24,6 +25,10 @@ func newInfoCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 				if dbPath == "" {
 					dbPath = cfg.DBPath
 				}
+				project := resp.Project
+				if project == "" {
+					project = resp.ProjectPrefix
+				}
 
 				output := struct {
 					DBPath        string         `json:"db_path"`
@@ -31,12 +36,14 @@ func newInfoCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 					SchemaVersion int            `json:"schema_version"`
 					TaskCounts    map[string]int `json:"task_counts"`
 					TotalTasks    int            `json:"total_tasks"`
+					LastBackupAt  *time.Time     `json:"last_backup_at,omitempty"`
 				}{
 					DBPath:        dbPath,
-					ProjectPrefix: resp.ProjectPrefix,
+					ProjectPrefix: project,
 					SchemaVersion: resp.SchemaVersion,
 					TaskCounts:    resp.TaskCounts,
 					TotalTasks:    resp.TotalTasks,
+					LastBackupAt:  resp.LastBackupAt,
 				}
 
 				if *jsonOutput {
@@ -52,6 +59,11 @@ func newInfoCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 				if err := writePlain("schema_version: %d\n", output.SchemaVersion); err != nil {
 					return err
 				}
+				if output.LastBackupAt != nil {
+					if err := writePlain("last_backup_at: %s\n", output.LastBackupAt.Format(time.RFC3339)); err != nil {
+						return err
+					}
+				}
 				if err := writePlain("total_tasks: %d\n", output.TotalTasks); err != nil {
 					return err
 				}
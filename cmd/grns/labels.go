@@ -46,6 +46,8 @@ func newLabelCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 		newLabelRemoveCmd(cfg, jsonOutput),
 		newLabelListCmd(cfg, jsonOutput),
 		newLabelListAllCmd(cfg, jsonOutput),
+		newLabelRelatedCmd(cfg, jsonOutput),
+		newLabelRenameCmd(cfg, jsonOutput),
 	)
 	return labelCmd
 }
@@ -116,3 +118,58 @@ func newLabelListAllCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 		},
 	}
 }
+
+func newLabelRelatedCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "related <label>",
+		Short: "List labels that often appear with a label",
+		Args:  requireExactlyArgs(1, "label is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				related, err := client.RelatedLabels(cmd.Context(), args[0], limit)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(related)
+				}
+				for _, item := range related {
+					if err := writePlain("%s\t%d\n", item.Label, item.Count); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 0, "max related labels (default: server reports.default_limit)")
+	return cmd
+}
+
+func newLabelRenameCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var allProjects bool
+	cmd := &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a label on every task (admin)",
+		Args:  requireExactlyArgs(2, "old and new labels are required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := api.LabelRenameRequest{From: args[0], To: args[1], Project: cfg.ProjectPrefix}
+			if allProjects {
+				req.Project = ""
+			}
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.AdminRenameLabel(cmd.Context(), req)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				return writePlain("renamed %s to %s on %d tasks\n", resp.From, resp.To, resp.Count)
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&allProjects, "all-projects", false, "rename in every project")
+	return cmd
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -17,8 +19,12 @@ type listCmdOptions struct {
 	taskType         string
 	label            string
 	labelAny         string
+	depType          string
 	spec             string
 	parentID         string
+	milestoneID      string
+	customEq         []string
+	customExists     string
 	assignee         string
 	noAssignee       bool
 	ids              string
@@ -33,7 +39,12 @@ type listCmdOptions struct {
 	closedBefore     string
 	emptyDescription bool
 	noLabels         bool
+	gitRefs          string
 	search           string
+	query            string
+	view             string
+	sort             string
+	order            string
 	limit            int
 	offset           int
 }
@@ -53,6 +64,11 @@ func newListCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 }
 
 func runList(cmd *cobra.Command, cfg *config.Config, opts *listCmdOptions, jsonOutput *bool) error {
+	for _, pair := range opts.customEq {
+		if idx := strings.IndexByte(pair, ':'); idx <= 0 {
+			return fmt.Errorf("invalid --custom-eq format %q, expected key:value", pair)
+		}
+	}
 	return withClient(cfg, func(client *api.Client) error {
 		query := buildListQueryValues(opts)
 		resp, err := client.ListTasks(cmd.Context(), query)
@@ -75,8 +91,14 @@ func buildListQueryValues(opts *listCmdOptions) url.Values {
 	setIfNotEmpty(query, "type", opts.taskType)
 	setIfNotEmpty(query, "label", opts.label)
 	setIfNotEmpty(query, "label_any", opts.labelAny)
+	setIfNotEmpty(query, "dep_type", opts.depType)
 	setIfNotEmpty(query, "spec", opts.spec)
 	setIfNotEmpty(query, "parent_id", opts.parentID)
+	setIfNotEmpty(query, "milestone_id", opts.milestoneID)
+	for _, pair := range opts.customEq {
+		query.Add("custom_eq", pair)
+	}
+	setIfNotEmpty(query, "custom_exists", opts.customExists)
 	setIfNotEmpty(query, "assignee", opts.assignee)
 	if opts.noAssignee {
 		query.Set("no_assignee", "true")
@@ -97,7 +119,12 @@ func buildListQueryValues(opts *listCmdOptions) url.Values {
 	if opts.noLabels {
 		query.Set("no_labels", "true")
 	}
+	setIfNotEmpty(query, "git_refs", opts.gitRefs)
 	setIfNotEmpty(query, "search", opts.search)
+	setIfNotEmpty(query, "q", opts.query)
+	setIfNotEmpty(query, "sort", opts.sort)
+	setIfNotEmpty(query, "order", opts.order)
+	setIfNotEmpty(query, "view", opts.view)
 	if opts.limit > 0 {
 		query.Set("limit", intToString(opts.limit))
 	}
@@ -115,8 +142,12 @@ func bindListFlags(cmd *cobra.Command, opts *listCmdOptions) {
 	cmd.Flags().StringVar(&opts.taskType, "type", "", "type filter")
 	cmd.Flags().StringVar(&opts.label, "label", "", "label filter")
 	cmd.Flags().StringVar(&opts.labelAny, "label-any", "", "label any filter")
+	cmd.Flags().StringVar(&opts.depType, "dep-type", "", "tasks with a dependency of this type (comma-separated)")
 	cmd.Flags().StringVar(&opts.spec, "spec", "", "spec regex")
 	cmd.Flags().StringVar(&opts.parentID, "parent", "", "parent id")
+	cmd.Flags().StringVar(&opts.milestoneID, "milestone", "", "milestone id")
+	cmd.Flags().StringArrayVar(&opts.customEq, "custom-eq", nil, "custom field key:value match (repeatable)")
+	cmd.Flags().StringVar(&opts.customExists, "custom-exists", "", "tasks with these custom fields set (comma-separated)")
 	cmd.Flags().StringVar(&opts.assignee, "assignee", "", "assignee filter")
 	cmd.Flags().BoolVar(&opts.noAssignee, "no-assignee", false, "unassigned tasks only")
 	cmd.Flags().StringVar(&opts.ids, "id", "", "filter by ids (comma-separated)")
@@ -131,7 +162,12 @@ func bindListFlags(cmd *cobra.Command, opts *listCmdOptions) {
 	cmd.Flags().StringVar(&opts.closedBefore, "closed-before", "", "closed before (RFC3339 or YYYY-MM-DD)")
 	cmd.Flags().BoolVar(&opts.emptyDescription, "empty-description", false, "tasks with no description")
 	cmd.Flags().BoolVar(&opts.noLabels, "no-labels", false, "tasks with no labels")
+	cmd.Flags().StringVar(&opts.gitRefs, "git-refs", "", "tasks with git refs: any|none|<relation>[,<relation>...]")
 	cmd.Flags().StringVar(&opts.search, "search", "", "full-text search query")
+	cmd.Flags().StringVarP(&opts.query, "query", "q", "", `search expression, e.g. 'status:open label:backend priority<=1 "auth bug"'`)
+	cmd.Flags().StringVar(&opts.view, "view", "", "saved filter name; explicit flags override its values")
+	cmd.Flags().StringVar(&opts.sort, "sort", "", "sort fields: priority|created_at|updated_at|title (comma-separated, -field for descending)")
+	cmd.Flags().StringVar(&opts.order, "order", "", "sort direction: asc|desc")
 	cmd.Flags().IntVar(&opts.limit, "limit", 0, "limit results")
 	cmd.Flags().IntVar(&opts.offset, "offset", 0, "offset results")
 }
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newMergeCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge <duplicate-id> <canonical-id>",
+		Short: "Merge a duplicate task into a canonical task",
+		Args:  requireExactlyArgs(2, "duplicate and canonical ids are required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.MergeTask(cmd.Context(), args[0], api.TaskMergeRequest{Into: args[1]})
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				return writeTaskDetail(resp)
+			})
+		},
+	}
+
+	return cmd
+}
@@ -21,7 +21,7 @@ func newMigrateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 		Use:   "migrate",
 		Short: "Run or inspect database schema migrations",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			db, err := openRawDB(cfg.DBPath)
+			db, err := openRawDB(cfg)
 			if err != nil {
 				return err
 			}
@@ -51,7 +51,7 @@ func newMigrateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 			}
 
 			// Run migrations (same as what happens on server start).
-			st, err := store.Open(cfg.DBPath)
+			st, err := openStore(cfg)
 			if err != nil {
 				return fmt.Errorf("migrate: %w", err)
 			}
@@ -59,7 +59,7 @@ func newMigrateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 
 			if *jsonOutput {
 				// Re-open raw DB to get status after migration.
-				db2, err := openRawDB(cfg.DBPath)
+				db2, err := openRawDB(cfg)
 				if err != nil {
 					return err
 				}
@@ -83,10 +83,18 @@ func newMigrateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	return cmd
 }
 
-func openRawDB(path string) (*sql.DB, error) {
+// openRawDB opens the configured database without migrating it.
+func openRawDB(cfg *config.Config) (*sql.DB, error) {
+	if cfg.DB.URL != "" {
+		return store.OpenPostgresDB(cfg.DB.URL)
+	}
+	path := cfg.DBPath
 	if path == "" {
 		return nil, fmt.Errorf("db path is required")
 	}
+	if store.IsMemoryPath(path) {
+		return nil, fmt.Errorf("in-memory databases are migrated when the server starts; nothing to do here")
+	}
 	u := url.URL{Scheme: "file", Path: path}
 	return sql.Open("sqlite", u.String())
 }
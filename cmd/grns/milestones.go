@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+	"grns/internal/models"
+)
+
+func newMilestoneCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	cmd := &cobra.Command{Use: "milestone", Short: "Manage milestones"}
+	cmd.AddCommand(
+		newMilestoneCreateCmd(cfg, jsonOutput),
+		newMilestoneListCmd(cfg, jsonOutput),
+		newMilestoneShowCmd(cfg, jsonOutput),
+		newMilestoneUpdateCmd(cfg, jsonOutput),
+		newMilestoneRemoveCmd(cfg, jsonOutput),
+	)
+	return cmd
+}
+
+func newMilestoneCreateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var dueDate string
+	cmd := &cobra.Command{
+		Use:   "create <title>",
+		Short: "Create a milestone",
+		Args:  requireExactlyArgs(1, "title is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := api.MilestoneCreateRequest{Title: args[0], DueDate: dueDate}
+			return withClient(cfg, func(client *api.Client) error {
+				milestone, err := client.CreateMilestone(cmd.Context(), req)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(milestone)
+				}
+				return writeMilestone(api.MilestoneResponse{Milestone: milestone})
+			})
+		},
+	}
+	cmd.Flags().StringVar(&dueDate, "due", "", "due date (YYYY-MM-DD)")
+	return cmd
+}
+
+func newMilestoneListCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var state string
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List milestones",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				milestones, err := client.ListMilestones(cmd.Context(), state)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(milestones)
+				}
+				for _, milestone := range milestones {
+					due := milestone.DueDate
+					if due == "" {
+						due = "-"
+					}
+					if err := writePlain("%s [%s] %s %s\n", milestone.ID, milestone.State, due, milestone.Title); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&state, "state", "", "filter by state (open|closed)")
+	return cmd
+}
+
+func newMilestoneShowCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <milestone-id>",
+		Short: "Show a milestone with burndown counts",
+		Args:  requireExactlyArgs(1, "milestone id is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				milestone, err := client.GetMilestone(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(milestone)
+				}
+				return writeMilestone(milestone)
+			})
+		},
+	}
+}
+
+func newMilestoneUpdateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var title, dueDate, state string
+	cmd := &cobra.Command{
+		Use:   "update <milestone-id>",
+		Short: "Update a milestone",
+		Args:  requireExactlyArgs(1, "milestone id is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := api.MilestoneUpdateRequest{}
+			if cmd.Flags().Changed("title") {
+				req.Title = &title
+			}
+			if cmd.Flags().Changed("due") {
+				req.DueDate = &dueDate
+			}
+			if cmd.Flags().Changed("state") {
+				req.State = &state
+			}
+			if req.Title == nil && req.DueDate == nil && req.State == nil {
+				return fmt.Errorf("no fields to update")
+			}
+			return withClient(cfg, func(client *api.Client) error {
+				milestone, err := client.UpdateMilestone(cmd.Context(), args[0], req)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(milestone)
+				}
+				return writeMilestone(milestone)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&title, "title", "", "new title")
+	cmd.Flags().StringVar(&dueDate, "due", "", "due date (YYYY-MM-DD, empty clears)")
+	cmd.Flags().StringVar(&state, "state", "", "state (open|closed)")
+	return cmd
+}
+
+func newMilestoneRemoveCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <milestone-id>",
+		Short: "Delete a milestone and detach its tasks",
+		Args:  requireExactlyArgs(1, "milestone id is required"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withClient(cfg, func(client *api.Client) error {
+				resp, err := client.DeleteMilestone(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				return writePlain("%s\n", args[0])
+			})
+		},
+	}
+}
+
+func writeMilestone(milestone api.MilestoneResponse) error {
+	lines := []string{
+		fmt.Sprintf("id: %s", milestone.ID),
+		fmt.Sprintf("title: %s", milestone.Title),
+		fmt.Sprintf("state: %s", milestone.State),
+	}
+	if milestone.DueDate != "" {
+		lines = append(lines, fmt.Sprintf("due_date: %s", milestone.DueDate))
+	}
+	if progress := milestone.Progress; progress != nil {
+		lines = append(lines, fmt.Sprintf("progress: %d/%d closed, %d remaining", progress.Closed, progress.Total, progress.Remaining))
+		for _, status := range models.TaskStatusStrings() {
+			if count := progress.ByStatus[status]; count > 0 {
+				lines = append(lines, fmt.Sprintf("  %s: %d", status, count))
+			}
+		}
+	}
+	return writePlain("%s\n", strings.Join(lines, "\n"))
+}
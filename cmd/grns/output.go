@@ -9,10 +9,65 @@ import (
 
 	"grns/internal/api"
 	"grns/internal/format"
+	"grns/internal/models"
 )
 
 var outputFormatter format.Formatter = format.JSONFormatter{}
 
+// Output formats accepted by --format.
+const (
+	outputFormatText     = "text"
+	outputFormatJSON     = "json"
+	outputFormatTemplate = "template"
+)
+
+// outputOptions holds the global output flags.
+type outputOptions struct {
+	format   string
+	template string
+	fields   []string
+}
+
+// configureOutput selects the formatter behind writeJSON. Structured formats
+// switch every command onto its --json code path.
+func configureOutput(opts outputOptions, jsonOutput *bool) error {
+	name := strings.ToLower(strings.TrimSpace(opts.format))
+	if name == "" && opts.template != "" {
+		name = outputFormatTemplate
+	}
+	if name == "" && *jsonOutput {
+		name = outputFormatJSON
+	}
+	if len(opts.fields) > 0 && name == outputFormatTemplate {
+		return fmt.Errorf("--fields cannot be combined with --template")
+	}
+
+	switch name {
+	case "", outputFormatText:
+		if len(opts.fields) == 0 {
+			return nil
+		}
+		outputFormatter = format.FieldsFormatter{Fields: opts.fields}
+	case outputFormatJSON:
+		if len(opts.fields) > 0 {
+			outputFormatter = format.FieldsFormatter{Fields: opts.fields}
+		}
+	case outputFormatTemplate:
+		if opts.template == "" {
+			return fmt.Errorf("--format template requires --template")
+		}
+		formatter, err := format.ParseTemplate(opts.template)
+		if err != nil {
+			return fmt.Errorf("invalid --template: %w", err)
+		}
+		outputFormatter = formatter
+	default:
+		return fmt.Errorf("invalid --format %q (allowed: text, json, template)", opts.format)
+	}
+	*jsonOutput = true
+	return nil
+}
+
 func writeJSON(payload any) error {
 	return outputFormatter.Write(os.Stdout, payload)
 }
@@ -27,6 +82,11 @@ func writeTaskList(tasks []api.TaskResponse) error {
 		if err := writePlain("%s\n", formatTaskLine(task)); err != nil {
 			return err
 		}
+		if task.Snippet != "" {
+			if err := writePlain("    %s\n", task.Snippet); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -51,9 +111,18 @@ func writeTaskDetail(task api.TaskResponse) error {
 	if task.Assignee != "" {
 		lines = append(lines, fmt.Sprintf("assignee: %s", task.Assignee))
 	}
+	if task.BlockedReason != "" {
+		lines = append(lines, fmt.Sprintf("blocked_reason: %s", task.BlockedReason))
+	}
+	if task.BlockedOn != "" {
+		lines = append(lines, fmt.Sprintf("blocked_on: %s", task.BlockedOn))
+	}
 	if task.SourceRepo != "" {
 		lines = append(lines, fmt.Sprintf("source_repo: %s", task.SourceRepo))
 	}
+	if task.MilestoneID != "" {
+		lines = append(lines, fmt.Sprintf("milestone_id: %s", task.MilestoneID))
+	}
 	if task.Description != "" {
 		lines = append(lines, fmt.Sprintf("description: %s", task.Description))
 	}
@@ -69,6 +138,12 @@ func writeTaskDetail(task api.TaskResponse) error {
 	if task.ClosedAt != nil {
 		lines = append(lines, fmt.Sprintf("closed_at: %s", formatTime(*task.ClosedAt)))
 	}
+	if task.DeletedAt != nil {
+		lines = append(lines, fmt.Sprintf("deleted_at: %s", formatTime(*task.DeletedAt)))
+	}
+	if task.MergedInto != "" {
+		lines = append(lines, fmt.Sprintf("merged_into: %s", task.MergedInto))
+	}
 
 	if len(task.Labels) > 0 {
 		lines = append(lines, fmt.Sprintf("labels: %s", strings.Join(task.Labels, ", ")))
@@ -90,14 +165,64 @@ func writeTaskDetail(task api.TaskResponse) error {
 			lines = append(lines, fmt.Sprintf("  - %s: %s", dep.Type, dep.ParentID))
 		}
 	}
+	if summary := task.ChildrenSummary; summary != nil {
+		lines = append(lines, fmt.Sprintf("children: %d/%d closed (%d%%)", summary.Closed, summary.Total, summary.PercentComplete))
+		for _, status := range models.TaskStatusStrings() {
+			if count := summary.ByStatus[status]; count > 0 {
+				lines = append(lines, fmt.Sprintf("  %s: %d", status, count))
+			}
+		}
+	}
+	if len(task.Watchers) > 0 {
+		lines = append(lines, fmt.Sprintf("watchers: %s", strings.Join(task.Watchers, ", ")))
+	}
+	if summary := task.GitSummary; summary != nil {
+		lines = append(lines, fmt.Sprintf("git: %d refs, %d commits", summary.Total, len(summary.Commits)))
+		for _, branch := range summary.Branches {
+			line := fmt.Sprintf("  - branch %s (%s) [%s] %s", branch.Branch, branch.Repo, branch.Relation, branch.Status)
+			if branch.MergedBy != "" {
+				line += " by " + shortCommit(branch.MergedBy)
+			}
+			lines = append(lines, line)
+		}
+		for _, commit := range summary.Commits {
+			lines = append(lines, "  - commit "+shortCommit(commit))
+		}
+	}
 
 	return writePlain("%s\n", strings.Join(lines, "\n"))
 }
 
 func formatTaskLine(task api.TaskResponse) string {
-	return fmt.Sprintf("○ %s [P%d] [%s] - %s", task.ID, task.Priority, task.Type, task.Title)
+	line := fmt.Sprintf("○ %s [P%d] [%s] - %s", task.ID, task.Priority, task.Type, task.Title)
+	if blocked := formatBlocked(task.Task); blocked != "" {
+		line += " (" + blocked + ")"
+	}
+	return line
+}
+
+// formatBlocked summarizes why a task is blocked, or returns "" when it has no explanation.
+func formatBlocked(task models.Task) string {
+	switch {
+	case task.BlockedReason != "" && task.BlockedOn != "":
+		return fmt.Sprintf("blocked: %s; on %s", task.BlockedReason, task.BlockedOn)
+	case task.BlockedReason != "":
+		return "blocked: " + task.BlockedReason
+	case task.BlockedOn != "":
+		return "blocked on " + task.BlockedOn
+	default:
+		return ""
+	}
 }
 
 func formatTime(t time.Time) string {
 	return t.UTC().Format(time.RFC3339)
 }
+
+// shortCommit abbreviates a commit sha for display.
+func shortCommit(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"grns/internal/api"
+	"grns/internal/config"
+	"grns/internal/format"
+	"grns/internal/models"
+)
+
+func TestConfigureOutputFormats(t *testing.T) {
+	t.Cleanup(func() { outputFormatter = format.JSONFormatter{} })
+	tasks := []api.TaskResponse{
+		{Task: models.Task{ID: "gr-ab12", Title: "First", Status: "open"}, Labels: []string{"a", "b"}},
+		{Task: models.Task{ID: "gr-cd34", Title: "Second", Status: "closed"}},
+	}
+
+	render := func(opts outputOptions) string {
+		t.Helper()
+		outputFormatter = format.JSONFormatter{}
+		jsonOutput := false
+		if err := configureOutput(opts, &jsonOutput); err != nil {
+			t.Fatalf("configure %+v: %v", opts, err)
+		}
+		if !jsonOutput {
+			t.Fatalf("expected %+v to select structured output", opts)
+		}
+		var buf bytes.Buffer
+		if err := outputFormatter.Write(&buf, tasks); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		return buf.String()
+	}
+
+	if got := render(outputOptions{template: `{{.ID}} {{.Title}} {{join .Labels ","}}`}); got != "gr-ab12 First a,b\ngr-cd34 Second \n" {
+		t.Fatalf("unexpected template output: %q", got)
+	}
+	if got := render(outputOptions{fields: []string{"title", "id", "missing"}}); got != `[{"title":"First","id":"gr-ab12","missing":null},{"title":"Second","id":"gr-cd34","missing":null}]`+"\n" {
+		t.Fatalf("unexpected fields output: %q", got)
+	}
+
+	jsonOutput := false
+	if err := configureOutput(outputOptions{}, &jsonOutput); err != nil || jsonOutput {
+		t.Fatalf("expected default text output, got json=%v err=%v", jsonOutput, err)
+	}
+	for _, opts := range []outputOptions{
+		{format: "yaml"},
+		{format: "template"},
+		{template: "{{.ID"},
+		{template: "{{.ID}}", fields: []string{"id"}},
+	} {
+		jsonOutput := false
+		if err := configureOutput(opts, &jsonOutput); err == nil {
+			t.Fatalf("expected error for %+v", opts)
+		}
+	}
+}
+
+func TestGlobalFormatOnGraphAndReportCommands(t *testing.T) {
+	t.Cleanup(func() { outputFormatter = format.JSONFormatter{} })
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") != "" && r.URL.Query().Get("format") != "json" {
+			t.Errorf("expected JSON request, got %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/projects/gr/tasks/gr-ab12/deps/tree":
+			_, _ = w.Write([]byte(`{"root_id":"gr-ab12","nodes":[{"id":"gr-cd34","depth":1}]}`))
+		case "/v1/projects/gr/tasks/deps/graph":
+			_, _ = w.Write([]byte(`{"nodes":[{"id":"gr-ab12","title":"First"},{"id":"gr-cd34","title":"Second"}]}`))
+		case "/v1/projects/gr/reports/summary":
+			_, _ = w.Write([]byte(`{"project":"gr","group_by":"status","total":3,"groups":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+		}
+	}))
+	defer ts.Close()
+
+	run := func(args ...string) string {
+		t.Helper()
+		outputFormatter = format.JSONFormatter{}
+		cfg := config.Default()
+		cfg.APIURL = ts.URL
+		cfg.ProjectPrefix = "gr"
+
+		stdout := os.Stdout
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("pipe: %v", err)
+		}
+		os.Stdout = w
+		root := newRootCmd(&cfg)
+		root.SetArgs(args)
+		runErr := root.ExecuteContext(context.Background())
+		os.Stdout = stdout
+		_ = w.Close()
+		out, _ := io.ReadAll(r)
+		if runErr != nil {
+			t.Fatalf("grns %s: %v", strings.Join(args, " "), runErr)
+		}
+		return string(out)
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		template string
+		want     string
+	}{
+		{args: []string{"dep", "tree", "gr-ab12"}, template: "{{.RootID}} {{len .Nodes}}", want: "gr-ab12 1"},
+		{args: []string{"dep", "graph"}, template: "{{len .Nodes}}", want: "2"},
+		{args: []string{"report"}, template: "{{.GroupBy}} {{.Total}}", want: "status 3"},
+	} {
+		name := strings.Join(tc.args, " ")
+		if got := run(append(tc.args, "--format", "json")...); !strings.HasPrefix(strings.TrimSpace(got), "{") {
+			t.Fatalf("%s --format json: expected JSON, got %q", name, got)
+		}
+		if got := run(append(tc.args, "--format", "template", "--template", tc.template)...); strings.TrimSpace(got) != tc.want {
+			t.Fatalf("%s --format template: expected %q, got %q", name, tc.want, got)
+		}
+	}
+}
@@ -95,6 +95,9 @@ func frontMatterToRequest(frontMatter map[string]any) (api.TaskCreateRequest, er
 	if value, ok := frontMatter["source_repo"].(string); ok {
 		req.SourceRepo = &value
 	}
+	if value, ok := frontMatter["milestone_id"].(string); ok {
+		req.MilestoneID = &value
+	}
 	if value, ok := frontMatter["labels"]; ok {
 		req.Labels = toStringSlice(value)
 	}
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"net/url"
-
 	"github.com/spf13/cobra"
 
 	"grns/internal/api"
@@ -10,18 +8,17 @@ import (
 )
 
 func newReadyCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
-	var limit int
+	var (
+		limit int
+		order string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "ready",
 		Short: "List ready tasks",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return withClient(cfg, func(client *api.Client) error {
-				query := url.Values{}
-				if limit > 0 {
-					query.Set("limit", intToString(limit))
-				}
-				resp, err := client.Ready(cmd.Context(), query)
+				resp, err := client.ReadyWithOptions(cmd.Context(), api.ReadyOptions{Order: order, Limit: limit})
 				if err != nil {
 					return err
 				}
@@ -34,5 +31,6 @@ func newReadyCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 0, "limit results")
+	cmd.Flags().StringVar(&order, "order", "", "order results (updated_at|priority|score)")
 	return cmd
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newReportCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var format, groupBy, status, label, taskType, milestoneID, view string
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Render a grouped backlog report as Markdown or JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			setIfNotEmpty(query, "group_by", groupBy)
+			setIfNotEmpty(query, "status", status)
+			setIfNotEmpty(query, "label", label)
+			setIfNotEmpty(query, "type", taskType)
+			setIfNotEmpty(query, "milestone_id", milestoneID)
+			setIfNotEmpty(query, "view", view)
+			if *jsonOutput {
+				format = "json"
+			}
+			return withClient(cfg, func(client *api.Client) error {
+				switch format {
+				case "markdown":
+					return client.ReportSummaryMarkdown(cmd.Context(), query, os.Stdout)
+				case "json":
+					resp, err := client.ReportSummary(cmd.Context(), query)
+					if err != nil {
+						return err
+					}
+					return writeJSON(resp)
+				default:
+					return errors.New("--report-format must be markdown or json")
+				}
+			})
+		},
+	}
+	cmd.Flags().StringVar(&format, "report-format", "markdown", "report rendering: markdown|json (--format json|template implies json)")
+	cmd.Flags().StringVar(&groupBy, "group-by", "status", "group tasks by status|epic|assignee")
+	cmd.Flags().StringVar(&status, "status", "", "filter by status (comma-separated)")
+	cmd.Flags().StringVar(&label, "label", "", "filter by labels (comma-separated, AND)")
+	cmd.Flags().StringVar(&taskType, "type", "", "filter by type (comma-separated)")
+	cmd.Flags().StringVar(&milestoneID, "milestone", "", "filter by milestone id")
+	cmd.Flags().StringVar(&view, "view", "", "apply a saved filter by name")
+	return cmd
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newRestoreCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <id> [<id>...]",
+		Short: "Restore soft-deleted tasks",
+		Args:  requireAtLeastOneID,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIDsMutation(cfg, *jsonOutput, cmd.Context(), args,
+				func(ctx context.Context, client *api.Client, ids []string) (any, error) {
+					return client.RestoreTasks(ctx, api.TaskRestoreRequest{IDs: ids})
+				},
+			)
+		},
+	}
+
+	return cmd
+}
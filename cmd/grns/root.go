@@ -12,6 +12,7 @@ import (
 func newRootCmd(cfg *config.Config) *cobra.Command {
 	var jsonOutput bool
 	var logLevel string
+	var output outputOptions
 
 	cmd := &cobra.Command{
 		Use:           "grns",
@@ -30,12 +31,15 @@ func newRootCmd(cfg *config.Config) *cobra.Command {
 			if warning != "" {
 				fmt.Fprintln(os.Stderr, warning)
 			}
-			return nil
+			return configureOutput(output, &jsonOutput)
 		},
 	}
 
 	cmd.Version = version
 	cmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "output JSON")
+	cmd.PersistentFlags().StringVar(&output.format, "format", "", "output format: text|json|template")
+	cmd.PersistentFlags().StringVar(&output.template, "template", "", "Go template applied to JSON output, once per item for lists (e.g. '{{.ID}} {{.Title}}')")
+	cmd.PersistentFlags().StringSliceVar(&output.fields, "fields", nil, "output JSON with only these fields (comma-separated, e.g. id,title,status)")
 	cmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "log level: debug|info|warn|error (overrides GRNS_LOG_LEVEL and log_level config)")
 
 	cmd.AddCommand(
@@ -43,17 +47,28 @@ func newRootCmd(cfg *config.Config) *cobra.Command {
 		newCreateCmd(cfg, &jsonOutput),
 		newShowCmd(cfg, &jsonOutput),
 		newUpdateCmd(cfg, &jsonOutput),
+		newEditCmd(cfg, &jsonOutput),
 		newListCmd(cfg, &jsonOutput),
 		newReadyCmd(cfg, &jsonOutput),
+		newClaimCmd(cfg, &jsonOutput),
 		newStaleCmd(cfg, &jsonOutput),
+		newReportCmd(cfg, &jsonOutput),
 		newCloseCmd(cfg, &jsonOutput),
 		newReopenCmd(cfg, &jsonOutput),
+		newDeleteCmd(cfg, &jsonOutput),
+		newRestoreCmd(cfg, &jsonOutput),
+		newMergeCmd(cfg, &jsonOutput),
+		newTouchCmd(cfg, &jsonOutput),
+		newUndoCmd(cfg, &jsonOutput),
 		newDepCmd(cfg, &jsonOutput),
 		newLabelCmd(cfg, &jsonOutput),
 		newAttachCmd(cfg, &jsonOutput),
 		newGitCmd(cfg, &jsonOutput),
+		newMilestoneCmd(cfg, &jsonOutput),
+		newCustomFieldCmd(cfg, &jsonOutput),
 		newMigrateCmd(cfg, &jsonOutput),
 		newInfoCmd(cfg, &jsonOutput),
+		newStatsCmd(cfg, &jsonOutput),
 		newAdminCmd(cfg, &jsonOutput),
 		newExportCmd(cfg, &jsonOutput),
 		newImportCmd(cfg, &jsonOutput),
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -15,10 +20,25 @@ import (
 	"grns/internal/store"
 )
 
+// srvOptions holds the srv command flags.
+type srvOptions struct {
+	listen   string
+	pidFile  string
+	embedded bool
+	stop     bool
+}
+
 func newSrvCmd(cfg *config.Config) *cobra.Command {
-	return &cobra.Command{
+	opts := &srvOptions{}
+	cmd := &cobra.Command{
 		Use:   "srv",
 		Short: "Run the grns API server",
+		Long: "Run the API server in the foreground until interrupted. SIGINT and SIGTERM stop it " +
+			"gracefully.\n\n" +
+			"--embedded instead starts a managed server in the background on a unix socket next " +
+			"to the database and returns once it is healthy; --stop stops it. With " +
+			"server.embedded = true, other commands use that server and start it when needed.",
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if cfg == nil {
 				return fmt.Errorf("config not initialized")
@@ -26,60 +46,317 @@ func newSrvCmd(cfg *config.Config) *cobra.Command {
 			if cfg.DBPath == "" {
 				return fmt.Errorf("db path is required")
 			}
-
-			logger := slog.Default().With("component", "server")
-			logger.Debug("resolved config",
-				"api_url", cfg.APIURL,
-				"api_url_source", cfg.Source("api_url"),
-				"api_token_env_set", strings.TrimSpace(os.Getenv("GRNS_API_TOKEN")) != "",
-				"admin_token_env_set", strings.TrimSpace(os.Getenv("GRNS_ADMIN_TOKEN")) != "",
-				"db_path", cfg.DBPath,
-				"db_path_source", cfg.Source("db_path"),
-				"project_prefix", cfg.ProjectPrefix,
-				"project_prefix_source", cfg.Source("project_prefix"),
-				"log_level", cfg.LogLevel,
-				"log_level_source", cfg.Source("log_level"),
-				"attachments.max_upload_bytes", cfg.Attachments.MaxUploadBytes,
-				"attachments.max_upload_bytes_source", cfg.Source("attachments.max_upload_bytes"),
-				"attachments.multipart_max_memory", cfg.Attachments.MultipartMaxMemory,
-				"attachments.multipart_max_memory_source", cfg.Source("attachments.multipart_max_memory"),
-				"attachments.allowed_media_types", strings.Join(cfg.Attachments.AllowedMediaTypes, ","),
-				"attachments.allowed_media_types_source", cfg.Source("attachments.allowed_media_types"),
-				"attachments.reject_media_type_mismatch", cfg.Attachments.RejectMediaTypeMismatch,
-				"attachments.reject_media_type_mismatch_source", cfg.Source("attachments.reject_media_type_mismatch"),
-				"attachments.gc_batch_size", cfg.Attachments.GCBatchSize,
-				"attachments.gc_batch_size_source", cfg.Source("attachments.gc_batch_size"),
-				"loaded_config_paths", strings.Join(cfg.LoadedPaths(), ","),
-			)
-
-			addr, err := server.ListenAddr(cfg.APIURL)
-			if err != nil {
-				return err
+			switch {
+			case opts.embedded && opts.stop:
+				return fmt.Errorf("--embedded and --stop cannot be combined")
+			case opts.embedded:
+				apiURL, err := ensureEmbeddedServer(cmd.Context(), cfg)
+				if err != nil {
+					return err
+				}
+				return writePlain("%s\n", apiURL)
+			case opts.stop:
+				return stopEmbeddedServer(cmd.Context(), cfg)
 			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return runServer(ctx, cfg, opts)
+		},
+	}
 
-			logger.Info("opening database", "path", cfg.DBPath)
-			st, err := store.Open(cfg.DBPath)
-			if err != nil {
-				return err
-			}
-			defer st.Close()
+	cmd.Flags().StringVar(&opts.listen, "listen", "", "address to listen on, overriding server.listen and api_url")
+	cmd.Flags().StringVar(&opts.pidFile, "pid-file", "", "write the process id to this file while serving")
+	cmd.Flags().BoolVar(&opts.embedded, "embedded", false, "start the managed background server on a unix socket next to the database")
+	cmd.Flags().BoolVar(&opts.stop, "stop", false, "stop the managed background server")
+	return cmd
+}
 
-			blobRoot := filepath.Join(filepath.Dir(cfg.DBPath), ".grns", "blobs")
-			bs, err := blobstore.NewLocalCAS(blobRoot)
-			if err != nil {
-				return err
-			}
+// runServer opens the database and serves the API until ctx is cancelled.
+func runServer(ctx context.Context, cfg *config.Config, opts *srvOptions) error {
+	logger := slog.Default().With("component", "server")
+	logResolvedConfig(logger, cfg)
 
-			srv := server.New(addr, st, cfg.ProjectPrefix, logger, bs)
-			srv.SetDBPath(cfg.DBPath)
-			srv.ConfigureAttachmentOptions(server.AttachmentOptions{
-				MaxUploadBytes:          cfg.Attachments.MaxUploadBytes,
-				MultipartMaxMemory:      cfg.Attachments.MultipartMaxMemory,
-				AllowedMediaTypes:       cfg.Attachments.AllowedMediaTypes,
-				RejectMediaTypeMismatch: cfg.Attachments.RejectMediaTypeMismatch,
-				GCBatchSize:             cfg.Attachments.GCBatchSize,
-			})
-			return srv.ListenAndServe()
-		},
+	listen := cfg.APIURL
+	if cfg.Server.Listen != "" {
+		listen = cfg.Server.Listen
+	}
+	if opts.listen != "" {
+		listen = opts.listen
+	}
+	addr, err := server.ListenAddr(listen)
+	if err != nil {
+		return err
+	}
+
+	dbLocation := cfg.DBPath
+	if cfg.DB.URL != "" {
+		dbLocation = redactDBURL(cfg.DB.URL)
+		logger.Info("opening database", "url", dbLocation)
+	} else {
+		logger.Info("opening database", "path", cfg.DBPath)
+	}
+	st, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	blobRoot, cleanupBlobs, err := blobRootFor(cfg.DBPath, logger)
+	if err != nil {
+		return err
+	}
+	defer cleanupBlobs()
+	bs, err := blobstore.NewLocalCAS(blobRoot)
+	if err != nil {
+		return err
+	}
+
+	srv := server.New(addr, st, cfg.ProjectPrefix, logger, bs)
+	srv.SetDBPath(dbLocation)
+	srv.ConfigureGRPC(cfg.Server.GRPCListen)
+	srv.ConfigureAttachmentOptions(attachmentOptions(cfg))
+	srv.ConfigureWorkflowOptions(server.WorkflowOptions{
+		WIPLimits:               cfg.WIPLimits,
+		WIPLimitsPerAssignee:    cfg.WIPLimitsPerAssignee,
+		ParentImpliesBlocks:     cfg.ParentImpliesBlocks,
+		RequireAssigneeStatuses: cfg.RequireAssigneeStatuses,
+		AutoCloseParents:        cfg.Workflow.AutoCloseParents,
+		AssigneeWIPLimit:        cfg.Workflow.WIPLimit,
+		RequireBlockedReason:    cfg.Workflow.RequireBlockedReason,
+		RequireMergedPR:         cfg.Workflow.RequireMergedPR,
+	})
+	srv.ConfigureReportOptions(server.ReportOptions{
+		DefaultLimit: cfg.Reports.DefaultLimit,
+		MaxLimit:     cfg.Reports.MaxLimit,
+	})
+	reportTemplate, err := reportTemplateOptions(cfg)
+	if err != nil {
+		return err
+	}
+	if err := srv.ConfigureReportTemplate(reportTemplate); err != nil {
+		return err
+	}
+	if err := srv.ConfigureResponseOptions(server.ResponseOptions{
+		ListIncludes: cfg.Responses.DefaultIncludes.List,
+		GetIncludes:  cfg.Responses.DefaultIncludes.Get,
+	}); err != nil {
+		return err
+	}
+	if err := srv.ConfigureGitResolver(server.GitResolverOptions{GitHubAPIURL: cfg.Git.GitHubAPIURL, GitLabAPIURL: cfg.Git.GitLabAPIURL}); err != nil {
+		return err
+	}
+	if err := srv.ConfigureImportOptions(server.ImportOptions{SourceLabel: cfg.Import.SourceLabel}); err != nil {
+		return err
+	}
+	if err := configureScheduledJobs(srv, cfg); err != nil {
+		return err
+	}
+	srv.ConfigureCreateLimits(server.CreateLimitOptions{
+		MaxLabels: cfg.Create.MaxLabels,
+		MaxDeps:   cfg.Create.MaxDeps,
+	})
+	srv.ConfigureCloseLimits(server.CloseLimitOptions{
+		MaxByFilter: cfg.Close.MaxByFilter,
+	})
+	srv.ConfigureUndoWindow(cfg.Undo.WindowDuration())
+	srv.ConfigureFieldLimits(server.FieldLimitOptions{
+		MaxDescriptionBytes: cfg.Fields.MaxDescriptionBytes,
+		MaxNotesBytes:       cfg.Fields.MaxNotesBytes,
+	})
+	listener, err := srv.Listen()
+	if err != nil {
+		return err
 	}
+	if opts.pidFile != "" {
+		if err := os.WriteFile(opts.pidFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o600); err != nil {
+			listener.Close()
+			return err
+		}
+		defer os.Remove(opts.pidFile)
+	}
+	return srv.Serve(ctx, listener)
+}
+
+// configureScheduledJobs maps backup.* and stale.* config keys onto the
+// server's background jobs.
+func configureScheduledJobs(srv *server.Server, cfg *config.Config) error {
+	srv.ConfigureBackupOptions(server.BackupOptions{
+		Dir:      cfg.Backup.Dir,
+		Interval: cfg.Backup.IntervalDuration(),
+		KeepLast: cfg.Backup.KeepLast,
+	})
+	return srv.ConfigureStaleEscalation(server.StaleEscalationOptions{
+		Interval:   cfg.Stale.IntervalDuration(),
+		Thresholds: cfg.Stale.Thresholds,
+		Actions:    cfg.Stale.Actions,
+		Label:      cfg.Stale.Label,
+		WebhookURL: cfg.Stale.WebhookURL,
+	})
+}
+
+// storeOptions maps db.* config keys onto store options.
+// openStore opens the PostgreSQL database at db.url when it is set, and the
+// SQLite file at db_path otherwise.
+func openStore(cfg *config.Config) (*store.Store, error) {
+	if cfg.DB.URL != "" {
+		return store.OpenPostgres(cfg.DB.URL, storeOptions(cfg))
+	}
+	return store.OpenWithOptions(cfg.DBPath, storeOptions(cfg))
+}
+
+// redactDBURL hides the password in a database URL for logs and metadata.
+func redactDBURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return u.Redacted()
+}
+
+func storeOptions(cfg *config.Config) store.Options {
+	return store.Options{
+		BusyTimeoutMS: cfg.DB.BusyTimeoutMS,
+		JournalMode:   cfg.DB.JournalMode,
+		QueryTimeout:  cfg.DB.QueryTimeoutDuration(),
+	}
+}
+
+// attachmentOptions maps attachments.* config keys onto server options.
+func attachmentOptions(cfg *config.Config) server.AttachmentOptions {
+	return server.AttachmentOptions{
+		MaxUploadBytes:          cfg.Attachments.MaxUploadBytes,
+		MultipartMaxMemory:      cfg.Attachments.MultipartMaxMemory,
+		AllowedMediaTypes:       cfg.Attachments.AllowedMediaTypes,
+		RejectMediaTypeMismatch: cfg.Attachments.RejectMediaTypeMismatch,
+		GCBatchSize:             cfg.Attachments.GCBatchSize,
+		ScanCommand:             cfg.Attachments.ScanCommand,
+		ScanURL:                 cfg.Attachments.ScanURL,
+		ScanAction:              cfg.Attachments.ScanAction,
+	}
+}
+
+// reportTemplateOptions loads the configured markdown report template, if any.
+func reportTemplateOptions(cfg *config.Config) (server.ReportTemplateOptions, error) {
+	opts := server.ReportTemplateOptions{TaskURL: cfg.Reports.TaskURL}
+	if path := strings.TrimSpace(cfg.Reports.MarkdownTemplate); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return opts, fmt.Errorf("read reports.markdown_template: %w", err)
+		}
+		opts.MarkdownTemplate = string(data)
+	}
+	return opts, nil
+}
+
+// blobRootFor places blobs next to the database file. An in-memory database
+// gets a temporary blob directory that cleanup removes.
+func blobRootFor(dbPath string, logger *slog.Logger) (string, func(), error) {
+	if !store.IsMemoryPath(dbPath) {
+		return filepath.Join(filepath.Dir(dbPath), ".grns", "blobs"), func() {}, nil
+	}
+	tmp, err := os.MkdirTemp("", "grns-blobs-")
+	if err != nil {
+		return "", nil, err
+	}
+	logger.Warn("using in-memory database; all data is discarded on shutdown", "blob_root", tmp)
+	return tmp, func() { _ = os.RemoveAll(tmp) }, nil
+}
+
+// logResolvedConfig logs effective config values with where each was resolved from.
+func logResolvedConfig(logger *slog.Logger, cfg *config.Config) {
+	logger.Debug("resolved config",
+		"api_url", cfg.APIURL,
+		"api_url_source", cfg.Source("api_url"),
+		"server.listen", cfg.Server.Listen,
+		"server.listen_source", cfg.Source("server.listen"),
+		"server.embedded", cfg.Server.Embedded,
+		"server.embedded_source", cfg.Source("server.embedded"),
+		"server.grpc_listen", cfg.Server.GRPCListen,
+		"server.grpc_listen_source", cfg.Source("server.grpc_listen"),
+		"backup.dir", cfg.Backup.Dir,
+		"backup.dir_source", cfg.Source("backup.dir"),
+		"backup.interval", cfg.Backup.Interval,
+		"backup.interval_source", cfg.Source("backup.interval"),
+		"backup.keep_last", cfg.Backup.KeepLast,
+		"backup.keep_last_source", cfg.Source("backup.keep_last"),
+		"stale.interval", cfg.Stale.Interval,
+		"stale.interval_source", cfg.Source("stale.interval"),
+		"stale.thresholds", config.FormatStaleThresholds(cfg.Stale.Thresholds),
+		"stale.thresholds_source", cfg.Source("stale.thresholds"),
+		"stale.actions", strings.Join(cfg.Stale.Actions, ","),
+		"stale.actions_source", cfg.Source("stale.actions"),
+		"stale.label", cfg.Stale.Label,
+		"stale.label_source", cfg.Source("stale.label"),
+		"stale.webhook_url_configured", cfg.Stale.WebhookURL != "",
+		"stale.webhook_url_source", cfg.Source("stale.webhook_url"),
+		"api_token_env_set", strings.TrimSpace(os.Getenv("GRNS_API_TOKEN")) != "",
+		"admin_token_env_set", strings.TrimSpace(os.Getenv("GRNS_ADMIN_TOKEN")) != "",
+		"db_path", cfg.DBPath,
+		"db_path_source", cfg.Source("db_path"),
+		"db.url", redactDBURL(cfg.DB.URL),
+		"db.url_source", cfg.Source("db.url"),
+		"db.busy_timeout_ms", cfg.DB.BusyTimeoutMS,
+		"db.busy_timeout_ms_source", cfg.Source("db.busy_timeout_ms"),
+		"db.journal_mode", cfg.DB.JournalMode,
+		"db.journal_mode_source", cfg.Source("db.journal_mode"),
+		"db.query_timeout", cfg.DB.QueryTimeout,
+		"db.query_timeout_source", cfg.Source("db.query_timeout"),
+		"project_prefix", cfg.ProjectPrefix,
+		"project_prefix_source", cfg.Source("project_prefix"),
+		"log_level", cfg.LogLevel,
+		"log_level_source", cfg.Source("log_level"),
+		"attachments.max_upload_bytes", cfg.Attachments.MaxUploadBytes,
+		"attachments.max_upload_bytes_source", cfg.Source("attachments.max_upload_bytes"),
+		"attachments.multipart_max_memory", cfg.Attachments.MultipartMaxMemory,
+		"attachments.multipart_max_memory_source", cfg.Source("attachments.multipart_max_memory"),
+		"attachments.allowed_media_types", strings.Join(cfg.Attachments.AllowedMediaTypes, ","),
+		"attachments.allowed_media_types_source", cfg.Source("attachments.allowed_media_types"),
+		"attachments.reject_media_type_mismatch", cfg.Attachments.RejectMediaTypeMismatch,
+		"attachments.reject_media_type_mismatch_source", cfg.Source("attachments.reject_media_type_mismatch"),
+		"attachments.gc_batch_size", cfg.Attachments.GCBatchSize,
+		"attachments.gc_batch_size_source", cfg.Source("attachments.gc_batch_size"),
+		"wip_limits", config.FormatWIPLimits(cfg.WIPLimits),
+		"wip_limits_source", cfg.Source("wip_limits"),
+		"wip_limits_per_assignee", cfg.WIPLimitsPerAssignee,
+		"wip_limits_per_assignee_source", cfg.Source("wip_limits_per_assignee"),
+		"parent_implies_blocks", cfg.ParentImpliesBlocks,
+		"parent_implies_blocks_source", cfg.Source("parent_implies_blocks"),
+		"require_assignee_for_statuses", strings.Join(cfg.RequireAssigneeStatuses, ","),
+		"require_assignee_for_statuses_source", cfg.Source("require_assignee_for_statuses"),
+		"workflow.auto_close_parents", cfg.Workflow.AutoCloseParents,
+		"workflow.auto_close_parents_source", cfg.Source("workflow.auto_close_parents"),
+		"workflow.wip_limit", cfg.Workflow.WIPLimit,
+		"workflow.wip_limit_source", cfg.Source("workflow.wip_limit"),
+		"workflow.require_blocked_reason", cfg.Workflow.RequireBlockedReason,
+		"workflow.require_blocked_reason_source", cfg.Source("workflow.require_blocked_reason"),
+		"workflow.require_merged_pr", cfg.Workflow.RequireMergedPR,
+		"workflow.require_merged_pr_source", cfg.Source("workflow.require_merged_pr"),
+		"fields.max_description_bytes", cfg.Fields.MaxDescriptionBytes,
+		"fields.max_description_bytes_source", cfg.Source("fields.max_description_bytes"),
+		"fields.max_notes_bytes", cfg.Fields.MaxNotesBytes,
+		"fields.max_notes_bytes_source", cfg.Source("fields.max_notes_bytes"),
+		"create.max_labels", cfg.Create.MaxLabels,
+		"create.max_labels_source", cfg.Source("create.max_labels"),
+		"create.max_deps", cfg.Create.MaxDeps,
+		"create.max_deps_source", cfg.Source("create.max_deps"),
+		"close.max_by_filter", cfg.Close.MaxByFilter,
+		"close.max_by_filter_source", cfg.Source("close.max_by_filter"),
+		"undo.window", cfg.Undo.Window,
+		"undo.window_source", cfg.Source("undo.window"),
+		"reports.default_limit", cfg.Reports.DefaultLimit,
+		"reports.default_limit_source", cfg.Source("reports.default_limit"),
+		"reports.max_limit", cfg.Reports.MaxLimit,
+		"reports.max_limit_source", cfg.Source("reports.max_limit"),
+		"reports.task_url", cfg.Reports.TaskURL,
+		"reports.markdown_template", cfg.Reports.MarkdownTemplate,
+		"responses.default_includes.list", strings.Join(cfg.Responses.DefaultIncludes.List, ","),
+		"responses.default_includes.list_source", cfg.Source("responses.default_includes.list"),
+		"responses.default_includes.get", strings.Join(cfg.Responses.DefaultIncludes.Get, ","),
+		"responses.default_includes.get_source", cfg.Source("responses.default_includes.get"),
+		"import.source_label", cfg.Import.SourceLabel,
+		"import.source_label_source", cfg.Source("import.source_label"),
+		"git.github_api_url", cfg.Git.GitHubAPIURL,
+		"git.gitlab_api_url", cfg.Git.GitLabAPIURL,
+		"loaded_config_paths", strings.Join(cfg.LoadedPaths(), ","),
+	)
 }
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"net/url"
-
 	"github.com/spf13/cobra"
 
 	"grns/internal/api"
@@ -21,15 +19,7 @@ func newStaleCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
 		Short: "List stale tasks",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return withClient(cfg, func(client *api.Client) error {
-				query := url.Values{}
-				if days > 0 {
-					query.Set("days", intToString(days))
-				}
-				setIfNotEmpty(query, "status", status)
-				if limit > 0 {
-					query.Set("limit", intToString(limit))
-				}
-				resp, err := client.Stale(cmd.Context(), query)
+				resp, err := client.StaleWithOptions(cmd.Context(), api.StaleOptions{Days: days, Statuses: splitCommaList(status), Limit: limit})
 				if err != nil {
 					return err
 				}
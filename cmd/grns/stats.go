@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newStatsCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	cmd := &cobra.Command{Use: "stats", Short: "Show task statistics"}
+	cmd.AddCommand(
+		newStatsSummaryCmd(cfg, jsonOutput),
+		newStatsFlowCmd(cfg, jsonOutput),
+	)
+	return cmd
+}
+
+func newStatsSummaryCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var (
+		from        string
+		to          string
+		interval    string
+		allProjects bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "summary",
+		Short: "Show task throughput statistics",
+		Long: "Show tasks created and closed per day or week, average time to close, the age of " +
+			"open tasks, and counts by type, assignee, and label.\n\n" +
+			"--from (inclusive) and --to (exclusive) take RFC3339 or YYYY-MM-DD and limit " +
+			"everything except open task ages.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			if from != "" {
+				query.Set("from", from)
+			}
+			if to != "" {
+				query.Set("to", to)
+			}
+			if interval != "" {
+				query.Set("interval", interval)
+			}
+
+			return withClient(cfg, func(client *api.Client) error {
+				get := client.GetProjectStats
+				if allProjects {
+					get = client.GetStats
+				}
+				resp, err := get(cmd.Context(), query)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				return writeStats(resp)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "count tasks from this time (RFC3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&to, "to", "", "count tasks before this time (RFC3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&interval, "interval", "", "bucket size: day|week (default day)")
+	cmd.Flags().BoolVar(&allProjects, "all-projects", false, "include every project")
+	return cmd
+}
+
+func newStatsFlowCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var (
+		window      string
+		allProjects bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "flow",
+		Short: "Show daily task counts per status for cumulative flow charts",
+		Long: "Show the number of tasks in each status at the end of each day in the window. " +
+			"Past days come from hourly server snapshots and are missing while the server was " +
+			"down; today shows live counts.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			if window != "" {
+				query.Set("window", window)
+			}
+			return withClient(cfg, func(client *api.Client) error {
+				get := client.GetProjectStatsFlow
+				if allProjects {
+					get = client.GetStatsFlow
+				}
+				resp, err := get(cmd.Context(), query)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(resp)
+				}
+				return writeStatsFlow(resp)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&window, "window", "", "number of days to show, e.g. 30d (default 30d)")
+	cmd.Flags().BoolVar(&allProjects, "all-projects", false, "include every project")
+	return cmd
+}
+
+func writeStats(resp api.StatsResponse) error {
+	lines := []string{"created:"}
+	lines = append(lines, formatStatsBuckets(resp.Created)...)
+	lines = append(lines, "closed:")
+	lines = append(lines, formatStatsBuckets(resp.Closed)...)
+	lines = append(lines, "time_to_close:",
+		fmt.Sprintf("  count: %d", resp.TimeToClose.Count),
+		fmt.Sprintf("  average_hours: %.2f", resp.TimeToClose.AverageHours))
+	lines = append(lines, "open_age:")
+	for _, bucket := range resp.OpenAge {
+		lines = append(lines, fmt.Sprintf("  %s: %d", bucket.Label, bucket.Count))
+	}
+	for _, group := range []struct {
+		name   string
+		counts map[string]int
+	}{
+		{"by_type", resp.ByType},
+		{"by_assignee", resp.ByAssignee},
+		{"by_label", resp.ByLabel},
+	} {
+		lines = append(lines, group.name+":")
+		keys := make([]string, 0, len(group.counts))
+		for key := range group.counts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			name := key
+			if name == "" {
+				name = "(none)"
+			}
+			lines = append(lines, fmt.Sprintf("  %s: %d", name, group.counts[key]))
+		}
+	}
+	return writePlain("%s\n", strings.Join(lines, "\n"))
+}
+
+func formatStatsBuckets(buckets []api.StatsBucket) []string {
+	lines := make([]string, 0, len(buckets))
+	for _, bucket := range buckets {
+		lines = append(lines, fmt.Sprintf("  %s: %d", bucket.Start, bucket.Count))
+	}
+	return lines
+}
+
+func writeStatsFlow(resp api.FlowResponse) error {
+	if err := writePlain("date        %s\n", strings.Join(resp.Statuses, " ")); err != nil {
+		return err
+	}
+	for _, day := range resp.Days {
+		cells := make([]string, len(resp.Statuses))
+		for i, status := range resp.Statuses {
+			cells[i] = fmt.Sprintf("%*d", len(status), day.Counts[status])
+		}
+		if err := writePlain("%s  %s\n", day.Date, strings.Join(cells, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+)
+
+func newTouchCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var actor string
+	cmd := &cobra.Command{
+		Use:   "touch <id> [<id>...]",
+		Short: "Mark tasks as reviewed by bumping updated_at",
+		Args:  requireAtLeastOneID,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIDsMutation(cfg, *jsonOutput, cmd.Context(), args,
+				func(ctx context.Context, client *api.Client, ids []string) (any, error) {
+					return client.TouchTasks(ctx, api.TaskTouchRequest{IDs: ids, Actor: actor})
+				},
+			)
+		},
+	}
+	cmd.Flags().StringVar(&actor, "actor", "", "who reviewed the tasks (recorded in the server log)")
+
+	return cmd
+}
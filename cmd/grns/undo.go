@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"grns/internal/api"
+	"grns/internal/config"
+	"grns/internal/models"
+)
+
+func newUndoCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "undo [<operation-id>]",
+		Short: "Undo the last close, reopen, bulk update or label delete",
+		Long: "Reverse a recent close, reopen, bulk update or project-wide label delete.\n\n" +
+			"Without an id the newest operation not yet undone is reversed. Operations stay undoable " +
+			"for undo.window (default 1h) and only while none of their tasks has changed since.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var id int64
+			if len(args) == 1 {
+				parsed, err := strconv.ParseInt(args[0], 10, 64)
+				if err != nil || parsed <= 0 {
+					return fmt.Errorf("invalid operation id %q", args[0])
+				}
+				id = parsed
+			}
+			if list && id > 0 {
+				return fmt.Errorf("--list does not take an operation id")
+			}
+
+			return withClient(cfg, func(client *api.Client) error {
+				if list {
+					ops, err := client.ListOperations(cmd.Context())
+					if err != nil {
+						return err
+					}
+					if *jsonOutput {
+						return writeJSON(ops)
+					}
+					return writeOperations(ops)
+				}
+
+				op, err := client.UndoOperation(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+				if *jsonOutput {
+					return writeJSON(op)
+				}
+				return writePlain("undone operation %d (%s): %s\n", op.ID, op.Kind, strings.Join(op.TaskIDs, ", "))
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "list operations that can still be undone")
+	return cmd
+}
+
+func writeOperations(ops []models.Operation) error {
+	if len(ops) == 0 {
+		return writePlain("no operations within the undo window\n")
+	}
+	if err := writePlain("ID\tTIME\tKIND\tACTOR\tTASKS\tSTATE\n"); err != nil {
+		return err
+	}
+	for _, op := range ops {
+		actor := op.Actor
+		if actor == "" {
+			actor = "-"
+		}
+		kind := string(op.Kind)
+		if op.Label != "" {
+			kind += " " + op.Label
+		}
+		state := "undoable"
+		if op.UndoneAt != nil {
+			state = "undone"
+		}
+		if err := writePlain("%d\t%s\t%s\t%s\t%d\t%s\n", op.ID, op.CreatedAt.Format(time.RFC3339), kind, actor, len(op.TaskIDs), state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
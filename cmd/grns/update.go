@@ -23,8 +23,12 @@ type updateCmdOptions struct {
 	design             string
 	acceptanceCriteria string
 	sourceRepo         string
+	milestoneID        string
+	blockedReason      string
+	blockedOn          string
 	customKV           []string
 	customJSON         string
+	force              bool
 }
 
 func newUpdateCmd(cfg *config.Config, jsonOutput *bool) *cobra.Command {
@@ -108,6 +112,15 @@ func buildUpdateRequest(cmd *cobra.Command, opts *updateCmdOptions) (api.TaskUpd
 	if cmd.Flags().Changed("source-repo") {
 		req.SourceRepo = &opts.sourceRepo
 	}
+	if cmd.Flags().Changed("milestone") {
+		req.MilestoneID = &opts.milestoneID
+	}
+	if cmd.Flags().Changed("blocked-reason") {
+		req.BlockedReason = &opts.blockedReason
+	}
+	if cmd.Flags().Changed("blocked-on") {
+		req.BlockedOn = &opts.blockedOn
+	}
 	if len(opts.customKV) > 0 || opts.customJSON != "" {
 		m, err := parseCustomFlags(opts.customKV, opts.customJSON)
 		if err != nil {
@@ -115,6 +128,7 @@ func buildUpdateRequest(cmd *cobra.Command, opts *updateCmdOptions) (api.TaskUpd
 		}
 		req.Custom = m
 	}
+	req.Force = opts.force
 
 	return req, nil
 }
@@ -132,6 +146,9 @@ func hasTaskUpdateFields(req api.TaskUpdateRequest) bool {
 		req.Design != nil ||
 		req.AcceptanceCriteria != nil ||
 		req.SourceRepo != nil ||
+		req.MilestoneID != nil ||
+		req.BlockedReason != nil ||
+		req.BlockedOn != nil ||
 		req.Custom != nil
 }
 
@@ -148,6 +165,10 @@ func bindUpdateFlags(cmd *cobra.Command, opts *updateCmdOptions) {
 	cmd.Flags().StringVar(&opts.design, "design", "", "design")
 	cmd.Flags().StringVar(&opts.acceptanceCriteria, "acceptance", "", "acceptance criteria")
 	cmd.Flags().StringVar(&opts.sourceRepo, "source-repo", "", "source repository")
+	cmd.Flags().StringVar(&opts.milestoneID, "milestone", "", "milestone id (empty clears)")
+	cmd.Flags().StringVar(&opts.blockedReason, "blocked-reason", "", "why the task is blocked (status blocked only)")
+	cmd.Flags().StringVar(&opts.blockedOn, "blocked-on", "", "what the task is waiting on (status blocked only)")
 	cmd.Flags().StringSliceVar(&opts.customKV, "custom", nil, "custom field key=value (repeatable)")
 	cmd.Flags().StringVar(&opts.customJSON, "custom-json", "", "custom fields as JSON object")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "bypass configured wip limits")
 }
@@ -1,6 +1,10 @@
 package api
 
-import "time"
+import (
+	"time"
+
+	"grns/internal/models"
+)
 
 // AttachmentUploadRequest defines request metadata for multipart managed uploads.
 type AttachmentUploadRequest struct {
@@ -10,6 +14,9 @@ type AttachmentUploadRequest struct {
 	MediaType string     `json:"media_type,omitempty"`
 	Labels    []string   `json:"labels,omitempty"`
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// ContentSHA256 is the client's hex SHA-256 of the content; the server rejects the upload on mismatch.
+	ContentSHA256 string `json:"content_sha256,omitempty"`
 }
 
 // AttachmentCreateLinkRequest defines JSON payload for creating a link/repo attachment.
@@ -25,6 +32,16 @@ type AttachmentCreateLinkRequest struct {
 	ExpiresAt   *time.Time     `json:"expires_at,omitempty"`
 }
 
+// AttachmentUpdateRequest defines the JSON payload for PATCH /v1/attachments/{attachment_id}.
+// Omitted fields are left unchanged; labels and meta replace the existing values.
+type AttachmentUpdateRequest struct {
+	Title          *string         `json:"title,omitempty"`
+	Labels         *[]string       `json:"labels,omitempty"`
+	Meta           *map[string]any `json:"meta,omitempty"`
+	ExpiresAt      *time.Time      `json:"expires_at,omitempty"`
+	ClearExpiresAt bool            `json:"clear_expires_at,omitempty"`
+}
+
 // BlobGCRequest requests one blob garbage-collection run.
 type BlobGCRequest struct {
 	DryRun    bool `json:"dry_run"`
@@ -39,3 +56,26 @@ type BlobGCResponse struct {
 	ReclaimedBytes int64 `json:"reclaimed_bytes"`
 	DryRun         bool  `json:"dry_run"`
 }
+
+// BlobUsageResponse summarizes blob storage for GET /v1/admin/blob-usage.
+type BlobUsageResponse struct {
+	models.BlobUsageStats
+	DedupSavedBytes    int64              `json:"dedup_saved_bytes"`
+	AttachmentsPerBlob float64            `json:"attachments_per_blob"`
+	Largest            []models.BlobUsage `json:"largest_blobs"`
+}
+
+// DanglingAttachmentsRequest requests cleanup of attachments whose blob row is missing.
+// Action is "delete" or "mark".
+type DanglingAttachmentsRequest struct {
+	Action string `json:"action"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// DanglingAttachmentsResponse lists attachments whose blob row is missing.
+type DanglingAttachmentsResponse struct {
+	Attachments   []models.Attachment `json:"attachments"`
+	Action        string              `json:"action,omitempty"`
+	AffectedCount int                 `json:"affected_count"`
+	DryRun        bool                `json:"dry_run"`
+}
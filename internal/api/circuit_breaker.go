@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultCircuitOpenDuration = 30 * time.Second
+
+// ErrCircuitOpen matches CircuitOpenError with errors.Is.
+var ErrCircuitOpen = errors.New("api circuit open")
+
+// CircuitBreakerOptions configures the client's circuit breaker. After
+// FailureThreshold consecutive transport errors or 5xx responses the breaker
+// opens and calls fail fast with CircuitOpenError for OpenDuration; then one
+// probe call is let through, closing the breaker on success and reopening it
+// on failure. A zero FailureThreshold disables the breaker.
+type CircuitBreakerOptions struct {
+	FailureThreshold int
+	// OpenDuration defaults to 30s.
+	OpenDuration time.Duration
+}
+
+// CircuitOpenError is returned without contacting the server while the
+// circuit breaker is open.
+type CircuitOpenError struct {
+	Failures int
+	// RetryAt is when the breaker lets a probe through; zero while a probe is in flight.
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	if e.RetryAt.IsZero() {
+		return fmt.Sprintf("api circuit open after %d consecutive failures; probe in progress", e.Failures)
+	}
+	return fmt.Sprintf("api circuit open after %d consecutive failures; retry after %s", e.Failures, e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+type circuitOutcome int
+
+const (
+	circuitSuccess circuitOutcome = iota
+	circuitFailure
+	// circuitNeutral says nothing about server health, e.g. a caller cancel.
+	circuitNeutral
+)
+
+// circuitBreaker is closed while failures < threshold, open until openUntil,
+// and half-open while a single probe is in flight.
+type circuitBreaker struct {
+	mu           sync.Mutex
+	threshold    int
+	openDuration time.Duration
+	failures     int
+	open         bool
+	openUntil    time.Time
+	probing      bool
+	now          func() time.Time
+}
+
+func newCircuitBreaker(opts CircuitBreakerOptions) *circuitBreaker {
+	if opts.FailureThreshold <= 0 {
+		return nil
+	}
+	openDuration := opts.OpenDuration
+	if openDuration <= 0 {
+		openDuration = defaultCircuitOpenDuration
+	}
+	return &circuitBreaker{threshold: opts.FailureThreshold, openDuration: openDuration, now: time.Now}
+}
+
+// before admits an attempt, or fails fast while the breaker is open.
+func (b *circuitBreaker) before() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return nil
+	}
+	if b.probing {
+		return &CircuitOpenError{Failures: b.failures}
+	}
+	if b.now().Before(b.openUntil) {
+		return &CircuitOpenError{Failures: b.failures, RetryAt: b.openUntil}
+	}
+	b.probing = true
+	return nil
+}
+
+// record reports the outcome of an admitted attempt.
+func (b *circuitBreaker) record(outcome circuitOutcome) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	probe := b.probing
+	b.probing = false
+	switch outcome {
+	case circuitSuccess:
+		b.failures = 0
+		b.open = false
+	case circuitFailure:
+		b.failures++
+		if probe || b.failures >= b.threshold {
+			b.open = true
+			b.openUntil = b.now().Add(b.openDuration)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	var attempts int32
+	var healthy atomic.Bool
+	client := NewClientWithOptions("http://example.invalid", ClientOptions{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+			if healthy.Load() {
+				return okInfoResponse(), nil
+			}
+			return unavailableResponse(), nil
+		}),
+		Retry:          RetryPolicy{MaxRetries: -1},
+		CircuitBreaker: CircuitBreakerOptions{FailureThreshold: 3, OpenDuration: time.Minute},
+	})
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		var apiErr *APIError
+		if _, err := client.GetInfo(context.Background()); !errors.As(err, &apiErr) {
+			t.Fatalf("call %d: expected server error, got %v", i, err)
+		}
+	}
+
+	_, err := client.GetInfo(context.Background())
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected open circuit, got %v", err)
+	}
+	if openErr.Failures != 3 || !openErr.RetryAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected open error: %+v", openErr)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("expected open circuit not to contact the server, got %d attempts", got)
+	}
+
+	// A failed probe reopens the breaker.
+	now = now.Add(time.Minute)
+	if _, err := client.GetInfo(context.Background()); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected a probe to reach the server, got %v", err)
+	}
+	if _, err := client.GetInfo(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected failed probe to reopen the circuit, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	healthy.Store(true)
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetInfo(context.Background()); err != nil {
+			t.Fatalf("expected recovery, got %v", err)
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpenAllowsSingleProbe(t *testing.T) {
+	breaker := newCircuitBreaker(CircuitBreakerOptions{FailureThreshold: 1})
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+
+	breaker.record(circuitFailure)
+	now = now.Add(defaultCircuitOpenDuration)
+	if err := breaker.before(); err != nil {
+		t.Fatalf("expected probe to be admitted, got %v", err)
+	}
+	var openErr *CircuitOpenError
+	if err := breaker.before(); !errors.As(err, &openErr) || !openErr.RetryAt.IsZero() {
+		t.Fatalf("expected concurrent call to fail fast during the probe, got %v", err)
+	}
+	breaker.record(circuitNeutral)
+	if err := breaker.before(); err != nil {
+		t.Fatalf("expected a neutral probe to leave room for another, got %v", err)
+	}
+
+	if newCircuitBreaker(CircuitBreakerOptions{}) != nil {
+		t.Fatal("expected zero threshold to disable the breaker")
+	}
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"bytes"
 	"context"
+	crand "crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,12 +30,17 @@ const (
 	apiTokenEnvKey       = "GRNS_API_TOKEN"
 	adminTokenEnvKey     = "GRNS_ADMIN_TOKEN"
 	idempotentRetryCount = 2 // total attempts = retry count + 1
+	idempotencyKeyHeader = "Idempotency-Key"
+	retryAfterRetryCount = 2 // extra attempts granted by Retry-After on any method
 	defaultProject       = "gr"
 )
 
 var (
 	retryBaseDelay = 50 * time.Millisecond
 	retryMaxDelay  = 500 * time.Millisecond
+	// retryAfterMaxDelay bounds how long a Retry-After response may hold a
+	// request; longer waits fail fast instead.
+	retryAfterMaxDelay = 30 * time.Second
 )
 
 // Client is a simple HTTP client for the grns API.
@@ -43,17 +50,16 @@ type Client struct {
 	http       *http.Client
 	authToken  string
 	adminToken string
+	logger     *slog.Logger
+	retry      RetryPolicy
+	budget     *retryBudget
+	breaker    *circuitBreaker
 }
 
 // NewClient creates a new API client.
+// A "unix:///path/to/grns.sock" base URL dials the server's unix socket.
 func NewClient(baseURL string) *Client {
-	return &Client{
-		baseURL:    strings.TrimRight(baseURL, "/"),
-		project:    defaultProject,
-		http:       &http.Client{Timeout: httpTimeoutFromEnv()},
-		authToken:  strings.TrimSpace(os.Getenv(apiTokenEnvKey)),
-		adminToken: strings.TrimSpace(os.Getenv(adminTokenEnvKey)),
-	}
+	return NewClientWithOptions(baseURL, ClientOptions{})
 }
 
 // SetProject sets the default project used for project-scoped endpoints.
@@ -87,17 +93,66 @@ func (c *Client) GetInfo(ctx context.Context) (InfoResponse, error) {
 	return resp, err
 }
 
+// GetProjectInfo returns task counts for the client's project from /v1/projects/{project}/info.
+func (c *Client) GetProjectInfo(ctx context.Context) (InfoResponse, error) {
+	var resp InfoResponse
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/info"), nil, nil, &resp)
+	return resp, err
+}
+
+// GetStats returns task throughput statistics across all projects from /v1/stats.
+func (c *Client) GetStats(ctx context.Context, query url.Values) (StatsResponse, error) {
+	var resp StatsResponse
+	err := c.do(ctx, http.MethodGet, "/v1/stats", query, nil, &resp)
+	return resp, err
+}
+
+// GetProjectStats returns task throughput statistics for the client's project from /v1/projects/{project}/stats.
+func (c *Client) GetProjectStats(ctx context.Context, query url.Values) (StatsResponse, error) {
+	var resp StatsResponse
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/stats"), query, nil, &resp)
+	return resp, err
+}
+
+// GetStatsFlow returns daily status counts across all projects from /v1/stats/flow.
+func (c *Client) GetStatsFlow(ctx context.Context, query url.Values) (FlowResponse, error) {
+	var resp FlowResponse
+	err := c.do(ctx, http.MethodGet, "/v1/stats/flow", query, nil, &resp)
+	return resp, err
+}
+
+// GetProjectStatsFlow returns daily status counts for the client's project from /v1/projects/{project}/stats/flow.
+func (c *Client) GetProjectStatsFlow(ctx context.Context, query url.Values) (FlowResponse, error) {
+	var resp FlowResponse
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/stats/flow"), query, nil, &resp)
+	return resp, err
+}
+
+// Capabilities returns enabled server features and limits.
+func (c *Client) Capabilities(ctx context.Context) (CapabilitiesResponse, error) {
+	var resp CapabilitiesResponse
+	err := c.do(ctx, http.MethodGet, "/v1/capabilities", nil, nil, &resp)
+	return resp, err
+}
+
 // CreateTask creates a task via POST /v1/tasks.
 func (c *Client) CreateTask(ctx context.Context, req TaskCreateRequest) (TaskResponse, error) {
 	var resp TaskResponse
-	err := c.do(ctx, http.MethodPost, c.scopedPath("/tasks"), nil, req, &resp)
+	err := c.doIdempotent(ctx, http.MethodPost, c.scopedPath("/tasks"), nil, req, &resp)
 	return resp, err
 }
 
 // BatchCreate creates tasks in a single request via POST /v1/tasks/batch.
 func (c *Client) BatchCreate(ctx context.Context, req []TaskCreateRequest) ([]TaskResponse, error) {
 	var resp []TaskResponse
-	err := c.do(ctx, http.MethodPost, c.scopedPath("/tasks/batch"), nil, req, &resp)
+	err := c.doIdempotent(ctx, http.MethodPost, c.scopedPath("/tasks/batch"), nil, req, &resp)
+	return resp, err
+}
+
+// RunBatch applies named task operations in one transaction via POST /v1/rpc.
+func (c *Client) RunBatch(ctx context.Context, ops []RPCOp) (RPCResponse, error) {
+	var resp RPCResponse
+	err := c.doIdempotent(ctx, http.MethodPost, c.scopedPath("/rpc"), nil, RPCRequest{Ops: ops}, &resp)
 	return resp, err
 }
 
@@ -122,6 +177,14 @@ func (c *Client) UpdateTask(ctx context.Context, id string, req TaskUpdateReques
 	return resp, err
 }
 
+// UpsertTask creates task id from req, or updates it when it already exists,
+// via PUT /v1/tasks/{id}. The response reports which one happened.
+func (c *Client) UpsertTask(ctx context.Context, id string, req TaskCreateRequest) (TaskUpsertResponse, error) {
+	var resp TaskUpsertResponse
+	err := c.do(ctx, http.MethodPut, c.scopedPath("/tasks/"+url.PathEscape(id)), nil, req, &resp)
+	return resp, err
+}
+
 // ListTasks returns tasks matching query filters via GET /v1/tasks.
 func (c *Client) ListTasks(ctx context.Context, query url.Values) ([]TaskResponse, error) {
 	var resp []TaskResponse
@@ -136,6 +199,13 @@ func (c *Client) Ready(ctx context.Context, query url.Values) ([]TaskResponse, e
 	return resp, err
 }
 
+// ClaimNextTask leases the top ready task to the caller via POST /v1/tasks/next/claim.
+func (c *Client) ClaimNextTask(ctx context.Context, req TaskClaimRequest) (TaskClaimResponse, error) {
+	var resp TaskClaimResponse
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/tasks/next/claim"), nil, req, &resp)
+	return resp, err
+}
+
 // Stale returns stale tasks via GET /v1/tasks/stale.
 func (c *Client) Stale(ctx context.Context, query url.Values) ([]TaskResponse, error) {
 	var resp []TaskResponse
@@ -146,7 +216,65 @@ func (c *Client) Stale(ctx context.Context, query url.Values) ([]TaskResponse, e
 // CloseTasks closes one or more tasks via POST /v1/tasks/close.
 func (c *Client) CloseTasks(ctx context.Context, req TaskCloseRequest) (map[string]any, error) {
 	var resp map[string]any
-	err := c.do(ctx, http.MethodPost, c.scopedPath("/tasks/close"), nil, req, &resp)
+	err := c.doIdempotent(ctx, http.MethodPost, c.scopedPath("/tasks/close"), nil, req, &resp)
+	return resp, err
+}
+
+// CloseTasksByFilter closes every open task matching a filter via POST /v1/tasks/close-by-filter.
+// Unless req.DryRun is set, confirm must be true so that X-Confirm is sent.
+func (c *Client) CloseTasksByFilter(ctx context.Context, req TaskCloseByFilterRequest, confirm bool) (TaskCloseByFilterResponse, error) {
+	var resp TaskCloseByFilterResponse
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+c.scopedPath("/tasks/close-by-filter"), bytes.NewReader(payload))
+	if err != nil {
+		return resp, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if confirm {
+		httpReq.Header.Set("X-Confirm", "true")
+	}
+	c.setAuthHeader(httpReq)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return resp, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode >= 400 {
+		return resp, decodeError(httpResp)
+	}
+	err = json.NewDecoder(httpResp.Body).Decode(&resp)
+	return resp, err
+}
+
+// BulkUpdateTasks applies one update to many tasks via POST /v1/tasks/bulk-update.
+func (c *Client) BulkUpdateTasks(ctx context.Context, req TaskBulkUpdateRequest) (TaskBulkUpdateResponse, error) {
+	var resp TaskBulkUpdateResponse
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/tasks/bulk-update"), nil, req, &resp)
+	return resp, err
+}
+
+// DeleteTask soft-deletes (tombstones) one task via DELETE /v1/tasks/{id}.
+func (c *Client) DeleteTask(ctx context.Context, id string) (map[string]any, error) {
+	var resp map[string]any
+	err := c.do(ctx, http.MethodDelete, c.scopedPath("/tasks/"+url.PathEscape(id)), nil, nil, &resp)
+	return resp, err
+}
+
+// MergeTask folds a duplicate task into a canonical one via POST /v1/tasks/{id}/merge.
+func (c *Client) MergeTask(ctx context.Context, id string, req TaskMergeRequest) (TaskResponse, error) {
+	var resp TaskResponse
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/tasks/"+url.PathEscape(id)+"/merge"), nil, req, &resp)
+	return resp, err
+}
+
+// RestoreTasks restores tombstoned tasks via POST /v1/tasks/restore.
+func (c *Client) RestoreTasks(ctx context.Context, req TaskRestoreRequest) (map[string]any, error) {
+	var resp map[string]any
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/tasks/restore"), nil, req, &resp)
 	return resp, err
 }
 
@@ -157,6 +285,13 @@ func (c *Client) ReopenTasks(ctx context.Context, req TaskReopenRequest) (map[st
 	return resp, err
 }
 
+// TouchTasks bumps updated_at on one or more tasks via POST /v1/tasks/touch.
+func (c *Client) TouchTasks(ctx context.Context, req TaskTouchRequest) (map[string]any, error) {
+	var resp map[string]any
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/tasks/touch"), nil, req, &resp)
+	return resp, err
+}
+
 // DependencyTree returns the dependency tree for a task via GET /v1/tasks/{id}/deps/tree.
 func (c *Client) DependencyTree(ctx context.Context, id string) (DepTreeResponse, error) {
 	var resp DepTreeResponse
@@ -164,6 +299,66 @@ func (c *Client) DependencyTree(ctx context.Context, id string) (DepTreeResponse
 	return resp, err
 }
 
+// DependencyGraph returns the blocks graph among filtered tasks as an adjacency list
+// via GET /v1/tasks/deps/graph?format=json.
+func (c *Client) DependencyGraph(ctx context.Context, query url.Values) (DepGraphResponse, error) {
+	var resp DepGraphResponse
+	query = cloneValues(query)
+	query.Set("format", "json")
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/tasks/deps/graph"), query, nil, &resp)
+	return resp, err
+}
+
+// DependencyGraphDOT streams the blocks graph among filtered tasks as GraphViz DOT to w.
+func (c *Client) DependencyGraphDOT(ctx context.Context, query url.Values, w io.Writer) error {
+	query = cloneValues(query)
+	query.Set("format", "dot")
+	return c.streamText(ctx, c.scopedPath("/tasks/deps/graph"), query, w)
+}
+
+// DependencyTreeText streams a task's dependency tree rendered server-side to w
+// via GET /v1/tasks/{id}/deps/tree?format=dot|mermaid.
+func (c *Client) DependencyTreeText(ctx context.Context, id, format string, w io.Writer) error {
+	query := url.Values{}
+	query.Set("format", format)
+	return c.streamText(ctx, c.scopedPath("/tasks/"+url.PathEscape(id))+"/deps/tree", query, w)
+}
+
+// ReportSummary returns filtered tasks grouped by groupBy via GET /v1/reports/summary.
+func (c *Client) ReportSummary(ctx context.Context, query url.Values) (ReportSummaryResponse, error) {
+	var resp ReportSummaryResponse
+	query = cloneValues(query)
+	query.Set("format", "json")
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/reports/summary"), query, nil, &resp)
+	return resp, err
+}
+
+// ReportSummaryMarkdown streams the summary report rendered as Markdown to w.
+func (c *Client) ReportSummaryMarkdown(ctx context.Context, query url.Values, w io.Writer) error {
+	query = cloneValues(query)
+	query.Set("format", "markdown")
+	return c.streamText(ctx, c.scopedPath("/reports/summary"), query, w)
+}
+
+// streamText copies a non-JSON GET response body to w.
+func (c *Client) streamText(ctx context.Context, path string, query url.Values, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	c.setAuthHeader(req)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return decodeError(resp)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
 // AdminCleanup executes admin cleanup via POST /v1/admin/cleanup.
 // If confirm is true, X-Confirm is sent to execute deletion; otherwise it is a dry-run.
 func (c *Client) AdminCleanup(ctx context.Context, req CleanupRequest, confirm bool) (CleanupResponse, error) {
@@ -194,6 +389,171 @@ func (c *Client) AdminCleanup(ctx context.Context, req CleanupRequest, confirm b
 	return resp, err
 }
 
+// AdminPurge executes admin purge via POST /v1/admin/purge.
+// If confirm is true, X-Confirm is sent to execute deletion; otherwise it is a dry-run.
+func (c *Client) AdminPurge(ctx context.Context, req CleanupRequest, confirm bool) (CleanupResponse, error) {
+	var resp CleanupResponse
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/admin/purge", bytes.NewReader(payload))
+	if err != nil {
+		return resp, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if confirm {
+		httpReq.Header.Set("X-Confirm", "true")
+	}
+	c.setAuthHeader(httpReq)
+	c.setAdminHeader(httpReq)
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return resp, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode >= 400 {
+		return resp, decodeError(httpResp)
+	}
+	err = json.NewDecoder(httpResp.Body).Decode(&resp)
+	return resp, err
+}
+
+// AdminRecompute recomputes store counts and repairs search-index drift via POST /v1/admin/recompute.
+// If confirm is true, X-Confirm is sent to apply repairs; otherwise it is a dry-run.
+func (c *Client) AdminRecompute(ctx context.Context, req RecomputeRequest, confirm bool) (RecomputeResponse, error) {
+	var resp RecomputeResponse
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/admin/recompute", bytes.NewReader(payload))
+	if err != nil {
+		return resp, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if confirm {
+		httpReq.Header.Set("X-Confirm", "true")
+	}
+	c.setAuthHeader(httpReq)
+	c.setAdminHeader(httpReq)
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return resp, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode >= 400 {
+		return resp, decodeError(httpResp)
+	}
+	err = json.NewDecoder(httpResp.Body).Decode(&resp)
+	return resp, err
+}
+
+// AdminMaintenance runs the integrity check and optional VACUUM/FTS rebuild
+// via POST /v1/admin/maintenance. Mutating steps send X-Confirm.
+func (c *Client) AdminMaintenance(ctx context.Context, req MaintenanceRequest) (MaintenanceResponse, error) {
+	var resp MaintenanceResponse
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/admin/maintenance", bytes.NewReader(payload))
+	if err != nil {
+		return resp, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if req.Vacuum || req.RebuildFTS {
+		httpReq.Header.Set("X-Confirm", "true")
+	}
+	c.setAuthHeader(httpReq)
+	c.setAdminHeader(httpReq)
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return resp, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode >= 400 {
+		return resp, decodeError(httpResp)
+	}
+	err = json.NewDecoder(httpResp.Body).Decode(&resp)
+	return resp, err
+}
+
+// AdminStartReindex starts a background search index rebuild via POST
+// /v1/admin/reindex and returns its initial status.
+func (c *Client) AdminStartReindex(ctx context.Context, req ReindexRequest) (ReindexStatus, error) {
+	var resp ReindexStatus
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/admin/reindex", bytes.NewReader(payload))
+	if err != nil {
+		return resp, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Confirm", "true")
+	c.setAuthHeader(httpReq)
+	c.setAdminHeader(httpReq)
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return resp, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode >= 400 {
+		return resp, decodeError(httpResp)
+	}
+	err = json.NewDecoder(httpResp.Body).Decode(&resp)
+	return resp, err
+}
+
+// AdminReindexStatus reports search index rebuild progress via GET /v1/admin/reindex.
+func (c *Client) AdminReindexStatus(ctx context.Context) (ReindexStatus, error) {
+	var resp ReindexStatus
+	err := c.doAdmin(ctx, http.MethodGet, "/v1/admin/reindex", nil, &resp)
+	return resp, err
+}
+
+// AdminMaintenanceMode reports whether writes are paused via GET /v1/admin/maintenance-mode.
+func (c *Client) AdminMaintenanceMode(ctx context.Context) (MaintenanceModeResponse, error) {
+	var resp MaintenanceModeResponse
+	err := c.doAdmin(ctx, http.MethodGet, "/v1/admin/maintenance-mode", nil, &resp)
+	return resp, err
+}
+
+// AdminSetMaintenanceMode pauses or resumes writes via POST /v1/admin/maintenance-mode.
+func (c *Client) AdminSetMaintenanceMode(ctx context.Context, req MaintenanceModeRequest) (MaintenanceModeResponse, error) {
+	var resp MaintenanceModeResponse
+	err := c.doAdmin(ctx, http.MethodPost, "/v1/admin/maintenance-mode", req, &resp)
+	return resp, err
+}
+
+// AdminRenameLabel renames a label across tasks via POST /v1/admin/labels/rename.
+func (c *Client) AdminRenameLabel(ctx context.Context, req LabelRenameRequest) (LabelRenameResponse, error) {
+	var resp LabelRenameResponse
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/admin/labels/rename", bytes.NewReader(payload))
+	if err != nil {
+		return resp, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	c.setAuthHeader(httpReq)
+	c.setAdminHeader(httpReq)
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return resp, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode >= 400 {
+		return resp, decodeError(httpResp)
+	}
+	err = json.NewDecoder(httpResp.Body).Decode(&resp)
+	return resp, err
+}
+
 // AdminUserAdd provisions one local admin user.
 func (c *Client) AdminUserAdd(ctx context.Context, req AdminUserCreateRequest) (AdminUser, error) {
 	var resp AdminUser
@@ -301,15 +661,143 @@ func (c *Client) AdminUserDelete(ctx context.Context, username string) (AdminUse
 	return resp, nil
 }
 
+// AdminTokenCreate issues one named API token via POST /v1/admin/tokens.
+// The response carries the plaintext secret, which is never returned again.
+func (c *Client) AdminTokenCreate(ctx context.Context, req AdminTokenCreateRequest) (AdminToken, error) {
+	var resp AdminToken
+	err := c.doAdmin(ctx, http.MethodPost, "/v1/admin/tokens", req, &resp)
+	return resp, err
+}
+
+// AdminTokenList lists named API tokens via GET /v1/admin/tokens.
+func (c *Client) AdminTokenList(ctx context.Context) ([]AdminToken, error) {
+	var resp []AdminToken
+	err := c.doAdmin(ctx, http.MethodGet, "/v1/admin/tokens", nil, &resp)
+	return resp, err
+}
+
+// AdminTokenRotate replaces a token secret via POST /v1/admin/tokens/{id}/rotate.
+func (c *Client) AdminTokenRotate(ctx context.Context, id string) (AdminToken, error) {
+	var resp AdminToken
+	err := c.doAdmin(ctx, http.MethodPost, "/v1/admin/tokens/"+url.PathEscape(id)+"/rotate", nil, &resp)
+	return resp, err
+}
+
+// AdminTokenDelete revokes one token via DELETE /v1/admin/tokens/{id}.
+func (c *Client) AdminTokenDelete(ctx context.Context, id string) (AdminTokenDeleteResponse, error) {
+	var resp AdminTokenDeleteResponse
+	err := c.doAdmin(ctx, http.MethodDelete, "/v1/admin/tokens/"+url.PathEscape(id), nil, &resp)
+	return resp, err
+}
+
+// AdminAuditList lists admin audit entries newest first via GET /v1/admin/audit.
+func (c *Client) AdminAuditList(ctx context.Context, query AdminAuditQuery) ([]models.AdminAuditEntry, error) {
+	values := url.Values{}
+	if query.Action != "" {
+		values.Set("action", query.Action)
+	}
+	if query.Actor != "" {
+		values.Set("actor", query.Actor)
+	}
+	if query.Since != "" {
+		values.Set("since", query.Since)
+	}
+	if query.Limit > 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.Offset > 0 {
+		values.Set("offset", strconv.Itoa(query.Offset))
+	}
+	path := "/v1/admin/audit"
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	var resp []models.AdminAuditEntry
+	err := c.doAdmin(ctx, http.MethodGet, path, nil, &resp)
+	return resp, err
+}
+
+// AdminBackup asks the server to write a snapshot into its backup.dir via
+// POST /v1/admin/backup.
+func (c *Client) AdminBackup(ctx context.Context) (AdminBackupResponse, error) {
+	var resp AdminBackupResponse
+	err := c.doAdmin(ctx, http.MethodPost, "/v1/admin/backup", AdminBackupRequest{Mode: "dir"}, &resp)
+	return resp, err
+}
+
+// AdminBackupDownload streams a database snapshot from POST /v1/admin/backup
+// into w and returns the number of bytes written.
+func (c *Client) AdminBackupDownload(ctx context.Context, w io.Writer) (int64, error) {
+	payload, err := json.Marshal(AdminBackupRequest{Mode: "stream"})
+	if err != nil {
+		return 0, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/admin/backup", bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	c.setAuthHeader(httpReq)
+	c.setAdminHeader(httpReq)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode >= 400 {
+		return 0, decodeError(httpResp)
+	}
+	written, err := io.Copy(w, httpResp.Body)
+	if err == nil && httpResp.ContentLength >= 0 && written != httpResp.ContentLength {
+		err = fmt.Errorf("backup truncated: got %d of %d bytes", written, httpResp.ContentLength)
+	}
+	return written, err
+}
+
+// doAdmin sends one admin request with auth and admin headers and decodes the
+// JSON response into out.
+func (c *Client) doAdmin(ctx context.Context, method, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	c.setAuthHeader(httpReq)
+	c.setAdminHeader(httpReq)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode >= 400 {
+		return decodeError(httpResp)
+	}
+	return json.NewDecoder(httpResp.Body).Decode(out)
+}
+
 // Import sends an import request.
 func (c *Client) Import(ctx context.Context, req ImportRequest) (ImportResponse, error) {
 	var resp ImportResponse
-	err := c.do(ctx, http.MethodPost, c.scopedPath("/import"), nil, req, &resp)
+	err := c.doIdempotent(ctx, http.MethodPost, c.scopedPath("/import"), nil, req, &resp)
 	return resp, err
 }
 
 // ImportStream sends NDJSON import records to the streaming import endpoint.
-func (c *Client) ImportStream(ctx context.Context, records io.Reader, dryRun bool, dedupe, orphanHandling string, atomic bool) (ImportResponse, error) {
+// With saveReport the server keeps the conflicts for GetImportReport; with
+// remapIDs it gives the records new ids under the client's project.
+func (c *Client) ImportStream(ctx context.Context, records io.Reader, dryRun bool, dedupe, orphanHandling string, atomic, lenient, saveReport, remapIDs bool) (ImportResponse, error) {
 	var resp ImportResponse
 	query := url.Values{}
 	if dryRun {
@@ -324,6 +812,15 @@ func (c *Client) ImportStream(ctx context.Context, records io.Reader, dryRun boo
 	if atomic {
 		query.Set("atomic", "true")
 	}
+	if lenient {
+		query.Set("lenient", "true")
+	}
+	if saveReport {
+		query.Set("save_report", "true")
+	}
+	if remapIDs {
+		query.Set("remap_ids", "true")
+	}
 
 	endpoint := c.baseURL + c.scopedPath("/import/stream")
 	if len(query) > 0 {
@@ -352,6 +849,13 @@ func (c *Client) ImportStream(ctx context.Context, records io.Reader, dryRun boo
 	return resp, nil
 }
 
+// GetImportReport fetches a saved import conflict report via GET /v1/import/reports/{id}.
+func (c *Client) GetImportReport(ctx context.Context, id int64) (models.ImportReport, error) {
+	var resp models.ImportReport
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/import/reports/"+strconv.FormatInt(id, 10)), nil, nil, &resp)
+	return resp, err
+}
+
 // Export streams NDJSON export to a writer.
 func (c *Client) Export(ctx context.Context, w io.Writer) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+c.scopedPath("/export"), nil)
@@ -371,6 +875,29 @@ func (c *Client) Export(ctx context.Context, w io.Writer) error {
 	return err
 }
 
+// ExportChanges streams NDJSON for tasks changed since a cursor or timestamp
+// via GET /v1/export?since=, and returns the cursor to pass next time.
+func (c *Client) ExportChanges(ctx context.Context, w io.Writer, since string) (string, error) {
+	query := url.Values{"since": []string{since}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+c.scopedPath("/export")+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	c.setAuthHeader(req)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", decodeError(resp)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return "", err
+	}
+	return resp.Header.Get("X-Next-Cursor"), nil
+}
+
 // AddDependency creates a dependency edge via POST /v1/deps.
 func (c *Client) AddDependency(ctx context.Context, req DepCreateRequest) (map[string]any, error) {
 	var resp map[string]any
@@ -378,6 +905,13 @@ func (c *Client) AddDependency(ctx context.Context, req DepCreateRequest) (map[s
 	return resp, err
 }
 
+// RemoveDependency deletes one dependency edge via DELETE /v1/deps.
+func (c *Client) RemoveDependency(ctx context.Context, req DepCreateRequest) (map[string]any, error) {
+	var resp map[string]any
+	err := c.do(ctx, http.MethodDelete, c.scopedPath("/deps"), nil, req, &resp)
+	return resp, err
+}
+
 // AddLabels adds labels to a task via POST /v1/tasks/{id}/labels.
 func (c *Client) AddLabels(ctx context.Context, id string, req LabelsRequest) ([]string, error) {
 	var resp []string
@@ -406,6 +940,44 @@ func (c *Client) ListAllLabels(ctx context.Context) ([]string, error) {
 	return resp, err
 }
 
+// DeleteLabel removes a label from every task in the project via DELETE /v1/labels/{label}.
+func (c *Client) DeleteLabel(ctx context.Context, label string) (LabelDeleteResponse, error) {
+	var resp LabelDeleteResponse
+	err := c.do(ctx, http.MethodDelete, c.scopedPath("/labels/"+url.PathEscape(label)), nil, nil, &resp)
+	return resp, err
+}
+
+// ListOperations lists undoable operations newest first via GET /v1/operations.
+func (c *Client) ListOperations(ctx context.Context) ([]models.Operation, error) {
+	var resp []models.Operation
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/operations"), nil, nil, &resp)
+	return resp, err
+}
+
+// UndoOperation reverses one operation via POST /v1/operations/{id}/undo, or
+// the newest one not yet undone via POST /v1/operations/undo when id is 0.
+func (c *Client) UndoOperation(ctx context.Context, id int64) (models.Operation, error) {
+	path := "/operations/undo"
+	if id > 0 {
+		path = "/operations/" + strconv.FormatInt(id, 10) + "/undo"
+	}
+	var resp models.Operation
+	err := c.do(ctx, http.MethodPost, c.scopedPath(path), nil, nil, &resp)
+	return resp, err
+}
+
+// RelatedLabels returns labels that co-occur with label via GET /v1/labels/{label}/related.
+// A zero limit uses the server's report default.
+func (c *Client) RelatedLabels(ctx context.Context, label string, limit int) ([]RelatedLabelResponse, error) {
+	var resp []RelatedLabelResponse
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/labels/"+url.PathEscape(label)+"/related"), query, nil, &resp)
+	return resp, err
+}
+
 // CreateTaskGitRef creates one git reference for a task via POST /v1/tasks/{id}/git-refs.
 func (c *Client) CreateTaskGitRef(ctx context.Context, taskID string, req TaskGitRefCreateRequest) (models.TaskGitRef, error) {
 	var resp models.TaskGitRef
@@ -420,6 +992,171 @@ func (c *Client) ListTaskGitRefs(ctx context.Context, taskID string) ([]models.T
 	return resp, err
 }
 
+// TaskHistory lists a task's recorded mutation events via GET /v1/tasks/{id}/history.
+func (c *Client) TaskHistory(ctx context.Context, taskID string) ([]models.TaskEvent, error) {
+	var resp []models.TaskEvent
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/tasks/"+url.PathEscape(taskID)+"/history"), nil, nil, &resp)
+	return resp, err
+}
+
+// CreateTaskComment adds a comment to a task via POST /v1/tasks/{id}/comments.
+func (c *Client) CreateTaskComment(ctx context.Context, taskID string, req TaskCommentCreateRequest) (models.TaskComment, error) {
+	var resp models.TaskComment
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/tasks/"+url.PathEscape(taskID)+"/comments"), nil, req, &resp)
+	return resp, err
+}
+
+// ListTaskComments lists a task's comments oldest first via GET /v1/tasks/{id}/comments.
+func (c *Client) ListTaskComments(ctx context.Context, taskID string) ([]models.TaskComment, error) {
+	var resp []models.TaskComment
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/tasks/"+url.PathEscape(taskID)+"/comments"), nil, nil, &resp)
+	return resp, err
+}
+
+// CreateTaskWorklog logs time spent on a task via POST /v1/tasks/{id}/worklogs.
+func (c *Client) CreateTaskWorklog(ctx context.Context, taskID string, req TaskWorklogCreateRequest) (models.TaskWorklog, error) {
+	var resp models.TaskWorklog
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/tasks/"+url.PathEscape(taskID)+"/worklogs"), nil, req, &resp)
+	return resp, err
+}
+
+// ListTaskWorklogs lists a task's worklogs by start time via GET /v1/tasks/{id}/worklogs.
+func (c *Client) ListTaskWorklogs(ctx context.Context, taskID string) ([]models.TaskWorklog, error) {
+	var resp []models.TaskWorklog
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/tasks/"+url.PathEscape(taskID)+"/worklogs"), nil, nil, &resp)
+	return resp, err
+}
+
+// WatchTask subscribes user to a task via PUT /v1/tasks/{id}/watchers/{user}.
+func (c *Client) WatchTask(ctx context.Context, taskID, user string) ([]models.TaskWatcher, error) {
+	var resp []models.TaskWatcher
+	err := c.do(ctx, http.MethodPut, c.scopedPath("/tasks/"+url.PathEscape(taskID)+"/watchers/"+url.PathEscape(user)), nil, nil, &resp)
+	return resp, err
+}
+
+// UnwatchTask unsubscribes user from a task via DELETE /v1/tasks/{id}/watchers/{user}.
+func (c *Client) UnwatchTask(ctx context.Context, taskID, user string) ([]models.TaskWatcher, error) {
+	var resp []models.TaskWatcher
+	err := c.do(ctx, http.MethodDelete, c.scopedPath("/tasks/"+url.PathEscape(taskID)+"/watchers/"+url.PathEscape(user)), nil, nil, &resp)
+	return resp, err
+}
+
+// ListTaskWatchers lists a task's watchers via GET /v1/tasks/{id}/watchers.
+func (c *Client) ListTaskWatchers(ctx context.Context, taskID string) ([]models.TaskWatcher, error) {
+	var resp []models.TaskWatcher
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/tasks/"+url.PathEscape(taskID)+"/watchers"), nil, nil, &resp)
+	return resp, err
+}
+
+// CreateSavedFilter stores a named list filter via POST /v1/filters.
+func (c *Client) CreateSavedFilter(ctx context.Context, req SavedFilterRequest) (models.SavedFilter, error) {
+	var resp models.SavedFilter
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/filters"), nil, req, &resp)
+	return resp, err
+}
+
+// ListSavedFilters lists saved filters via GET /v1/filters.
+func (c *Client) ListSavedFilters(ctx context.Context) ([]models.SavedFilter, error) {
+	var resp []models.SavedFilter
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/filters"), nil, nil, &resp)
+	return resp, err
+}
+
+// GetSavedFilter fetches one saved filter by name via GET /v1/filters/{name}.
+func (c *Client) GetSavedFilter(ctx context.Context, name string) (models.SavedFilter, error) {
+	var resp models.SavedFilter
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/filters/"+url.PathEscape(name)), nil, nil, &resp)
+	return resp, err
+}
+
+// ReplaceSavedFilter replaces a saved filter's parameters via PUT /v1/filters/{name}.
+func (c *Client) ReplaceSavedFilter(ctx context.Context, name string, req SavedFilterRequest) (models.SavedFilter, error) {
+	var resp models.SavedFilter
+	err := c.do(ctx, http.MethodPut, c.scopedPath("/filters/"+url.PathEscape(name)), nil, req, &resp)
+	return resp, err
+}
+
+// DeleteSavedFilter deletes a saved filter via DELETE /v1/filters/{name}.
+func (c *Client) DeleteSavedFilter(ctx context.Context, name string) (map[string]any, error) {
+	var resp map[string]any
+	err := c.do(ctx, http.MethodDelete, c.scopedPath("/filters/"+url.PathEscape(name)), nil, nil, &resp)
+	return resp, err
+}
+
+// CreateMilestone creates a milestone via POST /v1/milestones.
+func (c *Client) CreateMilestone(ctx context.Context, req MilestoneCreateRequest) (models.Milestone, error) {
+	var resp models.Milestone
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/milestones"), nil, req, &resp)
+	return resp, err
+}
+
+// ListMilestones lists milestones via GET /v1/milestones. An empty state lists all.
+func (c *Client) ListMilestones(ctx context.Context, state string) ([]models.Milestone, error) {
+	var resp []models.Milestone
+	query := url.Values{}
+	if state != "" {
+		query.Set("state", state)
+	}
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/milestones"), query, nil, &resp)
+	return resp, err
+}
+
+// GetMilestone fetches one milestone with burndown counts via GET /v1/milestones/{id}.
+func (c *Client) GetMilestone(ctx context.Context, id string) (MilestoneResponse, error) {
+	var resp MilestoneResponse
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/milestones/"+url.PathEscape(id)), nil, nil, &resp)
+	return resp, err
+}
+
+// UpdateMilestone updates a milestone via PATCH /v1/milestones/{id}.
+func (c *Client) UpdateMilestone(ctx context.Context, id string, req MilestoneUpdateRequest) (MilestoneResponse, error) {
+	var resp MilestoneResponse
+	err := c.do(ctx, http.MethodPatch, c.scopedPath("/milestones/"+url.PathEscape(id)), nil, req, &resp)
+	return resp, err
+}
+
+// DeleteMilestone deletes a milestone via DELETE /v1/milestones/{id}.
+func (c *Client) DeleteMilestone(ctx context.Context, id string) (map[string]any, error) {
+	var resp map[string]any
+	err := c.do(ctx, http.MethodDelete, c.scopedPath("/milestones/"+url.PathEscape(id)), nil, nil, &resp)
+	return resp, err
+}
+
+// ListCustomFields lists the project's custom field schema via GET /v1/custom-fields.
+func (c *Client) ListCustomFields(ctx context.Context) ([]models.CustomField, error) {
+	var resp []models.CustomField
+	err := c.do(ctx, http.MethodGet, c.scopedPath("/custom-fields"), nil, nil, &resp)
+	return resp, err
+}
+
+// PutCustomField creates or replaces a custom field definition via PUT /v1/custom-fields/{name}.
+func (c *Client) PutCustomField(ctx context.Context, name string, req CustomFieldRequest) (models.CustomField, error) {
+	var resp models.CustomField
+	err := c.do(ctx, http.MethodPut, c.scopedPath("/custom-fields/"+url.PathEscape(name)), nil, req, &resp)
+	return resp, err
+}
+
+// DeleteCustomField deletes a custom field definition via DELETE /v1/custom-fields/{name}.
+func (c *Client) DeleteCustomField(ctx context.Context, name string) (map[string]any, error) {
+	var resp map[string]any
+	err := c.do(ctx, http.MethodDelete, c.scopedPath("/custom-fields/"+url.PathEscape(name)), nil, nil, &resp)
+	return resp, err
+}
+
+// TasksByCommits returns tasks linked to any commit via POST /v1/git-refs/by-commits.
+func (c *Client) TasksByCommits(ctx context.Context, req TaskGitRefByCommitsRequest) ([]TaskResponse, error) {
+	var resp []TaskResponse
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/git-refs/by-commits"), nil, req, &resp)
+	return resp, err
+}
+
+// ScanGitRefs links tasks mentioned in commit messages via POST /v1/git-refs/scan.
+func (c *Client) ScanGitRefs(ctx context.Context, req GitRefScanRequest) (GitRefScanResponse, error) {
+	var resp GitRefScanResponse
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/git-refs/scan"), nil, req, &resp)
+	return resp, err
+}
+
 // GetTaskGitRef fetches one git reference by id via GET /v1/git-refs/{ref_id}.
 func (c *Client) GetTaskGitRef(ctx context.Context, refID string) (models.TaskGitRef, error) {
 	var resp models.TaskGitRef
@@ -427,6 +1164,14 @@ func (c *Client) GetTaskGitRef(ctx context.Context, refID string) (models.TaskGi
 	return resp, err
 }
 
+// ResolveTaskGitRef fills resolved_commit and pull request URLs from the hosting
+// provider via POST /v1/git-refs/{ref_id}/resolve.
+func (c *Client) ResolveTaskGitRef(ctx context.Context, refID string) (models.TaskGitRef, error) {
+	var resp models.TaskGitRef
+	err := c.do(ctx, http.MethodPost, c.scopedPath("/git-refs/"+url.PathEscape(refID)+"/resolve"), nil, nil, &resp)
+	return resp, err
+}
+
 // DeleteTaskGitRef deletes one git reference by id via DELETE /v1/git-refs/{ref_id}.
 func (c *Client) DeleteTaskGitRef(ctx context.Context, refID string) (map[string]any, error) {
 	var resp map[string]any
@@ -491,6 +1236,9 @@ func (c *Client) CreateTaskAttachment(ctx context.Context, taskID string, req At
 		return resp, err
 	}
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	if sum := strings.TrimSpace(req.ContentSHA256); sum != "" {
+		httpReq.Header.Set("X-Content-SHA256", sum)
+	}
 	c.setAuthHeader(httpReq)
 
 	httpResp, err := c.http.Do(httpReq)
@@ -533,23 +1281,83 @@ func (c *Client) GetAttachmentContent(ctx context.Context, attachmentID string,
 	if w == nil {
 		return fmt.Errorf("writer is required")
 	}
+	body, _, err := c.OpenAttachmentContent(ctx, attachmentID, 0)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, err = io.Copy(w, body)
+	return err
+}
+
+// OpenAttachmentContent opens managed content via GET /v1/attachments/{attachment_id}/content,
+// asking for the bytes from offset on when offset > 0. It returns the offset the
+// body starts at, which is 0 when the server sent the whole content. When offset
+// is already the content length the body is empty.
+func (c *Client) OpenAttachmentContent(ctx context.Context, attachmentID string, offset int64) (io.ReadCloser, int64, error) {
 	endpoint := c.baseURL + c.scopedPath("/attachments/"+url.PathEscape(attachmentID)) + "/content"
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
-		return err
+		return nil, 0, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
 	}
 	c.setAuthHeader(req)
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		return decodeError(resp)
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		return resp.Body, offset, nil
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		resp.Body.Close()
+		if resp.Header.Get("Content-Range") == fmt.Sprintf("bytes */%d", offset) {
+			return io.NopCloser(strings.NewReader("")), offset, nil
+		}
+		return nil, 0, fmt.Errorf("cannot resume at byte %d: the attachment is shorter", offset)
+	case resp.StatusCode >= 400:
+		defer resp.Body.Close()
+		return nil, 0, decodeError(resp)
 	}
-	_, err = io.Copy(w, resp.Body)
-	return err
+	return resp.Body, 0, nil
+}
+
+// UploadBlob stores managed blob content via POST /v1/blobs. A non-empty
+// sha256 is checked by the server against the uploaded bytes.
+func (c *Client) UploadBlob(ctx context.Context, content io.Reader, sha256 string) (models.Blob, error) {
+	var resp models.Blob
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+c.scopedPath("/blobs"), content)
+	if err != nil {
+		return resp, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if sha256 != "" {
+		req.Header.Set("X-Content-SHA256", sha256)
+	}
+	c.setAuthHeader(req)
+
+	httpResp, err := c.http.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode >= 400 {
+		return resp, decodeError(httpResp)
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// UpdateAttachment patches attachment metadata via PATCH /v1/attachments/{attachment_id}.
+func (c *Client) UpdateAttachment(ctx context.Context, attachmentID string, req AttachmentUpdateRequest) (models.Attachment, error) {
+	var resp models.Attachment
+	err := c.do(ctx, http.MethodPatch, c.scopedPath("/attachments/"+url.PathEscape(attachmentID)), nil, req, &resp)
+	return resp, err
 }
 
 // DeleteAttachment deletes an attachment via DELETE /v1/attachments/{attachment_id}.
@@ -590,7 +1398,42 @@ func (c *Client) AdminGCBlobs(ctx context.Context, req BlobGCRequest, confirm bo
 	return resp, err
 }
 
+// AdminBlobUsage reports blob storage and dedup savings via GET /v1/admin/blob-usage.
+// limit caps the largest-blob list; 0 uses the server default.
+func (c *Client) AdminBlobUsage(ctx context.Context, limit int) (BlobUsageResponse, error) {
+	path := "/v1/admin/blob-usage"
+	if limit > 0 {
+		path += "?limit=" + strconv.Itoa(limit)
+	}
+	var resp BlobUsageResponse
+	err := c.doAdmin(ctx, http.MethodGet, path, nil, &resp)
+	return resp, err
+}
+
 func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any, out any) error {
+	return c.doWithIdempotencyKey(ctx, method, path, query, body, out, "")
+}
+
+// doIdempotent sends a write with a fresh Idempotency-Key so that transport
+// and 5xx failures can be retried like a GET: the server replays the stored
+// response instead of applying the write twice.
+func (c *Client) doIdempotent(ctx context.Context, method, path string, query url.Values, body any, out any) error {
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return err
+	}
+	return c.doWithIdempotencyKey(ctx, method, path, query, body, out, key)
+}
+
+func newIdempotencyKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := crand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate idempotency key: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (c *Client) doWithIdempotencyKey(ctx context.Context, method, path string, query url.Values, body any, out any, idempotencyKey string) error {
 	endpoint := c.baseURL + path
 	if len(query) > 0 {
 		endpoint += "?" + query.Encode()
@@ -606,10 +1449,11 @@ func (c *Client) do(ctx context.Context, method, path string, query url.Values,
 	}
 
 	maxAttempts := 1
-	if isIdempotentMethod(method) {
-		maxAttempts += idempotentRetryCount
+	if isIdempotentMethod(method) || idempotencyKey != "" {
+		maxAttempts += c.retry.maxRetries()
 	}
 
+	retryAfterUsed := 0
 	for attempt := 0; attempt < maxAttempts; attempt++ {
 		var reader io.Reader
 		if payload != nil {
@@ -623,23 +1467,47 @@ func (c *Client) do(ctx context.Context, method, path string, query url.Values,
 		if payload != nil {
 			req.Header.Set("Content-Type", "application/json")
 		}
+		if err := c.breaker.before(); err != nil {
+			c.log().Debug("api request short-circuited", "method", method, "path", path, "error", err)
+			return err
+		}
+		if idempotencyKey != "" {
+			req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+		}
 		c.setAuthHeader(req)
 
 		resp, err := c.http.Do(req)
+		c.breaker.record(circuitOutcomeFor(ctx, resp, err))
 		if err != nil {
-			if attempt+1 < maxAttempts && shouldRetryTransport(err) {
-				delay := retryDelay(attempt)
-				slog.Debug("api request retrying after transport error", "method", method, "path", path, "attempt", attempt+1, "max_attempts", maxAttempts, "delay_ms", delay.Milliseconds(), "error", err)
+			if attempt+1 < maxAttempts && shouldRetryTransport(err) && c.budget.allow() {
+				delay := c.retry.delay(attempt)
+				c.log().Debug("api request retrying after transport error", "method", method, "path", path, "attempt", attempt+1, "max_attempts", maxAttempts, "delay_ms", delay.Milliseconds(), "error", err)
 				time.Sleep(delay)
 				continue
 			}
-			slog.Debug("api request transport failure", "method", method, "path", path, "attempt", attempt+1, "max_attempts", maxAttempts, "error", err)
+			c.log().Debug("api request transport failure", "method", method, "path", path, "attempt", attempt+1, "max_attempts", maxAttempts, "error", err)
 			return err
 		}
 
-		if resp.StatusCode >= 500 && attempt+1 < maxAttempts && isRetryableStatus(resp.StatusCode) {
-			delay := retryDelay(attempt)
-			slog.Debug("api request retrying after server error", "method", method, "path", path, "attempt", attempt+1, "max_attempts", maxAttempts, "status", resp.StatusCode, "delay_ms", delay.Milliseconds())
+		// A Retry-After on 429/503 means the server refused the request
+		// without applying it, so even non-idempotent methods may retry.
+		if delay, ok := retryAfterDelay(resp, c.retry.retryAfterMaxDelay()); ok && retryAfterUsed < retryAfterRetryCount && c.budget.allow() {
+			retryAfterUsed++
+			maxAttempts++
+			c.log().Debug("api request retrying after Retry-After", "method", method, "path", path, "attempt", attempt+1, "status", resp.StatusCode, "delay_ms", delay.Milliseconds())
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if err := sleepContext(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// A Retry-After too long to honor fails fast rather than falling back
+		// to the short backoff below.
+		if resp.StatusCode >= 500 && attempt+1 < maxAttempts && isRetryableStatus(resp.StatusCode) && resp.Header.Get("Retry-After") == "" && c.budget.allow() {
+			delay := c.retry.delay(attempt)
+			c.log().Debug("api request retrying after server error", "method", method, "path", path, "attempt", attempt+1, "max_attempts", maxAttempts, "status", resp.StatusCode, "delay_ms", delay.Milliseconds())
 			_, _ = io.Copy(io.Discard, resp.Body)
 			resp.Body.Close()
 			time.Sleep(delay)
@@ -647,14 +1515,14 @@ func (c *Client) do(ctx context.Context, method, path string, query url.Values,
 		}
 
 		if resp.StatusCode >= 400 {
-			slog.Debug("api request failed", "method", method, "path", path, "attempt", attempt+1, "status", resp.StatusCode)
+			c.log().Debug("api request failed", "method", method, "path", path, "attempt", attempt+1, "status", resp.StatusCode)
 			err := decodeError(resp)
 			resp.Body.Close()
 			return err
 		}
 
 		if attempt > 0 {
-			slog.Debug("api request succeeded after retry", "method", method, "path", path, "attempt", attempt+1, "status", resp.StatusCode)
+			c.log().Debug("api request succeeded after retry", "method", method, "path", path, "attempt", attempt+1, "status", resp.StatusCode)
 		}
 
 		if out == nil {
@@ -670,6 +1538,21 @@ func (c *Client) do(ctx context.Context, method, path string, query url.Values,
 	return fmt.Errorf("request failed after retries")
 }
 
+// circuitOutcomeFor classifies an attempt for the circuit breaker. A 5xx with
+// Retry-After is a deliberate refusal from a live server, so it does not count.
+func circuitOutcomeFor(ctx context.Context, resp *http.Response, err error) circuitOutcome {
+	if err != nil {
+		if ctx.Err() != nil {
+			return circuitNeutral
+		}
+		return circuitFailure
+	}
+	if resp.StatusCode >= 500 && resp.Header.Get("Retry-After") == "" {
+		return circuitFailure
+	}
+	return circuitSuccess
+}
+
 func isIdempotentMethod(method string) bool {
 	return method == http.MethodGet
 }
@@ -683,6 +1566,44 @@ func isRetryableStatus(status int) bool {
 	}
 }
 
+// retryAfterDelay parses Retry-After (seconds or HTTP date) on a 429 or 503.
+// It reports false when the header is absent or asks for more than max.
+func retryAfterDelay(resp *http.Response, max time.Duration) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusServiceUnavailable && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		delay = time.Until(at)
+	} else {
+		return 0, false
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > max {
+		return 0, false
+	}
+	return delay, true
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func shouldRetryTransport(err error) bool {
 	if err == nil {
 		return false
@@ -699,10 +1620,10 @@ func shouldRetryTransport(err error) bool {
 	return errors.As(err, &netErr)
 }
 
-func retryDelay(attempt int) time.Duration {
-	delay := retryBaseDelay << attempt
-	if delay > retryMaxDelay {
-		delay = retryMaxDelay
+func retryDelay(attempt int, base, max time.Duration) time.Duration {
+	delay := base << attempt
+	if delay > max {
+		delay = max
 	}
 	jitterMax := delay / 2
 	if jitterMax <= 0 {
@@ -771,3 +1692,11 @@ func httpTimeoutFromEnv() time.Duration {
 
 	return defaultHTTPTimeout
 }
+
+func cloneValues(values url.Values) url.Values {
+	out := url.Values{}
+	for key, list := range values {
+		out[key] = append([]string(nil), list...)
+	}
+	return out
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClientOptions configures NewClientWithOptions. Zero fields keep the
+// defaults NewClient derives from the environment.
+type ClientOptions struct {
+	// HTTPClient is used for requests when set. Its Transport, if any, takes
+	// precedence over Transport, and its own Timeout still applies.
+	HTTPClient *http.Client
+	// Transport replaces the default transport, or the unix socket transport
+	// for "unix://" base URLs.
+	Transport http.RoundTripper
+	// Timeout bounds each request attempt, including reading the body. It
+	// defaults to GRNS_HTTP_TIMEOUT or 10s; WithRequestTimeout overrides it per call.
+	Timeout time.Duration
+	// Logger receives request and retry diagnostics; it defaults to slog.Default().
+	Logger         *slog.Logger
+	Retry          RetryPolicy
+	CircuitBreaker CircuitBreakerOptions
+
+	Project    string
+	AuthToken  string // defaults to GRNS_API_TOKEN
+	AdminToken string // defaults to GRNS_ADMIN_TOKEN
+}
+
+// RetryPolicy tunes how the client retries GETs and writes sent with an
+// Idempotency-Key. Zero fields keep the defaults.
+type RetryPolicy struct {
+	// MaxRetries is the extra attempts after a transport error or 5xx;
+	// negative disables them. Defaults to 2.
+	MaxRetries int
+	// BaseDelay doubles per attempt up to MaxDelay, plus up to 50% jitter.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// RetryAfterMaxDelay is the longest Retry-After the client waits out;
+	// longer ones fail at once. Defaults to 30s.
+	RetryAfterMaxDelay time.Duration
+	// Budget caps retries across all calls on the client to this many per
+	// minute, so a failing server is not hammered. Zero is unlimited.
+	Budget int
+}
+
+func (p RetryPolicy) maxRetries() int {
+	switch {
+	case p.MaxRetries < 0:
+		return 0
+	case p.MaxRetries == 0:
+		return idempotentRetryCount
+	default:
+		return p.MaxRetries
+	}
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	base, max := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = retryBaseDelay
+	}
+	if max <= 0 {
+		max = retryMaxDelay
+	}
+	return retryDelay(attempt, base, max)
+}
+
+func (p RetryPolicy) retryAfterMaxDelay() time.Duration {
+	if p.RetryAfterMaxDelay > 0 {
+		return p.RetryAfterMaxDelay
+	}
+	return retryAfterMaxDelay
+}
+
+// NewClientWithOptions creates an API client configured in code rather than
+// through the environment.
+func NewClientWithOptions(baseURL string, opts ClientOptions) *Client {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = httpTimeoutFromEnv()
+	}
+
+	base := opts.Transport
+	if socketPath, ok := unixSocketPath(baseURL); ok {
+		if base == nil {
+			base = unixSocketTransport(socketPath)
+		}
+		baseURL = unixSocketBaseURL
+	}
+
+	var httpClient *http.Client
+	if opts.HTTPClient != nil {
+		clone := *opts.HTTPClient
+		if clone.Transport != nil {
+			base = clone.Transport
+		}
+		httpClient = &clone
+	} else {
+		httpClient = &http.Client{}
+	}
+	httpClient.Transport = &timeoutTransport{base: &gzipTransport{base: base}, timeout: timeout}
+
+	authToken := strings.TrimSpace(opts.AuthToken)
+	if authToken == "" {
+		authToken = strings.TrimSpace(os.Getenv(apiTokenEnvKey))
+	}
+	adminToken := strings.TrimSpace(opts.AdminToken)
+	if adminToken == "" {
+		adminToken = strings.TrimSpace(os.Getenv(adminTokenEnvKey))
+	}
+	project := defaultProject
+	if opts.Project != "" {
+		project = normalizeProject(opts.Project)
+	}
+
+	var budget *retryBudget
+	if opts.Retry.Budget > 0 {
+		budget = newRetryBudget(opts.Retry.Budget, time.Minute)
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		project:    project,
+		http:       httpClient,
+		authToken:  authToken,
+		adminToken: adminToken,
+		logger:     opts.Logger,
+		retry:      opts.Retry,
+		budget:     budget,
+		breaker:    newCircuitBreaker(opts.CircuitBreaker),
+	}
+}
+
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return slog.Default()
+}
+
+type requestTimeoutKey struct{}
+
+// WithRequestTimeout overrides the client's per-attempt timeout for calls
+// made with the returned context, e.g. to give a large export more time.
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, timeout)
+}
+
+// timeoutTransport bounds each attempt, body included: the deadline is
+// released when the response body is closed.
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := t.timeout
+	if override, ok := req.Context().Value(requestTimeoutKey{}).(time.Duration); ok && override > 0 {
+		timeout = override
+	}
+	if timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// retryBudget is a token bucket holding up to limit retries, refilled evenly
+// over each period.
+type retryBudget struct {
+	mu     sync.Mutex
+	limit  float64
+	rate   float64 // tokens per second
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRetryBudget(limit int, period time.Duration) *retryBudget {
+	return &retryBudget{
+		limit:  float64(limit),
+		rate:   float64(limit) / period.Seconds(),
+		tokens: float64(limit),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// allow spends one retry, reporting false when the budget is exhausted.
+func (b *retryBudget) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.limit {
+		b.tokens = b.limit
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func okInfoResponse() *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"project_prefix":"gr","schema_version":6,"task_counts":{},"total_tasks":0}`)),
+	}
+}
+
+func unavailableResponse() *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(`{"error":"upstream"}`)),
+	}
+}
+
+func TestNewClientWithOptionsUsesTransportTokensAndProject(t *testing.T) {
+	var seen *http.Request
+	client := NewClientWithOptions("http://example.invalid", ClientOptions{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			seen = r
+			return okInfoResponse(), nil
+		}),
+		Project:   "xy",
+		AuthToken: "secret",
+	})
+
+	if _, err := client.GetProjectInfo(context.Background()); err != nil {
+		t.Fatalf("GetProjectInfo: %v", err)
+	}
+	if seen == nil || seen.URL.Path != "/v1/projects/xy/info" {
+		t.Fatalf("unexpected request: %+v", seen)
+	}
+	if got := seen.Header.Get("Authorization"); got != "Bearer secret" {
+		t.Fatalf("expected bearer token, got %q", got)
+	}
+}
+
+func TestNewClientWithOptionsRetryPolicyAndLogger(t *testing.T) {
+	var attempts int32
+	var logs bytes.Buffer
+	client := NewClientWithOptions("http://example.invalid", ClientOptions{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+			return unavailableResponse(), nil
+		}),
+		Logger: slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		Retry:  RetryPolicy{MaxRetries: 4, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	})
+
+	if _, err := client.GetInfo(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 5 {
+		t.Fatalf("expected 5 attempts, got %d", got)
+	}
+	if !strings.Contains(logs.String(), "api request retrying after server error") {
+		t.Fatalf("expected retries on the configured logger, got %q", logs.String())
+	}
+
+	atomic.StoreInt32(&attempts, 0)
+	client.retry = RetryPolicy{MaxRetries: -1}
+	if _, err := client.GetInfo(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("expected retries disabled, got %d attempts", got)
+	}
+}
+
+func TestRetryBudgetLimitsRetriesAcrossCalls(t *testing.T) {
+	var attempts int32
+	client := NewClientWithOptions("http://example.invalid", ClientOptions{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+			return unavailableResponse(), nil
+		}),
+		Retry: RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Budget: 3},
+	})
+
+	for i := 0; i < 3; i++ {
+		_, _ = client.GetInfo(context.Background())
+	}
+	// Three calls with two retries each would make 9 attempts; the budget allows 3 retries.
+	if got := atomic.LoadInt32(&attempts); got != 6 {
+		t.Fatalf("expected 6 attempts, got %d", got)
+	}
+
+	now := time.Now()
+	budget := newRetryBudget(60, time.Minute)
+	budget.now = func() time.Time { return now }
+	budget.last = now
+	budget.tokens = 0
+	if budget.allow() {
+		t.Fatal("expected empty budget to refuse")
+	}
+	now = now.Add(time.Second)
+	if !budget.allow() {
+		t.Fatal("expected budget to refill one retry per second")
+	}
+}
+
+func TestWithRequestTimeoutOverridesClientTimeout(t *testing.T) {
+	client := NewClientWithOptions("http://example.invalid", ClientOptions{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			deadline, ok := r.Context().Deadline()
+			if !ok {
+				t.Fatal("expected a deadline")
+			}
+			if remaining := time.Until(deadline); remaining < time.Minute {
+				<-r.Context().Done()
+				return nil, r.Context().Err()
+			}
+			return okInfoResponse(), nil
+		}),
+		Timeout: 10 * time.Millisecond,
+		Retry:   RetryPolicy{MaxRetries: -1},
+	})
+
+	if _, err := client.GetInfo(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected client timeout, got %v", err)
+	}
+	if _, err := client.GetInfo(WithRequestTimeout(context.Background(), 2*time.Minute)); err != nil {
+		t.Fatalf("expected per-call timeout to allow the request, got %v", err)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"syscall"
@@ -97,15 +99,100 @@ func TestClientDoesNotRetryNonIdempotentPostOn5xx(t *testing.T) {
 	defer ts.Close()
 
 	client := NewClient(ts.URL)
-	_, err := client.CreateTask(context.Background(), TaskCreateRequest{Title: "x"})
+	_, err := client.ClaimNextTask(context.Background(), TaskClaimRequest{})
 	if err == nil {
-		t.Fatal("expected create to fail")
+		t.Fatal("expected claim to fail")
 	}
 	if got := atomic.LoadInt32(&attempts); got != 1 {
 		t.Fatalf("expected one attempt for non-idempotent POST, got %d", got)
 	}
 }
 
+func TestClientRetriesKeyedPostOn5xx(t *testing.T) {
+	origBase := retryBaseDelay
+	origMax := retryMaxDelay
+	retryBaseDelay = time.Millisecond
+	retryMaxDelay = time.Millisecond
+	t.Cleanup(func() {
+		retryBaseDelay = origBase
+		retryMaxDelay = origMax
+	})
+
+	var attempts int32
+	keys := make(chan string, 3)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get(idempotencyKeyHeader)
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`{"error":"upstream"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"gr-ab12","title":"x"}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+	if _, err := client.CreateTask(context.Background(), TaskCreateRequest{Title: "x"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+	first, second := <-keys, <-keys
+	if first == "" || first != second {
+		t.Fatalf("expected the same idempotency key on retry, got %q and %q", first, second)
+	}
+}
+
+func TestClientHonorsRetryAfterOnPost(t *testing.T) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"paused","code":"maintenance_mode","error_code":3004}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"gr-ab12","title":"x"}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+	resp, err := client.CreateTask(context.Background(), TaskCreateRequest{Title: "x"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if resp.Task.ID != "gr-ab12" {
+		t.Fatalf("unexpected task: %+v", resp.Task)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestClientFailsFastOnLongRetryAfter(t *testing.T) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":"paused","code":"maintenance_mode","error_code":3004}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+	_, err := client.CreateTask(context.Background(), TaskCreateRequest{Title: "x"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "maintenance_mode" {
+		t.Fatalf("expected maintenance_mode error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("expected one attempt, got %d", got)
+	}
+}
+
 func TestClientRetriesThenReturnsServerError(t *testing.T) {
 	origBase := retryBaseDelay
 	origMax := retryMaxDelay
@@ -270,6 +357,38 @@ func TestClientAttachmentMethods(t *testing.T) {
 	}
 }
 
+func TestClientOpenAttachmentContentResumes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader("hello world"))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+	body, start, err := client.OpenAttachmentContent(context.Background(), "at-a111", 6)
+	if err != nil {
+		t.Fatalf("OpenAttachmentContent: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if start != 6 || string(data) != "world" {
+		t.Fatalf("expected resume at 6 with %q, got %d %q", "world", start, data)
+	}
+
+	body, start, err = client.OpenAttachmentContent(context.Background(), "at-a111", 11)
+	if err != nil {
+		t.Fatalf("OpenAttachmentContent at end: %v", err)
+	}
+	data, _ = io.ReadAll(body)
+	body.Close()
+	if start != 11 || len(data) != 0 {
+		t.Fatalf("expected an empty body at the end, got %d %q", start, data)
+	}
+
+	if _, _, err := client.OpenAttachmentContent(context.Background(), "at-a111", 20); err == nil {
+		t.Fatalf("expected an error when resuming past the end")
+	}
+}
+
 func TestClientAdminUsers(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
@@ -375,9 +494,9 @@ func TestClientRetryPolicy_TransportErrors(t *testing.T) {
 			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
 		})}
 
-		_, err := client.CreateTask(context.Background(), TaskCreateRequest{Title: "x"})
+		_, err := client.ClaimNextTask(context.Background(), TaskClaimRequest{})
 		if err == nil {
-			t.Fatal("expected CreateTask transport error")
+			t.Fatal("expected ClaimNextTask transport error")
 		}
 		if got := atomic.LoadInt32(&attempts); got != 1 {
 			t.Fatalf("expected one POST attempt, got %d", got)
@@ -390,3 +509,32 @@ type roundTripperFunc func(*http.Request) (*http.Response, error)
 func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
 	return f(r)
 }
+
+func TestClientDialsUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "grns-sock")
+	if err != nil {
+		t.Fatalf("temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "grns.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("listen unix: %v", err)
+	}
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	ts.Listener = listener
+	ts.Start()
+	t.Cleanup(ts.Close)
+
+	client := NewClient("unix://" + socketPath)
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("ping over unix socket: %v", err)
+	}
+}
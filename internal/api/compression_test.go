@@ -0,0 +1,31 @@
+package api
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientDecompressesGzipResponses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("expected Accept-Encoding gzip, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`[{"id":"gr-ab12","title":"x"}]`))
+		_ = gz.Close()
+	}))
+	defer ts.Close()
+
+	tasks, err := NewClient(ts.URL).ListTasks(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "gr-ab12" {
+		t.Fatalf("unexpected tasks: %+v", tasks)
+	}
+}
@@ -13,6 +13,37 @@ type TaskGitRefCreateRequest struct {
 	Meta           map[string]any `json:"meta,omitempty"`
 }
 
+// TaskGitRefByCommitsRequest looks up tasks linked to any of the given commits.
+type TaskGitRefByCommitsRequest struct {
+	Commits  []string `json:"commits"`
+	Relation string   `json:"relation,omitempty"`
+}
+
+// GitRefScanCommit is one commit whose message is scanned for task ids.
+type GitRefScanCommit struct {
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+}
+
+// GitRefScanRequest links tasks mentioned in commit messages via
+// POST /v1/git-refs/scan.
+type GitRefScanRequest struct {
+	Repo    string             `json:"repo"`
+	Commits []GitRefScanCommit `json:"commits"`
+	DryRun  bool               `json:"dry_run,omitempty"`
+}
+
+// GitRefScanResponse reports the mentioned_by refs a scan created (or would
+// create on a dry run), how many already existed, and mentioned ids that are
+// not tasks in the project.
+type GitRefScanResponse struct {
+	Created        []models.TaskGitRef `json:"created"`
+	Existing       int                 `json:"existing"`
+	UnknownTaskIDs []string            `json:"unknown_task_ids,omitempty"`
+	CommitsScanned int                 `json:"commits_scanned"`
+	DryRun         bool                `json:"dry_run"`
+}
+
 // TaskGitRefResponse returns one task git reference.
 type TaskGitRefResponse struct {
 	models.TaskGitRef
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListOptions are the typed filters for GET /v1/tasks. Zero values are left
+// out of the query; Priority, PriorityMin, and PriorityMax are pointers
+// because 0 is a valid priority.
+type ListOptions struct {
+	IDs         []string
+	Statuses    []string
+	Types       []string
+	Labels      []string // tasks must carry every label
+	LabelsAny   []string // tasks must carry at least one label
+	ParentID    string
+	MilestoneID string
+	Assignee    string
+	NoAssignee  bool
+
+	Priority    *int
+	PriorityMin *int
+	PriorityMax *int
+
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	ClosedAfter   time.Time
+	ClosedBefore  time.Time
+
+	TitleContains string
+	DescContains  string
+	NotesContains string
+	Search        string // full-text search query
+	Spec          string // case-insensitive spec_id regex
+
+	Sort   string // comma-separated sort keys, e.g. "priority,-updated_at"
+	Order  string
+	Limit  int
+	Offset int
+	// AfterID switches to keyset pages ordered by id; use with Keyset for the first page.
+	AfterID string
+	Keyset  bool
+}
+
+// Values serializes the options to query parameters.
+func (o ListOptions) Values() url.Values {
+	query := url.Values{}
+	setCSV(query, "id", o.IDs)
+	setCSV(query, "status", o.Statuses)
+	setCSV(query, "type", o.Types)
+	setCSV(query, "label", o.Labels)
+	setCSV(query, "label_any", o.LabelsAny)
+	setString(query, "parent_id", o.ParentID)
+	setString(query, "milestone_id", o.MilestoneID)
+	setString(query, "assignee", o.Assignee)
+	if o.NoAssignee {
+		query.Set("no_assignee", "true")
+	}
+	setIntPtr(query, "priority", o.Priority)
+	setIntPtr(query, "priority_min", o.PriorityMin)
+	setIntPtr(query, "priority_max", o.PriorityMax)
+	setTime(query, "created_after", o.CreatedAfter)
+	setTime(query, "created_before", o.CreatedBefore)
+	setTime(query, "updated_after", o.UpdatedAfter)
+	setTime(query, "updated_before", o.UpdatedBefore)
+	setTime(query, "closed_after", o.ClosedAfter)
+	setTime(query, "closed_before", o.ClosedBefore)
+	setString(query, "title_contains", o.TitleContains)
+	setString(query, "desc_contains", o.DescContains)
+	setString(query, "notes_contains", o.NotesContains)
+	setString(query, "search", o.Search)
+	setString(query, "spec", o.Spec)
+	setString(query, "sort", o.Sort)
+	setString(query, "order", o.Order)
+	setPositiveInt(query, "limit", o.Limit)
+	setPositiveInt(query, "offset", o.Offset)
+	if o.Keyset || o.AfterID != "" {
+		query.Set("after_id", o.AfterID)
+	}
+	return query
+}
+
+// ReadyOptions are the parameters for GET /v1/tasks/ready.
+type ReadyOptions struct {
+	Order string
+	Limit int
+}
+
+// Values serializes the options to query parameters.
+func (o ReadyOptions) Values() url.Values {
+	query := url.Values{}
+	setString(query, "order", o.Order)
+	setPositiveInt(query, "limit", o.Limit)
+	return query
+}
+
+// StaleOptions are the parameters for GET /v1/tasks/stale. Days defaults to
+// 30 on the server.
+type StaleOptions struct {
+	Days     int
+	Statuses []string
+	Limit    int
+}
+
+// Values serializes the options to query parameters.
+func (o StaleOptions) Values() url.Values {
+	query := url.Values{}
+	setPositiveInt(query, "days", o.Days)
+	setCSV(query, "status", o.Statuses)
+	setPositiveInt(query, "limit", o.Limit)
+	return query
+}
+
+// ListTasksWithOptions returns tasks matching typed filters via GET /v1/tasks.
+func (c *Client) ListTasksWithOptions(ctx context.Context, opts ListOptions) ([]TaskResponse, error) {
+	return c.ListTasks(ctx, opts.Values())
+}
+
+// ReadyWithOptions returns ready-to-work tasks via GET /v1/tasks/ready.
+func (c *Client) ReadyWithOptions(ctx context.Context, opts ReadyOptions) ([]TaskResponse, error) {
+	return c.Ready(ctx, opts.Values())
+}
+
+// StaleWithOptions returns stale tasks via GET /v1/tasks/stale.
+func (c *Client) StaleWithOptions(ctx context.Context, opts StaleOptions) ([]TaskResponse, error) {
+	return c.Stale(ctx, opts.Values())
+}
+
+func setString(query url.Values, key, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		query.Set(key, value)
+	}
+}
+
+func setCSV(query url.Values, key string, values []string) {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	if len(parts) > 0 {
+		query.Set(key, strings.Join(parts, ","))
+	}
+}
+
+func setIntPtr(query url.Values, key string, value *int) {
+	if value != nil {
+		query.Set(key, strconv.Itoa(*value))
+	}
+}
+
+func setPositiveInt(query url.Values, key string, value int) {
+	if value > 0 {
+		query.Set(key, strconv.Itoa(value))
+	}
+}
+
+func setTime(query url.Values, key string, value time.Time) {
+	if !value.IsZero() {
+		query.Set(key, value.UTC().Format(time.RFC3339))
+	}
+}
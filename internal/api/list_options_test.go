@@ -0,0 +1,31 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListOptionsValues(t *testing.T) {
+	zero := 0
+	opts := ListOptions{
+		Statuses:     []string{"open", " in_progress ", ""},
+		Labels:       []string{"backend"},
+		PriorityMin:  &zero,
+		CreatedAfter: time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("x", 3600)),
+		Search:       "crash",
+		Limit:        10,
+		Keyset:       true,
+	}
+	got := opts.Values().Encode()
+	want := "after_id=&created_after=2026-01-02T02%3A04%3A05Z&label=backend&limit=10&priority_min=0&search=crash&status=open%2Cin_progress"
+	if got != want {
+		t.Fatalf("unexpected query:\n got %s\nwant %s", got, want)
+	}
+
+	if got := (ListOptions{}).Values(); len(got) != 0 {
+		t.Fatalf("expected empty query for zero options, got %v", got)
+	}
+	if got := (StaleOptions{Days: 7, Statuses: []string{"open"}}).Values().Encode(); got != "days=7&status=open" {
+		t.Fatalf("unexpected stale query: %s", got)
+	}
+}
@@ -21,9 +21,13 @@ type TaskCreateRequest struct {
 	Design             *string             `json:"design,omitempty"`
 	AcceptanceCriteria *string             `json:"acceptance_criteria,omitempty"`
 	SourceRepo         *string             `json:"source_repo,omitempty"`
+	MilestoneID        *string             `json:"milestone_id,omitempty"`
+	BlockedReason      *string             `json:"blocked_reason,omitempty"`
+	BlockedOn          *string             `json:"blocked_on,omitempty"`
 	Custom             map[string]any      `json:"custom,omitempty"`
 	Labels             []string            `json:"labels,omitempty"`
 	Deps               []models.Dependency `json:"deps,omitempty"`
+	Lenient            bool                `json:"lenient,omitempty"`
 }
 
 // TaskUpdateRequest defines the payload for updating a task.
@@ -40,16 +44,71 @@ type TaskUpdateRequest struct {
 	Design             *string        `json:"design,omitempty"`
 	AcceptanceCriteria *string        `json:"acceptance_criteria,omitempty"`
 	SourceRepo         *string        `json:"source_repo,omitempty"`
+	MilestoneID        *string        `json:"milestone_id,omitempty"`
+	BlockedReason      *string        `json:"blocked_reason,omitempty"`
+	BlockedOn          *string        `json:"blocked_on,omitempty"`
 	Custom             map[string]any `json:"custom,omitempty"`
+	Force              bool           `json:"force,omitempty"`
 }
 
 // InfoResponse is the response from GET /v1/info.
 type InfoResponse struct {
 	DBPath        string         `json:"db_path,omitempty"`
 	ProjectPrefix string         `json:"project_prefix"`
+	Project       string         `json:"project,omitempty"`
 	SchemaVersion int            `json:"schema_version"`
 	TaskCounts    map[string]int `json:"task_counts"`
 	TotalTasks    int            `json:"total_tasks"`
+	Projects      []string       `json:"projects,omitempty"`
+	LastBackupAt  *time.Time     `json:"last_backup_at,omitempty"`
+}
+
+// CapabilitiesResponse is the response from GET /v1/capabilities.
+type CapabilitiesResponse struct {
+	APIVersion      string             `json:"api_version"`
+	ProjectPrefix   string             `json:"project_prefix"`
+	ReadOnly        bool               `json:"read_only"`
+	TaskStatuses    []string           `json:"task_statuses"`
+	TaskTypes       []string           `json:"task_types"`
+	DependencyTypes []string           `json:"dependency_types"`
+	Features        CapabilityFeatures `json:"features"`
+	Limits          CapabilityLimits   `json:"limits"`
+}
+
+// CapabilityFeatures reports which optional server features are enabled.
+type CapabilityFeatures struct {
+	Attachments          bool `json:"attachments"`
+	GitRefs              bool `json:"git_refs"`
+	LocalUsers           bool `json:"local_users"`
+	EventLog             bool `json:"event_log"`
+	WIPLimits            bool `json:"wip_limits"`
+	ParentImpliesBlocks  bool `json:"parent_implies_blocks"`
+	AutoCloseParents     bool `json:"auto_close_parents"`
+	RequireBlockedReason bool `json:"require_blocked_reason"`
+	RequireMergedPR      bool `json:"require_merged_pr"`
+	Undo                 bool `json:"undo"`
+}
+
+// CapabilityLimits reports server-enforced limits. Zero MaxListLimit means no server cap.
+type CapabilityLimits struct {
+	MaxListLimit             int            `json:"max_list_limit"`
+	MaxJSONBodyBytes         int64          `json:"max_json_body_bytes"`
+	MaxBatchBodyBytes        int64          `json:"max_batch_body_bytes"`
+	MaxImportBodyBytes       int64          `json:"max_import_body_bytes"`
+	MaxAttachmentUploadBytes int64          `json:"max_attachment_upload_bytes"`
+	MaxDescriptionBytes      int            `json:"max_description_bytes,omitempty"`
+	MaxNotesBytes            int            `json:"max_notes_bytes,omitempty"`
+	MaxCreateLabels          int            `json:"max_create_labels,omitempty"`
+	MaxCreateDeps            int            `json:"max_create_deps,omitempty"`
+	MaxCloseByFilter         int            `json:"max_close_by_filter,omitempty"`
+	DefaultReportLimit       int            `json:"default_report_limit"`
+	MaxReportLimit           int            `json:"max_report_limit"`
+	PriorityMin              int            `json:"priority_min"`
+	PriorityMax              int            `json:"priority_max"`
+	DependencyTreeMaxDepth   int            `json:"dependency_tree_max_depth"`
+	WIPLimits                map[string]int `json:"wip_limits,omitempty"`
+	WIPLimitsPerAssignee     bool           `json:"wip_limits_per_assignee,omitempty"`
+	AssigneeWIPLimit         int            `json:"assignee_wip_limit,omitempty"`
 }
 
 // AuthLoginRequest defines the payload for browser login.
@@ -94,12 +153,103 @@ type AdminUserDeleteResponse struct {
 	Deleted  bool   `json:"deleted"`
 }
 
+// AdminTokenCreateRequest issues one named API token.
+type AdminTokenCreateRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// AdminToken is one named API token. Token carries the plaintext secret only
+// in create and rotate responses.
+type AdminToken struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Expired   bool       `json:"expired"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	Token     string     `json:"token,omitempty"`
+}
+
+// AdminTokenDeleteResponse reports one token revocation.
+type AdminTokenDeleteResponse struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// AdminBackupRequest is the body of POST /v1/admin/backup. Mode is "stream"
+// (send the snapshot in the response) or "dir" (write it to the server's
+// backup.dir); empty picks "dir" when configured, otherwise "stream".
+type AdminBackupRequest struct {
+	Mode string `json:"mode,omitempty"`
+}
+
+// AdminBackupResponse reports a backup written to the server's backup dir.
+type AdminBackupResponse struct {
+	Path      string    `json:"path"`
+	SizeBytes int64     `json:"size_bytes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdminAuditQuery filters GET /v1/admin/audit. Since accepts the same formats
+// as task time filters.
+type AdminAuditQuery struct {
+	Action string
+	Actor  string
+	Since  string
+	Limit  int
+	Offset int
+}
+
 // DepTreeResponse wraps the dependency tree output.
 type DepTreeResponse struct {
 	RootID string               `json:"root_id"`
 	Nodes  []models.DepTreeNode `json:"nodes"`
 }
 
+// DepGraphNode is one task in a dependency graph with the tasks blocking it.
+type DepGraphNode struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	BlockedBy []string `json:"blocked_by"`
+}
+
+// DepGraphResponse is the adjacency-list form of a dependency graph.
+type DepGraphResponse struct {
+	Nodes []DepGraphNode `json:"nodes"`
+}
+
+// ReportSummaryResponse is a backlog report with tasks grouped by status, epic, or assignee.
+type ReportSummaryResponse struct {
+	Project     string        `json:"project"`
+	GroupBy     string        `json:"group_by"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Total       int           `json:"total"`
+	Groups      []ReportGroup `json:"groups"`
+}
+
+// ReportGroup is one heading of a summary report.
+type ReportGroup struct {
+	Key   string       `json:"key"`
+	Title string       `json:"title"`
+	Count int          `json:"count"`
+	Tasks []ReportTask `json:"tasks"`
+}
+
+// ReportTask is one task line of a summary report. URL is set when reports.task_url is configured.
+type ReportTask struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Type     string `json:"type"`
+	Priority int    `json:"priority"`
+	Assignee string `json:"assignee,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
 // CleanupRequest defines the payload for admin cleanup.
 type CleanupRequest struct {
 	OlderThanDays int    `json:"older_than_days"`
@@ -114,11 +264,134 @@ type CleanupResponse struct {
 	DryRun  bool     `json:"dry_run"`
 }
 
+// RecomputeRequest defines the payload for admin recompute.
+type RecomputeRequest struct {
+	DryRun bool `json:"dry_run"`
+}
+
+// RecomputeResponse is the response from POST /v1/admin/recompute.
+type RecomputeResponse struct {
+	SchemaVersion int            `json:"schema_version"`
+	TaskCounts    map[string]int `json:"task_counts"`
+	TotalTasks    int            `json:"total_tasks"`
+	TaskRows      int            `json:"task_rows"`
+	FTSRows       int            `json:"fts_rows"`
+	FTSMissing    []string       `json:"fts_missing"`
+	FTSOrphaned   []string       `json:"fts_orphaned"`
+	FTSStale      []string       `json:"fts_stale"`
+	Corrected     int            `json:"corrected"`
+	DryRun        bool           `json:"dry_run"`
+}
+
+// MaintenanceRequest is the body of POST /v1/admin/maintenance. The
+// integrity check always runs; Vacuum and RebuildFTS require X-Confirm.
+type MaintenanceRequest struct {
+	Vacuum     bool `json:"vacuum"`
+	RebuildFTS bool `json:"rebuild_fts"`
+}
+
+// MaintenanceResponse is the response from POST /v1/admin/maintenance.
+type MaintenanceResponse struct {
+	IntegrityOK     bool     `json:"integrity_ok"`
+	IntegrityErrors []string `json:"integrity_errors"`
+	FTSRebuilt      bool     `json:"fts_rebuilt"`
+	FTSRows         int      `json:"fts_rows"`
+	Vacuumed        bool     `json:"vacuumed"`
+	SizeBeforeBytes int64    `json:"size_before_bytes"`
+	SizeAfterBytes  int64    `json:"size_after_bytes"`
+	FreePagesBefore int64    `json:"free_pages_before"`
+	FreePagesAfter  int64    `json:"free_pages_after"`
+}
+
+// ReindexRequest is the body of POST /v1/admin/reindex. It requires
+// X-Confirm; a zero BatchSize keeps the server default of 500.
+type ReindexRequest struct {
+	BatchSize int `json:"batch_size,omitempty"`
+}
+
+// Reindex states reported by ReindexStatus.
+const (
+	ReindexStateIdle    = "idle"
+	ReindexStateRunning = "running"
+	ReindexStateDone    = "done"
+	ReindexStateFailed  = "failed"
+)
+
+// ReindexStatus reports the background search index rebuild started by POST
+// /v1/admin/reindex and polled with GET /v1/admin/reindex.
+type ReindexStatus struct {
+	State      string     `json:"state"`
+	Total      int        `json:"total"`
+	Processed  int        `json:"processed"`
+	Orphaned   int        `json:"orphaned"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// MaintenanceModeRequest is the body of POST /v1/admin/maintenance-mode. Mode
+// is reject (default) or queue; zero durations keep the server defaults.
+type MaintenanceModeRequest struct {
+	Enabled             bool   `json:"enabled"`
+	Mode                string `json:"mode,omitempty"`
+	Reason              string `json:"reason,omitempty"`
+	RetryAfterSeconds   int    `json:"retry_after_seconds,omitempty"`
+	QueueTimeoutSeconds int    `json:"queue_timeout_seconds,omitempty"`
+}
+
+// MaintenanceModeResponse reports maintenance mode state. Queued counts writes
+// currently waiting for the mode to be lifted.
+type MaintenanceModeResponse struct {
+	Enabled             bool       `json:"enabled"`
+	Mode                string     `json:"mode,omitempty"`
+	Reason              string     `json:"reason,omitempty"`
+	RetryAfterSeconds   int        `json:"retry_after_seconds,omitempty"`
+	QueueTimeoutSeconds int        `json:"queue_timeout_seconds,omitempty"`
+	Since               *time.Time `json:"since,omitempty"`
+	Queued              int        `json:"queued"`
+}
+
 // TaskCloseRequest defines the payload for closing tasks.
+// Filter closes every open task matching list-style query parameters instead of IDs;
+// the response then has the TaskCloseByFilterResponse shape.
+// PRMerged asserts that linked pull requests have merged, skipping the
+// workflow.require_merged_pr check.
 type TaskCloseRequest struct {
+	IDs      []string          `json:"ids,omitempty"`
+	Commit   string            `json:"commit,omitempty"`
+	Repo     string            `json:"repo,omitempty"`
+	Filter   map[string]string `json:"filter,omitempty"`
+	DryRun   bool              `json:"dry_run,omitempty"`
+	PRMerged bool              `json:"pr_merged,omitempty"`
+}
+
+// TaskCloseByFilterRequest defines the payload for closing all open tasks matching a filter.
+// Filter keys and values mirror the list query parameters (e.g. {"label": "obsolete"}).
+type TaskCloseByFilterRequest struct {
+	Filter   map[string]string `json:"filter"`
+	DryRun   bool              `json:"dry_run,omitempty"`
+	PRMerged bool              `json:"pr_merged,omitempty"`
+}
+
+// TaskCloseByFilterResponse reports the tasks closed (or matched, for dry runs) by a filter.
+type TaskCloseByFilterResponse struct {
 	IDs    []string `json:"ids"`
-	Commit string   `json:"commit,omitempty"`
-	Repo   string   `json:"repo,omitempty"`
+	Count  int      `json:"count"`
+	DryRun bool     `json:"dry_run"`
+}
+
+// TaskBulkUpdateRequest applies one update to tasks named by IDs or matching Filter (exactly one is required).
+// Filter keys and values mirror the list query parameters.
+type TaskBulkUpdateRequest struct {
+	IDs    []string          `json:"ids,omitempty"`
+	Filter map[string]string `json:"filter,omitempty"`
+	Update TaskUpdateRequest `json:"update"`
+}
+
+// TaskBulkUpdateResponse reports the tasks changed by a bulk update.
+type TaskBulkUpdateResponse struct {
+	IDs   []string `json:"ids"`
+	Count int      `json:"count"`
 }
 
 // TaskReopenRequest defines the payload for reopening tasks.
@@ -126,11 +399,157 @@ type TaskReopenRequest struct {
 	IDs []string `json:"ids"`
 }
 
+// TaskRestoreRequest defines the payload for restoring tombstoned tasks.
+type TaskRestoreRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// TaskMergeRequest defines the payload for merging a duplicate task into a canonical one.
+type TaskMergeRequest struct {
+	Into string `json:"into"`
+}
+
+// TaskClaimRequest defines the payload for claiming the next ready task.
+// Actor defaults to the request actor; LeaseSeconds defaults server-side.
+type TaskClaimRequest struct {
+	Actor        string   `json:"actor,omitempty"`
+	Order        string   `json:"order,omitempty"`
+	Types        []string `json:"types,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	MaxPriority  *int     `json:"max_priority,omitempty"`
+	LeaseSeconds int      `json:"lease_seconds,omitempty"`
+}
+
+// TaskUpsertResponse is the response from PUT /v1/tasks/{id}.
+type TaskUpsertResponse struct {
+	Created bool         `json:"created"`
+	Task    TaskResponse `json:"task"`
+}
+
+// TaskClaimResponse is the claimed task and its lease.
+type TaskClaimResponse struct {
+	Task  TaskResponse     `json:"task"`
+	Lease models.TaskLease `json:"lease"`
+}
+
+// TaskTouchRequest defines the payload for touching tasks.
+type TaskTouchRequest struct {
+	IDs   []string `json:"ids"`
+	Actor string   `json:"actor,omitempty"`
+}
+
+// SavedFilterRequest defines the payload for creating or replacing a saved filter.
+// Filter holds list query parameters; Name is ignored on replace.
+type SavedFilterRequest struct {
+	Name   string            `json:"name,omitempty"`
+	Filter map[string]string `json:"filter"`
+}
+
+// TaskWorklogCreateRequest defines the payload for logging time on a task.
+// Actor defaults to the request actor; StartedAt defaults to now minus Seconds.
+type TaskWorklogCreateRequest struct {
+	Actor     string     `json:"actor,omitempty"`
+	Seconds   int64      `json:"seconds"`
+	Note      string     `json:"note,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+}
+
+// MilestoneCreateRequest defines the payload for creating a milestone. DueDate is YYYY-MM-DD.
+type MilestoneCreateRequest struct {
+	Title   string `json:"title"`
+	DueDate string `json:"due_date,omitempty"`
+	State   string `json:"state,omitempty"`
+}
+
+// MilestoneUpdateRequest defines the payload for updating a milestone. An empty DueDate clears it.
+type MilestoneUpdateRequest struct {
+	Title   *string `json:"title,omitempty"`
+	DueDate *string `json:"due_date,omitempty"`
+	State   *string `json:"state,omitempty"`
+}
+
+// MilestoneResponse is one milestone. Progress is set on single-milestone reads.
+type MilestoneResponse struct {
+	models.Milestone
+	Progress *MilestoneProgress `json:"progress,omitempty"`
+}
+
+// MilestoneProgress holds burndown counts for a milestone's tasks.
+// Tombstoned tasks are excluded from Total; Remaining is Total minus Closed.
+type MilestoneProgress struct {
+	Total     int            `json:"total"`
+	Closed    int            `json:"closed"`
+	Remaining int            `json:"remaining"`
+	ByStatus  map[string]int `json:"by_status"`
+}
+
+// ChildrenSummary rolls up a task's children (tasks whose parent_id names it).
+// Tombstoned children are not counted. PercentComplete is Closed/Total rounded down.
+type ChildrenSummary struct {
+	Total           int            `json:"total"`
+	Closed          int            `json:"closed"`
+	PercentComplete int            `json:"percent_complete"`
+	ByStatus        map[string]int `json:"by_status"`
+}
+
+// TaskGitSummary rolls up a task's git refs. Branches are the refs to branch
+// objects; a branch is merged once the task has a merged_by ref in its repo.
+type TaskGitSummary struct {
+	Total      int               `json:"total"`
+	ByRelation map[string]int    `json:"by_relation"`
+	Branches   []GitBranchStatus `json:"branches,omitempty"`
+	Commits    []string          `json:"commits,omitempty"`
+}
+
+// GitBranchStatus is one branch linked to a task. Status is "open" or "merged".
+type GitBranchStatus struct {
+	Repo     string `json:"repo"`
+	Branch   string `json:"branch"`
+	Relation string `json:"relation"`
+	Status   string `json:"status"`
+	MergedBy string `json:"merged_by,omitempty"`
+}
+
+// TaskCommentCreateRequest defines the payload for adding a comment to a task.
+type TaskCommentCreateRequest struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+}
+
 // LabelsRequest defines label add/remove payloads.
 type LabelsRequest struct {
 	Labels []string `json:"labels"`
 }
 
+// LabelRenameRequest defines the payload for POST /v1/admin/labels/rename.
+// An empty Project renames the label in every project.
+type LabelRenameRequest struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Project string `json:"project,omitempty"`
+}
+
+// LabelRenameResponse is the response from POST /v1/admin/labels/rename.
+type LabelRenameResponse struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	TaskIDs []string `json:"task_ids"`
+	Count   int      `json:"count"`
+}
+
+// LabelDeleteResponse is the response from DELETE /v1/labels/{label}.
+type LabelDeleteResponse struct {
+	Label   string   `json:"label"`
+	TaskIDs []string `json:"task_ids"`
+	Count   int      `json:"count"`
+}
+
+// RelatedLabelResponse is one label that co-occurs with a queried label.
+type RelatedLabelResponse struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
 // DepCreateRequest defines dependency creation payload.
 type DepCreateRequest struct {
 	ChildID  string `json:"child_id"`
@@ -143,6 +562,50 @@ type TaskResponse struct {
 	models.Task
 	Labels []string            `json:"labels"`
 	Deps   []models.Dependency `json:"deps,omitempty"`
+
+	// Dependents lists child task ids and is set only when requested via include=dependents.
+	Dependents []string `json:"dependents,omitempty"`
+
+	// Comments lists the task's discussion thread and is set only when requested via include=comments.
+	Comments []models.TaskComment `json:"comments,omitempty"`
+
+	// Watchers lists users subscribed to the task and is set only when requested via include=watchers.
+	Watchers []string `json:"watchers,omitempty"`
+
+	// GitSummary is set only when requested via include=git, on tasks with git refs.
+	GitSummary *TaskGitSummary `json:"git_summary,omitempty"`
+
+	// ChildrenSummary is set on epics and on any task with children.
+	ChildrenSummary *ChildrenSummary `json:"children_summary,omitempty"`
+
+	// TotalTimeSeconds sums the task's worklog entries.
+	TotalTimeSeconds int64 `json:"total_time_seconds,omitempty"`
+
+	// Readiness fields are set only when requested via include=readiness.
+	IsReady      *bool `json:"is_ready,omitempty"`
+	IsBlocked    *bool `json:"is_blocked,omitempty"`
+	OpenBlockers *int  `json:"open_blockers,omitempty"`
+
+	// Search fields are set only on list results filtered by search. Snippet marks
+	// matched terms with [ and ]; lower Rank is a better match.
+	Snippet string   `json:"snippet,omitempty"`
+	Rank    *float64 `json:"rank,omitempty"`
+
+	// Warnings lists values coerced by lenient validation on create.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Attachments and GitRefs are set only on export records, so an import
+	// elsewhere can recreate them.
+	Attachments []AttachmentRecord  `json:"attachments,omitempty"`
+	GitRefs     []models.TaskGitRef `json:"git_refs,omitempty"`
+}
+
+// AttachmentRecord is an attachment as carried by export and import records.
+// Managed blobs are identified by SHA256 so they can be matched on another server.
+type AttachmentRecord struct {
+	models.Attachment
+	SHA256    string `json:"sha256,omitempty"`
+	SizeBytes int64  `json:"size_bytes,omitempty"`
 }
 
 // TaskGetManyRequest defines payload for bulk task retrieval.
@@ -153,8 +616,10 @@ type TaskGetManyRequest struct {
 // TaskImportRecord represents one task in an import payload.
 type TaskImportRecord struct {
 	models.Task
-	Labels []string            `json:"labels"`
-	Deps   []models.Dependency `json:"deps"`
+	Labels      []string            `json:"labels"`
+	Deps        []models.Dependency `json:"deps"`
+	Attachments []AttachmentRecord  `json:"attachments,omitempty"`
+	GitRefs     []models.TaskGitRef `json:"git_refs,omitempty"`
 }
 
 // ImportRequest is the payload for POST /v1/import.
@@ -164,6 +629,10 @@ type ImportRequest struct {
 	Dedupe         string             `json:"dedupe"`
 	OrphanHandling string             `json:"orphan_handling"`
 	Atomic         bool               `json:"atomic,omitempty"`
+	Lenient        bool               `json:"lenient,omitempty"`
+	SourceLabel    string             `json:"source_label,omitempty"`
+	SaveReport     bool               `json:"save_report,omitempty"`
+	RemapIDs       bool               `json:"remap_ids,omitempty"`
 }
 
 // ImportResponse is the response from POST /v1/import.
@@ -175,6 +644,149 @@ type ImportResponse struct {
 	DryRun        bool     `json:"dry_run"`
 	TaskIDs       []string `json:"task_ids"`
 	Messages      []string `json:"messages,omitempty"`
+	Warnings      []string `json:"warnings,omitempty"`
 	ApplyMode     string   `json:"apply_mode,omitempty"`
 	AppliedChunks int      `json:"applied_chunks,omitempty"`
+
+	// RolledBack is set when an atomic import hit record errors and applied nothing.
+	RolledBack bool `json:"rolled_back,omitempty"`
+
+	// Conflicts lists rejected records; ReportID is set when they were saved via save_report.
+	Conflicts []models.ImportConflict `json:"conflicts,omitempty"`
+	ReportID  int64                   `json:"report_id,omitempty"`
+
+	// IDMap maps each record's original id to its new id when remap_ids is set.
+	IDMap map[string]string `json:"id_map,omitempty"`
+
+	AttachmentsCreated int `json:"attachments_created,omitempty"`
+	GitRefsCreated     int `json:"git_refs_created,omitempty"`
+}
+
+// CustomFieldRequest defines the payload for creating or replacing a custom
+// field definition. Values, when set, restricts a string field to an enum.
+type CustomFieldRequest struct {
+	Type     string   `json:"type"`
+	Required bool     `json:"required,omitempty"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// StaleWebhookPayload is POSTed to stale.webhook_url after an escalation run
+// that found stale tasks in a project. Tasks are reported as they were before
+// the run's other actions were applied.
+type StaleWebhookPayload struct {
+	Event   string          `json:"event"`
+	Project string          `json:"project"`
+	Actions []string        `json:"actions"`
+	RunAt   time.Time       `json:"run_at"`
+	Tasks   []StaleTaskInfo `json:"tasks"`
+}
+
+// StaleTaskInfo describes one escalated task.
+type StaleTaskInfo struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	Priority  int       `json:"priority"`
+	Assignee  string    `json:"assignee,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	StaleDays int       `json:"stale_days"`
+}
+
+// StatsResponse reports task throughput from GET /v1/stats. Project is empty
+// when the stats span all projects.
+type StatsResponse struct {
+	Project     string           `json:"project,omitempty"`
+	From        *time.Time       `json:"from,omitempty"`
+	To          *time.Time       `json:"to,omitempty"`
+	Interval    string           `json:"interval"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Created     []StatsBucket    `json:"created"`
+	Closed      []StatsBucket    `json:"closed"`
+	TimeToClose StatsTimeToClose `json:"time_to_close"`
+	OpenAge     []StatsAgeBucket `json:"open_age"`
+	ByType      map[string]int   `json:"by_type"`
+	ByAssignee  map[string]int   `json:"by_assignee"`
+	ByLabel     map[string]int   `json:"by_label"`
+}
+
+// StatsBucket counts tasks in the day or week starting at Start (YYYY-MM-DD, UTC).
+type StatsBucket struct {
+	Start string `json:"start"`
+	Count int    `json:"count"`
+}
+
+// StatsTimeToClose summarizes how long tasks closed in the range stayed open.
+type StatsTimeToClose struct {
+	Count        int     `json:"count"`
+	AverageHours float64 `json:"average_hours"`
+}
+
+// StatsAgeBucket counts open tasks created between MinDays and MaxDays ago;
+// MaxDays is omitted for the last, open-ended bucket.
+type StatsAgeBucket struct {
+	Label   string `json:"label"`
+	MinDays int    `json:"min_days"`
+	MaxDays *int   `json:"max_days,omitempty"`
+	Count   int    `json:"count"`
+}
+
+// FlowResponse is the cumulative flow series from GET /v1/stats/flow: one entry
+// per day in the window, oldest first, with task counts per status.
+type FlowResponse struct {
+	Project    string    `json:"project,omitempty"`
+	WindowDays int       `json:"window_days"`
+	Statuses   []string  `json:"statuses"`
+	Days       []FlowDay `json:"days"`
+}
+
+// FlowDay holds the task counts per status at the end of Date (YYYY-MM-DD, UTC).
+// The current day reflects live counts.
+type FlowDay struct {
+	Date   string         `json:"date"`
+	Counts map[string]int `json:"counts"`
+}
+
+// RPC batch operation names accepted by POST /v1/rpc.
+const (
+	RPCOpCreate   = "create"
+	RPCOpUpdate   = "update"
+	RPCOpClose    = "close"
+	RPCOpAddLabel = "add_label"
+	RPCOpAddDep   = "add_dep"
+)
+
+// RPCRequest is the payload for POST /v1/rpc. Ops run in order in one
+// transaction; if any op fails none of them are applied.
+type RPCRequest struct {
+	Ops []RPCOp `json:"ops"`
+}
+
+// RPCOp is one named operation in an RPC batch. ID names the target task for
+// update, close, add_label, and add_dep (the child); Task is the create payload,
+// Update the update payload, Labels the add_label payload, and ParentID/DepType
+// the add_dep edge. Ops may refer to tasks created earlier in the same batch.
+type RPCOp struct {
+	Op       string             `json:"op"`
+	ID       string             `json:"id,omitempty"`
+	Task     *TaskCreateRequest `json:"task,omitempty"`
+	Update   *TaskUpdateRequest `json:"update,omitempty"`
+	Labels   []string           `json:"labels,omitempty"`
+	ParentID string             `json:"parent_id,omitempty"`
+	DepType  string             `json:"dep_type,omitempty"`
+}
+
+// RPCResponse is the response from POST /v1/rpc, with one result per op.
+type RPCResponse struct {
+	Results []RPCResult `json:"results"`
+}
+
+// RPCResult reports one applied op. Task holds the task as of the end of the
+// batch for create, update, and close; Labels holds the task's label set after
+// add_label.
+type RPCResult struct {
+	Index  int           `json:"index"`
+	Op     string        `json:"op"`
+	ID     string        `json:"id"`
+	Task   *TaskResponse `json:"task,omitempty"`
+	Labels []string      `json:"labels,omitempty"`
 }
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	unixSocketScheme = "unix://"
+	// unixSocketBaseURL is the placeholder request URL for socket clients; the
+	// host is ignored because the transport always dials the socket.
+	unixSocketBaseURL = "http://unix"
+)
+
+// unixSocketPath returns the socket path for a "unix://" API URL.
+func unixSocketPath(apiURL string) (string, bool) {
+	apiURL = strings.TrimSpace(apiURL)
+	if !strings.HasPrefix(apiURL, unixSocketScheme) {
+		return "", false
+	}
+	return strings.TrimPrefix(apiURL, unixSocketScheme), true
+}
+
+func unixSocketTransport(socketPath string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+	return transport
+}
@@ -12,9 +12,10 @@ type BlobPutResult struct {
 	BlobKey   string
 }
 
-// BlobStore is the byte-storage abstraction used by AttachmentService.
+// BlobStore is the byte-storage abstraction used by AttachmentService. Open
+// returns a seekable reader so content can be served in ranges.
 type BlobStore interface {
 	Put(ctx context.Context, r io.Reader) (BlobPutResult, error)
-	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
 	Delete(ctx context.Context, key string) error
 }
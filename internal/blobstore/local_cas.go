@@ -102,8 +102,8 @@ func (c *LocalCAS) Put(ctx context.Context, r io.Reader) (BlobPutResult, error)
 	return BlobPutResult{SHA256: digest, SizeBytes: n, BlobKey: key}, nil
 }
 
-// Open returns a reader for blob key content.
-func (c *LocalCAS) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+// Open returns a seekable reader for blob key content.
+func (c *LocalCAS) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
 	if c == nil {
 		return nil, fmt.Errorf("blob store is not configured")
 	}
@@ -3,11 +3,14 @@ package config
 import (
 	"fmt"
 	"mime"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -22,6 +25,22 @@ const (
 	DefaultAttachmentMultipartMemory int64 = 8 * 1024 * 1024
 	DefaultAttachmentRejectMismatch        = true
 	DefaultAttachmentGCBatchSize           = 500
+	DefaultAttachmentScanAction            = "reject"
+	DefaultWIPLimitsPerAssignee            = true
+	DefaultMaxDescriptionBytes             = 256 * 1024
+	DefaultMaxNotesBytes                   = 256 * 1024
+	DefaultCreateMaxLabels                 = 100
+	DefaultCreateMaxDeps                   = 100
+	DefaultCloseMaxByFilter                = 100
+	DefaultUndoWindow                      = "1h"
+	DefaultReportLimit                     = 50
+	DefaultReportMaxLimit                  = 500
+	DefaultDBBusyTimeoutMS                 = 5000
+	DefaultDBJournalMode                   = "wal"
+	DefaultDBQueryTimeout                  = "30s"
+	DefaultGitHubAPIURL                    = "https://api.github.com"
+	DefaultGitLabAPIURL                    = "https://gitlab.com/api/v4"
+	DefaultStaleLabel                      = "stale"
 
 	configDirEnvKey          = "GRNS_CONFIG_DIR"
 	trustProjectConfigEnvKey = "GRNS_TRUST_PROJECT_CONFIG"
@@ -40,15 +59,261 @@ type AttachmentConfig struct {
 	AllowedMediaTypes       []string `toml:"allowed_media_types"`
 	RejectMediaTypeMismatch bool     `toml:"reject_media_type_mismatch"`
 	GCBatchSize             int      `toml:"gc_batch_size"`
+	// ScanCommand is run with each upload on stdin; exit 1 flags the content.
+	ScanCommand string `toml:"scan_command"`
+	// ScanURL receives each upload as a POST when ScanCommand is empty.
+	ScanURL string `toml:"scan_url"`
+	// ScanAction is reject or quarantine.
+	ScanAction string `toml:"scan_action"`
 }
 
+// FieldsConfig defines size limits for free-text task fields.
+type FieldsConfig struct {
+	MaxDescriptionBytes int `toml:"max_description_bytes"`
+	MaxNotesBytes       int `toml:"max_notes_bytes"`
+}
+
+// CreateConfig defines per-request caps on task create payloads.
+type CreateConfig struct {
+	MaxLabels int `toml:"max_labels"`
+	MaxDeps   int `toml:"max_deps"`
+}
+
+// CloseConfig defines caps on bulk close operations.
+type CloseConfig struct {
+	MaxByFilter int `toml:"max_by_filter"`
+}
+
+// UndoConfig defines how long journaled operations stay undoable.
+type UndoConfig struct {
+	// Window is a Go duration such as "1h".
+	Window string `toml:"window"`
+}
+
+// MinUndoWindow is the shortest accepted undo.window.
+const MinUndoWindow = time.Minute
+
+// WindowDuration returns the parsed undo window, falling back to the default.
+func (u UndoConfig) WindowDuration() time.Duration {
+	parsed, err := parseUndoWindow(u.Window)
+	if err != nil || parsed == 0 {
+		parsed, _ = parseUndoWindow(DefaultUndoWindow)
+	}
+	return parsed
+}
+
+func parseUndoWindow(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < MinUndoWindow {
+		return 0, fmt.Errorf("undo.window must be a duration of at least %s (e.g. 1h)", MinUndoWindow)
+	}
+	return parsed, nil
+}
+
+// StaleConfig defines the background job that escalates stale tasks.
+type StaleConfig struct {
+	// Interval is a Go duration such as "6h"; empty disables the job.
+	Interval string `toml:"interval"`
+	// Thresholds maps a status to the days without an update after which its
+	// tasks are stale. Statuses without a threshold are never escalated.
+	Thresholds map[string]int `toml:"thresholds"`
+	// Actions lists what happens to stale tasks; see StaleActions.
+	Actions    []string `toml:"actions"`
+	Label      string   `toml:"label"`
+	WebhookURL string   `toml:"webhook_url"`
+}
+
+// MinStaleInterval is the shortest accepted stale.interval.
+const MinStaleInterval = time.Minute
+
+// StaleActions lists the accepted stale.actions values.
+var StaleActions = []string{"label", "bump_priority", "webhook", "defer"}
+
+// IntervalDuration returns the parsed escalation interval, or 0 when unset.
+func (s StaleConfig) IntervalDuration() time.Duration {
+	parsed, err := parseStaleInterval(s.Interval)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
+func parseStaleInterval(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < MinStaleInterval {
+		return 0, fmt.Errorf("stale.interval must be a duration of at least %s (e.g. 6h)", MinStaleInterval)
+	}
+	return parsed, nil
+}
+
+func normalizeStaleActions(values []string) ([]string, error) {
+	actions := normalizeStatusList(values)
+	for _, action := range actions {
+		if !slices.Contains(StaleActions, action) {
+			return nil, fmt.Errorf("stale.actions must be some of: %s", strings.Join(StaleActions, ", "))
+		}
+	}
+	return actions, nil
+}
+
+// ReportsConfig defines pagination defaults for aggregate report endpoints and
+// how summary reports render.
+type ReportsConfig struct {
+	DefaultLimit     int    `toml:"default_limit"`
+	MaxLimit         int    `toml:"max_limit"`
+	TaskURL          string `toml:"task_url"`
+	MarkdownTemplate string `toml:"markdown_template"`
+}
+
+// WorkflowConfig defines opt-in task workflow rules.
+type WorkflowConfig struct {
+	AutoCloseParents     bool `toml:"auto_close_parents"`
+	RequireBlockedReason bool `toml:"require_blocked_reason"`
+	RequireMergedPR      bool `toml:"require_merged_pr"`
+	// WIPLimit caps in_progress tasks per assignee; 0 disables the cap.
+	WIPLimit int `toml:"wip_limit"`
+}
+
+// DBJournalModes lists the accepted db.journal_mode values.
+var DBJournalModes = []string{"wal", "delete", "truncate", "persist"}
+
+// DBConfig defines the database backend and SQLite connection tuning.
+type DBConfig struct {
+	// URL is a postgres:// connection string; when set the server uses
+	// PostgreSQL instead of the SQLite file at db_path.
+	URL           string `toml:"url"`
+	BusyTimeoutMS int    `toml:"busy_timeout_ms"`
+	JournalMode   string `toml:"journal_mode"`
+	// QueryTimeout is a Go duration bounding each SQL statement; "0" disables it.
+	QueryTimeout string `toml:"query_timeout"`
+}
+
+// QueryTimeoutDuration returns the parsed query timeout, or 0 when disabled.
+func (d DBConfig) QueryTimeoutDuration() time.Duration {
+	parsed, err := parseDBQueryTimeout(d.QueryTimeout)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
+func parseDBQueryTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		value = DefaultDBQueryTimeout
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("db.query_timeout must be a non-negative duration (e.g. 30s, or 0 to disable)")
+	}
+	return parsed, nil
+}
+
+// BackupConfig defines where server-side backups are written and how often
+// the server takes them on its own.
+type BackupConfig struct {
+	Dir string `toml:"dir"`
+	// Interval is a Go duration such as "24h"; empty disables scheduling.
+	Interval string `toml:"interval"`
+	// KeepLast caps how many snapshots stay in Dir; 0 keeps all.
+	KeepLast int `toml:"keep_last"`
+}
+
+// MinBackupInterval is the shortest accepted backup.interval.
+const MinBackupInterval = time.Minute
+
+// IntervalDuration returns the parsed backup interval, or 0 when unset.
+func (b BackupConfig) IntervalDuration() time.Duration {
+	parsed, err := parseBackupInterval(b.Interval)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
+func parseBackupInterval(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < MinBackupInterval {
+		return 0, fmt.Errorf("backup.interval must be a duration of at least %s (e.g. 24h)", MinBackupInterval)
+	}
+	return parsed, nil
+}
+
+// ServerConfig defines how `grns srv` listens.
+type ServerConfig struct {
+	// Listen overrides the address derived from api_url, e.g.
+	// "127.0.0.1:7333" or "unix:///run/grns.sock".
+	Listen string `toml:"listen"`
+	// Embedded makes the CLI talk to a managed server on a unix socket next
+	// to the database, started on first use.
+	Embedded bool `toml:"embedded"`
+	// GRPCListen serves the gRPC API on this address as well, e.g.
+	// "127.0.0.1:7334" or "unix:///run/grns-grpc.sock". Empty disables it.
+	GRPCListen string `toml:"grpc_listen"`
+}
+
+// ImportConfig defines defaults applied to task imports.
+type ImportConfig struct {
+	SourceLabel string `toml:"source_label"`
+}
+
+// GitConfig defines the hosting APIs used to resolve git refs. Tokens are read
+// from the environment, not from config files.
+type GitConfig struct {
+	GitHubAPIURL string `toml:"github_api_url"`
+	GitLabAPIURL string `toml:"gitlab_api_url"`
+}
+
+// ResponsesConfig defines server-wide defaults for task response payloads.
+type ResponsesConfig struct {
+	DefaultIncludes ResponseIncludesConfig `toml:"default_includes"`
+}
+
+// ResponseIncludesConfig lists the include sections applied per endpoint when a request omits ?include=.
+type ResponseIncludesConfig struct {
+	List []string `toml:"list"`
+	Get  []string `toml:"get"`
+}
+
+// ResponseIncludeSections lists the supported include section names.
+var ResponseIncludeSections = []string{"deps", "dependents", "readiness", "comments", "watchers", "git"}
+
 // Config defines runtime configuration for grns.
 type Config struct {
 	ProjectPrefix            string            `toml:"project_prefix"`
 	APIURL                   string            `toml:"api_url"`
 	DBPath                   string            `toml:"db_path"`
 	LogLevel                 string            `toml:"log_level"`
+	DB                       DBConfig          `toml:"db"`
+	Server                   ServerConfig      `toml:"server"`
+	Backup                   BackupConfig      `toml:"backup"`
 	Attachments              AttachmentConfig  `toml:"attachments"`
+	Fields                   FieldsConfig      `toml:"fields"`
+	Create                   CreateConfig      `toml:"create"`
+	Close                    CloseConfig       `toml:"close"`
+	Undo                     UndoConfig        `toml:"undo"`
+	Reports                  ReportsConfig     `toml:"reports"`
+	Responses                ResponsesConfig   `toml:"responses"`
+	Import                   ImportConfig      `toml:"import"`
+	Git                      GitConfig         `toml:"git"`
+	Workflow                 WorkflowConfig    `toml:"workflow"`
+	Stale                    StaleConfig       `toml:"stale"`
+	WIPLimits                map[string]int    `toml:"wip_limits"`
+	WIPLimitsPerAssignee     bool              `toml:"wip_limits_per_assignee"`
+	ParentImpliesBlocks      bool              `toml:"parent_implies_blocks"`
+	RequireAssigneeStatuses  []string          `toml:"require_assignee_for_statuses"`
 	TrustedProjectConfigPath string            `toml:"-"`
 	ValueSources             map[string]string `toml:"-"`
 	LoadedConfigPaths        []string          `toml:"-"`
@@ -57,10 +322,15 @@ type Config struct {
 // Default returns default configuration values.
 func Default() Config {
 	return Config{
-		ProjectPrefix:     DefaultProjectPrefix,
-		APIURL:            DefaultAPIURL,
-		DBPath:            "",
-		LogLevel:          DefaultLogLevel,
+		ProjectPrefix: DefaultProjectPrefix,
+		APIURL:        DefaultAPIURL,
+		DBPath:        "",
+		LogLevel:      DefaultLogLevel,
+		DB: DBConfig{
+			BusyTimeoutMS: DefaultDBBusyTimeoutMS,
+			JournalMode:   DefaultDBJournalMode,
+			QueryTimeout:  DefaultDBQueryTimeout,
+		},
 		ValueSources:      defaultValueSources(),
 		LoadedConfigPaths: nil,
 		Attachments: AttachmentConfig{
@@ -69,7 +339,44 @@ func Default() Config {
 			AllowedMediaTypes:       nil,
 			RejectMediaTypeMismatch: DefaultAttachmentRejectMismatch,
 			GCBatchSize:             DefaultAttachmentGCBatchSize,
+			ScanAction:              DefaultAttachmentScanAction,
+		},
+		Fields: FieldsConfig{
+			MaxDescriptionBytes: DefaultMaxDescriptionBytes,
+			MaxNotesBytes:       DefaultMaxNotesBytes,
+		},
+		Create: CreateConfig{
+			MaxLabels: DefaultCreateMaxLabels,
+			MaxDeps:   DefaultCreateMaxDeps,
+		},
+		Close: CloseConfig{
+			MaxByFilter: DefaultCloseMaxByFilter,
 		},
+		Undo: UndoConfig{
+			Window: DefaultUndoWindow,
+		},
+		Reports: ReportsConfig{
+			DefaultLimit: DefaultReportLimit,
+			MaxLimit:     DefaultReportMaxLimit,
+		},
+		Responses: ResponsesConfig{
+			DefaultIncludes: ResponseIncludesConfig{
+				List: nil,
+				Get:  []string{"deps", "watchers", "git"},
+			},
+		},
+		Git: GitConfig{
+			GitHubAPIURL: DefaultGitHubAPIURL,
+			GitLabAPIURL: DefaultGitLabAPIURL,
+		},
+		Stale: StaleConfig{
+			Actions: []string{"label"},
+			Label:   DefaultStaleLabel,
+		},
+		WIPLimits:               nil,
+		WIPLimitsPerAssignee:    DefaultWIPLimitsPerAssignee,
+		ParentImpliesBlocks:     false,
+		RequireAssigneeStatuses: nil,
 	}
 }
 
@@ -188,11 +495,52 @@ var allowedKeys = []string{
 	"api_url",
 	"db_path",
 	"log_level",
+	"db.url",
+	"db.busy_timeout_ms",
+	"db.journal_mode",
+	"db.query_timeout",
+	"server.listen",
+	"server.embedded",
+	"server.grpc_listen",
+	"backup.dir",
+	"backup.interval",
+	"backup.keep_last",
 	"attachments.max_upload_bytes",
 	"attachments.multipart_max_memory",
 	"attachments.allowed_media_types",
 	"attachments.reject_media_type_mismatch",
 	"attachments.gc_batch_size",
+	"attachments.scan_command",
+	"attachments.scan_url",
+	"attachments.scan_action",
+	"fields.max_description_bytes",
+	"fields.max_notes_bytes",
+	"create.max_labels",
+	"create.max_deps",
+	"close.max_by_filter",
+	"undo.window",
+	"reports.default_limit",
+	"reports.max_limit",
+	"reports.task_url",
+	"reports.markdown_template",
+	"responses.default_includes.list",
+	"responses.default_includes.get",
+	"import.source_label",
+	"git.github_api_url",
+	"git.gitlab_api_url",
+	"wip_limits",
+	"wip_limits_per_assignee",
+	"parent_implies_blocks",
+	"require_assignee_for_statuses",
+	"workflow.auto_close_parents",
+	"workflow.wip_limit",
+	"workflow.require_blocked_reason",
+	"workflow.require_merged_pr",
+	"stale.interval",
+	"stale.thresholds",
+	"stale.actions",
+	"stale.label",
+	"stale.webhook_url",
 }
 
 func defaultValueSources() map[string]string {
@@ -286,6 +634,26 @@ func (c *Config) Get(key string) (string, error) {
 		return c.DBPath, nil
 	case "log_level":
 		return c.LogLevel, nil
+	case "db.url":
+		return c.DB.URL, nil
+	case "db.busy_timeout_ms":
+		return strconv.Itoa(c.DB.BusyTimeoutMS), nil
+	case "db.journal_mode":
+		return c.DB.JournalMode, nil
+	case "db.query_timeout":
+		return c.DB.QueryTimeout, nil
+	case "server.listen":
+		return c.Server.Listen, nil
+	case "server.embedded":
+		return strconv.FormatBool(c.Server.Embedded), nil
+	case "server.grpc_listen":
+		return c.Server.GRPCListen, nil
+	case "backup.dir":
+		return c.Backup.Dir, nil
+	case "backup.interval":
+		return c.Backup.Interval, nil
+	case "backup.keep_last":
+		return strconv.Itoa(c.Backup.KeepLast), nil
 	case "attachments.max_upload_bytes":
 		return strconv.FormatInt(c.Attachments.MaxUploadBytes, 10), nil
 	case "attachments.multipart_max_memory":
@@ -296,6 +664,68 @@ func (c *Config) Get(key string) (string, error) {
 		return strconv.FormatBool(c.Attachments.RejectMediaTypeMismatch), nil
 	case "attachments.gc_batch_size":
 		return strconv.Itoa(c.Attachments.GCBatchSize), nil
+	case "attachments.scan_command":
+		return c.Attachments.ScanCommand, nil
+	case "attachments.scan_url":
+		return c.Attachments.ScanURL, nil
+	case "attachments.scan_action":
+		return c.Attachments.ScanAction, nil
+	case "fields.max_description_bytes":
+		return strconv.Itoa(c.Fields.MaxDescriptionBytes), nil
+	case "fields.max_notes_bytes":
+		return strconv.Itoa(c.Fields.MaxNotesBytes), nil
+	case "create.max_labels":
+		return strconv.Itoa(c.Create.MaxLabels), nil
+	case "create.max_deps":
+		return strconv.Itoa(c.Create.MaxDeps), nil
+	case "close.max_by_filter":
+		return strconv.Itoa(c.Close.MaxByFilter), nil
+	case "undo.window":
+		return c.Undo.Window, nil
+	case "reports.default_limit":
+		return strconv.Itoa(c.Reports.DefaultLimit), nil
+	case "reports.max_limit":
+		return strconv.Itoa(c.Reports.MaxLimit), nil
+	case "reports.task_url":
+		return c.Reports.TaskURL, nil
+	case "reports.markdown_template":
+		return c.Reports.MarkdownTemplate, nil
+	case "responses.default_includes.list":
+		return strings.Join(c.Responses.DefaultIncludes.List, ","), nil
+	case "responses.default_includes.get":
+		return strings.Join(c.Responses.DefaultIncludes.Get, ","), nil
+	case "import.source_label":
+		return c.Import.SourceLabel, nil
+	case "git.github_api_url":
+		return c.Git.GitHubAPIURL, nil
+	case "git.gitlab_api_url":
+		return c.Git.GitLabAPIURL, nil
+	case "wip_limits":
+		return FormatWIPLimits(c.WIPLimits), nil
+	case "wip_limits_per_assignee":
+		return strconv.FormatBool(c.WIPLimitsPerAssignee), nil
+	case "parent_implies_blocks":
+		return strconv.FormatBool(c.ParentImpliesBlocks), nil
+	case "require_assignee_for_statuses":
+		return strings.Join(c.RequireAssigneeStatuses, ","), nil
+	case "workflow.auto_close_parents":
+		return strconv.FormatBool(c.Workflow.AutoCloseParents), nil
+	case "workflow.wip_limit":
+		return strconv.Itoa(c.Workflow.WIPLimit), nil
+	case "workflow.require_blocked_reason":
+		return strconv.FormatBool(c.Workflow.RequireBlockedReason), nil
+	case "workflow.require_merged_pr":
+		return strconv.FormatBool(c.Workflow.RequireMergedPR), nil
+	case "stale.interval":
+		return c.Stale.Interval, nil
+	case "stale.thresholds":
+		return FormatStaleThresholds(c.Stale.Thresholds), nil
+	case "stale.actions":
+		return strings.Join(c.Stale.Actions, ","), nil
+	case "stale.label":
+		return c.Stale.Label, nil
+	case "stale.webhook_url":
+		return c.Stale.WebhookURL, nil
 	default:
 		return "", fmt.Errorf("unknown key: %s", key)
 	}
@@ -452,6 +882,10 @@ func Load() (*Config, error) {
 		cfg.DBPath = dbPath
 		cfg.setSource("db_path", "env:GRNS_DB")
 	}
+	if dbURL := strings.TrimSpace(os.Getenv("GRNS_DB_URL")); dbURL != "" {
+		cfg.DB.URL = dbURL
+		cfg.setSource("db.url", "env:GRNS_DB_URL")
+	}
 
 	if raw := strings.TrimSpace(os.Getenv(attachmentAllowedMediaTypesEnvKey)); raw != "" {
 		cfg.Attachments.AllowedMediaTypes = splitCSV(raw)
@@ -464,7 +898,39 @@ func Load() (*Config, error) {
 		}
 	}
 
-	cfg.normalizeAttachmentDefaults()
+	if err := cfg.normalizeAttachmentDefaults(); err != nil {
+		return nil, err
+	}
+	cfg.normalizeFieldDefaults()
+	cfg.normalizeReportDefaults()
+	cfg.WIPLimits = normalizeWIPLimits(cfg.WIPLimits)
+	cfg.Workflow.WIPLimit = max(cfg.Workflow.WIPLimit, 0)
+	cfg.RequireAssigneeStatuses = normalizeStatusList(cfg.RequireAssigneeStatuses)
+	if err := cfg.normalizeResponseIncludes(); err != nil {
+		return nil, err
+	}
+	cfg.Import.SourceLabel = strings.ToLower(strings.TrimSpace(cfg.Import.SourceLabel))
+	cfg.Server.Listen = strings.TrimSpace(cfg.Server.Listen)
+	cfg.Server.GRPCListen = strings.TrimSpace(cfg.Server.GRPCListen)
+	cfg.Backup.Dir = strings.TrimSpace(cfg.Backup.Dir)
+	cfg.Backup.Interval = strings.TrimSpace(cfg.Backup.Interval)
+	if _, err := parseBackupInterval(cfg.Backup.Interval); err != nil {
+		return nil, err
+	}
+	cfg.Backup.KeepLast = max(cfg.Backup.KeepLast, 0)
+	if err := cfg.normalizeStaleDefaults(); err != nil {
+		return nil, err
+	}
+	cfg.Undo.Window = strings.TrimSpace(cfg.Undo.Window)
+	if _, err := parseUndoWindow(cfg.Undo.Window); err != nil {
+		return nil, err
+	}
+	if cfg.Undo.Window == "" {
+		cfg.Undo.Window = DefaultUndoWindow
+	}
+	if err := cfg.normalizeDBDefaults(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
@@ -478,13 +944,66 @@ func parseSetValue(key, value string) (any, error) {
 			return nil, fmt.Errorf("%s must be a positive integer", key)
 		}
 		return parsed, nil
-	case "attachments.gc_batch_size":
+	case "attachments.gc_batch_size", "fields.max_description_bytes", "fields.max_notes_bytes", "create.max_labels", "create.max_deps", "close.max_by_filter", "reports.default_limit", "reports.max_limit", "db.busy_timeout_ms":
 		parsed, err := strconv.Atoi(value)
 		if err != nil || parsed <= 0 {
 			return nil, fmt.Errorf("%s must be a positive integer", key)
 		}
 		return parsed, nil
-	case "attachments.reject_media_type_mismatch":
+	case "backup.interval":
+		if _, err := parseBackupInterval(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case "db.query_timeout":
+		if _, err := parseDBQueryTimeout(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case "db.url":
+		if err := validateDBURL(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case "stale.interval":
+		if _, err := parseStaleInterval(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case "stale.actions":
+		actions, err := normalizeStaleActions(splitCSV(value))
+		if err != nil {
+			return nil, err
+		}
+		if actions == nil {
+			return []string{}, nil
+		}
+		return actions, nil
+	case "stale.thresholds":
+		thresholds, err := parseStatusInts("stale.thresholds", "days", value)
+		if err != nil {
+			return nil, err
+		}
+		out := make(map[string]any, len(thresholds))
+		for status, days := range thresholds {
+			out[status] = int64(days)
+		}
+		return out, nil
+	case "undo.window":
+		if value == "" {
+			return nil, fmt.Errorf("undo.window must be a duration of at least %s (e.g. 1h)", MinUndoWindow)
+		}
+		if _, err := parseUndoWindow(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case "workflow.wip_limit", "backup.keep_last":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative integer", key)
+		}
+		return parsed, nil
+	case "attachments.reject_media_type_mismatch", "wip_limits_per_assignee", "parent_implies_blocks", "workflow.auto_close_parents", "workflow.require_blocked_reason", "workflow.require_merged_pr", "server.embedded":
 		parsed, err := strconv.ParseBool(value)
 		if err != nil {
 			return nil, fmt.Errorf("%s must be true or false", key)
@@ -492,6 +1011,31 @@ func parseSetValue(key, value string) (any, error) {
 		return parsed, nil
 	case "attachments.allowed_media_types":
 		return splitCSV(value), nil
+	case "attachments.scan_action":
+		return normalizeAttachmentScanAction(value)
+	case "require_assignee_for_statuses":
+		return normalizeStatusList(splitCSV(value)), nil
+	case "responses.default_includes.list", "responses.default_includes.get":
+		includes, err := normalizeResponseIncludes(key, splitCSV(value))
+		if err != nil {
+			return nil, err
+		}
+		if includes == nil {
+			return []string{}, nil
+		}
+		return includes, nil
+	case "db.journal_mode":
+		return normalizeDBJournalMode(value)
+	case "wip_limits":
+		limits, err := parseWIPLimits(value)
+		if err != nil {
+			return nil, err
+		}
+		out := make(map[string]any, len(limits))
+		for status, max := range limits {
+			out[status] = int64(max)
+		}
+		return out, nil
 	default:
 		return value, nil
 	}
@@ -535,7 +1079,7 @@ func splitCSV(value string) []string {
 	return out
 }
 
-func (c *Config) normalizeAttachmentDefaults() {
+func (c *Config) normalizeAttachmentDefaults() error {
 	if c.Attachments.MaxUploadBytes <= 0 {
 		c.Attachments.MaxUploadBytes = DefaultAttachmentMaxUploadBytes
 	}
@@ -546,6 +1090,127 @@ func (c *Config) normalizeAttachmentDefaults() {
 		c.Attachments.GCBatchSize = DefaultAttachmentGCBatchSize
 	}
 	c.Attachments.AllowedMediaTypes = normalizeConfiguredMediaTypes(c.Attachments.AllowedMediaTypes)
+	action, err := normalizeAttachmentScanAction(c.Attachments.ScanAction)
+	if err != nil {
+		return err
+	}
+	c.Attachments.ScanAction = action
+	return nil
+}
+
+// AttachmentScanActions lists the accepted attachments.scan_action values.
+var AttachmentScanActions = []string{"reject", "quarantine"}
+
+func normalizeAttachmentScanAction(value string) (string, error) {
+	action := strings.ToLower(strings.TrimSpace(value))
+	if action == "" {
+		return DefaultAttachmentScanAction, nil
+	}
+	if !slices.Contains(AttachmentScanActions, action) {
+		return "", fmt.Errorf("attachments.scan_action must be one of: %s", strings.Join(AttachmentScanActions, ", "))
+	}
+	return action, nil
+}
+
+func (c *Config) normalizeFieldDefaults() {
+	if c.Fields.MaxDescriptionBytes <= 0 {
+		c.Fields.MaxDescriptionBytes = DefaultMaxDescriptionBytes
+	}
+	if c.Fields.MaxNotesBytes <= 0 {
+		c.Fields.MaxNotesBytes = DefaultMaxNotesBytes
+	}
+	if c.Create.MaxLabels <= 0 {
+		c.Create.MaxLabels = DefaultCreateMaxLabels
+	}
+	if c.Create.MaxDeps <= 0 {
+		c.Create.MaxDeps = DefaultCreateMaxDeps
+	}
+	if c.Close.MaxByFilter <= 0 {
+		c.Close.MaxByFilter = DefaultCloseMaxByFilter
+	}
+}
+
+func (c *Config) normalizeReportDefaults() {
+	if c.Reports.MaxLimit <= 0 {
+		c.Reports.MaxLimit = DefaultReportMaxLimit
+	}
+	if c.Reports.DefaultLimit <= 0 {
+		c.Reports.DefaultLimit = DefaultReportLimit
+	}
+	if c.Reports.DefaultLimit > c.Reports.MaxLimit {
+		c.Reports.DefaultLimit = c.Reports.MaxLimit
+	}
+}
+
+func (c *Config) normalizeDBDefaults() error {
+	c.DB.URL = strings.TrimSpace(c.DB.URL)
+	if err := validateDBURL(c.DB.URL); err != nil {
+		return err
+	}
+	if c.DB.BusyTimeoutMS <= 0 {
+		c.DB.BusyTimeoutMS = DefaultDBBusyTimeoutMS
+	}
+	mode, err := normalizeDBJournalMode(c.DB.JournalMode)
+	if err != nil {
+		return err
+	}
+	c.DB.JournalMode = mode
+	c.DB.QueryTimeout = strings.TrimSpace(c.DB.QueryTimeout)
+	if c.DB.QueryTimeout == "" {
+		c.DB.QueryTimeout = DefaultDBQueryTimeout
+	}
+	if _, err := parseDBQueryTimeout(c.DB.QueryTimeout); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateDBURL accepts an empty value, which selects SQLite, or a
+// postgres:// or postgresql:// URL.
+func validateDBURL(value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		return fmt.Errorf("db.url must be a postgres:// or postgresql:// URL")
+	}
+	return nil
+}
+
+func normalizeDBJournalMode(value string) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(value))
+	if mode == "" {
+		return DefaultDBJournalMode, nil
+	}
+	if !slices.Contains(DBJournalModes, mode) {
+		return "", fmt.Errorf("db.journal_mode must be one of: %s", strings.Join(DBJournalModes, ", "))
+	}
+	return mode, nil
+}
+
+func (c *Config) normalizeResponseIncludes() error {
+	list, err := normalizeResponseIncludes("responses.default_includes.list", c.Responses.DefaultIncludes.List)
+	if err != nil {
+		return err
+	}
+	get, err := normalizeResponseIncludes("responses.default_includes.get", c.Responses.DefaultIncludes.Get)
+	if err != nil {
+		return err
+	}
+	c.Responses.DefaultIncludes.List = list
+	c.Responses.DefaultIncludes.Get = get
+	return nil
+}
+
+func normalizeResponseIncludes(key string, values []string) ([]string, error) {
+	out := normalizeStatusList(values)
+	for _, value := range out {
+		if !slices.Contains(ResponseIncludeSections, value) {
+			return nil, fmt.Errorf("%s: unsupported include %q (supported: %s)", key, value, strings.Join(ResponseIncludeSections, ", "))
+		}
+	}
+	return out, nil
 }
 
 func normalizeConfiguredMediaTypes(rawValues []string) []string {
@@ -579,3 +1244,111 @@ func normalizeConfiguredMediaTypes(rawValues []string) []string {
 	}
 	return out
 }
+
+// FormatWIPLimits renders WIP limits as sorted status=max pairs.
+func FormatWIPLimits(limits map[string]int) string {
+	return formatStatusInts(limits)
+}
+
+// FormatStaleThresholds renders stale thresholds as sorted status=days pairs.
+func FormatStaleThresholds(thresholds map[string]int) string {
+	return formatStatusInts(thresholds)
+}
+
+func formatStatusInts(values map[string]int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	statuses := make([]string, 0, len(values))
+	for status := range values {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	parts := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		parts = append(parts, fmt.Sprintf("%s=%d", status, values[status]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseWIPLimits(value string) (map[string]int, error) {
+	return parseStatusInts("wip_limits", "max", value)
+}
+
+// parseStatusInts parses comma-separated status=N pairs with positive N.
+func parseStatusInts(key, unit, value string) (map[string]int, error) {
+	values := map[string]int{}
+	for _, part := range splitCSV(value) {
+		status, raw, ok := strings.Cut(part, "=")
+		status = strings.ToLower(strings.TrimSpace(status))
+		if !ok || status == "" {
+			return nil, fmt.Errorf("%s entries must be status=%s", key, unit)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%s %s for %s must be a positive integer", key, unit, status)
+		}
+		values[status] = n
+	}
+	return values, nil
+}
+
+func normalizeStatusList(values []string) []string {
+	out := make([]string, 0, len(values))
+	seen := map[string]struct{}{}
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		out = append(out, value)
+	}
+	sort.Strings(out)
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func (c *Config) normalizeStaleDefaults() error {
+	c.Stale.Interval = strings.TrimSpace(c.Stale.Interval)
+	if _, err := parseStaleInterval(c.Stale.Interval); err != nil {
+		return err
+	}
+	c.Stale.Thresholds = normalizeWIPLimits(c.Stale.Thresholds)
+	actions, err := normalizeStaleActions(c.Stale.Actions)
+	if err != nil {
+		return err
+	}
+	c.Stale.Actions = actions
+	c.Stale.Label = strings.ToLower(strings.TrimSpace(c.Stale.Label))
+	if c.Stale.Label == "" {
+		c.Stale.Label = DefaultStaleLabel
+	}
+	c.Stale.WebhookURL = strings.TrimSpace(c.Stale.WebhookURL)
+	return nil
+}
+
+// normalizeWIPLimits drops blank statuses and non-positive values; stale
+// thresholds share it.
+func normalizeWIPLimits(limits map[string]int) map[string]int {
+	if len(limits) == 0 {
+		return nil
+	}
+	out := make(map[string]int, len(limits))
+	for status, max := range limits {
+		status = strings.ToLower(strings.TrimSpace(status))
+		if status == "" || max <= 0 {
+			continue
+		}
+		out[status] = max
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
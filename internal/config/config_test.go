@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefault(t *testing.T) {
@@ -80,6 +82,14 @@ func TestIsAllowedKey(t *testing.T) {
 		"attachments.allowed_media_types",
 		"attachments.reject_media_type_mismatch",
 		"attachments.gc_batch_size",
+		"attachments.scan_command",
+		"attachments.scan_url",
+		"attachments.scan_action",
+		"git.github_api_url",
+		"git.gitlab_api_url",
+		"workflow.require_merged_pr",
+		"server.embedded",
+		"server.grpc_listen",
 	} {
 		if !IsAllowedKey(key) {
 			t.Fatalf("expected %q to be allowed", key)
@@ -221,6 +231,221 @@ func TestSetNestedAttachmentKey(t *testing.T) {
 	}
 }
 
+func TestSetWIPLimitsKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wip.toml")
+	if err := SetKey(path, "wip_limits", "in_progress=2, Blocked=5"); err != nil {
+		t.Fatalf("set wip_limits: %v", err)
+	}
+	if err := SetKey(path, "wip_limits", "in_progress=0"); err == nil {
+		t.Fatal("expected error for non-positive wip limit")
+	}
+
+	cfg := Default()
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.WIPLimits["in_progress"] != 2 || cfg.WIPLimits["blocked"] != 5 {
+		t.Fatalf("unexpected wip limits: %#v", cfg.WIPLimits)
+	}
+	got, err := cfg.Get("wip_limits")
+	if err != nil {
+		t.Fatalf("get wip_limits: %v", err)
+	}
+	if got != "blocked=5,in_progress=2" {
+		t.Fatalf("unexpected wip_limits value: %q", got)
+	}
+}
+
+func TestSetWorkflowWIPLimitKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workflow.toml")
+	if err := SetKey(path, "workflow.wip_limit", "3"); err != nil {
+		t.Fatalf("set workflow.wip_limit: %v", err)
+	}
+	if err := SetKey(path, "workflow.wip_limit", "-1"); err == nil {
+		t.Fatal("expected error for negative wip limit")
+	}
+
+	cfg := Default()
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	got, err := cfg.Get("workflow.wip_limit")
+	if err != nil {
+		t.Fatalf("get workflow.wip_limit: %v", err)
+	}
+	if cfg.Workflow.WIPLimit != 3 || got != "3" {
+		t.Fatalf("unexpected workflow.wip_limit: %d / %q", cfg.Workflow.WIPLimit, got)
+	}
+}
+
+func TestStaleKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stale.toml")
+	for key, value := range map[string]string{
+		"stale.interval":   "6h",
+		"stale.thresholds": "open=30, In_Progress=7",
+		"stale.actions":    "webhook,label",
+	} {
+		if err := SetKey(path, key, value); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+	for key, value := range map[string]string{
+		"stale.interval":   "10s",
+		"stale.thresholds": "open=0",
+		"stale.actions":    "close",
+	} {
+		if err := SetKey(path, key, value); err == nil {
+			t.Fatalf("expected error for %s=%s", key, value)
+		}
+	}
+
+	cfg := Default()
+	if cfg.Stale.Label != DefaultStaleLabel || len(cfg.Stale.Actions) != 1 || cfg.Stale.Actions[0] != "label" {
+		t.Fatalf("unexpected stale defaults: %+v", cfg.Stale)
+	}
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Stale.IntervalDuration() != 6*time.Hour {
+		t.Fatalf("unexpected stale.interval: %q", cfg.Stale.Interval)
+	}
+	thresholds, err := cfg.Get("stale.thresholds")
+	if err != nil {
+		t.Fatalf("get stale.thresholds: %v", err)
+	}
+	actions, err := cfg.Get("stale.actions")
+	if err != nil {
+		t.Fatalf("get stale.actions: %v", err)
+	}
+	if thresholds != "in_progress=7,open=30" || actions != "label,webhook" {
+		t.Fatalf("unexpected stale config: %q / %q", thresholds, actions)
+	}
+}
+
+func TestDBKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.toml")
+	if err := SetKey(path, "db.busy_timeout_ms", "15000"); err != nil {
+		t.Fatalf("set db.busy_timeout_ms: %v", err)
+	}
+	if err := SetKey(path, "db.journal_mode", "DELETE"); err != nil {
+		t.Fatalf("set db.journal_mode: %v", err)
+	}
+	if err := SetKey(path, "db.journal_mode", "off"); err == nil {
+		t.Fatal("expected error for unsupported journal mode")
+	}
+	if err := SetKey(path, "db.query_timeout", "2s"); err != nil {
+		t.Fatalf("set db.query_timeout: %v", err)
+	}
+	if err := SetKey(path, "db.query_timeout", "-1s"); err == nil {
+		t.Fatal("expected error for negative query timeout")
+	}
+	if err := SetKey(path, "db.url", "postgres://grns@db.example/grns"); err != nil {
+		t.Fatalf("set db.url: %v", err)
+	}
+	if err := SetKey(path, "db.url", "mysql://grns@db.example/grns"); err == nil {
+		t.Fatal("expected error for non-postgres db url")
+	}
+
+	cfg := Default()
+	if cfg.DB.BusyTimeoutMS != DefaultDBBusyTimeoutMS || cfg.DB.JournalMode != DefaultDBJournalMode || cfg.DB.QueryTimeoutDuration() != 30*time.Second {
+		t.Fatalf("unexpected db defaults: %+v", cfg.DB)
+	}
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.DB.BusyTimeoutMS != 15000 || cfg.DB.JournalMode != "delete" || cfg.DB.QueryTimeoutDuration() != 2*time.Second || cfg.DB.URL != "postgres://grns@db.example/grns" {
+		t.Fatalf("unexpected db config: %+v", cfg.DB)
+	}
+}
+
+func TestBackupScheduleKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.toml")
+	if err := SetKey(path, "backup.interval", "6h"); err != nil {
+		t.Fatalf("set backup.interval: %v", err)
+	}
+	if err := SetKey(path, "backup.interval", "5s"); err == nil {
+		t.Fatal("expected error for interval below the minimum")
+	}
+	if err := SetKey(path, "backup.keep_last", "7"); err != nil {
+		t.Fatalf("set backup.keep_last: %v", err)
+	}
+	if err := SetKey(path, "backup.keep_last", "-1"); err == nil {
+		t.Fatal("expected error for negative keep_last")
+	}
+
+	cfg := Default()
+	if cfg.Backup.IntervalDuration() != 0 {
+		t.Fatalf("expected scheduling off by default, got %v", cfg.Backup.IntervalDuration())
+	}
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Backup.IntervalDuration() != 6*time.Hour || cfg.Backup.KeepLast != 7 {
+		t.Fatalf("unexpected backup config: %+v", cfg.Backup)
+	}
+}
+
+func TestUndoWindowKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "undo.toml")
+	if err := SetKey(path, "undo.window", "30s"); err == nil {
+		t.Fatal("expected error for window below the minimum")
+	}
+	if err := SetKey(path, "undo.window", "15m"); err != nil {
+		t.Fatalf("set undo.window: %v", err)
+	}
+
+	cfg := Default()
+	if cfg.Undo.WindowDuration() != time.Hour {
+		t.Fatalf("expected 1h default window, got %v", cfg.Undo.WindowDuration())
+	}
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Undo.WindowDuration() != 15*time.Minute {
+		t.Fatalf("unexpected undo config: %+v", cfg.Undo)
+	}
+}
+
+func TestFieldLimitKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fields.toml")
+	if err := SetKey(path, "fields.max_description_bytes", "1024"); err != nil {
+		t.Fatalf("set fields.max_description_bytes: %v", err)
+	}
+	if err := SetKey(path, "fields.max_notes_bytes", "0"); err == nil {
+		t.Fatal("expected error for non-positive notes limit")
+	}
+
+	cfg := Default()
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Fields.MaxDescriptionBytes != 1024 {
+		t.Fatalf("expected description limit 1024, got %d", cfg.Fields.MaxDescriptionBytes)
+	}
+	if cfg.Fields.MaxNotesBytes != DefaultMaxNotesBytes {
+		t.Fatalf("expected default notes limit, got %d", cfg.Fields.MaxNotesBytes)
+	}
+}
+
+func TestSetRequireAssigneeForStatusesKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "assignee.toml")
+	if err := SetKey(path, "require_assignee_for_statuses", "In_Progress, blocked,in_progress"); err != nil {
+		t.Fatalf("set require_assignee_for_statuses: %v", err)
+	}
+
+	cfg := Default()
+	if err := loadFile(path, &cfg); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	got, err := cfg.Get("require_assignee_for_statuses")
+	if err != nil {
+		t.Fatalf("get require_assignee_for_statuses: %v", err)
+	}
+	if got != "blocked,in_progress" {
+		t.Fatalf("unexpected require_assignee_for_statuses value: %q", got)
+	}
+}
+
 func TestConfigDirOverridePaths(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("GRNS_CONFIG_DIR", dir)
@@ -681,3 +906,18 @@ func TestGlobalPathFallsBackToSnapCommonWhenHomeConfigMissing(t *testing.T) {
 		t.Fatalf("expected snap common global path %q, got %q", snapConfigPath, path)
 	}
 }
+
+func TestLoadRejectsUnknownResponseInclude(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".grns.toml"), []byte("[responses.default_includes]\nlist = [\"deps\", \"history\"]\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("GRNS_CONFIG_DIR", dir)
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "history") {
+		t.Fatalf("expected unsupported include error, got %v", err)
+	}
+	if _, err := parseSetValue("responses.default_includes.get", "deps,readiness"); err != nil {
+		t.Fatalf("expected valid includes to parse: %v", err)
+	}
+}
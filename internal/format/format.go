@@ -3,6 +3,9 @@ package format
 import (
 	"encoding/json"
 	"io"
+	"reflect"
+	"strings"
+	"text/template"
 )
 
 // Formatter abstracts output formatting.
@@ -18,3 +21,122 @@ func (f JSONFormatter) Write(w io.Writer, payload any) error {
 	enc := json.NewEncoder(w)
 	return enc.Encode(payload)
 }
+
+// TemplateFuncs are available to output templates in addition to the
+// text/template builtins.
+var TemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// TemplateFormatter renders payloads through a Go text/template. A slice
+// payload renders the template once per element, each followed by a newline.
+type TemplateFormatter struct {
+	Template *template.Template
+}
+
+// ParseTemplate compiles text into a TemplateFormatter.
+func ParseTemplate(text string) (TemplateFormatter, error) {
+	tmpl, err := template.New("output").Funcs(TemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return TemplateFormatter{}, err
+	}
+	return TemplateFormatter{Template: tmpl}, nil
+}
+
+// Write renders payload to a writer.
+func (f TemplateFormatter) Write(w io.Writer, payload any) error {
+	value := reflect.ValueOf(payload)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return f.writeOne(w, payload)
+	}
+	for i := range value.Len() {
+		if err := f.writeOne(w, value.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f TemplateFormatter) writeOne(w io.Writer, item any) error {
+	if err := f.Template.Execute(w, item); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// FieldsFormatter writes JSON keeping only the named top-level keys of each
+// object (or of each object in an array), in the order given. Missing keys
+// are written as null.
+type FieldsFormatter struct {
+	Fields []string
+}
+
+// Write writes the selected fields as JSON.
+func (f FieldsFormatter) Write(w io.Writer, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	switch v := generic.(type) {
+	case []any:
+		selected := make([]any, 0, len(v))
+		for _, item := range v {
+			selected = append(selected, f.pick(item))
+		}
+		generic = selected
+	default:
+		generic = f.pick(v)
+	}
+	return JSONFormatter{}.Write(w, generic)
+}
+
+func (f FieldsFormatter) pick(item any) any {
+	object, ok := item.(map[string]any)
+	if !ok {
+		return item
+	}
+	selected := make(orderedObject, 0, len(f.Fields))
+	for _, field := range f.Fields {
+		selected = append(selected, objectField{key: field, value: object[field]})
+	}
+	return selected
+}
+
+type objectField struct {
+	key   string
+	value any
+}
+
+// orderedObject marshals as a JSON object with keys in slice order.
+type orderedObject []objectField
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, field := range o {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		key, err := json.Marshal(field.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, key...)
+		buf = append(buf, ':')
+		buf = append(buf, value...)
+	}
+	return append(buf, '}'), nil
+}
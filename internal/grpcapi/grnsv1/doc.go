@@ -0,0 +1,4 @@
+// Package grnsv1 holds the Go code generated from proto/grns/v1/grns.proto.
+package grnsv1
+
+//go:generate protoc -I ../../../proto --go_out=../../.. --go_opt=module=grns --go-grpc_out=../../.. --go-grpc_opt=module=grns grns/v1/grns.proto
@@ -0,0 +1,1923 @@
+// gRPC interface to grns tasks. It is served next to the JSON HTTP API when
+// server.grpc_listen is set and shares its validation, workflow rules, and
+// authentication. Generated Go code lives in internal/grpcapi/grnsv1.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: grns/v1/grns.proto
+
+package grnsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Dependency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ParentId      string                 `protobuf:"bytes,1,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Dependency) Reset() {
+	*x = Dependency{}
+	mi := &file_grns_v1_grns_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dependency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dependency) ProtoMessage() {}
+
+func (x *Dependency) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dependency.ProtoReflect.Descriptor instead.
+func (*Dependency) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Dependency) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Dependency) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type Task struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Project            string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Id                 string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Title              string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Status             string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Type               string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	Priority           int32                  `protobuf:"varint,6,opt,name=priority,proto3" json:"priority,omitempty"`
+	Description        string                 `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	SpecId             string                 `protobuf:"bytes,8,opt,name=spec_id,json=specId,proto3" json:"spec_id,omitempty"`
+	ParentId           string                 `protobuf:"bytes,9,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Assignee           string                 `protobuf:"bytes,10,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	Notes              string                 `protobuf:"bytes,11,opt,name=notes,proto3" json:"notes,omitempty"`
+	Design             string                 `protobuf:"bytes,12,opt,name=design,proto3" json:"design,omitempty"`
+	AcceptanceCriteria string                 `protobuf:"bytes,13,opt,name=acceptance_criteria,json=acceptanceCriteria,proto3" json:"acceptance_criteria,omitempty"`
+	SourceRepo         string                 `protobuf:"bytes,14,opt,name=source_repo,json=sourceRepo,proto3" json:"source_repo,omitempty"`
+	MilestoneId        string                 `protobuf:"bytes,15,opt,name=milestone_id,json=milestoneId,proto3" json:"milestone_id,omitempty"`
+	BlockedReason      string                 `protobuf:"bytes,16,opt,name=blocked_reason,json=blockedReason,proto3" json:"blocked_reason,omitempty"`
+	BlockedOn          string                 `protobuf:"bytes,17,opt,name=blocked_on,json=blockedOn,proto3" json:"blocked_on,omitempty"`
+	MergedInto         string                 `protobuf:"bytes,18,opt,name=merged_into,json=mergedInto,proto3" json:"merged_into,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,20,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ClosedAt           *timestamppb.Timestamp `protobuf:"bytes,21,opt,name=closed_at,json=closedAt,proto3" json:"closed_at,omitempty"`
+	DeletedAt          *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	Custom             *structpb.Struct       `protobuf:"bytes,23,opt,name=custom,proto3" json:"custom,omitempty"`
+	Labels             []string               `protobuf:"bytes,24,rep,name=labels,proto3" json:"labels,omitempty"`
+	Deps               []*Dependency          `protobuf:"bytes,25,rep,name=deps,proto3" json:"deps,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_grns_v1_grns_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Task) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *Task) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Task) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Task) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Task) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Task) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Task) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Task) GetSpecId() string {
+	if x != nil {
+		return x.SpecId
+	}
+	return ""
+}
+
+func (x *Task) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Task) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *Task) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *Task) GetDesign() string {
+	if x != nil {
+		return x.Design
+	}
+	return ""
+}
+
+func (x *Task) GetAcceptanceCriteria() string {
+	if x != nil {
+		return x.AcceptanceCriteria
+	}
+	return ""
+}
+
+func (x *Task) GetSourceRepo() string {
+	if x != nil {
+		return x.SourceRepo
+	}
+	return ""
+}
+
+func (x *Task) GetMilestoneId() string {
+	if x != nil {
+		return x.MilestoneId
+	}
+	return ""
+}
+
+func (x *Task) GetBlockedReason() string {
+	if x != nil {
+		return x.BlockedReason
+	}
+	return ""
+}
+
+func (x *Task) GetBlockedOn() string {
+	if x != nil {
+		return x.BlockedOn
+	}
+	return ""
+}
+
+func (x *Task) GetMergedInto() string {
+	if x != nil {
+		return x.MergedInto
+	}
+	return ""
+}
+
+func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetClosedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return nil
+}
+
+func (x *Task) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Task) GetCustom() *structpb.Struct {
+	if x != nil {
+		return x.Custom
+	}
+	return nil
+}
+
+func (x *Task) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Task) GetDeps() []*Dependency {
+	if x != nil {
+		return x.Deps
+	}
+	return nil
+}
+
+// ListFilter mirrors the query parameters of GET /v1/tasks. Labels must all
+// match; labels_any needs one. Without statuses, tombstones are excluded.
+type ListFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	Types         []string               `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`
+	Labels        []string               `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty"`
+	LabelsAny     []string               `protobuf:"bytes,4,rep,name=labels_any,json=labelsAny,proto3" json:"labels_any,omitempty"`
+	Ids           []string               `protobuf:"bytes,5,rep,name=ids,proto3" json:"ids,omitempty"`
+	ParentId      string                 `protobuf:"bytes,6,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	MilestoneId   string                 `protobuf:"bytes,7,opt,name=milestone_id,json=milestoneId,proto3" json:"milestone_id,omitempty"`
+	Assignee      string                 `protobuf:"bytes,8,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	NoAssignee    bool                   `protobuf:"varint,9,opt,name=no_assignee,json=noAssignee,proto3" json:"no_assignee,omitempty"`
+	PriorityMin   *int32                 `protobuf:"varint,10,opt,name=priority_min,json=priorityMin,proto3,oneof" json:"priority_min,omitempty"`
+	PriorityMax   *int32                 `protobuf:"varint,11,opt,name=priority_max,json=priorityMax,proto3,oneof" json:"priority_max,omitempty"`
+	Search        string                 `protobuf:"bytes,12,opt,name=search,proto3" json:"search,omitempty"`
+	TitleContains string                 `protobuf:"bytes,13,opt,name=title_contains,json=titleContains,proto3" json:"title_contains,omitempty"`
+	UpdatedAfter  *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`
+	UpdatedBefore *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`
+	Sort          string                 `protobuf:"bytes,16,opt,name=sort,proto3" json:"sort,omitempty"`
+	Order         string                 `protobuf:"bytes,17,opt,name=order,proto3" json:"order,omitempty"`
+	Limit         int32                  `protobuf:"varint,18,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,19,opt,name=offset,proto3" json:"offset,omitempty"`
+	// after_id pages by id; an empty after_id with keyset set starts at the
+	// first page.
+	AfterId       string `protobuf:"bytes,20,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	Keyset        bool   `protobuf:"varint,21,opt,name=keyset,proto3" json:"keyset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFilter) Reset() {
+	*x = ListFilter{}
+	mi := &file_grns_v1_grns_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFilter) ProtoMessage() {}
+
+func (x *ListFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFilter.ProtoReflect.Descriptor instead.
+func (*ListFilter) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListFilter) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListFilter) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *ListFilter) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ListFilter) GetLabelsAny() []string {
+	if x != nil {
+		return x.LabelsAny
+	}
+	return nil
+}
+
+func (x *ListFilter) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *ListFilter) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *ListFilter) GetMilestoneId() string {
+	if x != nil {
+		return x.MilestoneId
+	}
+	return ""
+}
+
+func (x *ListFilter) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *ListFilter) GetNoAssignee() bool {
+	if x != nil {
+		return x.NoAssignee
+	}
+	return false
+}
+
+func (x *ListFilter) GetPriorityMin() int32 {
+	if x != nil && x.PriorityMin != nil {
+		return *x.PriorityMin
+	}
+	return 0
+}
+
+func (x *ListFilter) GetPriorityMax() int32 {
+	if x != nil && x.PriorityMax != nil {
+		return *x.PriorityMax
+	}
+	return 0
+}
+
+func (x *ListFilter) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListFilter) GetTitleContains() string {
+	if x != nil {
+		return x.TitleContains
+	}
+	return ""
+}
+
+func (x *ListFilter) GetUpdatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAfter
+	}
+	return nil
+}
+
+func (x *ListFilter) GetUpdatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedBefore
+	}
+	return nil
+}
+
+func (x *ListFilter) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListFilter) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *ListFilter) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListFilter) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListFilter) GetAfterId() string {
+	if x != nil {
+		return x.AfterId
+	}
+	return ""
+}
+
+func (x *ListFilter) GetKeyset() bool {
+	if x != nil {
+		return x.Keyset
+	}
+	return false
+}
+
+type GetTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Project       string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskRequest) Reset() {
+	*x = GetTaskRequest{}
+	mi := &file_grns_v1_grns_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskRequest) ProtoMessage() {}
+
+func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskRequest) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTaskRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *GetTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Project       string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Filter        *ListFilter            `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_grns_v1_grns_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListTasksRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetFilter() *ListFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ListTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tasks []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// next_after_id is set on full keyset pages.
+	NextAfterId   string `protobuf:"bytes,2,opt,name=next_after_id,json=nextAfterId,proto3" json:"next_after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_grns_v1_grns_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *ListTasksResponse) GetNextAfterId() string {
+	if x != nil {
+		return x.NextAfterId
+	}
+	return ""
+}
+
+type CreateTaskRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Project            string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Id                 string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Title              string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Status             *string                `protobuf:"bytes,4,opt,name=status,proto3,oneof" json:"status,omitempty"`
+	Type               *string                `protobuf:"bytes,5,opt,name=type,proto3,oneof" json:"type,omitempty"`
+	Priority           *int32                 `protobuf:"varint,6,opt,name=priority,proto3,oneof" json:"priority,omitempty"`
+	Description        *string                `protobuf:"bytes,7,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	SpecId             *string                `protobuf:"bytes,8,opt,name=spec_id,json=specId,proto3,oneof" json:"spec_id,omitempty"`
+	ParentId           *string                `protobuf:"bytes,9,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	Assignee           *string                `protobuf:"bytes,10,opt,name=assignee,proto3,oneof" json:"assignee,omitempty"`
+	Notes              *string                `protobuf:"bytes,11,opt,name=notes,proto3,oneof" json:"notes,omitempty"`
+	Design             *string                `protobuf:"bytes,12,opt,name=design,proto3,oneof" json:"design,omitempty"`
+	AcceptanceCriteria *string                `protobuf:"bytes,13,opt,name=acceptance_criteria,json=acceptanceCriteria,proto3,oneof" json:"acceptance_criteria,omitempty"`
+	SourceRepo         *string                `protobuf:"bytes,14,opt,name=source_repo,json=sourceRepo,proto3,oneof" json:"source_repo,omitempty"`
+	MilestoneId        *string                `protobuf:"bytes,15,opt,name=milestone_id,json=milestoneId,proto3,oneof" json:"milestone_id,omitempty"`
+	BlockedReason      *string                `protobuf:"bytes,16,opt,name=blocked_reason,json=blockedReason,proto3,oneof" json:"blocked_reason,omitempty"`
+	BlockedOn          *string                `protobuf:"bytes,17,opt,name=blocked_on,json=blockedOn,proto3,oneof" json:"blocked_on,omitempty"`
+	Custom             *structpb.Struct       `protobuf:"bytes,18,opt,name=custom,proto3" json:"custom,omitempty"`
+	Labels             []string               `protobuf:"bytes,19,rep,name=labels,proto3" json:"labels,omitempty"`
+	Deps               []*Dependency          `protobuf:"bytes,20,rep,name=deps,proto3" json:"deps,omitempty"`
+	Lenient            bool                   `protobuf:"varint,21,opt,name=lenient,proto3" json:"lenient,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CreateTaskRequest) Reset() {
+	*x = CreateTaskRequest{}
+	mi := &file_grns_v1_grns_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskRequest) ProtoMessage() {}
+
+func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateTaskRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetStatus() string {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetType() string {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetPriority() int32 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return 0
+}
+
+func (x *CreateTaskRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetSpecId() string {
+	if x != nil && x.SpecId != nil {
+		return *x.SpecId
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetParentId() string {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetAssignee() string {
+	if x != nil && x.Assignee != nil {
+		return *x.Assignee
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetNotes() string {
+	if x != nil && x.Notes != nil {
+		return *x.Notes
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetDesign() string {
+	if x != nil && x.Design != nil {
+		return *x.Design
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetAcceptanceCriteria() string {
+	if x != nil && x.AcceptanceCriteria != nil {
+		return *x.AcceptanceCriteria
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetSourceRepo() string {
+	if x != nil && x.SourceRepo != nil {
+		return *x.SourceRepo
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetMilestoneId() string {
+	if x != nil && x.MilestoneId != nil {
+		return *x.MilestoneId
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetBlockedReason() string {
+	if x != nil && x.BlockedReason != nil {
+		return *x.BlockedReason
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetBlockedOn() string {
+	if x != nil && x.BlockedOn != nil {
+		return *x.BlockedOn
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetCustom() *structpb.Struct {
+	if x != nil {
+		return x.Custom
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetDeps() []*Dependency {
+	if x != nil {
+		return x.Deps
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetLenient() bool {
+	if x != nil {
+		return x.Lenient
+	}
+	return false
+}
+
+// UpdateTaskRequest changes only the fields that are set.
+type UpdateTaskRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Project            string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Id                 string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Title              *string                `protobuf:"bytes,3,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Status             *string                `protobuf:"bytes,4,opt,name=status,proto3,oneof" json:"status,omitempty"`
+	Type               *string                `protobuf:"bytes,5,opt,name=type,proto3,oneof" json:"type,omitempty"`
+	Priority           *int32                 `protobuf:"varint,6,opt,name=priority,proto3,oneof" json:"priority,omitempty"`
+	Description        *string                `protobuf:"bytes,7,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	SpecId             *string                `protobuf:"bytes,8,opt,name=spec_id,json=specId,proto3,oneof" json:"spec_id,omitempty"`
+	ParentId           *string                `protobuf:"bytes,9,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	Assignee           *string                `protobuf:"bytes,10,opt,name=assignee,proto3,oneof" json:"assignee,omitempty"`
+	Notes              *string                `protobuf:"bytes,11,opt,name=notes,proto3,oneof" json:"notes,omitempty"`
+	Design             *string                `protobuf:"bytes,12,opt,name=design,proto3,oneof" json:"design,omitempty"`
+	AcceptanceCriteria *string                `protobuf:"bytes,13,opt,name=acceptance_criteria,json=acceptanceCriteria,proto3,oneof" json:"acceptance_criteria,omitempty"`
+	SourceRepo         *string                `protobuf:"bytes,14,opt,name=source_repo,json=sourceRepo,proto3,oneof" json:"source_repo,omitempty"`
+	MilestoneId        *string                `protobuf:"bytes,15,opt,name=milestone_id,json=milestoneId,proto3,oneof" json:"milestone_id,omitempty"`
+	BlockedReason      *string                `protobuf:"bytes,16,opt,name=blocked_reason,json=blockedReason,proto3,oneof" json:"blocked_reason,omitempty"`
+	BlockedOn          *string                `protobuf:"bytes,17,opt,name=blocked_on,json=blockedOn,proto3,oneof" json:"blocked_on,omitempty"`
+	Custom             *structpb.Struct       `protobuf:"bytes,18,opt,name=custom,proto3" json:"custom,omitempty"`
+	Force              bool                   `protobuf:"varint,19,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UpdateTaskRequest) Reset() {
+	*x = UpdateTaskRequest{}
+	mi := &file_grns_v1_grns_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskRequest) ProtoMessage() {}
+
+func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateTaskRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetStatus() string {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetType() string {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetPriority() int32 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return 0
+}
+
+func (x *UpdateTaskRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetSpecId() string {
+	if x != nil && x.SpecId != nil {
+		return *x.SpecId
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetParentId() string {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetAssignee() string {
+	if x != nil && x.Assignee != nil {
+		return *x.Assignee
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetNotes() string {
+	if x != nil && x.Notes != nil {
+		return *x.Notes
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetDesign() string {
+	if x != nil && x.Design != nil {
+		return *x.Design
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetAcceptanceCriteria() string {
+	if x != nil && x.AcceptanceCriteria != nil {
+		return *x.AcceptanceCriteria
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetSourceRepo() string {
+	if x != nil && x.SourceRepo != nil {
+		return *x.SourceRepo
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetMilestoneId() string {
+	if x != nil && x.MilestoneId != nil {
+		return *x.MilestoneId
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetBlockedReason() string {
+	if x != nil && x.BlockedReason != nil {
+		return *x.BlockedReason
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetBlockedOn() string {
+	if x != nil && x.BlockedOn != nil {
+		return *x.BlockedOn
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetCustom() *structpb.Struct {
+	if x != nil {
+		return x.Custom
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type CloseTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Project       string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Ids           []string               `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	PrMerged      bool                   `protobuf:"varint,3,opt,name=pr_merged,json=prMerged,proto3" json:"pr_merged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseTasksRequest) Reset() {
+	*x = CloseTasksRequest{}
+	mi := &file_grns_v1_grns_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseTasksRequest) ProtoMessage() {}
+
+func (x *CloseTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseTasksRequest.ProtoReflect.Descriptor instead.
+func (*CloseTasksRequest) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CloseTasksRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *CloseTasksRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *CloseTasksRequest) GetPrMerged() bool {
+	if x != nil {
+		return x.PrMerged
+	}
+	return false
+}
+
+type CloseTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseTasksResponse) Reset() {
+	*x = CloseTasksResponse{}
+	mi := &file_grns_v1_grns_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseTasksResponse) ProtoMessage() {}
+
+func (x *CloseTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseTasksResponse.ProtoReflect.Descriptor instead.
+func (*CloseTasksResponse) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CloseTasksResponse) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type ExportTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Project       string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportTasksRequest) Reset() {
+	*x = ExportTasksRequest{}
+	mi := &file_grns_v1_grns_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTasksRequest) ProtoMessage() {}
+
+func (x *ExportTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTasksRequest.ProtoReflect.Descriptor instead.
+func (*ExportTasksRequest) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExportTasksRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+type ImportOptions struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Project        string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	DryRun         bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Dedupe         string                 `protobuf:"bytes,3,opt,name=dedupe,proto3" json:"dedupe,omitempty"`
+	OrphanHandling string                 `protobuf:"bytes,4,opt,name=orphan_handling,json=orphanHandling,proto3" json:"orphan_handling,omitempty"`
+	Atomic         bool                   `protobuf:"varint,5,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	Lenient        bool                   `protobuf:"varint,6,opt,name=lenient,proto3" json:"lenient,omitempty"`
+	SourceLabel    string                 `protobuf:"bytes,7,opt,name=source_label,json=sourceLabel,proto3" json:"source_label,omitempty"`
+	SaveReport     bool                   `protobuf:"varint,8,opt,name=save_report,json=saveReport,proto3" json:"save_report,omitempty"`
+	RemapIds       bool                   `protobuf:"varint,9,opt,name=remap_ids,json=remapIds,proto3" json:"remap_ids,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ImportOptions) Reset() {
+	*x = ImportOptions{}
+	mi := &file_grns_v1_grns_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportOptions) ProtoMessage() {}
+
+func (x *ImportOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportOptions.ProtoReflect.Descriptor instead.
+func (*ImportOptions) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ImportOptions) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *ImportOptions) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportOptions) GetDedupe() string {
+	if x != nil {
+		return x.Dedupe
+	}
+	return ""
+}
+
+func (x *ImportOptions) GetOrphanHandling() string {
+	if x != nil {
+		return x.OrphanHandling
+	}
+	return ""
+}
+
+func (x *ImportOptions) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+func (x *ImportOptions) GetLenient() bool {
+	if x != nil {
+		return x.Lenient
+	}
+	return false
+}
+
+func (x *ImportOptions) GetSourceLabel() string {
+	if x != nil {
+		return x.SourceLabel
+	}
+	return ""
+}
+
+func (x *ImportOptions) GetSaveReport() bool {
+	if x != nil {
+		return x.SaveReport
+	}
+	return false
+}
+
+func (x *ImportOptions) GetRemapIds() bool {
+	if x != nil {
+		return x.RemapIds
+	}
+	return false
+}
+
+type ImportTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       *ImportOptions         `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	Tasks         []*Task                `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportTasksRequest) Reset() {
+	*x = ImportTasksRequest{}
+	mi := &file_grns_v1_grns_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTasksRequest) ProtoMessage() {}
+
+func (x *ImportTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTasksRequest.ProtoReflect.Descriptor instead.
+func (*ImportTasksRequest) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ImportTasksRequest) GetOptions() *ImportOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ImportTasksRequest) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type ImportConflict struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// line is the 1-based position of the task in the stream.
+	Line          int32  `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Detail        string `protobuf:"bytes,4,opt,name=detail,proto3" json:"detail,omitempty"`
+	Resolution    string `protobuf:"bytes,5,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportConflict) Reset() {
+	*x = ImportConflict{}
+	mi := &file_grns_v1_grns_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportConflict) ProtoMessage() {}
+
+func (x *ImportConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportConflict.ProtoReflect.Descriptor instead.
+func (*ImportConflict) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ImportConflict) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *ImportConflict) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImportConflict) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ImportConflict) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *ImportConflict) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+type ImportTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       int32                  `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Updated       int32                  `protobuf:"varint,2,opt,name=updated,proto3" json:"updated,omitempty"`
+	Skipped       int32                  `protobuf:"varint,3,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Errors        int32                  `protobuf:"varint,4,opt,name=errors,proto3" json:"errors,omitempty"`
+	DryRun        bool                   `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	TaskIds       []string               `protobuf:"bytes,6,rep,name=task_ids,json=taskIds,proto3" json:"task_ids,omitempty"`
+	Messages      []string               `protobuf:"bytes,7,rep,name=messages,proto3" json:"messages,omitempty"`
+	Warnings      []string               `protobuf:"bytes,8,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	ApplyMode     string                 `protobuf:"bytes,9,opt,name=apply_mode,json=applyMode,proto3" json:"apply_mode,omitempty"`
+	RolledBack    bool                   `protobuf:"varint,10,opt,name=rolled_back,json=rolledBack,proto3" json:"rolled_back,omitempty"`
+	Conflicts     []*ImportConflict      `protobuf:"bytes,11,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	ReportId      int64                  `protobuf:"varint,12,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	IdMap         map[string]string      `protobuf:"bytes,13,rep,name=id_map,json=idMap,proto3" json:"id_map,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportTasksResponse) Reset() {
+	*x = ImportTasksResponse{}
+	mi := &file_grns_v1_grns_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTasksResponse) ProtoMessage() {}
+
+func (x *ImportTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grns_v1_grns_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTasksResponse.ProtoReflect.Descriptor instead.
+func (*ImportTasksResponse) Descriptor() ([]byte, []int) {
+	return file_grns_v1_grns_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ImportTasksResponse) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportTasksResponse) GetUpdated() int32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *ImportTasksResponse) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *ImportTasksResponse) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *ImportTasksResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportTasksResponse) GetTaskIds() []string {
+	if x != nil {
+		return x.TaskIds
+	}
+	return nil
+}
+
+func (x *ImportTasksResponse) GetMessages() []string {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ImportTasksResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *ImportTasksResponse) GetApplyMode() string {
+	if x != nil {
+		return x.ApplyMode
+	}
+	return ""
+}
+
+func (x *ImportTasksResponse) GetRolledBack() bool {
+	if x != nil {
+		return x.RolledBack
+	}
+	return false
+}
+
+func (x *ImportTasksResponse) GetConflicts() []*ImportConflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+func (x *ImportTasksResponse) GetReportId() int64 {
+	if x != nil {
+		return x.ReportId
+	}
+	return 0
+}
+
+func (x *ImportTasksResponse) GetIdMap() map[string]string {
+	if x != nil {
+		return x.IdMap
+	}
+	return nil
+}
+
+var File_grns_v1_grns_proto protoreflect.FileDescriptor
+
+const file_grns_v1_grns_proto_rawDesc = "" +
+	"\n" +
+	"\x12grns/v1/grns.proto\x12\agrns.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"=\n" +
+	"\n" +
+	"Dependency\x12\x1b\n" +
+	"\tparent_id\x18\x01 \x01(\tR\bparentId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\"\xe8\x06\n" +
+	"\x04Task\x12\x18\n" +
+	"\aproject\x18\x01 \x01(\tR\aproject\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12\x1a\n" +
+	"\bpriority\x18\x06 \x01(\x05R\bpriority\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x12\x17\n" +
+	"\aspec_id\x18\b \x01(\tR\x06specId\x12\x1b\n" +
+	"\tparent_id\x18\t \x01(\tR\bparentId\x12\x1a\n" +
+	"\bassignee\x18\n" +
+	" \x01(\tR\bassignee\x12\x14\n" +
+	"\x05notes\x18\v \x01(\tR\x05notes\x12\x16\n" +
+	"\x06design\x18\f \x01(\tR\x06design\x12/\n" +
+	"\x13acceptance_criteria\x18\r \x01(\tR\x12acceptanceCriteria\x12\x1f\n" +
+	"\vsource_repo\x18\x0e \x01(\tR\n" +
+	"sourceRepo\x12!\n" +
+	"\fmilestone_id\x18\x0f \x01(\tR\vmilestoneId\x12%\n" +
+	"\x0eblocked_reason\x18\x10 \x01(\tR\rblockedReason\x12\x1d\n" +
+	"\n" +
+	"blocked_on\x18\x11 \x01(\tR\tblockedOn\x12\x1f\n" +
+	"\vmerged_into\x18\x12 \x01(\tR\n" +
+	"mergedInto\x129\n" +
+	"\n" +
+	"created_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x14 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x127\n" +
+	"\tclosed_at\x18\x15 \x01(\v2\x1a.google.protobuf.TimestampR\bclosedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\x16 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12/\n" +
+	"\x06custom\x18\x17 \x01(\v2\x17.google.protobuf.StructR\x06custom\x12\x16\n" +
+	"\x06labels\x18\x18 \x03(\tR\x06labels\x12'\n" +
+	"\x04deps\x18\x19 \x03(\v2\x13.grns.v1.DependencyR\x04deps\"\xc4\x05\n" +
+	"\n" +
+	"ListFilter\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12\x14\n" +
+	"\x05types\x18\x02 \x03(\tR\x05types\x12\x16\n" +
+	"\x06labels\x18\x03 \x03(\tR\x06labels\x12\x1d\n" +
+	"\n" +
+	"labels_any\x18\x04 \x03(\tR\tlabelsAny\x12\x10\n" +
+	"\x03ids\x18\x05 \x03(\tR\x03ids\x12\x1b\n" +
+	"\tparent_id\x18\x06 \x01(\tR\bparentId\x12!\n" +
+	"\fmilestone_id\x18\a \x01(\tR\vmilestoneId\x12\x1a\n" +
+	"\bassignee\x18\b \x01(\tR\bassignee\x12\x1f\n" +
+	"\vno_assignee\x18\t \x01(\bR\n" +
+	"noAssignee\x12&\n" +
+	"\fpriority_min\x18\n" +
+	" \x01(\x05H\x00R\vpriorityMin\x88\x01\x01\x12&\n" +
+	"\fpriority_max\x18\v \x01(\x05H\x01R\vpriorityMax\x88\x01\x01\x12\x16\n" +
+	"\x06search\x18\f \x01(\tR\x06search\x12%\n" +
+	"\x0etitle_contains\x18\r \x01(\tR\rtitleContains\x12?\n" +
+	"\rupdated_after\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\fupdatedAfter\x12A\n" +
+	"\x0eupdated_before\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\rupdatedBefore\x12\x12\n" +
+	"\x04sort\x18\x10 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\x11 \x01(\tR\x05order\x12\x14\n" +
+	"\x05limit\x18\x12 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x13 \x01(\x05R\x06offset\x12\x19\n" +
+	"\bafter_id\x18\x14 \x01(\tR\aafterId\x12\x16\n" +
+	"\x06keyset\x18\x15 \x01(\bR\x06keysetB\x0f\n" +
+	"\r_priority_minB\x0f\n" +
+	"\r_priority_max\":\n" +
+	"\x0eGetTaskRequest\x12\x18\n" +
+	"\aproject\x18\x01 \x01(\tR\aproject\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"Y\n" +
+	"\x10ListTasksRequest\x12\x18\n" +
+	"\aproject\x18\x01 \x01(\tR\aproject\x12+\n" +
+	"\x06filter\x18\x02 \x01(\v2\x13.grns.v1.ListFilterR\x06filter\"\\\n" +
+	"\x11ListTasksResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.grns.v1.TaskR\x05tasks\x12\"\n" +
+	"\rnext_after_id\x18\x02 \x01(\tR\vnextAfterId\"\x92\a\n" +
+	"\x11CreateTaskRequest\x12\x18\n" +
+	"\aproject\x18\x01 \x01(\tR\aproject\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x1b\n" +
+	"\x06status\x18\x04 \x01(\tH\x00R\x06status\x88\x01\x01\x12\x17\n" +
+	"\x04type\x18\x05 \x01(\tH\x01R\x04type\x88\x01\x01\x12\x1f\n" +
+	"\bpriority\x18\x06 \x01(\x05H\x02R\bpriority\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\a \x01(\tH\x03R\vdescription\x88\x01\x01\x12\x1c\n" +
+	"\aspec_id\x18\b \x01(\tH\x04R\x06specId\x88\x01\x01\x12 \n" +
+	"\tparent_id\x18\t \x01(\tH\x05R\bparentId\x88\x01\x01\x12\x1f\n" +
+	"\bassignee\x18\n" +
+	" \x01(\tH\x06R\bassignee\x88\x01\x01\x12\x19\n" +
+	"\x05notes\x18\v \x01(\tH\aR\x05notes\x88\x01\x01\x12\x1b\n" +
+	"\x06design\x18\f \x01(\tH\bR\x06design\x88\x01\x01\x124\n" +
+	"\x13acceptance_criteria\x18\r \x01(\tH\tR\x12acceptanceCriteria\x88\x01\x01\x12$\n" +
+	"\vsource_repo\x18\x0e \x01(\tH\n" +
+	"R\n" +
+	"sourceRepo\x88\x01\x01\x12&\n" +
+	"\fmilestone_id\x18\x0f \x01(\tH\vR\vmilestoneId\x88\x01\x01\x12*\n" +
+	"\x0eblocked_reason\x18\x10 \x01(\tH\fR\rblockedReason\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"blocked_on\x18\x11 \x01(\tH\rR\tblockedOn\x88\x01\x01\x12/\n" +
+	"\x06custom\x18\x12 \x01(\v2\x17.google.protobuf.StructR\x06custom\x12\x16\n" +
+	"\x06labels\x18\x13 \x03(\tR\x06labels\x12'\n" +
+	"\x04deps\x18\x14 \x03(\v2\x13.grns.v1.DependencyR\x04deps\x12\x18\n" +
+	"\alenient\x18\x15 \x01(\bR\alenientB\t\n" +
+	"\a_statusB\a\n" +
+	"\x05_typeB\v\n" +
+	"\t_priorityB\x0e\n" +
+	"\f_descriptionB\n" +
+	"\n" +
+	"\b_spec_idB\f\n" +
+	"\n" +
+	"_parent_idB\v\n" +
+	"\t_assigneeB\b\n" +
+	"\x06_notesB\t\n" +
+	"\a_designB\x16\n" +
+	"\x14_acceptance_criteriaB\x0e\n" +
+	"\f_source_repoB\x0f\n" +
+	"\r_milestone_idB\x11\n" +
+	"\x0f_blocked_reasonB\r\n" +
+	"\v_blocked_on\"\xdc\x06\n" +
+	"\x11UpdateTaskRequest\x12\x18\n" +
+	"\aproject\x18\x01 \x01(\tR\aproject\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x19\n" +
+	"\x05title\x18\x03 \x01(\tH\x00R\x05title\x88\x01\x01\x12\x1b\n" +
+	"\x06status\x18\x04 \x01(\tH\x01R\x06status\x88\x01\x01\x12\x17\n" +
+	"\x04type\x18\x05 \x01(\tH\x02R\x04type\x88\x01\x01\x12\x1f\n" +
+	"\bpriority\x18\x06 \x01(\x05H\x03R\bpriority\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\a \x01(\tH\x04R\vdescription\x88\x01\x01\x12\x1c\n" +
+	"\aspec_id\x18\b \x01(\tH\x05R\x06specId\x88\x01\x01\x12 \n" +
+	"\tparent_id\x18\t \x01(\tH\x06R\bparentId\x88\x01\x01\x12\x1f\n" +
+	"\bassignee\x18\n" +
+	" \x01(\tH\aR\bassignee\x88\x01\x01\x12\x19\n" +
+	"\x05notes\x18\v \x01(\tH\bR\x05notes\x88\x01\x01\x12\x1b\n" +
+	"\x06design\x18\f \x01(\tH\tR\x06design\x88\x01\x01\x124\n" +
+	"\x13acceptance_criteria\x18\r \x01(\tH\n" +
+	"R\x12acceptanceCriteria\x88\x01\x01\x12$\n" +
+	"\vsource_repo\x18\x0e \x01(\tH\vR\n" +
+	"sourceRepo\x88\x01\x01\x12&\n" +
+	"\fmilestone_id\x18\x0f \x01(\tH\fR\vmilestoneId\x88\x01\x01\x12*\n" +
+	"\x0eblocked_reason\x18\x10 \x01(\tH\rR\rblockedReason\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"blocked_on\x18\x11 \x01(\tH\x0eR\tblockedOn\x88\x01\x01\x12/\n" +
+	"\x06custom\x18\x12 \x01(\v2\x17.google.protobuf.StructR\x06custom\x12\x14\n" +
+	"\x05force\x18\x13 \x01(\bR\x05forceB\b\n" +
+	"\x06_titleB\t\n" +
+	"\a_statusB\a\n" +
+	"\x05_typeB\v\n" +
+	"\t_priorityB\x0e\n" +
+	"\f_descriptionB\n" +
+	"\n" +
+	"\b_spec_idB\f\n" +
+	"\n" +
+	"_parent_idB\v\n" +
+	"\t_assigneeB\b\n" +
+	"\x06_notesB\t\n" +
+	"\a_designB\x16\n" +
+	"\x14_acceptance_criteriaB\x0e\n" +
+	"\f_source_repoB\x0f\n" +
+	"\r_milestone_idB\x11\n" +
+	"\x0f_blocked_reasonB\r\n" +
+	"\v_blocked_on\"\\\n" +
+	"\x11CloseTasksRequest\x12\x18\n" +
+	"\aproject\x18\x01 \x01(\tR\aproject\x12\x10\n" +
+	"\x03ids\x18\x02 \x03(\tR\x03ids\x12\x1b\n" +
+	"\tpr_merged\x18\x03 \x01(\bR\bprMerged\"&\n" +
+	"\x12CloseTasksResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\".\n" +
+	"\x12ExportTasksRequest\x12\x18\n" +
+	"\aproject\x18\x01 \x01(\tR\aproject\"\x96\x02\n" +
+	"\rImportOptions\x12\x18\n" +
+	"\aproject\x18\x01 \x01(\tR\aproject\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12\x16\n" +
+	"\x06dedupe\x18\x03 \x01(\tR\x06dedupe\x12'\n" +
+	"\x0forphan_handling\x18\x04 \x01(\tR\x0eorphanHandling\x12\x16\n" +
+	"\x06atomic\x18\x05 \x01(\bR\x06atomic\x12\x18\n" +
+	"\alenient\x18\x06 \x01(\bR\alenient\x12!\n" +
+	"\fsource_label\x18\a \x01(\tR\vsourceLabel\x12\x1f\n" +
+	"\vsave_report\x18\b \x01(\bR\n" +
+	"saveReport\x12\x1b\n" +
+	"\tremap_ids\x18\t \x01(\bR\bremapIds\"k\n" +
+	"\x12ImportTasksRequest\x120\n" +
+	"\aoptions\x18\x01 \x01(\v2\x16.grns.v1.ImportOptionsR\aoptions\x12#\n" +
+	"\x05tasks\x18\x02 \x03(\v2\r.grns.v1.TaskR\x05tasks\"\x84\x01\n" +
+	"\x0eImportConflict\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\x05R\x04line\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06detail\x18\x04 \x01(\tR\x06detail\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\x05 \x01(\tR\n" +
+	"resolution\"\xf5\x03\n" +
+	"\x13ImportTasksResponse\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x05R\acreated\x12\x18\n" +
+	"\aupdated\x18\x02 \x01(\x05R\aupdated\x12\x18\n" +
+	"\askipped\x18\x03 \x01(\x05R\askipped\x12\x16\n" +
+	"\x06errors\x18\x04 \x01(\x05R\x06errors\x12\x17\n" +
+	"\adry_run\x18\x05 \x01(\bR\x06dryRun\x12\x19\n" +
+	"\btask_ids\x18\x06 \x03(\tR\ataskIds\x12\x1a\n" +
+	"\bmessages\x18\a \x03(\tR\bmessages\x12\x1a\n" +
+	"\bwarnings\x18\b \x03(\tR\bwarnings\x12\x1d\n" +
+	"\n" +
+	"apply_mode\x18\t \x01(\tR\tapplyMode\x12\x1f\n" +
+	"\vrolled_back\x18\n" +
+	" \x01(\bR\n" +
+	"rolledBack\x125\n" +
+	"\tconflicts\x18\v \x03(\v2\x17.grns.v1.ImportConflictR\tconflicts\x12\x1b\n" +
+	"\treport_id\x18\f \x01(\x03R\breportId\x12>\n" +
+	"\x06id_map\x18\r \x03(\v2'.grns.v1.ImportTasksResponse.IdMapEntryR\x05idMap\x1a8\n" +
+	"\n" +
+	"IdMapEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xc6\x03\n" +
+	"\vTaskService\x121\n" +
+	"\aGetTask\x12\x17.grns.v1.GetTaskRequest\x1a\r.grns.v1.Task\x12B\n" +
+	"\tListTasks\x12\x19.grns.v1.ListTasksRequest\x1a\x1a.grns.v1.ListTasksResponse\x127\n" +
+	"\n" +
+	"CreateTask\x12\x1a.grns.v1.CreateTaskRequest\x1a\r.grns.v1.Task\x127\n" +
+	"\n" +
+	"UpdateTask\x12\x1a.grns.v1.UpdateTaskRequest\x1a\r.grns.v1.Task\x12E\n" +
+	"\n" +
+	"CloseTasks\x12\x1a.grns.v1.CloseTasksRequest\x1a\x1b.grns.v1.CloseTasksResponse\x12;\n" +
+	"\vExportTasks\x12\x1b.grns.v1.ExportTasksRequest\x1a\r.grns.v1.Task0\x01\x12J\n" +
+	"\vImportTasks\x12\x1b.grns.v1.ImportTasksRequest\x1a\x1c.grns.v1.ImportTasksResponse(\x01B%Z#grns/internal/grpcapi/grnsv1;grnsv1b\x06proto3"
+
+var (
+	file_grns_v1_grns_proto_rawDescOnce sync.Once
+	file_grns_v1_grns_proto_rawDescData []byte
+)
+
+func file_grns_v1_grns_proto_rawDescGZIP() []byte {
+	file_grns_v1_grns_proto_rawDescOnce.Do(func() {
+		file_grns_v1_grns_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_grns_v1_grns_proto_rawDesc), len(file_grns_v1_grns_proto_rawDesc)))
+	})
+	return file_grns_v1_grns_proto_rawDescData
+}
+
+var file_grns_v1_grns_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_grns_v1_grns_proto_goTypes = []any{
+	(*Dependency)(nil),            // 0: grns.v1.Dependency
+	(*Task)(nil),                  // 1: grns.v1.Task
+	(*ListFilter)(nil),            // 2: grns.v1.ListFilter
+	(*GetTaskRequest)(nil),        // 3: grns.v1.GetTaskRequest
+	(*ListTasksRequest)(nil),      // 4: grns.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),     // 5: grns.v1.ListTasksResponse
+	(*CreateTaskRequest)(nil),     // 6: grns.v1.CreateTaskRequest
+	(*UpdateTaskRequest)(nil),     // 7: grns.v1.UpdateTaskRequest
+	(*CloseTasksRequest)(nil),     // 8: grns.v1.CloseTasksRequest
+	(*CloseTasksResponse)(nil),    // 9: grns.v1.CloseTasksResponse
+	(*ExportTasksRequest)(nil),    // 10: grns.v1.ExportTasksRequest
+	(*ImportOptions)(nil),         // 11: grns.v1.ImportOptions
+	(*ImportTasksRequest)(nil),    // 12: grns.v1.ImportTasksRequest
+	(*ImportConflict)(nil),        // 13: grns.v1.ImportConflict
+	(*ImportTasksResponse)(nil),   // 14: grns.v1.ImportTasksResponse
+	nil,                           // 15: grns.v1.ImportTasksResponse.IdMapEntry
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),       // 17: google.protobuf.Struct
+}
+var file_grns_v1_grns_proto_depIdxs = []int32{
+	16, // 0: grns.v1.Task.created_at:type_name -> google.protobuf.Timestamp
+	16, // 1: grns.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
+	16, // 2: grns.v1.Task.closed_at:type_name -> google.protobuf.Timestamp
+	16, // 3: grns.v1.Task.deleted_at:type_name -> google.protobuf.Timestamp
+	17, // 4: grns.v1.Task.custom:type_name -> google.protobuf.Struct
+	0,  // 5: grns.v1.Task.deps:type_name -> grns.v1.Dependency
+	16, // 6: grns.v1.ListFilter.updated_after:type_name -> google.protobuf.Timestamp
+	16, // 7: grns.v1.ListFilter.updated_before:type_name -> google.protobuf.Timestamp
+	2,  // 8: grns.v1.ListTasksRequest.filter:type_name -> grns.v1.ListFilter
+	1,  // 9: grns.v1.ListTasksResponse.tasks:type_name -> grns.v1.Task
+	17, // 10: grns.v1.CreateTaskRequest.custom:type_name -> google.protobuf.Struct
+	0,  // 11: grns.v1.CreateTaskRequest.deps:type_name -> grns.v1.Dependency
+	17, // 12: grns.v1.UpdateTaskRequest.custom:type_name -> google.protobuf.Struct
+	11, // 13: grns.v1.ImportTasksRequest.options:type_name -> grns.v1.ImportOptions
+	1,  // 14: grns.v1.ImportTasksRequest.tasks:type_name -> grns.v1.Task
+	13, // 15: grns.v1.ImportTasksResponse.conflicts:type_name -> grns.v1.ImportConflict
+	15, // 16: grns.v1.ImportTasksResponse.id_map:type_name -> grns.v1.ImportTasksResponse.IdMapEntry
+	3,  // 17: grns.v1.TaskService.GetTask:input_type -> grns.v1.GetTaskRequest
+	4,  // 18: grns.v1.TaskService.ListTasks:input_type -> grns.v1.ListTasksRequest
+	6,  // 19: grns.v1.TaskService.CreateTask:input_type -> grns.v1.CreateTaskRequest
+	7,  // 20: grns.v1.TaskService.UpdateTask:input_type -> grns.v1.UpdateTaskRequest
+	8,  // 21: grns.v1.TaskService.CloseTasks:input_type -> grns.v1.CloseTasksRequest
+	10, // 22: grns.v1.TaskService.ExportTasks:input_type -> grns.v1.ExportTasksRequest
+	12, // 23: grns.v1.TaskService.ImportTasks:input_type -> grns.v1.ImportTasksRequest
+	1,  // 24: grns.v1.TaskService.GetTask:output_type -> grns.v1.Task
+	5,  // 25: grns.v1.TaskService.ListTasks:output_type -> grns.v1.ListTasksResponse
+	1,  // 26: grns.v1.TaskService.CreateTask:output_type -> grns.v1.Task
+	1,  // 27: grns.v1.TaskService.UpdateTask:output_type -> grns.v1.Task
+	9,  // 28: grns.v1.TaskService.CloseTasks:output_type -> grns.v1.CloseTasksResponse
+	1,  // 29: grns.v1.TaskService.ExportTasks:output_type -> grns.v1.Task
+	14, // 30: grns.v1.TaskService.ImportTasks:output_type -> grns.v1.ImportTasksResponse
+	24, // [24:31] is the sub-list for method output_type
+	17, // [17:24] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_grns_v1_grns_proto_init() }
+func file_grns_v1_grns_proto_init() {
+	if File_grns_v1_grns_proto != nil {
+		return
+	}
+	file_grns_v1_grns_proto_msgTypes[2].OneofWrappers = []any{}
+	file_grns_v1_grns_proto_msgTypes[6].OneofWrappers = []any{}
+	file_grns_v1_grns_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_grns_v1_grns_proto_rawDesc), len(file_grns_v1_grns_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_grns_v1_grns_proto_goTypes,
+		DependencyIndexes: file_grns_v1_grns_proto_depIdxs,
+		MessageInfos:      file_grns_v1_grns_proto_msgTypes,
+	}.Build()
+	File_grns_v1_grns_proto = out.File
+	file_grns_v1_grns_proto_goTypes = nil
+	file_grns_v1_grns_proto_depIdxs = nil
+}
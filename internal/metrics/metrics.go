@@ -0,0 +1,260 @@
+// Package metrics implements the small subset of the Prometheus text
+// exposition format that grns exposes, without depending on the Prometheus
+// client library.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets are histogram bounds in seconds suited to HTTP and
+// SQLite latencies.
+var DefaultDurationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DefaultSizeBuckets are histogram bounds suited to record counts.
+var DefaultSizeBuckets = []float64{1, 10, 50, 100, 500, 1000, 5000, 10000, 50000}
+
+const labelSeparator = "\xff"
+
+// Registry holds metric families and renders them in registration order.
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+}
+
+type family interface {
+	writeTo(w *bufio.Writer)
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families = append(r.families, f)
+}
+
+// WriteText renders every registered family in the Prometheus text format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]family(nil), r.families...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.writeTo(bw)
+	}
+	return bw.Flush()
+}
+
+type vecBase struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (v vecBase) key(values []string) string {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	return strings.Join(values, labelSeparator)
+}
+
+func (v vecBase) writeHeader(w *bufio.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", v.name, escapeHelp(v.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", v.name, kind)
+}
+
+// labelPairs renders {a="x",b="y"} for key, appending extra pairs verbatim.
+func (v vecBase) labelPairs(key string, extra ...string) string {
+	pairs := make([]string, 0, len(v.labels)+len(extra))
+	if len(v.labels) > 0 {
+		values := strings.Split(key, labelSeparator)
+		for i, label := range v.labels {
+			pairs = append(pairs, label+`="`+escapeLabelValue(values[i])+`"`)
+		}
+	}
+	pairs = append(pairs, extra...)
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// CounterVec is a monotonically increasing value partitioned by labels.
+type CounterVec struct {
+	vecBase
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec registers a counter family.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vecBase: vecBase{name: name, help: help, labels: labels}, values: map[string]float64{}}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the series identified by values.
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds delta, which must not be negative, to the series identified by values.
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.name))
+	}
+	key := c.key(values)
+	c.mu.Lock()
+	c.values[key] += delta
+	c.mu.Unlock()
+}
+
+func (c *CounterVec) writeTo(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeHeader(w, "counter")
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelPairs(key), formatFloat(c.values[key]))
+	}
+}
+
+// GaugeVec is a value that can go up and down, partitioned by labels.
+type GaugeVec struct {
+	vecBase
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewGaugeVec registers a gauge family.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{vecBase: vecBase{name: name, help: help, labels: labels}, values: map[string]float64{}}
+	r.register(g)
+	return g
+}
+
+// Set replaces the value of the series identified by values.
+func (g *GaugeVec) Set(value float64, values ...string) {
+	key := g.key(values)
+	g.mu.Lock()
+	g.values[key] = value
+	g.mu.Unlock()
+}
+
+// Reset drops every series so stale label combinations disappear.
+func (g *GaugeVec) Reset() {
+	g.mu.Lock()
+	g.values = map[string]float64{}
+	g.mu.Unlock()
+}
+
+func (g *GaugeVec) writeTo(w *bufio.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.writeHeader(w, "gauge")
+	for _, key := range sortedKeys(g.values) {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelPairs(key), formatFloat(g.values[key]))
+	}
+}
+
+// HistogramVec counts observations into cumulative buckets, partitioned by labels.
+type HistogramVec struct {
+	vecBase
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec registers a histogram family with the given upper bounds.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	h := &HistogramVec{vecBase: vecBase{name: name, help: help, labels: labels}, buckets: bounds, series: map[string]*histogram{}}
+	r.register(h)
+	return h
+}
+
+// Observe records value in the series identified by values.
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = series
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+func (h *HistogramVec) writeTo(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.writeHeader(w, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		series := h.series[key]
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(key, `le="`+formatFloat(bound)+`"`), series.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(key, `le="+Inf"`), series.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(key), formatFloat(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(key), series.count)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
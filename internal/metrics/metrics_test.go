@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.NewCounterVec("grns_test_requests_total", "Requests served.", "route", "status")
+	tasks := reg.NewGaugeVec("grns_test_tasks", "Tasks by status.", "status")
+	latency := reg.NewHistogramVec("grns_test_seconds", "Latency.", []float64{0.1, 1})
+
+	requests.Inc("GET /a", "200")
+	requests.Add(2, "GET /a", "200")
+	requests.Inc(`GET /"b"`, "500")
+	tasks.Set(3, "open")
+	tasks.Reset()
+	tasks.Set(4, "closed")
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(3)
+
+	var buf bytes.Buffer
+	if err := reg.WriteText(&buf); err != nil {
+		t.Fatalf("write text: %v", err)
+	}
+	want := strings.Join([]string{
+		"# HELP grns_test_requests_total Requests served.",
+		"# TYPE grns_test_requests_total counter",
+		`grns_test_requests_total{route="GET /\"b\"",status="500"} 1`,
+		`grns_test_requests_total{route="GET /a",status="200"} 3`,
+		"# HELP grns_test_tasks Tasks by status.",
+		"# TYPE grns_test_tasks gauge",
+		`grns_test_tasks{status="closed"} 4`,
+		"# HELP grns_test_seconds Latency.",
+		"# TYPE grns_test_seconds histogram",
+		`grns_test_seconds_bucket{le="0.1"} 1`,
+		`grns_test_seconds_bucket{le="1"} 2`,
+		`grns_test_seconds_bucket{le="+Inf"} 3`,
+		"grns_test_seconds_sum 3.55",
+		"grns_test_seconds_count 3",
+	}, "\n") + "\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected exposition:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
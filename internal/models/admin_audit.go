@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// AdminAuditEntry records one admin operation for the server-side audit trail.
+type AdminAuditEntry struct {
+	ID        int64          `json:"id"`
+	Action    string         `json:"action"`
+	Actor     string         `json:"actor,omitempty"`
+	Params    map[string]any `json:"params,omitempty"`
+	Affected  int            `json:"affected"`
+	DryRun    bool           `json:"dry_run"`
+	CreatedAt time.Time      `json:"created_at"`
+}
@@ -10,4 +10,24 @@ type Blob struct {
 	StorageBackend string    `json:"storage_backend"`
 	BlobKey        string    `json:"blob_key"`
 	CreatedAt      time.Time `json:"created_at"`
+	// QuarantinedAt is set once an upload scan quarantines the content; every
+	// attachment of the blob is then withheld, however it was attached.
+	QuarantinedAt *time.Time `json:"quarantined_at,omitempty"`
+}
+
+// BlobUsage is a blob with the number of attachments that reference it.
+type BlobUsage struct {
+	Blob
+	AttachmentCount int `json:"attachment_count"`
+}
+
+// BlobUsageStats aggregates blob storage across all projects. LogicalBytes is
+// what managed attachments would take if each stored its own copy.
+type BlobUsageStats struct {
+	BlobCount              int   `json:"blob_count"`
+	TotalBytes             int64 `json:"total_bytes"`
+	ManagedAttachmentCount int   `json:"managed_attachment_count"`
+	LogicalBytes           int64 `json:"logical_bytes"`
+	UnreferencedCount      int   `json:"unreferenced_count"`
+	UnreferencedBytes      int64 `json:"unreferenced_bytes"`
 }
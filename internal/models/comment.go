@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// TaskComment is one entry in a task's discussion thread.
+type TaskComment struct {
+	Project   string    `json:"project,omitempty"`
+	ID        string    `json:"id"`
+	TaskID    string    `json:"task_id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
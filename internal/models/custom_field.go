@@ -0,0 +1,38 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CustomFieldType defines the value types a custom field schema entry accepts.
+type CustomFieldType string
+
+const (
+	CustomFieldString CustomFieldType = "string"
+	CustomFieldNumber CustomFieldType = "number"
+	CustomFieldBool   CustomFieldType = "bool"
+)
+
+// CustomField declares one allowed key of Task.Custom for a project.
+type CustomField struct {
+	Project   string    `json:"project"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Required  bool      `json:"required"`
+	Values    []string  `json:"values,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ParseCustomFieldType parses a custom field type string.
+func ParseCustomFieldType(value string) (CustomFieldType, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	switch CustomFieldType(normalized) {
+	case CustomFieldString, CustomFieldNumber, CustomFieldBool:
+		return CustomFieldType(normalized), nil
+	default:
+		return "", fmt.Errorf("invalid custom field type: %s", value)
+	}
+}
@@ -8,6 +8,7 @@ type Dependency struct {
 
 // DepTreeNode represents a single node in a dependency tree walk.
 type DepTreeNode struct {
+	Project   string `json:"project,omitempty"`
 	ID        string `json:"id"`
 	Title     string `json:"title"`
 	Status    string `json:"status"`
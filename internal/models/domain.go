@@ -32,8 +32,12 @@ const (
 // DependencyType defines supported dependency edge kinds.
 type DependencyType string
 
+// Only blocks edges gate readiness; the other kinds are informational.
 const (
-	DependencyBlocks DependencyType = "blocks"
+	DependencyBlocks     DependencyType = "blocks"
+	DependencyRelatesTo  DependencyType = "relates_to"
+	DependencyDuplicates DependencyType = "duplicates"
+	DependencySubtaskOf  DependencyType = "subtask_of"
 )
 
 const (
@@ -44,6 +48,31 @@ const (
 	DependencyTreeMaxDepth = 50
 )
 
+var orderedTaskStatuses = []TaskStatus{
+	StatusOpen,
+	StatusInProgress,
+	StatusBlocked,
+	StatusDeferred,
+	StatusClosed,
+	StatusTombstone,
+	StatusPinned,
+}
+
+var orderedTaskTypes = []TaskType{
+	TypeBug,
+	TypeFeature,
+	TypeTask,
+	TypeEpic,
+	TypeChore,
+}
+
+var orderedDependencyTypes = []DependencyType{
+	DependencyBlocks,
+	DependencyRelatesTo,
+	DependencyDuplicates,
+	DependencySubtaskOf,
+}
+
 var validTaskStatuses = map[TaskStatus]struct{}{
 	StatusOpen:       {},
 	StatusInProgress: {},
@@ -62,6 +91,13 @@ var validTaskTypes = map[TaskType]struct{}{
 	TypeChore:   {},
 }
 
+var validDependencyTypes = map[DependencyType]struct{}{
+	DependencyBlocks:     {},
+	DependencyRelatesTo:  {},
+	DependencyDuplicates: {},
+	DependencySubtaskOf:  {},
+}
+
 var readyTaskStatuses = []TaskStatus{
 	StatusOpen,
 	StatusInProgress,
@@ -80,11 +116,37 @@ func IsValidTaskStatus(status TaskStatus) bool {
 	return ok
 }
 
+// IsReadyTaskStatus reports whether status is eligible for the ready queue.
+func IsReadyTaskStatus(status string) bool {
+	for _, value := range readyTaskStatuses {
+		if string(value) == status {
+			return true
+		}
+	}
+	return false
+}
+
 func IsValidTaskType(taskType TaskType) bool {
 	_, ok := validTaskTypes[taskType]
 	return ok
 }
 
+func IsValidDependencyType(depType DependencyType) bool {
+	_, ok := validDependencyTypes[depType]
+	return ok
+}
+
+func ParseDependencyType(raw string) (DependencyType, error) {
+	value := DependencyType(strings.ToLower(strings.TrimSpace(raw)))
+	if value == "" {
+		return "", fmt.Errorf("dependency type is required")
+	}
+	if !IsValidDependencyType(value) {
+		return "", fmt.Errorf("invalid dependency type: %s", value)
+	}
+	return value, nil
+}
+
 func ParseTaskStatus(raw string) (TaskStatus, error) {
 	value := TaskStatus(strings.ToLower(strings.TrimSpace(raw)))
 	if value == "" {
@@ -107,6 +169,26 @@ func ParseTaskType(raw string) (TaskType, error) {
 	return value, nil
 }
 
+func TaskStatusStrings() []string {
+	return statusStrings(orderedTaskStatuses)
+}
+
+func TaskTypeStrings() []string {
+	out := make([]string, 0, len(orderedTaskTypes))
+	for _, value := range orderedTaskTypes {
+		out = append(out, string(value))
+	}
+	return out
+}
+
+func DependencyTypeStrings() []string {
+	out := make([]string, 0, len(orderedDependencyTypes))
+	for _, value := range orderedDependencyTypes {
+		out = append(out, string(value))
+	}
+	return out
+}
+
 func ReadyTaskStatusStrings() []string {
 	return statusStrings(readyTaskStatuses)
 }
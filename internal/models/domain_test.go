@@ -30,6 +30,20 @@ func TestParseTaskType(t *testing.T) {
 	}
 }
 
+func TestParseDependencyType(t *testing.T) {
+	got, err := ParseDependencyType(" Relates_To ")
+	if err != nil {
+		t.Fatalf("parse dependency type: %v", err)
+	}
+	if got != DependencyRelatesTo {
+		t.Fatalf("expected %q, got %q", DependencyRelatesTo, got)
+	}
+
+	if _, err := ParseDependencyType("parent-of"); err == nil {
+		t.Fatal("expected invalid dependency type error")
+	}
+}
+
 func TestIsValidPriority(t *testing.T) {
 	if !IsValidPriority(DefaultPriority) {
 		t.Fatalf("expected default priority %d to be valid", DefaultPriority)
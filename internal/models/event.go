@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// TaskEventType names the kind of mutation recorded in a task's history.
+type TaskEventType string
+
+const (
+	TaskEventCreated       TaskEventType = "created"
+	TaskEventUpdated       TaskEventType = "updated"
+	TaskEventClosed        TaskEventType = "closed"
+	TaskEventReopened      TaskEventType = "reopened"
+	TaskEventDeleted       TaskEventType = "deleted"
+	TaskEventRestored      TaskEventType = "restored"
+	TaskEventMerged        TaskEventType = "merged"
+	TaskEventClaimed       TaskEventType = "claimed"
+	TaskEventLeaseExpired  TaskEventType = "lease_expired"
+	TaskEventTouched       TaskEventType = "touched"
+	TaskEventLabelsAdded   TaskEventType = "labels_added"
+	TaskEventLabelsRemoved TaskEventType = "labels_removed"
+	TaskEventDepAdded      TaskEventType = "dep_added"
+	TaskEventDepRemoved    TaskEventType = "dep_removed"
+	TaskEventUndone        TaskEventType = "undone"
+)
+
+// TaskFieldChange records one field's value before and after a mutation.
+type TaskFieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// TaskEvent is one entry in a task's audit history.
+type TaskEvent struct {
+	Project   string            `json:"project,omitempty"`
+	ID        int64             `json:"id"`
+	TaskID    string            `json:"task_id"`
+	Type      TaskEventType     `json:"type"`
+	Actor     string            `json:"actor,omitempty"`
+	Changes   []TaskFieldChange `json:"changes,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
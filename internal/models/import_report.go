@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// ImportConflictReason classifies why an import rejected a record.
+type ImportConflictReason string
+
+const (
+	ImportConflictDuplicateID      ImportConflictReason = "duplicate_id"
+	ImportConflictOrphanDependency ImportConflictReason = "orphan_dependency"
+	ImportConflictMissingField     ImportConflictReason = "missing_id_or_title"
+	ImportConflictMissingBlob      ImportConflictReason = "missing_blob"
+)
+
+// ImportConflict is one rejected import record. Line is the 1-based NDJSON line
+// for streamed imports, or the 1-based position in the tasks array otherwise.
+type ImportConflict struct {
+	Line       int                  `json:"line"`
+	ID         string               `json:"id,omitempty"`
+	Reason     ImportConflictReason `json:"reason"`
+	Detail     string               `json:"detail"`
+	Resolution string               `json:"resolution"`
+}
+
+// ImportReport is a saved set of import conflicts that can be fetched after the import.
+type ImportReport struct {
+	ID        int64            `json:"id"`
+	Project   string           `json:"project,omitempty"`
+	DryRun    bool             `json:"dry_run"`
+	Conflicts []ImportConflict `json:"conflicts"`
+	CreatedAt time.Time        `json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// TaskLease records an agent's time-boxed claim on a task. When the lease
+// expires the task returns to the ready pool.
+type TaskLease struct {
+	TaskID    string    `json:"task_id"`
+	Holder    string    `json:"holder"`
+	ClaimedAt time.Time `json:"claimed_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MilestoneState defines the lifecycle states of a milestone.
+type MilestoneState string
+
+const (
+	MilestoneOpen   MilestoneState = "open"
+	MilestoneClosed MilestoneState = "closed"
+)
+
+// MilestoneDueDateLayout is the calendar-date format of Milestone.DueDate.
+const MilestoneDueDateLayout = "2006-01-02"
+
+// Milestone is a time-boxed planning bucket (release, sprint) that tasks can be assigned to.
+type Milestone struct {
+	Project   string    `json:"project"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	DueDate   string    `json:"due_date,omitempty"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ParseMilestoneState parses a milestone state string.
+func ParseMilestoneState(value string) (MilestoneState, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	switch MilestoneState(normalized) {
+	case MilestoneOpen, MilestoneClosed:
+		return MilestoneState(normalized), nil
+	default:
+		return "", fmt.Errorf("invalid milestone state: %s", value)
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// OperationKind names a journaled mutation that can be undone.
+type OperationKind string
+
+const (
+	OperationClose       OperationKind = "close"
+	OperationReopen      OperationKind = "reopen"
+	OperationBulkUpdate  OperationKind = "bulk_update"
+	OperationLabelDelete OperationKind = "label_delete"
+	OperationLabelRename OperationKind = "label_rename"
+	OperationBatch       OperationKind = "batch"
+)
+
+// Operation is one entry in a project's undo journal. Before holds the task
+// rows as they were prior to the mutation; label_delete entries carry Label
+// instead, and label_rename entries carry Label, RenamedTo, and the task ids
+// that gained RenamedTo.
+type Operation struct {
+	ID        int64         `json:"id"`
+	Project   string        `json:"project,omitempty"`
+	Kind      OperationKind `json:"kind"`
+	Actor     string        `json:"actor,omitempty"`
+	TaskIDs   []string      `json:"task_ids"`
+	Label     string        `json:"label,omitempty"`
+	RenamedTo string        `json:"renamed_to,omitempty"`
+	Added     []string      `json:"-"`
+	Before    []Task        `json:"-"`
+	CreatedAt time.Time     `json:"created_at"`
+	UndoneAt  *time.Time    `json:"undone_at,omitempty"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// SavedFilter is a named set of list query parameters persisted per project.
+type SavedFilter struct {
+	Project   string            `json:"project"`
+	Name      string            `json:"name"`
+	Filter    map[string]string `json:"filter"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
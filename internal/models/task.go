@@ -18,8 +18,13 @@ type Task struct {
 	Design             string         `json:"design,omitempty"`
 	AcceptanceCriteria string         `json:"acceptance_criteria,omitempty"`
 	SourceRepo         string         `json:"source_repo,omitempty"`
+	MilestoneID        string         `json:"milestone_id,omitempty"`
 	CreatedAt          time.Time      `json:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
 	ClosedAt           *time.Time     `json:"closed_at,omitempty"`
+	DeletedAt          *time.Time     `json:"deleted_at,omitempty"`
+	MergedInto         string         `json:"merged_into,omitempty"`
+	BlockedReason      string         `json:"blocked_reason,omitempty"`
+	BlockedOn          string         `json:"blocked_on,omitempty"`
 	Custom             map[string]any `json:"custom,omitempty"`
 }
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// TaskWatcher subscribes a user to changes on a task.
+type TaskWatcher struct {
+	TaskID    string    `json:"task_id"`
+	User      string    `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
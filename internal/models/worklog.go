@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// TaskWorklog is one recorded span of effort spent on a task.
+type TaskWorklog struct {
+	Project   string    `json:"project,omitempty"`
+	ID        string    `json:"id"`
+	TaskID    string    `json:"task_id"`
+	Actor     string    `json:"actor"`
+	Seconds   int64     `json:"seconds"`
+	Note      string    `json:"note,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
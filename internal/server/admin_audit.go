@@ -0,0 +1,61 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"grns/internal/models"
+)
+
+// Admin audit action names.
+const (
+	auditActionCleanup        = "cleanup"
+	auditActionPurge          = "purge"
+	auditActionLabelRename    = "labels.rename"
+	auditActionGCBlobs        = "gc-blobs"
+	auditActionRepairDangling = "dangling-attachments.repair"
+	auditActionRecompute      = "recompute"
+	auditActionBackup         = "backup"
+	auditActionMaintenance    = "maintenance"
+	auditActionReindex        = "reindex"
+	auditActionMaintenanceOn  = "maintenance-mode.enable"
+	auditActionMaintenanceOff = "maintenance-mode.disable"
+	auditActionUserAdd        = "user.add"
+	auditActionUserDisable    = "user.disable"
+	auditActionUserEnable     = "user.enable"
+	auditActionUserDelete     = "user.delete"
+	auditActionTokenCreate    = "token.create"
+	auditActionTokenRotate    = "token.rotate"
+	auditActionTokenDelete    = "token.delete"
+)
+
+// recordAdminAudit appends one entry to the admin audit trail. It runs after
+// the operation succeeded, so a failed write is logged rather than surfaced.
+func (s *Server) recordAdminAudit(r *http.Request, action string, params map[string]any, affected int, dryRun bool) {
+	if s.adminAudit == nil {
+		return
+	}
+	entry := &models.AdminAuditEntry{
+		Action:    action,
+		Actor:     adminActor(r),
+		Params:    params,
+		Affected:  affected,
+		DryRun:    dryRun,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.adminAudit.AppendAdminAudit(r.Context(), entry); err != nil {
+		s.log().Error("admin audit write failed", "action", action, "error", err)
+	}
+}
+
+// adminActor names who performed an admin request: the session user or
+// X-Actor when present, otherwise the named API token.
+func adminActor(r *http.Request) string {
+	if actor := actorFromContext(r.Context()); actor != "" {
+		return actor
+	}
+	if principal, ok := authPrincipalFromContext(r.Context()); ok && principal.Token != nil {
+		return "token:" + principal.Token.Name
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"grns/internal/store"
+)
+
+const (
+	authTypeAPIToken     = "api_token"
+	apiTokenSecretPrefix = "grns_"
+	apiTokenScopeRead    = "read"
+	apiTokenScopeWrite   = "write"
+	apiTokenScopeAdmin   = "admin"
+	maxAPITokenNameLen   = 64
+)
+
+var apiTokenScopes = []string{apiTokenScopeRead, apiTokenScopeWrite, apiTokenScopeAdmin}
+
+// normalizeAPITokenScopes lowercases, validates, and orders scopes. An empty
+// list defaults to read and write.
+func normalizeAPITokenScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return []string{apiTokenScopeRead, apiTokenScopeWrite}, nil
+	}
+	seen := map[string]bool{}
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(apiTokenScopes, scope) {
+			return nil, badRequestCode(fmt.Errorf("invalid scope %q (allowed: %s)", scope, strings.Join(apiTokenScopes, ", ")), ErrCodeInvalidArgument)
+		}
+		seen[scope] = true
+	}
+	normalized := make([]string, 0, len(seen))
+	for _, scope := range apiTokenScopes {
+		if seen[scope] {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
+// apiTokenAllows reports whether token's scopes cover the request: admin
+// endpoints need admin, other mutations need write, and reads need read.
+func apiTokenAllows(token *store.APIToken, r *http.Request) bool {
+	if token == nil {
+		return false
+	}
+	required := apiTokenScopeRead
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/admin/"):
+		required = apiTokenScopeAdmin
+	case isUnsafeMethod(r.Method):
+		required = apiTokenScopeWrite
+	}
+	return slices.Contains(token.Scopes, required)
+}
+
+// CreateAPIToken issues a named token and returns it with its plaintext
+// secret. The secret is not stored and cannot be recovered later.
+func (a *AuthService) CreateAPIToken(ctx context.Context, name string, scopes []string, expiresAt *time.Time, now time.Time) (*store.APIToken, string, error) {
+	if a == nil || a.tokens == nil {
+		return nil, "", fmt.Errorf("api token store is required")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", badRequestCode(fmt.Errorf("name is required"), ErrCodeMissingRequired)
+	}
+	if len(name) > maxAPITokenNameLen {
+		return nil, "", badRequestCode(fmt.Errorf("name must be at most %d characters", maxAPITokenNameLen), ErrCodeInvalidArgument)
+	}
+	normalized, err := normalizeAPITokenScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", badRequestCode(fmt.Errorf("expires_at must be in the future"), ErrCodeInvalidArgument)
+	}
+
+	secret, err := generateAPITokenSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	token := &store.APIToken{
+		Name:      name,
+		TokenHash: hashSessionToken(secret),
+		Scopes:    normalized,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+	if err := a.tokens.CreateAPIToken(ctx, token); err != nil {
+		if isUniqueConstraint(err) {
+			return nil, "", conflictCode(fmt.Errorf("token name already exists"), ErrCodeConflict)
+		}
+		return nil, "", err
+	}
+	a.InvalidateAuthRequiredCache()
+	return token, secret, nil
+}
+
+// ListAPITokens returns all named tokens without secrets.
+func (a *AuthService) ListAPITokens(ctx context.Context) ([]store.APIToken, error) {
+	if a == nil || a.tokens == nil {
+		return nil, fmt.Errorf("api token store is required")
+	}
+	return a.tokens.ListAPITokens(ctx)
+}
+
+// GetAPIToken returns one token or a not-found error.
+func (a *AuthService) GetAPIToken(ctx context.Context, id string) (*store.APIToken, error) {
+	if a == nil || a.tokens == nil {
+		return nil, fmt.Errorf("api token store is required")
+	}
+	token, err := a.tokens.GetAPIToken(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, notFoundCode(fmt.Errorf("api token not found"), ErrCodeAPITokenNotFound)
+	}
+	return token, nil
+}
+
+// RotateAPIToken replaces a token's secret, keeping its name, scopes, and
+// expiry. The previous secret stops working immediately.
+func (a *AuthService) RotateAPIToken(ctx context.Context, id string, now time.Time) (*store.APIToken, string, error) {
+	if a == nil || a.tokens == nil {
+		return nil, "", fmt.Errorf("api token store is required")
+	}
+	secret, err := generateAPITokenSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	token, err := a.tokens.RotateAPIToken(ctx, id, hashSessionToken(secret), now)
+	if err != nil {
+		return nil, "", err
+	}
+	if token == nil {
+		return nil, "", notFoundCode(fmt.Errorf("api token not found"), ErrCodeAPITokenNotFound)
+	}
+	return token, secret, nil
+}
+
+// DeleteAPIToken revokes one token.
+func (a *AuthService) DeleteAPIToken(ctx context.Context, id string) error {
+	if a == nil || a.tokens == nil {
+		return fmt.Errorf("api token store is required")
+	}
+	deleted, err := a.tokens.DeleteAPIToken(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return notFoundCode(fmt.Errorf("api token not found"), ErrCodeAPITokenNotFound)
+	}
+	a.InvalidateAuthRequiredCache()
+	return nil
+}
+
+// AuthenticateAPIToken returns the unexpired token matching secret, or nil.
+func (a *AuthService) AuthenticateAPIToken(ctx context.Context, secret string, now time.Time) (*store.APIToken, error) {
+	if a == nil || a.tokens == nil {
+		return nil, nil
+	}
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return nil, nil
+	}
+	return a.tokens.GetAPITokenByHash(ctx, hashSessionToken(secret), now)
+}
+
+func generateAPITokenSecret() (string, error) {
+	token, err := generateSessionToken()
+	if err != nil {
+		return "", err
+	}
+	return apiTokenSecretPrefix + token, nil
+}
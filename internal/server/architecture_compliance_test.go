@@ -19,10 +19,16 @@ type registeredRoute struct {
 }
 
 type boundaryCalls struct {
-	service           []string
-	attachmentService []string
-	gitRefService     []string
-	store             []string
+	service            []string
+	attachmentService  []string
+	gitRefService      []string
+	commentService     []string
+	worklogService     []string
+	watcherService     []string
+	savedFilterService []string
+	milestoneService   []string
+	customFieldService []string
+	store              []string
 }
 
 func TestMutationRoutesUseServiceBoundary(t *testing.T) {
@@ -52,7 +58,7 @@ func TestMutationRoutesUseServiceBoundary(t *testing.T) {
 		if len(calls.store) > 0 {
 			t.Fatalf("handler %q (%s %s) calls s.store directly: %v", route.handler, route.method, route.path, calls.store)
 		}
-		if len(calls.service) == 0 && len(calls.attachmentService) == 0 && len(calls.gitRefService) == 0 {
+		if len(calls.service) == 0 && len(calls.attachmentService) == 0 && len(calls.gitRefService) == 0 && len(calls.commentService) == 0 && len(calls.worklogService) == 0 && len(calls.watcherService) == 0 && len(calls.savedFilterService) == 0 && len(calls.milestoneService) == 0 && len(calls.customFieldService) == 0 {
 			t.Fatalf("handler %q (%s %s) does not call a service boundary", route.handler, route.method, route.path)
 		}
 	}
@@ -171,6 +177,18 @@ func inspectBoundaryCalls(fn *ast.FuncDecl) boundaryCalls {
 			calls.attachmentService = append(calls.attachmentService, selector.Sel.Name)
 		case "gitRefService":
 			calls.gitRefService = append(calls.gitRefService, selector.Sel.Name)
+		case "commentService":
+			calls.commentService = append(calls.commentService, selector.Sel.Name)
+		case "worklogService":
+			calls.worklogService = append(calls.worklogService, selector.Sel.Name)
+		case "watcherService":
+			calls.watcherService = append(calls.watcherService, selector.Sel.Name)
+		case "savedFilterService":
+			calls.savedFilterService = append(calls.savedFilterService, selector.Sel.Name)
+		case "milestoneService":
+			calls.milestoneService = append(calls.milestoneService, selector.Sel.Name)
+		case "customFieldService":
+			calls.customFieldService = append(calls.customFieldService, selector.Sel.Name)
 		case "store":
 			calls.store = append(calls.store, selector.Sel.Name)
 		}
@@ -179,6 +197,12 @@ func inspectBoundaryCalls(fn *ast.FuncDecl) boundaryCalls {
 	calls.service = uniqueSorted(calls.service)
 	calls.attachmentService = uniqueSorted(calls.attachmentService)
 	calls.gitRefService = uniqueSorted(calls.gitRefService)
+	calls.commentService = uniqueSorted(calls.commentService)
+	calls.worklogService = uniqueSorted(calls.worklogService)
+	calls.watcherService = uniqueSorted(calls.watcherService)
+	calls.savedFilterService = uniqueSorted(calls.savedFilterService)
+	calls.milestoneService = uniqueSorted(calls.milestoneService)
+	calls.customFieldService = uniqueSorted(calls.customFieldService)
 	calls.store = uniqueSorted(calls.store)
 	return calls
 }
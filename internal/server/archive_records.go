@@ -0,0 +1,280 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+// attachArchiveSections adds each task's attachments and git refs to export
+// records. Managed attachments carry their blob digest so an import can match
+// the bytes on another server. Sections whose store is not configured are left out.
+func (s *TaskService) attachArchiveSections(ctx context.Context, project string, records []api.TaskResponse) error {
+	if s.attachments == nil && s.gitRefs == nil {
+		return nil
+	}
+	var attachmentMap map[string][]models.Attachment
+	if s.attachments != nil {
+		ids := make([]string, 0, len(records))
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		var err error
+		attachmentMap, err = s.attachments.ListAttachmentsForTasks(ctx, project, ids)
+		if err != nil {
+			return err
+		}
+	}
+	blobs := map[string]*models.Blob{}
+	for i := range records {
+		if s.attachments != nil {
+			for _, attachment := range attachmentMap[records[i].ID] {
+				record := api.AttachmentRecord{Attachment: attachment}
+				if attachment.BlobID != "" {
+					blob, ok := blobs[attachment.BlobID]
+					if !ok {
+						var err error
+						blob, err = s.attachments.GetBlob(ctx, attachment.BlobID)
+						if err != nil {
+							return err
+						}
+						blobs[attachment.BlobID] = blob
+					}
+					if blob != nil {
+						record.SHA256 = blob.SHA256
+						record.SizeBytes = blob.SizeBytes
+					}
+				}
+				records[i].Attachments = append(records[i].Attachments, record)
+			}
+		}
+		if s.gitRefs != nil {
+			refs, err := s.gitRefs.ListTaskGitRefs(ctx, project, records[i].ID)
+			if err != nil {
+				return err
+			}
+			if len(refs) > 0 {
+				records[i].GitRefs = refs
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeArchiveSections validates the attachments and git refs of one
+// import record. Store-local ids (blob_id, repo_id) are dropped; they are
+// resolved again from sha256 and repo when the record is applied.
+func normalizeArchiveSections(rec api.TaskImportRecord, project string) (api.TaskImportRecord, error) {
+	if rec.Attachments != nil {
+		attachments := make([]api.AttachmentRecord, 0, len(rec.Attachments))
+		for _, attachment := range rec.Attachments {
+			normalized, err := normalizeImportAttachment(attachment, rec.ID, project)
+			if err != nil {
+				return rec, err
+			}
+			attachments = append(attachments, normalized)
+		}
+		rec.Attachments = attachments
+	}
+	if rec.GitRefs != nil {
+		refs := make([]models.TaskGitRef, 0, len(rec.GitRefs))
+		for _, ref := range rec.GitRefs {
+			normalized, err := normalizeImportGitRef(ref, rec.ID, project)
+			if err != nil {
+				return rec, err
+			}
+			refs = append(refs, normalized)
+		}
+		rec.GitRefs = refs
+	}
+	return rec, nil
+}
+
+func normalizeImportAttachment(attachment api.AttachmentRecord, taskID, project string) (api.AttachmentRecord, error) {
+	attachment.ID = strings.TrimSpace(attachment.ID)
+	if !validateAttachmentID(attachment.ID) {
+		return attachment, badRequestCode(fmt.Errorf("invalid attachment id: %s", attachment.ID), ErrCodeInvalidID)
+	}
+	if owner := strings.TrimSpace(attachment.TaskID); owner != "" && owner != taskID {
+		return attachment, badRequestCode(fmt.Errorf("attachment %s belongs to task %s, not %s", attachment.ID, owner, taskID), ErrCodeInvalidArgument)
+	}
+	attachment.TaskID = taskID
+	attachment.Project = project
+
+	kind, err := models.ParseAttachmentKind(attachment.Kind)
+	if err != nil {
+		return attachment, badRequestCode(fmt.Errorf("attachment %s: %w", attachment.ID, err), ErrCodeInvalidArgument)
+	}
+	attachment.Kind = string(kind)
+	sourceType, err := models.ParseAttachmentSourceType(attachment.SourceType)
+	if err != nil {
+		return attachment, badRequestCode(fmt.Errorf("attachment %s: %w", attachment.ID, err), ErrCodeInvalidArgument)
+	}
+	attachment.SourceType = string(sourceType)
+
+	switch sourceType {
+	case models.AttachmentSourceManagedBlob:
+		attachment.SHA256 = strings.ToLower(strings.TrimSpace(attachment.SHA256))
+		if !validateSHA256Hex(attachment.SHA256) {
+			return attachment, badRequestCode(fmt.Errorf("attachment %s: sha256 must be 64 hex characters", attachment.ID), ErrCodeInvalidArgument)
+		}
+	case models.AttachmentSourceExternalURL:
+		if strings.TrimSpace(attachment.ExternalURL) == "" {
+			return attachment, badRequestCode(fmt.Errorf("attachment %s: external_url is required", attachment.ID), ErrCodeMissingRequired)
+		}
+	case models.AttachmentSourceRepoPath:
+		if err := validateWorkspaceRelativePath(strings.TrimSpace(attachment.RepoPath)); err != nil {
+			return attachment, badRequestCode(fmt.Errorf("attachment %s: %w", attachment.ID, err), ErrCodeInvalidArgument)
+		}
+	}
+	attachment.BlobID = ""
+
+	labels, err := normalizeLabels(attachment.Labels)
+	if err != nil {
+		return attachment, badRequest(err)
+	}
+	attachment.Labels = labels
+	return attachment, nil
+}
+
+func normalizeImportGitRef(ref models.TaskGitRef, taskID, project string) (models.TaskGitRef, error) {
+	ref.ID = strings.TrimSpace(ref.ID)
+	if !validateGitRefID(ref.ID) {
+		return ref, badRequestCode(fmt.Errorf("invalid git ref id: %s", ref.ID), ErrCodeInvalidID)
+	}
+	if owner := strings.TrimSpace(ref.TaskID); owner != "" && owner != taskID {
+		return ref, badRequestCode(fmt.Errorf("git ref %s belongs to task %s, not %s", ref.ID, owner, taskID), ErrCodeInvalidArgument)
+	}
+	ref.TaskID = taskID
+	ref.Project = project
+
+	relation, err := normalizeGitRelation(ref.Relation)
+	if err != nil {
+		return ref, err
+	}
+	ref.Relation = relation
+	objectType, err := models.ParseGitObjectType(ref.ObjectType)
+	if err != nil {
+		return ref, badRequestCode(err, ErrCodeInvalidArgument)
+	}
+	ref.ObjectType = string(objectType)
+	if ref.ObjectValue, err = normalizeGitObjectValue(objectType, ref.ObjectValue); err != nil {
+		return ref, err
+	}
+	if ref.ResolvedCommit, err = normalizeGitHash(ref.ResolvedCommit, "resolved_commit"); err != nil {
+		return ref, err
+	}
+	if ref.Repo, err = canonicalGitRepoSlug(ref.Repo); err != nil {
+		return ref, badRequestCode(fmt.Errorf("git ref %s: %w", ref.ID, err), ErrCodeInvalidArgument)
+	}
+	ref.RepoID = ""
+	ref.Note = strings.TrimSpace(ref.Note)
+	return ref, nil
+}
+
+// applyArchiveSections creates the attachments and git refs of imported tasks.
+// It runs after tasks and deps, outside any atomic transaction. Ids that
+// already exist are left alone, so re-importing an archive is idempotent.
+func (i *Importer) applyArchiveSections(ctx context.Context, run *importRun) error {
+	for idx, rec := range run.normalized {
+		action := run.actions[idx]
+		if action != importActionCreated && action != importActionUpdated && action != importActionMerged {
+			continue
+		}
+		if len(rec.Attachments) > 0 {
+			if i.attachments == nil {
+				run.response.Messages = append(run.response.Messages, fmt.Sprintf("skipped %d attachments for %s: attachments are not supported", len(rec.Attachments), rec.ID))
+			} else {
+				for _, attachment := range rec.Attachments {
+					if err := i.importAttachment(ctx, run, idx, attachment); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if len(rec.GitRefs) > 0 {
+			if i.gitRefs == nil {
+				run.response.Messages = append(run.response.Messages, fmt.Sprintf("skipped %d git refs for %s: git refs are not supported", len(rec.GitRefs), rec.ID))
+			} else {
+				for _, ref := range rec.GitRefs {
+					if err := i.importGitRef(ctx, run, ref); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// importAttachment recreates one attachment under its original id. A managed
+// attachment needs its blob to be on this server already (POST /blobs); dry
+// runs do not check for it.
+func (i *Importer) importAttachment(ctx context.Context, run *importRun, idx int, record api.AttachmentRecord) error {
+	existing, err := i.attachments.GetAttachment(ctx, "", record.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	attachment := record.Attachment
+	// A scan verdict is only taken from the archive when it quarantines;
+	// otherwise the blob on this server decides.
+	if !quarantinedScan(attachment.Meta) {
+		attachment.Meta = withoutScanMeta(attachment.Meta)
+	}
+	if !run.req.DryRun {
+		if attachment.SourceType == string(models.AttachmentSourceManagedBlob) {
+			blob, err := i.attachments.GetBlobBySHA256(ctx, record.SHA256)
+			if err != nil {
+				return err
+			}
+			if blob == nil {
+				run.response.Errors++
+				run.response.Messages = append(run.response.Messages, fmt.Sprintf("missing blob for attachment %s (sha256 %s); attachment skipped", record.ID, record.SHA256))
+				run.conflict(idx, record.TaskID, models.ImportConflictMissingBlob, fmt.Sprintf("blob %s for attachment %s is not on this server; attachment skipped", record.SHA256, record.ID),
+					"upload the blob via POST /blobs, or import with grns import --archive, then re-import")
+				return nil
+			}
+			attachment.BlobID = blob.ID
+			attachment.Meta = withBlobQuarantine(attachment.Meta, blob)
+		}
+		if err := i.attachments.CreateAttachment(ctx, &attachment); err != nil {
+			return err
+		}
+	}
+	run.response.AttachmentsCreated++
+	return nil
+}
+
+// importGitRef recreates one git ref under its original id, registering its repo when needed.
+func (i *Importer) importGitRef(ctx context.Context, run *importRun, ref models.TaskGitRef) error {
+	existing, err := i.gitRefs.GetTaskGitRef(ctx, "", ref.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	if !run.req.DryRun {
+		repo, err := i.gitRefs.UpsertGitRepo(ctx, &models.GitRepo{Slug: ref.Repo})
+		if err != nil {
+			return err
+		}
+		if repo == nil || !validateGitRepoID(repo.ID) {
+			return internalError(fmt.Errorf("invalid git repo state"))
+		}
+		ref.RepoID = repo.ID
+		if err := i.gitRefs.CreateTaskGitRef(ctx, &ref); err != nil {
+			return err
+		}
+	}
+	run.response.GitRefsCreated++
+	return nil
+}
@@ -0,0 +1,214 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register decoders for image previews
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Preview limits. Sizes are clamped rather than rejected so UIs can ask for
+// whatever fits their layout.
+const (
+	defaultPreviewImageSize = 256
+	minPreviewImageSize     = 16
+	maxPreviewImageSize     = 1024
+	defaultPreviewTextBytes = 16 * 1024
+	maxPreviewTextBytes     = 256 * 1024
+	// maxPreviewSourcePixels guards against decompression bombs.
+	maxPreviewSourcePixels = 40_000_000
+	maxCachedPreviews      = 128
+)
+
+// AttachmentPreview is a small rendering of managed attachment content: a PNG
+// thumbnail for images, or the leading UTF-8 text of text blobs.
+type AttachmentPreview struct {
+	Data      []byte
+	MediaType string
+	Truncated bool
+	// ETag identifies the preview; it is derived from the blob sha256 and the requested limit.
+	ETag string
+}
+
+// previewCache keeps recent previews keyed by blob sha256 and limit. Blobs are
+// immutable, so entries never go stale; the oldest are evicted first.
+type previewCache struct {
+	mu      sync.Mutex
+	entries map[string]AttachmentPreview
+	order   []string
+}
+
+func (c *previewCache) get(key string) (AttachmentPreview, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	preview, ok := c.entries[key]
+	return preview, ok
+}
+
+func (c *previewCache) put(key string, preview AttachmentPreview) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]AttachmentPreview{}
+	}
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	if len(c.order) >= maxCachedPreviews {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[key] = preview
+	c.order = append(c.order, key)
+}
+
+// AttachmentPreview renders a preview of managed attachment content. size caps
+// the longer image edge in pixels and textBytes caps text previews; zero uses
+// the defaults. Media types other than images and text return 400.
+func (s *AttachmentService) AttachmentPreview(ctx context.Context, attachmentID string, size, textBytes int) (AttachmentPreview, error) {
+	content, err := s.OpenAttachmentContent(ctx, attachmentID)
+	if err != nil {
+		return AttachmentPreview{}, err
+	}
+	defer content.Reader.Close()
+
+	mediaType, _, err := mime.ParseMediaType(content.MediaType)
+	if err != nil {
+		mediaType = content.MediaType
+	}
+	var key string
+	var render func() (AttachmentPreview, error)
+	switch {
+	case mediaType == "image/png" || mediaType == "image/jpeg" || mediaType == "image/gif":
+		size = clampPreviewLimit(size, defaultPreviewImageSize, minPreviewImageSize, maxPreviewImageSize)
+		key = fmt.Sprintf("%s-image-%d", content.SHA256, size)
+		render = func() (AttachmentPreview, error) { return renderImagePreview(content.Reader, size) }
+	case isTextPreviewMediaType(mediaType):
+		textBytes = clampPreviewLimit(textBytes, defaultPreviewTextBytes, 1, maxPreviewTextBytes)
+		key = fmt.Sprintf("%s-text-%d", content.SHA256, textBytes)
+		render = func() (AttachmentPreview, error) { return renderTextPreview(content.Reader, mediaType, textBytes) }
+	default:
+		return AttachmentPreview{}, badRequestCode(fmt.Errorf("no preview for media type %s", mediaType), ErrCodeInvalidArgument)
+	}
+
+	if preview, ok := s.previews.get(key); ok {
+		return preview, nil
+	}
+	preview, err := render()
+	if err != nil {
+		return AttachmentPreview{}, err
+	}
+	preview.ETag = `"` + key + `"`
+	s.previews.put(key, preview)
+	return preview, nil
+}
+
+func clampPreviewLimit(value, def, lo, hi int) int {
+	if value <= 0 {
+		return def
+	}
+	return min(max(value, lo), hi)
+}
+
+func isTextPreviewMediaType(mediaType string) bool {
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/x-ndjson", "application/xml", "application/yaml", "application/toml":
+		return true
+	}
+	return false
+}
+
+// renderTextPreview returns up to limit bytes of text, cut at a rune boundary.
+// Markdown keeps its media type so the client can render it.
+func renderTextPreview(r io.Reader, mediaType string, limit int) (AttachmentPreview, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return AttachmentPreview{}, err
+	}
+	truncated := len(data) > limit
+	if truncated {
+		data = data[:limit]
+		for cut := 0; cut < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); cut++ {
+			data = data[:len(data)-1]
+		}
+	}
+	if mediaType != "text/markdown" {
+		mediaType = "text/plain"
+	}
+	return AttachmentPreview{Data: data, MediaType: mediaType + "; charset=utf-8", Truncated: truncated}, nil
+}
+
+// renderImagePreview decodes an image and encodes a PNG whose longer edge is
+// at most size pixels. Smaller images are re-encoded unscaled.
+func renderImagePreview(r io.ReadSeeker, size int) (AttachmentPreview, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return AttachmentPreview{}, badRequestCode(fmt.Errorf("decode image: %w", err), ErrCodeInvalidArgument)
+	}
+	if cfg.Width*cfg.Height > maxPreviewSourcePixels {
+		return AttachmentPreview{}, badRequestCode(fmt.Errorf("image is too large to preview (%dx%d)", cfg.Width, cfg.Height), ErrCodeInvalidArgument)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return AttachmentPreview{}, err
+	}
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return AttachmentPreview{}, badRequestCode(fmt.Errorf("decode image: %w", err), ErrCodeInvalidArgument)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	truncated := false
+	if width > size || height > size {
+		truncated = true
+		if width >= height {
+			width, height = size, max(1, height*size/bounds.Dx())
+		} else {
+			width, height = max(1, width*size/bounds.Dy()), size
+		}
+		src = scaleImage(src, width, height)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		return AttachmentPreview{}, err
+	}
+	return AttachmentPreview{Data: buf.Bytes(), MediaType: "image/png", Truncated: truncated}, nil
+}
+
+// scaleImage downscales src to width x height by averaging the source pixels
+// that fall into each destination pixel.
+func scaleImage(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	dst := image.NewRGBA64(image.Rect(0, 0, width, height))
+	for y := range height {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := range width {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"grns/internal/blobstore"
+	"grns/internal/models"
+)
+
+// Upload scan actions for infected content.
+const (
+	attachmentScanActionReject     = "reject"
+	attachmentScanActionQuarantine = "quarantine"
+)
+
+// Scan statuses recorded in attachment meta under attachmentScanMetaKey.
+const (
+	attachmentScanStatusClean       = "clean"
+	attachmentScanStatusQuarantined = "quarantined"
+)
+
+const (
+	attachmentScanMetaKey   = "scan"
+	attachmentScanTimeout   = 2 * time.Minute
+	maxAttachmentScanDetail = 512
+)
+
+// ContentScanner inspects uploaded content before it is attached.
+type ContentScanner interface {
+	// Name identifies the scanner in attachment meta.
+	Name() string
+	// Scan reads content and reports whether it was flagged. An error means the
+	// content could not be scanned, not that it is infected.
+	Scan(ctx context.Context, content io.Reader) (ScanResult, error)
+}
+
+// ScanResult is one scanner verdict.
+type ScanResult struct {
+	Infected bool
+	Detail   string
+}
+
+// newContentScanner builds the configured scanner, or nil when scanning is off.
+// A command takes precedence over a URL.
+func newContentScanner(command, url string) ContentScanner {
+	if argv := strings.Fields(command); len(argv) > 0 {
+		return commandScanner{argv: argv}
+	}
+	if url = strings.TrimSpace(url); url != "" {
+		return httpScanner{url: url, client: &http.Client{Timeout: attachmentScanTimeout}}
+	}
+	return nil
+}
+
+// commandScanner runs an external command with the content on stdin. Exit
+// status 0 means clean and 1 means infected, as with clamscan; anything else
+// is a scan failure.
+type commandScanner struct {
+	argv []string
+}
+
+func (commandScanner) Name() string { return "command" }
+
+func (c commandScanner) Scan(ctx context.Context, content io.Reader) (ScanResult, error) {
+	cmd := exec.CommandContext(ctx, c.argv[0], c.argv[1:]...)
+	cmd.Stdin = content
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+	if err == nil {
+		return ScanResult{}, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return ScanResult{Infected: true, Detail: scanDetail(output.String())}, nil
+	}
+	return ScanResult{}, fmt.Errorf("scan command failed: %w: %s", err, scanDetail(output.String()))
+}
+
+// httpScanner POSTs the content to a scanning service, which answers 200 with
+// {"infected": bool, "detail": "..."}.
+type httpScanner struct {
+	url    string
+	client *http.Client
+}
+
+func (httpScanner) Name() string { return "http" }
+
+func (h httpScanner) Scan(ctx context.Context, content io.Reader) (ScanResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, content)
+	if err != nil {
+		return ScanResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return ScanResult{}, fmt.Errorf("scan request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ScanResult{}, fmt.Errorf("scan service returned %s", resp.Status)
+	}
+	var verdict struct {
+		Infected bool   `json:"infected"`
+		Detail   string `json:"detail"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&verdict); err != nil {
+		return ScanResult{}, fmt.Errorf("decode scan response: %w", err)
+	}
+	return ScanResult{Infected: verdict.Infected, Detail: scanDetail(verdict.Detail)}, nil
+}
+
+func scanDetail(detail string) string {
+	detail = strings.TrimSpace(detail)
+	if len(detail) > maxAttachmentScanDetail {
+		detail = detail[:maxAttachmentScanDetail]
+	}
+	return detail
+}
+
+// ConfigureScanner sets the upload scanner and what happens to infected
+// content. A nil scanner turns scanning off; action defaults to reject.
+func (s *AttachmentService) ConfigureScanner(scanner ContentScanner, action string) {
+	if s == nil {
+		return
+	}
+	s.scanner = scanner
+	s.scanAction = attachmentScanActionReject
+	if strings.EqualFold(strings.TrimSpace(action), attachmentScanActionQuarantine) {
+		s.scanAction = attachmentScanActionQuarantine
+	}
+}
+
+// scanBlob runs the configured scanner over stored blob bytes. It returns the
+// meta entry to record, or nil when scanning is off. Rejected content is
+// discarded and reported as 422; scanner failures are 502 so that nothing
+// unscanned is attached.
+func (s *AttachmentService) scanBlob(ctx context.Context, putResult blobstore.BlobPutResult) (map[string]any, error) {
+	if s.scanner == nil {
+		return nil, nil
+	}
+	content, err := s.blobStore.Open(ctx, putResult.BlobKey)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	scanCtx, cancel := context.WithTimeout(ctx, attachmentScanTimeout)
+	defer cancel()
+	result, err := s.scanner.Scan(scanCtx, content)
+	if err != nil {
+		s.discardUnreferencedBlob(ctx, putResult)
+		return nil, scanFailed(err)
+	}
+
+	entry := map[string]any{
+		"status":     attachmentScanStatusClean,
+		"scanner":    s.scanner.Name(),
+		"scanned_at": time.Now().UTC().Format(time.RFC3339),
+	}
+	if !result.Infected {
+		return entry, nil
+	}
+	if s.scanAction != attachmentScanActionQuarantine {
+		s.discardUnreferencedBlob(ctx, putResult)
+		return nil, contentRejected(fmt.Errorf("content rejected by scanner: %s", result.Detail))
+	}
+	entry["status"] = attachmentScanStatusQuarantined
+	entry["detail"] = result.Detail
+	return entry, nil
+}
+
+// withScanMeta returns meta with the scan entry set, leaving the caller's map alone.
+func withScanMeta(meta map[string]any, scan map[string]any) map[string]any {
+	if scan == nil {
+		return meta
+	}
+	out := make(map[string]any, len(meta)+1)
+	maps.Copy(out, meta)
+	out[attachmentScanMetaKey] = scan
+	return out
+}
+
+// withoutScanMeta drops a client-supplied scan entry: the verdict is
+// server-owned, and a forged "clean" one must not stand in for a scan.
+func withoutScanMeta(meta map[string]any) map[string]any {
+	if _, ok := meta[attachmentScanMetaKey]; !ok {
+		return meta
+	}
+	out := maps.Clone(meta)
+	delete(out, attachmentScanMetaKey)
+	return out
+}
+
+// withBlobQuarantine marks meta quarantined when blob is, so that attaching
+// quarantined content again shows the same verdict as the original upload.
+func withBlobQuarantine(meta map[string]any, blob *models.Blob) map[string]any {
+	if blob == nil || blob.QuarantinedAt == nil || quarantinedScan(meta) {
+		return meta
+	}
+	return withScanMeta(meta, map[string]any{
+		"status":     attachmentScanStatusQuarantined,
+		"scanned_at": blob.QuarantinedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func quarantinedScan(meta map[string]any) bool {
+	scan, ok := meta[attachmentScanMetaKey].(map[string]any)
+	return ok && scan["status"] == attachmentScanStatusQuarantined
+}
+
+// attachmentQuarantined reports whether an upload scan quarantined the
+// attachment or any upload of the same content.
+func attachmentQuarantined(attachment *models.Attachment, blob *models.Blob) bool {
+	return quarantinedScan(attachment.Meta) || (blob != nil && blob.QuarantinedAt != nil)
+}
@@ -19,6 +19,8 @@ import (
 
 const (
 	defaultBlobGCBatchSize             = 500
+	defaultBlobUsageLimit              = 10
+	maxBlobUsageLimit                  = 100
 	attachmentAllowedMediaTypesEnvKey  = "GRNS_ATTACH_ALLOWED_MEDIA_TYPES"
 	attachmentRejectMismatchEnvKey     = "GRNS_ATTACH_REJECT_MEDIA_TYPE_MISMATCH"
 	fallbackAttachmentContentMediaType = "application/octet-stream"
@@ -34,14 +36,22 @@ type AttachmentService struct {
 	allowedMediaTypes map[string]struct{}
 	rejectMismatch    bool
 	gcBatchSize       int
+
+	scanner    ContentScanner
+	scanAction string
+
+	previews previewCache
 }
 
-// AttachmentContent describes managed attachment stream metadata.
+// AttachmentContent describes managed attachment stream metadata. SHA256 and
+// ModTime identify the blob for HTTP caching.
 type AttachmentContent struct {
-	Reader    io.ReadCloser
+	Reader    io.ReadSeekCloser
 	SizeBytes int64
 	MediaType string
 	Filename  string
+	SHA256    string
+	ModTime   time.Time
 }
 
 // BlobGCResult reports one GC run result.
@@ -53,6 +63,15 @@ type BlobGCResult struct {
 	DryRun         bool  `json:"dry_run"`
 }
 
+// BlobUsageReport summarizes blob storage, the bytes saved by sharing blobs
+// between attachments, and the largest blobs.
+type BlobUsageReport struct {
+	models.BlobUsageStats
+	DedupSavedBytes    int64              `json:"dedup_saved_bytes"`
+	AttachmentsPerBlob float64            `json:"attachments_per_blob"`
+	Largest            []models.BlobUsage `json:"largest_blobs"`
+}
+
 // NewAttachmentService constructs an AttachmentService.
 func NewAttachmentService(taskStore store.TaskServiceStore, attachmentStore store.AttachmentStore, blobStore blobstore.BlobStore, projectPrefix string) *AttachmentService {
 	svc := &AttachmentService{taskStore: taskStore, attachmentStore: attachmentStore, blobStore: blobStore, projectPrefix: projectPrefix}
@@ -101,6 +120,7 @@ type CreateManagedAttachmentInput struct {
 	MediaTypeSource   string
 	DeclaredMediaType string
 	SniffedMediaType  string
+	ExpectedSHA256    string
 	BlobID            string
 	Labels            []string
 	Meta              map[string]any
@@ -121,6 +141,16 @@ type CreateLinkAttachmentInput struct {
 	ExpiresAt       *time.Time
 }
 
+// UpdateAttachmentInput describes an attachment metadata patch. Nil fields are
+// left unchanged; ClearExpiresAt removes the expiry.
+type UpdateAttachmentInput struct {
+	Title          *string
+	Labels         *[]string
+	Meta           *map[string]any
+	ExpiresAt      *time.Time
+	ClearExpiresAt bool
+}
+
 // CreateManagedAttachment creates an attachment row pointing at an existing managed blob.
 func (s *AttachmentService) CreateManagedAttachment(ctx context.Context, taskID string, in CreateManagedAttachmentInput) (models.Attachment, error) {
 	var zero models.Attachment
@@ -138,6 +168,10 @@ func (s *AttachmentService) CreateManagedAttachment(ctx context.Context, taskID
 	if err := s.ensureTaskExists(ctx, taskID); err != nil {
 		return zero, err
 	}
+	blob, err := s.attachmentStore.GetBlob(ctx, strings.TrimSpace(in.BlobID))
+	if err != nil {
+		return zero, err
+	}
 
 	kind, err := models.ParseAttachmentKind(in.Kind)
 	if err != nil {
@@ -172,7 +206,7 @@ func (s *AttachmentService) CreateManagedAttachment(ctx context.Context, taskID
 		MediaType:       mediaType,
 		MediaTypeSource: mediaTypeSource,
 		BlobID:          strings.TrimSpace(in.BlobID),
-		Meta:            in.Meta,
+		Meta:            withBlobQuarantine(withoutScanMeta(in.Meta), blob),
 		Labels:          labels,
 		CreatedAt:       now,
 		UpdatedAt:       now,
@@ -226,6 +260,10 @@ func (s *AttachmentService) CreateManagedAttachmentFromReader(ctx context.Contex
 	if err != nil {
 		return zero, err
 	}
+	expectedSHA256 := strings.ToLower(strings.TrimSpace(in.ExpectedSHA256))
+	if expectedSHA256 != "" && !validateSHA256Hex(expectedSHA256) {
+		return zero, badRequestCode(fmt.Errorf("content sha256 must be 64 hex characters"), ErrCodeInvalidArgument)
+	}
 
 	now := time.Now().UTC()
 	if err := validateAttachmentExpiry(in.ExpiresAt, now); err != nil {
@@ -246,7 +284,7 @@ func (s *AttachmentService) CreateManagedAttachmentFromReader(ctx context.Contex
 		Filename:        strings.TrimSpace(in.Filename),
 		MediaType:       mediaType,
 		MediaTypeSource: mediaTypeSource,
-		Meta:            in.Meta,
+		Meta:            withoutScanMeta(in.Meta),
 		Labels:          labels,
 		CreatedAt:       now,
 		UpdatedAt:       now,
@@ -257,13 +295,33 @@ func (s *AttachmentService) CreateManagedAttachmentFromReader(ctx context.Contex
 	if err != nil {
 		return zero, err
 	}
-
-	_, err = s.attachmentStore.CreateManagedAttachmentWithBlob(ctx, &models.Blob{
+	if expectedSHA256 != "" && expectedSHA256 != putResult.SHA256 {
+		s.discardUnreferencedBlob(ctx, putResult)
+		return zero, badRequestCode(fmt.Errorf("content checksum mismatch"), ErrCodeInvalidArgument)
+	}
+	scan, err := s.scanBlob(ctx, putResult)
+	if err != nil {
+		return zero, err
+	}
+	attachment.Meta = withScanMeta(attachment.Meta, scan)
+	blob := &models.Blob{
 		SHA256:         putResult.SHA256,
 		SizeBytes:      putResult.SizeBytes,
 		StorageBackend: "local_cas",
 		BlobKey:        putResult.BlobKey,
-	}, attachment)
+	}
+	if quarantinedScan(attachment.Meta) {
+		blob.QuarantinedAt = &now
+	} else {
+		// The same content may have been quarantined by an earlier upload.
+		existing, err := s.attachmentStore.GetBlobBySHA256(ctx, putResult.SHA256)
+		if err != nil {
+			return zero, err
+		}
+		attachment.Meta = withBlobQuarantine(attachment.Meta, existing)
+	}
+
+	_, err = s.attachmentStore.CreateManagedAttachmentWithBlob(ctx, blob, attachment)
 	if err != nil {
 		if isUniqueConstraint(err) {
 			return zero, conflictCode(fmt.Errorf("attachment already exists"), ErrCodeConflict)
@@ -281,6 +339,65 @@ func (s *AttachmentService) CreateManagedAttachmentFromReader(ctx context.Contex
 	return *stored, nil
 }
 
+// PutBlob stores content as a managed blob without attaching it to a task, so
+// an import can reference it by sha256. An existing blob with the same digest
+// is returned unchanged.
+func (s *AttachmentService) PutBlob(ctx context.Context, content io.Reader, expectedSHA256 string) (models.Blob, error) {
+	var zero models.Blob
+	if content == nil {
+		return zero, badRequestCode(fmt.Errorf("content is required"), ErrCodeMissingRequired)
+	}
+	if s == nil || s.attachmentStore == nil || s.blobStore == nil {
+		return zero, internalError(fmt.Errorf("attachment service is not configured"))
+	}
+	expectedSHA256 = strings.ToLower(strings.TrimSpace(expectedSHA256))
+	if expectedSHA256 != "" && !validateSHA256Hex(expectedSHA256) {
+		return zero, badRequestCode(fmt.Errorf("content sha256 must be 64 hex characters"), ErrCodeInvalidArgument)
+	}
+
+	putResult, err := s.blobStore.Put(ctx, content)
+	if err != nil {
+		return zero, err
+	}
+	if expectedSHA256 != "" && expectedSHA256 != putResult.SHA256 {
+		s.discardUnreferencedBlob(ctx, putResult)
+		return zero, badRequestCode(fmt.Errorf("content checksum mismatch"), ErrCodeInvalidArgument)
+	}
+	// A bare blob has no attachment to quarantine, so flagged content is always rejected.
+	scan, err := s.scanBlob(ctx, putResult)
+	if err != nil {
+		return zero, err
+	}
+	if scan != nil && scan["status"] == attachmentScanStatusQuarantined {
+		s.discardUnreferencedBlob(ctx, putResult)
+		return zero, contentRejected(fmt.Errorf("content rejected by scanner: %s", scan["detail"]))
+	}
+
+	blob, err := s.attachmentStore.UpsertBlob(ctx, &models.Blob{
+		SHA256:         putResult.SHA256,
+		SizeBytes:      putResult.SizeBytes,
+		StorageBackend: "local_cas",
+		BlobKey:        putResult.BlobKey,
+	})
+	if err != nil {
+		return zero, err
+	}
+	if blob == nil {
+		return zero, internalError(fmt.Errorf("blob not found after upsert"))
+	}
+	return *blob, nil
+}
+
+// discardUnreferencedBlob removes a just-written blob unless the CAS already tracked it,
+// since content-addressed keys may be shared with existing attachments.
+func (s *AttachmentService) discardUnreferencedBlob(ctx context.Context, putResult blobstore.BlobPutResult) {
+	existing, err := s.attachmentStore.GetBlobBySHA256(ctx, putResult.SHA256)
+	if err != nil || existing != nil {
+		return
+	}
+	_ = s.blobStore.Delete(ctx, putResult.BlobKey)
+}
+
 // CreateLinkAttachment creates an attachment pointing to an external URL or repo path.
 func (s *AttachmentService) CreateLinkAttachment(ctx context.Context, taskID string, in CreateLinkAttachmentInput) (models.Attachment, error) {
 	var zero models.Attachment
@@ -363,7 +480,7 @@ func (s *AttachmentService) CreateLinkAttachment(ctx context.Context, taskID str
 		MediaTypeSource: mediaTypeSource,
 		ExternalURL:     externalURL,
 		RepoPath:        repoPath,
-		Meta:            in.Meta,
+		Meta:            withoutScanMeta(in.Meta),
 		Labels:          labels,
 		CreatedAt:       now,
 		UpdatedAt:       now,
@@ -436,6 +553,61 @@ func (s *AttachmentService) GetAttachment(ctx context.Context, id string) (model
 	return *attachment, nil
 }
 
+// UpdateAttachment changes the title, labels, meta, or expiry of one attachment.
+func (s *AttachmentService) UpdateAttachment(ctx context.Context, id string, in UpdateAttachmentInput) (models.Attachment, error) {
+	var zero models.Attachment
+	if s == nil || s.attachmentStore == nil {
+		return zero, internalError(fmt.Errorf("attachment service is not configured"))
+	}
+	if in.Title == nil && in.Labels == nil && in.Meta == nil && in.ExpiresAt == nil && !in.ClearExpiresAt {
+		return zero, badRequestCode(fmt.Errorf("no fields to update"), ErrCodeMissingRequired)
+	}
+	if in.ExpiresAt != nil && in.ClearExpiresAt {
+		return zero, badRequestCode(fmt.Errorf("expires_at and clear_expires_at are mutually exclusive"), ErrCodeInvalidArgument)
+	}
+
+	existing, err := s.GetAttachment(ctx, id)
+	if err != nil {
+		return zero, err
+	}
+
+	update := store.AttachmentUpdate{UpdatedAt: time.Now().UTC()}
+	if in.Meta != nil {
+		// The upload scan verdict is server-owned and survives meta replacement.
+		meta := withoutScanMeta(*in.Meta)
+		if scan, ok := existing.Meta[attachmentScanMetaKey].(map[string]any); ok {
+			meta = withScanMeta(meta, scan)
+		}
+		update.Meta = &meta
+	}
+	if in.Title != nil {
+		title := strings.TrimSpace(*in.Title)
+		update.Title = &title
+	}
+	if in.Labels != nil {
+		labels, err := normalizeLabels(*in.Labels)
+		if err != nil {
+			return zero, badRequest(err)
+		}
+		update.Labels = &labels
+	}
+	if in.ExpiresAt != nil {
+		if err := validateAttachmentExpiry(in.ExpiresAt, existing.CreatedAt); err != nil {
+			return zero, err
+		}
+		expiresAt := in.ExpiresAt.UTC()
+		update.ExpiresAt = &expiresAt
+	}
+	if in.ClearExpiresAt {
+		update.ExpiresAt = &time.Time{}
+	}
+
+	if err := s.attachmentStore.UpdateAttachment(ctx, existing.ID, update); err != nil {
+		return zero, err
+	}
+	return s.GetAttachment(ctx, existing.ID)
+}
+
 // DeleteAttachment removes one attachment row.
 func (s *AttachmentService) DeleteAttachment(ctx context.Context, id string) error {
 	if s == nil || s.attachmentStore == nil {
@@ -495,6 +667,9 @@ func (s *AttachmentService) OpenAttachmentContent(ctx context.Context, attachmen
 	if err != nil {
 		return nil, err
 	}
+	if attachmentQuarantined(attachment, blob) {
+		return nil, contentQuarantined(fmt.Errorf("attachment content is quarantined"))
+	}
 	if blob == nil {
 		return nil, notFoundCode(fmt.Errorf("attachment content not found"), ErrCodeAttachmentNotFound)
 	}
@@ -516,7 +691,14 @@ func (s *AttachmentService) OpenAttachmentContent(ctx context.Context, attachmen
 		filename = attachment.ID
 	}
 
-	return &AttachmentContent{Reader: rc, SizeBytes: blob.SizeBytes, MediaType: mediaType, Filename: filename}, nil
+	return &AttachmentContent{
+		Reader:    rc,
+		SizeBytes: blob.SizeBytes,
+		MediaType: mediaType,
+		Filename:  filename,
+		SHA256:    blob.SHA256,
+		ModTime:   blob.CreatedAt,
+	}, nil
 }
 
 // GCBlobs sweeps unreferenced blobs and optionally deletes them.
@@ -574,6 +756,80 @@ func (s *AttachmentService) GCBlobs(ctx context.Context, batchSize int, apply bo
 	}
 }
 
+// BlobUsage reports blob counts, bytes and dedup savings with the limit largest
+// blobs. limit defaults to 10 and is capped at 100.
+func (s *AttachmentService) BlobUsage(ctx context.Context, limit int) (BlobUsageReport, error) {
+	report := BlobUsageReport{}
+	if s == nil || s.attachmentStore == nil {
+		return report, internalError(fmt.Errorf("attachment service is not configured"))
+	}
+	if limit < 0 {
+		return report, badRequestCode(fmt.Errorf("limit must be >= 0"), ErrCodeInvalidArgument)
+	}
+	if limit == 0 {
+		limit = defaultBlobUsageLimit
+	}
+	limit = min(limit, maxBlobUsageLimit)
+
+	stats, err := s.attachmentStore.BlobUsageStats(ctx)
+	if err != nil {
+		return report, err
+	}
+	largest, err := s.attachmentStore.ListLargestBlobs(ctx, limit)
+	if err != nil {
+		return report, err
+	}
+	report.BlobUsageStats = stats
+	report.Largest = largest
+	// Only referenced blobs are shared; unreferenced ones are GC candidates.
+	if referenced := stats.BlobCount - stats.UnreferencedCount; referenced > 0 {
+		report.DedupSavedBytes = stats.LogicalBytes - (stats.TotalBytes - stats.UnreferencedBytes)
+		report.AttachmentsPerBlob = float64(stats.ManagedAttachmentCount) / float64(referenced)
+	}
+	return report, nil
+}
+
+// Dangling attachment cleanup actions.
+const (
+	danglingAttachmentActionDelete = "delete"
+	danglingAttachmentActionMark   = "mark"
+)
+
+// DanglingAttachments lists managed attachments whose blob row is missing.
+func (s *AttachmentService) DanglingAttachments(ctx context.Context) ([]models.Attachment, error) {
+	if s == nil || s.attachmentStore == nil {
+		return nil, internalError(fmt.Errorf("attachment service is not configured"))
+	}
+	return s.attachmentStore.ListAttachmentsWithMissingBlob(ctx)
+}
+
+// RepairDanglingAttachments deletes or marks dangling attachments and returns the affected rows.
+// With apply=false it only reports what would be affected.
+func (s *AttachmentService) RepairDanglingAttachments(ctx context.Context, action string, apply bool) ([]models.Attachment, error) {
+	action = strings.ToLower(strings.TrimSpace(action))
+	if action != danglingAttachmentActionDelete && action != danglingAttachmentActionMark {
+		return nil, badRequestCode(fmt.Errorf("action must be %s or %s", danglingAttachmentActionDelete, danglingAttachmentActionMark), ErrCodeInvalidArgument)
+	}
+	attachments, err := s.DanglingAttachments(ctx)
+	if err != nil || !apply || len(attachments) == 0 {
+		return attachments, err
+	}
+
+	if action == danglingAttachmentActionMark {
+		ids := make([]string, 0, len(attachments))
+		for _, attachment := range attachments {
+			ids = append(ids, attachment.ID)
+		}
+		return attachments, s.attachmentStore.MarkAttachmentsBlobMissing(ctx, ids, time.Now().UTC())
+	}
+	for _, attachment := range attachments {
+		if err := s.attachmentStore.DeleteAttachment(ctx, "", attachment.ID); err != nil {
+			return nil, err
+		}
+	}
+	return attachments, nil
+}
+
 func (s *AttachmentService) ensureTaskExists(ctx context.Context, id string) error {
 	project, err := s.project(ctx)
 	if err != nil {
@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"io"
 	"path/filepath"
@@ -266,13 +268,160 @@ func TestAttachmentServiceGCBlobs_TerminatesWhenDeletesFail(t *testing.T) {
 	}
 }
 
+func TestAttachmentServiceBlobUsage(t *testing.T) {
+	svc, st := newAttachmentServiceForTest(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	task := &models.Task{ID: "gr-bu11", Title: "Blob usage target", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	upload := func(content string) models.Attachment {
+		t.Helper()
+		attachment, err := svc.CreateManagedAttachmentFromReader(ctx, task.ID, CreateManagedAttachmentInput{Kind: string(models.AttachmentKindArtifact)}, strings.NewReader(content))
+		if err != nil {
+			t.Fatalf("create attachment: %v", err)
+		}
+		return attachment
+	}
+	upload("shared content")
+	upload("shared content")
+	upload("shared content")
+	upload("small")
+	unique := upload("orphan")
+	if err := svc.DeleteAttachment(ctx, unique.ID); err != nil {
+		t.Fatalf("delete attachment: %v", err)
+	}
+
+	report, err := svc.BlobUsage(ctx, 1)
+	if err != nil {
+		t.Fatalf("blob usage: %v", err)
+	}
+	shared := int64(len("shared content"))
+	if report.BlobCount != 3 || report.TotalBytes != shared+5+int64(len("orphan")) {
+		t.Fatalf("unexpected blob totals: %#v", report.BlobUsageStats)
+	}
+	if report.UnreferencedCount != 1 || report.UnreferencedBytes != int64(len("orphan")) {
+		t.Fatalf("unexpected unreferenced totals: %#v", report.BlobUsageStats)
+	}
+	if report.ManagedAttachmentCount != 4 || report.DedupSavedBytes != 2*shared || report.AttachmentsPerBlob != 2 {
+		t.Fatalf("unexpected dedup stats: %#v", report)
+	}
+	if len(report.Largest) != 1 || report.Largest[0].SizeBytes != shared || report.Largest[0].AttachmentCount != 3 {
+		t.Fatalf("unexpected largest blobs: %#v", report.Largest)
+	}
+
+	if _, err := svc.BlobUsage(ctx, -1); httpStatusFromError(err) != 400 {
+		t.Fatalf("expected 400 for a negative limit, got %v", err)
+	}
+}
+
+func TestAttachmentServiceScanHook(t *testing.T) {
+	svc, st := newAttachmentServiceForTest(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	task := &models.Task{ID: "gr-sc11", Title: "Scan target", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	upload := func(content string) (models.Attachment, error) {
+		return svc.CreateManagedAttachmentFromReader(ctx, task.ID, CreateManagedAttachmentInput{
+			Kind: string(models.AttachmentKindArtifact),
+			Meta: map[string]any{"origin": "ci"},
+		}, strings.NewReader(content))
+	}
+	scanner := &fakeContentScanner{flag: "EICAR"}
+
+	svc.ConfigureScanner(scanner, "reject")
+	clean, err := upload("plain log output")
+	if err != nil {
+		t.Fatalf("upload clean content: %v", err)
+	}
+	if scan, _ := clean.Meta["scan"].(map[string]any); scan["status"] != "clean" || scan["scanner"] != "fake" || clean.Meta["origin"] != "ci" {
+		t.Fatalf("expected clean scan meta alongside caller meta, got %#v", clean.Meta)
+	}
+
+	if _, err := upload("EICAR test string"); httpStatusFromError(err) != 422 {
+		t.Fatalf("expected 422 for rejected content, got %v", err)
+	}
+	sum := sha256.Sum256([]byte("EICAR test string"))
+	if blob, err := st.GetBlobBySHA256(ctx, hex.EncodeToString(sum[:])); err != nil || blob != nil {
+		t.Fatalf("expected rejected blob to be discarded, got %#v (%v)", blob, err)
+	}
+
+	svc.ConfigureScanner(scanner, "quarantine")
+	quarantined, err := upload("EICAR test string")
+	if err != nil {
+		t.Fatalf("upload quarantined content: %v", err)
+	}
+	if scan, _ := quarantined.Meta["scan"].(map[string]any); scan["status"] != "quarantined" || scan["detail"] != "found EICAR" {
+		t.Fatalf("expected quarantined scan meta, got %#v", quarantined.Meta)
+	}
+	if _, err := svc.OpenAttachmentContent(ctx, quarantined.ID); httpStatusFromError(err) != 403 {
+		t.Fatalf("expected 403 for quarantined content, got %v", err)
+	}
+	emptyMeta := map[string]any{}
+	updated, err := svc.UpdateAttachment(ctx, quarantined.ID, UpdateAttachmentInput{Meta: &emptyMeta})
+	if err != nil {
+		t.Fatalf("update attachment meta: %v", err)
+	}
+	if !attachmentQuarantined(&updated, nil) {
+		t.Fatalf("expected meta replacement to keep the scan verdict, got %#v", updated.Meta)
+	}
+
+	scanner.err = errors.New("scanner offline")
+	if _, err := upload("another file"); httpStatusFromError(err) != 502 {
+		t.Fatalf("expected 502 when the scanner fails, got %v", err)
+	}
+}
+
+func TestCommandScannerExitStatus(t *testing.T) {
+	ctx := context.Background()
+	if result, err := newContentScanner("true", "").Scan(ctx, strings.NewReader("x")); err != nil || result.Infected {
+		t.Fatalf("expected exit 0 to be clean, got %#v (%v)", result, err)
+	}
+	if result, err := newContentScanner("false", "").Scan(ctx, strings.NewReader("x")); err != nil || !result.Infected {
+		t.Fatalf("expected exit 1 to flag content, got %#v (%v)", result, err)
+	}
+	if _, err := newContentScanner("sh -c exit2", "").Scan(ctx, strings.NewReader("x")); err == nil {
+		t.Fatal("expected other exit statuses to fail the scan")
+	}
+	if newContentScanner(" ", "") != nil {
+		t.Fatal("expected no scanner without a command or url")
+	}
+}
+
+// fakeContentScanner flags content containing flag, or fails with err.
+type fakeContentScanner struct {
+	flag string
+	err  error
+}
+
+func (*fakeContentScanner) Name() string { return "fake" }
+
+func (f *fakeContentScanner) Scan(_ context.Context, content io.Reader) (ScanResult, error) {
+	if f.err != nil {
+		return ScanResult{}, f.err
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return ScanResult{}, err
+	}
+	if strings.Contains(string(data), f.flag) {
+		return ScanResult{Infected: true, Detail: "found " + f.flag}, nil
+	}
+	return ScanResult{}, nil
+}
+
 type failingDeleteBlobStore struct{}
 
 func (failingDeleteBlobStore) Put(context.Context, io.Reader) (blobstore.BlobPutResult, error) {
 	return blobstore.BlobPutResult{}, errors.New("not implemented")
 }
 
-func (failingDeleteBlobStore) Open(context.Context, string) (io.ReadCloser, error) {
+func (failingDeleteBlobStore) Open(context.Context, string) (io.ReadSeekCloser, error) {
 	return nil, errors.New("not implemented")
 }
 
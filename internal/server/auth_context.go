@@ -12,6 +12,7 @@ type authRequiredContextKey struct{}
 type authPrincipal struct {
 	AuthType string
 	User     *store.AuthUser
+	Token    *store.APIToken
 }
 
 func contextWithAuthPrincipal(ctx context.Context, principal authPrincipal) context.Context {
@@ -37,3 +38,22 @@ func authRequiredFromContext(ctx context.Context) (bool, bool) {
 	required, ok := ctx.Value(authRequiredContextKey{}).(bool)
 	return required, ok
 }
+
+type actorContextKey struct{}
+
+func contextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// actorFromContext names who is performing a request: the session user when
+// authenticated by cookie, otherwise the caller-declared X-Actor header.
+func actorFromContext(ctx context.Context) string {
+	if principal, ok := authPrincipalFromContext(ctx); ok && principal.User != nil {
+		return principal.User.Username
+	}
+	if ctx == nil {
+		return ""
+	}
+	actor, _ := ctx.Value(actorContextKey{}).(string)
+	return actor
+}
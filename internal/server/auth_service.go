@@ -31,6 +31,7 @@ var (
 // AuthService encapsulates browser auth operations backed by the store.
 type AuthService struct {
 	store                store.AuthStore
+	tokens               store.APITokenStore
 	sessionTTL           time.Duration
 	enabledUsersCacheTTL time.Duration
 
@@ -38,6 +39,9 @@ type AuthService struct {
 	enabledUsersCached   bool
 	enabledUsersValue    bool
 	enabledUsersCachedAt time.Time
+	activeTokensCached   bool
+	activeTokensValue    bool
+	activeTokensCachedAt time.Time
 }
 
 type authLoginResult struct {
@@ -61,6 +65,13 @@ func (a *AuthService) AuthRequired(ctx context.Context, apiTokenConfigured, requ
 	if apiTokenConfigured {
 		return true, nil
 	}
+	hasTokens, err := a.hasActiveAPITokens(ctx, now)
+	if err != nil {
+		return false, err
+	}
+	if hasTokens {
+		return true, nil
+	}
 	if !requireAuthWithUsers {
 		return false, nil
 	}
@@ -114,6 +125,38 @@ func (a *AuthService) setCachedEnabledUsers(now time.Time, value bool) {
 	a.enabledUsersCachedAt = now
 }
 
+// hasActiveAPITokens reports whether any unexpired named API token exists;
+// issuing the first token turns on API auth.
+func (a *AuthService) hasActiveAPITokens(ctx context.Context, now time.Time) (bool, error) {
+	if a == nil || a.tokens == nil {
+		return false, nil
+	}
+
+	if a.enabledUsersCacheTTL > 0 {
+		a.mu.Lock()
+		if a.activeTokensCached && now.Sub(a.activeTokensCachedAt) <= a.enabledUsersCacheTTL {
+			value := a.activeTokensValue
+			a.mu.Unlock()
+			return value, nil
+		}
+		a.mu.Unlock()
+	}
+
+	count, err := a.tokens.CountActiveAPITokens(ctx, now)
+	if err != nil {
+		return false, err
+	}
+	value := count > 0
+	if a.enabledUsersCacheTTL > 0 {
+		a.mu.Lock()
+		a.activeTokensCached = true
+		a.activeTokensValue = value
+		a.activeTokensCachedAt = now
+		a.mu.Unlock()
+	}
+	return value, nil
+}
+
 func (a *AuthService) InvalidateAuthRequiredCache() {
 	if a == nil {
 		return
@@ -123,6 +166,9 @@ func (a *AuthService) InvalidateAuthRequiredCache() {
 	a.enabledUsersCached = false
 	a.enabledUsersCachedAt = time.Time{}
 	a.enabledUsersValue = false
+	a.activeTokensCached = false
+	a.activeTokensCachedAt = time.Time{}
+	a.activeTokensValue = false
 }
 
 func (a *AuthService) Login(ctx context.Context, username, password string, now time.Time) (*authLoginResult, error) {
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	backupFilePrefix     = "grns-"
+	backupFileSuffix     = ".db"
+	backupFileTimeFormat = "20060102T150405.000Z"
+	backupFileMode       = 0o600
+	backupDirMode        = 0o700
+)
+
+// backupFileName names a snapshot taken at now. Names sort chronologically.
+func backupFileName(now time.Time) string {
+	return backupFilePrefix + now.UTC().Format(backupFileTimeFormat) + backupFileSuffix
+}
+
+// backupToDir snapshots under a temporary name and renames it into place, so
+// the backup dir never holds a partial file under a final name. Older
+// snapshots beyond backupKeepLast are pruned afterwards.
+func (s *Server) backupToDir(ctx context.Context, now time.Time) (string, int64, error) {
+	if err := os.MkdirAll(s.backupDir, backupDirMode); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(s.backupDir, backupFileName(now))
+	partial := path + ".partial"
+	if err := s.backups.BackupTo(ctx, partial); err != nil {
+		return "", 0, err
+	}
+	if err := os.Chmod(partial, backupFileMode); err != nil {
+		_ = os.Remove(partial)
+		return "", 0, err
+	}
+	if err := os.Rename(partial, path); err != nil {
+		_ = os.Remove(partial)
+		return "", 0, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	createdAt := now.UTC()
+	s.lastBackupAt.Store(&createdAt)
+	s.log().Info("backup written", "path", path, "size_bytes", info.Size())
+	if err := s.pruneBackups(); err != nil {
+		s.log().Warn("backup retention failed", "dir", s.backupDir, "error", err)
+	}
+	return path, info.Size(), nil
+}
+
+// pruneBackups removes the oldest snapshots so at most backupKeepLast remain.
+// Zero keeps everything.
+func (s *Server) pruneBackups() error {
+	if s.backupKeepLast <= 0 {
+		return nil
+	}
+	names, err := listBackupFiles(s.backupDir)
+	if err != nil {
+		return err
+	}
+	if len(names) <= s.backupKeepLast {
+		return nil
+	}
+	for _, name := range names[:len(names)-s.backupKeepLast] {
+		path := filepath.Join(s.backupDir, name)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		s.log().Debug("backup pruned", "path", path)
+	}
+	return nil
+}
+
+// listBackupFiles returns snapshot file names in dir, oldest first.
+func listBackupFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, backupFilePrefix) && strings.HasSuffix(name, backupFileSuffix) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// latestBackupTime reports when the newest snapshot in dir was taken, so
+// /v1/info survives restarts.
+func latestBackupTime(dir string) *time.Time {
+	names, err := listBackupFiles(dir)
+	if err != nil || len(names) == 0 {
+		return nil
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(names[len(names)-1], backupFilePrefix), backupFileSuffix)
+	parsed, err := time.Parse(backupFileTimeFormat, stamp)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+func (s *Server) runScheduledBackups(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if _, _, err := s.backupToDir(context.Background(), time.Now().UTC()); err != nil {
+				s.log().Error("scheduled backup failed", "dir", s.backupDir, "error", err)
+			}
+		}
+	}
+}
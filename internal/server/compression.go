@@ -0,0 +1,120 @@
+package server
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const encodingGzip = "gzip"
+
+// compressibleReadRoutes are the project-scoped GET routes, relative to
+// /v1/projects/{project}/, whose responses can be large enough to compress.
+var compressibleReadRoutes = map[string]bool{
+	"tasks":       true,
+	"tasks/ready": true,
+	"tasks/stale": true,
+	"export":      true,
+}
+
+func isCompressibleRoute(r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, "/v1/projects/")
+	if !ok {
+		return false
+	}
+	_, route, ok := strings.Cut(rest, "/")
+	return ok && compressibleReadRoutes[route]
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows encoding.
+// A q=0 weight refuses it.
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), encoding) {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			weight, err := strconv.ParseFloat(q, 64)
+			return err == nil && weight > 0
+		}
+		return true
+	}
+	return false
+}
+
+// withCompression gzips list and export responses for clients that send
+// Accept-Encoding: gzip. Error responses are left uncompressed.
+func (s *Server) withCompression(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isCompressibleRoute(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsEncoding(r.Header.Get("Accept-Encoding"), encodingGzip) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressResponseWriter{ResponseWriter: w}
+		defer func() {
+			if err := cw.Close(); err != nil {
+				s.log().Debug("close compressed response", "method", r.Method, "path", r.URL.Path, "error", err)
+			}
+		}()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// compressResponseWriter decides on the first WriteHeader whether to gzip:
+// only 200 responses are compressed.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+func (c *compressResponseWriter) WriteHeader(status int) {
+	if c.wroteHeader {
+		return
+	}
+	c.wroteHeader = true
+	if status == http.StatusOK && c.Header().Get("Content-Encoding") == "" {
+		c.Header().Set("Content-Encoding", encodingGzip)
+		c.Header().Del("Content-Length")
+		c.gz = gzip.NewWriter(c.ResponseWriter)
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *compressResponseWriter) Write(p []byte) (int, error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.gz != nil {
+		return c.gz.Write(p)
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+// Flush pushes buffered compressed bytes through, so streamed exports keep
+// arriving record by record.
+func (c *compressResponseWriter) Flush() {
+	if c.gz != nil {
+		_ = c.gz.Flush()
+	}
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (c *compressResponseWriter) Close() error {
+	if c.gz == nil {
+		return nil
+	}
+	return c.gz.Close()
+}
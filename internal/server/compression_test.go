@@ -0,0 +1,68 @@
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"grns/internal/api"
+)
+
+func TestCompressionGzipsListWhenAccepted(t *testing.T) {
+	srv := newListTestServer(t)
+	if _, err := srv.service.Create(context.Background(), api.TaskCreateRequest{ID: "gr-cz01", Title: "compressed"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip;q=0.8")
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: %d (%s)", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", got)
+	}
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	var tasks []api.TaskResponse
+	if err := json.Unmarshal(body, &tasks); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "gr-cz01" {
+		t.Fatalf("unexpected tasks: %+v", tasks)
+	}
+}
+
+func TestCompressionSkipsUnacceptedAndErrors(t *testing.T) {
+	srv := newListTestServer(t)
+
+	for _, header := range []string{"", "identity", "gzip;q=0"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks", nil)
+		req.Header.Set("Accept-Encoding", header)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if got := w.Header().Get("Content-Encoding"); got != "" {
+			t.Fatalf("Accept-Encoding %q: expected no encoding, got %q", header, got)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks?priority=bogus", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || w.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected plain 400, got %d with encoding %q", w.Code, w.Header().Get("Content-Encoding"))
+	}
+}
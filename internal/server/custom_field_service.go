@@ -0,0 +1,215 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+// CustomFieldService manages the per-project schema for Task.Custom.
+type CustomFieldService struct {
+	store         store.CustomFieldStore
+	projectPrefix string
+}
+
+// NewCustomFieldService constructs a CustomFieldService.
+func NewCustomFieldService(fieldStore store.CustomFieldStore, projectPrefix string) *CustomFieldService {
+	return &CustomFieldService{store: fieldStore, projectPrefix: projectPrefix}
+}
+
+// Put creates or replaces one custom field definition.
+func (s *CustomFieldService) Put(ctx context.Context, name string, req api.CustomFieldRequest) (models.CustomField, error) {
+	project, name, err := s.scope(ctx, name)
+	if err != nil {
+		return models.CustomField{}, err
+	}
+	fieldType, err := models.ParseCustomFieldType(req.Type)
+	if err != nil {
+		return models.CustomField{}, badRequestCode(err, ErrCodeInvalidCustomField)
+	}
+	values := make([]string, 0, len(req.Values))
+	for _, value := range req.Values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return models.CustomField{}, badRequestCode(fmt.Errorf("enum values must not be empty"), ErrCodeInvalidCustomField)
+		}
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	if len(values) > 0 && fieldType != models.CustomFieldString {
+		return models.CustomField{}, badRequestCode(fmt.Errorf("enum values require type string"), ErrCodeInvalidCustomField)
+	}
+
+	now := time.Now().UTC()
+	field := models.CustomField{
+		Project:   project,
+		Name:      name,
+		Type:      string(fieldType),
+		Required:  req.Required,
+		Values:    values,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.PutCustomField(ctx, &field); err != nil {
+		return models.CustomField{}, err
+	}
+	fields, err := s.store.ListCustomFields(ctx, project)
+	if err != nil {
+		return models.CustomField{}, err
+	}
+	for _, stored := range fields {
+		if stored.Name == name {
+			return stored, nil
+		}
+	}
+	return field, nil
+}
+
+// List returns the project's custom field definitions ordered by name.
+func (s *CustomFieldService) List(ctx context.Context) ([]models.CustomField, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.ListCustomFields(ctx, project)
+}
+
+// Delete removes one custom field definition. Values already stored on tasks are kept.
+func (s *CustomFieldService) Delete(ctx context.Context, name string) error {
+	project, name, err := s.scope(ctx, name)
+	if err != nil {
+		return err
+	}
+	found, err := s.store.DeleteCustomField(ctx, project, name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notFoundCode(fmt.Errorf("custom field not found"), ErrCodeCustomFieldNotFound)
+	}
+	return nil
+}
+
+func (s *CustomFieldService) scope(ctx context.Context, name string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", badRequestCode(fmt.Errorf("name is required"), ErrCodeMissingRequired)
+	}
+	if !validateCustomFieldName(name) {
+		return "", "", badRequestCode(fmt.Errorf("invalid custom field name"), ErrCodeInvalidCustomField)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return project, name, nil
+}
+
+func (s *CustomFieldService) project(ctx context.Context) (string, error) {
+	if project, ok := projectFromContext(ctx); ok {
+		return project, nil
+	}
+	return normalizePrefix(s.projectPrefix)
+}
+
+// applyCustomFieldSchema checks custom against the project's field definitions
+// and returns it with string inputs coerced to the declared number or bool type.
+// Unknown keys are rejected so typos cannot slip in. With requireAll, every
+// required field must be present. An empty schema accepts any map unchanged.
+func applyCustomFieldSchema(fields []models.CustomField, custom map[string]any, requireAll bool) (map[string]any, error) {
+	if len(fields) == 0 {
+		return custom, nil
+	}
+	byName := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+
+	keys := make([]string, 0, len(custom))
+	for key := range custom {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var out map[string]any
+	if custom != nil {
+		out = make(map[string]any, len(custom))
+	}
+	for _, key := range keys {
+		field, ok := byName[key]
+		if !ok {
+			return nil, badRequestCode(fmt.Errorf("unknown custom field %q", key), ErrCodeInvalidCustomField)
+		}
+		value := custom[key]
+		if value == nil {
+			if field.Required && requireAll {
+				return nil, badRequestCode(fmt.Errorf("custom field %q is required", key), ErrCodeMissingRequired)
+			}
+			continue
+		}
+		coerced, err := coerceCustomValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = coerced
+	}
+
+	if requireAll {
+		for _, field := range fields {
+			if _, ok := out[field.Name]; field.Required && !ok {
+				return nil, badRequestCode(fmt.Errorf("custom field %q is required", field.Name), ErrCodeMissingRequired)
+			}
+		}
+	}
+	return out, nil
+}
+
+func coerceCustomValue(field models.CustomField, value any) (any, error) {
+	invalid := func() error {
+		return badRequestCode(fmt.Errorf("custom field %q must be a %s", field.Name, field.Type), ErrCodeInvalidCustomField)
+	}
+	switch models.CustomFieldType(field.Type) {
+	case models.CustomFieldNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, invalid()
+			}
+			return parsed, nil
+		}
+		return nil, invalid()
+	case models.CustomFieldBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, invalid()
+			}
+			return parsed, nil
+		}
+		return nil, invalid()
+	default:
+		v, ok := value.(string)
+		if !ok {
+			return nil, invalid()
+		}
+		if len(field.Values) > 0 && !slices.Contains(field.Values, v) {
+			return nil, badRequestCode(fmt.Errorf("custom field %q must be one of %s", field.Name, strings.Join(field.Values, ", ")), ErrCodeInvalidCustomField)
+		}
+		return v, nil
+	}
+}
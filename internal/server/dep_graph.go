@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+// dependencyGraph is the dependency-edge subgraph among a task set.
+type dependencyGraph struct {
+	Nodes []dependencyGraphNode
+	Edges []dependencyGraphEdge
+}
+
+type dependencyGraphNode struct {
+	ID     string
+	Title  string
+	Status string
+}
+
+// dependencyGraphEdge points from a parent to the child that depends on it.
+type dependencyGraphEdge struct {
+	ParentID string
+	ChildID  string
+	Type     string
+}
+
+var dependencyGraphStatusColors = map[string]string{
+	string(models.StatusOpen):       "white",
+	string(models.StatusInProgress): "lightblue",
+	string(models.StatusBlocked):    "salmon",
+	string(models.StatusDeferred):   "lightgrey",
+	string(models.StatusClosed):     "palegreen",
+	string(models.StatusTombstone):  "grey",
+	string(models.StatusPinned):     "gold",
+}
+
+// buildDependencyGraph keeps blocks edges whose endpoints are both in tasks.
+// Nodes and edges are sorted by ID for stable output.
+func buildDependencyGraph(tasks []models.Task, deps map[string][]models.Dependency) dependencyGraph {
+	nodes := make([]dependencyGraphNode, 0, len(tasks))
+	for _, task := range tasks {
+		nodes = append(nodes, dependencyGraphNode{ID: task.ID, Title: task.Title, Status: task.Status})
+	}
+	return newDependencyGraph(nodes, deps, func(dep models.Dependency) bool {
+		return dep.Type == string(models.DependencyBlocks)
+	})
+}
+
+// buildDependencyTreeGraph keeps edges of every type among root and the nodes of its
+// dependency tree walk.
+func buildDependencyTreeGraph(root models.Task, tree []models.DepTreeNode, deps map[string][]models.Dependency) dependencyGraph {
+	nodes := make([]dependencyGraphNode, 0, len(tree)+1)
+	nodes = append(nodes, dependencyGraphNode{ID: root.ID, Title: root.Title, Status: root.Status})
+	seen := map[string]bool{root.ID: true}
+	for _, node := range tree {
+		if seen[node.ID] {
+			continue
+		}
+		seen[node.ID] = true
+		nodes = append(nodes, dependencyGraphNode{ID: node.ID, Title: node.Title, Status: node.Status})
+	}
+	return newDependencyGraph(nodes, deps, func(models.Dependency) bool { return true })
+}
+
+func newDependencyGraph(nodes []dependencyGraphNode, deps map[string][]models.Dependency, keep func(models.Dependency) bool) dependencyGraph {
+	graph := dependencyGraph{Nodes: nodes}
+	inSet := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		inSet[node.ID] = true
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].ID < graph.Nodes[j].ID })
+
+	for childID, childDeps := range deps {
+		if !inSet[childID] {
+			continue
+		}
+		for _, dep := range childDeps {
+			if !keep(dep) || !inSet[dep.ParentID] {
+				continue
+			}
+			graph.Edges = append(graph.Edges, dependencyGraphEdge{ParentID: dep.ParentID, ChildID: childID, Type: dep.Type})
+		}
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].ParentID != graph.Edges[j].ParentID {
+			return graph.Edges[i].ParentID < graph.Edges[j].ParentID
+		}
+		if graph.Edges[i].ChildID != graph.Edges[j].ChildID {
+			return graph.Edges[i].ChildID < graph.Edges[j].ChildID
+		}
+		return graph.Edges[i].Type < graph.Edges[j].Type
+	})
+	return graph
+}
+
+// writeDependencyGraphDOT renders graph as a GraphViz DOT document.
+func writeDependencyGraphDOT(w io.Writer, graph dependencyGraph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph deps {")
+	fmt.Fprintln(bw, "  rankdir=LR;")
+	fmt.Fprintln(bw, "  node [shape=box, style=filled];")
+	for _, node := range graph.Nodes {
+		color, ok := dependencyGraphStatusColors[node.Status]
+		if !ok {
+			color = "white"
+		}
+		fmt.Fprintf(bw, "  %s [label=%s, fillcolor=%s, tooltip=%s];\n",
+			dotQuote(node.ID), dotQuote(node.ID+"\n"+node.Title), dotQuote(color), dotQuote(node.Status))
+	}
+	for _, edge := range graph.Edges {
+		if edge.Type == string(models.DependencyBlocks) {
+			fmt.Fprintf(bw, "  %s -> %s;\n", dotQuote(edge.ParentID), dotQuote(edge.ChildID))
+			continue
+		}
+		fmt.Fprintf(bw, "  %s -> %s [label=%s, style=dashed];\n", dotQuote(edge.ParentID), dotQuote(edge.ChildID), dotQuote(edge.Type))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// writeDependencyGraphMermaid renders graph as a Mermaid flowchart. Node IDs are
+// positional because task IDs are not valid Mermaid identifiers in general.
+func writeDependencyGraphMermaid(w io.Writer, graph dependencyGraph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "flowchart LR")
+	keys := make(map[string]string, len(graph.Nodes))
+	statuses := make(map[string]bool)
+	for i, node := range graph.Nodes {
+		key := fmt.Sprintf("n%d", i)
+		keys[node.ID] = key
+		fmt.Fprintf(bw, "  %s[\"%s<br/>%s\"]", key, mermaidEscape(node.ID), mermaidEscape(node.Title))
+		if _, ok := dependencyGraphStatusColors[node.Status]; ok {
+			fmt.Fprintf(bw, ":::%s", node.Status)
+			statuses[node.Status] = true
+		}
+		fmt.Fprintln(bw)
+	}
+	for _, edge := range graph.Edges {
+		if edge.Type == string(models.DependencyBlocks) {
+			fmt.Fprintf(bw, "  %s --> %s\n", keys[edge.ParentID], keys[edge.ChildID])
+			continue
+		}
+		fmt.Fprintf(bw, "  %s -. \"%s\" .-> %s\n", keys[edge.ParentID], mermaidEscape(edge.Type), keys[edge.ChildID])
+	}
+	names := make([]string, 0, len(statuses))
+	for status := range statuses {
+		names = append(names, status)
+	}
+	sort.Strings(names)
+	for _, status := range names {
+		fmt.Fprintf(bw, "  classDef %s fill:%s\n", status, dependencyGraphStatusColors[status])
+	}
+	return bw.Flush()
+}
+
+func mermaidEscape(value string) string {
+	replacer := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\r", "", "\n", " ")
+	return replacer.Replace(value)
+}
+
+func dotQuote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// dependencyGraphAdjacency renders graph as an adjacency list keyed by blocked task.
+func dependencyGraphAdjacency(graph dependencyGraph) api.DepGraphResponse {
+	blockedBy := make(map[string][]string, len(graph.Nodes))
+	for _, edge := range graph.Edges {
+		blockedBy[edge.ChildID] = append(blockedBy[edge.ChildID], edge.ParentID)
+	}
+	resp := api.DepGraphResponse{Nodes: make([]api.DepGraphNode, 0, len(graph.Nodes))}
+	for _, node := range graph.Nodes {
+		parents := blockedBy[node.ID]
+		if parents == nil {
+			parents = []string{}
+		}
+		resp.Nodes = append(resp.Nodes, api.DepGraphNode{ID: node.ID, Title: node.Title, Status: node.Status, BlockedBy: parents})
+	}
+	return resp
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestWriteDependencyGraphDOT(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "gr-g002", Title: `Say "hi"`, Status: "in_progress"},
+		{ID: "gr-g001", Title: "Root", Status: "closed"},
+		{ID: "gr-g003", Title: "Leaf", Status: "open"},
+	}
+	deps := map[string][]models.Dependency{
+		"gr-g002": {{ParentID: "gr-g001", Type: "blocks"}},
+		"gr-g003": {{ParentID: "gr-g002", Type: "blocks"}, {ParentID: "gr-zz99", Type: "blocks"}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeDependencyGraphDOT(&buf, buildDependencyGraph(tasks, deps)); err != nil {
+		t.Fatalf("write dot: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"digraph deps {",
+		`"gr-g001" -> "gr-g002";`,
+		`"gr-g002" -> "gr-g003";`,
+		`"gr-g001" [label="gr-g001\nRoot", fillcolor="palegreen", tooltip="closed"];`,
+		`"gr-g002" [label="gr-g002\nSay \"hi\"", fillcolor="lightblue", tooltip="in_progress"];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected DOT output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "gr-zz99") {
+		t.Fatalf("expected edges outside the task set to be omitted, got:\n%s", out)
+	}
+}
+
+func TestHandleDepGraphJSON(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-gj01", "parent", 1)
+	seedListTask(t, srv, "gr-gj02", "child", 2)
+	if err := srv.store.AddDependency(t.Context(), "gr-gj02", "gr-gj01", "blocks"); err != nil {
+		t.Fatalf("add dependency: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/deps/graph?format=json", nil)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	var got api.DepGraphResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(got.Nodes) != 2 || got.Nodes[1].ID != "gr-gj02" || len(got.Nodes[1].BlockedBy) != 1 || got.Nodes[1].BlockedBy[0] != "gr-gj01" {
+		t.Fatalf("unexpected adjacency list: %+v", got.Nodes)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/deps/graph?format=svg", nil)
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown format, got %d", w.Code)
+	}
+}
+
+func TestHandleDepTreeMermaid(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-gt01", "root", 1)
+	seedListTask(t, srv, "gr-gt02", `Say "hi"`, 2)
+	seedListTask(t, srv, "gr-gt03", "related", 2)
+	if err := srv.store.AddDependency(t.Context(), "gr-gt02", "gr-gt01", "blocks"); err != nil {
+		t.Fatalf("add dependency: %v", err)
+	}
+	if err := srv.store.AddDependency(t.Context(), "gr-gt01", "gr-gt03", "relates_to"); err != nil {
+		t.Fatalf("add dependency: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/gr-gt01/deps/tree?format=mermaid", nil)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	out := w.Body.String()
+	for _, want := range []string{
+		"flowchart LR",
+		`n0["gr-gt01<br/>root"]:::open`,
+		`n1["gr-gt02<br/>Say #quot;hi#quot;"]:::open`,
+		"n0 --> n1",
+		`n2 -. "relates_to" .-> n0`,
+		"classDef open fill:white",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected Mermaid output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/gr-gt01/deps/tree?format=dot", nil)
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"gr-gt03" -> "gr-gt01" [label="relates_to", style=dashed];`) {
+		t.Fatalf("unexpected DOT tree response %d:\n%s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/gr-gt01/deps/tree?format=svg", nil)
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown format, got %d", w.Code)
+	}
+}
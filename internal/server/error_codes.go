@@ -17,20 +17,34 @@ const (
 	ErrCodeInvalidDependency  = 1012
 	ErrCodeInvalidParentID    = 1013
 	ErrCodeInvalidSearchQuery = 1014
+	ErrCodeInvalidMilestone   = 1015
+	ErrCodeInvalidCustomField = 1016
 
 	// Domain state (2xxx)
-	ErrCodeTaskNotFound       = 2001
-	ErrCodeDependencyNotFound = 2002
-	ErrCodeAttachmentNotFound = 2003
-	ErrCodeGitRefNotFound     = 2004
-	ErrCodeUserNotFound       = 2005
-	ErrCodeTaskIDExists       = 2101
-	ErrCodeConflict           = 2102
+	ErrCodeTaskNotFound         = 2001
+	ErrCodeDependencyNotFound   = 2002
+	ErrCodeAttachmentNotFound   = 2003
+	ErrCodeGitRefNotFound       = 2004
+	ErrCodeUserNotFound         = 2005
+	ErrCodeSavedFilterNotFound  = 2006
+	ErrCodeMilestoneNotFound    = 2007
+	ErrCodeCustomFieldNotFound  = 2008
+	ErrCodeAPITokenNotFound     = 2009
+	ErrCodeOperationNotFound    = 2010
+	ErrCodeImportReportNotFound = 2011
+	ErrCodeTaskIDExists         = 2101
+	ErrCodeConflict             = 2102
+	ErrCodeWIPLimitExceeded     = 2103
+	ErrCodeContentRejected      = 2104
+	ErrCodeContentQuarantined   = 2105
+	ErrCodePRNotMerged          = 2106
+	ErrCodeIdempotencyKeyReused = 2107
 
 	// Auth & limits (3xxx)
 	ErrCodeUnauthorized      = 3001
 	ErrCodeForbidden         = 3002
 	ErrCodeResourceExhausted = 3003
+	ErrCodeMaintenanceMode   = 3004
 
 	// Internal/system (4xxx)
 	ErrCodeInternal       = 4001
@@ -38,6 +52,9 @@ const (
 	ErrCodeExportFailed   = 4003
 	ErrCodeImportFailed   = 4004
 	ErrCodeNotImplemented = 4005
+	ErrCodeScanFailed     = 4006
+	ErrCodeResolveFailed  = 4007
+	ErrCodeQueryTimeout   = 4008
 )
 
 func defaultErrorCodeByStatus(status int) int {
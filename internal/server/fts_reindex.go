@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/store"
+)
+
+// maxReindexBatchSize bounds how long one reindex transaction holds the writer.
+const maxReindexBatchSize = 5000
+
+// ftsReindex tracks the one background search index rebuild a server runs at
+// a time; its status outlives the run so operators can read the outcome.
+type ftsReindex struct {
+	mu     sync.Mutex
+	status api.ReindexStatus
+}
+
+func (j *ftsReindex) snapshot() api.ReindexStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.status
+	if status.State == "" {
+		status.State = api.ReindexStateIdle
+	}
+	return status
+}
+
+// start marks a run as started, reporting false if one is already running.
+func (j *ftsReindex) start(now time.Time) (api.ReindexStatus, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status.State == api.ReindexStateRunning {
+		return j.status, false
+	}
+	j.status = api.ReindexStatus{State: api.ReindexStateRunning, StartedAt: &now}
+	return j.status, true
+}
+
+func (j *ftsReindex) progress(p store.ReindexProgress) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Total = p.Total
+	j.status.Processed = p.Processed
+}
+
+func (j *ftsReindex) finish(result *store.ReindexResult, err error, now time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.FinishedAt = &now
+	if result != nil {
+		j.status.Processed = result.Reindexed
+		j.status.Orphaned = result.Orphaned
+	}
+	if err != nil {
+		j.status.State = api.ReindexStateFailed
+		j.status.Error = err.Error()
+		return
+	}
+	j.status.State = api.ReindexStateDone
+}
+
+func (s *Server) handleAdminStartReindex(w http.ResponseWriter, r *http.Request) {
+	if s.maintenance == nil {
+		s.writeErrorReq(w, r, http.StatusNotImplemented, apiError{
+			status:  http.StatusNotImplemented,
+			code:    "not_implemented",
+			errCode: ErrCodeNotImplemented,
+			err:     fmt.Errorf("search reindex is not supported"),
+		})
+		return
+	}
+
+	var req api.ReindexRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+	if r.Header.Get("X-Confirm") != "true" {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("reindex requires X-Confirm: true header"), ErrCodeMissingRequired))
+		return
+	}
+	if req.BatchSize < 0 || req.BatchSize > maxReindexBatchSize {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("batch_size must be between 1 and %d", maxReindexBatchSize), ErrCodeInvalidArgument))
+		return
+	}
+
+	status, ok := s.reindex.start(time.Now().UTC())
+	if !ok {
+		s.writeErrorReq(w, r, http.StatusConflict, conflictCode(fmt.Errorf("a reindex is already running"), ErrCodeConflict))
+		return
+	}
+
+	s.recordAdminAudit(r, auditActionReindex, map[string]any{"batch_size": req.BatchSize}, 0, false)
+	s.log().Info("search reindex started", "batch_size", req.BatchSize)
+	// The run outlives this request; it stops only if the store fails.
+	go s.runReindex(context.WithoutCancel(r.Context()), req.BatchSize)
+	s.writeJSON(w, http.StatusAccepted, status)
+}
+
+func (s *Server) runReindex(ctx context.Context, batchSize int) {
+	start := time.Now()
+	result, err := s.maintenance.ReindexFTS(ctx, store.ReindexOptions{
+		BatchSize: batchSize,
+		Progress:  s.reindex.progress,
+	})
+	s.reindex.finish(result, err, time.Now().UTC())
+	if err != nil {
+		s.log().Error("search reindex failed", "error", err)
+		return
+	}
+	s.log().Info("search reindex complete", "reindexed", result.Reindexed, "orphaned", result.Orphaned, "duration", time.Since(start))
+}
+
+func (s *Server) handleAdminReindexStatus(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.reindex.snapshot())
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+const (
+	gitRelationMentionedBy = "mentioned_by"
+	maxGitRefScanNote      = 200
+)
+
+// ScanCommits creates a mentioned_by commit ref for every task of the project
+// whose id appears in a commit message, e.g. "gr-ab12: fix parser". Refs that
+// already exist are counted, not duplicated; mentioned ids without a task are
+// reported back.
+func (s *TaskGitRefService) ScanCommits(ctx context.Context, req api.GitRefScanRequest) (api.GitRefScanResponse, error) {
+	resp := api.GitRefScanResponse{Created: []models.TaskGitRef{}, DryRun: req.DryRun}
+	if s == nil || s.taskStore == nil || s.gitRefStore == nil {
+		return resp, internalError(fmt.Errorf("task git ref service is not configured"))
+	}
+	if len(req.Commits) == 0 {
+		return resp, badRequestCode(fmt.Errorf("commits are required"), ErrCodeMissingRequired)
+	}
+	if len(req.Commits) > maxGitRefLookupCommits {
+		return resp, badRequestCode(fmt.Errorf("too many commits (max %d)", maxGitRefLookupCommits), ErrCodeInvalidArgument)
+	}
+	if strings.TrimSpace(req.Repo) == "" {
+		return resp, badRequestCode(fmt.Errorf("repo is required"), ErrCodeMissingRequired)
+	}
+	repo, err := canonicalGitRepoSlug(req.Repo)
+	if err != nil {
+		return resp, badRequestCode(err, ErrCodeInvalidArgument)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return resp, err
+	}
+
+	mention := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(project) + `-[0-9a-z]{4}\b`)
+	refsByTask := map[string][]models.TaskGitRef{}
+	unknown := map[string]bool{}
+	for _, commit := range req.Commits {
+		sha, err := normalizeGitHash(commit.SHA, "sha")
+		if err != nil {
+			return resp, err
+		}
+		if sha == "" {
+			return resp, badRequestCode(fmt.Errorf("commits must not contain empty sha values"), ErrCodeMissingRequired)
+		}
+		resp.CommitsScanned++
+
+		for _, taskID := range uniqueStrings(lowerAll(mention.FindAllString(commit.Message, -1))) {
+			if unknown[taskID] {
+				continue
+			}
+			refs, known := refsByTask[taskID]
+			if !known {
+				exists, err := s.taskStore.TaskExists(taskID)
+				if err != nil {
+					return resp, err
+				}
+				if !exists {
+					unknown[taskID] = true
+					resp.UnknownTaskIDs = append(resp.UnknownTaskIDs, taskID)
+					continue
+				}
+				if refs, err = s.gitRefStore.ListTaskGitRefs(ctx, project, taskID); err != nil {
+					return resp, err
+				}
+			}
+			if hasMentionRef(refs, repo, sha) {
+				resp.Existing++
+				refsByTask[taskID] = refs
+				continue
+			}
+
+			ref := models.TaskGitRef{Project: project, TaskID: taskID, Repo: repo, Relation: gitRelationMentionedBy, ObjectType: string(models.GitObjectTypeCommit), ObjectValue: sha, Note: commitSubject(commit.Message)}
+			if !req.DryRun {
+				ref, err = s.Create(ctx, taskID, api.TaskGitRefCreateRequest{Repo: repo, Relation: ref.Relation, ObjectType: ref.ObjectType, ObjectValue: sha, Note: ref.Note})
+				if err != nil && httpStatusFromError(err) == http.StatusConflict {
+					resp.Existing++
+					continue
+				}
+				if err != nil {
+					return resp, err
+				}
+			}
+			refsByTask[taskID] = append(refs, ref)
+			resp.Created = append(resp.Created, ref)
+		}
+	}
+	return resp, nil
+}
+
+func hasMentionRef(refs []models.TaskGitRef, repo, sha string) bool {
+	for _, ref := range refs {
+		if ref.Repo == repo && ref.Relation == gitRelationMentionedBy && ref.ObjectType == string(models.GitObjectTypeCommit) && ref.ObjectValue == sha {
+			return true
+		}
+	}
+	return false
+}
+
+// commitSubject returns the first line of a commit message, shortened for a ref note.
+func commitSubject(message string) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	subject = strings.TrimSpace(subject)
+	if len(subject) > maxGitRefScanNote {
+		subject = strings.ToValidUTF8(subject[:maxGitRefScanNote], "")
+	}
+	return subject
+}
+
+func lowerAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, value := range values {
+		out[i] = strings.ToLower(value)
+	}
+	return out
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"grns/internal/models"
+)
+
+const (
+	githubTokenEnvKey  = "GRNS_GITHUB_TOKEN"
+	gitlabTokenEnvKey  = "GRNS_GITLAB_TOKEN"
+	gitResolveTimeout  = 30 * time.Second
+	maxGitResolveBody  = 4 << 20
+	gitProviderGitHub  = "github"
+	gitProviderGitLab  = "gitlab"
+	gitRefPullRequests = "pull_requests"
+	gitRefPRMerged     = "pr_merged"
+)
+
+// errGitObjectNotFound reports that the hosting provider does not know the ref.
+var errGitObjectNotFound = errors.New("git object not found")
+
+// GitResolver looks up git objects on the repository's hosting provider.
+type GitResolver interface {
+	// Resolve returns the commit that ref (a branch, tag, or sha) points to in
+	// repo (host/owner/name), the URLs of pull requests containing it, and
+	// whether any of them has merged.
+	Resolve(ctx context.Context, repo, ref string) (GitResolution, error)
+}
+
+// GitResolution is one resolved ref.
+type GitResolution struct {
+	Provider     string
+	Commit       string
+	PullRequests []string
+	Merged       bool
+}
+
+// GitResolverOptions configures the hosting APIs used by POST /git-refs/{id}/resolve.
+// Tokens come from GRNS_GITHUB_TOKEN and GRNS_GITLAB_TOKEN.
+type GitResolverOptions struct {
+	GitHubAPIURL string
+	GitLabAPIURL string
+}
+
+// gitHostingProvider is one API endpoint. host is the repo host it serves: the
+// API host without a leading "api.", so api.github.com serves github.com.
+type gitHostingProvider struct {
+	kind   string
+	host   string
+	apiURL string
+	token  string
+}
+
+// hostingResolver resolves refs through the GitHub and GitLab REST APIs.
+type hostingResolver struct {
+	client    *http.Client
+	providers []gitHostingProvider
+}
+
+func newGitResolver(opts GitResolverOptions) (*hostingResolver, error) {
+	resolver := &hostingResolver{client: &http.Client{Timeout: gitResolveTimeout}}
+	for _, provider := range []gitHostingProvider{
+		{kind: gitProviderGitHub, apiURL: opts.GitHubAPIURL, token: os.Getenv(githubTokenEnvKey)},
+		{kind: gitProviderGitLab, apiURL: opts.GitLabAPIURL, token: os.Getenv(gitlabTokenEnvKey)},
+	} {
+		provider.apiURL = strings.TrimRight(strings.TrimSpace(provider.apiURL), "/")
+		if provider.apiURL == "" {
+			continue
+		}
+		u, err := url.Parse(provider.apiURL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid %s api url %q", provider.kind, provider.apiURL)
+		}
+		provider.host = strings.TrimPrefix(strings.ToLower(u.Host), "api.")
+		provider.token = strings.TrimSpace(provider.token)
+		resolver.providers = append(resolver.providers, provider)
+	}
+	return resolver, nil
+}
+
+func (h *hostingResolver) Resolve(ctx context.Context, repo, ref string) (GitResolution, error) {
+	host, path, _ := strings.Cut(repo, "/")
+	for _, provider := range h.providers {
+		if provider.host != host {
+			continue
+		}
+		if provider.kind == gitProviderGitLab {
+			return h.resolveGitLab(ctx, provider, path, ref)
+		}
+		return h.resolveGitHub(ctx, provider, path, ref)
+	}
+	return GitResolution{}, badRequestCode(fmt.Errorf("no git hosting provider configured for %s", host), ErrCodeInvalidArgument)
+}
+
+func (h *hostingResolver) resolveGitHub(ctx context.Context, provider gitHostingProvider, path, ref string) (GitResolution, error) {
+	resolution := GitResolution{Provider: provider.kind}
+	base := provider.apiURL + "/repos/" + path
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	if err := h.getJSON(ctx, provider, base+"/commits/"+url.PathEscape(ref), &commit); err != nil {
+		return resolution, err
+	}
+	resolution.Commit = commit.SHA
+
+	var pulls []struct {
+		HTMLURL  string  `json:"html_url"`
+		MergedAt *string `json:"merged_at"`
+	}
+	if err := h.getJSON(ctx, provider, base+"/commits/"+commit.SHA+"/pulls", &pulls); err != nil {
+		return resolution, err
+	}
+	for _, pull := range pulls {
+		resolution.PullRequests = append(resolution.PullRequests, pull.HTMLURL)
+		resolution.Merged = resolution.Merged || pull.MergedAt != nil
+	}
+	return resolution, nil
+}
+
+func (h *hostingResolver) resolveGitLab(ctx context.Context, provider gitHostingProvider, path, ref string) (GitResolution, error) {
+	resolution := GitResolution{Provider: provider.kind}
+	base := provider.apiURL + "/projects/" + url.PathEscape(path) + "/repository/commits/"
+	var commit struct {
+		ID string `json:"id"`
+	}
+	if err := h.getJSON(ctx, provider, base+url.PathEscape(ref), &commit); err != nil {
+		return resolution, err
+	}
+	resolution.Commit = commit.ID
+
+	var requests []struct {
+		WebURL string `json:"web_url"`
+		State  string `json:"state"`
+	}
+	if err := h.getJSON(ctx, provider, base+commit.ID+"/merge_requests", &requests); err != nil {
+		return resolution, err
+	}
+	for _, request := range requests {
+		resolution.PullRequests = append(resolution.PullRequests, request.WebURL)
+		resolution.Merged = resolution.Merged || request.State == "merged"
+	}
+	return resolution, nil
+}
+
+func (h *hostingResolver) getJSON(ctx context.Context, provider gitHostingProvider, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if provider.token != "" {
+		if provider.kind == gitProviderGitLab {
+			req.Header.Set("PRIVATE-TOKEN", provider.token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+provider.token)
+		}
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", provider.kind, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity:
+		return errGitObjectNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%s returned %s", provider.kind, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxGitResolveBody)).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", provider.kind, err)
+	}
+	return nil
+}
+
+// ConfigureResolver sets the resolver used by Resolve; nil turns resolution off.
+func (s *TaskGitRefService) ConfigureResolver(resolver GitResolver) {
+	if s == nil {
+		return
+	}
+	s.resolver = resolver
+}
+
+// Resolve asks the hosting provider which commit a git ref points to now and
+// which pull requests contain it. It sets resolved_commit and records the pull
+// request URLs in meta.pull_requests and whether one has merged in
+// meta.pr_merged; other meta keys are kept.
+func (s *TaskGitRefService) Resolve(ctx context.Context, id string) (models.TaskGitRef, error) {
+	ref, err := s.Get(ctx, id)
+	if err != nil {
+		return ref, err
+	}
+	if s.resolver == nil {
+		return ref, badRequestCode(fmt.Errorf("git ref resolution is not configured"), ErrCodeInvalidArgument)
+	}
+	if !gitRefResolvable(ref) {
+		return ref, badRequestCode(fmt.Errorf("cannot resolve %s refs", ref.ObjectType), ErrCodeInvalidArgument)
+	}
+
+	resolution, err := s.resolver.Resolve(ctx, ref.Repo, ref.ObjectValue)
+	if errors.Is(err, errGitObjectNotFound) {
+		return ref, notFoundCode(fmt.Errorf("%s %s not found in %s", ref.ObjectType, ref.ObjectValue, ref.Repo), ErrCodeGitRefNotFound)
+	}
+	if err != nil {
+		var apiErr apiError
+		if errors.As(err, &apiErr) {
+			return ref, err
+		}
+		return ref, resolveFailed(err)
+	}
+	commit, err := normalizeGitHash(resolution.Commit, "resolved_commit")
+	if err != nil || commit == "" {
+		return ref, resolveFailed(fmt.Errorf("%s returned an invalid commit %q", resolution.Provider, resolution.Commit))
+	}
+
+	meta := make(map[string]any, len(ref.Meta)+2)
+	maps.Copy(meta, ref.Meta)
+	pullRequests := resolution.PullRequests
+	if pullRequests == nil {
+		pullRequests = []string{}
+	}
+	meta[gitRefPullRequests] = pullRequests
+	meta[gitRefPRMerged] = resolution.Merged
+	if err := s.gitRefStore.UpdateTaskGitRefResolution(ctx, ref.ID, commit, meta, time.Now().UTC()); err != nil {
+		if isUniqueConstraint(err) {
+			return ref, conflictCode(fmt.Errorf("an identical git ref already resolves to %s", commit), ErrCodeConflict)
+		}
+		return ref, err
+	}
+	return s.Get(ctx, ref.ID)
+}
+
+// gitRefResolvable reports whether a ref names something a provider can resolve to a commit.
+func gitRefResolvable(ref models.TaskGitRef) bool {
+	switch models.GitObjectType(ref.ObjectType) {
+	case models.GitObjectTypeCommit, models.GitObjectTypeBranch, models.GitObjectTypeTag:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,672 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"grns/internal/api"
+	"grns/internal/grpcapi/grnsv1"
+	"grns/internal/models"
+)
+
+// grpcWriteMethods need a write-scoped api token, like unsafe HTTP methods.
+var grpcWriteMethods = map[string]bool{
+	grnsv1.TaskService_CreateTask_FullMethodName:  true,
+	grnsv1.TaskService_UpdateTask_FullMethodName:  true,
+	grnsv1.TaskService_CloseTasks_FullMethodName:  true,
+	grnsv1.TaskService_ImportTasks_FullMethodName: true,
+}
+
+// grpcTaskService serves grns.v1.TaskService on top of the server's TaskService.
+type grpcTaskService struct {
+	grnsv1.UnimplementedTaskServiceServer
+	s *Server
+}
+
+// ConfigureGRPC sets the address the gRPC API listens on, a TCP host:port or
+// a "unix://" socket. An empty address leaves gRPC off.
+func (s *Server) ConfigureGRPC(addr string) {
+	if s == nil {
+		return
+	}
+	s.grpcAddr = strings.TrimSpace(addr)
+}
+
+func (s *Server) newGRPCServer() *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.grpcUnaryAuth),
+		grpc.ChainStreamInterceptor(s.grpcStreamAuth),
+	)
+	grnsv1.RegisterTaskServiceServer(server, &grpcTaskService{s: s})
+	return server
+}
+
+// stopGRPC drains in-flight calls for up to shutdownTimeout, then cancels the rest.
+func stopGRPC(server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		server.Stop()
+	}
+}
+
+func (s *Server) grpcUnaryAuth(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.grpcAuthorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.grpcAdmitWrite(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) grpcStreamAuth(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.grpcAuthorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	if err := s.grpcAdmitWrite(ctx, info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, &grpcServerStream{ServerStream: stream, ctx: ctx})
+}
+
+// grpcAdmitWrite holds write methods back while maintenance mode is on. A
+// refused call fails with Unavailable and a retry-after metadata hint.
+func (s *Server) grpcAdmitWrite(ctx context.Context, method string) error {
+	if !grpcWriteMethods[method] {
+		return nil
+	}
+	err := s.maintenanceMode.admit(ctx)
+	refused, ok := err.(*errMaintenanceMode)
+	if !ok {
+		if err != nil {
+			return status.FromContextError(err).Err()
+		}
+		return nil
+	}
+	s.log().Warn("grpc write rejected by maintenance mode", "method", method)
+	_ = grpc.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(refused.retryAfter.Seconds()))))
+	return status.Error(codes.Unavailable, refused.Error())
+}
+
+type grpcServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (g *grpcServerStream) Context() context.Context {
+	return g.ctx
+}
+
+// grpcAuthorize applies the HTTP API's authentication to a gRPC call. The
+// authorization and x-actor metadata stand in for the HTTP headers; session
+// cookies are not accepted.
+func (s *Server) grpcAuthorize(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	r := (&http.Request{Method: http.MethodGet, URL: &url.URL{Path: method}, Header: http.Header{}}).WithContext(ctx)
+	if grpcWriteMethods[method] {
+		r.Method = http.MethodPost
+	}
+	if values := md.Get("authorization"); len(values) > 0 {
+		r.Header.Set("Authorization", values[0])
+	}
+
+	requireAuth, err := s.apiAuthRequired(r)
+	if err != nil {
+		return ctx, s.grpcError(method, storeFailure(err))
+	}
+	authenticated := false
+	principal := authPrincipal{}
+	if requireAuth {
+		authenticated, principal, err = s.authorizeAPIRequest(r)
+		if err != nil {
+			return ctx, s.grpcError(method, storeFailure(err))
+		}
+		if !authenticated {
+			s.log().Debug("grpc call unauthorized", "method", method)
+			return ctx, status.Error(codes.Unauthenticated, "unauthorized")
+		}
+		if principal.AuthType == authTypeAPIToken && !apiTokenAllows(principal.Token, r) {
+			s.log().Debug("grpc call forbidden by api token scope", "method", method, "token_id", principal.Token.ID)
+			return ctx, status.Error(codes.PermissionDenied, "api token lacks required scope")
+		}
+	}
+
+	ctx = contextWithAuthRequired(ctx, requireAuth)
+	if authenticated {
+		ctx = contextWithAuthPrincipal(ctx, principal)
+	}
+	if values := md.Get("x-actor"); len(values) > 0 {
+		if actor := strings.TrimSpace(values[0]); actor != "" && utf8.RuneCountInString(actor) <= maxActorLength {
+			ctx = contextWithActor(ctx, actor)
+		}
+	}
+	return ctx, nil
+}
+
+// grpcError converts a service error to a gRPC status. Internal errors are
+// logged and reported without detail, as on the HTTP API.
+func (s *Server) grpcError(method string, err error) error {
+	httpStatus := httpStatusFromError(err)
+	message := err.Error()
+	if httpStatus >= http.StatusInternalServerError {
+		s.log().Error("grpc call error", "method", method, "status", httpStatus, "error", err)
+		message = publicServerError(err)
+	} else {
+		s.log().Debug("grpc call rejected", "method", method, "status", httpStatus, "error", err)
+	}
+	return status.Error(grpcCodeFromHTTPStatus(httpStatus), message)
+}
+
+func grpcCodeFromHTTPStatus(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// projectContext scopes ctx to project, or to the server's default prefix
+// when project is empty.
+func (g *grpcTaskService) projectContext(ctx context.Context, project string) (context.Context, error) {
+	project = strings.TrimSpace(project)
+	if project == "" {
+		project = g.s.projectPrefix
+	}
+	normalized, err := normalizePrefix(project)
+	if err != nil {
+		return ctx, badRequestCode(fmt.Errorf("invalid project"), ErrCodeInvalidArgument)
+	}
+	return contextWithProject(ctx, normalized), nil
+}
+
+func (g *grpcTaskService) GetTask(ctx context.Context, req *grnsv1.GetTaskRequest) (*grnsv1.Task, error) {
+	method := grnsv1.TaskService_GetTask_FullMethodName
+	ctx, err := g.projectContext(ctx, req.GetProject())
+	if err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	id := strings.TrimSpace(req.GetId())
+	if !validateID(id) {
+		return nil, g.s.grpcError(method, badRequestCode(fmt.Errorf("invalid id"), ErrCodeInvalidID))
+	}
+	resp, err := g.s.service.GetWithIncludes(ctx, id, taskIncludes{Deps: true})
+	if err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	return taskToProto(resp)
+}
+
+func (g *grpcTaskService) ListTasks(ctx context.Context, req *grnsv1.ListTasksRequest) (*grnsv1.ListTasksResponse, error) {
+	method := grnsv1.TaskService_ListTasks_FullMethodName
+	ctx, err := g.projectContext(ctx, req.GetProject())
+	if err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	filter, err := listFilterFromProto(req.GetFilter())
+	if err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	filter.Includes = taskIncludes{Deps: true}
+
+	if filter.SearchQuery != "" || filter.SpecRegex != "" {
+		if !tryAcquire(g.s.searchLimiter) {
+			return nil, status.Error(codes.ResourceExhausted, "too many concurrent search requests")
+		}
+		defer g.s.releaseLimiter(g.s.searchLimiter)
+	}
+
+	responses, err := g.s.service.List(ctx, filter)
+	if err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	out := &grnsv1.ListTasksResponse{Tasks: make([]*grnsv1.Task, 0, len(responses))}
+	for _, resp := range responses {
+		task, err := taskToProto(resp)
+		if err != nil {
+			return nil, err
+		}
+		out.Tasks = append(out.Tasks, task)
+	}
+	if filter.OrderByID && filter.Limit > 0 && len(responses) == filter.Limit {
+		out.NextAfterId = responses[len(responses)-1].ID
+	}
+	return out, nil
+}
+
+func (g *grpcTaskService) CreateTask(ctx context.Context, req *grnsv1.CreateTaskRequest) (*grnsv1.Task, error) {
+	method := grnsv1.TaskService_CreateTask_FullMethodName
+	ctx, err := g.projectContext(ctx, req.GetProject())
+	if err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	resp, err := g.s.service.Create(ctx, api.TaskCreateRequest{
+		ID:                 req.GetId(),
+		Title:              req.GetTitle(),
+		Status:             req.Status,
+		Type:               req.Type,
+		Priority:           intPtr(req.Priority),
+		Description:        req.Description,
+		SpecID:             req.SpecId,
+		ParentID:           req.ParentId,
+		Assignee:           req.Assignee,
+		Notes:              req.Notes,
+		Design:             req.Design,
+		AcceptanceCriteria: req.AcceptanceCriteria,
+		SourceRepo:         req.SourceRepo,
+		MilestoneID:        req.MilestoneId,
+		BlockedReason:      req.BlockedReason,
+		BlockedOn:          req.BlockedOn,
+		Custom:             structToMap(req.GetCustom()),
+		Labels:             req.GetLabels(),
+		Deps:               depsFromProto(req.GetDeps()),
+		Lenient:            req.GetLenient(),
+	})
+	if err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	g.s.log().Debug("task created", "task_id", resp.Task.ID, "project", resp.Task.Project, "transport", "grpc")
+	return taskToProto(resp)
+}
+
+func (g *grpcTaskService) UpdateTask(ctx context.Context, req *grnsv1.UpdateTaskRequest) (*grnsv1.Task, error) {
+	method := grnsv1.TaskService_UpdateTask_FullMethodName
+	ctx, err := g.projectContext(ctx, req.GetProject())
+	if err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	id := strings.TrimSpace(req.GetId())
+	if !validateID(id) {
+		return nil, g.s.grpcError(method, badRequestCode(fmt.Errorf("invalid id"), ErrCodeInvalidID))
+	}
+	resp, err := g.s.service.Update(ctx, id, api.TaskUpdateRequest{
+		Title:              req.Title,
+		Status:             req.Status,
+		Type:               req.Type,
+		Priority:           intPtr(req.Priority),
+		Description:        req.Description,
+		SpecID:             req.SpecId,
+		ParentID:           req.ParentId,
+		Assignee:           req.Assignee,
+		Notes:              req.Notes,
+		Design:             req.Design,
+		AcceptanceCriteria: req.AcceptanceCriteria,
+		SourceRepo:         req.SourceRepo,
+		MilestoneID:        req.MilestoneId,
+		BlockedReason:      req.BlockedReason,
+		BlockedOn:          req.BlockedOn,
+		Custom:             structToMap(req.GetCustom()),
+		Force:              req.GetForce(),
+	})
+	if err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	g.s.log().Debug("task updated", "task_id", resp.Task.ID, "status", resp.Task.Status, "transport", "grpc")
+	return taskToProto(resp)
+}
+
+func (g *grpcTaskService) CloseTasks(ctx context.Context, req *grnsv1.CloseTasksRequest) (*grnsv1.CloseTasksResponse, error) {
+	method := grnsv1.TaskService_CloseTasks_FullMethodName
+	ctx, err := g.projectContext(ctx, req.GetProject())
+	if err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	if err := requireIDs(req.GetIds()); err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	if err := g.s.service.Close(ctx, req.GetIds(), req.GetPrMerged()); err != nil {
+		return nil, g.s.grpcError(method, err)
+	}
+	g.s.log().Debug("tasks closed", "count", len(req.GetIds()), "transport", "grpc")
+	return &grnsv1.CloseTasksResponse{Ids: req.GetIds()}, nil
+}
+
+func (g *grpcTaskService) ExportTasks(req *grnsv1.ExportTasksRequest, stream grnsv1.TaskService_ExportTasksServer) error {
+	method := grnsv1.TaskService_ExportTasks_FullMethodName
+	ctx, err := g.projectContext(stream.Context(), req.GetProject())
+	if err != nil {
+		return g.s.grpcError(method, err)
+	}
+	if !tryAcquire(g.s.exportLimiter) {
+		return status.Error(codes.ResourceExhausted, "too many concurrent export requests")
+	}
+	defer g.s.releaseLimiter(g.s.exportLimiter)
+
+	afterID := ""
+	total := 0
+	for {
+		records, err := g.s.service.ExportPage(ctx, exportPageSize, afterID)
+		if err != nil {
+			return g.s.grpcError(method, err)
+		}
+		if len(records) == 0 {
+			g.s.log().Debug("export complete", "records", total, "transport", "grpc")
+			g.s.metrics.exportRecords.Observe(float64(total))
+			return nil
+		}
+		for _, record := range records {
+			task, err := taskToProto(record)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(task); err != nil {
+				return err
+			}
+			total++
+		}
+		afterID = records[len(records)-1].ID
+	}
+}
+
+func (g *grpcTaskService) ImportTasks(stream grnsv1.TaskService_ImportTasksServer) error {
+	method := grnsv1.TaskService_ImportTasks_FullMethodName
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return g.s.grpcError(method, badRequestCode(fmt.Errorf("no records found in input"), ErrCodeMissingRequired))
+	}
+	if err != nil {
+		return err
+	}
+	options := first.GetOptions()
+	opts := importStreamOptions{
+		dedupe:         strings.TrimSpace(options.GetDedupe()),
+		orphanHandling: strings.TrimSpace(options.GetOrphanHandling()),
+		sourceLabel:    strings.TrimSpace(options.GetSourceLabel()),
+		dryRun:         options.GetDryRun(),
+		atomic:         options.GetAtomic(),
+		lenient:        options.GetLenient(),
+		saveReport:     options.GetSaveReport(),
+		remapIDs:       options.GetRemapIds(),
+	}
+	if err := validateImportModes(opts.dedupe, opts.orphanHandling); err != nil {
+		return g.s.grpcError(method, err)
+	}
+	ctx, err := g.projectContext(stream.Context(), options.GetProject())
+	if err != nil {
+		return g.s.grpcError(method, err)
+	}
+	if !tryAcquire(g.s.importLimiter) {
+		return status.Error(codes.ResourceExhausted, "too many concurrent import requests")
+	}
+	defer g.s.releaseLimiter(g.s.importLimiter)
+
+	importer := g.s.service.newImportStream(opts, g.s.log())
+	line := 0
+	for msg := first; ; {
+		for _, task := range msg.GetTasks() {
+			line++
+			if err := importer.add(ctx, importRecordFromProto(task), line); err != nil {
+				return g.s.grpcError(method, err)
+			}
+		}
+		msg, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	resp, err := importer.finish(ctx)
+	if err != nil {
+		return g.s.grpcError(method, err)
+	}
+	g.s.metrics.importRecords.Observe(float64(importer.records), "grpc")
+	return stream.SendAndClose(importResponseToProto(resp))
+}
+
+// tryAcquire takes a concurrency slot without waiting, like acquireLimiter.
+func tryAcquire(limiter chan struct{}) bool {
+	if limiter == nil {
+		return true
+	}
+	select {
+	case limiter <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// listFilterFromProto encodes filter as list query parameters, so gRPC
+// lists are validated exactly like GET /v1/tasks.
+func listFilterFromProto(filter *grnsv1.ListFilter) (taskListFilter, error) {
+	if filter == nil {
+		filter = &grnsv1.ListFilter{}
+	}
+	query := url.Values{}
+	setCSV := func(key string, values []string) {
+		if len(values) > 0 {
+			query.Set(key, strings.Join(values, ","))
+		}
+	}
+	setString := func(key, value string) {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	setTime := func(key string, value *timestamppb.Timestamp) {
+		if value != nil {
+			query.Set(key, value.AsTime().UTC().Format(time.RFC3339Nano))
+		}
+	}
+	setCSV("status", filter.GetStatuses())
+	setCSV("type", filter.GetTypes())
+	setCSV("label", filter.GetLabels())
+	setCSV("label_any", filter.GetLabelsAny())
+	setCSV("id", filter.GetIds())
+	setString("parent_id", filter.GetParentId())
+	setString("milestone_id", filter.GetMilestoneId())
+	setString("assignee", filter.GetAssignee())
+	if filter.GetNoAssignee() {
+		query.Set("no_assignee", "true")
+	}
+	if filter.PriorityMin != nil {
+		query.Set("priority_min", strconv.Itoa(int(filter.GetPriorityMin())))
+	}
+	if filter.PriorityMax != nil {
+		query.Set("priority_max", strconv.Itoa(int(filter.GetPriorityMax())))
+	}
+	setString("search", filter.GetSearch())
+	setString("title_contains", filter.GetTitleContains())
+	setTime("updated_after", filter.GetUpdatedAfter())
+	setTime("updated_before", filter.GetUpdatedBefore())
+	setString("sort", filter.GetSort())
+	setString("order", filter.GetOrder())
+	if filter.GetLimit() != 0 {
+		query.Set("limit", strconv.Itoa(int(filter.GetLimit())))
+	}
+	if filter.GetOffset() != 0 {
+		query.Set("offset", strconv.Itoa(int(filter.GetOffset())))
+	}
+	if filter.GetKeyset() || filter.GetAfterId() != "" {
+		query.Set("after_id", filter.GetAfterId())
+	}
+	return parseListFilter(&http.Request{URL: &url.URL{RawQuery: query.Encode()}})
+}
+
+func taskToProto(resp api.TaskResponse) (*grnsv1.Task, error) {
+	task := resp.Task
+	custom, err := mapToStruct(task.Custom)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encode custom fields of %s: %v", task.ID, err)
+	}
+	out := &grnsv1.Task{
+		Project:            task.Project,
+		Id:                 task.ID,
+		Title:              task.Title,
+		Status:             task.Status,
+		Type:               task.Type,
+		Priority:           int32(task.Priority),
+		Description:        task.Description,
+		SpecId:             task.SpecID,
+		ParentId:           task.ParentID,
+		Assignee:           task.Assignee,
+		Notes:              task.Notes,
+		Design:             task.Design,
+		AcceptanceCriteria: task.AcceptanceCriteria,
+		SourceRepo:         task.SourceRepo,
+		MilestoneId:        task.MilestoneID,
+		BlockedReason:      task.BlockedReason,
+		BlockedOn:          task.BlockedOn,
+		MergedInto:         task.MergedInto,
+		CreatedAt:          timestamppb.New(task.CreatedAt),
+		UpdatedAt:          timestamppb.New(task.UpdatedAt),
+		ClosedAt:           timestampOrNil(task.ClosedAt),
+		DeletedAt:          timestampOrNil(task.DeletedAt),
+		Custom:             custom,
+		Labels:             resp.Labels,
+	}
+	for _, dep := range resp.Deps {
+		out.Deps = append(out.Deps, &grnsv1.Dependency{ParentId: dep.ParentID, Type: dep.Type})
+	}
+	return out, nil
+}
+
+func importRecordFromProto(task *grnsv1.Task) api.TaskImportRecord {
+	rec := api.TaskImportRecord{
+		Task: models.Task{
+			Project:            task.GetProject(),
+			ID:                 task.GetId(),
+			Title:              task.GetTitle(),
+			Status:             task.GetStatus(),
+			Type:               task.GetType(),
+			Priority:           int(task.GetPriority()),
+			Description:        task.GetDescription(),
+			SpecID:             task.GetSpecId(),
+			ParentID:           task.GetParentId(),
+			Assignee:           task.GetAssignee(),
+			Notes:              task.GetNotes(),
+			Design:             task.GetDesign(),
+			AcceptanceCriteria: task.GetAcceptanceCriteria(),
+			SourceRepo:         task.GetSourceRepo(),
+			MilestoneID:        task.GetMilestoneId(),
+			BlockedReason:      task.GetBlockedReason(),
+			BlockedOn:          task.GetBlockedOn(),
+			MergedInto:         task.GetMergedInto(),
+			ClosedAt:           timeOrNil(task.GetClosedAt()),
+			DeletedAt:          timeOrNil(task.GetDeletedAt()),
+			Custom:             structToMap(task.GetCustom()),
+		},
+		Labels: task.GetLabels(),
+		Deps:   depsFromProto(task.GetDeps()),
+	}
+	if task.GetCreatedAt() != nil {
+		rec.CreatedAt = task.GetCreatedAt().AsTime()
+	}
+	if task.GetUpdatedAt() != nil {
+		rec.UpdatedAt = task.GetUpdatedAt().AsTime()
+	}
+	return rec
+}
+
+func importResponseToProto(resp api.ImportResponse) *grnsv1.ImportTasksResponse {
+	out := &grnsv1.ImportTasksResponse{
+		Created:    int32(resp.Created),
+		Updated:    int32(resp.Updated),
+		Skipped:    int32(resp.Skipped),
+		Errors:     int32(resp.Errors),
+		DryRun:     resp.DryRun,
+		TaskIds:    resp.TaskIDs,
+		Messages:   resp.Messages,
+		Warnings:   resp.Warnings,
+		ApplyMode:  resp.ApplyMode,
+		RolledBack: resp.RolledBack,
+		ReportId:   resp.ReportID,
+		IdMap:      resp.IDMap,
+	}
+	for _, conflict := range resp.Conflicts {
+		out.Conflicts = append(out.Conflicts, &grnsv1.ImportConflict{
+			Line:       int32(conflict.Line),
+			Id:         conflict.ID,
+			Reason:     string(conflict.Reason),
+			Detail:     conflict.Detail,
+			Resolution: conflict.Resolution,
+		})
+	}
+	return out
+}
+
+func depsFromProto(deps []*grnsv1.Dependency) []models.Dependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	out := make([]models.Dependency, 0, len(deps))
+	for _, dep := range deps {
+		out = append(out, models.Dependency{ParentID: dep.GetParentId(), Type: dep.GetType()})
+	}
+	return out
+}
+
+func mapToStruct(values map[string]any) (*structpb.Struct, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return structpb.NewStruct(values)
+}
+
+func structToMap(value *structpb.Struct) map[string]any {
+	if value == nil {
+		return nil
+	}
+	return value.AsMap()
+}
+
+func intPtr(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	converted := int(*value)
+	return &converted
+}
+
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func timeOrNil(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
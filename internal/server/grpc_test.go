@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"grns/internal/grpcapi/grnsv1"
+)
+
+func newGRPCTestClient(t *testing.T, srv *Server) grnsv1.TaskServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := srv.newGRPCServer()
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return grnsv1.NewTaskServiceClient(conn)
+}
+
+func TestGRPCTaskLifecycle(t *testing.T) {
+	srv := newListTestServer(t)
+	client := newGRPCTestClient(t, srv)
+	ctx := context.Background()
+
+	priority := int32(1)
+	created, err := client.CreateTask(ctx, &grnsv1.CreateTaskRequest{Project: "gr", Title: "Ship gRPC", Priority: &priority, Labels: []string{"api"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.GetId() == "" || created.GetPriority() != 1 || created.GetStatus() != "open" {
+		t.Fatalf("unexpected created task: %+v", created)
+	}
+
+	assignee := "alice"
+	updated, err := client.UpdateTask(ctx, &grnsv1.UpdateTaskRequest{Project: "gr", Id: created.GetId(), Assignee: &assignee})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.GetAssignee() != "alice" || updated.GetTitle() != "Ship gRPC" {
+		t.Fatalf("unexpected updated task: %+v", updated)
+	}
+
+	list, err := client.ListTasks(ctx, &grnsv1.ListTasksRequest{Project: "gr", Filter: &grnsv1.ListFilter{Labels: []string{"api"}}})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list.GetTasks()) != 1 || list.GetTasks()[0].GetId() != created.GetId() {
+		t.Fatalf("unexpected list: %+v", list.GetTasks())
+	}
+
+	if _, err := client.CloseTasks(ctx, &grnsv1.CloseTasksRequest{Project: "gr", Ids: []string{created.GetId()}}); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	got, err := client.GetTask(ctx, &grnsv1.GetTaskRequest{Project: "gr", Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.GetStatus() != "closed" || got.GetClosedAt() == nil {
+		t.Fatalf("expected closed task, got %+v", got)
+	}
+
+	_, err = client.GetTask(ctx, &grnsv1.GetTaskRequest{Project: "gr", Id: "gr-zzzz"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	_, err = client.ListTasks(ctx, &grnsv1.ListTasksRequest{Project: "gr", Filter: &grnsv1.ListFilter{Statuses: []string{"bogus"}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestGRPCImportExportStreams(t *testing.T) {
+	srv := newListTestServer(t)
+	client := newGRPCTestClient(t, srv)
+	ctx := context.Background()
+
+	upload, err := client.ImportTasks(ctx)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	batches := []*grnsv1.ImportTasksRequest{
+		{
+			Options: &grnsv1.ImportOptions{Project: "gr"},
+			Tasks:   []*grnsv1.Task{{Id: "gr-im01", Title: "first", Status: "open", Type: "task", Priority: 2, Labels: []string{"imported"}}},
+		},
+		{Tasks: []*grnsv1.Task{{
+			Id: "gr-im02", Title: "second", Status: "open", Type: "task", Priority: 2,
+			Deps: []*grnsv1.Dependency{{ParentId: "gr-im01", Type: "blocks"}},
+		}}},
+	}
+	for _, batch := range batches {
+		if err := upload.Send(batch); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	result, err := upload.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("import result: %v", err)
+	}
+	if result.GetCreated() != 2 || result.GetErrors() != 0 {
+		t.Fatalf("unexpected import result: %+v", result)
+	}
+
+	download, err := client.ExportTasks(ctx, &grnsv1.ExportTasksRequest{Project: "gr"})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	var exported []*grnsv1.Task
+	for {
+		task, err := download.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("recv: %v", err)
+		}
+		exported = append(exported, task)
+	}
+	if len(exported) != 2 || exported[0].GetId() != "gr-im01" || exported[1].GetId() != "gr-im02" {
+		t.Fatalf("unexpected export: %+v", exported)
+	}
+	if len(exported[0].GetLabels()) != 1 || len(exported[1].GetDeps()) != 1 || exported[1].GetDeps()[0].GetParentId() != "gr-im01" {
+		t.Fatalf("export lost labels or deps: %+v", exported)
+	}
+}
+
+func TestGRPCRequiresBearerToken(t *testing.T) {
+	srv := newListTestServer(t)
+	srv.apiToken = "secret"
+	client := newGRPCTestClient(t, srv)
+
+	_, err := client.ListTasks(context.Background(), &grnsv1.ListTasksRequest{Project: "gr"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	if _, err := client.ListTasks(ctx, &grnsv1.ListTasksRequest{Project: "gr"}); err != nil {
+		t.Fatalf("list with token: %v", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"grns/internal/api"
+	"grns/internal/store"
 )
 
 const (
@@ -26,6 +27,14 @@ func (s *Server) writeErrorReq(w http.ResponseWriter, r *http.Request, status in
 	if err == nil {
 		err = errors.New(http.StatusText(status))
 	}
+	if errors.Is(err, store.ErrQueryTimeout) {
+		err = queryTimeout(err)
+		status = http.StatusServiceUnavailable
+	}
+	if errors.Is(err, store.ErrUnsupported) {
+		err = unsupported(err)
+		status = http.StatusNotImplemented
+	}
 
 	code := errorCode(status, err)
 	numericCode := errorNumericCode(status, err)
@@ -39,7 +48,7 @@ func (s *Server) writeErrorReq(w http.ResponseWriter, r *http.Request, status in
 	switch {
 	case status >= 500:
 		s.log().Error("request error", fields...)
-		message = "internal error"
+		message = publicServerError(err)
 	case status >= 400 && shouldWarnClientError(status):
 		s.log().Warn("request rejected", fields...)
 	case status >= 400:
@@ -106,6 +115,43 @@ func conflictCode(err error, code int) error {
 	return makeAPIError(http.StatusConflict, "conflict", code, err)
 }
 
+func wipLimitExceeded(err error) error {
+	return makeAPIError(http.StatusConflict, "wip_limit_exceeded", ErrCodeWIPLimitExceeded, err)
+}
+
+// wipLimitError maps a store WIP limit failure: the workflow.wip_limit cap
+// reports wip_limit_exceeded, per-status wip_limits a generic conflict.
+func wipLimitError(err *store.WIPLimitExceededError) error {
+	if err.AssigneeCap {
+		return wipLimitExceeded(err)
+	}
+	return conflictCode(err, ErrCodeConflict)
+}
+
+func prNotMerged(err error) error {
+	return makeAPIError(http.StatusConflict, "pr_not_merged", ErrCodePRNotMerged, err)
+}
+
+func idempotencyKeyReused(err error) error {
+	return makeAPIError(http.StatusUnprocessableEntity, "idempotency_key_reused", ErrCodeIdempotencyKeyReused, err)
+}
+
+func contentRejected(err error) error {
+	return makeAPIError(http.StatusUnprocessableEntity, "content_rejected", ErrCodeContentRejected, err)
+}
+
+func contentQuarantined(err error) error {
+	return makeAPIError(http.StatusForbidden, "forbidden", ErrCodeContentQuarantined, err)
+}
+
+func scanFailed(err error) error {
+	return makeAPIError(http.StatusBadGateway, "scan_failed", ErrCodeScanFailed, err)
+}
+
+func resolveFailed(err error) error {
+	return makeAPIError(http.StatusBadGateway, "resolve_failed", ErrCodeResolveFailed, err)
+}
+
 func internalError(err error) error {
 	return makeAPIError(http.StatusInternalServerError, "internal", ErrCodeInternal, err)
 }
@@ -114,7 +160,39 @@ func storeFailure(err error) error {
 	return makeAPIError(http.StatusInternalServerError, "internal", ErrCodeStoreFailure, err)
 }
 
+// queryTimeout reports a store statement interrupted at db.query_timeout.
+// writeErrorReq applies it to any error wrapping store.ErrQueryTimeout, so
+// unlike makeAPIError it overrides how a handler classified the failure.
+func queryTimeout(err error) error {
+	return apiError{status: http.StatusServiceUnavailable, code: "query_timeout", errCode: ErrCodeQueryTimeout, err: err}
+}
+
+// unsupported reports an operation the database backend cannot perform,
+// such as a file backup on PostgreSQL.
+func unsupported(err error) error {
+	return apiError{status: http.StatusNotImplemented, code: "not_implemented", errCode: ErrCodeNotImplemented, err: err}
+}
+
+// publicServerError is the message clients see for a 5xx. Details stay in
+// the log, except that a query timeout says so, since narrowing the request
+// can help, and an unsupported operation names itself.
+func publicServerError(err error) string {
+	if errors.Is(err, store.ErrQueryTimeout) {
+		return store.ErrQueryTimeout.Error()
+	}
+	if errors.Is(err, store.ErrUnsupported) {
+		return err.Error()
+	}
+	return "internal error"
+}
+
 func httpStatusFromError(err error) int {
+	if errors.Is(err, store.ErrQueryTimeout) {
+		return http.StatusServiceUnavailable
+	}
+	if errors.Is(err, store.ErrUnsupported) {
+		return http.StatusNotImplemented
+	}
 	var apiErr apiError
 	if errors.As(err, &apiErr) {
 		return apiErr.status
@@ -170,14 +248,18 @@ func isUniqueConstraint(err error) bool {
 	if err == nil {
 		return false
 	}
-	return strings.Contains(strings.ToLower(err.Error()), "unique constraint failed")
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "unique constraint failed") ||
+		strings.Contains(message, "violates unique constraint")
 }
 
 func isForeignKeyConstraint(err error) bool {
 	if err == nil {
 		return false
 	}
-	return strings.Contains(strings.ToLower(err.Error()), "foreign key constraint failed")
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "foreign key constraint failed") ||
+		strings.Contains(message, "violates foreign key constraint")
 }
 
 func isInvalidSearchQuery(err error) bool {
@@ -196,7 +278,7 @@ func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
 	switch {
 	case strings.HasSuffix(path, "/import"):
 		maxBytes = importJSONMaxBody
-	case strings.HasSuffix(path, "/tasks/batch"):
+	case strings.HasSuffix(path, "/rpc"):
 		maxBytes = batchJSONMaxBody
 	}
 
@@ -217,6 +299,37 @@ func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
 	return fmt.Errorf("invalid JSON payload")
 }
 
+// decodeJSONArrayStream decodes a JSON array body one element at a time and
+// hands each to fn, so a bad element is rejected before the rest is read.
+// Malformed JSON comes back already classified; fn's errors pass through.
+func decodeJSONArrayStream[T any](w http.ResponseWriter, r *http.Request, maxBytes int64, fn func(index int, item T) error) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	decoder := json.NewDecoder(r.Body)
+	token, err := decoder.Token()
+	if err != nil {
+		return classifyDecodeJSONError(err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return badRequestCode(fmt.Errorf("expected a JSON array"), ErrCodeInvalidJSON)
+	}
+	for index := 0; decoder.More(); index++ {
+		var item T
+		if err := decoder.Decode(&item); err != nil {
+			return classifyDecodeJSONError(err)
+		}
+		if err := fn(index, item); err != nil {
+			return err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return classifyDecodeJSONError(err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return badRequestCode(fmt.Errorf("invalid JSON payload"), ErrCodeInvalidJSON)
+	}
+	return nil
+}
+
 func classifyDecodeJSONError(err error) error {
 	if err == nil {
 		return nil
@@ -340,7 +453,7 @@ func requireIDs(ids []string) error {
 
 func validateImportModes(dedupe, orphanHandling string) error {
 	switch dedupe {
-	case "", "skip", "overwrite", "error":
+	case "", "skip", "overwrite", "merge", "error":
 	default:
 		return badRequestCode(fmt.Errorf("invalid dedupe mode: %s", dedupe), ErrCodeInvalidImportMode)
 	}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"grns/internal/api"
+	"grns/internal/store"
 )
 
 func (s *Server) handleAdminCleanup(w http.ResponseWriter, r *http.Request) {
@@ -51,9 +52,101 @@ func (s *Server) handleAdminCleanup(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.log().Debug("admin cleanup complete", "count", resp.Count, "dry_run", resp.DryRun)
+	s.recordAdminAudit(r, auditActionCleanup, map[string]any{"project": project, "older_than_days": req.OlderThanDays}, resp.Count, resp.DryRun)
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
+func (s *Server) handleAdminPurge(w http.ResponseWriter, r *http.Request) {
+	var req api.CleanupRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+	if req.OlderThanDays <= 0 {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("older_than_days must be > 0"), ErrCodeInvalidQuery))
+		return
+	}
+	if !req.DryRun && r.Header.Get("X-Confirm") != "true" {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("non-dry-run requires X-Confirm: true header"), ErrCodeMissingRequired))
+		return
+	}
+
+	project := ""
+	if strings.TrimSpace(req.Project) != "" {
+		normalized, err := normalizePrefix(req.Project)
+		if err != nil {
+			s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("invalid project"), ErrCodeInvalidArgument))
+			return
+		}
+		project = normalized
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -req.OlderThanDays)
+	s.log().Debug("admin purge requested", "project", project, "older_than_days", req.OlderThanDays, "dry_run", req.DryRun)
+	result, err := s.service.PurgeTombstoned(r.Context(), project, cutoff, req.DryRun)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	resp := api.CleanupResponse{
+		TaskIDs: result.TaskIDs,
+		Count:   result.Count,
+		DryRun:  result.DryRun,
+	}
+	if resp.TaskIDs == nil {
+		resp.TaskIDs = []string{}
+	}
+
+	s.log().Debug("admin purge complete", "count", resp.Count, "dry_run", resp.DryRun)
+	s.recordAdminAudit(r, auditActionPurge, map[string]any{"project": project, "older_than_days": req.OlderThanDays}, resp.Count, resp.DryRun)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleAdminRenameLabel(w http.ResponseWriter, r *http.Request) {
+	var req api.LabelRenameRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	from, err := normalizeLabel(req.From)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+	to, err := normalizeLabel(req.To)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if from == to {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("from and to must differ"), ErrCodeInvalidLabel))
+		return
+	}
+
+	project := ""
+	if strings.TrimSpace(req.Project) != "" {
+		normalized, err := normalizePrefix(req.Project)
+		if err != nil {
+			s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("invalid project"), ErrCodeInvalidArgument))
+			return
+		}
+		project = normalized
+	}
+
+	ids, err := s.service.RenameLabel(r.Context(), project, from, to)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+
+	s.log().Info("label renamed", "project", project, "from", from, "to", to, "count", len(ids))
+	s.recordAdminAudit(r, auditActionLabelRename, map[string]any{"project": project, "from": from, "to": to}, len(ids), false)
+	s.writeJSON(w, http.StatusOK, api.LabelRenameResponse{From: from, To: to, TaskIDs: ids, Count: len(ids)})
+}
+
 func (s *Server) handleAdminGCBlobs(w http.ResponseWriter, r *http.Request) {
 	if s.attachmentService == nil {
 		s.writeServiceError(w, r, internalError(fmt.Errorf("attachments are not configured")))
@@ -83,6 +176,238 @@ func (s *Server) handleAdminGCBlobs(w http.ResponseWriter, r *http.Request) {
 		ReclaimedBytes: result.ReclaimedBytes,
 		DryRun:         result.DryRun,
 	}
+	s.recordAdminAudit(r, auditActionGCBlobs, map[string]any{"batch_size": req.BatchSize, "reclaimed_bytes": resp.ReclaimedBytes}, resp.DeletedCount, resp.DryRun)
 	s.log().Debug("blob gc complete", "candidates", resp.CandidateCount, "deleted", resp.DeletedCount, "failed", resp.FailedCount, "reclaimed_bytes", resp.ReclaimedBytes, "dry_run", resp.DryRun)
 	s.writeJSON(w, http.StatusOK, resp)
 }
+
+func (s *Server) handleAdminBlobUsage(w http.ResponseWriter, r *http.Request) {
+	if s.attachmentService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("attachments are not configured")))
+		return
+	}
+
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+	report, err := s.attachmentService.BlobUsage(r.Context(), limit)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("blob usage reported", "blobs", report.BlobCount, "total_bytes", report.TotalBytes, "dedup_saved_bytes", report.DedupSavedBytes)
+	s.writeJSON(w, http.StatusOK, api.BlobUsageResponse{
+		BlobUsageStats:     report.BlobUsageStats,
+		DedupSavedBytes:    report.DedupSavedBytes,
+		AttachmentsPerBlob: report.AttachmentsPerBlob,
+		Largest:            report.Largest,
+	})
+}
+
+func (s *Server) handleAdminListDanglingAttachments(w http.ResponseWriter, r *http.Request) {
+	if s.attachmentService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("attachments are not configured")))
+		return
+	}
+
+	attachments, err := s.attachmentService.DanglingAttachments(r.Context())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("dangling attachments listed", "count", len(attachments))
+	s.writeJSON(w, http.StatusOK, api.DanglingAttachmentsResponse{Attachments: attachments, DryRun: true})
+}
+
+func (s *Server) handleAdminRepairDanglingAttachments(w http.ResponseWriter, r *http.Request) {
+	if s.attachmentService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("attachments are not configured")))
+		return
+	}
+
+	var req api.DanglingAttachmentsRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+	if !req.DryRun && r.Header.Get("X-Confirm") != "true" {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("non-dry-run requires X-Confirm: true header"), ErrCodeMissingRequired))
+		return
+	}
+
+	s.log().Debug("dangling attachment repair requested", "action", req.Action, "dry_run", req.DryRun)
+	attachments, err := s.attachmentService.RepairDanglingAttachments(r.Context(), req.Action, !req.DryRun)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	resp := api.DanglingAttachmentsResponse{
+		Attachments: attachments,
+		Action:      strings.ToLower(strings.TrimSpace(req.Action)),
+		DryRun:      req.DryRun,
+	}
+	if !req.DryRun {
+		resp.AffectedCount = len(attachments)
+	}
+	s.recordAdminAudit(r, auditActionRepairDangling, map[string]any{"action": resp.Action, "candidates": len(attachments)}, resp.AffectedCount, resp.DryRun)
+	s.log().Info("dangling attachment repair complete", "action", resp.Action, "candidates", len(attachments), "affected", resp.AffectedCount, "dry_run", resp.DryRun)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleAdminRecompute(w http.ResponseWriter, r *http.Request) {
+	var req api.RecomputeRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+	if !req.DryRun && r.Header.Get("X-Confirm") != "true" {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("non-dry-run requires X-Confirm: true header"), ErrCodeMissingRequired))
+		return
+	}
+
+	s.log().Debug("admin recompute requested", "dry_run", req.DryRun)
+	result, err := s.service.Recompute(r.Context(), req.DryRun)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	resp := api.RecomputeResponse{
+		SchemaVersion: result.Info.SchemaVersion,
+		TaskCounts:    result.Info.TaskCounts,
+		TotalTasks:    result.Info.TotalTasks,
+		TaskRows:      result.TaskRows,
+		FTSRows:       result.FTSRows,
+		FTSMissing:    result.FTSMissing,
+		FTSOrphaned:   result.FTSOrphaned,
+		FTSStale:      result.FTSStale,
+		Corrected:     result.Corrected,
+		DryRun:        result.DryRun,
+	}
+
+	s.recordAdminAudit(r, auditActionRecompute, nil, resp.Corrected, resp.DryRun)
+	s.log().Debug("admin recompute complete", "task_rows", resp.TaskRows, "fts_rows", resp.FTSRows, "missing", len(resp.FTSMissing), "orphaned", len(resp.FTSOrphaned), "stale", len(resp.FTSStale), "corrected", resp.Corrected, "dry_run", resp.DryRun)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleAdminMaintenance(w http.ResponseWriter, r *http.Request) {
+	if s.maintenance == nil {
+		s.writeErrorReq(w, r, http.StatusNotImplemented, apiError{
+			status:  http.StatusNotImplemented,
+			code:    "not_implemented",
+			errCode: ErrCodeNotImplemented,
+			err:     fmt.Errorf("database maintenance is not supported"),
+		})
+		return
+	}
+
+	var req api.MaintenanceRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+	if (req.Vacuum || req.RebuildFTS) && r.Header.Get("X-Confirm") != "true" {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("vacuum and rebuild_fts require X-Confirm: true header"), ErrCodeMissingRequired))
+		return
+	}
+
+	s.log().Debug("admin maintenance requested", "vacuum", req.Vacuum, "rebuild_fts", req.RebuildFTS)
+	result, err := s.maintenance.Maintain(r.Context(), store.MaintenanceOptions{Vacuum: req.Vacuum, RebuildFTS: req.RebuildFTS})
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	resp := api.MaintenanceResponse{
+		IntegrityOK:     result.IntegrityOK,
+		IntegrityErrors: result.IntegrityErrors,
+		FTSRebuilt:      result.FTSRebuilt,
+		FTSRows:         result.FTSRows,
+		Vacuumed:        result.Vacuumed,
+		SizeBeforeBytes: result.SizeBeforeBytes,
+		SizeAfterBytes:  result.SizeAfterBytes,
+		FreePagesBefore: result.FreePagesBefore,
+		FreePagesAfter:  result.FreePagesAfter,
+	}
+	if !resp.IntegrityOK {
+		s.log().Error("database integrity check failed", "problems", len(resp.IntegrityErrors))
+	}
+	s.recordAdminAudit(r, auditActionMaintenance, map[string]any{
+		"vacuum":       req.Vacuum,
+		"rebuild_fts":  req.RebuildFTS,
+		"integrity_ok": resp.IntegrityOK,
+		"reclaimed":    resp.SizeBeforeBytes - resp.SizeAfterBytes,
+	}, len(resp.IntegrityErrors), false)
+	s.log().Debug("admin maintenance complete", "integrity_ok", resp.IntegrityOK, "vacuumed", resp.Vacuumed, "fts_rebuilt", resp.FTSRebuilt, "size_before", resp.SizeBeforeBytes, "size_after", resp.SizeAfterBytes)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleAdminGetMaintenanceMode(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.maintenanceMode.status())
+}
+
+func (s *Server) handleAdminSetMaintenanceMode(w http.ResponseWriter, r *http.Request) {
+	var req api.MaintenanceModeRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	resp, err := s.maintenanceMode.set(req, time.Now().UTC())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	action := auditActionMaintenanceOff
+	params := map[string]any(nil)
+	if resp.Enabled {
+		action = auditActionMaintenanceOn
+		params = map[string]any{
+			"mode":                  resp.Mode,
+			"reason":                resp.Reason,
+			"retry_after_seconds":   resp.RetryAfterSeconds,
+			"queue_timeout_seconds": resp.QueueTimeoutSeconds,
+		}
+	}
+	s.recordAdminAudit(r, action, params, 0, false)
+	s.log().Info("maintenance mode changed", "enabled", resp.Enabled, "mode", resp.Mode, "reason", resp.Reason, "queued", resp.Queued)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleAdminListAudit(w http.ResponseWriter, r *http.Request) {
+	if s.adminAudit == nil {
+		s.writeErrorReq(w, r, http.StatusNotImplemented, apiError{
+			status:  http.StatusNotImplemented,
+			code:    "not_implemented",
+			errCode: ErrCodeNotImplemented,
+			err:     fmt.Errorf("admin audit is not supported"),
+		})
+		return
+	}
+
+	page, err := s.parseReportPage(r)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+	since, err := parseTimeFilter(r, "since")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("invalid since: %w", err), ErrCodeInvalidTimeFilter))
+		return
+	}
+
+	entries, err := s.adminAudit.ListAdminAudit(r.Context(), store.AdminAuditFilter{
+		Action: strings.TrimSpace(r.URL.Query().Get("action")),
+		Actor:  strings.TrimSpace(r.URL.Query().Get("actor")),
+		Since:  since,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	})
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, entries)
+}
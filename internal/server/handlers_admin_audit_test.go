@@ -0,0 +1,76 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"grns/internal/models"
+)
+
+func TestAdminAuditRecordsAdminActions(t *testing.T) {
+	srv := newListTestServer(t)
+	h := srv.routes()
+
+	send := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("X-Actor", "alice")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+	listAudit := func(query string) []models.AdminAuditEntry {
+		t.Helper()
+		w := send(http.MethodGet, "/v1/admin/audit"+query, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("audit list: expected 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var entries []models.AdminAuditEntry
+		if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+			t.Fatalf("decode audit: %v", err)
+		}
+		return entries
+	}
+
+	if entries := listAudit(""); len(entries) != 0 {
+		t.Fatalf("expected empty audit log, got %+v", entries)
+	}
+
+	if w := send(http.MethodPost, "/v1/admin/cleanup", []byte(`{"older_than_days":30,"dry_run":true}`)); w.Code != http.StatusOK {
+		t.Fatalf("cleanup: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, "/v1/admin/users", []byte(`{"username":"bob","password":"s3cret-pass"}`)); w.Code != http.StatusCreated {
+		t.Fatalf("user add: expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	entries := listAudit("")
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %+v", entries)
+	}
+	if entries[0].Action != auditActionUserAdd || entries[1].Action != auditActionCleanup {
+		t.Fatalf("expected newest first, got %s then %s", entries[0].Action, entries[1].Action)
+	}
+	if entries[0].Actor != "alice" || entries[0].Params["username"] != "bob" || entries[0].Affected != 1 {
+		t.Fatalf("unexpected user.add entry: %+v", entries[0])
+	}
+	if _, ok := entries[0].Params["password"]; ok {
+		t.Fatalf("password must not be audited: %+v", entries[0].Params)
+	}
+	if !entries[1].DryRun {
+		t.Fatalf("expected cleanup entry to be a dry run: %+v", entries[1])
+	}
+
+	filtered := listAudit("?action=cleanup")
+	if len(filtered) != 1 || filtered[0].Action != auditActionCleanup {
+		t.Fatalf("expected one cleanup entry, got %+v", filtered)
+	}
+	if limited := listAudit("?limit=1"); len(limited) != 1 || limited[0].Action != auditActionUserAdd {
+		t.Fatalf("expected limit=1 to return newest entry, got %+v", limited)
+	}
+	if w := send(http.MethodGet, "/v1/admin/audit?since=nope", nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for bad since, got %d", w.Code)
+	}
+}
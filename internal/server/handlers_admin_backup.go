@@ -0,0 +1,108 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"grns/internal/api"
+)
+
+const (
+	backupModeStream = "stream"
+	backupModeDir    = "dir"
+
+	backupMediaType = "application/vnd.sqlite3"
+)
+
+func (s *Server) handleAdminBackup(w http.ResponseWriter, r *http.Request) {
+	if s.backups == nil {
+		s.writeErrorReq(w, r, http.StatusNotImplemented, apiError{
+			status:  http.StatusNotImplemented,
+			code:    "not_implemented",
+			errCode: ErrCodeNotImplemented,
+			err:     fmt.Errorf("backup is not supported"),
+		})
+		return
+	}
+
+	var req api.AdminBackupRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+	mode := strings.ToLower(strings.TrimSpace(req.Mode))
+	if mode == "" {
+		mode = backupModeStream
+		if s.backupDir != "" {
+			mode = backupModeDir
+		}
+	}
+
+	now := time.Now().UTC()
+	switch mode {
+	case backupModeStream:
+		s.streamBackup(w, r, backupFileName(now))
+	case backupModeDir:
+		if s.backupDir == "" {
+			s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("backup.dir is not configured; use mode %q", backupModeStream), ErrCodeInvalidArgument))
+			return
+		}
+		s.writeBackupToDir(w, r, now)
+	default:
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("invalid mode %q (allowed: %s, %s)", req.Mode, backupModeStream, backupModeDir), ErrCodeInvalidArgument))
+	}
+}
+
+// streamBackup snapshots into a private temp file, then sends it. The
+// snapshot is complete before the first byte goes out, so a failed backup
+// still yields a JSON error instead of a truncated download.
+func (s *Server) streamBackup(w http.ResponseWriter, r *http.Request, name string) {
+	tmpDir, err := os.MkdirTemp("", "grns-backup-")
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, name)
+	if err := s.backups.BackupTo(r.Context(), path); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.recordAdminAudit(r, auditActionBackup, map[string]any{"mode": backupModeStream, "size_bytes": info.Size()}, 1, false)
+	w.Header().Set("Content-Type", backupMediaType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, file); err != nil {
+		s.log().Warn("backup stream interrupted", "error", err)
+	}
+}
+
+func (s *Server) writeBackupToDir(w http.ResponseWriter, r *http.Request, now time.Time) {
+	path, size, err := s.backupToDir(r.Context(), now)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	s.recordAdminAudit(r, auditActionBackup, map[string]any{"mode": backupModeDir, "path": path, "size_bytes": size}, 1, false)
+	s.writeJSON(w, http.StatusOK, api.AdminBackupResponse{Path: path, SizeBytes: size, CreatedAt: now})
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+)
+
+func TestHandleAdminRecompute(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-ar01", "recompute me", 2)
+
+	t.Run("non-dry-run requires confirm header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/recompute", bytes.NewReader([]byte(`{"dry_run":false}`)))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d (%s)", w.Code, w.Body.String())
+		}
+		var errResp api.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+			t.Fatalf("decode error response: %v", err)
+		}
+		if errResp.ErrorCode != ErrCodeMissingRequired {
+			t.Fatalf("expected error_code %d, got %d", ErrCodeMissingRequired, errResp.ErrorCode)
+		}
+	})
+
+	t.Run("dry run reports counts", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/recompute", bytes.NewReader([]byte(`{"dry_run":true}`)))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var resp api.RecomputeResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if !resp.DryRun || resp.TotalTasks != 1 || resp.TaskRows != 1 || resp.FTSRows != 1 || resp.Corrected != 0 {
+			t.Fatalf("unexpected response: %#v", resp)
+		}
+	})
+}
+
+func TestHandleAdminMaintenance(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-am01", "maintain me", 2)
+
+	send := func(body string, confirm bool) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/maintenance", bytes.NewReader([]byte(body)))
+		if confirm {
+			req.Header.Set("X-Confirm", "true")
+		}
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) api.MaintenanceResponse {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var resp api.MaintenanceResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp
+	}
+
+	resp := decode(send(`{}`, false))
+	if !resp.IntegrityOK || resp.Vacuumed || resp.FTSRebuilt || resp.SizeAfterBytes == 0 {
+		t.Fatalf("unexpected check-only response: %#v", resp)
+	}
+
+	if w := send(`{"vacuum":true}`, false); w.Code != http.StatusBadRequest {
+		t.Fatalf("vacuum without confirm: expected 400, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	resp = decode(send(`{"vacuum":true,"rebuild_fts":true}`, true))
+	if !resp.IntegrityOK || !resp.Vacuumed || !resp.FTSRebuilt || resp.FTSRows != 1 || resp.FreePagesAfter != 0 {
+		t.Fatalf("unexpected maintenance response: %#v", resp)
+	}
+}
+
+func TestHandleAdminReindex(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-ri01", "reindex me", 2)
+	seedListTask(t, srv, "gr-ri02", "reindex me too", 2)
+
+	send := func(method, body string, confirm bool) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, "/v1/admin/reindex", bytes.NewReader([]byte(body)))
+		if confirm {
+			req.Header.Set("X-Confirm", "true")
+		}
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder, status int) api.ReindexStatus {
+		t.Helper()
+		if w.Code != status {
+			t.Fatalf("expected %d, got %d (%s)", status, w.Code, w.Body.String())
+		}
+		var resp api.ReindexStatus
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp
+	}
+
+	if resp := decode(send(http.MethodGet, "", false), http.StatusOK); resp.State != api.ReindexStateIdle {
+		t.Fatalf("expected idle before any run, got %#v", resp)
+	}
+	if w := send(http.MethodPost, `{}`, false); w.Code != http.StatusBadRequest {
+		t.Fatalf("reindex without confirm: expected 400, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, `{"batch_size":100000}`, true); w.Code != http.StatusBadRequest {
+		t.Fatalf("oversized batch: expected 400, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	started := decode(send(http.MethodPost, `{"batch_size":1}`, true), http.StatusAccepted)
+	if started.State != api.ReindexStateRunning || started.StartedAt == nil {
+		t.Fatalf("unexpected start response: %#v", started)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	var resp api.ReindexStatus
+	for {
+		resp = decode(send(http.MethodGet, "", false), http.StatusOK)
+		if resp.State != api.ReindexStateRunning || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if resp.State != api.ReindexStateDone || resp.Processed != 2 || resp.Total != 2 || resp.FinishedAt == nil {
+		t.Fatalf("unexpected final status: %#v", resp)
+	}
+}
+
+func TestHandleAdminRenameLabel(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-lr01", "rename me", 2)
+	if err := srv.store.AddLabels(context.Background(), "gr-lr01", []string{"bakend"}); err != nil {
+		t.Fatalf("add labels: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/labels/rename", bytes.NewReader([]byte(`{"from":"bakend","to":"Backend","project":"gr"}`)))
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var resp api.LabelRenameResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.To != "backend" || resp.Count != 1 || len(resp.TaskIDs) != 1 || resp.TaskIDs[0] != "gr-lr01" {
+		t.Fatalf("unexpected response: %#v", resp)
+	}
+
+	same := httptest.NewRequest(http.MethodPost, "/v1/admin/labels/rename", bytes.NewReader([]byte(`{"from":"backend","to":"backend"}`)))
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, same)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for identical labels, got %d (%s)", w.Code, w.Body.String())
+	}
+}
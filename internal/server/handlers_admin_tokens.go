@@ -0,0 +1,143 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/store"
+)
+
+func (s *Server) handleAdminCreateToken(w http.ResponseWriter, r *http.Request) {
+	if !s.requireAPITokenService(w, r) {
+		return
+	}
+
+	var req api.AdminTokenCreateRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	now := time.Now().UTC()
+	token, secret, err := s.authService.CreateAPIToken(r.Context(), req.Name, req.Scopes, req.ExpiresAt, now)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.recordAdminAudit(r, auditActionTokenCreate, map[string]any{"token_id": token.ID, "name": token.Name, "scopes": token.Scopes}, 1, false)
+	resp := toAPIAdminToken(*token, now)
+	resp.Token = secret
+	s.writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *Server) handleAdminListTokens(w http.ResponseWriter, r *http.Request) {
+	if !s.requireAPITokenService(w, r) {
+		return
+	}
+
+	tokens, err := s.authService.ListAPITokens(r.Context())
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	resp := make([]api.AdminToken, 0, len(tokens))
+	for _, token := range tokens {
+		resp = append(resp, toAPIAdminToken(token, now))
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleAdminGetToken(w http.ResponseWriter, r *http.Request) {
+	if !s.requireAPITokenService(w, r) {
+		return
+	}
+	id, ok := s.pathTokenIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := s.authService.GetAPIToken(r.Context(), id)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, toAPIAdminToken(*token, time.Now().UTC()))
+}
+
+func (s *Server) handleAdminRotateToken(w http.ResponseWriter, r *http.Request) {
+	if !s.requireAPITokenService(w, r) {
+		return
+	}
+	id, ok := s.pathTokenIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	token, secret, err := s.authService.RotateAPIToken(r.Context(), id, now)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.recordAdminAudit(r, auditActionTokenRotate, map[string]any{"token_id": token.ID, "name": token.Name}, 1, false)
+	resp := toAPIAdminToken(*token, now)
+	resp.Token = secret
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleAdminDeleteToken(w http.ResponseWriter, r *http.Request) {
+	if !s.requireAPITokenService(w, r) {
+		return
+	}
+	id, ok := s.pathTokenIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.authService.DeleteAPIToken(r.Context(), id); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	s.recordAdminAudit(r, auditActionTokenDelete, map[string]any{"token_id": id}, 1, false)
+	s.writeJSON(w, http.StatusOK, api.AdminTokenDeleteResponse{ID: id, Deleted: true})
+}
+
+func (s *Server) requireAPITokenService(w http.ResponseWriter, r *http.Request) bool {
+	if s.authService != nil && s.authService.tokens != nil {
+		return true
+	}
+	s.writeErrorReq(w, r, http.StatusNotImplemented, apiError{
+		status:  http.StatusNotImplemented,
+		code:    "not_implemented",
+		errCode: ErrCodeNotImplemented,
+		err:     fmt.Errorf("api token management is not supported"),
+	})
+	return false
+}
+
+func (s *Server) pathTokenIDOrBadRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := strings.TrimSpace(r.PathValue("id"))
+	if id == "" {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("token id is required"), ErrCodeMissingRequired))
+		return "", false
+	}
+	return id, true
+}
+
+func toAPIAdminToken(token store.APIToken, now time.Time) api.AdminToken {
+	return api.AdminToken{
+		ID:        token.ID,
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		ExpiresAt: token.ExpiresAt,
+		Expired:   token.Expired(now),
+		CreatedAt: token.CreatedAt,
+		RotatedAt: token.RotatedAt,
+	}
+}
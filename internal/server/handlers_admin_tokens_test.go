@@ -0,0 +1,121 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"grns/internal/api"
+)
+
+func TestAdminTokenHandlersLifecycle(t *testing.T) {
+	srv := newListTestServer(t)
+	h := srv.routes()
+
+	send := func(method, path, bearer string, body []byte) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+	decodeToken := func(w *httptest.ResponseRecorder) api.AdminToken {
+		t.Helper()
+		var token api.AdminToken
+		if err := json.Unmarshal(w.Body.Bytes(), &token); err != nil {
+			t.Fatalf("decode token: %v", err)
+		}
+		return token
+	}
+
+	if w := send(http.MethodPost, "/v1/admin/tokens", "", []byte(`{"name":"bad","scopes":["root"]}`)); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown scope, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w := send(http.MethodPost, "/v1/admin/tokens", "", []byte(`{"name":"ops","scopes":["admin","read","write"]}`))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create admin token: expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	ops := decodeToken(w)
+	if ops.Token == "" || len(ops.Scopes) != 3 || ops.Scopes[0] != "read" {
+		t.Fatalf("unexpected admin token: %+v", ops)
+	}
+
+	// The first token turns on auth for /v1/*.
+	if w := send(http.MethodGet, "/v1/projects/gr/tasks", "", nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", w.Code)
+	}
+
+	w = send(http.MethodPost, "/v1/admin/tokens", ops.Token, []byte(`{"name":"reader","scopes":["read"]}`))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create reader token: expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	reader := decodeToken(w)
+	if w := send(http.MethodPost, "/v1/admin/tokens", ops.Token, []byte(`{"name":"reader"}`)); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for duplicate name, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	if w := send(http.MethodGet, "/v1/projects/gr/tasks", reader.Token, nil); w.Code != http.StatusOK {
+		t.Fatalf("reader list: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, "/v1/projects/gr/tasks", reader.Token, []byte(`{"title":"nope"}`)); w.Code != http.StatusForbidden {
+		t.Fatalf("reader create: expected 403, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/v1/admin/tokens", reader.Token, nil); w.Code != http.StatusForbidden {
+		t.Fatalf("reader admin list: expected 403, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w = send(http.MethodGet, "/v1/admin/tokens", ops.Token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list tokens: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var tokens []api.AdminToken
+	if err := json.Unmarshal(w.Body.Bytes(), &tokens); err != nil {
+		t.Fatalf("decode token list: %v", err)
+	}
+	if len(tokens) != 2 || tokens[0].Token != "" || tokens[1].Token != "" {
+		t.Fatalf("expected two tokens without secrets, got %+v", tokens)
+	}
+
+	w = send(http.MethodPost, "/v1/admin/tokens/"+reader.ID+"/rotate", ops.Token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("rotate: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	rotated := decodeToken(w)
+	if rotated.Token == "" || rotated.Token == reader.Token || rotated.RotatedAt == nil {
+		t.Fatalf("unexpected rotated token: %+v", rotated)
+	}
+	if w := send(http.MethodGet, "/v1/projects/gr/tasks", reader.Token, nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("old secret: expected 401, got %d", w.Code)
+	}
+	if w := send(http.MethodGet, "/v1/projects/gr/tasks", rotated.Token, nil); w.Code != http.StatusOK {
+		t.Fatalf("rotated secret: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	if w := send(http.MethodDelete, "/v1/admin/tokens/"+reader.ID, ops.Token, nil); w.Code != http.StatusOK {
+		t.Fatalf("delete: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	w = send(http.MethodGet, "/v1/admin/tokens/"+reader.ID, ops.Token, nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("get deleted: expected 404, got %d (%s)", w.Code, w.Body.String())
+	}
+	var errResp api.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if errResp.ErrorCode != ErrCodeAPITokenNotFound {
+		t.Fatalf("expected error_code %d, got %d", ErrCodeAPITokenNotFound, errResp.ErrorCode)
+	}
+
+	if w := send(http.MethodDelete, "/v1/admin/tokens/"+ops.ID, ops.Token, nil); w.Code != http.StatusOK {
+		t.Fatalf("delete last token: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/v1/projects/gr/tasks", "", nil); w.Code != http.StatusOK {
+		t.Fatalf("expected API open again after last token revoked, got %d", w.Code)
+	}
+}
@@ -40,6 +40,7 @@ func (s *Server) handleAdminCreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAdminAudit(r, auditActionUserAdd, map[string]any{"username": created.Username}, 1, false)
 	s.writeJSON(w, http.StatusCreated, toAPIAdminUser(*created))
 }
 
@@ -104,6 +105,11 @@ func (s *Server) handleAdminSetUserDisabled(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	action := auditActionUserEnable
+	if req.Disabled {
+		action = auditActionUserDisable
+	}
+	s.recordAdminAudit(r, action, map[string]any{"username": updated.Username}, 1, false)
 	s.writeJSON(w, http.StatusOK, toAPIAdminUser(*updated))
 }
 
@@ -139,7 +145,9 @@ func (s *Server) handleAdminDeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.writeJSON(w, http.StatusOK, api.AdminUserDeleteResponse{Username: strings.ToLower(strings.TrimSpace(username)), Deleted: true})
+	deletedUsername := strings.ToLower(strings.TrimSpace(username))
+	s.recordAdminAudit(r, auditActionUserDelete, map[string]any{"username": deletedUsername}, 1, false)
+	s.writeJSON(w, http.StatusOK, api.AdminUserDeleteResponse{Username: deletedUsername, Deleted: true})
 }
 
 func pathUsername(r *http.Request) (string, error) {
@@ -2,10 +2,11 @@ package server
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
-	"io"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -83,6 +84,7 @@ func (s *Server) handleCreateTaskAttachment(w http.ResponseWriter, r *http.Reque
 		MediaTypeSource:   mediaTypeSource,
 		DeclaredMediaType: declaredMediaType,
 		SniffedMediaType:  detectedMediaType,
+		ExpectedSHA256:    firstNonEmpty(strings.TrimSpace(r.Header.Get("X-Content-SHA256")), strings.TrimSpace(r.FormValue("content_sha256"))),
 		Labels:            labels,
 		Meta:              map[string]any{},
 		ExpiresAt:         expiresAt,
@@ -96,6 +98,34 @@ func (s *Server) handleCreateTaskAttachment(w http.ResponseWriter, r *http.Reque
 	s.writeJSON(w, http.StatusCreated, attachment)
 }
 
+// handleUploadBlob stores a raw request body as a managed blob. Archive imports
+// upload blobs this way before the records that reference them.
+func (s *Server) handleUploadBlob(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	if s.attachmentService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("attachments are not configured")))
+		return
+	}
+
+	maxBody := s.attachmentUploadMaxBody
+	if maxBody <= 0 {
+		maxBody = defaultAttachmentUploadMaxBody
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBody)
+
+	blob, err := s.attachmentService.PutBlob(r.Context(), r.Body, r.Header.Get("X-Content-SHA256"))
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("blob uploaded", "blob_id", blob.ID, "sha256", blob.SHA256, "size_bytes", blob.SizeBytes)
+	s.writeJSON(w, http.StatusCreated, blob)
+}
+
 func (s *Server) handleCreateTaskAttachmentLink(w http.ResponseWriter, r *http.Request) {
 	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
 		return
@@ -214,16 +244,91 @@ func (s *Server) handleGetAttachmentContent(w http.ResponseWriter, r *http.Reque
 
 	w.Header().Set("Content-Type", content.MediaType)
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", content.Filename))
-	if content.SizeBytes >= 0 {
-		w.Header().Set("Content-Length", fmt.Sprintf("%d", content.SizeBytes))
+	if content.SHA256 != "" {
+		// Blob content never changes, so its digest is a strong validator.
+		w.Header().Set("ETag", `"`+content.SHA256+`"`)
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+	// ServeContent answers Range, If-Range, If-None-Match and HEAD requests.
+	http.ServeContent(w, r, "", content.ModTime, content.Reader)
+	s.log().Debug("attachment content served", "attachment_id", attachmentID, "range", r.Header.Get("Range"), "bytes", content.SizeBytes)
+}
+
+func (s *Server) handleGetAttachmentPreview(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
 	}
-	w.WriteHeader(http.StatusOK)
-	written, err := io.Copy(w, content.Reader)
+
+	if s.attachmentService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("attachments are not configured")))
+		return
+	}
+
+	attachmentID, err := requireAttachmentID(r)
 	if err != nil {
-		s.log().Warn("stream attachment content", "attachment_id", attachmentID, "error", err)
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+	size, err := queryInt(r, "size")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
 		return
 	}
-	s.log().Debug("attachment content streamed", "attachment_id", attachmentID, "bytes", written)
+	textBytes, err := queryInt(r, "bytes")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+	preview, err := s.attachmentService.AttachmentPreview(r.Context(), attachmentID, size, textBytes)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", preview.MediaType)
+	w.Header().Set("Content-Disposition", "inline")
+	w.Header().Set("ETag", preview.ETag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("X-Preview-Truncated", strconv.FormatBool(preview.Truncated))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(preview.Data))
+	s.log().Debug("attachment preview served", "attachment_id", attachmentID, "media_type", preview.MediaType, "bytes", len(preview.Data), "truncated", preview.Truncated)
+}
+
+func (s *Server) handleUpdateAttachment(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	if s.attachmentService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("attachments are not configured")))
+		return
+	}
+
+	attachmentID, err := requireAttachmentID(r)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req api.AttachmentUpdateRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	attachment, err := s.attachmentService.UpdateAttachment(r.Context(), attachmentID, UpdateAttachmentInput{
+		Title:          req.Title,
+		Labels:         req.Labels,
+		Meta:           req.Meta,
+		ExpiresAt:      req.ExpiresAt,
+		ClearExpiresAt: req.ClearExpiresAt,
+	})
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("attachment updated", "attachment_id", attachmentID)
+	s.writeJSON(w, http.StatusOK, attachment)
 }
 
 func (s *Server) handleDeleteAttachment(w http.ResponseWriter, r *http.Request) {
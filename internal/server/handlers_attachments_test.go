@@ -3,8 +3,13 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/color"
+	"image/png"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -13,6 +18,7 @@ import (
 	"testing"
 
 	"grns/internal/api"
+	"grns/internal/blobstore"
 	"grns/internal/models"
 	storepkg "grns/internal/store"
 )
@@ -97,6 +103,73 @@ func TestAttachmentLinkCRUDHandlers(t *testing.T) {
 	}
 }
 
+func TestAttachmentUpdateHandler(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-at01", "attachment task", 2)
+
+	body, _ := json.Marshal(api.AttachmentCreateLinkRequest{
+		Kind:        string(models.AttachmentKindSpec),
+		Title:       "Desgin spec",
+		ExternalURL: "https://example.com/spec.pdf",
+		Labels:      []string{"draft"},
+		Meta:        map[string]any{"rev": "1"},
+	})
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/gr-at01/attachments/link", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create attachment: %d (%s)", w.Code, w.Body.String())
+	}
+	var created models.Attachment
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode attachment: %v", err)
+	}
+
+	patch := func(payload string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/v1/projects/gr/attachments/"+created.ID, strings.NewReader(payload)))
+		return w
+	}
+
+	w = patch(`{"title":" Design spec ","labels":["Final"],"meta":{"rev":"2"},"expires_at":"2099-01-01T00:00:00Z"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch attachment: %d (%s)", w.Code, w.Body.String())
+	}
+	var updated models.Attachment
+	if err := json.Unmarshal(w.Body.Bytes(), &updated); err != nil {
+		t.Fatalf("decode updated attachment: %v", err)
+	}
+	if updated.Title != "Design spec" || len(updated.Labels) != 1 || updated.Labels[0] != "final" || updated.Meta["rev"] != "2" {
+		t.Fatalf("unexpected updated attachment: %#v", updated)
+	}
+	if updated.ExpiresAt == nil || updated.ExpiresAt.Year() != 2099 || updated.ExternalURL != created.ExternalURL {
+		t.Fatalf("expected expiry set and source unchanged, got %#v", updated)
+	}
+
+	w = patch(`{"labels":[],"clear_expires_at":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("clear attachment fields: %d (%s)", w.Code, w.Body.String())
+	}
+	updated = models.Attachment{}
+	if err := json.Unmarshal(w.Body.Bytes(), &updated); err != nil {
+		t.Fatalf("decode cleared attachment: %v", err)
+	}
+	if len(updated.Labels) != 0 || updated.ExpiresAt != nil || updated.Title != "Design spec" {
+		t.Fatalf("expected labels and expiry cleared with title kept, got %#v", updated)
+	}
+
+	if w := patch(`{}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an empty patch, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := patch(`{"expires_at":"2000-01-01T00:00:00Z"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for expires_at before created_at, got %d (%s)", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/v1/projects/gr/attachments/at-zzzz", strings.NewReader(`{"title":"x"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown attachment, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
 func TestAttachmentManagedUploadHandler(t *testing.T) {
 	srv := newListTestServer(t)
 	seedListTask(t, srv, "gr-am01", "managed attachment task", 2)
@@ -195,6 +268,36 @@ func TestAttachmentContentManaged(t *testing.T) {
 	if got := w.Body.String(); got != "hello attachment content" {
 		t.Fatalf("unexpected content body %q", got)
 	}
+	sum := sha256.Sum256([]byte("hello attachment content"))
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	if got := w.Header().Get("ETag"); got != etag {
+		t.Fatalf("expected ETag %s, got %q", etag, got)
+	}
+	if got := w.Header().Get("Accept-Ranges"); got != "bytes" {
+		t.Fatalf("expected Accept-Ranges bytes, got %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/projects/gr/attachments/"+created.ID+"/content", nil)
+	req.Header.Set("Range", "bytes=6-")
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("expected 206 for a range request, got %d (%s)", w.Code, w.Body.String())
+	}
+	if got := w.Body.String(); got != "attachment content" {
+		t.Fatalf("unexpected ranged body %q", got)
+	}
+	if got := w.Header().Get("Content-Range"); got != "bytes 6-23/24" {
+		t.Fatalf("unexpected Content-Range %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/projects/gr/attachments/"+created.ID+"/content", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("expected 304 for a matching If-None-Match, got %d (%s)", w.Code, w.Body.String())
+	}
 }
 
 func TestAttachmentContentNonManagedRejected(t *testing.T) {
@@ -238,6 +341,98 @@ func TestAttachmentContentNonManagedRejected(t *testing.T) {
 	}
 }
 
+func TestAttachmentPreviewHandler(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-pv01", "preview task", 2)
+
+	upload := func(filename string, content []byte) models.Attachment {
+		t.Helper()
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("kind", string(models.AttachmentKindArtifact))
+		part, err := writer.CreateFormFile("content", filename)
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		if _, err := part.Write(content); err != nil {
+			t.Fatalf("write form content: %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("close multipart writer: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/gr-pv01/attachments", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d (%s)", w.Code, w.Body.String())
+		}
+		var created models.Attachment
+		if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+			t.Fatalf("decode attachment: %v", err)
+		}
+		return created
+	}
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, 600, 300))
+	for y := range 300 {
+		for x := range 600 {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, src); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	picture := upload("diagram.png", pngData.Bytes())
+
+	w := get("/v1/projects/gr/attachments/" + picture.ID + "/preview?size=120")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("expected a png preview, got %d %q (%s)", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+	}
+	thumb, err := png.Decode(w.Body)
+	if err != nil {
+		t.Fatalf("decode preview: %v", err)
+	}
+	if got := thumb.Bounds().Size(); got != image.Pt(120, 60) {
+		t.Fatalf("expected a 120x60 thumbnail, got %v", got)
+	}
+	if w.Header().Get("X-Preview-Truncated") != "true" {
+		t.Fatalf("expected scaled preview to be marked truncated")
+	}
+	etag := w.Header().Get("ETag")
+	if !strings.Contains(etag, "-image-120") {
+		t.Fatalf("unexpected preview ETag %q", etag)
+	}
+	if w := get("/v1/projects/gr/attachments/"+picture.ID+"/preview?size=120", "If-None-Match", etag); w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for a cached preview, got %d", w.Code)
+	}
+
+	text := upload("notes.txt", []byte("héllo preview text"))
+	w = get("/v1/projects/gr/attachments/" + text.ID + "/preview?bytes=2")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("expected a text preview, got %d %q (%s)", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+	}
+	if got := w.Body.String(); got != "h" || w.Header().Get("X-Preview-Truncated") != "true" {
+		t.Fatalf("expected the preview cut before a split rune, got %q", got)
+	}
+
+	binary := upload("blob.bin", []byte{0x00, 0x01, 0x02, 0xff})
+	if w := get("/v1/projects/gr/attachments/" + binary.ID + "/preview"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a binary attachment, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
 func TestAttachmentBlobGCEndToEnd(t *testing.T) {
 	srv := newListTestServer(t)
 	seedListTask(t, srv, "gr-gc01", "gc task", 2)
@@ -357,3 +552,63 @@ func (zeroReader) Read(p []byte) (int, error) {
 	}
 	return len(p), nil
 }
+
+type deleteRecordingBlobStore struct {
+	blobstore.BlobStore
+	deleted []string
+}
+
+func (d *deleteRecordingBlobStore) Delete(ctx context.Context, key string) error {
+	d.deleted = append(d.deleted, key)
+	return d.BlobStore.Delete(ctx, key)
+}
+
+func TestAttachmentManagedUploadVerifiesDeclaredChecksum(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-ck01", "checksum task", 2)
+	recorder := &deleteRecordingBlobStore{BlobStore: srv.attachmentService.blobStore}
+	srv.attachmentService.blobStore = recorder
+
+	upload := func(content, declared string) *httptest.ResponseRecorder {
+		t.Helper()
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("kind", string(models.AttachmentKindArtifact))
+		part, err := writer.CreateFormFile("content", "artifact.txt")
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		if _, err := part.Write([]byte(content)); err != nil {
+			t.Fatalf("write form content: %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("close multipart writer: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/gr-ck01/attachments", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("X-Content-SHA256", declared)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	sum := sha256.Sum256([]byte("checked content"))
+	if w := upload("checked content", strings.ToUpper(hex.EncodeToString(sum[:]))); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for matching checksum, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w := upload("tampered content", hex.EncodeToString(sum[:]))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for mismatched checksum, got %d (%s)", w.Code, w.Body.String())
+	}
+	var errResp api.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if errResp.ErrorCode != ErrCodeInvalidArgument || !strings.Contains(errResp.Error, "content checksum mismatch") {
+		t.Fatalf("unexpected error response: %+v", errResp)
+	}
+	if len(recorder.deleted) != 1 {
+		t.Fatalf("expected mismatched blob to be deleted, got deletes %v", recorder.deleted)
+	}
+}
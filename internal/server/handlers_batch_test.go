@@ -11,6 +11,7 @@ import (
 
 	"grns/internal/api"
 	"grns/internal/models"
+	"grns/internal/store"
 )
 
 func TestHandleBatchCreateAllOrNothing(t *testing.T) {
@@ -212,3 +213,95 @@ func TestHandleCreateTask_MissingDependencyParentReturns400(t *testing.T) {
 		t.Fatalf("expected error_code %d, got %d", ErrCodeInvalidDependency, errResp.ErrorCode)
 	}
 }
+
+func TestHandleBatchCreateStreamRejectsBadItem(t *testing.T) {
+	srv := newListTestServer(t)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/batch", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`[{"title":"ok"},{"title":""},{"title":`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d (%s)", w.Code, w.Body.String())
+	}
+	var errResp api.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if errResp.ErrorCode != ErrCodeMissingRequired || errResp.Error != "tasks[1]: title is required" {
+		t.Fatalf("expected the bad item to be reported before the truncated tail, got %+v", errResp)
+	}
+
+	for _, body := range []string{`{"title":"x"}`, `[{"title":"x"}] []`, `[{"title":"x"}`, `[]`} {
+		if w := post(body); w.Code != http.StatusBadRequest {
+			t.Fatalf("body %s: expected 400, got %d (%s)", body, w.Code, w.Body.String())
+		}
+	}
+
+	count, err := srv.store.CountTasks(context.Background(), store.ListFilter{})
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected rejected batches to create nothing, got %d", count)
+	}
+}
+
+type countingSchemaStores struct {
+	store.CustomFieldStore
+	store.MilestoneStore
+	schemaLoads     int
+	milestoneChecks int
+}
+
+func (c *countingSchemaStores) ListCustomFields(ctx context.Context, project string) ([]models.CustomField, error) {
+	c.schemaLoads++
+	return c.CustomFieldStore.ListCustomFields(ctx, project)
+}
+
+func (c *countingSchemaStores) GetMilestone(ctx context.Context, project, id string) (*models.Milestone, error) {
+	c.milestoneChecks++
+	return c.MilestoneStore.GetMilestone(ctx, project, id)
+}
+
+func TestHandleBatchCreateLoadsSchemaOncePerBatch(t *testing.T) {
+	srv := newListTestServer(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	if err := srv.service.customFields.PutCustomField(ctx, &models.CustomField{Project: "gr", Name: "team", Type: string(models.CustomFieldString), CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("put custom field: %v", err)
+	}
+	for _, id := range []string{"ms-0001", "ms-0002"} {
+		if err := srv.service.milestones.CreateMilestone(ctx, &models.Milestone{Project: "gr", ID: id, Title: id, State: "open", CreatedAt: now, UpdatedAt: now}); err != nil {
+			t.Fatalf("create milestone %s: %v", id, err)
+		}
+	}
+	counter := &countingSchemaStores{CustomFieldStore: srv.service.customFields, MilestoneStore: srv.service.milestones}
+	srv.service.customFields = counter
+	srv.service.milestones = counter
+
+	first, second := "ms-0001", "ms-0002"
+	payload := []api.TaskCreateRequest{
+		{Title: "one", MilestoneID: &first, Custom: map[string]any{"team": "infra"}},
+		{Title: "two", MilestoneID: &first, Custom: map[string]any{"team": "infra"}},
+		{Title: "three", MilestoneID: &second, Custom: map[string]any{"team": "web"}},
+		{Title: "four"},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/batch", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	if counter.schemaLoads != 1 || counter.milestoneChecks != 2 {
+		t.Fatalf("expected 1 schema load and 2 milestone checks, got %d and %d", counter.schemaLoads, counter.milestoneChecks)
+	}
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestClaimNextTask(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for _, task := range []*models.Task{
+		{ID: "gr-ck01", Title: "later", Status: "open", Type: "task", Priority: 3, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-ck02", Title: "first", Status: "open", Type: "bug", Priority: 0, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	claim := func(actor, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/next/claim", strings.NewReader(body))
+		if actor != "" {
+			req.Header.Set("X-Actor", actor)
+		}
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+	expectError := func(w *httptest.ResponseRecorder, wantStatus, wantCode int) {
+		t.Helper()
+		if w.Code != wantStatus {
+			t.Fatalf("expected %d, got %d (%s)", wantStatus, w.Code, w.Body.String())
+		}
+		var errResp api.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+			t.Fatalf("decode error response: %v", err)
+		}
+		if errResp.ErrorCode != wantCode {
+			t.Fatalf("expected error_code %d, got %d", wantCode, errResp.ErrorCode)
+		}
+	}
+
+	w := claim("", `{}`)
+	expectError(w, http.StatusBadRequest, ErrCodeMissingRequired)
+
+	w = claim("", `{"actor":"agent-a","lease_seconds":-5}`)
+	expectError(w, http.StatusBadRequest, ErrCodeInvalidArgument)
+
+	w = claim("agent-a", `{"order":"priority","lease_seconds":600}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("claim: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var resp api.TaskClaimResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode claim: %v", err)
+	}
+	if resp.Task.ID != "gr-ck02" || resp.Task.Status != "in_progress" || resp.Task.Assignee != "agent-a" {
+		t.Fatalf("unexpected claimed task: %#v", resp.Task)
+	}
+	if resp.Lease.Holder != "agent-a" || resp.Lease.ExpiresAt.Sub(resp.Lease.ClaimedAt) != 10*time.Minute {
+		t.Fatalf("unexpected lease: %#v", resp.Lease)
+	}
+
+	events, err := srv.service.History(contextWithProject(context.Background(), "gr"), "gr-ck02")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(events) == 0 || events[len(events)-1].Type != models.TaskEventClaimed || events[len(events)-1].Actor != "agent-a" {
+		t.Fatalf("expected claimed event by agent-a, got %#v", events)
+	}
+
+	w = claim("agent-b", `{"types":["bug"]}`)
+	expectError(w, http.StatusNotFound, ErrCodeTaskNotFound)
+
+	w = claim("agent-b", `{}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("second claim: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode claim: %v", err)
+	}
+	if resp.Task.ID != "gr-ck01" {
+		t.Fatalf("expected gr-ck01 for second claim, got %s", resp.Task.ID)
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func (s *Server) handleCreateTaskComment(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	if s.commentService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("comments are not configured")))
+		return
+	}
+
+	taskID, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req api.TaskCommentCreateRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	comment, err := s.commentService.Create(r.Context(), taskID, req)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("task comment created", "task_id", taskID, "comment_id", comment.ID, "author", comment.Author)
+	s.writeJSON(w, http.StatusCreated, comment)
+}
+
+func (s *Server) handleListTaskComments(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	if s.commentService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("comments are not configured")))
+		return
+	}
+
+	taskID, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	comments, err := s.commentService.List(r.Context(), taskID)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	if comments == nil {
+		comments = []models.TaskComment{}
+	}
+
+	s.log().Debug("task comments listed", "task_id", taskID, "count", len(comments))
+	s.writeJSON(w, http.StatusOK, comments)
+}
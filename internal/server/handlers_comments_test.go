@@ -0,0 +1,79 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestTaskCommentHandlers(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	task := &models.Task{ID: "gr-cm01", Title: "discussed task", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+		t.Fatalf("seed task: %v", err)
+	}
+
+	post := func(taskID string, payload api.TaskCommentCreateRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/"+taskID+"/comments", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("gr-cm01", api.TaskCommentCreateRequest{Author: "alice", Body: "  "}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty body, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := post("gr-zz99", api.TaskCommentCreateRequest{Author: "alice", Body: "hello"}); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing task, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	for _, payload := range []api.TaskCommentCreateRequest{
+		{Author: "alice", Body: "first"},
+		{Author: "agent-7", Body: "second"},
+	} {
+		if w := post("gr-cm01", payload); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d (%s)", w.Code, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/gr-cm01/comments", nil)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var comments []models.TaskComment
+	if err := json.Unmarshal(w.Body.Bytes(), &comments); err != nil {
+		t.Fatalf("decode comments: %v", err)
+	}
+	if len(comments) != 2 || comments[0].Body != "first" || comments[1].Author != "agent-7" {
+		t.Fatalf("unexpected comments: %+v", comments)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/gr-cm01?include=comments", nil)
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var resp api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode task: %v", err)
+	}
+	if len(resp.Comments) != 2 || resp.Comments[0].ID != comments[0].ID {
+		t.Fatalf("expected comments included on task, got %+v", resp.Comments)
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func (s *Server) customFieldsConfigured(w http.ResponseWriter, r *http.Request) bool {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return false
+	}
+	if s.customFieldService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("custom fields are not configured")))
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleListCustomFields(w http.ResponseWriter, r *http.Request) {
+	if !s.customFieldsConfigured(w, r) {
+		return
+	}
+
+	fields, err := s.customFieldService.List(r.Context())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	if fields == nil {
+		fields = []models.CustomField{}
+	}
+	s.writeJSON(w, http.StatusOK, fields)
+}
+
+func (s *Server) handlePutCustomField(w http.ResponseWriter, r *http.Request) {
+	if !s.customFieldsConfigured(w, r) {
+		return
+	}
+
+	var req api.CustomFieldRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	field, err := s.customFieldService.Put(r.Context(), r.PathValue("name"), req)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("custom field saved", "name", field.Name, "type", field.Type)
+	s.writeJSON(w, http.StatusOK, field)
+}
+
+func (s *Server) handleDeleteCustomField(w http.ResponseWriter, r *http.Request) {
+	if !s.customFieldsConfigured(w, r) {
+		return
+	}
+
+	name := r.PathValue("name")
+	if err := s.customFieldService.Delete(r.Context(), name); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("custom field deleted", "name", name)
+	s.writeJSON(w, http.StatusOK, map[string]any{"name": name, "deleted": true})
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestCustomFieldSchemaEnforcedOnTaskWrites(t *testing.T) {
+	srv := newListTestServer(t)
+
+	send := func(method, path string, payload any) *httptest.ResponseRecorder {
+		t.Helper()
+		var body bytes.Buffer
+		if payload != nil {
+			if err := json.NewEncoder(&body).Encode(payload); err != nil {
+				t.Fatalf("marshal payload: %v", err)
+			}
+		}
+		req := httptest.NewRequest(method, path, &body)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+	expectError := func(w *httptest.ResponseRecorder, status, code int) {
+		t.Helper()
+		if w.Code != status {
+			t.Fatalf("expected %d, got %d (%s)", status, w.Code, w.Body.String())
+		}
+		var errResp api.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+			t.Fatalf("decode error response: %v", err)
+		}
+		if errResp.ErrorCode != code {
+			t.Fatalf("expected error_code %d, got %d", code, errResp.ErrorCode)
+		}
+	}
+
+	// Without a schema custom stays free-form.
+	if w := send(http.MethodPost, "/v1/projects/gr/tasks", api.TaskCreateRequest{Title: "free", Custom: map[string]any{"anything": "goes"}}); w.Code != http.StatusCreated {
+		t.Fatalf("free-form create: expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	expectError(send(http.MethodPut, "/v1/projects/gr/custom-fields/points", api.CustomFieldRequest{Type: "number", Values: []string{"1"}}), http.StatusBadRequest, ErrCodeInvalidCustomField)
+	expectError(send(http.MethodPut, "/v1/projects/gr/custom-fields/points", api.CustomFieldRequest{Type: "date"}), http.StatusBadRequest, ErrCodeInvalidCustomField)
+	for name, req := range map[string]api.CustomFieldRequest{
+		"team":   {Type: "string", Required: true, Values: []string{"platform", "infra"}},
+		"points": {Type: "number"},
+	} {
+		if w := send(http.MethodPut, "/v1/projects/gr/custom-fields/"+name, req); w.Code != http.StatusOK {
+			t.Fatalf("put %s: expected 200, got %d (%s)", name, w.Code, w.Body.String())
+		}
+	}
+
+	w := send(http.MethodGet, "/v1/projects/gr/custom-fields", nil)
+	var fields []models.CustomField
+	if err := json.Unmarshal(w.Body.Bytes(), &fields); err != nil {
+		t.Fatalf("decode fields: %v", err)
+	}
+	if len(fields) != 2 || fields[0].Name != "points" || fields[1].Name != "team" || !fields[1].Required || len(fields[1].Values) != 2 {
+		t.Fatalf("unexpected fields: %#v", fields)
+	}
+
+	expectError(send(http.MethodPost, "/v1/projects/gr/tasks", api.TaskCreateRequest{Title: "typo", Custom: map[string]any{"team": "infra", "tema": "infra"}}), http.StatusBadRequest, ErrCodeInvalidCustomField)
+	expectError(send(http.MethodPost, "/v1/projects/gr/tasks", api.TaskCreateRequest{Title: "enum", Custom: map[string]any{"team": "sales"}}), http.StatusBadRequest, ErrCodeInvalidCustomField)
+	expectError(send(http.MethodPost, "/v1/projects/gr/tasks", api.TaskCreateRequest{Title: "type", Custom: map[string]any{"team": "infra", "points": "lots"}}), http.StatusBadRequest, ErrCodeInvalidCustomField)
+	expectError(send(http.MethodPost, "/v1/projects/gr/tasks", api.TaskCreateRequest{Title: "required"}), http.StatusBadRequest, ErrCodeMissingRequired)
+
+	w = send(http.MethodPost, "/v1/projects/gr/tasks", api.TaskCreateRequest{Title: "valid", Custom: map[string]any{"team": "infra", "points": "3"}})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("valid create: expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	var created api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode task: %v", err)
+	}
+	if created.Custom["points"] != float64(3) {
+		t.Fatalf("expected points coerced to number, got %#v", created.Custom["points"])
+	}
+
+	expectError(send(http.MethodPatch, "/v1/projects/gr/tasks/"+created.ID, api.TaskUpdateRequest{Custom: map[string]any{"points": 5}}), http.StatusBadRequest, ErrCodeMissingRequired)
+	title := "renamed"
+	if w := send(http.MethodPatch, "/v1/projects/gr/tasks/"+created.ID, api.TaskUpdateRequest{Title: &title}); w.Code != http.StatusOK {
+		t.Fatalf("update without custom: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w = send(http.MethodGet, "/v1/projects/gr/tasks?custom.team=infra&custom.points=3", nil)
+	var tasks []api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("decode tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != created.ID {
+		t.Fatalf("expected custom filter to match %s, got %#v", created.ID, tasks)
+	}
+	w = send(http.MethodGet, "/v1/projects/gr/tasks?custom_eq=team:infra&custom_exists=points", nil)
+	tasks = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("decode tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != created.ID {
+		t.Fatalf("expected custom_eq/custom_exists to match %s, got %#v", created.ID, tasks)
+	}
+	expectError(send(http.MethodGet, "/v1/projects/gr/tasks?custom.bad%20key=x", nil), http.StatusBadRequest, ErrCodeInvalidQuery)
+	expectError(send(http.MethodGet, "/v1/projects/gr/tasks?custom_eq=team", nil), http.StatusBadRequest, ErrCodeInvalidQuery)
+	expectError(send(http.MethodGet, "/v1/projects/gr/tasks?custom_exists=a.b", nil), http.StatusBadRequest, ErrCodeInvalidQuery)
+
+	if w := send(http.MethodDelete, "/v1/projects/gr/custom-fields/team", nil); w.Code != http.StatusOK {
+		t.Fatalf("delete field: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	expectError(send(http.MethodDelete, "/v1/projects/gr/custom-fields/team", nil), http.StatusNotFound, ErrCodeCustomFieldNotFound)
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestDeleteRestoreAndPurgeHandlers(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for _, task := range []*models.Task{
+		{ID: "gr-dl01", Title: "keep", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-dl02", Title: "drop", Status: "closed", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now, ClosedAt: &now},
+	} {
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	send := func(method, path string, payload any, header ...string) *httptest.ResponseRecorder {
+		t.Helper()
+		var body bytes.Buffer
+		if payload != nil {
+			if err := json.NewEncoder(&body).Encode(payload); err != nil {
+				t.Fatalf("marshal payload: %v", err)
+			}
+		}
+		req := httptest.NewRequest(method, path, &body)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+	listIDs := func(query string) []string {
+		t.Helper()
+		w := send(http.MethodGet, "/v1/projects/gr/tasks"+query, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("list: expected 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var tasks []api.TaskResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+			t.Fatalf("decode tasks: %v", err)
+		}
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	if w := send(http.MethodDelete, "/v1/projects/gr/tasks/gr-zzzz", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown task, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodDelete, "/v1/projects/gr/tasks/gr-dl02", nil); w.Code != http.StatusOK {
+		t.Fatalf("delete: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	if ids := listIDs(""); len(ids) != 1 || ids[0] != "gr-dl01" {
+		t.Fatalf("expected tombstone hidden from default list, got %v", ids)
+	}
+	if ids := listIDs("?status=tombstone"); len(ids) != 1 || ids[0] != "gr-dl02" {
+		t.Fatalf("expected tombstone listed with status filter, got %v", ids)
+	}
+
+	w := send(http.MethodPost, "/v1/projects/gr/tasks/restore", api.TaskRestoreRequest{IDs: []string{"gr-dl01"}})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 restoring live task, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, "/v1/projects/gr/tasks/restore", api.TaskRestoreRequest{IDs: []string{"gr-dl02"}}); w.Code != http.StatusOK {
+		t.Fatalf("restore: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	task, err := srv.store.GetTask(context.Background(), "gr-dl02")
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if task.Status != "closed" || task.DeletedAt != nil {
+		t.Fatalf("expected restored task closed, got status=%s deleted_at=%v", task.Status, task.DeletedAt)
+	}
+
+	if w := send(http.MethodDelete, "/v1/projects/gr/tasks/gr-dl02", nil); w.Code != http.StatusOK {
+		t.Fatalf("delete again: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	purge := api.CleanupRequest{OlderThanDays: 1}
+	if w := send(http.MethodPost, "/v1/admin/purge", purge); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without X-Confirm, got %d (%s)", w.Code, w.Body.String())
+	}
+	w = send(http.MethodPost, "/v1/admin/purge", purge, "X-Confirm", "true")
+	if w.Code != http.StatusOK {
+		t.Fatalf("purge: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var resp api.CleanupResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode purge response: %v", err)
+	}
+	if resp.Count != 0 {
+		t.Fatalf("expected fresh tombstone kept, got %#v", resp)
+	}
+}
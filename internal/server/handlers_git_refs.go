@@ -93,6 +93,32 @@ func (s *Server) handleGetTaskGitRef(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, ref)
 }
 
+func (s *Server) handleResolveTaskGitRef(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	if s.gitRefService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("git refs are not configured")))
+		return
+	}
+
+	refID, err := requireTaskGitRefID(r)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	ref, err := s.gitRefService.Resolve(r.Context(), refID)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("git ref resolved", "ref_id", refID, "resolved_commit", ref.ResolvedCommit)
+	s.writeJSON(w, http.StatusOK, ref)
+}
+
 func (s *Server) handleDeleteTaskGitRef(w http.ResponseWriter, r *http.Request) {
 	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
 		return
@@ -125,3 +151,62 @@ func requireTaskGitRefID(r *http.Request) (string, error) {
 	}
 	return id, nil
 }
+
+func (s *Server) handleTasksByCommits(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	if s.gitRefService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("git refs are not configured")))
+		return
+	}
+
+	var req api.TaskGitRefByCommitsRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	ids, err := s.gitRefService.TaskIDsByCommits(r.Context(), req)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	tasks := []api.TaskResponse{}
+	if len(ids) > 0 {
+		tasks, err = s.service.GetMany(r.Context(), ids)
+		if err != nil {
+			s.writeServiceError(w, r, err)
+			return
+		}
+	}
+
+	s.log().Debug("tasks by commits listed", "commit_count", len(req.Commits), "count", len(tasks))
+	s.writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *Server) handleScanGitRefs(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	if s.gitRefService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("git refs are not configured")))
+		return
+	}
+
+	var req api.GitRefScanRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	resp, err := s.gitRefService.ScanCommits(r.Context(), req)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("git refs scanned", "commits", resp.CommitsScanned, "created", len(resp.Created), "existing", resp.Existing, "unknown", len(resp.UnknownTaskIDs), "dry_run", resp.DryRun)
+	s.writeJSON(w, http.StatusOK, resp)
+}
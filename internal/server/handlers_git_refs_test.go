@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -230,3 +231,358 @@ func TestCloseWithCommitWithoutRepoContextDoesNotCloseTask(t *testing.T) {
 		t.Fatalf("expected task to remain open, got %q", shown.Status)
 	}
 }
+
+func TestTasksByCommitsReturnsOnlyMatchingTasks(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	commitA := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	commitB := "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	commitC := "cccccccccccccccccccccccccccccccccccccccc"
+
+	for _, id := range []string{"gr-bc01", "gr-bc02", "gr-bc03", "gr-bc04"} {
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, SourceRepo: "github.com/acme/repo", CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	refs := []struct {
+		taskID string
+		req    api.TaskGitRefCreateRequest
+	}{
+		{"gr-bc01", api.TaskGitRefCreateRequest{Relation: "closed_by", ObjectType: "commit", ObjectValue: commitA}},
+		{"gr-bc02", api.TaskGitRefCreateRequest{Relation: "closed_by", ObjectType: "tag", ObjectValue: "v1.1", ResolvedCommit: commitB}},
+		{"gr-bc03", api.TaskGitRefCreateRequest{Relation: "closed_by", ObjectType: "commit", ObjectValue: commitC}},
+		{"gr-bc04", api.TaskGitRefCreateRequest{Relation: "related", ObjectType: "commit", ObjectValue: commitA}},
+	}
+	for _, ref := range refs {
+		if _, err := srv.gitRefService.Create(context.Background(), ref.taskID, ref.req); err != nil {
+			t.Fatalf("seed ref for %s: %v", ref.taskID, err)
+		}
+	}
+
+	body, err := json.Marshal(api.TaskGitRefByCommitsRequest{Commits: []string{commitA, commitB}})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/git-refs/by-commits", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	var tasks []api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("decode tasks: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != "gr-bc01" || tasks[1].ID != "gr-bc02" {
+		t.Fatalf("expected [gr-bc01 gr-bc02], got %#v", tasks)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/projects/gr/git-refs/by-commits", bytes.NewReader([]byte(`{"commits":["not-a-sha"]}`)))
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid commit, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestScanGitRefsLinksMentionedTasks(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for _, id := range []string{"gr-sc01", "gr-sc02"} {
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	scan := func(dryRun bool) api.GitRefScanResponse {
+		t.Helper()
+		body, err := json.Marshal(api.GitRefScanRequest{
+			Repo: "git@github.com:acme/repo.git",
+			Commits: []api.GitRefScanCommit{
+				{SHA: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Message: "GR-SC01: fix parser\n\nAlso touches gr-sc02 and gr-zz99."},
+				{SHA: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Message: "unrelated change"},
+			},
+			DryRun: dryRun,
+		})
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/git-refs/scan", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var resp api.GitRefScanResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode scan response: %v", err)
+		}
+		return resp
+	}
+
+	dry := scan(true)
+	if len(dry.Created) != 2 || dry.CommitsScanned != 2 {
+		t.Fatalf("unexpected dry run response: %#v", dry)
+	}
+	refs, err := srv.gitRefService.List(context.Background(), "gr-sc01")
+	if err != nil || len(refs) != 0 {
+		t.Fatalf("dry run created refs: %#v, %v", refs, err)
+	}
+
+	first := scan(false)
+	if len(first.Created) != 2 || first.Existing != 0 {
+		t.Fatalf("unexpected scan response: %#v", first)
+	}
+	if len(first.UnknownTaskIDs) != 1 || first.UnknownTaskIDs[0] != "gr-zz99" {
+		t.Fatalf("expected unknown [gr-zz99], got %#v", first.UnknownTaskIDs)
+	}
+	ref := first.Created[0]
+	if ref.TaskID != "gr-sc01" || ref.Relation != "mentioned_by" || ref.Repo != "github.com/acme/repo" || ref.Note != "GR-SC01: fix parser" {
+		t.Fatalf("unexpected created ref: %#v", ref)
+	}
+
+	again := scan(false)
+	if len(again.Created) != 0 || again.Existing != 2 {
+		t.Fatalf("expected rescan to find 2 existing refs, got %#v", again)
+	}
+}
+
+func TestGitRefsListFilterAndTaskSummary(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for _, id := range []string{"gr-wb01", "gr-wb02"} {
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, SourceRepo: "github.com/acme/repo", CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+	mergeCommit := "dddddddddddddddddddddddddddddddddddddddd"
+	for _, ref := range []api.TaskGitRefCreateRequest{
+		{Relation: "working_branch", ObjectType: "branch", ObjectValue: "feature/parser"},
+		{Relation: "merged_by", ObjectType: "commit", ObjectValue: mergeCommit},
+	} {
+		if _, err := srv.gitRefService.Create(context.Background(), "gr-wb01", ref); err != nil {
+			t.Fatalf("seed ref: %v", err)
+		}
+	}
+
+	listIDs := func(query string) []string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks?"+query, nil)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("list %s: expected 200, got %d (%s)", query, w.Code, w.Body.String())
+		}
+		var tasks []api.TaskResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+			t.Fatalf("decode tasks: %v", err)
+		}
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+	if ids := listIDs("git_refs=working_branch"); len(ids) != 1 || ids[0] != "gr-wb01" {
+		t.Fatalf("git_refs=working_branch: expected [gr-wb01], got %v", ids)
+	}
+	if ids := listIDs("git_refs=none"); len(ids) != 1 || ids[0] != "gr-wb02" {
+		t.Fatalf("git_refs=none: expected [gr-wb02], got %v", ids)
+	}
+	if ids := listIDs("q=git:any"); len(ids) != 1 || ids[0] != "gr-wb01" {
+		t.Fatalf("q=git:any: expected [gr-wb01], got %v", ids)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks?git_refs=none,closed_by", nil)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for none combined with a relation, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/gr-wb01", nil)
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var task api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &task); err != nil {
+		t.Fatalf("decode task: %v", err)
+	}
+	summary := task.GitSummary
+	if summary == nil || summary.Total != 2 || summary.ByRelation["working_branch"] != 1 || len(summary.Commits) != 1 {
+		t.Fatalf("unexpected git summary: %#v", summary)
+	}
+	if len(summary.Branches) != 1 || summary.Branches[0].Branch != "feature/parser" || summary.Branches[0].Status != "merged" || summary.Branches[0].MergedBy != mergeCommit {
+		t.Fatalf("unexpected branches: %#v", summary.Branches)
+	}
+}
+
+func TestResolveTaskGitRefFromHostingProviders(t *testing.T) {
+	t.Setenv(githubTokenEnvKey, "gh-secret")
+	commit := "eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/repos/acme/repo/commits/main":
+			_, _ = w.Write([]byte(`{"sha":"` + commit + `"}`))
+		case "/repos/acme/repo/commits/" + commit + "/pulls":
+			_, _ = w.Write([]byte(`[{"html_url":"https://github.com/acme/repo/pull/7"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer github.Close()
+	gitlab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/acme%2Fother/repository/commits/v1.0":
+			_, _ = w.Write([]byte(`{"id":"` + commit + `"}`))
+		case "/api/v4/projects/acme%2Fother/repository/commits/" + commit + "/merge_requests":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gitlab.Close()
+
+	srv := newListTestServer(t)
+	// Both fakes listen on 127.0.0.1, so give GitLab its own host name.
+	if err := srv.ConfigureGitResolver(GitResolverOptions{GitHubAPIURL: github.URL, GitLabAPIURL: strings.Replace(gitlab.URL, "127.0.0.1", "localhost", 1) + "/api/v4"}); err != nil {
+		t.Fatalf("configure resolver: %v", err)
+	}
+	githubHost := strings.TrimPrefix(github.URL, "http://")
+	gitlabHost := strings.Replace(strings.TrimPrefix(gitlab.URL, "http://"), "127.0.0.1", "localhost", 1)
+
+	now := time.Now().UTC()
+	task := &models.Task{ID: "gr-rs01", Title: "resolve", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+		t.Fatalf("seed task: %v", err)
+	}
+	create := func(req api.TaskGitRefCreateRequest) models.TaskGitRef {
+		t.Helper()
+		ref, err := srv.gitRefService.Create(context.Background(), "gr-rs01", req)
+		if err != nil {
+			t.Fatalf("seed ref: %v", err)
+		}
+		return ref
+	}
+	branch := create(api.TaskGitRefCreateRequest{Repo: githubHost + "/acme/repo", Relation: "working_branch", ObjectType: "branch", ObjectValue: "main", Meta: map[string]any{"owner": "ana"}})
+	tag := create(api.TaskGitRefCreateRequest{Repo: gitlabHost + "/acme/other", Relation: "related", ObjectType: "tag", ObjectValue: "v1.0"})
+	missing := create(api.TaskGitRefCreateRequest{Repo: githubHost + "/acme/repo", Relation: "related", ObjectType: "branch", ObjectValue: "gone"})
+	path := create(api.TaskGitRefCreateRequest{Repo: githubHost + "/acme/repo", Relation: "design_doc", ObjectType: "path", ObjectValue: "docs/design.md"})
+
+	resolve := func(id string, wantStatus int) models.TaskGitRef {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/git-refs/"+id+"/resolve", nil)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != wantStatus {
+			t.Fatalf("resolve %s: expected %d, got %d (%s)", id, wantStatus, w.Code, w.Body.String())
+		}
+		var ref models.TaskGitRef
+		if wantStatus == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &ref); err != nil {
+				t.Fatalf("decode ref: %v", err)
+			}
+		}
+		return ref
+	}
+
+	resolved := resolve(branch.ID, http.StatusOK)
+	pulls, _ := resolved.Meta["pull_requests"].([]any)
+	if resolved.ResolvedCommit != commit || len(pulls) != 1 || pulls[0] != "https://github.com/acme/repo/pull/7" || resolved.Meta["owner"] != "ana" {
+		t.Fatalf("unexpected resolved branch: %#v", resolved)
+	}
+	if resolved = resolve(tag.ID, http.StatusOK); resolved.ResolvedCommit != commit {
+		t.Fatalf("unexpected resolved tag: %#v", resolved)
+	}
+	resolve(missing.ID, http.StatusNotFound)
+	resolve(path.ID, http.StatusBadRequest)
+}
+
+func TestCloseRequiresMergedPullRequest(t *testing.T) {
+	commit := "ffffffffffffffffffffffffffffffffffffffff"
+	merged := false
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/repos/acme/repo/commits/feature":
+			_, _ = w.Write([]byte(`{"sha":"` + commit + `"}`))
+		case "/repos/acme/repo/commits/" + commit + "/pulls":
+			mergedAt := "null"
+			if merged {
+				mergedAt = `"2026-01-02T03:04:05Z"`
+			}
+			_, _ = w.Write([]byte(`[{"html_url":"https://github.com/acme/repo/pull/9","merged_at":` + mergedAt + `}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer github.Close()
+
+	srv := newListTestServer(t)
+	srv.ConfigureWorkflowOptions(WorkflowOptions{RequireMergedPR: true})
+	if err := srv.ConfigureGitResolver(GitResolverOptions{GitHubAPIURL: github.URL}); err != nil {
+		t.Fatalf("configure resolver: %v", err)
+	}
+	repo := strings.TrimPrefix(github.URL, "http://") + "/acme/repo"
+
+	now := time.Now().UTC()
+	for _, id := range []string{"gr-pm01", "gr-pm02", "gr-pm03"} {
+		task := &models.Task{ID: id, Title: "merge gate", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+	for _, id := range []string{"gr-pm01", "gr-pm02"} {
+		if _, err := srv.gitRefService.Create(context.Background(), id, api.TaskGitRefCreateRequest{
+			Repo: repo, Relation: "working_branch", ObjectType: "branch", ObjectValue: "feature",
+			Meta: map[string]any{"pull_requests": []string{"https://github.com/acme/repo/pull/9"}},
+		}); err != nil {
+			t.Fatalf("seed ref: %v", err)
+		}
+	}
+
+	closeTasks := func(payload api.TaskCloseRequest, wantStatus int) string {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/close", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != wantStatus {
+			t.Fatalf("close %v: expected %d, got %d (%s)", payload.IDs, wantStatus, w.Code, w.Body.String())
+		}
+		return w.Body.String()
+	}
+
+	body := closeTasks(api.TaskCloseRequest{IDs: []string{"gr-pm01", "gr-pm03"}}, http.StatusConflict)
+	if !strings.Contains(body, "pr_not_merged") || !strings.Contains(body, "pull/9") {
+		t.Fatalf("unexpected pr_not_merged error: %s", body)
+	}
+	if task, err := srv.store.GetTask(context.Background(), "gr-pm03"); err != nil || task.Status != "open" {
+		t.Fatalf("rejected close must not close other tasks: %#v, %v", task, err)
+	}
+
+	closeTasks(api.TaskCloseRequest{IDs: []string{"gr-pm02"}, PRMerged: true}, http.StatusOK)
+
+	merged = true
+	closeTasks(api.TaskCloseRequest{IDs: []string{"gr-pm01", "gr-pm03"}}, http.StatusOK)
+	refs, err := srv.gitRefService.List(context.Background(), "gr-pm01")
+	if err != nil || len(refs) != 1 || refs[0].Meta["pr_merged"] != true {
+		t.Fatalf("expected re-resolved merged ref, got %#v, %v", refs, err)
+	}
+}
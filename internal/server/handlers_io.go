@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"grns/internal/api"
@@ -16,6 +17,10 @@ type importStreamOptions struct {
 	orphanHandling string
 	dryRun         bool
 	atomic         bool
+	lenient        bool
+	saveReport     bool
+	remapIDs       bool
+	sourceLabel    string
 }
 
 func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
@@ -28,29 +33,35 @@ func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
 	}
 	defer s.releaseLimiter(s.exportLimiter)
 
+	if r.URL.Query().Has("since") {
+		s.exportChanges(w, r)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/x-ndjson")
 	w.WriteHeader(http.StatusOK)
 	enc := json.NewEncoder(w)
 
-	offset := 0
+	afterID := ""
 	total := 0
 	pages := 0
 	s.log().Debug("export request")
 	for {
-		records, err := s.service.ExportPage(r.Context(), exportPageSize, offset)
+		records, err := s.service.ExportPage(r.Context(), exportPageSize, afterID)
 		if err != nil {
-			s.logExportError(r, "page", offset, "", err)
+			s.logExportError(r, "page", afterID, "", err)
 			return
 		}
 		if len(records) == 0 {
 			s.log().Debug("export complete", "records", total, "pages", pages)
+			s.metrics.exportRecords.Observe(float64(total))
 			return
 		}
 
 		pages++
 		for _, record := range records {
 			if err := enc.Encode(record); err != nil {
-				s.logExportError(r, "encode", offset, record.Task.ID, err)
+				s.logExportError(r, "encode", afterID, record.Task.ID, err)
 				return
 			}
 			total++
@@ -59,12 +70,69 @@ func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		offset += len(records)
+		afterID = records[len(records)-1].ID
+	}
+}
+
+// exportChanges streams tasks changed since the since= watermark, oldest
+// change first. Tombstoned tasks are included so deletions replicate.
+// X-Next-Cursor is fixed before streaming: changes made while the export runs
+// are left for the next call.
+func (s *Server) exportChanges(w http.ResponseWriter, r *http.Request) {
+	if s.service.changes == nil {
+		s.writeErrorReq(w, r, http.StatusNotImplemented, apiError{
+			status:  http.StatusNotImplemented,
+			code:    "not_implemented",
+			errCode: ErrCodeNotImplemented,
+			err:     fmt.Errorf("incremental export is not supported"),
+		})
+		return
+	}
+	afterSeq, updatedSince, err := parseExportSince(r.URL.Query().Get("since"))
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+	throughSeq, err := s.service.ChangeWatermark(r.Context())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("X-Next-Cursor", strconv.FormatInt(throughSeq, 10))
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+
+	total := 0
+	s.log().Debug("incremental export request", "after_seq", afterSeq, "through_seq", throughSeq)
+	for afterSeq < throughSeq {
+		records, lastSeq, err := s.service.ExportChangesPage(r.Context(), afterSeq, throughSeq, updatedSince, exportPageSize)
+		if err != nil {
+			s.logExportError(r, "changes", strconv.FormatInt(afterSeq, 10), "", err)
+			return
+		}
+		if len(records) == 0 {
+			break
+		}
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				s.logExportError(r, "encode", strconv.FormatInt(afterSeq, 10), record.Task.ID, err)
+				return
+			}
+			total++
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		afterSeq = lastSeq
 	}
+	s.log().Debug("incremental export complete", "records", total, "next_cursor", throughSeq)
+	s.metrics.exportRecords.Observe(float64(total))
 }
 
-func (s *Server) logExportError(r *http.Request, stage string, offset int, taskID string, err error) {
-	fields := []any{"stage", stage, "method", r.Method, "path", r.URL.Path, "offset", offset, "error", err}
+func (s *Server) logExportError(r *http.Request, stage string, afterID string, taskID string, err error) {
+	fields := []any{"stage", stage, "method", r.Method, "path", r.URL.Path, "after_id", afterID, "error", err}
 	if taskID != "" {
 		fields = append(fields, "task_id", taskID)
 	}
@@ -81,10 +149,32 @@ func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
 	}
 	defer s.releaseLimiter(s.importLimiter)
 
+	lenient, err := queryBool(r, "lenient")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
 	var req api.ImportRequest
 	if !s.decodeJSONReq(w, r, &req) {
 		return
 	}
+	req.Lenient = req.Lenient || lenient
+	saveReport, err := queryBool(r, "save_report")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+	req.SaveReport = req.SaveReport || saveReport
+	remapIDs, err := queryBool(r, "remap_ids")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+	req.RemapIDs = req.RemapIDs || remapIDs
+	if sourceLabel := strings.TrimSpace(r.URL.Query().Get("source_label")); sourceLabel != "" {
+		req.SourceLabel = sourceLabel
+	}
 
 	if err := validateImportModes(req.Dedupe, req.OrphanHandling); err != nil {
 		s.writeErrorReq(w, r, http.StatusBadRequest, err)
@@ -96,15 +186,22 @@ func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.log().Debug("import request", "task_count", len(req.Tasks), "dry_run", req.DryRun, "dedupe", req.Dedupe, "orphan_handling", req.OrphanHandling, "atomic", req.Atomic)
+	s.metrics.importRecords.Observe(float64(len(req.Tasks)), "batch")
+	s.log().Debug("import request", "task_count", len(req.Tasks), "dry_run", req.DryRun, "dedupe", req.Dedupe, "orphan_handling", req.OrphanHandling, "atomic", req.Atomic, "lenient", req.Lenient, "source_label", req.SourceLabel)
 
 	resp, err := s.service.Import(r.Context(), req)
 	if err != nil {
 		s.writeServiceError(w, r, err)
 		return
 	}
+	if req.SaveReport {
+		if resp.ReportID, err = s.service.SaveImportReport(r.Context(), req.DryRun, resp.Conflicts); err != nil {
+			s.writeServiceError(w, r, err)
+			return
+		}
+	}
 
-	s.log().Debug("import complete", "created", resp.Created, "updated", resp.Updated, "skipped", resp.Skipped, "errors", resp.Errors, "apply_mode", resp.ApplyMode, "applied_chunks", resp.AppliedChunks)
+	s.log().Debug("import complete", "created", resp.Created, "updated", resp.Updated, "skipped", resp.Skipped, "errors", resp.Errors, "apply_mode", resp.ApplyMode, "applied_chunks", resp.AppliedChunks, "rolled_back", resp.RolledBack)
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
@@ -124,86 +221,41 @@ func (s *Server) handleImportStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.log().Debug("import stream request", "dry_run", opts.dryRun, "dedupe", opts.dedupe, "orphan_handling", opts.orphanHandling, "atomic", opts.atomic)
+	s.log().Debug("import stream request", "dry_run", opts.dryRun, "dedupe", opts.dedupe, "orphan_handling", opts.orphanHandling, "atomic", opts.atomic, "lenient", opts.lenient, "save_report", opts.saveReport, "remap_ids", opts.remapIDs, "source_label", opts.sourceLabel)
 
 	r.Body = http.MaxBytesReader(w, r.Body, int64(importJSONMaxBody))
 	scanner := bufio.NewScanner(r.Body)
 	scanner.Buffer(make([]byte, 0, 64*1024), importStreamMaxLine)
 
-	response := api.ImportResponse{DryRun: opts.dryRun, TaskIDs: []string{}}
-	chunk := make([]api.TaskImportRecord, 0, importStreamChunkSize)
+	stream := s.service.newImportStream(opts, s.log())
 	lineNum := 0
-	chunkIndex := 0
-	hasRecords := false
-
-	flushChunk := func() error {
-		if len(chunk) == 0 {
-			return nil
-		}
-		chunkIndex++
-		chunkSize := len(chunk)
-		s.log().Debug("import stream chunk", "chunk", chunkIndex, "size", chunkSize)
-		resp, err := s.service.Import(r.Context(), api.ImportRequest{
-			Tasks:          chunk,
-			DryRun:         opts.dryRun,
-			Dedupe:         opts.dedupe,
-			OrphanHandling: opts.orphanHandling,
-			Atomic:         opts.atomic,
-		})
-		if err != nil {
-			return err
-		}
-		response.Created += resp.Created
-		response.Updated += resp.Updated
-		response.Skipped += resp.Skipped
-		response.Errors += resp.Errors
-		response.TaskIDs = append(response.TaskIDs, resp.TaskIDs...)
-		response.Messages = append(response.Messages, resp.Messages...)
-		response.AppliedChunks += resp.AppliedChunks
-		if response.ApplyMode == "" {
-			response.ApplyMode = resp.ApplyMode
-		}
-		s.log().Debug("import stream chunk complete", "chunk", chunkIndex, "size", chunkSize, "created", resp.Created, "updated", resp.Updated, "skipped", resp.Skipped, "errors", resp.Errors)
-		chunk = chunk[:0]
-		return nil
-	}
-
 	for scanner.Scan() {
 		lineNum++
 		line := bytes.TrimSpace(scanner.Bytes())
 		if len(line) == 0 {
 			continue
 		}
-		hasRecords = true
 
 		var rec api.TaskImportRecord
 		if err := json.Unmarshal(line, &rec); err != nil {
 			s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("line %d: %w", lineNum, err), ErrCodeInvalidJSON))
 			return
 		}
-		chunk = append(chunk, rec)
-
-		if len(chunk) >= importStreamChunkSize {
-			if err := flushChunk(); err != nil {
-				s.writeServiceError(w, r, err)
-				return
-			}
+		if err := stream.add(r.Context(), rec, lineNum); err != nil {
+			s.writeServiceError(w, r, err)
+			return
 		}
 	}
 	if err := scanner.Err(); err != nil {
 		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("reading input: %w", err), ErrCodeInvalidJSON))
 		return
 	}
-	if !hasRecords {
-		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("no records found in input"), ErrCodeMissingRequired))
-		return
-	}
-	if err := flushChunk(); err != nil {
+	response, err := stream.finish(r.Context())
+	if err != nil {
 		s.writeServiceError(w, r, err)
 		return
 	}
-
-	s.log().Debug("import stream complete", "created", response.Created, "updated", response.Updated, "skipped", response.Skipped, "errors", response.Errors, "chunks", chunkIndex, "apply_mode", response.ApplyMode)
+	s.metrics.importRecords.Observe(float64(stream.records), "stream")
 	s.writeJSON(w, http.StatusOK, response)
 }
 
@@ -211,6 +263,7 @@ func parseImportStreamOptions(r *http.Request) (importStreamOptions, error) {
 	opts := importStreamOptions{
 		dedupe:         strings.TrimSpace(r.URL.Query().Get("dedupe")),
 		orphanHandling: strings.TrimSpace(r.URL.Query().Get("orphan_handling")),
+		sourceLabel:    strings.TrimSpace(r.URL.Query().Get("source_label")),
 	}
 
 	dryRun, err := queryBool(r, "dry_run")
@@ -225,9 +278,46 @@ func parseImportStreamOptions(r *http.Request) (importStreamOptions, error) {
 	}
 	opts.atomic = atomic
 
+	lenient, err := queryBool(r, "lenient")
+	if err != nil {
+		return importStreamOptions{}, err
+	}
+	opts.lenient = lenient
+
+	saveReport, err := queryBool(r, "save_report")
+	if err != nil {
+		return importStreamOptions{}, err
+	}
+	opts.saveReport = saveReport
+
+	remapIDs, err := queryBool(r, "remap_ids")
+	if err != nil {
+		return importStreamOptions{}, err
+	}
+	opts.remapIDs = remapIDs
+
 	if err := validateImportModes(opts.dedupe, opts.orphanHandling); err != nil {
 		return importStreamOptions{}, err
 	}
 
 	return opts, nil
 }
+
+// handleGetImportReport returns a conflict report saved by an import with save_report.
+func (s *Server) handleGetImportReport(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(r.PathValue("report_id")), 10, 64)
+	if err != nil || id <= 0 {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("invalid import report id"), ErrCodeInvalidID))
+		return
+	}
+
+	report, err := s.service.ImportReport(r.Context(), id)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, report)
+}
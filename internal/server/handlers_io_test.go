@@ -0,0 +1,445 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+func TestHandleExportSinceStreamsChangesWithCursor(t *testing.T) {
+	srv := newListTestServer(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	for _, id := range []string{"gr-ex01", "gr-ex02", "gr-ex03"} {
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("seed task %s: %v", id, err)
+		}
+	}
+
+	export := func(since string) ([]string, string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/projects/gr/export?since="+since, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("export since=%s: %d %s", since, w.Code, w.Body.String())
+		}
+		var ids []string
+		scanner := bufio.NewScanner(bytes.NewReader(w.Body.Bytes()))
+		for scanner.Scan() {
+			var record api.TaskResponse
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				t.Fatalf("decode record: %v", err)
+			}
+			ids = append(ids, record.ID+":"+record.Status)
+		}
+		return ids, w.Header().Get("X-Next-Cursor")
+	}
+
+	all, cursor := export("0")
+	if len(all) != 3 || cursor == "" {
+		t.Fatalf("expected every task and a cursor, got %v (%q)", all, cursor)
+	}
+	if again, next := export(cursor); len(again) != 0 || next != cursor {
+		t.Fatalf("expected no changes after cursor, got %v (%q)", again, next)
+	}
+
+	// A label-only change and a soft delete both show up, oldest change first.
+	if err := srv.store.AddLabels(ctx, "gr-ex02", []string{"synced"}); err != nil {
+		t.Fatalf("add label: %v", err)
+	}
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/projects/gr/tasks/gr-ex03", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete: %d %s", w.Code, w.Body.String())
+	}
+	changed, next := export(cursor)
+	if len(changed) != 2 || changed[0] != "gr-ex02:open" || changed[1] != "gr-ex03:tombstone" || next == cursor {
+		t.Fatalf("unexpected changes: %v (%q)", changed, next)
+	}
+
+	if since, _ := export(now.Add(time.Hour).Format(time.RFC3339)); len(since) != 0 {
+		t.Fatalf("expected nothing updated after a future timestamp, got %v", since)
+	}
+
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/projects/gr/export?since=yesterday", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid since, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleExportSinceIncludesHardDeletesAsTombstones(t *testing.T) {
+	srv := newListTestServer(t)
+	ctx := context.Background()
+	old := time.Now().UTC().AddDate(0, 0, -90)
+	seed := []*models.Task{
+		{ID: "gr-hd01", Title: "kept", Status: "open", Type: "task", Priority: 2, CreatedAt: old, UpdatedAt: old},
+		{ID: "gr-hd02", Title: "closed long ago", Status: "closed", Type: "task", Priority: 2, CreatedAt: old, UpdatedAt: old, ClosedAt: &old},
+		{ID: "gr-hd03", Title: "tombstoned long ago", Status: "tombstone", Type: "task", Priority: 2, CreatedAt: old, UpdatedAt: old, DeletedAt: &old},
+	}
+	for _, task := range seed {
+		if err := srv.store.CreateTask(ctx, task, []string{"sync"}, nil); err != nil {
+			t.Fatalf("seed task %s: %v", task.ID, err)
+		}
+	}
+	feed := srv.store.(store.ChangeFeedStore)
+	cursor, err := feed.LatestChangeSeq(ctx)
+	if err != nil {
+		t.Fatalf("latest change: %v", err)
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -30)
+	if result, err := srv.store.CleanupClosedTasks(ctx, "gr", cutoff, false); err != nil || result.Count != 1 {
+		t.Fatalf("cleanup: %+v %v", result, err)
+	}
+	if result, err := srv.store.PurgeTombstonedTasks(ctx, "gr", cutoff, false); err != nil || result.Count != 1 {
+		t.Fatalf("purge: %+v %v", result, err)
+	}
+
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/projects/gr/export?since=%d", cursor), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("export: %d %s", w.Code, w.Body.String())
+	}
+	var records []api.TaskResponse
+	scanner := bufio.NewScanner(bytes.NewReader(w.Body.Bytes()))
+	for scanner.Scan() {
+		var record api.TaskResponse
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("decode record: %v", err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 || records[0].ID != "gr-hd02" || records[1].ID != "gr-hd03" {
+		t.Fatalf("expected tombstones for the removed tasks, got %+v", records)
+	}
+	for _, record := range records {
+		if record.Status != "tombstone" || record.DeletedAt == nil || record.DeletedAt.Before(cutoff) {
+			t.Fatalf("expected a tombstone dated at removal, got %+v", record)
+		}
+	}
+
+	// Recreating a removed ID turns its tombstone back into a live change.
+	now := time.Now().UTC()
+	if err := srv.store.CreateTask(ctx, &models.Task{ID: "gr-hd02", Title: "back", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil); err != nil {
+		t.Fatalf("recreate: %v", err)
+	}
+	changes, err := feed.ListTaskChanges(ctx, store.ChangeFilter{Project: "gr", AfterSeq: cursor})
+	if err != nil {
+		t.Fatalf("list changes: %v", err)
+	}
+	if len(changes) != 2 || changes[0].Task.ID != "gr-hd03" || !changes[0].Deleted || changes[1].Task.ID != "gr-hd02" || changes[1].Deleted || changes[1].Task.Title != "back" {
+		t.Fatalf("unexpected changes after recreate: %+v", changes)
+	}
+}
+
+func TestExportImportRoundTripsAttachmentsAndGitRefs(t *testing.T) {
+	src := newListTestServer(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	task := &models.Task{ID: "gr-rt01", Title: "round trip", Status: "open", Type: "task", Priority: 2, SourceRepo: "github.com/acme/repo", CreatedAt: now, UpdatedAt: now}
+	if err := src.store.CreateTask(ctx, task, []string{"keep"}, nil); err != nil {
+		t.Fatalf("seed task: %v", err)
+	}
+
+	serve := func(srv *Server, req *http.Request, want int) []byte {
+		t.Helper()
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != want {
+			t.Fatalf("%s %s: expected %d, got %d %s", req.Method, req.URL.Path, want, w.Code, w.Body.String())
+		}
+		return w.Body.Bytes()
+	}
+
+	content := []byte("archived attachment bytes")
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("kind", string(models.AttachmentKindArtifact))
+	part, err := writer.CreateFormFile("content", "notes.txt")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	_, _ = part.Write(content)
+	_ = writer.Close()
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/gr-rt01/attachments", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	var attachment models.Attachment
+	if err := json.Unmarshal(serve(src, req, http.StatusCreated), &attachment); err != nil {
+		t.Fatalf("decode attachment: %v", err)
+	}
+	refBody := `{"relation":"design_doc","object_type":"path","object_value":"docs/design.md"}`
+	var ref models.TaskGitRef
+	if err := json.Unmarshal(serve(src, httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/gr-rt01/git-refs", strings.NewReader(refBody)), http.StatusCreated), &ref); err != nil {
+		t.Fatalf("decode git ref: %v", err)
+	}
+
+	exported := serve(src, httptest.NewRequest(http.MethodGet, "/v1/projects/gr/export", nil), http.StatusOK)
+	var record api.TaskResponse
+	if err := json.Unmarshal(bytes.TrimSpace(exported), &record); err != nil {
+		t.Fatalf("decode export record: %v", err)
+	}
+	if len(record.Attachments) != 1 || record.Attachments[0].SHA256 == "" || len(record.GitRefs) != 1 {
+		t.Fatalf("expected attachment with digest and git ref on export record, got %#v / %#v", record.Attachments, record.GitRefs)
+	}
+	digest := record.Attachments[0].SHA256
+
+	// Without the blob, the task and git ref import but the attachment is reported.
+	missing := newListTestServer(t)
+	var resp api.ImportResponse
+	if err := json.Unmarshal(serve(missing, httptest.NewRequest(http.MethodPost, "/v1/projects/gr/import/stream", bytes.NewReader(exported)), http.StatusOK), &resp); err != nil {
+		t.Fatalf("decode import response: %v", err)
+	}
+	if resp.Created != 1 || resp.AttachmentsCreated != 0 || resp.GitRefsCreated != 1 || resp.Errors != 1 {
+		t.Fatalf("unexpected import without blob: %#v", resp)
+	}
+
+	dst := newListTestServer(t)
+	upload := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/blobs", bytes.NewReader(content))
+	upload.Header.Set("X-Content-SHA256", digest)
+	serve(dst, upload, http.StatusCreated)
+	// Re-importing over the same tasks keeps the existing attachment and git ref.
+	for _, wantCreated := range []int{1, 0} {
+		resp = api.ImportResponse{}
+		if err := json.Unmarshal(serve(dst, httptest.NewRequest(http.MethodPost, "/v1/projects/gr/import/stream?dedupe=overwrite", bytes.NewReader(exported)), http.StatusOK), &resp); err != nil {
+			t.Fatalf("decode import response: %v", err)
+		}
+		if resp.AttachmentsCreated != wantCreated || resp.GitRefsCreated != wantCreated || resp.Errors != 0 {
+			t.Fatalf("expected %d attachments and git refs created, got %#v", wantCreated, resp)
+		}
+	}
+
+	got := serve(dst, httptest.NewRequest(http.MethodGet, "/v1/projects/gr/attachments/"+attachment.ID+"/content", nil), http.StatusOK)
+	if !bytes.Equal(got, content) {
+		t.Fatalf("attachment content = %q, want %q", got, content)
+	}
+	var refs []models.TaskGitRef
+	if err := json.Unmarshal(serve(dst, httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/gr-rt01/git-refs", nil), http.StatusOK), &refs); err != nil {
+		t.Fatalf("decode git refs: %v", err)
+	}
+	if len(refs) != 1 || refs[0].ID != ref.ID || refs[0].Repo != "github.com/acme/repo" || refs[0].ObjectValue != "docs/design.md" {
+		t.Fatalf("unexpected git refs after import: %#v", refs)
+	}
+}
+
+func TestQuarantinedContentStaysQuarantinedOnEveryAttachPath(t *testing.T) {
+	srv := newListTestServer(t)
+	ctx := context.Background()
+	seedListTask(t, srv, "gr-qb01", "quarantined upload", 2)
+	seedListTask(t, srv, "gr-qb02", "reattach target", 2)
+	svc := srv.attachmentService
+	svc.ConfigureScanner(&fakeContentScanner{flag: "EICAR"}, "quarantine")
+
+	content := "EICAR payload"
+	quarantined, err := svc.CreateManagedAttachmentFromReader(ctx, "gr-qb01", CreateManagedAttachmentInput{Kind: string(models.AttachmentKindArtifact)}, strings.NewReader(content))
+	if err != nil || !quarantinedScan(quarantined.Meta) {
+		t.Fatalf("expected a quarantined upload, got %#v (%v)", quarantined.Meta, err)
+	}
+	sum := sha256.Sum256([]byte(content))
+	digest := hex.EncodeToString(sum[:])
+	blob, err := srv.attachmentService.attachmentStore.GetBlobBySHA256(ctx, digest)
+	if err != nil || blob == nil || blob.QuarantinedAt == nil {
+		t.Fatalf("expected the blob itself to be quarantined, got %#v (%v)", blob, err)
+	}
+
+	// With scanning off nothing re-checks the bytes, and every path below
+	// claims a clean verdict.
+	svc.ConfigureScanner(nil, "")
+	forged := map[string]any{"scan": map[string]any{"status": "clean"}}
+	assertWithheld := func(id string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/projects/gr/attachments/"+id+"/content", nil))
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("expected 403 for %s content, got %d %s", id, w.Code, w.Body.String())
+		}
+	}
+
+	now := time.Now().UTC()
+	record := api.TaskResponse{
+		Task: models.Task{ID: "gr-qb03", Title: "imported", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+		Attachments: []api.AttachmentRecord{{
+			Attachment: models.Attachment{ID: "at-zq01", TaskID: "gr-qb03", Kind: string(models.AttachmentKindArtifact), SourceType: string(models.AttachmentSourceManagedBlob), Meta: forged},
+			SHA256:     digest,
+		}},
+	}
+	body, _ := json.Marshal(record)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/projects/gr/import/stream", bytes.NewReader(body)))
+	var resp api.ImportResponse
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &resp) != nil || resp.AttachmentsCreated != 1 {
+		t.Fatalf("import: %d %s", w.Code, w.Body.String())
+	}
+	assertWithheld("at-zq01")
+	imported, err := svc.GetAttachment(ctx, "at-zq01")
+	if err != nil || !quarantinedScan(imported.Meta) {
+		t.Fatalf("expected the imported attachment to show the quarantine, got %#v (%v)", imported.Meta, err)
+	}
+
+	reuploaded, err := svc.CreateManagedAttachmentFromReader(ctx, "gr-qb02", CreateManagedAttachmentInput{Kind: string(models.AttachmentKindArtifact), Meta: forged}, strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("re-upload: %v", err)
+	}
+	assertWithheld(reuploaded.ID)
+
+	byBlob, err := svc.CreateManagedAttachment(ctx, "gr-qb02", CreateManagedAttachmentInput{Kind: string(models.AttachmentKindArtifact), BlobID: blob.ID, Meta: forged})
+	if err != nil {
+		t.Fatalf("attach by blob id: %v", err)
+	}
+	assertWithheld(byBlob.ID)
+
+	// A clean verdict cannot be written onto a quarantined attachment either.
+	updated, err := svc.UpdateAttachment(ctx, quarantined.ID, UpdateAttachmentInput{Meta: &forged})
+	if err != nil || !quarantinedScan(updated.Meta) {
+		t.Fatalf("expected meta updates to keep the verdict, got %#v (%v)", updated.Meta, err)
+	}
+}
+
+func TestImportStreamAtomicRollsBackWholeStream(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-dup1", "already here", 2)
+
+	// More records than one stream chunk, with the only failure at the very end.
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for i := 0; i < importStreamChunkSize+5; i++ {
+		_ = enc.Encode(api.TaskImportRecord{Task: models.Task{ID: fmt.Sprintf("gr-s%03d", i), Title: "streamed", Status: "open", Type: "task", Priority: 2}})
+	}
+	_ = enc.Encode(api.TaskImportRecord{Task: models.Task{ID: "gr-dup1", Title: "duplicate", Status: "open", Type: "task", Priority: 2}})
+
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/projects/gr/import/stream?atomic=true&dedupe=error", bytes.NewReader(body.Bytes())))
+	if w.Code != http.StatusOK {
+		t.Fatalf("import stream: %d %s", w.Code, w.Body.String())
+	}
+	var resp api.ImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !resp.RolledBack || resp.Errors != 1 || resp.AppliedChunks != 0 {
+		t.Fatalf("expected a rolled back import with one error, got %#v", resp)
+	}
+	for _, id := range []string{"gr-s000", fmt.Sprintf("gr-s%03d", importStreamChunkSize+4)} {
+		exists, err := srv.store.TaskExists(id)
+		if err != nil {
+			t.Fatalf("task exists: %v", err)
+		}
+		if exists {
+			t.Fatalf("expected %s to be rolled back", id)
+		}
+	}
+}
+
+func TestImportStreamReportsConflictsByLine(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-dup1", "already here", 2)
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	_ = enc.Encode(api.TaskImportRecord{Task: models.Task{ID: "gr-ok01", Title: "fine", Status: "open", Type: "task", Priority: 2}})
+	body.WriteString("\n")
+	_ = enc.Encode(api.TaskImportRecord{Task: models.Task{ID: "gr-dup1", Title: "duplicate", Status: "open", Type: "task", Priority: 2}})
+	_ = enc.Encode(api.TaskImportRecord{
+		Task: models.Task{ID: "gr-orp1", Title: "orphan", Status: "open", Type: "task", Priority: 2},
+		Deps: []models.Dependency{{ParentID: "gr-nope", Type: "blocks"}},
+	})
+
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/projects/gr/import/stream?dedupe=error&orphan_handling=strict&save_report=true", bytes.NewReader(body.Bytes())))
+	if w.Code != http.StatusOK {
+		t.Fatalf("import stream: %d %s", w.Code, w.Body.String())
+	}
+	var resp api.ImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Conflicts) != 2 || resp.ReportID == 0 {
+		t.Fatalf("expected two conflicts and a saved report, got %#v", resp)
+	}
+	if got := resp.Conflicts[0]; got.Line != 3 || got.ID != "gr-dup1" || got.Reason != models.ImportConflictDuplicateID || got.Resolution == "" {
+		t.Fatalf("unexpected duplicate conflict: %#v", got)
+	}
+	if got := resp.Conflicts[1]; got.Line != 4 || got.ID != "gr-orp1" || got.Reason != models.ImportConflictOrphanDependency {
+		t.Fatalf("unexpected orphan conflict: %#v", got)
+	}
+
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/projects/gr/import/reports/%d", resp.ReportID), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("get import report: %d %s", w.Code, w.Body.String())
+	}
+	var report models.ImportReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.ID != resp.ReportID || len(report.Conflicts) != 2 || report.Conflicts[1].Line != 4 {
+		t.Fatalf("unexpected report: %#v", report)
+	}
+
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/projects/gr/import/reports/999", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing report, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestImportRemapIDsRewritesReferences(t *testing.T) {
+	srv := newListTestServer(t)
+
+	body, _ := json.Marshal(api.ImportRequest{
+		RemapIDs: true,
+		Tasks: []api.TaskImportRecord{
+			{Task: models.Task{Project: "xy", ID: "xy-aaaa", Title: "parent", Status: "open", Type: "epic", Priority: 2}},
+			{
+				Task: models.Task{Project: "xy", ID: "xy-bbbb", Title: "child", Status: "open", Type: "task", Priority: 2, ParentID: "xy-aaaa"},
+				Deps: []models.Dependency{{ParentID: "xy-aaaa", Type: "blocks"}, {ParentID: "xy-zzzz", Type: "blocks"}},
+			},
+		},
+	})
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/projects/gr/import", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("import: %d %s", w.Code, w.Body.String())
+	}
+	var resp api.ImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	parentID, childID := resp.IDMap["xy-aaaa"], resp.IDMap["xy-bbbb"]
+	if resp.Created != 2 || !strings.HasPrefix(parentID, "gr-") || !strings.HasPrefix(childID, "gr-") {
+		t.Fatalf("expected two tasks created under gr, got %#v", resp)
+	}
+	if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "xy-zzzz") {
+		t.Fatalf("expected a warning for the dependency outside the import, got %v", resp.Warnings)
+	}
+
+	child, err := srv.store.GetTask(context.Background(), childID)
+	if err != nil || child == nil {
+		t.Fatalf("get remapped child: %v", err)
+	}
+	if child.ParentID != parentID {
+		t.Fatalf("expected parent_id %s, got %s", parentID, child.ParentID)
+	}
+	deps, err := srv.store.ListDependencies(context.Background(), childID)
+	if err != nil {
+		t.Fatalf("list deps: %v", err)
+	}
+	if len(deps) != 1 || deps[0].ParentID != parentID {
+		t.Fatalf("expected the dep to point at %s, got %+v", parentID, deps)
+	}
+}
@@ -38,11 +38,20 @@ func (s *Server) handleDepTree(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "dot" && format != "mermaid" {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("format must be json, dot, or mermaid"), ErrCodeInvalidQuery))
+		return
+	}
+
 	if !s.requireScopedTask(w, r, project, id) {
 		return
 	}
 
-	nodes, err := s.store.DependencyTree(r.Context(), project, id)
+	nodes, err := s.store.DependencyTree(r.Context(), id)
 	if err != nil {
 		s.writeStoreError(w, r, err)
 		return
@@ -51,11 +60,31 @@ func (s *Server) handleDepTree(w http.ResponseWriter, r *http.Request) {
 		nodes = []models.DepTreeNode{}
 	}
 
-	s.log().Debug("dependency tree listed", "root_id", id, "node_count", len(nodes))
-	s.writeJSON(w, http.StatusOK, api.DepTreeResponse{
-		RootID: id,
-		Nodes:  nodes,
-	})
+	s.log().Debug("dependency tree listed", "root_id", id, "node_count", len(nodes), "format", format)
+	if format == "json" {
+		s.writeJSON(w, http.StatusOK, api.DepTreeResponse{
+			RootID: id,
+			Nodes:  nodes,
+		})
+		return
+	}
+
+	graph, err := s.service.DependencyTreeGraph(r.Context(), id, nodes)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	write := writeDependencyGraphDOT
+	w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+	if format == "mermaid" {
+		write = writeDependencyGraphMermaid
+		w.Header().Set("Content-Type", "text/vnd.mermaid; charset=utf-8")
+	}
+	w.WriteHeader(http.StatusOK)
+	if err := write(w, graph); err != nil {
+		s.log().Error("dependency tree write failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
 }
 
 func (s *Server) handleDeps(w http.ResponseWriter, r *http.Request) {
@@ -84,6 +113,87 @@ func (s *Server) handleDeps(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, map[string]any{"child_id": childID, "parent_id": parentID, "type": depType})
 }
 
+func (s *Server) handleRemoveDep(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	var req api.DepCreateRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	childID := strings.TrimSpace(req.ChildID)
+	parentID := strings.TrimSpace(req.ParentID)
+	depType := strings.TrimSpace(req.Type)
+	if depType == "" {
+		depType = string(models.DependencyBlocks)
+	}
+
+	removed, err := s.service.RemoveDependency(r.Context(), childID, parentID, depType)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("dependency removed", "child_id", childID, "parent_id", parentID, "type", depType, "removed", removed)
+	s.writeJSON(w, http.StatusOK, map[string]any{"child_id": childID, "parent_id": parentID, "type": depType, "removed": removed})
+}
+
+func (s *Server) handleDepGraph(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = "dot"
+	}
+	if format != "dot" && format != "json" {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("format must be dot or json"), ErrCodeInvalidQuery))
+		return
+	}
+
+	filter, err := parseListFilter(r)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	graph, err := s.service.DependencyGraph(r.Context(), filter)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("dependency graph rendered", "format", format, "nodes", len(graph.Nodes), "edges", len(graph.Edges))
+	if format == "json" {
+		s.writeJSON(w, http.StatusOK, dependencyGraphAdjacency(graph))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := writeDependencyGraphDOT(w, graph); err != nil {
+		s.log().Error("dependency graph write failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+}
+
+func (s *Server) handleDeleteLabel(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	label, ids, err := s.service.DeleteLabel(r.Context(), r.PathValue("label"))
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Info("label deleted", "label", label, "count", len(ids))
+	s.writeJSON(w, http.StatusOK, api.LabelDeleteResponse{Label: label, TaskIDs: ids, Count: len(ids)})
+}
+
 func (s *Server) handleLabels(w http.ResponseWriter, r *http.Request) {
 	project, ok := s.pathProjectOrBadRequest(w, r)
 	if !ok {
@@ -100,6 +210,39 @@ func (s *Server) handleLabels(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, labels)
 }
 
+func (s *Server) handleRelatedLabels(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.pathProjectOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	label, err := normalizeLabel(r.PathValue("label"))
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := s.parseReportPage(r)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	related, err := s.store.LabelCooccurrence(r.Context(), project, label, page.Limit, page.Offset)
+	if err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+
+	resp := make([]api.RelatedLabelResponse, 0, len(related))
+	for _, item := range related {
+		resp = append(resp, api.RelatedLabelResponse{Label: item.Label, Count: item.Count})
+	}
+
+	s.log().Debug("related labels listed", "project", project, "label", label, "count", len(resp), "limit", page.Limit, "offset", page.Offset)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
 func (s *Server) handleListTaskLabels(w http.ResponseWriter, r *http.Request) {
 	project, ok := s.pathProjectOrBadRequest(w, r)
 	if !ok {
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -87,3 +88,115 @@ func TestHandleDepTree_RequiresTaskInScopedProject(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleRemoveDep(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-rd01", "child", 2)
+	seedListTask(t, srv, "gr-rd02", "parent", 2)
+	if err := srv.store.AddDependency(context.Background(), "gr-rd01", "gr-rd02", "blocks"); err != nil {
+		t.Fatalf("seed dependency: %v", err)
+	}
+
+	remove := func(payload api.DepCreateRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodDelete, "/v1/projects/gr/deps", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := remove(api.DepCreateRequest{ChildID: "xy-rd01", ParentID: "gr-rd02"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for child outside the project, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	for _, want := range []bool{true, false} {
+		w := remove(api.DepCreateRequest{ChildID: "gr-rd01", ParentID: "gr-rd02"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var resp map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if resp["removed"] != want {
+			t.Fatalf("expected removed=%v, got %#v", want, resp)
+		}
+	}
+
+	deps, err := srv.store.ListDependencies(context.Background(), "gr-rd01")
+	if err != nil {
+		t.Fatalf("list deps: %v", err)
+	}
+	if len(deps) != 0 {
+		t.Fatalf("expected dependency removed, got %#v", deps)
+	}
+}
+
+func TestHandleDeleteLabel(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-dl01", "labeled", 2)
+	if err := srv.store.AddLabels(context.Background(), "gr-dl01", []string{"wontfix", "keep"}); err != nil {
+		t.Fatalf("seed labels: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/projects/gr/labels/wontfix", nil)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var resp api.LabelDeleteResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Label != "wontfix" || resp.Count != 1 || resp.TaskIDs[0] != "gr-dl01" {
+		t.Fatalf("unexpected response: %#v", resp)
+	}
+
+	labels, err := srv.store.ListLabels(context.Background(), "gr-dl01")
+	if err != nil {
+		t.Fatalf("list labels: %v", err)
+	}
+	if len(labels) != 1 || labels[0] != "keep" {
+		t.Fatalf("expected [keep], got %v", labels)
+	}
+}
+
+func TestHandleRelatedLabelsReportPaging(t *testing.T) {
+	srv := newListTestServer(t)
+	srv.ConfigureReportOptions(ReportOptions{DefaultLimit: 2, MaxLimit: 3})
+
+	seedListTask(t, srv, "gr-rp01", "hub", 2)
+	if err := srv.store.AddLabels(context.Background(), "gr-rp01", []string{"hub", "a", "b", "c", "d", "e"}); err != nil {
+		t.Fatalf("seed labels: %v", err)
+	}
+
+	related := func(query string) []api.RelatedLabelResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/labels/hub/related"+query, nil)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var got []api.RelatedLabelResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return got
+	}
+
+	if got := related(""); len(got) != 2 || got[0].Label != "a" || got[1].Label != "b" {
+		t.Fatalf("expected default limit of 2 in stable order, got %v", got)
+	}
+	if got := related("?limit=100"); len(got) != 3 {
+		t.Fatalf("expected oversized limit clamped to 3, got %d", len(got))
+	}
+	if got := related("?limit=2&offset=2"); len(got) != 2 || got[0].Label != "c" {
+		t.Fatalf("expected second page starting at c, got %v", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -54,6 +55,13 @@ func TestHandleListTasksInvalidQueryParams(t *testing.T) {
 		{name: "priority min parse", query: "priority_min=bad", wantMessage: "invalid priority_min", wantCode: ErrCodeInvalidPriority},
 		{name: "priority range inverted", query: "priority_min=4&priority_max=1", wantMessage: "priority_min cannot be greater than priority_max", wantCode: ErrCodeInvalidPriority},
 		{name: "invalid created_after", query: "created_after=nope", wantMessage: "invalid created_after", wantCode: ErrCodeInvalidTimeFilter},
+		{name: "unknown include", query: "include=bogus", wantMessage: "invalid include", wantCode: ErrCodeInvalidQuery},
+		{name: "after_id with offset", query: "after_id=gr-b001&offset=1", wantMessage: "after_id cannot be combined with offset", wantCode: ErrCodeInvalidQuery},
+		{name: "unknown sort field", query: "sort=status", wantMessage: "invalid sort field", wantCode: ErrCodeInvalidQuery},
+		{name: "invalid order", query: "order=sideways", wantMessage: "order must be asc or desc", wantCode: ErrCodeInvalidQuery},
+		{name: "unknown dep_type", query: "dep_type=bogus", wantMessage: "invalid dependency type", wantCode: ErrCodeInvalidDependency},
+		{name: "unknown q field", query: "q=colour:red", wantMessage: "unknown q field", wantCode: ErrCodeInvalidQuery},
+		{name: "q priority out of range", query: "q=priority<=9", wantMessage: "priority_max must be between 0 and 4", wantCode: ErrCodeInvalidPriority},
 	}
 
 	for _, tt := range tests {
@@ -104,6 +112,245 @@ func TestHandleListTasksMalformedSearch(t *testing.T) {
 	}
 }
 
+type countingBlockerStore struct {
+	store.TaskServiceStore
+	blockerQueries int
+	depQueries     int
+}
+
+func (c *countingBlockerStore) ListOpenBlockerCounts(ctx context.Context, ids []string) (map[string]int, error) {
+	c.blockerQueries++
+	return c.TaskServiceStore.ListOpenBlockerCounts(ctx, ids)
+}
+
+func (c *countingBlockerStore) ListDependencies(ctx context.Context, id string) ([]models.Dependency, error) {
+	c.depQueries++
+	return c.TaskServiceStore.ListDependencies(ctx, id)
+}
+
+func TestHandleListTasksIncludeReadiness(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-rd01", "blocker", 1)
+	seedListTask(t, srv, "gr-rd02", "free", 2)
+	now := time.Now().UTC()
+	blocked := &models.Task{ID: "gr-rd03", Title: "blocked", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := srv.store.CreateTask(context.Background(), blocked, nil, []models.Dependency{{ParentID: "gr-rd01", Type: "blocks"}}); err != nil {
+		t.Fatalf("seed blocked task: %v", err)
+	}
+
+	counter := &countingBlockerStore{TaskServiceStore: srv.service.store}
+	srv.service.store = counter
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks?include=readiness", nil)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	var got []api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 tasks, got %d", len(got))
+	}
+	for _, task := range got {
+		if task.IsReady == nil || task.IsBlocked == nil || task.OpenBlockers == nil {
+			t.Fatalf("expected readiness fields on %s, got %+v", task.ID, task)
+		}
+		wantBlocked := task.ID == "gr-rd03"
+		if *task.IsBlocked != wantBlocked || *task.IsReady == wantBlocked {
+			t.Fatalf("unexpected readiness for %s: ready=%v blocked=%v", task.ID, *task.IsReady, *task.IsBlocked)
+		}
+		if wantBlocked && *task.OpenBlockers != 1 {
+			t.Fatalf("expected 1 open blocker for %s, got %d", task.ID, *task.OpenBlockers)
+		}
+	}
+	if counter.blockerQueries != 1 || counter.depQueries != 0 {
+		t.Fatalf("expected one batched blocker query and no per-task deps queries, got %d and %d", counter.blockerQueries, counter.depQueries)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks", nil)
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "is_ready") {
+		t.Fatalf("expected no readiness fields without include, got %s", w.Body.String())
+	}
+}
+
+func TestHandleListTasksCursorPages(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-cp02", "second", 2)
+	seedListTask(t, srv, "gr-cp01", "first", 2)
+	seedListTask(t, srv, "gr-cp03", "third", 2)
+
+	var got []string
+	query := "limit=2&after_id="
+	for page := 0; page < 3; page++ {
+		req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks?"+query, nil)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var tasks []api.TaskResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		for _, task := range tasks {
+			got = append(got, task.ID)
+		}
+		next := w.Header().Get("X-Next-Cursor")
+		if next == "" {
+			break
+		}
+		query = "limit=2&after_id=" + next
+	}
+	if strings.Join(got, ",") != "gr-cp01,gr-cp02,gr-cp03" {
+		t.Fatalf("unexpected cursor pages: %v", got)
+	}
+}
+
+func TestHandleListTasksIncludeTotal(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-tc01", "alpha one", 1)
+	seedListTask(t, srv, "gr-tc02", "alpha two", 2)
+	seedListTask(t, srv, "gr-tc03", "beta", 2)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "limit=1&include_total=true", want: "3"},
+		{query: "title_contains=alpha&limit=1&offset=1&include_total=true", want: "2"},
+		{query: "search=alpha&limit=1&include_total=true", want: "2"},
+		{query: "limit=1", want: ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d (%s)", tt.query, w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("X-Total-Count"); got != tt.want {
+			t.Fatalf("%s: expected X-Total-Count %q, got %q", tt.query, tt.want, got)
+		}
+	}
+}
+
+func TestHandleListTasksSearchSnippets(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-sn01", "rotate the signing keys", 1)
+	seedListTask(t, srv, "gr-sn02", "unrelated", 2)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks?search=signing", nil)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var got []api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(got) != 1 || got[0].Snippet != "rotate the [signing] keys" || got[0].Rank == nil {
+		t.Fatalf("unexpected search result: %+v", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks", nil)
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "snippet") {
+		t.Fatalf("expected no snippet without search, got %s", w.Body.String())
+	}
+}
+
+func TestHandleListTasksDepTypeFilter(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-dt01", "open parent", 1)
+	now := time.Now().UTC()
+	for id, depType := range map[string]string{"gr-dt02": "relates_to", "gr-dt03": "duplicates"} {
+		task := &models.Task{ID: id, Title: depType, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(context.Background(), task, nil, []models.Dependency{{ParentID: "gr-dt01", Type: depType}}); err != nil {
+			t.Fatalf("seed %s: %v", id, err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks?dep_type=relates_to&include=readiness", nil)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	var got []api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "gr-dt02" {
+		t.Fatalf("expected only gr-dt02, got %+v", got)
+	}
+	if got[0].IsReady == nil || !*got[0].IsReady {
+		t.Fatalf("expected non-blocking dependency to leave task ready, got %+v", got[0])
+	}
+}
+
+func TestHandleListTasksDefaultIncludes(t *testing.T) {
+	srv := newListTestServer(t)
+	if err := srv.ConfigureResponseOptions(ResponseOptions{ListIncludes: []string{"deps"}, GetIncludes: []string{"deps"}}); err != nil {
+		t.Fatalf("configure response options: %v", err)
+	}
+	seedListTask(t, srv, "gr-di01", "parent", 1)
+	now := time.Now().UTC()
+	child := &models.Task{ID: "gr-di02", Title: "child", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := srv.store.CreateTask(context.Background(), child, nil, []models.Dependency{{ParentID: "gr-di01", Type: "blocks"}}); err != nil {
+		t.Fatalf("seed child task: %v", err)
+	}
+
+	list := func(query string) map[string]api.TaskResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks"+query, nil)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200 for %q, got %d (%s)", query, w.Code, w.Body.String())
+		}
+		var got []api.TaskResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		byID := make(map[string]api.TaskResponse, len(got))
+		for _, task := range got {
+			byID[task.ID] = task
+		}
+		return byID
+	}
+
+	got := list("")
+	if deps := got["gr-di02"].Deps; len(deps) != 1 || deps[0].ParentID != "gr-di01" {
+		t.Fatalf("expected default deps include, got %+v", deps)
+	}
+
+	got = list("?include=dependents")
+	if got["gr-di02"].Deps != nil {
+		t.Fatalf("expected explicit include to override default deps, got %+v", got["gr-di02"].Deps)
+	}
+	if dependents := got["gr-di01"].Dependents; len(dependents) != 1 || dependents[0] != "gr-di02" {
+		t.Fatalf("expected dependents on parent, got %+v", dependents)
+	}
+
+	got = list("?include=")
+	if got["gr-di02"].Deps != nil || got["gr-di01"].Dependents != nil {
+		t.Fatalf("expected empty include to select no sections, got %+v", got)
+	}
+
+	if err := srv.ConfigureResponseOptions(ResponseOptions{ListIncludes: []string{"history"}}); err == nil {
+		t.Fatal("expected unknown default include to be rejected")
+	}
+}
+
 func newListTestServer(t *testing.T) *Server {
 	t.Helper()
 	t.Setenv(apiTokenEnvKey, "")
@@ -129,6 +376,50 @@ func newListTestServer(t *testing.T) *Server {
 	return New("127.0.0.1:0", st, "gr", nil, bs)
 }
 
+func TestHandleListTasksSearchExpression(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-q001", "Fix auth bug in login", 0)
+	seedListTask(t, srv, "gr-q002", "Fix auth bug in logout", 3)
+	seedListTask(t, srv, "gr-q003", "Write docs", 1)
+	if err := srv.store.ReplaceLabels(context.Background(), "gr-q001", []string{"backend"}); err != nil {
+		t.Fatalf("label: %v", err)
+	}
+
+	list := func(rawQuery string) []string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks?"+rawQuery, nil)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200 for %s, got %d (%s)", rawQuery, w.Code, w.Body.String())
+		}
+		var got []api.TaskResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		ids := make([]string, 0, len(got))
+		for _, task := range got {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	q := url.Values{"q": {`status:open priority<=1 "auth bug"`}}
+	if ids := list(q.Encode()); len(ids) != 1 || ids[0] != "gr-q001" {
+		t.Fatalf("expected gr-q001, got %v", ids)
+	}
+	q = url.Values{"q": {"label:backend"}}
+	if ids := list(q.Encode()); len(ids) != 1 || ids[0] != "gr-q001" {
+		t.Fatalf("expected label filter to match gr-q001, got %v", ids)
+	}
+
+	// Explicit params override values set by q.
+	q = url.Values{"q": {"auth priority<=1"}, "priority_max": {"4"}}
+	if ids := list(q.Encode()); len(ids) != 2 {
+		t.Fatalf("expected explicit priority_max to win, got %v", ids)
+	}
+}
+
 func seedListTask(t *testing.T, srv *Server, id, title string, priority int) {
 	t.Helper()
 	now := time.Now().UTC()
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestHandleMergeTask(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for _, task := range []*models.Task{
+		{ID: "gr-mh01", Title: "canonical", Status: "open", Type: "bug", Priority: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-mh02", Title: "duplicate", Status: "open", Type: "bug", Priority: 2, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := srv.store.CreateTask(context.Background(), task, []string{"crash"}, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	merge := func(id, into string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(api.TaskMergeRequest{Into: into})
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/"+id+"/merge", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := merge("gr-mh02", "gr-mh02"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for self merge, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := merge("gr-mh02", "gr-zzzz"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown canonical, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w := merge("gr-mh02", "gr-mh01")
+	if w.Code != http.StatusOK {
+		t.Fatalf("merge: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var resp api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.ID != "gr-mh01" || len(resp.Labels) != 1 {
+		t.Fatalf("expected canonical task in response, got %#v", resp)
+	}
+
+	duplicate, err := srv.store.GetTask(context.Background(), "gr-mh02")
+	if err != nil {
+		t.Fatalf("get duplicate: %v", err)
+	}
+	if duplicate.Status != "tombstone" || duplicate.MergedInto != "gr-mh01" {
+		t.Fatalf("expected duplicate tombstoned into gr-mh01, got %#v", duplicate)
+	}
+
+	if w := merge("gr-mh02", "gr-mh01"); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 merging a tombstone, got %d (%s)", w.Code, w.Body.String())
+	}
+}
@@ -1,17 +1,44 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"grns/internal/api"
+	"grns/internal/models"
 )
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if err := s.refreshStoreMetrics(r.Context()); err != nil {
+		s.writeStoreError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := s.metrics.registry.WriteText(w); err != nil {
+		s.log().Error("metrics write failed", "error", err)
+	}
+}
+
 func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
-	info, err := s.store.StoreInfo(r.Context())
+	s.writeInfo(w, r, "")
+}
+
+func (s *Server) handleProjectInfo(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.pathProjectOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+	s.writeInfo(w, r, project)
+}
+
+func (s *Server) writeInfo(w http.ResponseWriter, r *http.Request, project string) {
+	info, err := s.store.StoreInfo(r.Context(), project)
 	if err != nil {
 		s.writeStoreError(w, r, err)
 		return
@@ -20,11 +47,137 @@ func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
 	resp := api.InfoResponse{
 		DBPath:        s.dbPath,
 		ProjectPrefix: s.projectPrefix,
+		Project:       info.Project,
 		SchemaVersion: info.SchemaVersion,
 		TaskCounts:    info.TaskCounts,
 		TotalTasks:    info.TotalTasks,
+		Projects:      info.Projects,
+		LastBackupAt:  s.lastBackupAt.Load(),
+	}
+
+	s.log().Debug("info requested", "project", resp.Project, "schema_version", resp.SchemaVersion, "total_tasks", resp.TotalTasks)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	s.writeStats(w, r, "")
+}
+
+func (s *Server) handleProjectStats(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.pathProjectOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+	s.writeStats(w, r, project)
+}
+
+func (s *Server) writeStats(w http.ResponseWriter, r *http.Request, project string) {
+	from, err := parseTimeFilter(r, "from")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("invalid from: %w", err), ErrCodeInvalidTimeFilter))
+		return
+	}
+	to, err := parseTimeFilter(r, "to")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("invalid to: %w", err), ErrCodeInvalidTimeFilter))
+		return
+	}
+	interval, err := normalizeStatsInterval(r.URL.Query().Get("interval"))
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
 	}
 
-	s.log().Debug("info requested", "schema_version", resp.SchemaVersion, "total_tasks", resp.TotalTasks)
+	resp, err := s.taskStats(r.Context(), project, from, to, interval)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	s.log().Debug("stats requested", "project", project, "interval", interval, "closed", resp.TimeToClose.Count)
 	s.writeJSON(w, http.StatusOK, resp)
 }
+
+func (s *Server) handleStatsFlow(w http.ResponseWriter, r *http.Request) {
+	s.writeStatsFlow(w, r, "")
+}
+
+func (s *Server) handleProjectStatsFlow(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.pathProjectOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+	s.writeStatsFlow(w, r, project)
+}
+
+func (s *Server) writeStatsFlow(w http.ResponseWriter, r *http.Request, project string) {
+	windowDays, err := parseFlowWindow(r.URL.Query().Get("window"))
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := s.taskFlow(r.Context(), project, windowDays, time.Now())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	s.log().Debug("stats flow requested", "project", project, "window_days", windowDays, "days", len(resp.Days))
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCapabilities(w http.ResponseWriter, r *http.Request) {
+	resp := s.capabilities()
+	s.log().Debug("capabilities requested", "read_only", resp.ReadOnly)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) capabilities() api.CapabilitiesResponse {
+	resp := api.CapabilitiesResponse{
+		APIVersion:      "v1",
+		ProjectPrefix:   s.projectPrefix,
+		ReadOnly:        s.maintenanceMode.active(),
+		TaskStatuses:    models.TaskStatusStrings(),
+		TaskTypes:       models.TaskTypeStrings(),
+		DependencyTypes: models.DependencyTypeStrings(),
+		Features: api.CapabilityFeatures{
+			Attachments:          s.attachmentService != nil,
+			GitRefs:              s.gitRefService != nil,
+			LocalUsers:           s.authService != nil,
+			EventLog:             false,
+			WIPLimits:            s.service != nil && (len(s.service.wipLimits) > 0 || s.service.assigneeWIPLimit > 0),
+			ParentImpliesBlocks:  s.service != nil && s.service.parentImpliesBlocks,
+			AutoCloseParents:     s.service != nil && s.service.autoCloseParents,
+			RequireBlockedReason: s.service != nil && s.service.requireBlockedReason,
+			RequireMergedPR:      s.service != nil && s.service.requireMergedPR,
+			Undo:                 s.service != nil && s.service.operations != nil,
+		},
+		Limits: api.CapabilityLimits{
+			MaxListLimit:             0,
+			MaxJSONBodyBytes:         defaultJSONMaxBody,
+			MaxBatchBodyBytes:        batchJSONMaxBody,
+			MaxImportBodyBytes:       importJSONMaxBody,
+			MaxAttachmentUploadBytes: s.attachmentUploadMaxBody,
+			PriorityMin:              models.PriorityMin,
+			PriorityMax:              models.PriorityMax,
+			DependencyTreeMaxDepth:   models.DependencyTreeMaxDepth,
+			DefaultReportLimit:       s.reportDefaultLimit,
+			MaxReportLimit:           s.reportMaxLimit,
+		},
+	}
+	if s.service != nil {
+		resp.Limits.MaxDescriptionBytes = s.service.fieldLimits.MaxDescriptionBytes
+		resp.Limits.MaxNotesBytes = s.service.fieldLimits.MaxNotesBytes
+		resp.Limits.MaxCreateLabels = s.service.createLimits.MaxLabels
+		resp.Limits.MaxCreateDeps = s.service.createLimits.MaxDeps
+		resp.Limits.MaxCloseByFilter = s.service.maxCloseByFilter
+		resp.Limits.AssigneeWIPLimit = s.service.assigneeWIPLimit
+	}
+	if s.service != nil && len(s.service.wipLimits) > 0 {
+		resp.Limits.WIPLimits = make(map[string]int, len(s.service.wipLimits))
+		for status, max := range s.service.wipLimits {
+			resp.Limits.WIPLimits[status] = max
+		}
+		resp.Limits.WIPLimitsPerAssignee = s.service.wipLimitsPerAssignee
+	}
+	return resp
+}
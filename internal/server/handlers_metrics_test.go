@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"grns/internal/models"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for _, task := range []*models.Task{
+		{ID: "gr-mt01", Title: "open one", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-mt02", Title: "open two", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-mt03", Title: "closed", Status: "closed", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now, ClosedAt: &now},
+	} {
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	handler := srv.routes()
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	if w := get("/v1/projects/gr/tasks/gr-mt01"); w.Code != http.StatusOK {
+		t.Fatalf("get task: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	get("/v1/projects/gr/export")
+	get("/nope")
+
+	w := get("/metrics")
+	if w.Code != http.StatusOK {
+		t.Fatalf("metrics: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`grns_http_requests_total{method="GET",route="/v1/projects/{project}/tasks/{id}",status="200"} 1`,
+		`grns_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`grns_http_request_duration_seconds_count{method="GET",route="/v1/projects/{project}/tasks/{id}"} 1`,
+		`grns_tasks{status="open"} 2`,
+		`grns_tasks{status="closed"} 1`,
+		`grns_export_records_count 1`,
+		`grns_export_records_sum 3`,
+		`grns_blob_storage_bytes 0`,
+		`grns_db_query_duration_seconds_count{op="query"}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics output missing %q:\n%s", want, body)
+		}
+	}
+
+	srv.apiToken = "secret"
+	if w := get("/metrics"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", w.Code)
+	}
+}
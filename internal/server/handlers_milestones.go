@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func (s *Server) milestonesConfigured(w http.ResponseWriter, r *http.Request) bool {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return false
+	}
+	if s.milestoneService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("milestones are not configured")))
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleCreateMilestone(w http.ResponseWriter, r *http.Request) {
+	if !s.milestonesConfigured(w, r) {
+		return
+	}
+
+	var req api.MilestoneCreateRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	milestone, err := s.milestoneService.Create(r.Context(), req)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("milestone created", "id", milestone.ID)
+	s.writeJSON(w, http.StatusCreated, milestone)
+}
+
+func (s *Server) handleListMilestones(w http.ResponseWriter, r *http.Request) {
+	if !s.milestonesConfigured(w, r) {
+		return
+	}
+
+	milestones, err := s.milestoneService.List(r.Context(), r.URL.Query().Get("state"))
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	if milestones == nil {
+		milestones = []models.Milestone{}
+	}
+	s.writeJSON(w, http.StatusOK, milestones)
+}
+
+func (s *Server) handleGetMilestone(w http.ResponseWriter, r *http.Request) {
+	if !s.milestonesConfigured(w, r) {
+		return
+	}
+
+	milestone, err := s.milestoneService.Get(r.Context(), r.PathValue("milestone_id"))
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, milestone)
+}
+
+func (s *Server) handleUpdateMilestone(w http.ResponseWriter, r *http.Request) {
+	if !s.milestonesConfigured(w, r) {
+		return
+	}
+
+	var req api.MilestoneUpdateRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	milestone, err := s.milestoneService.Update(r.Context(), r.PathValue("milestone_id"), req)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("milestone updated", "id", milestone.ID)
+	s.writeJSON(w, http.StatusOK, milestone)
+}
+
+func (s *Server) handleDeleteMilestone(w http.ResponseWriter, r *http.Request) {
+	if !s.milestonesConfigured(w, r) {
+		return
+	}
+
+	id := r.PathValue("milestone_id")
+	if err := s.milestoneService.Delete(r.Context(), id); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("milestone deleted", "id", id)
+	s.writeJSON(w, http.StatusOK, map[string]any{"id": id, "deleted": true})
+}
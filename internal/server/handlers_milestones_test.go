@@ -0,0 +1,115 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestMilestoneHandlersAndTaskAssignment(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for _, task := range []*models.Task{
+		{ID: "gr-ms01", Title: "first", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-ms02", Title: "second", Status: "closed", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now, ClosedAt: &now},
+		{ID: "gr-ms03", Title: "unplanned", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	send := func(method, path string, payload any) *httptest.ResponseRecorder {
+		t.Helper()
+		var body bytes.Buffer
+		if payload != nil {
+			if err := json.NewEncoder(&body).Encode(payload); err != nil {
+				t.Fatalf("marshal payload: %v", err)
+			}
+		}
+		req := httptest.NewRequest(method, path, &body)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/v1/projects/gr/milestones", api.MilestoneCreateRequest{Title: "Sprint 1", DueDate: "2026-11-01"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create milestone: expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	var milestone models.Milestone
+	if err := json.Unmarshal(w.Body.Bytes(), &milestone); err != nil {
+		t.Fatalf("decode milestone: %v", err)
+	}
+	if !validateMilestoneID(milestone.ID) || milestone.State != "open" || milestone.DueDate != "2026-11-01" {
+		t.Fatalf("unexpected milestone: %#v", milestone)
+	}
+
+	if w := send(http.MethodPost, "/v1/projects/gr/milestones", api.MilestoneCreateRequest{Title: "Bad", DueDate: "next week"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for bad due_date, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	unknown := "ms-zzzz"
+	w = send(http.MethodPatch, "/v1/projects/gr/tasks/gr-ms01", api.TaskUpdateRequest{MilestoneID: &unknown})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown milestone, got %d (%s)", w.Code, w.Body.String())
+	}
+	var errResp api.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if errResp.ErrorCode != ErrCodeInvalidMilestone {
+		t.Fatalf("expected error_code %d, got %d", ErrCodeInvalidMilestone, errResp.ErrorCode)
+	}
+
+	for _, id := range []string{"gr-ms01", "gr-ms02"} {
+		if w := send(http.MethodPatch, "/v1/projects/gr/tasks/"+id, api.TaskUpdateRequest{MilestoneID: &milestone.ID}); w.Code != http.StatusOK {
+			t.Fatalf("assign %s: expected 200, got %d (%s)", id, w.Code, w.Body.String())
+		}
+	}
+
+	w = send(http.MethodGet, "/v1/projects/gr/tasks?milestone_id="+milestone.ID, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list by milestone: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var tasks []api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("decode tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks in milestone, got %d", len(tasks))
+	}
+
+	w = send(http.MethodGet, "/v1/projects/gr/milestones/"+milestone.ID, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get milestone: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var got api.MilestoneResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode milestone response: %v", err)
+	}
+	if got.Progress == nil || got.Progress.Total != 2 || got.Progress.Closed != 1 || got.Progress.Remaining != 1 {
+		t.Fatalf("unexpected progress: %#v", got.Progress)
+	}
+
+	if w := send(http.MethodDelete, "/v1/projects/gr/milestones/"+milestone.ID, nil); w.Code != http.StatusOK {
+		t.Fatalf("delete milestone: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/v1/projects/gr/milestones/"+milestone.ID, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d (%s)", w.Code, w.Body.String())
+	}
+	task, err := srv.store.GetTask(context.Background(), "gr-ms01")
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if task.MilestoneID != "" {
+		t.Fatalf("expected milestone cleared on delete, got %q", task.MilestoneID)
+	}
+}
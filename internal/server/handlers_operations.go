@@ -0,0 +1,59 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (s *Server) handleListOperations(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	ops, err := s.service.Operations(r.Context())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("operations listed", "count", len(ops))
+	s.writeJSON(w, http.StatusOK, ops)
+}
+
+// handleUndoLastOperation undoes the newest operation not yet undone.
+func (s *Server) handleUndoLastOperation(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	op, err := s.service.Undo(r.Context(), 0)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Info("operation undone", "operation_id", op.ID, "kind", op.Kind, "count", len(op.TaskIDs))
+	s.writeJSON(w, http.StatusOK, op)
+}
+
+func (s *Server) handleUndoOperation(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(r.PathValue("id")), 10, 64)
+	if err != nil || id <= 0 {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("invalid operation id"), ErrCodeInvalidID))
+		return
+	}
+
+	op, err := s.service.Undo(r.Context(), id)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Info("operation undone", "operation_id", op.ID, "kind", op.Kind, "count", len(op.TaskIDs))
+	s.writeJSON(w, http.StatusOK, op)
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"grns/internal/models"
+)
+
+func TestUndoRestoresJournaledOperations(t *testing.T) {
+	srv := newListTestServer(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	for _, id := range []string{"gr-un01", "gr-un02"} {
+		task := &models.Task{ID: id, Title: id, Status: "in_progress", Type: "task", Priority: 2, Assignee: "alice", CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(ctx, task, []string{"backend"}, nil); err != nil {
+			t.Fatalf("seed task %s: %v", id, err)
+		}
+	}
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do(http.MethodPost, "/v1/projects/gr/tasks/close", `{"ids":["gr-un01","gr-un02"]}`); w.Code != http.StatusOK {
+		t.Fatalf("close: %d %s", w.Code, w.Body.String())
+	}
+	w := do(http.MethodPost, "/v1/projects/gr/operations/undo", "")
+	var op models.Operation
+	if err := json.Unmarshal(w.Body.Bytes(), &op); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("undo: %d %s (%v)", w.Code, w.Body.String(), err)
+	}
+	if op.Kind != models.OperationClose || op.UndoneAt == nil || len(op.TaskIDs) != 2 {
+		t.Fatalf("unexpected undone operation: %+v", op)
+	}
+	for _, id := range op.TaskIDs {
+		task, err := srv.store.GetTask(ctx, id)
+		if err != nil || task.Status != "in_progress" || task.ClosedAt != nil || task.Assignee != "alice" {
+			t.Fatalf("task %s not restored: %+v (%v)", id, task, err)
+		}
+	}
+	events, err := srv.service.History(ctx, "gr-un01")
+	if err != nil || events[len(events)-1].Type != models.TaskEventUndone {
+		t.Fatalf("expected undone history event, got %+v (%v)", events, err)
+	}
+	if w := do(http.MethodPost, "/v1/projects/gr/operations/undo", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 with nothing left to undo, got %d %s", w.Code, w.Body.String())
+	}
+	path := "/v1/projects/gr/operations/" + strconv.FormatInt(op.ID, 10) + "/undo"
+	if w := do(http.MethodPost, path, ""); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 undoing twice, got %d %s", w.Code, w.Body.String())
+	}
+
+	// A later edit to a journaled task blocks the undo.
+	if w := do(http.MethodPost, "/v1/projects/gr/tasks/reopen", `{"ids":["gr-un01"]}`); w.Code != http.StatusOK {
+		t.Fatalf("reopen: %d %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodPatch, "/v1/projects/gr/tasks/gr-un01", `{"priority":0}`); w.Code != http.StatusOK {
+		t.Fatalf("update: %d %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodPost, "/v1/projects/gr/operations/undo", ""); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 after a later edit, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := do(http.MethodDelete, "/v1/projects/gr/labels/backend", ""); w.Code != http.StatusOK {
+		t.Fatalf("delete label: %d %s", w.Code, w.Body.String())
+	}
+	w = do(http.MethodGet, "/v1/projects/gr/operations", "")
+	var ops []models.Operation
+	if err := json.Unmarshal(w.Body.Bytes(), &ops); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("list operations: %d %s (%v)", w.Code, w.Body.String(), err)
+	}
+	if len(ops) != 3 || ops[0].Kind != models.OperationLabelDelete || ops[0].Label != "backend" {
+		t.Fatalf("unexpected operations: %+v", ops)
+	}
+	if w := do(http.MethodPost, "/v1/projects/gr/operations/"+strconv.FormatInt(ops[0].ID, 10)+"/undo", ""); w.Code != http.StatusOK {
+		t.Fatalf("undo label delete: %d %s", w.Code, w.Body.String())
+	}
+	for _, id := range []string{"gr-un01", "gr-un02"} {
+		labels, err := srv.store.ListLabels(ctx, id)
+		if err != nil || !slices.Contains(labels, "backend") {
+			t.Fatalf("label not restored on %s: %v (%v)", id, labels, err)
+		}
+	}
+}
+
+func TestUndoCloseReopensAutoClosedParents(t *testing.T) {
+	srv := newListTestServer(t)
+	srv.service.ConfigureAutoCloseParents(true)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	seed := []*models.Task{
+		{ID: "gr-up01", Title: "epic", Status: "open", Type: "epic", Priority: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-up02", Title: "child", Status: "in_progress", Type: "task", Priority: 2, ParentID: "gr-up01", CreatedAt: now, UpdatedAt: now},
+	}
+	for _, task := range seed {
+		if err := srv.store.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("seed task %s: %v", task.ID, err)
+		}
+	}
+
+	if err := srv.service.Close(ctx, []string{"gr-up02"}, false); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if epic, err := srv.store.GetTask(ctx, "gr-up01"); err != nil || epic.Status != "closed" {
+		t.Fatalf("expected epic auto-closed, got %+v (%v)", epic, err)
+	}
+
+	op, err := srv.service.Undo(ctx, 0)
+	if err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if !slices.Equal(op.TaskIDs, []string{"gr-up02", "gr-up01"}) {
+		t.Fatalf("expected child and auto-closed epic journaled, got %v", op.TaskIDs)
+	}
+	for id, want := range map[string]string{"gr-up01": "open", "gr-up02": "in_progress"} {
+		task, err := srv.store.GetTask(ctx, id)
+		if err != nil || task.Status != want || task.ClosedAt != nil {
+			t.Fatalf("task %s not restored to %s: %+v (%v)", id, want, task, err)
+		}
+	}
+}
+
+func TestUndoAdminLabelRename(t *testing.T) {
+	srv := newListTestServer(t)
+	ctx := context.Background()
+	seedListTask(t, srv, "gr-ur01", "renamed", 2)
+	seedListTask(t, srv, "gr-ur02", "already has target", 2)
+	if err := srv.store.AddLabels(ctx, "gr-ur01", []string{"bakend"}); err != nil {
+		t.Fatalf("add labels: %v", err)
+	}
+	if err := srv.store.AddLabels(ctx, "gr-ur02", []string{"bakend", "backend"}); err != nil {
+		t.Fatalf("add labels: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/labels/rename", bytes.NewBufferString(`{"from":"bakend","to":"backend"}`))
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("rename: %d %s", w.Code, w.Body.String())
+	}
+
+	op, err := srv.service.Undo(ctx, 0)
+	if err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if op.Kind != models.OperationLabelRename || op.Label != "bakend" || op.RenamedTo != "backend" {
+		t.Fatalf("unexpected undone operation: %+v", op)
+	}
+	for id, want := range map[string][]string{"gr-ur01": {"bakend"}, "gr-ur02": {"backend", "bakend"}} {
+		labels, err := srv.store.ListLabels(ctx, id)
+		if err != nil || !slices.Equal(labels, want) {
+			t.Fatalf("labels of %s = %v, want %v (%v)", id, labels, want, err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func (s *Server) handleReportSummary(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "markdown" {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("format must be json or markdown"), ErrCodeInvalidQuery))
+		return
+	}
+	groupBy, err := normalizeReportGroupBy(r.URL.Query().Get("group_by"))
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	r, err = s.withSavedView(r)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	filter, err := parseListFilter(r)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := s.service.ReportSummary(r.Context(), filter, groupBy)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	applyReportTaskURLs(&report, s.reportTaskURL)
+
+	s.log().Debug("summary report rendered", "format", format, "group_by", groupBy, "tasks", report.Total, "groups", len(report.Groups))
+	if format == "json" {
+		s.writeJSON(w, http.StatusOK, report)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := writeReportMarkdown(w, s.reportTemplate, report); err != nil {
+		s.log().Error("summary report write failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestReportSummaryGroupsAndRendersMarkdown(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for _, task := range []*models.Task{
+		{ID: "gr-rp01", Title: "Checkout epic", Status: "open", Type: "epic", Priority: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-rp02", Title: "Fix cart", Status: "in_progress", Type: "bug", Priority: 0, ParentID: "gr-rp01", Assignee: "alice", CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-rp03", Title: "Add coupons", Status: "open", Type: "feature", Priority: 2, ParentID: "gr-rp01", CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-rp04", Title: "Update docs", Status: "open", Type: "task", Priority: 3, Assignee: "bob", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/v1/projects/gr/reports/summary?group_by=epic&type=bug,feature,task")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var report api.ReportSummaryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.Total != 3 || len(report.Groups) != 2 {
+		t.Fatalf("unexpected report: %#v", report)
+	}
+	epic := report.Groups[0]
+	if epic.Key != "gr-rp01" || epic.Title != "gr-rp01 Checkout epic" || epic.Count != 2 || epic.Tasks[0].ID != "gr-rp02" {
+		t.Fatalf("unexpected epic group: %#v", epic)
+	}
+	if report.Groups[1].Title != "No epic" || report.Groups[1].Tasks[0].ID != "gr-rp04" {
+		t.Fatalf("unexpected ungrouped group: %#v", report.Groups[1])
+	}
+
+	w = get("/v1/projects/gr/reports/summary?group_by=assignee")
+	report = api.ReportSummaryResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode assignee report: %v", err)
+	}
+	titles := make([]string, 0, len(report.Groups))
+	for _, group := range report.Groups {
+		titles = append(titles, group.Title)
+	}
+	if strings.Join(titles, ",") != "alice,bob,Unassigned" {
+		t.Fatalf("unexpected assignee groups: %v", titles)
+	}
+
+	if err := srv.ConfigureReportTemplate(ReportTemplateOptions{TaskURL: "https://grns.example.com/?task={id}"}); err != nil {
+		t.Fatalf("configure report template: %v", err)
+	}
+	w = get("/v1/projects/gr/reports/summary?format=markdown")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/markdown") {
+		t.Fatalf("expected markdown 200, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"# gr backlog",
+		"## open (3)",
+		"## in_progress (1)",
+		"- [gr-rp02](https://grns.example.com/?task=gr-rp02) [P0] [in_progress] Fix cart (@alice)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Index(body, "## open") > strings.Index(body, "## in_progress") {
+		t.Fatalf("expected status groups in status order:\n%s", body)
+	}
+
+	if err := srv.ConfigureReportTemplate(ReportTemplateOptions{MarkdownTemplate: "{{range .Groups}}{{.Key}}={{.Count}};{{end}}"}); err != nil {
+		t.Fatalf("configure custom template: %v", err)
+	}
+	if body := get("/v1/projects/gr/reports/summary?format=markdown").Body.String(); body != "open=3;in_progress=1;" {
+		t.Fatalf("unexpected custom template output %q", body)
+	}
+	if err := srv.ConfigureReportTemplate(ReportTemplateOptions{MarkdownTemplate: "{{.Broken"}); err == nil {
+		t.Fatal("expected invalid template to fail")
+	}
+
+	for _, path := range []string{
+		"/v1/projects/gr/reports/summary?group_by=label",
+		"/v1/projects/gr/reports/summary?format=html",
+	} {
+		if w := get(path); w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d (%s)", path, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net/http"
+
+	"grns/internal/api"
+)
+
+func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	var req api.RPCRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	results, err := s.service.RunBatch(r.Context(), req.Ops)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("rpc batch applied", "ops", len(results))
+	s.writeJSON(w, http.StatusOK, api.RPCResponse{Results: results})
+}
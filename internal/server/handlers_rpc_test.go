@@ -0,0 +1,147 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func postRPC(t *testing.T, srv *Server, ops []api.RPCOp) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(api.RPCRequest{Ops: ops})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/rpc", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	return w
+}
+
+func TestHandleRPCAppliesOpsInOrder(t *testing.T) {
+	srv := newListTestServer(t)
+
+	w := postRPC(t, srv, []api.RPCOp{
+		{Op: api.RPCOpCreate, Task: &api.TaskCreateRequest{ID: "gr-rp01", Title: "parent"}},
+		{Op: api.RPCOpCreate, Task: &api.TaskCreateRequest{ID: "gr-rp02", Title: "child"}},
+		{Op: api.RPCOpAddDep, ID: "gr-rp02", ParentID: "gr-rp01"},
+		{Op: api.RPCOpAddLabel, ID: "gr-rp02", Labels: []string{"Backend"}},
+		{Op: api.RPCOpUpdate, ID: "gr-rp02", Update: &api.TaskUpdateRequest{Priority: intPtrRef(0)}},
+		{Op: api.RPCOpClose, ID: "gr-rp01"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	var resp api.RPCResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Results) != 6 {
+		t.Fatalf("expected 6 results, got %d", len(resp.Results))
+	}
+	if got := resp.Results[3].Labels; len(got) != 1 || got[0] != "backend" {
+		t.Fatalf("expected normalized labels [backend], got %v", got)
+	}
+	child := resp.Results[4].Task
+	if child == nil || child.Task.Priority != 0 || len(child.Deps) != 1 || child.Deps[0].ParentID != "gr-rp01" {
+		t.Fatalf("unexpected child result: %+v", child)
+	}
+	parent := resp.Results[5].Task
+	if parent == nil || parent.Task.Status != "closed" || parent.Task.ClosedAt == nil {
+		t.Fatalf("expected parent closed, got %+v", parent)
+	}
+}
+
+func TestHandleRPCRollsBackOnFailedOp(t *testing.T) {
+	srv := newListTestServer(t)
+
+	w := postRPC(t, srv, []api.RPCOp{
+		{Op: api.RPCOpCreate, Task: &api.TaskCreateRequest{ID: "gr-rp01", Title: "first"}},
+		{Op: api.RPCOpUpdate, ID: "gr-rp01", Update: &api.TaskUpdateRequest{Title: strPtr("renamed")}},
+		{Op: api.RPCOpClose, ID: "gr-zz99"},
+	})
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d (%s)", w.Code, w.Body.String())
+	}
+	var errResp api.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if errResp.ErrorCode != ErrCodeTaskNotFound || !strings.HasPrefix(errResp.Error, "ops[2] close:") {
+		t.Fatalf("unexpected error response: %+v", errResp)
+	}
+
+	getReq := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/gr-rp01", nil)
+	getW := httptest.NewRecorder()
+	srv.routes().ServeHTTP(getW, getReq)
+	if getW.Code != http.StatusNotFound {
+		t.Fatalf("expected created task rolled back, got %d (%s)", getW.Code, getW.Body.String())
+	}
+}
+
+func TestHandleRPCRejectsUnknownOp(t *testing.T) {
+	srv := newListTestServer(t)
+
+	w := postRPC(t, srv, []api.RPCOp{{Op: "delete", ID: "gr-rp01"}})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w = postRPC(t, srv, nil)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty ops, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleRPCStatusChangesPropagateAndAreJournaled(t *testing.T) {
+	srv := newListTestServer(t)
+	srv.service.ConfigureAutoCloseParents(true)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	for _, task := range []*models.Task{
+		{ID: "gr-rj01", Title: "epic", Status: "open", Type: "epic", Priority: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-rj02", Title: "closed by update", Status: "in_progress", Type: "task", Priority: 2, ParentID: "gr-rj01", CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-rj03", Title: "closed by close", Status: "open", Type: "task", Priority: 2, ParentID: "gr-rj01", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := srv.store.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("seed task %s: %v", task.ID, err)
+		}
+	}
+
+	closed := "closed"
+	w := postRPC(t, srv, []api.RPCOp{
+		{Op: api.RPCOpUpdate, ID: "gr-rj02", Update: &api.TaskUpdateRequest{Status: &closed}},
+		{Op: api.RPCOpClose, ID: "gr-rj03"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if epic, err := srv.store.GetTask(ctx, "gr-rj01"); err != nil || epic.Status != "closed" {
+		t.Fatalf("expected epic auto-closed, got %+v (%v)", epic, err)
+	}
+
+	op, err := srv.service.Undo(ctx, 0)
+	if err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if op.Kind != models.OperationBatch || !slices.Equal(op.TaskIDs, []string{"gr-rj02", "gr-rj03", "gr-rj01"}) {
+		t.Fatalf("unexpected journaled batch: %+v", op)
+	}
+	for id, want := range map[string]string{"gr-rj01": "open", "gr-rj02": "in_progress", "gr-rj03": "open"} {
+		task, err := srv.store.GetTask(ctx, id)
+		if err != nil || task.Status != want {
+			t.Fatalf("task %s not restored to %s: %+v (%v)", id, want, task, err)
+		}
+	}
+}
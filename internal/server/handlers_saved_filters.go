@@ -0,0 +1,136 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func (s *Server) savedFiltersConfigured(w http.ResponseWriter, r *http.Request) bool {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return false
+	}
+	if s.savedFilterService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("saved filters are not configured")))
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleCreateSavedFilter(w http.ResponseWriter, r *http.Request) {
+	if !s.savedFiltersConfigured(w, r) {
+		return
+	}
+
+	var req api.SavedFilterRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	saved, err := s.savedFilterService.Create(r.Context(), req.Name, req.Filter)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("saved filter created", "name", saved.Name)
+	s.writeJSON(w, http.StatusCreated, saved)
+}
+
+func (s *Server) handleListSavedFilters(w http.ResponseWriter, r *http.Request) {
+	if !s.savedFiltersConfigured(w, r) {
+		return
+	}
+
+	filters, err := s.savedFilterService.List(r.Context())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	if filters == nil {
+		filters = []models.SavedFilter{}
+	}
+	s.writeJSON(w, http.StatusOK, filters)
+}
+
+func (s *Server) handleGetSavedFilter(w http.ResponseWriter, r *http.Request) {
+	if !s.savedFiltersConfigured(w, r) {
+		return
+	}
+
+	saved, err := s.savedFilterService.Get(r.Context(), r.PathValue("name"))
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, saved)
+}
+
+func (s *Server) handleReplaceSavedFilter(w http.ResponseWriter, r *http.Request) {
+	if !s.savedFiltersConfigured(w, r) {
+		return
+	}
+
+	var req api.SavedFilterRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	saved, err := s.savedFilterService.Replace(r.Context(), r.PathValue("name"), req.Filter)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("saved filter replaced", "name", saved.Name)
+	s.writeJSON(w, http.StatusOK, saved)
+}
+
+func (s *Server) handleDeleteSavedFilter(w http.ResponseWriter, r *http.Request) {
+	if !s.savedFiltersConfigured(w, r) {
+		return
+	}
+
+	name := r.PathValue("name")
+	if err := s.savedFilterService.Delete(r.Context(), name); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("saved filter deleted", "name", name)
+	s.writeJSON(w, http.StatusOK, map[string]any{"name": name, "deleted": true})
+}
+
+// withSavedView expands the "view" query parameter into the saved filter's
+// parameters. Parameters given explicitly on the request take precedence.
+func (s *Server) withSavedView(r *http.Request) (*http.Request, error) {
+	query := r.URL.Query()
+	name := strings.TrimSpace(query.Get("view"))
+	if name == "" {
+		return r, nil
+	}
+	if s.savedFilterService == nil {
+		return r, internalError(fmt.Errorf("saved filters are not configured"))
+	}
+
+	saved, err := s.savedFilterService.Get(r.Context(), name)
+	if err != nil {
+		return r, err
+	}
+
+	query.Del("view")
+	for key, value := range saved.Filter {
+		if !query.Has(key) {
+			query.Set(key, value)
+		}
+	}
+
+	expanded := r.Clone(r.Context())
+	expandedURL := *r.URL
+	expandedURL.RawQuery = query.Encode()
+	expanded.URL = &expandedURL
+	return expanded, nil
+}
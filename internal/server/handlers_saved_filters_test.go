@@ -0,0 +1,104 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestSavedFilterHandlersAndListView(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for _, task := range []*models.Task{
+		{ID: "gr-sf01", Title: "open bug", Status: "open", Type: "bug", Priority: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-sf02", Title: "closed bug", Status: "closed", Type: "bug", Priority: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-sf03", Title: "open task", Status: "open", Type: "task", Priority: 1, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	send := func(method, path string, payload any) *httptest.ResponseRecorder {
+		t.Helper()
+		var body bytes.Buffer
+		if payload != nil {
+			if err := json.NewEncoder(&body).Encode(payload); err != nil {
+				t.Fatalf("marshal payload: %v", err)
+			}
+		}
+		req := httptest.NewRequest(method, path, &body)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+	listIDs := func(query string) []string {
+		t.Helper()
+		w := send(http.MethodGet, "/v1/projects/gr/tasks?"+query, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("list %q: expected 200, got %d (%s)", query, w.Code, w.Body.String())
+		}
+		var tasks []api.TaskResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+			t.Fatalf("decode tasks: %v", err)
+		}
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	create := api.SavedFilterRequest{Name: "open-bugs", Filter: map[string]string{"status": "open", "type": "bug"}}
+	if w := send(http.MethodPost, "/v1/projects/gr/filters", create); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, "/v1/projects/gr/filters", create); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for duplicate, got %d (%s)", w.Code, w.Body.String())
+	}
+	invalid := api.SavedFilterRequest{Name: "paged", Filter: map[string]string{"limit": "5"}}
+	if w := send(http.MethodPost, "/v1/projects/gr/filters", invalid); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for limit in filter, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	if ids := listIDs("view=open-bugs"); len(ids) != 1 || ids[0] != "gr-sf01" {
+		t.Fatalf("expected view to match gr-sf01, got %v", ids)
+	}
+	if ids := listIDs("view=open-bugs&status=closed"); len(ids) != 1 || ids[0] != "gr-sf02" {
+		t.Fatalf("expected explicit status to override view, got %v", ids)
+	}
+
+	replace := api.SavedFilterRequest{Filter: map[string]string{"status": "open", "type": "task"}}
+	if w := send(http.MethodPut, "/v1/projects/gr/filters/open-bugs", replace); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 on replace, got %d (%s)", w.Code, w.Body.String())
+	}
+	if ids := listIDs("view=open-bugs"); len(ids) != 1 || ids[0] != "gr-sf03" {
+		t.Fatalf("expected replaced view to match gr-sf03, got %v", ids)
+	}
+
+	w := send(http.MethodGet, "/v1/projects/gr/filters", nil)
+	var filters []models.SavedFilter
+	if err := json.Unmarshal(w.Body.Bytes(), &filters); err != nil {
+		t.Fatalf("decode filters: %v", err)
+	}
+	if len(filters) != 1 || filters[0].Name != "open-bugs" {
+		t.Fatalf("unexpected saved filters: %+v", filters)
+	}
+
+	if w := send(http.MethodDelete, "/v1/projects/gr/filters/open-bugs", nil); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 on delete, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/v1/projects/gr/filters/open-bugs", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/v1/projects/gr/tasks?view=open-bugs", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown view, got %d (%s)", w.Code, w.Body.String())
+	}
+}
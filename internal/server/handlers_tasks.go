@@ -3,10 +3,12 @@ package server
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"grns/internal/api"
+	"grns/internal/models"
 )
 
 func (s *Server) handleClose(w http.ResponseWriter, r *http.Request) {
@@ -18,6 +20,31 @@ func (s *Server) handleClose(w http.ResponseWriter, r *http.Request) {
 	if !s.decodeJSONReq(w, r, &req) {
 		return
 	}
+	if len(req.Filter) > 0 {
+		if len(req.IDs) > 0 {
+			s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("ids and filter are mutually exclusive"), ErrCodeInvalidArgument))
+			return
+		}
+		if strings.TrimSpace(req.Commit) != "" || strings.TrimSpace(req.Repo) != "" {
+			s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("commit cannot be combined with filter"), ErrCodeInvalidArgument))
+			return
+		}
+		filter, ok := s.closeByFilterArgs(w, r, req.Filter, req.DryRun)
+		if !ok {
+			return
+		}
+		ids, err := s.service.CloseByFilter(r.Context(), filter, req.DryRun, req.PRMerged)
+		if err != nil {
+			s.writeServiceError(w, r, err)
+			return
+		}
+		s.writeCloseByFilterResult(w, ids, req.DryRun)
+		return
+	}
+	if req.DryRun {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("dry_run requires filter"), ErrCodeInvalidArgument))
+		return
+	}
 	if err := requireIDs(req.IDs); err != nil {
 		s.writeErrorReq(w, r, http.StatusBadRequest, err)
 		return
@@ -40,13 +67,13 @@ func (s *Server) handleClose(w http.ResponseWriter, r *http.Request) {
 
 	annotated := 0
 	if commit == "" {
-		if err := s.service.Close(r.Context(), req.IDs); err != nil {
+		if err := s.service.Close(r.Context(), req.IDs, req.PRMerged); err != nil {
 			s.writeServiceError(w, r, err)
 			return
 		}
 	} else {
 		var err error
-		annotated, err = s.service.CloseWithCommit(r.Context(), req.IDs, commit, repo)
+		annotated, err = s.service.CloseWithCommit(r.Context(), req.IDs, commit, repo, req.PRMerged)
 		if err != nil {
 			s.writeServiceError(w, r, err)
 			return
@@ -81,6 +108,204 @@ func (s *Server) handleReopen(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, map[string]any{"ids": ids})
 }
 
+func (s *Server) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.service.Delete(r.Context(), []string{id}); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("task deleted", "id", id)
+	s.writeJSON(w, http.StatusOK, map[string]any{"ids": []string{id}})
+}
+
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	ids, ok := s.decodeIDsReq(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.service.Restore(r.Context(), ids); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("tasks restored", "count", len(ids))
+	s.writeJSON(w, http.StatusOK, map[string]any{"ids": ids})
+}
+
+func (s *Server) handleMergeTask(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req api.TaskMergeRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	resp, err := s.service.Merge(r.Context(), id, req)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("task merged", "id", id, "into", resp.ID)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleClaimTask(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	var req api.TaskClaimRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	resp, err := s.service.Claim(r.Context(), req)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("task claimed", "id", resp.Task.ID, "holder", resp.Lease.Holder, "expires_at", resp.Lease.ExpiresAt)
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCloseByFilter(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	var req api.TaskCloseByFilterRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+	filter, ok := s.closeByFilterArgs(w, r, req.Filter, req.DryRun)
+	if !ok {
+		return
+	}
+	ids, err := s.service.CloseByFilter(r.Context(), filter, req.DryRun, req.PRMerged)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	s.writeCloseByFilterResult(w, ids, req.DryRun)
+}
+
+// closeByFilterArgs validates a close-by-filter request: a non-empty filter
+// and, unless dryRun, the X-Confirm header.
+func (s *Server) closeByFilterArgs(w http.ResponseWriter, r *http.Request, rawFilter map[string]string, dryRun bool) (taskListFilter, bool) {
+	if len(rawFilter) == 0 {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("filter is required"), ErrCodeMissingRequired))
+		return taskListFilter{}, false
+	}
+	if !dryRun && r.Header.Get("X-Confirm") != "true" {
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("non-dry-run requires X-Confirm: true header"), ErrCodeMissingRequired))
+		return taskListFilter{}, false
+	}
+
+	filter, err := parseFilterBody(rawFilter)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return taskListFilter{}, false
+	}
+	return filter, true
+}
+
+func (s *Server) writeCloseByFilterResult(w http.ResponseWriter, ids []string, dryRun bool) {
+	if !dryRun {
+		s.log().Info("tasks closed by filter", "event", "closed", "ids", ids, "count", len(ids))
+	}
+	s.writeJSON(w, http.StatusOK, api.TaskCloseByFilterResponse{IDs: ids, Count: len(ids), DryRun: dryRun})
+}
+
+func (s *Server) handleBulkUpdate(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	var req api.TaskBulkUpdateRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	var filter *taskListFilter
+	switch {
+	case len(req.IDs) > 0 && len(req.Filter) > 0:
+		s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("ids and filter are mutually exclusive"), ErrCodeInvalidArgument))
+		return
+	case len(req.Filter) > 0:
+		parsed, err := parseFilterBody(req.Filter)
+		if err != nil {
+			s.writeErrorReq(w, r, http.StatusBadRequest, err)
+			return
+		}
+		filter = &parsed
+	default:
+		if err := requireIDs(req.IDs); err != nil {
+			s.writeErrorReq(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	ids, err := s.service.BulkUpdate(r.Context(), req.IDs, filter, req.Update)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Info("tasks bulk updated", "ids", ids, "count", len(ids))
+	s.writeJSON(w, http.StatusOK, api.TaskBulkUpdateResponse{IDs: ids, Count: len(ids)})
+}
+
+func (s *Server) handleTouch(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	var req api.TaskTouchRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+	if err := requireIDs(req.IDs); err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.service.Touch(r.Context(), req.IDs); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	actor := strings.TrimSpace(req.Actor)
+	s.log().Info("tasks touched", "event", "touched", "ids", req.IDs, "actor", actor)
+	resp := map[string]any{"ids": req.IDs}
+	if actor != "" {
+		resp["actor"] = actor
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
 func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
 		return
@@ -92,13 +317,14 @@ func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	responses, err := s.service.Ready(r.Context(), limit)
+	order := r.URL.Query().Get("order")
+	responses, err := s.service.Ready(r.Context(), order, limit)
 	if err != nil {
 		s.writeServiceError(w, r, err)
 		return
 	}
 
-	s.log().Debug("ready tasks listed", "count", len(responses), "limit", limit)
+	s.log().Debug("ready tasks listed", "count", len(responses), "order", order, "limit", limit)
 	s.writeJSON(w, http.StatusOK, responses)
 }
 
@@ -148,10 +374,17 @@ func (s *Server) handleCreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	lenient, err := queryBool(r, "lenient")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
 	var req api.TaskCreateRequest
 	if !s.decodeJSONReq(w, r, &req) {
 		return
 	}
+	req.Lenient = req.Lenient || lenient
 
 	resp, err := s.service.Create(r.Context(), req)
 	if err != nil {
@@ -159,7 +392,7 @@ func (s *Server) handleCreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.log().Debug("task created", "task_id", resp.Task.ID, "project", resp.Task.Project, "label_count", len(resp.Labels), "dep_count", len(resp.Deps))
+	s.log().Debug("task created", "task_id", resp.Task.ID, "project", resp.Task.Project, "label_count", len(resp.Labels), "dep_count", len(resp.Deps), "warning_count", len(resp.Warnings))
 	s.writeJSON(w, http.StatusCreated, resp)
 }
 
@@ -168,18 +401,33 @@ func (s *Server) handleBatchCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var reqs []api.TaskCreateRequest
-	if !s.decodeJSONReq(w, r, &reqs) {
+	lenient, err := queryBool(r, "lenient")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	responses, err := s.service.BatchCreate(r.Context(), reqs)
+	batch, err := s.service.newTaskBatch(r.Context())
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	err = decodeJSONArrayStream(w, r, batchJSONMaxBody, func(index int, req api.TaskCreateRequest) error {
+		req.Lenient = req.Lenient || lenient
+		return batch.addAt(index, req)
+	})
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	responses, err := batch.commit()
 	if err != nil {
 		s.writeServiceError(w, r, err)
 		return
 	}
 
-	s.log().Debug("tasks batch created", "requested", len(reqs), "created", len(responses))
+	s.log().Debug("tasks batch created", "created", len(responses))
 	s.writeJSON(w, http.StatusCreated, responses)
 }
 
@@ -193,7 +441,13 @@ func (s *Server) handleGetTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := s.service.Get(r.Context(), id)
+	includes, err := requestIncludes(r, s.getIncludes)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := s.service.GetWithIncludes(r.Context(), id, includes)
 	if err != nil {
 		s.writeServiceError(w, r, err)
 		return
@@ -202,6 +456,28 @@ func (s *Server) handleGetTask(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
+func (s *Server) handleTaskHistory(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := s.service.History(r.Context(), id)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	if events == nil {
+		events = []models.TaskEvent{}
+	}
+
+	s.writeJSON(w, http.StatusOK, events)
+}
+
 func (s *Server) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
 		return
@@ -247,16 +523,62 @@ func (s *Server) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
+func (s *Server) handleUpsertTask(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	lenient, err := queryBool(r, "lenient")
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req api.TaskCreateRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+	req.Lenient = req.Lenient || lenient
+
+	resp, err := s.service.Upsert(r.Context(), id, req)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("task upserted", "task_id", resp.Task.Task.ID, "created", resp.Created)
+	status := http.StatusOK
+	if resp.Created {
+		status = http.StatusCreated
+	}
+	s.writeJSON(w, status, resp)
+}
+
 func (s *Server) handleListTasks(w http.ResponseWriter, r *http.Request) {
 	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
 		return
 	}
 
+	r, err := s.withSavedView(r)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
 	filter, err := parseListFilter(r)
 	if err != nil {
 		s.writeErrorReq(w, r, http.StatusBadRequest, err)
 		return
 	}
+	filter.Includes, err = requestIncludes(r, s.listIncludes)
+	if err != nil {
+		s.writeErrorReq(w, r, http.StatusBadRequest, err)
+		return
+	}
 
 	heavySearch := filter.SearchQuery != "" || filter.SpecRegex != ""
 	if heavySearch {
@@ -272,6 +594,18 @@ func (s *Server) handleListTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if filter.OrderByID && filter.Limit > 0 && len(responses) == filter.Limit {
+		w.Header().Set("X-Next-Cursor", responses[len(responses)-1].ID)
+	}
+	if r.URL.Query().Get("include_total") == "true" {
+		total, err := s.service.Count(r.Context(), filter)
+		if err != nil {
+			s.writeServiceError(w, r, err)
+			return
+		}
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	}
+
 	s.log().Debug("tasks listed", "count", len(responses), "search", filter.SearchQuery != "", "spec_regex", filter.SpecRegex != "", "limit", filter.Limit, "offset", filter.Offset)
 	s.writeJSON(w, http.StatusOK, responses)
 }
@@ -2,12 +2,17 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"grns/internal/api"
+	"grns/internal/models"
 )
 
 func TestCreateTask_UnknownJSONFieldsAreIgnored(t *testing.T) {
@@ -96,3 +101,428 @@ func TestCreateTask_TrailingJSONRejected(t *testing.T) {
 		t.Fatalf("expected no tasks to be created, got %d", len(tasks))
 	}
 }
+
+func TestCreateTask_LenientClampsPriorityWithWarning(t *testing.T) {
+	srv := newListTestServer(t)
+
+	post := func(query string, payload map[string]any) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks"+query, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("", map[string]any{"title": "Strict", "priority": 9}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without lenient, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w := post("?lenient=true", map[string]any{"title": "Legacy", "priority": 9, "type": "story"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 with lenient, got %d (%s)", w.Code, w.Body.String())
+	}
+	var created api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if created.Priority != 4 || created.Type != "task" {
+		t.Fatalf("expected clamped priority 4 and default type, got %d/%s", created.Priority, created.Type)
+	}
+	if len(created.Warnings) != 2 {
+		t.Fatalf("expected priority and type warnings, got %v", created.Warnings)
+	}
+
+	if w := post("?lenient=true", map[string]any{"title": " ", "priority": 9}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected missing title to fail under lenient, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestReadyOrderParam(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for _, task := range []*models.Task{
+		{ID: "gr-rq01", Title: "low", Status: "open", Type: "task", Priority: 3, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-rq02", Title: "high", Status: "open", Type: "task", Priority: 0, CreatedAt: now, UpdatedAt: now.Add(-time.Hour)},
+	} {
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	for _, order := range []string{"priority", "score"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/ready?order="+order, nil)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("order=%s: expected 200, got %d (%s)", order, w.Code, w.Body.String())
+		}
+		var tasks []api.TaskResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &tasks); err != nil {
+			t.Fatalf("decode ready: %v", err)
+		}
+		if len(tasks) != 2 || tasks[0].ID != "gr-rq02" {
+			t.Fatalf("order=%s: expected gr-rq02 first, got %#v", order, tasks)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/ready?order=random", nil)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown order, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestTouchTasksBumpsUpdatedAtAndLogsEvent(t *testing.T) {
+	srv := newListTestServer(t)
+	var logs bytes.Buffer
+	srv.logger = slog.New(slog.NewJSONHandler(&logs, nil))
+
+	old := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	for _, id := range []string{"gr-tc01", "gr-tc02"} {
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, CreatedAt: old, UpdatedAt: old}
+		if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+			t.Fatalf("seed task %s: %v", id, err)
+		}
+	}
+
+	touch := func(ids ...string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(api.TaskTouchRequest{IDs: ids, Actor: "alice"})
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/touch", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := touch("gr-tc01", "gr-zz99"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing id, got %d (%s)", w.Code, w.Body.String())
+	}
+	unchanged, err := srv.store.GetTask(context.Background(), "gr-tc01")
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if !unchanged.UpdatedAt.Equal(old) {
+		t.Fatalf("expected failed touch to leave updated_at, got %s", unchanged.UpdatedAt)
+	}
+
+	if w := touch("gr-tc01", "gr-tc02"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	for _, id := range []string{"gr-tc01", "gr-tc02"} {
+		task, err := srv.store.GetTask(context.Background(), id)
+		if err != nil {
+			t.Fatalf("get task %s: %v", id, err)
+		}
+		if !task.UpdatedAt.After(old) {
+			t.Fatalf("expected updated_at to advance for %s, got %s", id, task.UpdatedAt)
+		}
+	}
+	if !strings.Contains(logs.String(), `"event":"touched"`) || !strings.Contains(logs.String(), `"actor":"alice"`) {
+		t.Fatalf("expected touched event in log, got %s", logs.String())
+	}
+}
+
+func TestCloseByFilterClosesOnlyMatchingTasks(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	seed := map[string][]string{
+		"gr-cf01": {"obsolete"},
+		"gr-cf02": {"obsolete", "ui"},
+		"gr-cf03": {"ui"},
+		"gr-cf04": nil,
+	}
+	for id, labels := range seed {
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(context.Background(), task, labels, nil); err != nil {
+			t.Fatalf("seed task %s: %v", id, err)
+		}
+	}
+
+	closeByFilter := func(confirm bool, filter map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(api.TaskCloseByFilterRequest{Filter: filter})
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/close-by-filter", bytes.NewReader(body))
+		if confirm {
+			req.Header.Set("X-Confirm", "true")
+		}
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := closeByFilter(false, map[string]string{"label": "obsolete"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without X-Confirm, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	srv.ConfigureCloseLimits(CloseLimitOptions{MaxByFilter: 1})
+	if w := closeByFilter(true, map[string]string{"label": "obsolete"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 over cap, got %d (%s)", w.Code, w.Body.String())
+	}
+	srv.ConfigureCloseLimits(CloseLimitOptions{MaxByFilter: 10})
+
+	w := closeByFilter(true, map[string]string{"label": "obsolete"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var resp api.TaskCloseByFilterResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Count != 2 || len(resp.IDs) != 2 {
+		t.Fatalf("expected 2 closed tasks, got %+v", resp)
+	}
+
+	for id, labels := range seed {
+		task, err := srv.store.GetTask(context.Background(), id)
+		if err != nil {
+			t.Fatalf("get task %s: %v", id, err)
+		}
+		wantClosed := len(labels) > 0 && labels[0] == "obsolete"
+		if closed := task.Status == "closed"; closed != wantClosed {
+			t.Fatalf("task %s: closed=%v, want %v", id, closed, wantClosed)
+		}
+	}
+}
+
+func TestCloseAcceptsFilterWithDryRunPreview(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	for id, labels := range map[string][]string{"gr-cq01": {"spike"}, "gr-cq02": {"spike"}, "gr-cq03": nil} {
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(context.Background(), task, labels, nil); err != nil {
+			t.Fatalf("seed task %s: %v", id, err)
+		}
+	}
+
+	closeTasks := func(confirm bool, req api.TaskCloseRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		httpReq := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/close", bytes.NewReader(body))
+		if confirm {
+			httpReq.Header.Set("X-Confirm", "true")
+		}
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, httpReq)
+		return w
+	}
+	filter := map[string]string{"q": "label:spike"}
+
+	for name, req := range map[string]api.TaskCloseRequest{
+		"ids and filter":         {IDs: []string{"gr-cq03"}, Filter: filter},
+		"commit and filter":      {Filter: filter, Commit: strings.Repeat("a", 40)},
+		"dry run without filter": {IDs: []string{"gr-cq03"}, DryRun: true},
+	} {
+		if w := closeTasks(true, req); w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d (%s)", name, w.Code, w.Body.String())
+		}
+	}
+	if w := closeTasks(false, api.TaskCloseRequest{Filter: filter}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without X-Confirm, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w := closeTasks(false, api.TaskCloseRequest{Filter: filter, DryRun: true})
+	var preview api.TaskCloseByFilterResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("dry run: %d %s (%v)", w.Code, w.Body.String(), err)
+	}
+	if !preview.DryRun || preview.Count != 2 {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+	if task, _ := srv.store.GetTask(context.Background(), "gr-cq01"); task.Status != "open" {
+		t.Fatalf("dry run closed gr-cq01")
+	}
+
+	w = closeTasks(true, api.TaskCloseRequest{Filter: filter})
+	var closed api.TaskCloseByFilterResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &closed); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("close: %d %s (%v)", w.Code, w.Body.String(), err)
+	}
+	if closed.DryRun || closed.Count != 2 {
+		t.Fatalf("unexpected close result: %+v", closed)
+	}
+	if task, _ := srv.store.GetTask(context.Background(), "gr-cq03"); task.Status != "open" {
+		t.Fatalf("unmatched task gr-cq03 was closed")
+	}
+}
+
+func TestTaskHistoryRecordsMutationsWithActor(t *testing.T) {
+	srv := newListTestServer(t)
+	handler := srv.routes()
+
+	send := func(method, path string, payload any) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("X-Actor", "agent-7")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code >= 300 {
+			t.Fatalf("%s %s: expected success, got %d (%s)", method, path, w.Code, w.Body.String())
+		}
+		return w
+	}
+
+	var created api.TaskResponse
+	w := send(http.MethodPost, "/v1/projects/gr/tasks", api.TaskCreateRequest{Title: "audited"})
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode created task: %v", err)
+	}
+	id := created.ID
+
+	status := "in_progress"
+	send(http.MethodPatch, "/v1/projects/gr/tasks/"+id, api.TaskUpdateRequest{Status: &status})
+	send(http.MethodPost, "/v1/projects/gr/tasks/"+id+"/labels", api.LabelsRequest{Labels: []string{"audit"}})
+	send(http.MethodPost, "/v1/projects/gr/tasks/close", api.TaskCloseRequest{IDs: []string{id}})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/"+id+"/history", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var events []models.TaskEvent
+	if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+		t.Fatalf("decode history: %v", err)
+	}
+
+	want := []models.TaskEventType{models.TaskEventCreated, models.TaskEventUpdated, models.TaskEventLabelsAdded, models.TaskEventClosed}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, event := range events {
+		if event.Type != want[i] || event.Actor != "agent-7" {
+			t.Fatalf("event %d: expected %s by agent-7, got %+v", i, want[i], event)
+		}
+	}
+	update := events[1].Changes
+	if len(update) != 1 || update[0].Field != "status" || update[0].Old != "open" || update[0].New != "in_progress" {
+		t.Fatalf("expected status diff on update, got %+v", update)
+	}
+}
+
+func TestBulkUpdateByIDsAndFilter(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	seed := map[string][]string{
+		"gr-bu01": {"backend"},
+		"gr-bu02": {"backend"},
+		"gr-bu03": {"frontend"},
+	}
+	for id, labels := range seed {
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 3, CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(context.Background(), task, labels, nil); err != nil {
+			t.Fatalf("seed task %s: %v", id, err)
+		}
+	}
+
+	bulkUpdate := func(payload api.TaskBulkUpdateRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/bulk-update", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+	get := func(id string) *models.Task {
+		t.Helper()
+		task, err := srv.store.GetTask(context.Background(), id)
+		if err != nil {
+			t.Fatalf("get task %s: %v", id, err)
+		}
+		return task
+	}
+
+	priority := 0
+	if w := bulkUpdate(api.TaskBulkUpdateRequest{IDs: []string{"gr-bu01", "gr-zz99"}, Update: api.TaskUpdateRequest{Priority: &priority}}); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing id, got %d (%s)", w.Code, w.Body.String())
+	}
+	if got := get("gr-bu01").Priority; got != 3 {
+		t.Fatalf("expected failed bulk update to change nothing, got priority %d", got)
+	}
+
+	if w := bulkUpdate(api.TaskBulkUpdateRequest{IDs: []string{"gr-bu01", "gr-bu03"}, Update: api.TaskUpdateRequest{Priority: &priority}}); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if get("gr-bu01").Priority != 0 || get("gr-bu03").Priority != 0 || get("gr-bu02").Priority != 3 {
+		t.Fatal("expected only listed ids to be re-prioritized")
+	}
+
+	assignee := "alice"
+	w := bulkUpdate(api.TaskBulkUpdateRequest{Filter: map[string]string{"label": "backend"}, Update: api.TaskUpdateRequest{Assignee: &assignee}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var resp api.TaskBulkUpdateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Count != 2 {
+		t.Fatalf("expected 2 updated tasks, got %+v", resp)
+	}
+	if get("gr-bu01").Assignee != "alice" || get("gr-bu02").Assignee != "alice" || get("gr-bu03").Assignee != "" {
+		t.Fatal("expected only backend tasks to be re-assigned")
+	}
+
+	if w := bulkUpdate(api.TaskBulkUpdateRequest{IDs: []string{"gr-bu01"}}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty update, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestUpsertTaskCreatesThenUpdates(t *testing.T) {
+	srv := newListTestServer(t)
+
+	upsert := func(id string, req api.TaskCreateRequest) (*httptest.ResponseRecorder, api.TaskUpsertResponse) {
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPut, "/v1/projects/gr/tasks/"+id, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, r)
+		var resp api.TaskUpsertResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	priority := 1
+	w, resp := upsert("gr-up01", api.TaskCreateRequest{Title: "Sync me", Priority: &priority, Labels: []string{"sync"}})
+	if w.Code != http.StatusCreated || !resp.Created {
+		t.Fatalf("expected create, got %d (%s)", w.Code, w.Body.String())
+	}
+	if resp.Task.ID != "gr-up01" || resp.Task.Priority != 1 || len(resp.Task.Labels) != 1 {
+		t.Fatalf("unexpected created task: %+v", resp.Task)
+	}
+
+	priority = 3
+	w, resp = upsert("gr-up01", api.TaskCreateRequest{Title: "Synced", Priority: &priority})
+	if w.Code != http.StatusOK || resp.Created {
+		t.Fatalf("expected update, got %d (%s)", w.Code, w.Body.String())
+	}
+	if resp.Task.Title != "Synced" || resp.Task.Priority != 3 || len(resp.Task.Labels) != 1 {
+		t.Fatalf("unexpected updated task: %+v", resp.Task)
+	}
+
+	if w, _ := upsert("gr-up01", api.TaskCreateRequest{ID: "gr-up02", Title: "x"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for mismatched id, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w, _ := upsert("xx-up03", api.TaskCreateRequest{Title: "x"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for foreign prefix, got %d (%s)", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"grns/internal/models"
+)
+
+func (s *Server) watchersConfigured(w http.ResponseWriter, r *http.Request) bool {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return false
+	}
+	if s.watcherService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("watchers are not configured")))
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleWatchTask(w http.ResponseWriter, r *http.Request) {
+	if !s.watchersConfigured(w, r) {
+		return
+	}
+	taskID, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	user := r.PathValue("user")
+	watchers, err := s.watcherService.Watch(r.Context(), taskID, user)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("task watched", "task_id", taskID, "user", user)
+	s.writeJSON(w, http.StatusOK, watchers)
+}
+
+func (s *Server) handleUnwatchTask(w http.ResponseWriter, r *http.Request) {
+	if !s.watchersConfigured(w, r) {
+		return
+	}
+	taskID, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	user := r.PathValue("user")
+	watchers, err := s.watcherService.Unwatch(r.Context(), taskID, user)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	if watchers == nil {
+		watchers = []models.TaskWatcher{}
+	}
+
+	s.log().Debug("task unwatched", "task_id", taskID, "user", user)
+	s.writeJSON(w, http.StatusOK, watchers)
+}
+
+func (s *Server) handleListTaskWatchers(w http.ResponseWriter, r *http.Request) {
+	if !s.watchersConfigured(w, r) {
+		return
+	}
+	taskID, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	watchers, err := s.watcherService.List(r.Context(), taskID)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	if watchers == nil {
+		watchers = []models.TaskWatcher{}
+	}
+	s.writeJSON(w, http.StatusOK, watchers)
+}
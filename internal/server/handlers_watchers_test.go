@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestTaskWatcherHandlers(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	task := &models.Task{ID: "gr-wt01", Title: "reviewed task", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+		t.Fatalf("seed task: %v", err)
+	}
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+	decodeWatchers := func(w *httptest.ResponseRecorder) []models.TaskWatcher {
+		t.Helper()
+		var watchers []models.TaskWatcher
+		if err := json.Unmarshal(w.Body.Bytes(), &watchers); err != nil {
+			t.Fatalf("decode watchers: %v", err)
+		}
+		return watchers
+	}
+
+	if w := send(http.MethodPut, "/v1/projects/gr/tasks/gr-zz99/watchers/alice"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing task, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPut, "/v1/projects/gr/tasks/gr-wt01/watchers/%20"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for blank user, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	for _, user := range []string{"bob", "alice", "bob"} {
+		if w := send(http.MethodPut, "/v1/projects/gr/tasks/gr-wt01/watchers/"+user); w.Code != http.StatusOK {
+			t.Fatalf("watch as %s: expected 200, got %d (%s)", user, w.Code, w.Body.String())
+		}
+	}
+	w := send(http.MethodGet, "/v1/projects/gr/tasks/gr-wt01/watchers")
+	if w.Code != http.StatusOK {
+		t.Fatalf("list watchers: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	watchers := decodeWatchers(w)
+	if len(watchers) != 2 || watchers[0].User != "alice" || watchers[1].User != "bob" {
+		t.Fatalf("unexpected watchers: %#v", watchers)
+	}
+
+	w = send(http.MethodGet, "/v1/projects/gr/tasks/gr-wt01")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get task: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var got api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode task: %v", err)
+	}
+	if len(got.Watchers) != 2 || got.Watchers[0] != "alice" || got.Watchers[1] != "bob" {
+		t.Fatalf("expected watchers on task detail, got %#v", got.Watchers)
+	}
+
+	w = send(http.MethodDelete, "/v1/projects/gr/tasks/gr-wt01/watchers/alice")
+	if w.Code != http.StatusOK {
+		t.Fatalf("unwatch: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if watchers := decodeWatchers(w); len(watchers) != 1 || watchers[0].User != "bob" {
+		t.Fatalf("unexpected watchers after unwatch: %#v", watchers)
+	}
+	if w := send(http.MethodDelete, "/v1/projects/gr/tasks/gr-wt01/watchers/alice"); w.Code != http.StatusOK {
+		t.Fatalf("repeat unwatch: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+}
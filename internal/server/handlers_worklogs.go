@@ -0,0 +1,67 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func (s *Server) handleCreateTaskWorklog(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	if s.worklogService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("worklogs are not configured")))
+		return
+	}
+
+	taskID, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req api.TaskWorklogCreateRequest
+	if !s.decodeJSONReq(w, r, &req) {
+		return
+	}
+
+	worklog, err := s.worklogService.Create(r.Context(), taskID, req)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.log().Debug("task worklog created", "task_id", taskID, "worklog_id", worklog.ID, "actor", worklog.Actor, "seconds", worklog.Seconds)
+	s.writeJSON(w, http.StatusCreated, worklog)
+}
+
+func (s *Server) handleListTaskWorklogs(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathProjectOrBadRequest(w, r); !ok {
+		return
+	}
+
+	if s.worklogService == nil {
+		s.writeServiceError(w, r, internalError(fmt.Errorf("worklogs are not configured")))
+		return
+	}
+
+	taskID, ok := s.pathIDOrBadRequest(w, r)
+	if !ok {
+		return
+	}
+
+	worklogs, err := s.worklogService.List(r.Context(), taskID)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	if worklogs == nil {
+		worklogs = []models.TaskWorklog{}
+	}
+
+	s.log().Debug("task worklogs listed", "task_id", taskID, "count", len(worklogs))
+	s.writeJSON(w, http.StatusOK, worklogs)
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestTaskWorklogHandlers(t *testing.T) {
+	srv := newListTestServer(t)
+	now := time.Now().UTC()
+	task := &models.Task{ID: "gr-wl01", Title: "tracked task", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := srv.store.CreateTask(context.Background(), task, nil, nil); err != nil {
+		t.Fatalf("seed task: %v", err)
+	}
+
+	post := func(taskID string, payload api.TaskWorklogCreateRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks/"+taskID+"/worklogs", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("gr-wl01", api.TaskWorklogCreateRequest{Actor: "alice"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for zero seconds, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := post("gr-wl01", api.TaskWorklogCreateRequest{Seconds: 60}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without actor, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := post("gr-zz99", api.TaskWorklogCreateRequest{Actor: "alice", Seconds: 60}); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing task, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	started := now.Add(-2 * time.Hour).Truncate(time.Second)
+	w := post("gr-wl01", api.TaskWorklogCreateRequest{Actor: "alice", Seconds: 5400, Note: "pairing", StartedAt: &started})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create worklog: expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	var created models.TaskWorklog
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode worklog: %v", err)
+	}
+	if created.TaskID != "gr-wl01" || created.Actor != "alice" || created.Seconds != 5400 || !created.StartedAt.Equal(started) {
+		t.Fatalf("unexpected worklog: %#v", created)
+	}
+	if w := post("gr-wl01", api.TaskWorklogCreateRequest{Actor: "bob", Seconds: 600}); w.Code != http.StatusCreated {
+		t.Fatalf("create second worklog: expected 201, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/gr-wl01/worklogs", nil)
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list worklogs: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var worklogs []models.TaskWorklog
+	if err := json.Unmarshal(w.Body.Bytes(), &worklogs); err != nil {
+		t.Fatalf("decode worklogs: %v", err)
+	}
+	if len(worklogs) != 2 || worklogs[0].ID != created.ID || worklogs[1].Actor != "bob" {
+		t.Fatalf("unexpected worklogs: %#v", worklogs)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks/gr-wl01", nil)
+	w = httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get task: expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var got api.TaskResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode task: %v", err)
+	}
+	if got.TotalTimeSeconds != 6000 {
+		t.Fatalf("expected total_time_seconds 6000, got %d", got.TotalTimeSeconds)
+	}
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"grns/internal/store"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	// idempotencyKeyRetention is how long a stored response is replayed for its key.
+	idempotencyKeyRetention = 24 * time.Hour
+	// idempotencyPruneInterval is how often expired records are deleted.
+	idempotencyPruneInterval = time.Hour
+	maxIdempotencyKeyLength  = 255
+)
+
+// idempotentWriteRoutes are the project-scoped POST routes, relative to
+// /v1/projects/{project}/, that honor Idempotency-Key, with the body limit
+// their handler enforces.
+var idempotentWriteRoutes = map[string]int64{
+	"tasks":       defaultJSONMaxBody,
+	"tasks/batch": batchJSONMaxBody,
+	"tasks/close": defaultJSONMaxBody,
+	"import":      importJSONMaxBody,
+	"rpc":         batchJSONMaxBody,
+}
+
+// idempotencyLocks serializes requests sharing one key and scope, so a retry
+// that races the original waits for its stored response instead of running twice.
+type idempotencyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*idempotencyLock
+}
+
+type idempotencyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func (l *idempotencyLocks) lock(name string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*idempotencyLock{}
+	}
+	entry := l.locks[name]
+	if entry == nil {
+		entry = &idempotencyLock{}
+		l.locks[name] = entry
+	}
+	entry.refs++
+	l.mu.Unlock()
+
+	entry.mu.Lock()
+	return func() {
+		entry.mu.Unlock()
+		l.mu.Lock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(l.locks, name)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// idempotentWriteRoute returns the body limit of r's route, or false when
+// the route does not honor Idempotency-Key.
+func idempotentWriteRoute(r *http.Request) (int64, bool) {
+	if r.Method != http.MethodPost {
+		return 0, false
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, "/v1/projects/")
+	if !ok {
+		return 0, false
+	}
+	_, route, ok := strings.Cut(rest, "/")
+	if !ok {
+		return 0, false
+	}
+	maxBody, ok := idempotentWriteRoutes[route]
+	return maxBody, ok
+}
+
+// idempotencyScope keys stored responses by caller and endpoint, so one
+// caller cannot replay another's response by reusing its key.
+func idempotencyScope(r *http.Request) string {
+	caller := "anonymous"
+	if principal, ok := authPrincipalFromContext(r.Context()); ok {
+		switch {
+		case principal.User != nil:
+			caller = "user:" + principal.User.Username
+		case principal.Token != nil:
+			caller = "token:" + principal.Token.ID
+		case principal.AuthType != "":
+			caller = principal.AuthType
+		}
+	}
+	return caller + " " + r.Method + " " + r.URL.Path
+}
+
+// withIdempotency replays the stored response when a write is retried with the
+// same Idempotency-Key. Only 2xx responses are stored; reusing a key with a
+// different request body or query is rejected with 422.
+func (s *Server) withIdempotency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get(idempotencyKeyHeader))
+		maxBody, ok := idempotentWriteRoute(r)
+		if key == "" || s.idempotency == nil || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			s.writeErrorReq(w, r, http.StatusBadRequest, badRequestCode(fmt.Errorf("%s must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength), ErrCodeInvalidArgument))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+		if err != nil {
+			s.writeErrorReq(w, r, http.StatusBadRequest, classifyDecodeJSONError(err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		scope := idempotencyScope(r)
+		hash := idempotencyRequestHash(r.URL.RawQuery, body)
+
+		unlock := s.idempotencyLocks.lock(key + "\x00" + scope)
+		defer unlock()
+
+		now := time.Now().UTC()
+		record, err := s.idempotency.GetIdempotencyRecord(r.Context(), key, scope)
+		if err != nil {
+			s.writeStoreError(w, r, err)
+			return
+		}
+		if record != nil && record.CreatedAt.After(now.Add(-idempotencyKeyRetention)) {
+			if record.RequestHash != hash {
+				s.writeErrorReq(w, r, http.StatusUnprocessableEntity, idempotencyKeyReused(fmt.Errorf("%s was already used with a different request", idempotencyKeyHeader)))
+				return
+			}
+			s.log().Debug("idempotent request replayed", "method", r.Method, "path", r.URL.Path, "status", record.Status)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(idempotencyReplayedHeader, "true")
+			w.WriteHeader(record.Status)
+			_, _ = w.Write(record.Body)
+			return
+		}
+
+		capture := &idempotencyResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(capture, r)
+		if capture.status < 200 || capture.status >= 300 {
+			return
+		}
+		record = &store.IdempotencyRecord{
+			Key:         key,
+			Scope:       scope,
+			RequestHash: hash,
+			Status:      capture.status,
+			Body:        capture.body.Bytes(),
+			CreatedAt:   now,
+		}
+		if err := s.idempotency.SaveIdempotencyRecord(r.Context(), record); err != nil {
+			s.log().Warn("save idempotency record", "method", r.Method, "path", r.URL.Path, "error", err)
+		}
+	})
+}
+
+// pruneIdempotencyRecords deletes records past their retention every interval until done closes.
+func (s *Server) pruneIdempotencyRecords(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			cutoff := time.Now().UTC().Add(-idempotencyKeyRetention)
+			if _, err := s.idempotency.PruneIdempotencyRecords(context.Background(), cutoff); err != nil {
+				s.log().Warn("prune idempotency records", "error", err)
+			}
+		}
+	}
+}
+
+func idempotencyRequestHash(query string, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(query))
+	sum.Write([]byte{0})
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// idempotencyResponseWriter records the status and body written through it.
+type idempotencyResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (c *idempotencyResponseWriter) WriteHeader(status int) {
+	if !c.wroteHeader {
+		c.status = status
+		c.wroteHeader = true
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *idempotencyResponseWriter) Write(p []byte) (int, error) {
+	c.wroteHeader = true
+	c.body.Write(p)
+	return c.ResponseWriter.Write(p)
+}
+
+func (c *idempotencyResponseWriter) Flush() {
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
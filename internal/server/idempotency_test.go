@@ -0,0 +1,142 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"grns/internal/api"
+	"grns/internal/store"
+)
+
+func postWithIdempotencyKey(srv *Server, path, key string, body any) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	if key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyKeyReplaysCreate(t *testing.T) {
+	srv := newListTestServer(t)
+	create := api.TaskCreateRequest{Title: "retry me"}
+
+	first := postWithIdempotencyKey(srv, "/v1/projects/gr/tasks", "key-1", create)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("create: %d (%s)", first.Code, first.Body.String())
+	}
+	second := postWithIdempotencyKey(srv, "/v1/projects/gr/tasks", "key-1", create)
+	if second.Code != http.StatusCreated {
+		t.Fatalf("replay: %d (%s)", second.Code, second.Body.String())
+	}
+	if second.Header().Get(idempotencyReplayedHeader) != "true" {
+		t.Fatal("expected replayed response header")
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Fatalf("expected identical bodies:\n%s\n%s", first.Body.String(), second.Body.String())
+	}
+
+	listReq := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks", nil)
+	listW := httptest.NewRecorder()
+	srv.routes().ServeHTTP(listW, listReq)
+	var tasks []api.TaskResponse
+	if err := json.Unmarshal(listW.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	count := 0
+	for _, task := range tasks {
+		if task.Title == "retry me" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Fatalf("expected one task, got %d", count)
+	}
+
+	other := postWithIdempotencyKey(srv, "/v1/projects/gr/tasks", "key-2", create)
+	if other.Code != http.StatusCreated || other.Header().Get(idempotencyReplayedHeader) != "" {
+		t.Fatalf("expected a fresh create for a new key, got %d", other.Code)
+	}
+}
+
+func TestIdempotencyKeyRejectsDifferentRequest(t *testing.T) {
+	srv := newListTestServer(t)
+
+	if w := postWithIdempotencyKey(srv, "/v1/projects/gr/tasks", "key-1", api.TaskCreateRequest{Title: "one"}); w.Code != http.StatusCreated {
+		t.Fatalf("create: %d (%s)", w.Code, w.Body.String())
+	}
+	w := postWithIdempotencyKey(srv, "/v1/projects/gr/tasks", "key-1", api.TaskCreateRequest{Title: "two"})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d (%s)", w.Code, w.Body.String())
+	}
+	var errResp api.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if errResp.ErrorCode != ErrCodeIdempotencyKeyReused {
+		t.Fatalf("unexpected error response: %+v", errResp)
+	}
+}
+
+func TestIdempotencyKeyDoesNotStoreFailures(t *testing.T) {
+	srv := newListTestServer(t)
+
+	if w := postWithIdempotencyKey(srv, "/v1/projects/gr/tasks", "key-1", api.TaskCreateRequest{}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d (%s)", w.Code, w.Body.String())
+	}
+	w := postWithIdempotencyKey(srv, "/v1/projects/gr/tasks", "key-1", api.TaskCreateRequest{Title: "fixed"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected key to be reusable after a failure, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestIdempotencyKeyIsScopedToPrincipal(t *testing.T) {
+	srv := newListTestServer(t)
+	calls := 0
+	handler := srv.withIdempotency(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		srv.writeJSON(w, http.StatusCreated, map[string]int{"call": calls})
+	}))
+	post := func(principal authPrincipal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks", strings.NewReader(`{"title":"same"}`))
+		req = req.WithContext(contextWithAuthPrincipal(req.Context(), principal))
+		req.Header.Set(idempotencyKeyHeader, "shared-key")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	alice := authPrincipal{AuthType: authTypeAPIToken, Token: &store.APIToken{ID: "tok-alice"}}
+	bob := authPrincipal{AuthType: authTypeAPIToken, Token: &store.APIToken{ID: "tok-bob"}}
+	if w := post(alice); w.Code != http.StatusCreated {
+		t.Fatalf("alice: %d (%s)", w.Code, w.Body.String())
+	}
+	if w := post(bob); w.Code != http.StatusCreated || w.Header().Get(idempotencyReplayedHeader) != "" {
+		t.Fatalf("expected bob's request to run, got %d replayed=%q", w.Code, w.Header().Get(idempotencyReplayedHeader))
+	}
+	if w := post(alice); w.Header().Get(idempotencyReplayedHeader) != "true" {
+		t.Fatalf("expected alice's retry to replay, got %d", w.Code)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 handler calls, got %d", calls)
+	}
+}
+
+func TestIdempotencyKeyUsesRouteBodyLimit(t *testing.T) {
+	srv := newListTestServer(t)
+	body := `{"title":"` + strings.Repeat("x", defaultJSONMaxBody) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks", strings.NewReader(body))
+	req.Header.Set(idempotencyKeyHeader, "big")
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	var errResp api.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil || w.Code != http.StatusBadRequest || errResp.ErrorCode != ErrCodeRequestTooLarge {
+		t.Fatalf("expected request_too_large for a body over the create limit, got %d (%s)", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"grns/internal/api"
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+// remapImportIDs gives every record that has an id a fresh id under the
+// destination project and rewrites parent_id, merged_into, and deps to match.
+// Attachments and git refs get fresh ids too, so they are not mistaken for the
+// originals. References to tasks outside the import are kept when they already
+// belong to the project and dropped with a warning otherwise. The old to new
+// task id mapping is returned in response.IDMap.
+func (i *Importer) remapImportIDs(ctx context.Context, run *importRun) error {
+	prefix, err := normalizePrefix(run.project)
+	if err != nil {
+		return badRequestCode(fmt.Errorf("invalid project"), ErrCodeInvalidArgument)
+	}
+
+	idMap := make(map[string]string, len(run.req.Tasks))
+	assigned := make(map[string]bool, len(run.req.Tasks))
+	exists := func(id string) (bool, error) {
+		if assigned[id] {
+			return true, nil
+		}
+		return i.store.TaskExists(id)
+	}
+	for _, rec := range run.req.Tasks {
+		oldID := strings.TrimSpace(rec.ID)
+		if oldID == "" {
+			continue
+		}
+		if _, ok := idMap[oldID]; ok {
+			return badRequestCode(fmt.Errorf("duplicate id in import: %s", oldID), ErrCodeInvalidID)
+		}
+		newID, err := store.GenerateID(prefix, exists)
+		if err != nil {
+			return err
+		}
+		idMap[oldID] = newID
+		assigned[newID] = true
+	}
+
+	tasks := make([]api.TaskImportRecord, len(run.req.Tasks))
+	for idx, rec := range run.req.Tasks {
+		newID, ok := idMap[strings.TrimSpace(rec.ID)]
+		if !ok {
+			tasks[idx] = rec
+			continue
+		}
+		rec.ID = newID
+		rec.Project = ""
+		warn := func(format string, args ...any) {
+			run.response.Warnings = append(run.response.Warnings, fmt.Sprintf("%s: %s", newID, fmt.Sprintf(format, args...)))
+		}
+
+		if parentID, ok := remapImportRef(idMap, prefix, rec.ParentID); ok {
+			rec.ParentID = parentID
+		} else {
+			warn("dropped parent_id %s: not part of this import", rec.ParentID)
+			rec.ParentID = ""
+		}
+		if mergedInto, ok := remapImportRef(idMap, prefix, rec.MergedInto); ok {
+			rec.MergedInto = mergedInto
+		} else {
+			warn("dropped merged_into %s: not part of this import", rec.MergedInto)
+			rec.MergedInto = ""
+		}
+		if rec.Deps != nil {
+			deps := make([]models.Dependency, 0, len(rec.Deps))
+			for _, dep := range rec.Deps {
+				parentID, ok := remapImportRef(idMap, prefix, dep.ParentID)
+				if !ok {
+					warn("dropped dependency on %s: not part of this import", dep.ParentID)
+					continue
+				}
+				dep.ParentID = parentID
+				deps = append(deps, dep)
+			}
+			rec.Deps = deps
+		}
+		if rec.Attachments != nil {
+			attachments := make([]api.AttachmentRecord, len(rec.Attachments))
+			for j, attachment := range rec.Attachments {
+				if attachment.ID, err = i.newAttachmentID(ctx); err != nil {
+					return err
+				}
+				attachment.TaskID = newID
+				attachments[j] = attachment
+			}
+			rec.Attachments = attachments
+		}
+		if rec.GitRefs != nil {
+			refs := make([]models.TaskGitRef, len(rec.GitRefs))
+			for j, ref := range rec.GitRefs {
+				if ref.ID, err = i.newGitRefID(ctx); err != nil {
+					return err
+				}
+				ref.TaskID = newID
+				refs[j] = ref
+			}
+			rec.GitRefs = refs
+		}
+		tasks[idx] = rec
+	}
+
+	run.req.Tasks = tasks
+	run.response.IDMap = idMap
+	return nil
+}
+
+func (i *Importer) newAttachmentID(ctx context.Context) (string, error) {
+	return store.GenerateAttachmentID(func(id string) (bool, error) {
+		if i.attachments == nil {
+			return false, nil
+		}
+		existing, err := i.attachments.GetAttachment(ctx, "", id)
+		return existing != nil, err
+	})
+}
+
+func (i *Importer) newGitRefID(ctx context.Context) (string, error) {
+	return store.GenerateTaskGitRefID(func(id string) (bool, error) {
+		if i.gitRefs == nil {
+			return false, nil
+		}
+		existing, err := i.gitRefs.GetTaskGitRef(ctx, "", id)
+		return existing != nil, err
+	})
+}
+
+// remapImportRef returns the new id for a task reference. ok is false when the
+// reference points outside both the import and the destination project.
+func remapImportRef(idMap map[string]string, prefix, ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", true
+	}
+	if newID, ok := idMap[ref]; ok {
+		return newID, true
+	}
+	return ref, taskIDBelongsToProject(ref, prefix)
+}
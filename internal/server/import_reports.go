@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"grns/internal/models"
+)
+
+// importReportRetention is how long saved import conflict reports stay downloadable.
+const importReportRetention = 7 * 24 * time.Hour
+
+// SaveImportReport persists an import's conflicts and returns the report id,
+// or 0 when there is nothing to save. Reports past the retention are pruned.
+func (s *TaskService) SaveImportReport(ctx context.Context, dryRun bool, conflicts []models.ImportConflict) (int64, error) {
+	if len(conflicts) == 0 {
+		return 0, nil
+	}
+	if s.importReports == nil {
+		return 0, internalError(fmt.Errorf("import reports are not configured"))
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return 0, err
+	}
+	report := &models.ImportReport{Project: project, DryRun: dryRun, Conflicts: conflicts, CreatedAt: time.Now().UTC()}
+	if err := s.importReports.SaveImportReport(ctx, report); err != nil {
+		return 0, err
+	}
+	if _, err := s.importReports.PruneImportReports(ctx, report.CreatedAt.Add(-importReportRetention)); err != nil {
+		return 0, err
+	}
+	return report.ID, nil
+}
+
+// ImportReport returns a saved import conflict report.
+func (s *TaskService) ImportReport(ctx context.Context, id int64) (models.ImportReport, error) {
+	if s.importReports == nil {
+		return models.ImportReport{}, internalError(fmt.Errorf("import reports are not configured"))
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+	report, err := s.importReports.GetImportReport(ctx, project, id)
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+	if report == nil {
+		return models.ImportReport{}, notFoundCode(fmt.Errorf("import report not found"), ErrCodeImportReportNotFound)
+	}
+	return *report, nil
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"grns/internal/api"
+)
+
+// importStream applies a stream of import records in chunks of
+// importStreamChunkSize and merges the chunk results into one response.
+// Atomic and remapped imports are staged whole and applied by finish.
+type importStream struct {
+	service  *TaskService
+	logger   *slog.Logger
+	opts     importStreamOptions
+	response api.ImportResponse
+	chunk    []api.TaskImportRecord
+	// chunkLines holds the stream position of each chunk record, so conflict
+	// lines refer to the stream rather than to the chunk.
+	chunkLines []int
+	chunks     int
+	records    int
+}
+
+func (s *TaskService) newImportStream(opts importStreamOptions, logger *slog.Logger) *importStream {
+	return &importStream{
+		service:    s,
+		logger:     logger,
+		opts:       opts,
+		response:   api.ImportResponse{DryRun: opts.dryRun, TaskIDs: []string{}},
+		chunk:      make([]api.TaskImportRecord, 0, importStreamChunkSize),
+		chunkLines: make([]int, 0, importStreamChunkSize),
+	}
+}
+
+// add queues rec, read at line of the stream, and applies the chunk once it is full.
+func (st *importStream) add(ctx context.Context, rec api.TaskImportRecord, line int) error {
+	st.records++
+	st.chunk = append(st.chunk, rec)
+	st.chunkLines = append(st.chunkLines, line)
+	if len(st.chunk) >= importStreamChunkSize && !st.opts.atomic && !st.opts.remapIDs {
+		return st.flush(ctx)
+	}
+	return nil
+}
+
+// finish applies the remaining records, saves the conflict report if
+// requested, and returns the merged response.
+func (st *importStream) finish(ctx context.Context) (api.ImportResponse, error) {
+	if st.records == 0 {
+		return st.response, badRequestCode(fmt.Errorf("no records found in input"), ErrCodeMissingRequired)
+	}
+	if err := st.flush(ctx); err != nil {
+		return st.response, err
+	}
+	if st.opts.saveReport {
+		reportID, err := st.service.SaveImportReport(ctx, st.opts.dryRun, st.response.Conflicts)
+		if err != nil {
+			return st.response, err
+		}
+		st.response.ReportID = reportID
+	}
+	resp := st.response
+	st.logger.Debug("import stream complete", "created", resp.Created, "updated", resp.Updated, "skipped", resp.Skipped, "errors", resp.Errors, "chunks", st.chunks, "apply_mode", resp.ApplyMode, "rolled_back", resp.RolledBack)
+	return resp, nil
+}
+
+func (st *importStream) flush(ctx context.Context) error {
+	if len(st.chunk) == 0 {
+		return nil
+	}
+	st.chunks++
+	chunkSize := len(st.chunk)
+	st.logger.Debug("import stream chunk", "chunk", st.chunks, "size", chunkSize)
+	resp, err := st.service.Import(ctx, api.ImportRequest{
+		Tasks:          st.chunk,
+		DryRun:         st.opts.dryRun,
+		Dedupe:         st.opts.dedupe,
+		OrphanHandling: st.opts.orphanHandling,
+		Atomic:         st.opts.atomic,
+		Lenient:        st.opts.lenient,
+		SourceLabel:    st.opts.sourceLabel,
+		RemapIDs:       st.opts.remapIDs,
+	})
+	if err != nil {
+		return err
+	}
+	response := &st.response
+	response.Created += resp.Created
+	response.Updated += resp.Updated
+	response.Skipped += resp.Skipped
+	response.Errors += resp.Errors
+	response.TaskIDs = append(response.TaskIDs, resp.TaskIDs...)
+	response.Messages = append(response.Messages, resp.Messages...)
+	response.Warnings = append(response.Warnings, resp.Warnings...)
+	for _, conflict := range resp.Conflicts {
+		if conflict.Line >= 1 && conflict.Line <= len(st.chunkLines) {
+			conflict.Line = st.chunkLines[conflict.Line-1]
+		}
+		response.Conflicts = append(response.Conflicts, conflict)
+	}
+	response.AppliedChunks += resp.AppliedChunks
+	response.AttachmentsCreated += resp.AttachmentsCreated
+	response.GitRefsCreated += resp.GitRefsCreated
+	response.RolledBack = response.RolledBack || resp.RolledBack
+	response.IDMap = resp.IDMap
+	if response.ApplyMode == "" {
+		response.ApplyMode = resp.ApplyMode
+	}
+	st.logger.Debug("import stream chunk complete", "chunk", st.chunks, "size", chunkSize, "created", resp.Created, "updated", resp.Updated, "skipped", resp.Skipped, "errors", resp.Errors)
+	st.chunk = st.chunk[:0]
+	st.chunkLines = st.chunkLines[:0]
+	return nil
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -13,7 +14,12 @@ import (
 
 // Importer executes import requests in explicit phases.
 type Importer struct {
-	store store.ImportStore
+	store       store.ImportStore
+	attachments store.AttachmentStore
+	gitRefs     store.GitRefStore
+
+	// defaultSourceLabel is added to imported tasks when a request sets no source_label.
+	defaultSourceLabel string
 }
 
 // NewImporter constructs an Importer.
@@ -21,12 +27,16 @@ func NewImporter(store store.ImportStore) *Importer {
 	return &Importer{store: store}
 }
 
+// errImportRolledBack aborts an atomic import transaction that recorded record errors.
+var errImportRolledBack = errors.New("import rolled back")
+
 type importTaskAction int
 
 const (
 	importActionNone importTaskAction = iota
 	importActionCreated
 	importActionUpdated
+	importActionMerged
 	importActionSkipped
 	importActionError
 )
@@ -36,6 +46,7 @@ type importRun struct {
 	req             api.ImportRequest
 	dedupe          string
 	orphanHandling  string
+	sourceLabel     string
 	response        api.ImportResponse
 	normalized      []api.TaskImportRecord
 	actions         []importTaskAction
@@ -43,7 +54,8 @@ type importRun struct {
 	taskExistsCache map[string]bool
 }
 
-// Import processes an import request (validate/normalize -> upsert tasks -> apply deps).
+// Import processes an import request (validate/normalize -> upsert tasks -> apply deps
+// -> add attachments and git refs).
 func (i *Importer) Import(ctx context.Context, req api.ImportRequest, project string) (api.ImportResponse, error) {
 	applyMode := "best_effort"
 	if req.Atomic {
@@ -67,12 +79,23 @@ func (i *Importer) Import(ctx context.Context, req api.ImportRequest, project st
 	if run.orphanHandling == "" {
 		run.orphanHandling = "allow"
 	}
+	sourceLabel, err := resolveImportSourceLabel(req.SourceLabel, i.defaultSourceLabel)
+	if err != nil {
+		return run.response, err
+	}
+	run.sourceLabel = sourceLabel
 
+	if req.RemapIDs {
+		if err := i.remapImportIDs(ctx, run); err != nil {
+			return run.response, err
+		}
+	}
 	if err := i.normalizeAndValidate(run); err != nil {
 		return run.response, err
 	}
 
 	if req.Atomic && !req.DryRun {
+		// Any record error, including ones found while validating, rolls back every record.
 		err := i.store.RunInTx(ctx, func(mutator store.ImportMutator) error {
 			if err := i.applyTaskUpserts(ctx, run, mutator); err != nil {
 				return err
@@ -80,13 +103,20 @@ func (i *Importer) Import(ctx context.Context, req api.ImportRequest, project st
 			if err := i.applyDependencies(ctx, run, mutator); err != nil {
 				return err
 			}
+			if run.response.Errors > 0 {
+				return errImportRolledBack
+			}
 			return nil
 		})
+		if errors.Is(err, errImportRolledBack) {
+			run.response.RolledBack = true
+			return run.response, nil
+		}
 		if err != nil {
 			return run.response, err
 		}
 		run.response.AppliedChunks = 1
-		return run.response, nil
+		return run.response, i.applyArchiveSections(ctx, run)
 	}
 
 	if err := i.applyTaskUpserts(ctx, run, i.store); err != nil {
@@ -95,6 +125,9 @@ func (i *Importer) Import(ctx context.Context, req api.ImportRequest, project st
 	if err := i.applyDependencies(ctx, run, i.store); err != nil {
 		return run.response, err
 	}
+	if err := i.applyArchiveSections(ctx, run); err != nil {
+		return run.response, err
+	}
 	if !req.DryRun {
 		run.response.AppliedChunks = 1
 	}
@@ -104,14 +137,19 @@ func (i *Importer) Import(ctx context.Context, req api.ImportRequest, project st
 
 func (i *Importer) normalizeAndValidate(run *importRun) error {
 	for idx, raw := range run.req.Tasks {
-		rec, skip, err := normalizeImportRecord(raw, run.project)
+		validation := &lenientValidation{enabled: run.req.Lenient}
+		rec, skip, err := normalizeImportRecord(raw, run.project, validation)
 		if err != nil {
 			return badRequest(err)
 		}
+		for _, warning := range validation.warnings {
+			run.response.Warnings = append(run.response.Warnings, fmt.Sprintf("%s: %s", rec.ID, warning))
+		}
 		if skip {
 			run.actions[idx] = importActionError
 			run.response.Errors++
 			run.response.Messages = append(run.response.Messages, "skipping record with missing id or title")
+			run.conflict(idx, rec.ID, models.ImportConflictMissingField, "record is missing id or title", "add id and title to the record")
 			continue
 		}
 
@@ -143,17 +181,28 @@ func (i *Importer) applyTaskUpserts(ctx context.Context, run *importRun, mutator
 				run.actions[idx] = importActionError
 				run.response.Errors++
 				run.response.Messages = append(run.response.Messages, fmt.Sprintf("duplicate id: %s", rec.ID))
+				run.conflict(idx, rec.ID, models.ImportConflictDuplicateID, fmt.Sprintf("task %s already exists", rec.ID),
+					"re-import with dedupe=merge or dedupe=overwrite to update the existing task, or dedupe=skip to keep it")
 				continue
 			case "overwrite":
 				run.actions[idx] = importActionUpdated
 				if !run.req.DryRun {
-					if err := i.overwriteTask(ctx, mutator, rec); err != nil {
+					if err := i.overwriteTask(ctx, mutator, rec, run.sourceLabel); err != nil {
 						return err
 					}
 				}
 				run.response.Updated++
 				run.taskExistsCache[rec.ID] = true
 				run.response.TaskIDs = append(run.response.TaskIDs, rec.ID)
+			case "merge":
+				run.actions[idx] = importActionMerged
+				if !run.req.DryRun {
+					if err := i.mergeTask(ctx, mutator, rec, run.sourceLabel); err != nil {
+						return err
+					}
+				}
+				run.response.Updated++
+				run.response.TaskIDs = append(run.response.TaskIDs, rec.ID)
 			}
 			continue
 		}
@@ -161,7 +210,7 @@ func (i *Importer) applyTaskUpserts(ctx context.Context, run *importRun, mutator
 		run.actions[idx] = importActionCreated
 		if !run.req.DryRun {
 			task := rec.Task
-			if err := mutator.CreateTask(ctx, &task, rec.Labels, nil); err != nil {
+			if err := mutator.CreateTask(ctx, &task, withSourceLabel(rec.Labels, run.sourceLabel), nil); err != nil {
 				return err
 			}
 		}
@@ -173,19 +222,50 @@ func (i *Importer) applyTaskUpserts(ctx context.Context, run *importRun, mutator
 	return nil
 }
 
-func (i *Importer) overwriteTask(ctx context.Context, mutator store.ImportMutator, rec api.TaskImportRecord) error {
+// overwriteTask updates an existing task. Labels are only replaced when the record
+// carries them, so the source label from the original import survives otherwise.
+func (i *Importer) overwriteTask(ctx context.Context, mutator store.ImportMutator, rec api.TaskImportRecord, sourceLabel string) error {
 	update := buildTaskUpdateFromImport(rec)
 	if err := mutator.UpdateTask(ctx, rec.ID, update.toStoreTaskUpdate()); err != nil {
 		return err
 	}
 	if rec.Labels != nil {
-		if err := mutator.ReplaceLabels(ctx, rec.ID, rec.Labels); err != nil {
+		if err := mutator.ReplaceLabels(ctx, rec.ID, withSourceLabel(rec.Labels, sourceLabel)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// mergeTask folds a record into an existing task (see buildTaskMergeFromImport).
+// Labels are unioned with the task's current ones; dependencies are only added,
+// by applyDependencies.
+func (i *Importer) mergeTask(ctx context.Context, mutator store.ImportMutator, rec api.TaskImportRecord, sourceLabel string) error {
+	existing, err := mutator.GetTask(ctx, rec.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return notFoundCode(fmt.Errorf("task not found: %s", rec.ID), ErrCodeTaskNotFound)
+	}
+	update := buildTaskMergeFromImport(*existing, rec)
+	if err := mutator.UpdateTask(ctx, rec.ID, update.toStoreTaskUpdate()); err != nil {
+		return err
+	}
+	if len(rec.Labels) == 0 && sourceLabel == "" {
+		return nil
+	}
+	labels, err := mutator.ListLabels(ctx, rec.ID)
+	if err != nil {
+		return err
+	}
+	merged, err := normalizeLabels(append(labels, rec.Labels...))
+	if err != nil {
+		return badRequest(err)
+	}
+	return mutator.ReplaceLabels(ctx, rec.ID, withSourceLabel(merged, sourceLabel))
+}
+
 func (i *Importer) applyDependencies(ctx context.Context, run *importRun, mutator store.ImportMutator) error {
 	if run.req.DryRun {
 		return nil
@@ -193,14 +273,14 @@ func (i *Importer) applyDependencies(ctx context.Context, run *importRun, mutato
 
 	for idx, rec := range run.normalized {
 		action := run.actions[idx]
-		if action != importActionCreated && action != importActionUpdated {
+		if action != importActionCreated && action != importActionUpdated && action != importActionMerged {
 			continue
 		}
 		if rec.Deps == nil {
 			continue
 		}
 
-		deps, skipRecord, err := i.resolvedDeps(run, mutator, rec)
+		deps, skipRecord, err := i.resolvedDeps(run, mutator, idx, rec)
 		if err != nil {
 			return err
 		}
@@ -224,7 +304,7 @@ func (i *Importer) applyDependencies(ctx context.Context, run *importRun, mutato
 	return nil
 }
 
-func (i *Importer) resolvedDeps(run *importRun, mutator store.ImportMutator, rec api.TaskImportRecord) ([]models.Dependency, bool, error) {
+func (i *Importer) resolvedDeps(run *importRun, mutator store.ImportMutator, idx int, rec api.TaskImportRecord) ([]models.Dependency, bool, error) {
 	if run.orphanHandling == "allow" {
 		return rec.Deps, false, nil
 	}
@@ -253,6 +333,8 @@ func (i *Importer) resolvedDeps(run *importRun, mutator store.ImportMutator, rec
 		for _, parentID := range strictOrphans {
 			run.response.Errors++
 			run.response.Messages = append(run.response.Messages, fmt.Sprintf("strict orphan dep: %s -> %s (dependencies unchanged)", rec.ID, parentID))
+			run.conflict(idx, rec.ID, models.ImportConflictOrphanDependency, fmt.Sprintf("dependency parent %s does not exist; dependencies unchanged", parentID),
+				fmt.Sprintf("import %s first, or re-import with orphan_handling=skip or allow", parentID))
 		}
 		return nil, true, nil
 	}
@@ -260,6 +342,18 @@ func (i *Importer) resolvedDeps(run *importRun, mutator store.ImportMutator, rec
 	return deps, false, nil
 }
 
+// conflict adds a structured entry for a rejected record. idx is the record's
+// position in the request; Line is 1-based.
+func (run *importRun) conflict(idx int, id string, reason models.ImportConflictReason, detail, resolution string) {
+	run.response.Conflicts = append(run.response.Conflicts, models.ImportConflict{
+		Line:       idx + 1,
+		ID:         id,
+		Reason:     reason,
+		Detail:     detail,
+		Resolution: resolution,
+	})
+}
+
 func (i *Importer) taskExists(run *importRun, mutator store.ImportMutator, id string) (bool, error) {
 	exists, ok := run.taskExistsCache[id]
 	if ok {
@@ -274,7 +368,34 @@ func (i *Importer) taskExists(run *importRun, mutator store.ImportMutator, id st
 	return exists, nil
 }
 
-func normalizeImportRecord(rec api.TaskImportRecord, project string) (api.TaskImportRecord, bool, error) {
+func resolveImportSourceLabel(requested, fallback string) (string, error) {
+	raw := strings.TrimSpace(requested)
+	if raw == "" {
+		raw = strings.TrimSpace(fallback)
+	}
+	if raw == "" {
+		return "", nil
+	}
+	label, err := normalizeLabel(raw)
+	if err != nil {
+		return "", badRequestCode(fmt.Errorf("invalid source_label: %w", err), ErrCodeInvalidLabel)
+	}
+	return label, nil
+}
+
+// withSourceLabel returns labels plus sourceLabel, deduped and sorted like normalizeLabels.
+func withSourceLabel(labels []string, sourceLabel string) []string {
+	if sourceLabel == "" {
+		return labels
+	}
+	merged, err := normalizeLabels(append(append([]string{}, labels...), sourceLabel))
+	if err != nil {
+		return labels
+	}
+	return merged
+}
+
+func normalizeImportRecord(rec api.TaskImportRecord, project string, validation *lenientValidation) (api.TaskImportRecord, bool, error) {
 	project, err := normalizePrefix(project)
 	if err != nil {
 		return rec, false, badRequestCode(fmt.Errorf("invalid project"), ErrCodeInvalidArgument)
@@ -304,21 +425,23 @@ func normalizeImportRecord(rec api.TaskImportRecord, project string) (api.TaskIm
 		return rec, false, badRequestCode(fmt.Errorf("invalid id: %s", rec.ID), ErrCodeInvalidID)
 	}
 
-	status, err := normalizeStatus(rec.Status)
+	status, err := validation.status(rec.Status, string(models.StatusOpen))
 	if err != nil {
 		return rec, false, err
 	}
 	rec.Status = status
 
-	taskType, err := normalizeType(rec.Type)
+	taskType, err := validation.taskType(rec.Type, string(models.TypeTask))
 	if err != nil {
 		return rec, false, err
 	}
 	rec.Type = taskType
 
-	if !models.IsValidPriority(rec.Priority) {
-		return rec, false, badRequestCode(fmt.Errorf("priority must be between %d and %d", models.PriorityMin, models.PriorityMax), ErrCodeInvalidPriority)
+	priority, err := validation.priority(rec.Priority)
+	if err != nil {
+		return rec, false, err
 	}
+	rec.Priority = priority
 
 	rec.ParentID = strings.TrimSpace(rec.ParentID)
 	if rec.ParentID != "" {
@@ -328,7 +451,7 @@ func normalizeImportRecord(rec api.TaskImportRecord, project string) (api.TaskIm
 	}
 
 	if rec.Labels != nil {
-		labels, err := normalizeLabels(rec.Labels)
+		labels, err := validation.labels(rec.Labels)
 		if err != nil {
 			return rec, false, err
 		}
@@ -342,9 +465,9 @@ func normalizeImportRecord(rec api.TaskImportRecord, project string) (api.TaskIm
 			if parentID == "" || !validateID(parentID) || !taskIDBelongsToProject(parentID, project) {
 				return rec, false, badRequestCode(fmt.Errorf("invalid dependency parent_id"), ErrCodeInvalidDependency)
 			}
-			depType := strings.TrimSpace(dep.Type)
-			if depType == "" {
-				depType = string(models.DependencyBlocks)
+			depType, err := normalizeDependencyType(dep.Type)
+			if err != nil {
+				return rec, false, err
 			}
 			deps = append(deps, models.Dependency{ParentID: parentID, Type: depType})
 		}
@@ -366,6 +489,23 @@ func normalizeImportRecord(rec api.TaskImportRecord, project string) (api.TaskIm
 	} else {
 		rec.ClosedAt = nil
 	}
+	if rec.Status == string(models.StatusTombstone) {
+		if rec.DeletedAt == nil || rec.DeletedAt.IsZero() {
+			deletedAt := rec.UpdatedAt
+			rec.DeletedAt = &deletedAt
+		}
+	} else {
+		rec.DeletedAt = nil
+		rec.MergedInto = ""
+	}
+	if rec.Status != string(models.StatusBlocked) {
+		rec.BlockedReason = ""
+		rec.BlockedOn = ""
+	}
 
+	rec, err = normalizeArchiveSections(rec, project)
+	if err != nil {
+		return rec, false, err
+	}
 	return rec, false, nil
 }
@@ -3,15 +3,35 @@ package server
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"grns/internal/models"
+	"grns/internal/store"
 )
 
+// parseFilterBody parses list-style filter keys sent in a JSON body.
+// Paging and include keys are rejected because the caller operates on the full match set.
+func parseFilterBody(values map[string]string) (taskListFilter, error) {
+	query := url.Values{}
+	for key, value := range values {
+		switch key {
+		case "limit", "offset", "after_id", "include", "include_total", "view":
+			return taskListFilter{}, badRequestCode(fmt.Errorf("filter does not support %s", key), ErrCodeInvalidQuery)
+		}
+		query.Set(key, value)
+	}
+	return parseListFilter(&http.Request{URL: &url.URL{RawQuery: query.Encode()}})
+}
+
 func parseListFilter(r *http.Request) (taskListFilter, error) {
+	r, err := withSearchQuery(r)
+	if err != nil {
+		return taskListFilter{}, err
+	}
 	limit, err := queryInt(r, "limit")
 	if err != nil {
 		return taskListFilter{}, err
@@ -22,18 +42,44 @@ func parseListFilter(r *http.Request) (taskListFilter, error) {
 	}
 
 	filter := taskListFilter{
-		Statuses:  splitCSV(r.URL.Query().Get("status")),
-		Types:     splitCSV(r.URL.Query().Get("type")),
-		ParentID:  strings.TrimSpace(r.URL.Query().Get("parent_id")),
-		Labels:    splitCSV(r.URL.Query().Get("label")),
-		LabelsAny: splitCSV(r.URL.Query().Get("label_any")),
-		Limit:     limit,
-		Offset:    offset,
+		Statuses:    splitCSV(r.URL.Query().Get("status")),
+		Types:       splitCSV(r.URL.Query().Get("type")),
+		ParentID:    strings.TrimSpace(r.URL.Query().Get("parent_id")),
+		MilestoneID: strings.TrimSpace(r.URL.Query().Get("milestone_id")),
+		Labels:      splitCSV(r.URL.Query().Get("label")),
+		LabelsAny:   splitCSV(r.URL.Query().Get("label_any")),
+		DepTypes:    splitCSV(r.URL.Query().Get("dep_type")),
+		Limit:       limit,
+		Offset:      offset,
 	}
 
+	// Any after_id, even empty, switches to keyset pages ordered by id.
+	if r.URL.Query().Has("after_id") {
+		filter.OrderByID = true
+		filter.AfterID = strings.TrimSpace(r.URL.Query().Get("after_id"))
+		if filter.AfterID != "" && !validateID(filter.AfterID) {
+			return taskListFilter{}, badRequestCode(fmt.Errorf("invalid after_id"), ErrCodeInvalidQuery)
+		}
+		if filter.Offset > 0 {
+			return taskListFilter{}, badRequestCode(fmt.Errorf("after_id cannot be combined with offset"), ErrCodeInvalidQuery)
+		}
+	}
+
+	sortKeys, err := parseListSort(r.URL.Query().Get("sort"), r.URL.Query().Get("order"))
+	if err != nil {
+		return taskListFilter{}, err
+	}
+	if len(sortKeys) > 0 && filter.OrderByID {
+		return taskListFilter{}, badRequestCode(fmt.Errorf("sort cannot be combined with after_id"), ErrCodeInvalidQuery)
+	}
+	filter.Sort = sortKeys
+
 	if filter.ParentID != "" && !validateID(filter.ParentID) {
 		return taskListFilter{}, badRequestCode(fmt.Errorf("invalid parent_id"), ErrCodeInvalidParentID)
 	}
+	if filter.MilestoneID != "" && !validateMilestoneID(filter.MilestoneID) {
+		return taskListFilter{}, badRequestCode(fmt.Errorf("invalid milestone_id"), ErrCodeInvalidMilestone)
+	}
 
 	if len(filter.Statuses) > 0 {
 		statuses := make([]string, 0, len(filter.Statuses))
@@ -45,6 +91,8 @@ func parseListFilter(r *http.Request) (taskListFilter, error) {
 			statuses = append(statuses, value)
 		}
 		filter.Statuses = statuses
+	} else {
+		filter.ExcludeTombstones = true
 	}
 
 	if len(filter.Types) > 0 {
@@ -74,6 +122,18 @@ func parseListFilter(r *http.Request) (taskListFilter, error) {
 		filter.LabelsAny = labels
 	}
 
+	if len(filter.DepTypes) > 0 {
+		depTypes := make([]string, 0, len(filter.DepTypes))
+		for _, depType := range filter.DepTypes {
+			value, err := normalizeDependencyType(depType)
+			if err != nil {
+				return taskListFilter{}, err
+			}
+			depTypes = append(depTypes, value)
+		}
+		filter.DepTypes = depTypes
+	}
+
 	priority, err := parsePriorityQuery(r.URL.Query().Get("priority"), "priority")
 	if err != nil {
 		return taskListFilter{}, err
@@ -159,10 +219,26 @@ func parseListFilter(r *http.Request) (taskListFilter, error) {
 	if r.URL.Query().Get("no_labels") == "true" {
 		filter.NoLabels = true
 	}
+	if err := parseGitRefsFilter(r.URL.Query().Get("git_refs"), &filter); err != nil {
+		return taskListFilter{}, err
+	}
 	if search := strings.TrimSpace(r.URL.Query().Get("search")); search != "" {
 		filter.SearchQuery = search
 	}
 
+	custom, err := parseCustomQuery(r.URL.Query())
+	if err != nil {
+		return taskListFilter{}, err
+	}
+	filter.Custom = custom
+	customExists := splitCSV(r.URL.Query().Get("custom_exists"))
+	for _, key := range customExists {
+		if !validateCustomFieldName(key) {
+			return taskListFilter{}, badRequestCode(fmt.Errorf("invalid custom_exists key %q", key), ErrCodeInvalidQuery)
+		}
+	}
+	filter.CustomExists = customExists
+
 	spec := strings.TrimSpace(r.URL.Query().Get("spec"))
 	if spec != "" {
 		pattern := "(?i)" + spec
@@ -175,6 +251,96 @@ func parseListFilter(r *http.Request) (taskListFilter, error) {
 	return filter, nil
 }
 
+// parseGitRefsFilter reads git_refs: "any", "none", or comma-separated relations.
+func parseGitRefsFilter(raw string, filter *taskListFilter) error {
+	values := splitCSV(raw)
+	for _, value := range values {
+		switch strings.ToLower(value) {
+		case "any":
+			filter.HasGitRefs = true
+		case "none":
+			filter.NoGitRefs = true
+		default:
+			relation, err := normalizeGitRelation(value)
+			if err != nil {
+				return badRequestCode(fmt.Errorf("invalid git_refs value %q", value), ErrCodeInvalidQuery)
+			}
+			filter.GitRefRelations = append(filter.GitRefRelations, relation)
+		}
+	}
+	if filter.NoGitRefs && len(values) > 1 {
+		return badRequestCode(fmt.Errorf("git_refs=none cannot be combined with other values"), ErrCodeInvalidQuery)
+	}
+	return nil
+}
+
+// parseCustomQuery collects custom.<key>=value and repeatable custom_eq=key:value
+// params into an exact-match map.
+func parseCustomQuery(query url.Values) (map[string]string, error) {
+	var custom map[string]string
+	set := func(key, value string) error {
+		if !validateCustomFieldName(key) {
+			return badRequestCode(fmt.Errorf("invalid custom field filter key %q", key), ErrCodeInvalidQuery)
+		}
+		if custom == nil {
+			custom = map[string]string{}
+		}
+		custom[key] = strings.TrimSpace(value)
+		return nil
+	}
+	for param, values := range query {
+		key, ok := strings.CutPrefix(param, "custom.")
+		if !ok {
+			continue
+		}
+		if err := set(key, values[0]); err != nil {
+			return nil, err
+		}
+	}
+	for _, pair := range query["custom_eq"] {
+		key, value, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, badRequestCode(fmt.Errorf("custom_eq must be key:value"), ErrCodeInvalidQuery)
+		}
+		if err := set(strings.TrimSpace(key), value); err != nil {
+			return nil, err
+		}
+	}
+	return custom, nil
+}
+
+// parseListSort parses comma-separated sort fields. A leading "-" sorts that field
+// descending; other fields use order (default asc). Without sort, order applies to
+// the default updated_at ordering.
+func parseListSort(rawSort, rawOrder string) ([]store.SortKey, error) {
+	order := strings.ToLower(strings.TrimSpace(rawOrder))
+	if order != "" && order != "asc" && order != "desc" {
+		return nil, badRequestCode(fmt.Errorf("order must be asc or desc"), ErrCodeInvalidQuery)
+	}
+	fields := splitCSV(rawSort)
+	if len(fields) == 0 {
+		if order == "" {
+			return nil, nil
+		}
+		return []store.SortKey{{Field: "updated_at", Desc: order == "desc"}}, nil
+	}
+
+	keys := make([]store.SortKey, 0, len(fields))
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		desc := order == "desc"
+		if strings.HasPrefix(field, "-") {
+			field = strings.TrimPrefix(field, "-")
+			desc = true
+		}
+		if _, ok := store.SortFields[field]; !ok {
+			return nil, badRequestCode(fmt.Errorf("invalid sort field: %s", field), ErrCodeInvalidQuery)
+		}
+		keys = append(keys, store.SortKey{Field: field, Desc: desc})
+	}
+	return keys, nil
+}
+
 func parsePriorityQuery(raw, key string) (*int, error) {
 	if raw == "" {
 		return nil, nil
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	unixSocketScheme = "unix://"
+	unixSocketMode   = 0o600
+)
+
+// unixSocketPath returns the socket path for a "unix://" address.
+func unixSocketPath(addr string) (string, bool) {
+	if !strings.HasPrefix(addr, unixSocketScheme) {
+		return "", false
+	}
+	return strings.TrimPrefix(addr, unixSocketScheme), true
+}
+
+// listen opens a TCP listener for host:port addresses and a unix socket for
+// "unix://" addresses. The socket is owner-only; filesystem permissions are
+// its access control.
+func listen(addr string) (net.Listener, error) {
+	path, ok := unixSocketPath(addr)
+	if !ok {
+		if addr == "" {
+			addr = ":http"
+		}
+		return net.Listen("tcp", addr)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("unix socket path is required")
+	}
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, unixSocketMode); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// removeStaleSocket deletes a socket file left behind by a server that did
+// not shut down cleanly. It refuses to touch regular files or a socket
+// another server is still accepting on.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is already in use", path)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+)
+
+func TestListenUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "grns-sock")
+	if err != nil {
+		t.Fatalf("temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "grns.sock")
+	addr := "unix://" + socketPath
+
+	listener, err := listen(addr)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatalf("stat socket: %v", err)
+	}
+	if info.Mode().Perm() != unixSocketMode {
+		t.Fatalf("expected socket mode %o, got %o", unixSocketMode, info.Mode().Perm())
+	}
+
+	if _, err := listen(addr); err == nil || !strings.Contains(err.Error(), "already in use") {
+		t.Fatalf("expected in-use error for live socket, got %v", err)
+	}
+
+	// Simulate a crashed server: the socket file stays behind with no listener.
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+	listener, err = listen(addr)
+	if err != nil {
+		t.Fatalf("listen over stale socket: %v", err)
+	}
+	listener.Close()
+
+	regular := filepath.Join(dir, "not-a-socket")
+	if err := os.WriteFile(regular, []byte("x"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if _, err := listen("unix://" + regular); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Fatalf("expected refusal to replace regular file, got %v", err)
+	}
+}
+
+func TestListenAddrKeepsUnixURL(t *testing.T) {
+	got, err := ListenAddr("unix:///run/grns.sock")
+	if err != nil {
+		t.Fatalf("ListenAddr: %v", err)
+	}
+	if got != "unix:///run/grns.sock" {
+		t.Fatalf("expected unix URL unchanged, got %q", got)
+	}
+}
+
+func TestServeShutsDownOnCancel(t *testing.T) {
+	srv := newListTestServer(t)
+	dir, err := os.MkdirTemp("", "grns-sock")
+	if err != nil {
+		t.Fatalf("temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "grns.sock")
+	srv.addr = "unix://" + socketPath
+
+	listener, err := srv.Listen()
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, listener) }()
+
+	client := api.NewClient(srv.addr)
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("serve: %v", err)
+		}
+	case <-time.After(shutdownTimeout):
+		t.Fatal("server did not shut down")
+	}
+	if _, err := os.Stat(socketPath); !os.IsNotExist(err) {
+		t.Fatalf("expected socket removed after shutdown, got %v", err)
+	}
+}
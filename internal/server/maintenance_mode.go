@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"grns/internal/api"
+)
+
+const (
+	maintenanceModeReject = "reject"
+	maintenanceModeQueue  = "queue"
+
+	defaultMaintenanceRetryAfter   = 30 * time.Second
+	defaultMaintenanceQueueTimeout = 5 * time.Second
+	maxMaintenanceRetryAfter       = time.Hour
+	maxMaintenanceQueueTimeout     = 2 * time.Minute
+)
+
+// maintenanceMode gates writes while an operator drains the server for a
+// backup or migration. In reject mode writes fail at once with 503 and
+// Retry-After; in queue mode they wait up to queueTimeout for the mode to be
+// lifted before failing the same way.
+type maintenanceMode struct {
+	mu           sync.Mutex
+	enabled      bool
+	mode         string
+	reason       string
+	retryAfter   time.Duration
+	queueTimeout time.Duration
+	since        time.Time
+	queued       int
+	// lifted is closed when the mode is disabled, releasing queued writes.
+	lifted chan struct{}
+}
+
+// errMaintenanceMode reports a write refused by maintenance mode.
+type errMaintenanceMode struct {
+	reason     string
+	retryAfter time.Duration
+}
+
+func (e *errMaintenanceMode) Error() string {
+	if e.reason == "" {
+		return "server is in maintenance mode; writes are paused"
+	}
+	return fmt.Sprintf("server is in maintenance mode (%s); writes are paused", e.reason)
+}
+
+// set applies req and returns the resulting state.
+func (m *maintenanceMode) set(req api.MaintenanceModeRequest, now time.Time) (api.MaintenanceModeResponse, error) {
+	if !req.Enabled {
+		m.mu.Lock()
+		if m.enabled {
+			m.enabled = false
+			close(m.lifted)
+		}
+		m.mu.Unlock()
+		return m.status(), nil
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(req.Mode))
+	switch mode {
+	case "":
+		mode = maintenanceModeReject
+	case maintenanceModeReject, maintenanceModeQueue:
+	default:
+		return api.MaintenanceModeResponse{}, badRequestCode(fmt.Errorf("mode must be %s or %s", maintenanceModeReject, maintenanceModeQueue), ErrCodeInvalidArgument)
+	}
+	retryAfter, err := maintenanceSeconds("retry_after_seconds", req.RetryAfterSeconds, defaultMaintenanceRetryAfter, maxMaintenanceRetryAfter)
+	if err != nil {
+		return api.MaintenanceModeResponse{}, err
+	}
+	queueTimeout, err := maintenanceSeconds("queue_timeout_seconds", req.QueueTimeoutSeconds, defaultMaintenanceQueueTimeout, maxMaintenanceQueueTimeout)
+	if err != nil {
+		return api.MaintenanceModeResponse{}, err
+	}
+
+	m.mu.Lock()
+	if !m.enabled {
+		m.enabled = true
+		m.since = now
+		m.lifted = make(chan struct{})
+	}
+	m.mode = mode
+	m.reason = strings.TrimSpace(req.Reason)
+	m.retryAfter = retryAfter
+	m.queueTimeout = queueTimeout
+	m.mu.Unlock()
+	return m.status(), nil
+}
+
+func maintenanceSeconds(field string, seconds int, fallback, max time.Duration) (time.Duration, error) {
+	if seconds < 0 {
+		return 0, badRequestCode(fmt.Errorf("%s must be >= 0", field), ErrCodeInvalidArgument)
+	}
+	if seconds == 0 {
+		return fallback, nil
+	}
+	value := time.Duration(seconds) * time.Second
+	if value > max {
+		return 0, badRequestCode(fmt.Errorf("%s must be <= %d", field, int(max.Seconds())), ErrCodeInvalidArgument)
+	}
+	return value, nil
+}
+
+func (m *maintenanceMode) active() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.enabled
+}
+
+func (m *maintenanceMode) status() api.MaintenanceModeResponse {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.enabled {
+		return api.MaintenanceModeResponse{Queued: m.queued}
+	}
+	since := m.since
+	return api.MaintenanceModeResponse{
+		Enabled:             true,
+		Mode:                m.mode,
+		Reason:              m.reason,
+		RetryAfterSeconds:   int(m.retryAfter.Seconds()),
+		QueueTimeoutSeconds: int(m.queueTimeout.Seconds()),
+		Since:               &since,
+		Queued:              m.queued,
+	}
+}
+
+// admit returns nil once a write may proceed. In queue mode it blocks until
+// the mode is lifted, the queue timeout passes, or ctx ends.
+func (m *maintenanceMode) admit(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.enabled {
+		m.mu.Unlock()
+		return nil
+	}
+	refused := &errMaintenanceMode{reason: m.reason, retryAfter: m.retryAfter}
+	if m.mode != maintenanceModeQueue {
+		m.mu.Unlock()
+		return refused
+	}
+	lifted := m.lifted
+	timeout := m.queueTimeout
+	m.queued++
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.queued--
+		m.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-lifted:
+		return nil
+	case <-timer.C:
+		return refused
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isMaintenanceGatedRequest reports whether r is a write that maintenance mode
+// holds back. Admin and auth endpoints stay open so operators can act.
+func isMaintenanceGatedRequest(r *http.Request) bool {
+	if !isUnsafeMethod(r.Method) || !strings.HasPrefix(r.URL.Path, "/v1/") {
+		return false
+	}
+	return !strings.HasPrefix(r.URL.Path, "/v1/admin/") && !strings.HasPrefix(r.URL.Path, "/v1/auth/")
+}
+
+func (s *Server) withMaintenanceMode(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isMaintenanceGatedRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err := s.maintenanceMode.admit(r.Context()); err != nil {
+			s.writeMaintenanceError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeMaintenanceError writes 503 with Retry-After for a refused write. It
+// bypasses writeErrorReq, which hides 5xx messages as internal errors.
+func (s *Server) writeMaintenanceError(w http.ResponseWriter, r *http.Request, err error) {
+	refused, ok := err.(*errMaintenanceMode)
+	if !ok {
+		s.log().Debug("queued write abandoned", "method", r.Method, "path", r.URL.Path, "error", err)
+		return
+	}
+	s.log().Warn("write rejected by maintenance mode", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+	w.Header().Set("Retry-After", strconv.Itoa(int(refused.retryAfter.Seconds())))
+	s.writeJSON(w, http.StatusServiceUnavailable, api.ErrorResponse{
+		Error:     refused.Error(),
+		Code:      "maintenance_mode",
+		ErrorCode: ErrCodeMaintenanceMode,
+	})
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+)
+
+func setMaintenanceMode(t *testing.T, srv *Server, body string) api.MaintenanceModeResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/maintenance-mode", bytes.NewReader([]byte(body)))
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("set maintenance mode: %d (%s)", w.Code, w.Body.String())
+	}
+	var resp api.MaintenanceModeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp
+}
+
+func createDuringMaintenance(srv *Server, id string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.TaskCreateRequest{ID: id, Title: "write"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects/gr/tasks", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	return w
+}
+
+func TestMaintenanceModeRejectsWrites(t *testing.T) {
+	srv := newListTestServer(t)
+
+	resp := setMaintenanceMode(t, srv, `{"enabled":true,"reason":"backup","retry_after_seconds":12}`)
+	if !resp.Enabled || resp.Mode != maintenanceModeReject || resp.RetryAfterSeconds != 12 || resp.Since == nil {
+		t.Fatalf("unexpected state: %+v", resp)
+	}
+
+	w := createDuringMaintenance(srv, "gr-mm01")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d (%s)", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Retry-After"); got != "12" {
+		t.Fatalf("expected Retry-After 12, got %q", got)
+	}
+	var errResp api.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if errResp.ErrorCode != ErrCodeMaintenanceMode || errResp.Code != "maintenance_mode" {
+		t.Fatalf("unexpected error response: %+v", errResp)
+	}
+
+	getReq := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks", nil)
+	getW := httptest.NewRecorder()
+	srv.routes().ServeHTTP(getW, getReq)
+	if getW.Code != http.StatusOK {
+		t.Fatalf("expected reads to stay open, got %d", getW.Code)
+	}
+	if !srv.capabilities().ReadOnly {
+		t.Fatal("expected capabilities to report read_only")
+	}
+
+	if resp := setMaintenanceMode(t, srv, `{"enabled":false}`); resp.Enabled {
+		t.Fatalf("expected mode lifted, got %+v", resp)
+	}
+	if w := createDuringMaintenance(srv, "gr-mm01"); w.Code != http.StatusCreated {
+		t.Fatalf("expected write after lift, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestMaintenanceModeQueuesWritesUntilLifted(t *testing.T) {
+	srv := newListTestServer(t)
+	setMaintenanceMode(t, srv, `{"enabled":true,"mode":"queue","queue_timeout_seconds":30}`)
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() { done <- createDuringMaintenance(srv, "gr-mm02") }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.maintenanceMode.status().Queued == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("write was not queued")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	setMaintenanceMode(t, srv, `{"enabled":false}`)
+	select {
+	case w := <-done:
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected queued write to succeed, got %d (%s)", w.Code, w.Body.String())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued write was not released")
+	}
+}
+
+func TestMaintenanceModeRejectsInvalidMode(t *testing.T) {
+	srv := newListTestServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/maintenance-mode", bytes.NewReader([]byte(`{"enabled":true,"mode":"drain"}`)))
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d (%s)", w.Code, w.Body.String())
+	}
+}
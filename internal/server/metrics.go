@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"grns/internal/metrics"
+	"grns/internal/store"
+)
+
+// serverMetrics holds the Prometheus families exposed at /metrics.
+type serverMetrics struct {
+	registry        *metrics.Registry
+	requests        *metrics.CounterVec
+	requestDuration *metrics.HistogramVec
+	dbQueryDuration *metrics.HistogramVec
+	tasks           *metrics.GaugeVec
+	importRecords   *metrics.HistogramVec
+	exportRecords   *metrics.HistogramVec
+	blobBytes       *metrics.GaugeVec
+}
+
+func newServerMetrics() *serverMetrics {
+	reg := metrics.NewRegistry()
+	return &serverMetrics{
+		registry:        reg,
+		requests:        reg.NewCounterVec("grns_http_requests_total", "HTTP requests served, by method, route pattern, and status code.", "method", "route", "status"),
+		requestDuration: reg.NewHistogramVec("grns_http_request_duration_seconds", "HTTP request latency, by method and route pattern.", metrics.DefaultDurationBuckets, "method", "route"),
+		dbQueryDuration: reg.NewHistogramVec("grns_db_query_duration_seconds", "SQLite statement latency, by operation.", metrics.DefaultDurationBuckets, "op"),
+		tasks:           reg.NewGaugeVec("grns_tasks", "Tasks in the database, by status.", "status"),
+		importRecords:   reg.NewHistogramVec("grns_import_records", "Records received per import request.", metrics.DefaultSizeBuckets, "mode"),
+		exportRecords:   reg.NewHistogramVec("grns_export_records", "Records written per export request.", metrics.DefaultSizeBuckets),
+		blobBytes:       reg.NewGaugeVec("grns_blob_storage_bytes", "Total size of stored attachment blobs."),
+	}
+}
+
+func (m *serverMetrics) observeQuery(op string, duration time.Duration) {
+	m.dbQueryDuration.Observe(duration.Seconds(), op)
+}
+
+// withMetrics records request counts and latency keyed by the matched route
+// pattern. It must wrap the mux directly so r.Pattern is visible afterwards.
+func (s *Server) withMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &loggingResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(rw, r)
+
+		route := routeLabel(r.Pattern)
+		s.metrics.requests.Inc(r.Method, route, strconv.Itoa(rw.Status()))
+		s.metrics.requestDuration.Observe(time.Since(start).Seconds(), r.Method, route)
+	})
+}
+
+// routeLabel strips the method from a mux pattern; unmatched requests share
+// one label so arbitrary paths cannot inflate cardinality.
+func routeLabel(pattern string) string {
+	if pattern == "" {
+		return "unmatched"
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+// refreshStoreMetrics samples gauges that are derived from the database at
+// scrape time.
+func (s *Server) refreshStoreMetrics(ctx context.Context) error {
+	info, err := s.store.StoreInfo(ctx, "")
+	if err != nil {
+		return err
+	}
+	s.metrics.tasks.Reset()
+	for status, count := range info.TaskCounts {
+		s.metrics.tasks.Set(float64(count), status)
+	}
+
+	metricsStore, ok := any(s.store).(store.MetricsStore)
+	if !ok {
+		return nil
+	}
+	blobBytes, err := metricsStore.BlobStorageBytes(ctx)
+	if err != nil {
+		return err
+	}
+	s.metrics.blobBytes.Set(float64(blobBytes))
+	return nil
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+// MilestoneService manages time-boxed milestones (releases, sprints) per project.
+type MilestoneService struct {
+	store         store.MilestoneStore
+	projectPrefix string
+}
+
+// NewMilestoneService constructs a MilestoneService.
+func NewMilestoneService(milestoneStore store.MilestoneStore, projectPrefix string) *MilestoneService {
+	return &MilestoneService{store: milestoneStore, projectPrefix: projectPrefix}
+}
+
+// Create stores a new milestone with a generated id.
+func (s *MilestoneService) Create(ctx context.Context, req api.MilestoneCreateRequest) (models.Milestone, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return models.Milestone{}, err
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return models.Milestone{}, badRequestCode(fmt.Errorf("title is required"), ErrCodeMissingRequired)
+	}
+	dueDate, err := normalizeMilestoneDueDate(req.DueDate)
+	if err != nil {
+		return models.Milestone{}, err
+	}
+	state := string(models.MilestoneOpen)
+	if strings.TrimSpace(req.State) != "" {
+		state, err = normalizeMilestoneState(req.State)
+		if err != nil {
+			return models.Milestone{}, err
+		}
+	}
+
+	id, err := store.GenerateMilestoneID(func(id string) (bool, error) {
+		return s.store.MilestoneIDExists(ctx, id)
+	})
+	if err != nil {
+		return models.Milestone{}, err
+	}
+
+	now := time.Now().UTC()
+	milestone := models.Milestone{
+		Project:   project,
+		ID:        id,
+		Title:     title,
+		DueDate:   dueDate,
+		State:     state,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.CreateMilestone(ctx, &milestone); err != nil {
+		return models.Milestone{}, err
+	}
+	return milestone, nil
+}
+
+// List returns the project's milestones, optionally restricted to one state.
+func (s *MilestoneService) List(ctx context.Context, state string) ([]models.Milestone, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(state) != "" {
+		state, err = normalizeMilestoneState(state)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.store.ListMilestones(ctx, project, state)
+}
+
+// Get returns one milestone with burndown counts over its tasks.
+func (s *MilestoneService) Get(ctx context.Context, id string) (api.MilestoneResponse, error) {
+	project, id, err := s.scope(ctx, id)
+	if err != nil {
+		return api.MilestoneResponse{}, err
+	}
+	milestone, err := s.store.GetMilestone(ctx, project, id)
+	if err != nil {
+		return api.MilestoneResponse{}, err
+	}
+	if milestone == nil {
+		return api.MilestoneResponse{}, notFoundCode(fmt.Errorf("milestone not found"), ErrCodeMilestoneNotFound)
+	}
+	counts, err := s.store.MilestoneStatusCounts(ctx, project, id)
+	if err != nil {
+		return api.MilestoneResponse{}, err
+	}
+	return api.MilestoneResponse{Milestone: *milestone, Progress: milestoneProgress(counts)}, nil
+}
+
+// Update applies a partial update and returns the milestone with burndown counts.
+func (s *MilestoneService) Update(ctx context.Context, id string, req api.MilestoneUpdateRequest) (api.MilestoneResponse, error) {
+	project, id, err := s.scope(ctx, id)
+	if err != nil {
+		return api.MilestoneResponse{}, err
+	}
+	update := store.MilestoneUpdate{UpdatedAt: time.Now().UTC()}
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return api.MilestoneResponse{}, badRequestCode(fmt.Errorf("title cannot be empty"), ErrCodeMissingRequired)
+		}
+		update.Title = &title
+	}
+	if req.DueDate != nil {
+		dueDate, err := normalizeMilestoneDueDate(*req.DueDate)
+		if err != nil {
+			return api.MilestoneResponse{}, err
+		}
+		update.DueDate = &dueDate
+	}
+	if req.State != nil {
+		state, err := normalizeMilestoneState(*req.State)
+		if err != nil {
+			return api.MilestoneResponse{}, err
+		}
+		update.State = &state
+	}
+
+	found, err := s.store.UpdateMilestone(ctx, project, id, update)
+	if err != nil {
+		return api.MilestoneResponse{}, err
+	}
+	if !found {
+		return api.MilestoneResponse{}, notFoundCode(fmt.Errorf("milestone not found"), ErrCodeMilestoneNotFound)
+	}
+	return s.Get(ctx, id)
+}
+
+// Delete removes one milestone; its tasks keep existing without a milestone.
+func (s *MilestoneService) Delete(ctx context.Context, id string) error {
+	project, id, err := s.scope(ctx, id)
+	if err != nil {
+		return err
+	}
+	found, err := s.store.DeleteMilestone(ctx, project, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notFoundCode(fmt.Errorf("milestone not found"), ErrCodeMilestoneNotFound)
+	}
+	return nil
+}
+
+func (s *MilestoneService) scope(ctx context.Context, id string) (string, string, error) {
+	id = strings.TrimSpace(id)
+	if !validateMilestoneID(id) {
+		return "", "", badRequestCode(fmt.Errorf("invalid milestone id"), ErrCodeInvalidMilestone)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return project, id, nil
+}
+
+func (s *MilestoneService) project(ctx context.Context) (string, error) {
+	if project, ok := projectFromContext(ctx); ok {
+		return project, nil
+	}
+	return normalizePrefix(s.projectPrefix)
+}
+
+func milestoneProgress(counts map[string]int) *api.MilestoneProgress {
+	progress := &api.MilestoneProgress{ByStatus: counts}
+	for status, count := range counts {
+		if status == string(models.StatusTombstone) {
+			continue
+		}
+		progress.Total += count
+		if status == string(models.StatusClosed) {
+			progress.Closed += count
+		}
+	}
+	progress.Remaining = progress.Total - progress.Closed
+	return progress
+}
+
+func normalizeMilestoneState(value string) (string, error) {
+	state, err := models.ParseMilestoneState(value)
+	if err != nil {
+		return "", badRequestCode(err, ErrCodeInvalidArgument)
+	}
+	return string(state), nil
+}
+
+func normalizeMilestoneDueDate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	if _, err := time.Parse(models.MilestoneDueDateLayout, value); err != nil {
+		return "", badRequestCode(fmt.Errorf("due_date must be YYYY-MM-DD"), ErrCodeInvalidArgument)
+	}
+	return value, nil
+}
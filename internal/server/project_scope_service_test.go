@@ -25,8 +25,14 @@ func TestTaskService_ProjectContextValidation(t *testing.T) {
 		t.Fatalf("expected 404 for cross-project get, got status=%d err=%v", httpStatusFromError(err), err)
 	}
 
-	if err := svc.AddDependency(ctxGR, "gr-p511", "xy-p511", "blocks"); httpStatusFromError(err) != 400 {
-		t.Fatalf("expected 400 for cross-project dependency, got status=%d err=%v", httpStatusFromError(err), err)
+	if err := svc.AddDependency(ctxGR, "xy-p511", "gr-p511", "blocks"); httpStatusFromError(err) != 400 {
+		t.Fatalf("expected 400 for child outside the request project, got status=%d err=%v", httpStatusFromError(err), err)
+	}
+	if err := svc.AddDependency(ctxGR, "gr-p511", "xy-p511", "blocks"); err != nil {
+		t.Fatalf("expected cross-project parent to be accepted: %v", err)
+	}
+	if err := svc.AddDependency(ctxGR, "gr-p511", "xy-p599", "blocks"); httpStatusFromError(err) != 404 {
+		t.Fatalf("expected 404 for missing cross-project parent, got status=%d err=%v", httpStatusFromError(err), err)
 	}
 
 	if _, err := svc.Create(ctxXY, api.TaskCreateRequest{ID: "gr-p512", Title: "wrong prefix"}); httpStatusFromError(err) != 400 {
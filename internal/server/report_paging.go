@@ -0,0 +1,31 @@
+package server
+
+import "net/http"
+
+// reportPage is the resolved limit/offset window for a report endpoint.
+type reportPage struct {
+	Limit  int
+	Offset int
+}
+
+// parseReportPage resolves limit/offset query params for report endpoints.
+// A missing or zero limit uses the configured default; larger limits are clamped to the max.
+// Report queries must apply a stable sort so pages do not overlap.
+func (s *Server) parseReportPage(r *http.Request) (reportPage, error) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		return reportPage{}, err
+	}
+	offset, err := queryInt(r, "offset")
+	if err != nil {
+		return reportPage{}, err
+	}
+
+	if limit == 0 {
+		limit = s.reportDefaultLimit
+	}
+	if s.reportMaxLimit > 0 && limit > s.reportMaxLimit {
+		limit = s.reportMaxLimit
+	}
+	return reportPage{Limit: limit, Offset: offset}, nil
+}
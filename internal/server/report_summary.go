@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+// maxReportSummaryTasks caps how many tasks one summary report may render.
+const maxReportSummaryTasks = 2000
+
+const (
+	reportGroupByStatus   = "status"
+	reportGroupByEpic     = "epic"
+	reportGroupByAssignee = "assignee"
+)
+
+// defaultReportMarkdownTemplate renders a summary report as Markdown. It is
+// executed with an api.ReportSummaryResponse; taskLink renders a task ID,
+// linked when the task has a URL.
+const defaultReportMarkdownTemplate = `# {{.Project}} backlog
+
+{{.Total}} tasks by {{.GroupBy}}, generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}.
+{{range .Groups}}
+## {{.Title}} ({{.Count}})
+
+{{range .Tasks}}- {{taskLink .}} [P{{.Priority}}] [{{.Status}}] {{.Title}}{{if .Assignee}} (@{{.Assignee}}){{end}}
+{{end}}{{end}}`
+
+var reportTemplateFuncs = template.FuncMap{
+	"taskLink": func(task api.ReportTask) string {
+		if task.URL == "" {
+			return task.ID
+		}
+		return "[" + task.ID + "](" + task.URL + ")"
+	},
+}
+
+// parseReportTemplate parses a Markdown report template, falling back to the built-in one.
+func parseReportTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = defaultReportMarkdownTemplate
+	}
+	return template.New("report").Funcs(reportTemplateFuncs).Parse(text)
+}
+
+func normalizeReportGroupBy(value string) (string, error) {
+	switch groupBy := strings.ToLower(strings.TrimSpace(value)); groupBy {
+	case "":
+		return reportGroupByStatus, nil
+	case reportGroupByStatus, reportGroupByEpic, reportGroupByAssignee:
+		return groupBy, nil
+	default:
+		return "", badRequestCode(fmt.Errorf("group_by must be status, epic, or assignee"), ErrCodeInvalidQuery)
+	}
+}
+
+// ReportSummary groups the tasks matching filter by status, parent epic, or
+// assignee. Groups and the tasks within them are sorted for stable output.
+func (s *TaskService) ReportSummary(ctx context.Context, filter taskListFilter, groupBy string) (api.ReportSummaryResponse, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return api.ReportSummaryResponse{}, err
+	}
+	filter.Project = project
+	tasks, err := s.listTasksCapped(ctx, filter, maxReportSummaryTasks)
+	if err != nil {
+		return api.ReportSummaryResponse{}, err
+	}
+
+	keyOf, titleOf, order, err := s.reportGrouping(ctx, project, tasks, groupBy)
+	if err != nil {
+		return api.ReportSummaryResponse{}, err
+	}
+
+	byKey := map[string]*api.ReportGroup{}
+	for _, task := range tasks {
+		key := keyOf(task)
+		group, ok := byKey[key]
+		if !ok {
+			group = &api.ReportGroup{Key: key, Title: titleOf(key)}
+			byKey[key] = group
+		}
+		group.Tasks = append(group.Tasks, api.ReportTask{
+			ID:       task.ID,
+			Title:    task.Title,
+			Status:   task.Status,
+			Type:     task.Type,
+			Priority: task.Priority,
+			Assignee: task.Assignee,
+		})
+	}
+
+	groups := make([]api.ReportGroup, 0, len(byKey))
+	for _, group := range byKey {
+		sort.Slice(group.Tasks, func(i, j int) bool {
+			if group.Tasks[i].Priority != group.Tasks[j].Priority {
+				return group.Tasks[i].Priority < group.Tasks[j].Priority
+			}
+			return group.Tasks[i].ID < group.Tasks[j].ID
+		})
+		group.Count = len(group.Tasks)
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return order(groups[i].Key, groups[j].Key) })
+
+	return api.ReportSummaryResponse{
+		Project:     project,
+		GroupBy:     groupBy,
+		GeneratedAt: time.Now().UTC(),
+		Total:       len(tasks),
+		Groups:      groups,
+	}, nil
+}
+
+// reportGrouping returns the group key and title functions and the group
+// ordering for groupBy. Ungrouped tasks use an empty key, which sorts last.
+func (s *TaskService) reportGrouping(ctx context.Context, project string, tasks []models.Task, groupBy string) (func(models.Task) string, func(string) string, func(a, b string) bool, error) {
+	emptyLast := func(a, b string) bool {
+		if a == "" || b == "" {
+			return b == ""
+		}
+		return a < b
+	}
+
+	switch groupBy {
+	case reportGroupByAssignee:
+		keyOf := func(task models.Task) string { return task.Assignee }
+		titleOf := func(key string) string {
+			if key == "" {
+				return "Unassigned"
+			}
+			return key
+		}
+		return keyOf, titleOf, emptyLast, nil
+	case reportGroupByEpic:
+		epics, err := s.reportParentEpics(ctx, project, tasks)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		keyOf := func(task models.Task) string {
+			if _, ok := epics[task.ParentID]; ok {
+				return task.ParentID
+			}
+			return ""
+		}
+		titleOf := func(key string) string {
+			if key == "" {
+				return "No epic"
+			}
+			return key + " " + epics[key]
+		}
+		return keyOf, titleOf, emptyLast, nil
+	default:
+		rank := map[string]int{}
+		for i, status := range models.TaskStatusStrings() {
+			rank[status] = i
+		}
+		keyOf := func(task models.Task) string { return task.Status }
+		titleOf := func(key string) string { return key }
+		order := func(a, b string) bool { return rank[a] < rank[b] }
+		return keyOf, titleOf, order, nil
+	}
+}
+
+// reportParentEpics maps the IDs of the epic parents of tasks to their titles.
+func (s *TaskService) reportParentEpics(ctx context.Context, project string, tasks []models.Task) (map[string]string, error) {
+	parentIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if task.ParentID != "" {
+			parentIDs = append(parentIDs, task.ParentID)
+		}
+	}
+	epics := map[string]string{}
+	if len(parentIDs) == 0 {
+		return epics, nil
+	}
+	parents, err := s.store.ListTasks(ctx, taskListFilter{Project: project, IDs: uniqueStrings(parentIDs)}.toStoreListFilter())
+	if err != nil {
+		return nil, err
+	}
+	for _, parent := range parents {
+		if parent.Type == string(models.TypeEpic) {
+			epics[parent.ID] = parent.Title
+		}
+	}
+	return epics, nil
+}
+
+// applyReportTaskURLs fills each task URL from a template containing {id}.
+func applyReportTaskURLs(report *api.ReportSummaryResponse, taskURL string) {
+	if taskURL == "" {
+		return
+	}
+	for i := range report.Groups {
+		for j := range report.Groups[i].Tasks {
+			task := &report.Groups[i].Tasks[j]
+			task.URL = strings.ReplaceAll(taskURL, "{id}", task.ID)
+		}
+	}
+}
+
+func writeReportMarkdown(w io.Writer, tmpl *template.Template, report api.ReportSummaryResponse) error {
+	return tmpl.Execute(w, report)
+}
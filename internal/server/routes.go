@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 func (s *Server) routes() http.Handler {
@@ -14,7 +15,14 @@ func (s *Server) routes() http.Handler {
 
 	// Health check and info.
 	mux.HandleFunc("GET /health", s.handleHealth)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
 	mux.HandleFunc("GET /v1/info", s.handleInfo)
+	mux.HandleFunc("GET /v1/projects/{project}/info", s.handleProjectInfo)
+	mux.HandleFunc("GET /v1/stats", s.handleStats)
+	mux.HandleFunc("GET /v1/projects/{project}/stats", s.handleProjectStats)
+	mux.HandleFunc("GET /v1/stats/flow", s.handleStatsFlow)
+	mux.HandleFunc("GET /v1/projects/{project}/stats/flow", s.handleProjectStatsFlow)
+	mux.HandleFunc("GET /v1/capabilities", s.handleCapabilities)
 
 	// Authentication.
 	mux.HandleFunc("POST /v1/auth/login", s.handleAuthLogin)
@@ -29,60 +37,139 @@ func (s *Server) routes() http.Handler {
 	mux.HandleFunc("POST /v1/projects/{project}/tasks/get", s.handleGetTasks)
 	mux.HandleFunc("POST /v1/projects/{project}/tasks/batch", s.handleBatchCreate)
 	mux.HandleFunc("POST /v1/projects/{project}/tasks/close", s.handleClose)
+	mux.HandleFunc("POST /v1/projects/{project}/tasks/close-by-filter", s.handleCloseByFilter)
 	mux.HandleFunc("POST /v1/projects/{project}/tasks/reopen", s.handleReopen)
+	mux.HandleFunc("POST /v1/projects/{project}/tasks/restore", s.handleRestore)
+	mux.HandleFunc("POST /v1/projects/{project}/tasks/touch", s.handleTouch)
+	mux.HandleFunc("POST /v1/projects/{project}/tasks/bulk-update", s.handleBulkUpdate)
+	mux.HandleFunc("POST /v1/projects/{project}/rpc", s.handleRPC)
 
 	// Project-scoped task queries.
 	mux.HandleFunc("GET /v1/projects/{project}/tasks/ready", s.handleReady)
 	mux.HandleFunc("GET /v1/projects/{project}/tasks/stale", s.handleStale)
+	mux.HandleFunc("POST /v1/projects/{project}/tasks/next/claim", s.handleClaimTask)
+
+	// Project-scoped reports.
+	mux.HandleFunc("GET /v1/projects/{project}/reports/summary", s.handleReportSummary)
 
 	// Project-scoped single task.
 	mux.HandleFunc("GET /v1/projects/{project}/tasks/{id}", s.handleGetTask)
 	mux.HandleFunc("PATCH /v1/projects/{project}/tasks/{id}", s.handleUpdateTask)
+	mux.HandleFunc("PUT /v1/projects/{project}/tasks/{id}", s.handleUpsertTask)
+	mux.HandleFunc("DELETE /v1/projects/{project}/tasks/{id}", s.handleDeleteTask)
+	mux.HandleFunc("POST /v1/projects/{project}/tasks/{id}/merge", s.handleMergeTask)
+	mux.HandleFunc("GET /v1/projects/{project}/tasks/{id}/history", s.handleTaskHistory)
 
 	// Project-scoped task labels.
 	mux.HandleFunc("GET /v1/projects/{project}/tasks/{id}/labels", s.handleListTaskLabels)
 	mux.HandleFunc("POST /v1/projects/{project}/tasks/{id}/labels", s.handleAddTaskLabels)
 	mux.HandleFunc("DELETE /v1/projects/{project}/tasks/{id}/labels", s.handleRemoveTaskLabels)
 
+	// Project-scoped task comments.
+	mux.HandleFunc("POST /v1/projects/{project}/tasks/{id}/comments", s.handleCreateTaskComment)
+	mux.HandleFunc("GET /v1/projects/{project}/tasks/{id}/comments", s.handleListTaskComments)
+
+	// Project-scoped task worklogs.
+	mux.HandleFunc("POST /v1/projects/{project}/tasks/{id}/worklogs", s.handleCreateTaskWorklog)
+	mux.HandleFunc("GET /v1/projects/{project}/tasks/{id}/worklogs", s.handleListTaskWorklogs)
+
+	// Project-scoped task watchers.
+	mux.HandleFunc("GET /v1/projects/{project}/tasks/{id}/watchers", s.handleListTaskWatchers)
+	mux.HandleFunc("PUT /v1/projects/{project}/tasks/{id}/watchers/{user}", s.handleWatchTask)
+	mux.HandleFunc("DELETE /v1/projects/{project}/tasks/{id}/watchers/{user}", s.handleUnwatchTask)
+
+	// Project-scoped saved filters.
+	mux.HandleFunc("POST /v1/projects/{project}/filters", s.handleCreateSavedFilter)
+	mux.HandleFunc("GET /v1/projects/{project}/filters", s.handleListSavedFilters)
+	mux.HandleFunc("GET /v1/projects/{project}/filters/{name}", s.handleGetSavedFilter)
+	mux.HandleFunc("PUT /v1/projects/{project}/filters/{name}", s.handleReplaceSavedFilter)
+	mux.HandleFunc("DELETE /v1/projects/{project}/filters/{name}", s.handleDeleteSavedFilter)
+
+	// Project-scoped custom field schema.
+	mux.HandleFunc("GET /v1/projects/{project}/custom-fields", s.handleListCustomFields)
+	mux.HandleFunc("PUT /v1/projects/{project}/custom-fields/{name}", s.handlePutCustomField)
+	mux.HandleFunc("DELETE /v1/projects/{project}/custom-fields/{name}", s.handleDeleteCustomField)
+
+	// Project-scoped milestones.
+	mux.HandleFunc("POST /v1/projects/{project}/milestones", s.handleCreateMilestone)
+	mux.HandleFunc("GET /v1/projects/{project}/milestones", s.handleListMilestones)
+	mux.HandleFunc("GET /v1/projects/{project}/milestones/{milestone_id}", s.handleGetMilestone)
+	mux.HandleFunc("PATCH /v1/projects/{project}/milestones/{milestone_id}", s.handleUpdateMilestone)
+	mux.HandleFunc("DELETE /v1/projects/{project}/milestones/{milestone_id}", s.handleDeleteMilestone)
+
 	// Project-scoped attachments.
 	mux.HandleFunc("POST /v1/projects/{project}/tasks/{id}/attachments", s.handleCreateTaskAttachment)
 	mux.HandleFunc("POST /v1/projects/{project}/tasks/{id}/attachments/link", s.handleCreateTaskAttachmentLink)
 	mux.HandleFunc("GET /v1/projects/{project}/tasks/{id}/attachments", s.handleListTaskAttachments)
 	mux.HandleFunc("GET /v1/projects/{project}/attachments/{attachment_id}", s.handleGetAttachment)
 	mux.HandleFunc("GET /v1/projects/{project}/attachments/{attachment_id}/content", s.handleGetAttachmentContent)
+	mux.HandleFunc("GET /v1/projects/{project}/attachments/{attachment_id}/preview", s.handleGetAttachmentPreview)
+	mux.HandleFunc("PATCH /v1/projects/{project}/attachments/{attachment_id}", s.handleUpdateAttachment)
 	mux.HandleFunc("DELETE /v1/projects/{project}/attachments/{attachment_id}", s.handleDeleteAttachment)
+	mux.HandleFunc("POST /v1/projects/{project}/blobs", s.handleUploadBlob)
 
 	// Project-scoped task git references.
 	mux.HandleFunc("POST /v1/projects/{project}/tasks/{id}/git-refs", s.handleCreateTaskGitRef)
 	mux.HandleFunc("GET /v1/projects/{project}/tasks/{id}/git-refs", s.handleListTaskGitRefs)
+	mux.HandleFunc("POST /v1/projects/{project}/git-refs/by-commits", s.handleTasksByCommits)
+	mux.HandleFunc("POST /v1/projects/{project}/git-refs/scan", s.handleScanGitRefs)
 	mux.HandleFunc("GET /v1/projects/{project}/git-refs/{ref_id}", s.handleGetTaskGitRef)
 	mux.HandleFunc("DELETE /v1/projects/{project}/git-refs/{ref_id}", s.handleDeleteTaskGitRef)
+	mux.HandleFunc("POST /v1/projects/{project}/git-refs/{ref_id}/resolve", s.handleResolveTaskGitRef)
 
 	// Project-scoped dependency tree.
 	mux.HandleFunc("GET /v1/projects/{project}/tasks/{id}/deps/tree", s.handleDepTree)
+	mux.HandleFunc("GET /v1/projects/{project}/tasks/deps/graph", s.handleDepGraph)
 
 	// Project-scoped import/export.
 	mux.HandleFunc("GET /v1/projects/{project}/export", s.handleExport)
 	mux.HandleFunc("POST /v1/projects/{project}/import", s.handleImport)
 	mux.HandleFunc("POST /v1/projects/{project}/import/stream", s.handleImportStream)
+	mux.HandleFunc("GET /v1/projects/{project}/import/reports/{report_id}", s.handleGetImportReport)
 
 	// Admin.
 	mux.HandleFunc("POST /v1/admin/cleanup", s.handleAdminCleanup)
+	mux.HandleFunc("POST /v1/admin/backup", s.handleAdminBackup)
+	mux.HandleFunc("POST /v1/admin/maintenance", s.handleAdminMaintenance)
+	mux.HandleFunc("POST /v1/admin/reindex", s.handleAdminStartReindex)
+	mux.HandleFunc("GET /v1/admin/reindex", s.handleAdminReindexStatus)
+	mux.HandleFunc("GET /v1/admin/maintenance-mode", s.handleAdminGetMaintenanceMode)
+	mux.HandleFunc("POST /v1/admin/maintenance-mode", s.handleAdminSetMaintenanceMode)
+	mux.HandleFunc("POST /v1/admin/purge", s.handleAdminPurge)
+	mux.HandleFunc("POST /v1/admin/labels/rename", s.handleAdminRenameLabel)
 	mux.HandleFunc("POST /v1/admin/gc-blobs", s.handleAdminGCBlobs)
+	mux.HandleFunc("GET /v1/admin/blob-usage", s.handleAdminBlobUsage)
+	mux.HandleFunc("POST /v1/admin/recompute", s.handleAdminRecompute)
+	mux.HandleFunc("GET /v1/admin/dangling-attachments", s.handleAdminListDanglingAttachments)
+	mux.HandleFunc("POST /v1/admin/dangling-attachments", s.handleAdminRepairDanglingAttachments)
 	mux.HandleFunc("POST /v1/admin/users", s.handleAdminCreateUser)
 	mux.HandleFunc("GET /v1/admin/users", s.handleAdminListUsers)
 	mux.HandleFunc("PATCH /v1/admin/users/{username}", s.handleAdminSetUserDisabled)
 	mux.HandleFunc("DELETE /v1/admin/users/{username}", s.handleAdminDeleteUser)
+	mux.HandleFunc("POST /v1/admin/tokens", s.handleAdminCreateToken)
+	mux.HandleFunc("GET /v1/admin/tokens", s.handleAdminListTokens)
+	mux.HandleFunc("GET /v1/admin/tokens/{id}", s.handleAdminGetToken)
+	mux.HandleFunc("POST /v1/admin/tokens/{id}/rotate", s.handleAdminRotateToken)
+	mux.HandleFunc("DELETE /v1/admin/tokens/{id}", s.handleAdminDeleteToken)
+	mux.HandleFunc("GET /v1/admin/audit", s.handleAdminListAudit)
 
 	// Project-scoped dependencies and labels.
 	mux.HandleFunc("POST /v1/projects/{project}/deps", s.handleDeps)
+	mux.HandleFunc("DELETE /v1/projects/{project}/deps", s.handleRemoveDep)
 	mux.HandleFunc("GET /v1/projects/{project}/labels", s.handleLabels)
+	mux.HandleFunc("DELETE /v1/projects/{project}/labels/{label}", s.handleDeleteLabel)
+	mux.HandleFunc("GET /v1/projects/{project}/labels/{label}/related", s.handleRelatedLabels)
+
+	// Project-scoped undo journal.
+	mux.HandleFunc("GET /v1/projects/{project}/operations", s.handleListOperations)
+	mux.HandleFunc("POST /v1/projects/{project}/operations/undo", s.handleUndoLastOperation)
+	mux.HandleFunc("POST /v1/projects/{project}/operations/{id}/undo", s.handleUndoOperation)
 
 	// Embedded Web UI.
 	mux.HandleFunc("GET /{$}", s.handleUIIndex)
 	mux.Handle("GET /ui/", s.uiAssetHandler())
 
-	return s.withRequestLogging(s.withAuth(s.withProjectContext(mux)))
+	return s.withRequestLogging(s.withAuth(s.withMaintenanceMode(s.withProjectContext(s.withIdempotency(s.withMetrics(s.withCompression(mux)))))))
 }
 
 func (s *Server) withProjectContext(next http.Handler) http.Handler {
@@ -105,7 +192,7 @@ func (s *Server) withProjectContext(next http.Handler) http.Handler {
 
 func (s *Server) withAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/health" || !strings.HasPrefix(r.URL.Path, "/v1/") {
+		if r.URL.Path == "/health" || (r.URL.Path != "/metrics" && !strings.HasPrefix(r.URL.Path, "/v1/")) {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -150,6 +237,16 @@ func (s *Server) withAuth(next http.Handler) http.Handler {
 				})
 				return
 			}
+			if principal.AuthType == authTypeAPIToken && !apiTokenAllows(principal.Token, r) {
+				s.log().Debug("request forbidden by api token scope", "method", r.Method, "path", r.URL.Path, "token_id", principal.Token.ID)
+				s.writeErrorReq(w, r, http.StatusForbidden, apiError{
+					status:  http.StatusForbidden,
+					code:    "forbidden",
+					errCode: ErrCodeForbidden,
+					err:     fmt.Errorf("api token lacks required scope"),
+				})
+				return
+			}
 		}
 
 		if s.adminToken != "" && strings.HasPrefix(r.URL.Path, "/v1/admin/") {
@@ -170,6 +267,9 @@ func (s *Server) withAuth(next http.Handler) http.Handler {
 		if authenticated {
 			ctx = contextWithAuthPrincipal(ctx, principal)
 		}
+		if actor := strings.TrimSpace(r.Header.Get("X-Actor")); actor != "" && utf8.RuneCountInString(actor) <= maxActorLength {
+			ctx = contextWithActor(ctx, actor)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -194,6 +294,15 @@ func (s *Server) authorizeAPIRequest(r *http.Request) (bool, authPrincipal, erro
 	if s.apiToken != "" && authHeader == "Bearer "+s.apiToken {
 		return true, authPrincipal{AuthType: authTypeBearer}, nil
 	}
+	if secret, ok := strings.CutPrefix(authHeader, "Bearer "); ok && s.authService != nil {
+		token, err := s.authService.AuthenticateAPIToken(r.Context(), secret, time.Now().UTC())
+		if err != nil {
+			return false, authPrincipal{}, err
+		}
+		if token != nil {
+			return true, authPrincipal{AuthType: authTypeAPIToken, Token: token}, nil
+		}
+	}
 
 	sessionToken := sessionTokenFromRequest(r)
 	if sessionToken == "" || s.authService == nil {
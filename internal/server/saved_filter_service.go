@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+var savedFilterNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// SavedFilterService manages named list filters ("views") per project.
+type SavedFilterService struct {
+	store         store.SavedFilterStore
+	projectPrefix string
+}
+
+// NewSavedFilterService constructs a SavedFilterService.
+func NewSavedFilterService(filterStore store.SavedFilterStore, projectPrefix string) *SavedFilterService {
+	return &SavedFilterService{store: filterStore, projectPrefix: projectPrefix}
+}
+
+// Create stores a new named filter after validating it parses as a list filter.
+func (s *SavedFilterService) Create(ctx context.Context, name string, filter map[string]string) (models.SavedFilter, error) {
+	saved, err := s.prepare(ctx, name, filter)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+	if err := s.store.CreateSavedFilter(ctx, &saved); err != nil {
+		if isUniqueConstraint(err) {
+			return models.SavedFilter{}, conflictCode(fmt.Errorf("saved filter already exists"), ErrCodeConflict)
+		}
+		return models.SavedFilter{}, err
+	}
+	return saved, nil
+}
+
+// Replace overwrites the filter payload of an existing named filter.
+func (s *SavedFilterService) Replace(ctx context.Context, name string, filter map[string]string) (models.SavedFilter, error) {
+	saved, err := s.prepare(ctx, name, filter)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+	found, err := s.store.UpdateSavedFilter(ctx, &saved)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+	if !found {
+		return models.SavedFilter{}, notFoundCode(fmt.Errorf("saved filter not found"), ErrCodeSavedFilterNotFound)
+	}
+	return s.Get(ctx, name)
+}
+
+// Get returns one named filter.
+func (s *SavedFilterService) Get(ctx context.Context, name string) (models.SavedFilter, error) {
+	project, name, err := s.scope(ctx, name)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+	saved, err := s.store.GetSavedFilter(ctx, project, name)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+	if saved == nil {
+		return models.SavedFilter{}, notFoundCode(fmt.Errorf("saved filter not found"), ErrCodeSavedFilterNotFound)
+	}
+	return *saved, nil
+}
+
+// List returns the project's named filters ordered by name.
+func (s *SavedFilterService) List(ctx context.Context) ([]models.SavedFilter, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.ListSavedFilters(ctx, project)
+}
+
+// Delete removes one named filter.
+func (s *SavedFilterService) Delete(ctx context.Context, name string) error {
+	project, name, err := s.scope(ctx, name)
+	if err != nil {
+		return err
+	}
+	found, err := s.store.DeleteSavedFilter(ctx, project, name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notFoundCode(fmt.Errorf("saved filter not found"), ErrCodeSavedFilterNotFound)
+	}
+	return nil
+}
+
+func (s *SavedFilterService) prepare(ctx context.Context, name string, filter map[string]string) (models.SavedFilter, error) {
+	project, name, err := s.scope(ctx, name)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+	if len(filter) == 0 {
+		return models.SavedFilter{}, badRequestCode(fmt.Errorf("filter is required"), ErrCodeMissingRequired)
+	}
+	if _, err := parseFilterBody(filter); err != nil {
+		return models.SavedFilter{}, err
+	}
+	now := time.Now().UTC()
+	return models.SavedFilter{Project: project, Name: name, Filter: filter, CreatedAt: now, UpdatedAt: now}, nil
+}
+
+func (s *SavedFilterService) scope(ctx context.Context, name string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", "", badRequestCode(fmt.Errorf("name is required"), ErrCodeMissingRequired)
+	}
+	if !savedFilterNameRegex.MatchString(name) {
+		return "", "", badRequestCode(fmt.Errorf("invalid filter name"), ErrCodeInvalidArgument)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return project, name, nil
+}
+
+func (s *SavedFilterService) project(ctx context.Context) (string, error) {
+	if project, ok := projectFromContext(ctx); ok {
+		return project, nil
+	}
+	return normalizePrefix(s.projectPrefix)
+}
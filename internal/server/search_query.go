@@ -0,0 +1,252 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// searchQueryFields maps DSL field names to the list query params they set.
+// Fields in searchQueryListFields accumulate comma-separated values when
+// repeated; the rest may appear once.
+var searchQueryFields = map[string]string{
+	"status":    "status",
+	"type":      "type",
+	"label":     "label",
+	"label_any": "label_any",
+	"id":        "id",
+	"dep_type":  "dep_type",
+	"git":       "git_refs",
+	"assignee":  "assignee",
+	"parent":    "parent_id",
+	"milestone": "milestone_id",
+	"spec":      "spec",
+	"title":     "title_contains",
+	"desc":      "desc_contains",
+	"notes":     "notes_contains",
+	"sort":      "sort",
+}
+
+var searchQueryListFields = map[string]bool{
+	"status": true, "type": true, "label": true, "label_any": true, "id": true, "dep_type": true, "git": true,
+}
+
+// searchQueryDateFields map to <param>_after for > and >=, and <param>_before for < and <=.
+var searchQueryDateFields = map[string]string{
+	"created": "created",
+	"updated": "updated",
+	"closed":  "closed",
+}
+
+// searchQueryNoFields are the values accepted by no:<value>.
+var searchQueryNoFields = map[string]string{
+	"assignee":    "no_assignee",
+	"labels":      "no_labels",
+	"description": "empty_description",
+}
+
+// withSearchQuery expands the q param into list query params. Explicit params
+// win over values from q, as they do over saved views.
+func withSearchQuery(r *http.Request) (*http.Request, error) {
+	query := r.URL.Query()
+	if !query.Has("q") {
+		return r, nil
+	}
+	expandedValues, err := parseSearchQuery(query.Get("q"))
+	if err != nil {
+		return r, err
+	}
+
+	query.Del("q")
+	for key, values := range expandedValues {
+		if !query.Has(key) {
+			query[key] = values
+		}
+	}
+
+	expanded := r.Clone(r.Context())
+	expandedURL := *r.URL
+	expandedURL.RawQuery = query.Encode()
+	expanded.URL = &expandedURL
+	return expanded, nil
+}
+
+// parseSearchQuery expands a q= search expression such as
+// `status:open label:backend priority<=1 "auth bug"` into list query params.
+// Bare words and quoted phrases become the full-text search.
+func parseSearchQuery(q string) (url.Values, error) {
+	terms, err := splitSearchTerms(q)
+	if err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	var text []string
+	set := func(param, value string) error {
+		if values.Has(param) {
+			return badRequestCode(fmt.Errorf("q sets %s more than once", param), ErrCodeInvalidQuery)
+		}
+		values.Set(param, value)
+		return nil
+	}
+
+	for _, term := range terms {
+		if term.quoted {
+			text = append(text, `"`+strings.ReplaceAll(term.text, `"`, `""`)+`"`)
+			continue
+		}
+		field, op, value, ok := cutSearchTerm(term.text)
+		if !ok {
+			text = append(text, term.text)
+			continue
+		}
+		if value == "" {
+			return nil, badRequestCode(fmt.Errorf("q term %q has no value", term.text), ErrCodeInvalidQuery)
+		}
+
+		switch {
+		case field == "priority":
+			if err := setPriorityTerm(set, op, value); err != nil {
+				return nil, err
+			}
+		case searchQueryDateFields[field] != "":
+			param := searchQueryDateFields[field]
+			switch op {
+			case ">", ">=":
+				param += "_after"
+			case "<", "<=":
+				param += "_before"
+			default:
+				return nil, badRequestCode(fmt.Errorf("q field %s needs <, <=, > or >=", field), ErrCodeInvalidQuery)
+			}
+			if err := set(param, value); err != nil {
+				return nil, err
+			}
+		case op != ":" && op != "=":
+			return nil, badRequestCode(fmt.Errorf("q field %s does not support %s", field, op), ErrCodeInvalidQuery)
+		case field == "no":
+			param, ok := searchQueryNoFields[strings.ToLower(value)]
+			if !ok {
+				return nil, badRequestCode(fmt.Errorf("q term no:%s is not supported (allowed: assignee, labels, description)", value), ErrCodeInvalidQuery)
+			}
+			values.Set(param, "true")
+		case strings.HasPrefix(field, "custom."):
+			if err := set(field, value); err != nil {
+				return nil, err
+			}
+		case searchQueryFields[field] != "":
+			param := searchQueryFields[field]
+			if searchQueryListFields[field] && values.Has(param) {
+				values.Set(param, values.Get(param)+","+value)
+				continue
+			}
+			if err := set(param, value); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, badRequestCode(fmt.Errorf("unknown q field %q (quote the term to search for it as text)", field), ErrCodeInvalidQuery)
+		}
+	}
+
+	if len(text) > 0 {
+		values.Set("search", strings.Join(text, " "))
+	}
+	return values, nil
+}
+
+// setPriorityTerm maps priority:N, priority<=N and friends onto the
+// priority, priority_min and priority_max params.
+func setPriorityTerm(set func(param, value string) error, op, value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return badRequestCode(fmt.Errorf("invalid priority %q in q", value), ErrCodeInvalidPriority)
+	}
+	switch op {
+	case ":", "=":
+		return set("priority", value)
+	case "<=":
+		return set("priority_max", value)
+	case "<":
+		return set("priority_max", strconv.Itoa(n-1))
+	case ">=":
+		return set("priority_min", value)
+	default: // ">"
+		return set("priority_min", strconv.Itoa(n+1))
+	}
+}
+
+// cutSearchTerm splits field<op>value. Terms without an identifier before the
+// operator stay free text.
+func cutSearchTerm(term string) (field, op, value string, ok bool) {
+	idx := strings.IndexAny(term, ":=<>")
+	if idx <= 0 {
+		return "", "", "", false
+	}
+	field = strings.ToLower(term[:idx])
+	for _, r := range field {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' && r != '-' {
+			return "", "", "", false
+		}
+	}
+	rest := term[idx:]
+	for _, candidate := range []string{"<=", ">=", ":", "=", "<", ">"} {
+		if after, found := strings.CutPrefix(rest, candidate); found {
+			return field, candidate, unquoteSearchValue(after), true
+		}
+	}
+	return "", "", "", false
+}
+
+func unquoteSearchValue(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+type searchTerm struct {
+	text   string
+	quoted bool
+}
+
+// splitSearchTerms splits on whitespace. A quoted phrase is one term; quotes
+// after a field operator (assignee:"Jane Doe") keep the value together.
+func splitSearchTerms(q string) ([]searchTerm, error) {
+	var terms []searchTerm
+	var current strings.Builder
+	inQuotes, quotedTerm := false, false
+
+	flush := func() {
+		if current.Len() > 0 {
+			terms = append(terms, searchTerm{text: current.String(), quoted: quotedTerm})
+		}
+		current.Reset()
+		quotedTerm = false
+	}
+
+	for _, r := range q {
+		switch {
+		case r == '"' && !inQuotes && current.Len() == 0:
+			inQuotes, quotedTerm = true, true
+		case r == '"' && inQuotes && quotedTerm:
+			inQuotes = false
+			flush()
+		case r == '"':
+			// Quotes inside a field value are kept and stripped by cutSearchTerm.
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, badRequestCode(fmt.Errorf("q has an unterminated quote"), ErrCodeInvalidQuery)
+	}
+	flush()
+	return terms, nil
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		q    string
+		want url.Values
+	}{
+		{
+			q: `status:open label:backend priority<=1 "auth bug"`,
+			want: url.Values{
+				"status":       {"open"},
+				"label":        {"backend"},
+				"priority_max": {"1"},
+				"search":       {`"auth bug"`},
+			},
+		},
+		{
+			q:    "label:api label:urgent type:bug,feature",
+			want: url.Values{"label": {"api,urgent"}, "type": {"bug,feature"}},
+		},
+		{
+			q:    "priority>1 priority<4",
+			want: url.Values{"priority_min": {"2"}, "priority_max": {"3"}},
+		},
+		{
+			q:    "created>=2026-01-01 updated<2026-02-01T00:00:00Z",
+			want: url.Values{"created_after": {"2026-01-01"}, "updated_before": {"2026-02-01T00:00:00Z"}},
+		},
+		{
+			q:    `assignee:"Jane Doe" parent:gr-ab12 no:labels custom.team:infra`,
+			want: url.Values{"assignee": {"Jane Doe"}, "parent_id": {"gr-ab12"}, "no_labels": {"true"}, "custom.team": {"infra"}},
+		},
+		{
+			q:    "auth OR oauth",
+			want: url.Values{"search": {"auth OR oauth"}},
+		},
+		{
+			q:    "   ",
+			want: url.Values{},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSearchQuery(tt.q)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.q, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("parse %q:\n got %v\nwant %v", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	for _, q := range []string{
+		"colour:red",
+		"assignee:a assignee:b",
+		"created:2026-01-01",
+		"status>open",
+		"priority<=high",
+		"no:milestone",
+		"label:",
+		`"unterminated`,
+	} {
+		if _, err := parseSearchQuery(q); err == nil {
+			t.Fatalf("expected error for %q", q)
+		}
+	}
+}
@@ -1,14 +1,19 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"grns/internal/blobstore"
@@ -24,6 +29,7 @@ const (
 	readTimeout                 = 30 * time.Second
 	writeTimeout                = 60 * time.Second
 	idleTimeout                 = 60 * time.Second
+	shutdownTimeout             = 10 * time.Second
 	importConcurrencyLimit      = 1
 	exportConcurrencyLimit      = 2
 	searchConcurrencyLimit      = 4
@@ -33,16 +39,37 @@ const (
 
 	defaultAttachmentUploadMaxBody   int64 = 100 << 20 // 100 MiB
 	defaultAttachmentMultipartMemory int64 = 8 << 20   // 8 MiB
+
+	defaultMaxDescriptionBytes = 256 << 10 // 256 KiB
+	defaultMaxNotesBytes       = 256 << 10 // 256 KiB
+
+	defaultCreateMaxLabels = 100
+	defaultCreateMaxDeps   = 100
+
+	defaultCloseMaxByFilter = 100
+	maxBulkUpdateTasks      = 500
+
+	defaultUndoWindow = time.Hour
+
+	defaultReportLimit    = 50
+	defaultReportMaxLimit = 500
 )
 
 // Server wraps HTTP handlers for the grns API.
 type Server struct {
 	addr                      string
+	grpcAddr                  string
 	store                     store.TaskStore
 	projectPrefix             string
 	service                   *TaskService
 	attachmentService         *AttachmentService
 	gitRefService             *TaskGitRefService
+	commentService            *TaskCommentService
+	worklogService            *TaskWorklogService
+	watcherService            *TaskWatcherService
+	savedFilterService        *SavedFilterService
+	milestoneService          *MilestoneService
+	customFieldService        *CustomFieldService
 	authService               *AuthService
 	blobStore                 blobstore.BlobStore
 	logger                    *slog.Logger
@@ -55,7 +82,27 @@ type Server struct {
 	loginLimiter              *loginRateLimiter
 	attachmentUploadMaxBody   int64
 	attachmentMultipartMemory int64
+	reportDefaultLimit        int
+	reportMaxLimit            int
+	reportTaskURL             string
+	reportTemplate            *template.Template
+	listIncludes              taskIncludes
+	getIncludes               taskIncludes
 	dbPath                    string
+	metrics                   *serverMetrics
+	adminAudit                store.AdminAuditStore
+	backups                   store.BackupStore
+	maintenance               store.MaintenanceStore
+	maintenanceMode           maintenanceMode
+	reindex                   ftsReindex
+	idempotency               store.IdempotencyStore
+	idempotencyLocks          idempotencyLocks
+	backupDir                 string
+	backupInterval            time.Duration
+	backupKeepLast            int
+	lastBackupAt              atomic.Pointer[time.Time]
+	staleEscalation           StaleEscalationOptions
+	staleWebhookClient        *http.Client
 }
 
 // AttachmentOptions configures attachment runtime behavior on the server.
@@ -65,6 +112,64 @@ type AttachmentOptions struct {
 	AllowedMediaTypes       []string
 	RejectMediaTypeMismatch bool
 	GCBatchSize             int
+	// ScanCommand or ScanURL enables upload scanning; ScanAction is reject or quarantine.
+	ScanCommand string
+	ScanURL     string
+	ScanAction  string
+}
+
+// WorkflowOptions configures task workflow rules on the server.
+type WorkflowOptions struct {
+	WIPLimits               map[string]int
+	WIPLimitsPerAssignee    bool
+	ParentImpliesBlocks     bool
+	RequireAssigneeStatuses []string
+	AutoCloseParents        bool
+	AssigneeWIPLimit        int
+	RequireBlockedReason    bool
+	RequireMergedPR         bool
+}
+
+// ReportOptions configures pagination for aggregate report endpoints.
+type ReportOptions struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// ReportTemplateOptions configures Markdown report rendering. TaskURL may
+// contain {id}; an empty MarkdownTemplate keeps the built-in template.
+type ReportTemplateOptions struct {
+	TaskURL          string
+	MarkdownTemplate string
+}
+
+// ResponseOptions configures default include sections per endpoint.
+// Requests that pass ?include= override these defaults.
+type ResponseOptions struct {
+	ListIncludes []string
+	GetIncludes  []string
+}
+
+// ImportOptions configures import defaults.
+type ImportOptions struct {
+	SourceLabel string
+}
+
+// BackupOptions configures server-side database backups.
+type BackupOptions struct {
+	// Dir receives backups requested with mode "dir" and scheduled backups.
+	// Empty disables both.
+	Dir string
+	// Interval between scheduled backups; zero disables scheduling.
+	Interval time.Duration
+	// KeepLast caps how many snapshots stay in Dir; zero keeps all.
+	KeepLast int
+}
+
+// FieldLimitOptions configures free-text task field size limits.
+type FieldLimitOptions struct {
+	MaxDescriptionBytes int
+	MaxNotesBytes       int
 }
 
 // New creates a new server instance.
@@ -100,13 +205,73 @@ func New(addr string, taskStore store.TaskStore, projectPrefix string, logger *s
 		gitRefService = NewTaskGitRefService(taskStore, gitRefStore, projectPrefix)
 	}
 
+	service := NewTaskService(taskStore, projectPrefix)
+	var commentService *TaskCommentService
+	if commentStore, ok := any(taskStore).(store.CommentStore); ok {
+		commentService = NewTaskCommentService(taskStore, commentStore, projectPrefix)
+		service.comments = commentStore
+	}
+	if eventStore, ok := any(taskStore).(store.EventStore); ok {
+		service.events = eventStore
+	}
+	var worklogService *TaskWorklogService
+	if worklogStore, ok := any(taskStore).(store.WorklogStore); ok {
+		worklogService = NewTaskWorklogService(taskStore, worklogStore, projectPrefix)
+		service.worklogs = worklogStore
+	}
+	var watcherService *TaskWatcherService
+	if watcherStore, ok := any(taskStore).(store.WatcherStore); ok {
+		watcherService = NewTaskWatcherService(taskStore, watcherStore, projectPrefix)
+		service.watchers = watcherStore
+	}
+	var savedFilterService *SavedFilterService
+	if filterStore, ok := any(taskStore).(store.SavedFilterStore); ok {
+		savedFilterService = NewSavedFilterService(filterStore, projectPrefix)
+	}
+	var milestoneService *MilestoneService
+	if milestoneStore, ok := any(taskStore).(store.MilestoneStore); ok {
+		service.milestones = milestoneStore
+		milestoneService = NewMilestoneService(milestoneStore, projectPrefix)
+	}
+	if leaseStore, ok := any(taskStore).(store.LeaseStore); ok {
+		service.leases = leaseStore
+	}
+	if operationStore, ok := any(taskStore).(store.OperationStore); ok {
+		service.operations = operationStore
+	}
+	if changeStore, ok := any(taskStore).(store.ChangeFeedStore); ok {
+		service.changes = changeStore
+	}
+	if attachmentStore, ok := any(taskStore).(store.AttachmentStore); ok {
+		service.attachments = attachmentStore
+		service.importer.attachments = attachmentStore
+	}
+	if gitRefStore, ok := any(taskStore).(store.GitRefStore); ok {
+		service.gitRefs = gitRefStore
+		service.importer.gitRefs = gitRefStore
+	}
+	if reportStore, ok := any(taskStore).(store.ImportReportStore); ok {
+		service.importReports = reportStore
+	}
+	var customFieldService *CustomFieldService
+	if fieldStore, ok := any(taskStore).(store.CustomFieldStore); ok {
+		service.customFields = fieldStore
+		customFieldService = NewCustomFieldService(fieldStore, projectPrefix)
+	}
+
 	srv := &Server{
 		addr:                      addr,
 		store:                     taskStore,
 		projectPrefix:             projectPrefix,
-		service:                   NewTaskService(taskStore, projectPrefix),
+		service:                   service,
 		attachmentService:         attachmentService,
 		gitRefService:             gitRefService,
+		commentService:            commentService,
+		worklogService:            worklogService,
+		watcherService:            watcherService,
+		savedFilterService:        savedFilterService,
+		milestoneService:          milestoneService,
+		customFieldService:        customFieldService,
 		blobStore:                 bs,
 		logger:                    logger,
 		apiToken:                  strings.TrimSpace(os.Getenv(apiTokenEnvKey)),
@@ -117,10 +282,33 @@ func New(addr string, taskStore store.TaskStore, projectPrefix string, logger *s
 		searchLimiter:             make(chan struct{}, searchConcurrencyLimit),
 		loginLimiter:              newLoginRateLimiter(defaultLoginMaxFailures, defaultLoginFailureWindow, defaultLoginBlockedDuration),
 		attachmentUploadMaxBody:   defaultAttachmentUploadMaxBody,
+		reportDefaultLimit:        defaultReportLimit,
+		reportMaxLimit:            defaultReportMaxLimit,
+		reportTemplate:            template.Must(parseReportTemplate("")),
+		getIncludes:               taskIncludes{Deps: true, Watchers: true, Git: true},
 		attachmentMultipartMemory: defaultAttachmentMultipartMemory,
+		metrics:                   newServerMetrics(),
+	}
+	if auditStore, ok := any(taskStore).(store.AdminAuditStore); ok {
+		srv.adminAudit = auditStore
+	}
+	if backupStore, ok := any(taskStore).(store.BackupStore); ok {
+		srv.backups = backupStore
+	}
+	if idempotencyStore, ok := any(taskStore).(store.IdempotencyStore); ok {
+		srv.idempotency = idempotencyStore
+	}
+	if maintenanceStore, ok := any(taskStore).(store.MaintenanceStore); ok {
+		srv.maintenance = maintenanceStore
+	}
+	if metricsStore, ok := any(taskStore).(store.MetricsStore); ok {
+		metricsStore.SetQueryObserver(srv.metrics.observeQuery)
 	}
 	if authStore, ok := any(taskStore).(store.AuthStore); ok {
 		srv.authService = NewAuthService(authStore)
+		if tokenStore, ok := any(taskStore).(store.APITokenStore); ok {
+			srv.authService.tokens = tokenStore
+		}
 	}
 
 	fields := []any{
@@ -128,7 +316,21 @@ func New(addr string, taskStore store.TaskStore, projectPrefix string, logger *s
 		"project_prefix", projectPrefix,
 		"attachment_service_enabled", attachmentService != nil,
 		"git_ref_service_enabled", gitRefService != nil,
+		"comment_service_enabled", commentService != nil,
+		"worklog_service_enabled", worklogService != nil,
+		"watcher_service_enabled", watcherService != nil,
+		"task_history_enabled", service.events != nil,
+		"saved_filter_service_enabled", savedFilterService != nil,
+		"milestone_service_enabled", milestoneService != nil,
+		"custom_field_service_enabled", customFieldService != nil,
+		"task_leases_enabled", service.leases != nil,
+		"undo_journal_enabled", service.operations != nil,
+		"change_feed_enabled", service.changes != nil,
 		"auth_service_enabled", srv.authService != nil,
+		"api_token_store_enabled", srv.authService != nil && srv.authService.tokens != nil,
+		"admin_audit_enabled", srv.adminAudit != nil,
+		"backup_enabled", srv.backups != nil,
+		"maintenance_enabled", srv.maintenance != nil,
 		"api_token_configured", srv.apiToken != "",
 		"admin_token_configured", srv.adminToken != "",
 		"require_auth_with_users", srv.requireAuthWithUsers,
@@ -156,6 +358,7 @@ func (s *Server) ConfigureAttachmentOptions(opts AttachmentOptions) {
 	}
 	if s.attachmentService != nil {
 		s.attachmentService.ConfigurePolicy(opts.AllowedMediaTypes, opts.RejectMediaTypeMismatch, opts.GCBatchSize)
+		s.attachmentService.ConfigureScanner(newContentScanner(opts.ScanCommand, opts.ScanURL), opts.ScanAction)
 	}
 	if s.logger != nil {
 		s.log().Debug("attachment options configured",
@@ -164,10 +367,219 @@ func (s *Server) ConfigureAttachmentOptions(opts AttachmentOptions) {
 			"allowed_media_type_count", len(opts.AllowedMediaTypes),
 			"reject_media_type_mismatch", opts.RejectMediaTypeMismatch,
 			"gc_batch_size", opts.GCBatchSize,
+			"scan_command_configured", strings.TrimSpace(opts.ScanCommand) != "",
+			"scan_url_configured", strings.TrimSpace(opts.ScanURL) != "",
+			"scan_action", opts.ScanAction,
+		)
+	}
+}
+
+// ConfigureGitResolver sets the hosting APIs used to resolve git refs.
+func (s *Server) ConfigureGitResolver(opts GitResolverOptions) error {
+	if s == nil || s.gitRefService == nil {
+		return nil
+	}
+	resolver, err := newGitResolver(opts)
+	if err != nil {
+		return err
+	}
+	s.gitRefService.ConfigureResolver(resolver)
+	if s.service != nil {
+		s.service.gitRefResolver = s.gitRefService
+	}
+	if s.logger != nil {
+		s.log().Debug("git resolver configured",
+			"github_api_url", opts.GitHubAPIURL,
+			"gitlab_api_url", opts.GitLabAPIURL,
+		)
+	}
+	return nil
+}
+
+// ConfigureReportOptions applies report pagination defaults from config.
+// Non-positive values keep defaults; the default limit never exceeds the max.
+func (s *Server) ConfigureReportOptions(opts ReportOptions) {
+	if s == nil {
+		return
+	}
+	if opts.MaxLimit > 0 {
+		s.reportMaxLimit = opts.MaxLimit
+	}
+	if opts.DefaultLimit > 0 {
+		s.reportDefaultLimit = opts.DefaultLimit
+	}
+	s.reportDefaultLimit = min(s.reportDefaultLimit, s.reportMaxLimit)
+	if s.logger != nil {
+		s.log().Debug("report options configured",
+			"default_limit", s.reportDefaultLimit,
+			"max_limit", s.reportMaxLimit,
+		)
+	}
+}
+
+// ConfigureReportTemplate applies the report task URL and Markdown template from config.
+func (s *Server) ConfigureReportTemplate(opts ReportTemplateOptions) error {
+	if s == nil {
+		return nil
+	}
+	tmpl, err := parseReportTemplate(opts.MarkdownTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid reports.markdown_template: %w", err)
+	}
+	s.reportTemplate = tmpl
+	s.reportTaskURL = strings.TrimSpace(opts.TaskURL)
+	if s.logger != nil {
+		s.log().Debug("report template configured",
+			"task_url", s.reportTaskURL,
+			"custom_template", strings.TrimSpace(opts.MarkdownTemplate) != "",
+		)
+	}
+	return nil
+}
+
+// ConfigureResponseOptions applies default include sections from config.
+func (s *Server) ConfigureResponseOptions(opts ResponseOptions) error {
+	if s == nil {
+		return nil
+	}
+	listIncludes, err := parseTaskIncludes(opts.ListIncludes)
+	if err != nil {
+		return fmt.Errorf("list default includes: %w", err)
+	}
+	getIncludes, err := parseTaskIncludes(opts.GetIncludes)
+	if err != nil {
+		return fmt.Errorf("get default includes: %w", err)
+	}
+	s.listIncludes = listIncludes
+	s.getIncludes = getIncludes
+	if s.logger != nil {
+		s.log().Debug("response options configured",
+			"list_includes", strings.Join(opts.ListIncludes, ","),
+			"get_includes", strings.Join(opts.GetIncludes, ","),
+		)
+	}
+	return nil
+}
+
+// ConfigureImportOptions applies import defaults from config.
+func (s *Server) ConfigureImportOptions(opts ImportOptions) error {
+	if s == nil || s.service == nil || s.service.importer == nil {
+		return nil
+	}
+	sourceLabel, err := resolveImportSourceLabel(opts.SourceLabel, "")
+	if err != nil {
+		return fmt.Errorf("import source label: %w", err)
+	}
+	s.service.importer.defaultSourceLabel = sourceLabel
+	if s.logger != nil {
+		s.log().Debug("import options configured", "source_label", sourceLabel)
+	}
+	return nil
+}
+
+// ConfigureBackupOptions applies the backup directory from config.
+func (s *Server) ConfigureBackupOptions(opts BackupOptions) {
+	if s == nil {
+		return
+	}
+	s.backupDir = strings.TrimSpace(opts.Dir)
+	s.backupInterval = max(opts.Interval, 0)
+	s.backupKeepLast = max(opts.KeepLast, 0)
+	if s.backupDir != "" {
+		s.lastBackupAt.Store(latestBackupTime(s.backupDir))
+	}
+	if s.logger != nil {
+		s.log().Debug("backup options configured", "dir", s.backupDir, "interval", s.backupInterval, "keep_last", s.backupKeepLast)
+	}
+}
+
+// ConfigureWorkflowOptions applies task workflow settings from config.
+func (s *Server) ConfigureWorkflowOptions(opts WorkflowOptions) {
+	if s == nil || s.service == nil {
+		return
+	}
+	s.service.ConfigureWIPLimits(opts.WIPLimits, opts.WIPLimitsPerAssignee)
+	s.service.ConfigureParentImpliesBlocks(opts.ParentImpliesBlocks)
+	s.service.ConfigureRequireAssigneeStatuses(opts.RequireAssigneeStatuses)
+	s.service.ConfigureAutoCloseParents(opts.AutoCloseParents)
+	s.service.ConfigureAssigneeWIPLimit(opts.AssigneeWIPLimit)
+	s.service.ConfigureRequireBlockedReason(opts.RequireBlockedReason)
+	s.service.ConfigureRequireMergedPR(opts.RequireMergedPR)
+	if s.logger != nil {
+		s.log().Debug("workflow options configured",
+			"wip_limit_count", len(s.service.wipLimits),
+			"wip_limits_per_assignee", opts.WIPLimitsPerAssignee,
+			"parent_implies_blocks", opts.ParentImpliesBlocks,
+			"require_assignee_status_count", len(s.service.requireAssigneeStatuses),
+			"auto_close_parents", opts.AutoCloseParents,
+			"assignee_wip_limit", s.service.assigneeWIPLimit,
+			"require_blocked_reason", opts.RequireBlockedReason,
+			"require_merged_pr", opts.RequireMergedPR,
+		)
+	}
+}
+
+// ConfigureFieldLimits applies task field size limits from config.
+func (s *Server) ConfigureFieldLimits(opts FieldLimitOptions) {
+	if s == nil || s.service == nil {
+		return
+	}
+	s.service.ConfigureFieldLimits(opts.MaxDescriptionBytes, opts.MaxNotesBytes)
+	if s.logger != nil {
+		s.log().Debug("field limits configured",
+			"max_description_bytes", s.service.fieldLimits.MaxDescriptionBytes,
+			"max_notes_bytes", s.service.fieldLimits.MaxNotesBytes,
+		)
+	}
+}
+
+// CreateLimitOptions configures per-request label and dependency caps on create.
+type CreateLimitOptions struct {
+	MaxLabels int
+	MaxDeps   int
+}
+
+// ConfigureCreateLimits applies create payload caps from config. Non-positive values keep defaults.
+func (s *Server) ConfigureCreateLimits(opts CreateLimitOptions) {
+	if s == nil || s.service == nil {
+		return
+	}
+	s.service.ConfigureCreateLimits(opts.MaxLabels, opts.MaxDeps)
+	if s.logger != nil {
+		s.log().Debug("create limits configured",
+			"max_labels", s.service.createLimits.MaxLabels,
+			"max_deps", s.service.createLimits.MaxDeps,
 		)
 	}
 }
 
+// CloseLimitOptions configures caps on bulk close operations.
+type CloseLimitOptions struct {
+	MaxByFilter int
+}
+
+// ConfigureCloseLimits applies the close-by-filter cap from config. Non-positive values keep the default.
+func (s *Server) ConfigureCloseLimits(opts CloseLimitOptions) {
+	if s == nil || s.service == nil {
+		return
+	}
+	s.service.ConfigureCloseLimits(opts.MaxByFilter)
+	if s.logger != nil {
+		s.log().Debug("close limits configured", "max_by_filter", s.service.maxCloseByFilter)
+	}
+}
+
+// ConfigureUndoWindow sets how long journaled operations stay undoable. Non-positive values keep the default.
+func (s *Server) ConfigureUndoWindow(window time.Duration) {
+	if s == nil || s.service == nil {
+		return
+	}
+	s.service.ConfigureUndoWindow(window)
+	if s.logger != nil {
+		s.log().Debug("undo window configured", "window", s.service.undoWindow.String())
+	}
+}
+
 // SetDBPath records the active database path for runtime metadata endpoints.
 func (s *Server) SetDBPath(path string) {
 	if s == nil {
@@ -178,6 +590,22 @@ func (s *Server) SetDBPath(path string) {
 
 // ListenAndServe starts the HTTP server.
 func (s *Server) ListenAndServe() error {
+	listener, err := s.Listen()
+	if err != nil {
+		return err
+	}
+	return s.Serve(context.Background(), listener)
+}
+
+// Listen opens the server's address: a TCP host:port or a "unix://" socket.
+func (s *Server) Listen() (net.Listener, error) {
+	return listen(s.addr)
+}
+
+// Serve serves HTTP on listener and runs the background jobs until ctx is
+// cancelled, then stops accepting requests and waits up to
+// shutdownTimeout for in-flight ones. A unix socket is removed on return.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
 	s.log().Info("starting server",
 		"addr", s.addr,
 		"project_prefix", s.projectPrefix,
@@ -185,6 +613,7 @@ func (s *Server) ListenAndServe() error {
 		"admin_token_configured", s.adminToken != "",
 		"require_auth_with_users", s.requireAuthWithUsers,
 	)
+
 	server := &http.Server{
 		Addr:              s.addr,
 		Handler:           s.routes(),
@@ -194,10 +623,62 @@ func (s *Server) ListenAndServe() error {
 		IdleTimeout:       idleTimeout,
 	}
 
-	return server.ListenAndServe()
+	done := make(chan struct{})
+	defer close(done)
+	go s.sweepExpiredLeases(done, leaseSweepInterval)
+	go s.runFlowSnapshots(done, flowSnapshotInterval)
+	if s.idempotency != nil {
+		go s.pruneIdempotencyRecords(done, idempotencyPruneInterval)
+	}
+	if s.backupInterval > 0 {
+		if s.backups == nil || s.backupDir == "" {
+			s.log().Warn("scheduled backups disabled: backup.dir is not configured", "interval", s.backupInterval)
+		} else {
+			go s.runScheduledBackups(done, s.backupInterval)
+		}
+	}
+	if s.staleEscalation.Interval > 0 && len(s.staleEscalation.Thresholds) > 0 {
+		go s.runStaleEscalation(done, s.staleEscalation.Interval)
+	}
+
+	serveErr := make(chan error, 2)
+	go func() { serveErr <- server.Serve(listener) }()
+	if s.grpcAddr != "" {
+		grpcListener, err := listen(s.grpcAddr)
+		if err != nil {
+			server.Close()
+			return fmt.Errorf("grpc listen: %w", err)
+		}
+		grpcServer := s.newGRPCServer()
+		s.log().Info("starting grpc server", "addr", s.grpcAddr)
+		go func() {
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				serveErr <- fmt.Errorf("grpc: %w", err)
+			}
+		}()
+		defer stopGRPC(grpcServer)
+	}
+	select {
+	case err := <-serveErr:
+		server.Close()
+		return err
+	case <-ctx.Done():
+	}
+
+	s.log().Info("shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
-// ListenAddr converts a base API URL into a listen address.
+// ListenAddr converts a base API URL into a listen address. Unix socket
+// URLs ("unix:///run/grns.sock") are returned unchanged.
 func ListenAddr(apiURL string) (string, error) {
 	if apiURL == "" {
 		return "", fmt.Errorf("api url is required")
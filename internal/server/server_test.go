@@ -2,11 +2,16 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"grns/internal/api"
+	"grns/internal/store"
 )
 
 func TestListenAddr(t *testing.T) {
@@ -68,6 +73,33 @@ func TestAcquireLimiterReturnsStructured429(t *testing.T) {
 	}
 }
 
+func TestStoreQueryTimeoutReturnsStructured503(t *testing.T) {
+	srv := &Server{}
+	err := storeFailure(fmt.Errorf("list tasks: %w", fmt.Errorf("%w after 30s: interrupted (9)", store.ErrQueryTimeout)))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/tasks", nil)
+	w := httptest.NewRecorder()
+	srv.writeErrorReq(w, req, httpStatusFromError(err), err)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", w.Code)
+	}
+
+	var errResp api.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if errResp.Code != "query_timeout" || errResp.ErrorCode != ErrCodeQueryTimeout {
+		t.Fatalf("expected query_timeout/%d, got %q/%d", ErrCodeQueryTimeout, errResp.Code, errResp.ErrorCode)
+	}
+	if errResp.Error != store.ErrQueryTimeout.Error() {
+		t.Fatalf("expected public timeout message, got %q", errResp.Error)
+	}
+
+	if got := status.Code(srv.grpcError("ListTasks", err)); got != codes.Unavailable {
+		t.Fatalf("expected gRPC Unavailable, got %s", got)
+	}
+}
+
 func TestWithAuth(t *testing.T) {
 	t.Run("denies missing auth", func(t *testing.T) {
 		srv := &Server{apiToken: "token"}
@@ -199,3 +231,74 @@ func TestWithAuth(t *testing.T) {
 		}
 	})
 }
+
+func TestHandleCapabilitiesReflectsConfiguration(t *testing.T) {
+	srv := newListTestServer(t)
+	srv.ConfigureAttachmentOptions(AttachmentOptions{MaxUploadBytes: 4096})
+	srv.ConfigureWorkflowOptions(WorkflowOptions{WIPLimits: map[string]int{"in_progress": 3}, WIPLimitsPerAssignee: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/capabilities", nil)
+	w := httptest.NewRecorder()
+	srv.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	var resp api.CapabilitiesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode capabilities: %v", err)
+	}
+	if resp.Limits.MaxAttachmentUploadBytes != 4096 {
+		t.Fatalf("expected max upload 4096, got %d", resp.Limits.MaxAttachmentUploadBytes)
+	}
+	if !resp.Features.WIPLimits || resp.Limits.WIPLimits["in_progress"] != 3 || !resp.Limits.WIPLimitsPerAssignee {
+		t.Fatalf("expected configured wip limits, got %#v / %#v", resp.Features, resp.Limits)
+	}
+	if !resp.Features.Attachments || resp.ReadOnly {
+		t.Fatalf("unexpected features: %#v read_only=%v", resp.Features, resp.ReadOnly)
+	}
+	if len(resp.DependencyTypes) != 4 || resp.DependencyTypes[0] != "blocks" {
+		t.Fatalf("unexpected dependency types: %#v", resp.DependencyTypes)
+	}
+}
+
+func TestProjectIsolationAcrossListingsAndInfo(t *testing.T) {
+	srv := newListTestServer(t)
+	seedListTask(t, srv, "gr-iso1", "gr task", 2)
+	seedListTask(t, srv, "xy-iso1", "xy task one", 2)
+	seedListTask(t, srv, "xy-iso2", "xy task two", 2)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		srv.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: expected 200, got %d (%s)", path, w.Code, w.Body.String())
+		}
+		return w
+	}
+
+	var tasks []api.TaskResponse
+	if err := json.Unmarshal(get("/v1/projects/gr/tasks").Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("decode tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "gr-iso1" {
+		t.Fatalf("expected only gr tasks, got %#v", tasks)
+	}
+
+	var info api.InfoResponse
+	if err := json.Unmarshal(get("/v1/projects/xy/info").Body.Bytes(), &info); err != nil {
+		t.Fatalf("decode project info: %v", err)
+	}
+	if info.Project != "xy" || info.TotalTasks != 2 {
+		t.Fatalf("unexpected project info: %#v", info)
+	}
+
+	if err := json.Unmarshal(get("/v1/info").Body.Bytes(), &info); err != nil {
+		t.Fatalf("decode info: %v", err)
+	}
+	if info.TotalTasks != 3 || len(info.Projects) != 2 {
+		t.Fatalf("unexpected global info: %#v", info)
+	}
+}
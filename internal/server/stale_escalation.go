@@ -0,0 +1,244 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+// Stale escalation actions, applied in the configured order.
+const (
+	staleActionLabel        = "label"
+	staleActionBumpPriority = "bump_priority"
+	staleActionWebhook      = "webhook"
+	staleActionDefer        = "defer"
+)
+
+const (
+	staleEscalationActor = "grns-stale"
+	staleWebhookEvent    = "tasks.stale"
+	staleWebhookTimeout  = 30 * time.Second
+	// maxStaleEscalationTasks caps how many tasks of one status one run escalates per project.
+	maxStaleEscalationTasks = 500
+)
+
+var staleActions = []string{staleActionLabel, staleActionBumpPriority, staleActionWebhook, staleActionDefer}
+
+// StaleEscalationOptions configures the background job that escalates stale tasks.
+type StaleEscalationOptions struct {
+	// Interval between runs; zero disables the job.
+	Interval time.Duration
+	// Thresholds maps a status to the days without an update after which its
+	// tasks are stale. Statuses without a threshold are never escalated.
+	Thresholds map[string]int
+	// Actions lists what happens to stale tasks: label, bump_priority, webhook, defer.
+	Actions []string
+	// Label is added by the label action.
+	Label string
+	// WebhookURL receives an api.StaleWebhookPayload per project for the webhook action.
+	WebhookURL string
+}
+
+// ConfigureStaleEscalation validates and applies stale escalation settings.
+func (s *Server) ConfigureStaleEscalation(opts StaleEscalationOptions) error {
+	if s == nil {
+		return nil
+	}
+	thresholds := make(map[string]int, len(opts.Thresholds))
+	for raw, days := range opts.Thresholds {
+		status, err := normalizeStatus(raw)
+		if err != nil {
+			return fmt.Errorf("stale threshold: %w", err)
+		}
+		if status == string(models.StatusClosed) || status == string(models.StatusTombstone) {
+			return fmt.Errorf("stale threshold: %s tasks cannot be escalated", status)
+		}
+		if days <= 0 {
+			return fmt.Errorf("stale threshold for %s must be positive", status)
+		}
+		thresholds[status] = days
+	}
+	for _, action := range opts.Actions {
+		if !slices.Contains(staleActions, action) {
+			return fmt.Errorf("unknown stale action %q", action)
+		}
+	}
+	if slices.Contains(opts.Actions, staleActionWebhook) && strings.TrimSpace(opts.WebhookURL) == "" {
+		return fmt.Errorf("stale action webhook requires a webhook url")
+	}
+	if slices.Contains(opts.Actions, staleActionLabel) {
+		if _, err := normalizeLabels([]string{opts.Label}); err != nil {
+			return fmt.Errorf("stale label: %w", err)
+		}
+	}
+
+	opts.Thresholds = thresholds
+	opts.Interval = max(opts.Interval, 0)
+	opts.WebhookURL = strings.TrimSpace(opts.WebhookURL)
+	s.staleEscalation = opts
+	s.staleWebhookClient = &http.Client{Timeout: staleWebhookTimeout}
+	if s.logger != nil {
+		s.log().Debug("stale escalation configured",
+			"interval", opts.Interval,
+			"threshold_count", len(thresholds),
+			"actions", strings.Join(opts.Actions, ","),
+			"webhook", opts.WebhookURL != "",
+		)
+	}
+	return nil
+}
+
+func (s *Server) runStaleEscalation(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if _, err := s.escalateStaleTasks(context.Background(), time.Now().UTC()); err != nil {
+				s.log().Error("stale escalation failed", "error", err)
+			}
+		}
+	}
+}
+
+// escalateStaleTasks runs one escalation pass over every project. It returns
+// one report per project that had stale tasks; a task whose actions fail is
+// logged and left out of its report.
+func (s *Server) escalateStaleTasks(ctx context.Context, now time.Time) ([]api.StaleWebhookPayload, error) {
+	opts := s.staleEscalation
+	if len(opts.Thresholds) == 0 || len(opts.Actions) == 0 {
+		return nil, nil
+	}
+	info, err := s.store.StoreInfo(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []api.StaleWebhookPayload
+	for _, project := range info.Projects {
+		projectCtx := contextWithActor(contextWithProject(ctx, project), staleEscalationActor)
+		tasks, err := s.service.staleForEscalation(projectCtx, opts.Thresholds, now)
+		if err != nil {
+			return reports, err
+		}
+		report := api.StaleWebhookPayload{Event: staleWebhookEvent, Project: project, Actions: opts.Actions, RunAt: now, Tasks: []api.StaleTaskInfo{}}
+		for _, task := range tasks {
+			if err := s.service.escalateStaleTask(projectCtx, task, opts); err != nil {
+				s.log().Warn("stale escalation skipped task", "project", project, "id", task.ID, "error", err)
+				continue
+			}
+			report.Tasks = append(report.Tasks, api.StaleTaskInfo{
+				ID:        task.ID,
+				Title:     task.Title,
+				Status:    task.Status,
+				Priority:  task.Priority,
+				Assignee:  task.Assignee,
+				UpdatedAt: task.UpdatedAt,
+				StaleDays: int(now.Sub(task.UpdatedAt).Hours() / 24),
+			})
+		}
+		if len(report.Tasks) == 0 {
+			continue
+		}
+		s.log().Info("stale tasks escalated", "project", project, "count", len(report.Tasks))
+		if slices.Contains(opts.Actions, staleActionWebhook) {
+			if err := s.postStaleWebhook(ctx, report); err != nil {
+				s.log().Warn("stale webhook failed", "project", project, "error", err)
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func (s *Server) postStaleWebhook(ctx context.Context, payload api.StaleWebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.staleEscalation.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.staleWebhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// staleForEscalation lists the project's tasks that have gone longer than
+// their status threshold without an update, oldest first within each status.
+func (s *TaskService) staleForEscalation(ctx context.Context, thresholds map[string]int, now time.Time) ([]models.Task, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]string, 0, len(thresholds))
+	for status := range thresholds {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	var stale []models.Task
+	for _, status := range statuses {
+		cutoff := now.AddDate(0, 0, -thresholds[status])
+		tasks, err := s.store.ListStaleTasks(ctx, project, cutoff, []string{status}, maxStaleEscalationTasks)
+		if err != nil {
+			return nil, err
+		}
+		stale = append(stale, tasks...)
+	}
+	return stale, nil
+}
+
+// escalateStaleTask applies the label, bump_priority, and defer actions to one
+// task. Priority and status change in one update, which also resets
+// updated_at, so the task is escalated again only after another threshold
+// passes. Labels alone do not touch updated_at, so without such a change the
+// task is touched instead; otherwise every run would escalate it again.
+func (s *TaskService) escalateStaleTask(ctx context.Context, task models.Task, opts StaleEscalationOptions) error {
+	var update api.TaskUpdateRequest
+	changed := false
+	for _, action := range opts.Actions {
+		switch action {
+		case staleActionLabel:
+			if _, err := s.AddLabels(ctx, task.ID, []string{opts.Label}); err != nil {
+				return err
+			}
+		case staleActionBumpPriority:
+			if task.Priority > models.PriorityMin {
+				priority := task.Priority - 1
+				update.Priority = &priority
+				changed = true
+			}
+		case staleActionDefer:
+			if task.Status != string(models.StatusDeferred) {
+				status := string(models.StatusDeferred)
+				update.Status = &status
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return s.Touch(ctx, []string{task.ID})
+	}
+	_, err := s.Update(ctx, task.ID, update)
+	return err
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestEscalateStaleTasks(t *testing.T) {
+	var payloads []api.StaleWebhookPayload
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload api.StaleWebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode webhook: %v", err)
+		}
+		payloads = append(payloads, payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhook.Close()
+
+	srv := newListTestServer(t)
+	if err := srv.ConfigureStaleEscalation(StaleEscalationOptions{Actions: []string{"webhook"}}); err == nil {
+		t.Fatal("expected webhook action without url to fail")
+	}
+	if err := srv.ConfigureStaleEscalation(StaleEscalationOptions{
+		Interval:   time.Hour,
+		Thresholds: map[string]int{"open": 30, "in_progress": 7},
+		Actions:    []string{"label", "bump_priority", "webhook", "defer"},
+		Label:      "stale",
+		WebhookURL: webhook.URL,
+	}); err != nil {
+		t.Fatalf("configure stale escalation: %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	for _, seed := range []struct {
+		id, status string
+		priority   int
+		age        time.Duration
+	}{
+		{"gr-st01", "open", 2, 40 * 24 * time.Hour},
+		{"gr-st02", "open", 2, 5 * 24 * time.Hour},
+		{"gr-st03", "in_progress", 0, 10 * 24 * time.Hour},
+		{"gr-st04", "blocked", 2, 90 * 24 * time.Hour},
+	} {
+		updated := now.Add(-seed.age)
+		task := &models.Task{ID: seed.id, Title: "stale " + seed.id, Status: seed.status, Type: "task", Priority: seed.priority, CreatedAt: updated, UpdatedAt: updated}
+		if err := srv.store.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	reports, err := srv.escalateStaleTasks(ctx, now)
+	if err != nil {
+		t.Fatalf("escalate: %v", err)
+	}
+	if len(reports) != 1 || len(payloads) != 1 || payloads[0].Project != "gr" || len(payloads[0].Tasks) != 2 {
+		t.Fatalf("unexpected reports %#v / webhook payloads %#v", reports, payloads)
+	}
+	if stale := payloads[0].Tasks[1]; stale.ID != "gr-st01" || stale.Status != "open" || stale.StaleDays != 40 {
+		t.Fatalf("unexpected webhook task: %#v", stale)
+	}
+
+	for id, wantPriority := range map[string]int{"gr-st01": 1, "gr-st03": 0} {
+		task, err := srv.store.GetTask(ctx, id)
+		if err != nil {
+			t.Fatalf("get %s: %v", id, err)
+		}
+		labels, err := srv.store.ListLabels(ctx, id)
+		if err != nil {
+			t.Fatalf("labels %s: %v", id, err)
+		}
+		if task.Status != "deferred" || task.Priority != wantPriority || !slices.Contains(labels, "stale") {
+			t.Fatalf("unexpected escalated task %s: %#v labels=%v", id, task, labels)
+		}
+	}
+	for _, id := range []string{"gr-st02", "gr-st04"} {
+		task, err := srv.store.GetTask(ctx, id)
+		if err != nil {
+			t.Fatalf("get %s: %v", id, err)
+		}
+		if task.Status == "deferred" || task.Priority != 2 {
+			t.Fatalf("task %s should not be escalated: %#v", id, task)
+		}
+	}
+
+	// Escalated tasks were updated and deferred, so the next run finds nothing.
+	if reports, err := srv.escalateStaleTasks(ctx, now); err != nil || len(reports) != 0 || len(payloads) != 1 {
+		t.Fatalf("expected no second escalation, got %#v, %v", reports, err)
+	}
+}
+
+func TestEscalateStaleTasksLabelOnlyNotifiesOnce(t *testing.T) {
+	deliveries := 0
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deliveries++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhook.Close()
+
+	srv := newListTestServer(t)
+	if err := srv.ConfigureStaleEscalation(StaleEscalationOptions{
+		Interval:   time.Hour,
+		Thresholds: map[string]int{"open": 30},
+		Actions:    []string{"label", "webhook"},
+		Label:      "stale",
+		WebhookURL: webhook.URL,
+	}); err != nil {
+		t.Fatalf("configure stale escalation: %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	updated := now.Add(-40 * 24 * time.Hour)
+	task := &models.Task{ID: "gr-sl01", Title: "forgotten", Status: "open", Type: "task", Priority: 2, CreatedAt: updated, UpdatedAt: updated}
+	if err := srv.store.CreateTask(ctx, task, nil, nil); err != nil {
+		t.Fatalf("seed task: %v", err)
+	}
+
+	for tick := 0; tick < 2; tick++ {
+		if _, err := srv.escalateStaleTasks(ctx, now.Add(time.Duration(tick)*time.Hour)); err != nil {
+			t.Fatalf("escalate tick %d: %v", tick, err)
+		}
+	}
+	if deliveries != 1 {
+		t.Fatalf("expected one webhook delivery over two ticks, got %d", deliveries)
+	}
+	labels, err := srv.store.ListLabels(ctx, "gr-sl01")
+	if err != nil || !slices.Contains(labels, "stale") {
+		t.Fatalf("expected stale label, got %v (%v)", labels, err)
+	}
+}
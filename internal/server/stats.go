@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+const (
+	statsIntervalDay  = "day"
+	statsIntervalWeek = "week"
+	statsDateLayout   = "2006-01-02"
+
+	// flowSnapshotInterval is how often the current day's status snapshot is refreshed.
+	flowSnapshotInterval  = time.Hour
+	defaultFlowWindowDays = 30
+	maxFlowWindowDays     = 365
+)
+
+// statsAgeBounds are the lower bounds, in days, of the open task age buckets.
+// The last bucket is open-ended.
+var statsAgeBounds = []int{0, 1, 7, 30, 90}
+
+func normalizeStatsInterval(value string) (string, error) {
+	switch interval := strings.ToLower(strings.TrimSpace(value)); interval {
+	case "":
+		return statsIntervalDay, nil
+	case statsIntervalDay, statsIntervalWeek:
+		return interval, nil
+	default:
+		return "", badRequestCode(fmt.Errorf("interval must be day or week"), ErrCodeInvalidQuery)
+	}
+}
+
+// taskStats computes throughput statistics for project, or for all projects
+// when project is empty. Created and closed counts are bucketed by interval
+// and run without gaps from the first to the last bucket with any tasks.
+func (s *Server) taskStats(ctx context.Context, project string, from, to *time.Time, interval string) (api.StatsResponse, error) {
+	statsStore, ok := any(s.store).(store.StatsStore)
+	if !ok {
+		return api.StatsResponse{}, internalError(fmt.Errorf("stats are not supported by this store"))
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return api.StatsResponse{}, badRequestCode(fmt.Errorf("from must be before to"), ErrCodeInvalidQuery)
+	}
+
+	now := time.Now().UTC()
+	filter := store.StatsFilter{Project: project, Now: now}
+	if from != nil {
+		filter.From = *from
+	}
+	if to != nil {
+		filter.To = *to
+	}
+	stats, err := statsStore.TaskStats(ctx, filter)
+	if err != nil {
+		return api.StatsResponse{}, err
+	}
+
+	return api.StatsResponse{
+		Project:     project,
+		From:        from,
+		To:          to,
+		Interval:    interval,
+		GeneratedAt: now,
+		Created:     statsBuckets(stats.CreatedPerDay, interval),
+		Closed:      statsBuckets(stats.ClosedPerDay, interval),
+		TimeToClose: api.StatsTimeToClose{
+			Count:        stats.ClosedCount,
+			AverageHours: float64(int64(stats.AvgCloseSeconds/36+0.5)) / 100,
+		},
+		OpenAge:    statsAgeBuckets(stats.OpenAgeDays),
+		ByType:     stats.ByType,
+		ByAssignee: stats.ByAssignee,
+		ByLabel:    stats.ByLabel,
+	}, nil
+}
+
+// statsBuckets folds per-day counts into day or week (Monday-start) buckets.
+func statsBuckets(perDay map[string]int, interval string) []api.StatsBucket {
+	counts := map[time.Time]int{}
+	var first, last time.Time
+	for day, count := range perDay {
+		parsed, err := time.Parse(statsDateLayout, day)
+		if err != nil {
+			continue
+		}
+		start := statsBucketStart(parsed, interval)
+		counts[start] += count
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+
+	buckets := []api.StatsBucket{}
+	if len(counts) == 0 {
+		return buckets
+	}
+	step := 1
+	if interval == statsIntervalWeek {
+		step = 7
+	}
+	for start := first; !start.After(last); start = start.AddDate(0, 0, step) {
+		buckets = append(buckets, api.StatsBucket{Start: start.Format(statsDateLayout), Count: counts[start]})
+	}
+	return buckets
+}
+
+func statsBucketStart(day time.Time, interval string) time.Time {
+	if interval != statsIntervalWeek {
+		return day
+	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func statsAgeBuckets(ageDays map[int]int) []api.StatsAgeBucket {
+	buckets := make([]api.StatsAgeBucket, len(statsAgeBounds))
+	for i, lower := range statsAgeBounds {
+		buckets[i] = api.StatsAgeBucket{MinDays: lower, Label: strconv.Itoa(lower) + "d+"}
+		if i+1 < len(statsAgeBounds) {
+			upper := statsAgeBounds[i+1]
+			buckets[i].MaxDays = &upper
+			buckets[i].Label = fmt.Sprintf("%d-%dd", lower, upper)
+		}
+	}
+	for days, count := range ageDays {
+		i := sort.Search(len(statsAgeBounds), func(i int) bool { return statsAgeBounds[i] > days }) - 1
+		buckets[max(i, 0)].Count += count
+	}
+	return buckets
+}
+
+// parseFlowWindow reads a window such as "30d" or "30" as a number of days.
+func parseFlowWindow(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return defaultFlowWindowDays, nil
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+	if err != nil || days < 1 || days > maxFlowWindowDays {
+		return 0, badRequestCode(fmt.Errorf("window must be between 1d and %dd", maxFlowWindowDays), ErrCodeInvalidQuery)
+	}
+	return days, nil
+}
+
+// runFlowSnapshots records a status snapshot now and every interval until
+// done closes. Each run overwrites the current day's snapshot, so a past day
+// keeps the counts from the last run before it ended.
+func (s *Server) runFlowSnapshots(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.recordFlowSnapshot(context.Background(), time.Now()); err != nil {
+			s.log().Warn("status snapshot failed", "error", err)
+		}
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) recordFlowSnapshot(ctx context.Context, now time.Time) error {
+	statsStore, ok := any(s.store).(store.StatsStore)
+	if !ok {
+		return nil
+	}
+	return statsStore.RecordStatusSnapshots(ctx, now.UTC().Format(statsDateLayout))
+}
+
+// taskFlow returns per-status task counts for each of the last windowDays
+// days. Past days come from recorded snapshots and are left out when none was
+// taken, including every day before the first snapshot; the current day uses
+// live counts.
+func (s *Server) taskFlow(ctx context.Context, project string, windowDays int, now time.Time) (api.FlowResponse, error) {
+	statsStore, ok := any(s.store).(store.StatsStore)
+	if !ok {
+		return api.FlowResponse{}, internalError(fmt.Errorf("stats are not supported by this store"))
+	}
+	today := now.UTC().Truncate(24 * time.Hour)
+	first := today.AddDate(0, 0, 1-windowDays)
+	snapshots, err := statsStore.ListStatusSnapshots(ctx, project, first.Format(statsDateLayout), today.AddDate(0, 0, -1).Format(statsDateLayout))
+	if err != nil {
+		return api.FlowResponse{}, err
+	}
+	info, err := s.store.StoreInfo(ctx, project)
+	if err != nil {
+		return api.FlowResponse{}, err
+	}
+
+	statuses := []string{}
+	for _, status := range models.TaskStatusStrings() {
+		if status != string(models.StatusTombstone) {
+			statuses = append(statuses, status)
+		}
+	}
+	newDay := func(date string) api.FlowDay {
+		day := api.FlowDay{Date: date, Counts: make(map[string]int, len(statuses))}
+		for _, status := range statuses {
+			day.Counts[status] = 0
+		}
+		return day
+	}
+
+	resp := api.FlowResponse{Project: project, WindowDays: windowDays, Statuses: statuses, Days: []api.FlowDay{}}
+	for _, snapshot := range snapshots {
+		if n := len(resp.Days); n == 0 || resp.Days[n-1].Date != snapshot.Day {
+			resp.Days = append(resp.Days, newDay(snapshot.Day))
+		}
+		if _, ok := resp.Days[len(resp.Days)-1].Counts[snapshot.Status]; ok {
+			resp.Days[len(resp.Days)-1].Counts[snapshot.Status] = snapshot.Count
+		}
+	}
+	current := newDay(today.Format(statsDateLayout))
+	for status, count := range info.TaskCounts {
+		if _, ok := current.Counts[status]; ok {
+			current.Counts[status] = count
+		}
+	}
+	resp.Days = append(resp.Days, current)
+	return resp, nil
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+)
+
+func TestProjectStats(t *testing.T) {
+	srv := newListTestServer(t)
+	ctx := context.Background()
+	day := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("parse %s: %v", value, err)
+		}
+		return parsed
+	}
+	for _, seed := range []struct {
+		id, taskType, assignee string
+		labels                 []string
+		created                time.Time
+		closed                 string
+	}{
+		{"gr-sa01", "bug", "alice", []string{"ui"}, day("2026-03-02T10:00:00Z"), "2026-03-04T10:00:00Z"},
+		{"gr-sa02", "task", "", []string{"ui", "backend"}, day("2026-03-03T10:00:00Z"), ""},
+		{"gr-sa03", "task", "alice", nil, day("2026-03-11T10:00:00Z"), "2026-03-11T22:00:00Z"},
+		{"gr-sa04", "task", "bob", nil, time.Now().UTC().AddDate(0, 0, -3), ""},
+		{"gr-sa05", "task", "bob", nil, day("2026-02-20T10:00:00Z"), "2026-03-12T10:00:00Z"},
+	} {
+		task := &models.Task{ID: seed.id, Title: "stats " + seed.id, Status: "open", Type: seed.taskType, Priority: 2, Assignee: seed.assignee, CreatedAt: seed.created, UpdatedAt: seed.created}
+		if seed.closed != "" {
+			closedAt := day(seed.closed)
+			task.Status = "closed"
+			task.ClosedAt = &closedAt
+		}
+		if err := srv.store.CreateTask(ctx, task, seed.labels, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/projects/gr/stats?from=2026-03-01&to=2026-04-01&interval=week", nil)
+	rec := httptest.NewRecorder()
+	srv.routes().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp api.StatsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	wantCreated := []api.StatsBucket{{Start: "2026-03-02", Count: 2}, {Start: "2026-03-09", Count: 1}}
+	wantClosed := []api.StatsBucket{{Start: "2026-03-02", Count: 1}, {Start: "2026-03-09", Count: 2}}
+	if !slices.Equal(resp.Created, wantCreated) || !slices.Equal(resp.Closed, wantClosed) {
+		t.Fatalf("unexpected buckets: created %#v closed %#v", resp.Created, resp.Closed)
+	}
+	if resp.TimeToClose.Count != 3 || resp.TimeToClose.AverageHours != 180 {
+		t.Fatalf("unexpected time to close: %#v", resp.TimeToClose)
+	}
+	if !maps.Equal(resp.ByType, map[string]int{"bug": 1, "task": 2}) ||
+		!maps.Equal(resp.ByAssignee, map[string]int{"alice": 2, "": 1}) ||
+		!maps.Equal(resp.ByLabel, map[string]int{"ui": 2, "backend": 1}) {
+		t.Fatalf("unexpected breakdowns: %#v %#v %#v", resp.ByType, resp.ByAssignee, resp.ByLabel)
+	}
+	ages := map[string]int{}
+	for _, bucket := range resp.OpenAge {
+		ages[bucket.Label] = bucket.Count
+	}
+	if !maps.Equal(ages, map[string]int{"0-1d": 0, "1-7d": 1, "7-30d": 0, "30-90d": 0, "90d+": 1}) {
+		t.Fatalf("unexpected open age: %#v", resp.OpenAge)
+	}
+
+	for _, query := range []string{"interval=month", "from=2026-04-01&to=2026-03-01", "from=yesterday"} {
+		rec := httptest.NewRecorder()
+		srv.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/stats?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d: %s", query, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestStatsFlow(t *testing.T) {
+	srv := newListTestServer(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	for _, id := range []string{"gr-fl01", "gr-fl02", "gr-fl03"} {
+		task := &models.Task{ID: id, Title: "flow " + id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := srv.store.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+	past := now.AddDate(0, 0, -2)
+	if err := srv.recordFlowSnapshot(ctx, past); err != nil {
+		t.Fatalf("record snapshot: %v", err)
+	}
+	closed := "closed"
+	if _, err := srv.service.Update(contextWithProject(ctx, "gr"), "gr-fl01", api.TaskUpdateRequest{Status: &closed}); err != nil {
+		t.Fatalf("close task: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/projects/gr/stats/flow?window=3d", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp api.FlowResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.WindowDays != 3 || len(resp.Days) != 2 || slices.Contains(resp.Statuses, "tombstone") {
+		t.Fatalf("unexpected flow: %#v", resp)
+	}
+	if day := resp.Days[0]; day.Date != past.Format("2006-01-02") || day.Counts["open"] != 3 || day.Counts["closed"] != 0 {
+		t.Fatalf("unexpected snapshot day: %#v", day)
+	}
+	if day := resp.Days[1]; day.Date != now.Format("2006-01-02") || day.Counts["open"] != 2 || day.Counts["closed"] != 1 {
+		t.Fatalf("unexpected current day: %#v", day)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/stats/flow?window=2y", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for bad window, got %d", rec.Code)
+	}
+}
+
+// TestStatsFlowHistoryStartsAtUpgrade pins that days before the first
+// snapshot are not backfilled from task timestamps or history, so a database
+// that predates snapshots shows only the live current day.
+func TestStatsFlowHistoryStartsAtUpgrade(t *testing.T) {
+	srv := newListTestServer(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	created := now.AddDate(0, 0, -10)
+	closedAt := now.AddDate(0, 0, -5)
+	seeds := []*models.Task{
+		{ID: "gr-fu01", Title: "old open", Status: "open", Type: "task", Priority: 2, CreatedAt: created, UpdatedAt: created},
+		{ID: "gr-fu02", Title: "old closed", Status: "closed", Type: "task", Priority: 2, CreatedAt: created, UpdatedAt: closedAt, ClosedAt: &closedAt},
+	}
+	for _, task := range seeds {
+		if err := srv.store.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("seed task: %v", err)
+		}
+	}
+	if err := srv.store.AppendTaskEvents(ctx, []models.TaskEvent{
+		{TaskID: "gr-fu02", Type: models.TaskEventClosed, CreatedAt: closedAt},
+	}); err != nil {
+		t.Fatalf("seed event: %v", err)
+	}
+
+	resp, err := srv.taskFlow(ctx, "gr", 30, now)
+	if err != nil {
+		t.Fatalf("task flow: %v", err)
+	}
+	if len(resp.Days) != 1 {
+		t.Fatalf("expected only the current day before any snapshot, got %#v", resp.Days)
+	}
+	if day := resp.Days[0]; day.Date != now.Format("2006-01-02") || day.Counts["open"] != 1 || day.Counts["closed"] != 1 {
+		t.Fatalf("unexpected current day: %#v", day)
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+// parseExportSince reads the export since= watermark: a cursor returned in
+// X-Next-Cursor (digits only) or an RFC3339/YYYY-MM-DD timestamp.
+func parseExportSince(value string) (int64, *time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil, badRequestCode(fmt.Errorf("since must be a cursor or timestamp"), ErrCodeInvalidQuery)
+	}
+	if strings.Trim(value, "0123456789") == "" {
+		cursor, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, nil, badRequestCode(fmt.Errorf("invalid since cursor"), ErrCodeInvalidQuery)
+		}
+		return cursor, nil, nil
+	}
+	since, err := parseFlexibleTime(value)
+	if err != nil {
+		return 0, nil, err
+	}
+	return 0, &since, nil
+}
+
+// ChangeWatermark returns the newest change sequence number. Exporting every
+// change up to it and resuming after it misses nothing.
+func (s *TaskService) ChangeWatermark(ctx context.Context) (int64, error) {
+	if s.changes == nil {
+		return 0, internalError(fmt.Errorf("incremental export is not configured"))
+	}
+	return s.changes.LatestChangeSeq(ctx)
+}
+
+// ExportChangesPage returns up to limit tasks whose latest change is after
+// afterSeq and at or before throughSeq, oldest change first, hydrated like
+// ExportPage. It also returns the sequence number of the last task returned.
+func (s *TaskService) ExportChangesPage(ctx context.Context, afterSeq, throughSeq int64, updatedSince *time.Time, limit int) ([]api.TaskResponse, int64, error) {
+	if s.changes == nil {
+		return nil, 0, internalError(fmt.Errorf("incremental export is not configured"))
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	changes, err := s.changes.ListTaskChanges(ctx, store.ChangeFilter{
+		Project:      project,
+		AfterSeq:     afterSeq,
+		ThroughSeq:   throughSeq,
+		UpdatedSince: updatedSince,
+		Limit:        limit,
+	})
+	if err != nil || len(changes) == 0 {
+		return nil, afterSeq, err
+	}
+
+	tasks := make([]models.Task, 0, len(changes))
+	for _, change := range changes {
+		tasks = append(tasks, change.Task)
+	}
+	records, err := s.attachLabelsAndDeps(ctx, tasks)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.attachArchiveSections(ctx, project, records); err != nil {
+		return nil, 0, err
+	}
+	return records, changes[len(changes)-1].Seq, nil
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"grns/internal/api"
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+const (
+	defaultClaimLease  = 30 * time.Minute
+	maxClaimLease      = 24 * time.Hour
+	leaseSweepInterval = 30 * time.Second
+)
+
+// Claim leases the top ready task matching req to the caller: the task moves to
+// in_progress, is assigned to the caller, and returns to the ready pool when
+// the lease expires.
+func (s *TaskService) Claim(ctx context.Context, req api.TaskClaimRequest) (api.TaskClaimResponse, error) {
+	var resp api.TaskClaimResponse
+	if s.leases == nil {
+		return resp, internalError(fmt.Errorf("task leases are not configured"))
+	}
+
+	actor := strings.TrimSpace(req.Actor)
+	if actor == "" {
+		actor = actorFromContext(ctx)
+	}
+	if actor == "" {
+		return resp, badRequestCode(fmt.Errorf("actor is required"), ErrCodeMissingRequired)
+	}
+	if utf8.RuneCountInString(actor) > maxActorLength {
+		return resp, badRequestCode(fmt.Errorf("actor exceeds %d characters", maxActorLength), ErrCodeInvalidArgument)
+	}
+	lease := defaultClaimLease
+	if req.LeaseSeconds != 0 {
+		lease = time.Duration(req.LeaseSeconds) * time.Second
+		if lease <= 0 || lease > maxClaimLease {
+			return resp, badRequestCode(fmt.Errorf("lease_seconds must be between 1 and %d", int(maxClaimLease/time.Second)), ErrCodeInvalidArgument)
+		}
+	}
+	order, err := parseReadyOrder(req.Order)
+	if err != nil {
+		return resp, err
+	}
+	types := make([]string, 0, len(req.Types))
+	for _, value := range req.Types {
+		taskType, err := normalizeType(value)
+		if err != nil {
+			return resp, err
+		}
+		types = append(types, taskType)
+	}
+	labels, err := normalizeLabels(req.Labels)
+	if err != nil {
+		return resp, err
+	}
+	if req.MaxPriority != nil && !models.IsValidPriority(*req.MaxPriority) {
+		return resp, badRequestCode(fmt.Errorf("max_priority must be between %d and %d", models.PriorityMin, models.PriorityMax), ErrCodeInvalidPriority)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return resp, err
+	}
+
+	// Release expired leases first so their tasks are claimable without
+	// waiting for the next sweep.
+	if err := s.ReleaseExpiredLeases(ctx); err != nil {
+		return resp, err
+	}
+
+	now := time.Now().UTC()
+	claim := store.ReadyClaim{
+		Project:     project,
+		Order:       order,
+		Types:       types,
+		Labels:      labels,
+		MaxPriority: req.MaxPriority,
+		Holder:      actor,
+		ClaimedAt:   now,
+		ExpiresAt:   now.Add(lease),
+	}
+	inProgress := string(models.StatusInProgress)
+	claim.WIPLimits = s.wipLimitsFor(&inProgress)
+	claim.Events = func(task *models.Task) []models.TaskEvent {
+		changes := []models.TaskFieldChange{
+			{Field: "status", Old: string(models.StatusOpen), New: task.Status},
+			{Field: "assignee", New: task.Assignee},
+			{Field: "lease_expires_at", New: claim.ExpiresAt},
+		}
+		return s.taskEvents(ctx, models.TaskEventClaimed, []string{task.ID}, changes)
+	}
+	task, err := s.leases.ClaimReadyTask(ctx, claim)
+	if err != nil {
+		var wipErr *store.WIPLimitExceededError
+		if errors.As(err, &wipErr) {
+			return resp, wipLimitError(wipErr)
+		}
+		return resp, err
+	}
+	if task == nil {
+		return resp, notFoundCode(fmt.Errorf("no ready task to claim"), ErrCodeTaskNotFound)
+	}
+
+	responses, err := s.attachLabels(ctx, []models.Task{*task})
+	if err != nil {
+		return resp, err
+	}
+	resp.Task = responses[0]
+	resp.Lease = models.TaskLease{TaskID: task.ID, Holder: actor, ClaimedAt: claim.ClaimedAt, ExpiresAt: claim.ExpiresAt}
+	return resp, nil
+}
+
+// ReleaseExpiredLeases returns tasks whose claim lease has expired to the
+// ready pool and records a lease_expired event on each.
+func (s *TaskService) ReleaseExpiredLeases(ctx context.Context) error {
+	if s.leases == nil {
+		return nil
+	}
+	_, err := s.leases.ReleaseExpiredLeases(ctx, time.Now().UTC(), func(lease models.TaskLease) []models.TaskEvent {
+		changes := []models.TaskFieldChange{
+			{Field: "status", Old: string(models.StatusInProgress), New: string(models.StatusOpen)},
+			{Field: "assignee", Old: lease.Holder, New: ""},
+		}
+		return s.taskEvents(ctx, models.TaskEventLeaseExpired, []string{lease.TaskID}, changes)
+	})
+	return err
+}
+
+// sweepExpiredLeases releases expired leases every interval until done closes.
+func (s *Server) sweepExpiredLeases(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := s.service.ReleaseExpiredLeases(context.Background()); err != nil {
+				s.log().Warn("lease sweep failed", "error", err)
+			}
+		}
+	}
+}
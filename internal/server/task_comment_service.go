@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"grns/internal/api"
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+const (
+	maxCommentAuthorLength = 128
+	maxCommentBodyBytes    = 64 << 10 // 64 KiB
+)
+
+// TaskCommentService orchestrates task comment threads.
+type TaskCommentService struct {
+	taskStore     store.TaskServiceStore
+	commentStore  store.CommentStore
+	projectPrefix string
+}
+
+// NewTaskCommentService constructs a TaskCommentService.
+func NewTaskCommentService(taskStore store.TaskServiceStore, commentStore store.CommentStore, projectPrefix string) *TaskCommentService {
+	return &TaskCommentService{taskStore: taskStore, commentStore: commentStore, projectPrefix: projectPrefix}
+}
+
+// Create appends one comment to a task's thread.
+func (s *TaskCommentService) Create(ctx context.Context, taskID string, req api.TaskCommentCreateRequest) (models.TaskComment, error) {
+	var zero models.TaskComment
+	if s == nil || s.taskStore == nil || s.commentStore == nil {
+		return zero, internalError(fmt.Errorf("task comment service is not configured"))
+	}
+
+	author, body, err := normalizeCommentInput(req)
+	if err != nil {
+		return zero, err
+	}
+	project, err := s.ensureTaskExists(ctx, taskID)
+	if err != nil {
+		return zero, err
+	}
+
+	id, err := store.GenerateTaskCommentID(func(id string) (bool, error) {
+		return s.commentStore.TaskCommentIDExists(ctx, id)
+	})
+	if err != nil {
+		return zero, err
+	}
+
+	comment := models.TaskComment{
+		Project:   project,
+		ID:        id,
+		TaskID:    strings.TrimSpace(taskID),
+		Author:    author,
+		Body:      body,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.commentStore.CreateTaskComment(ctx, &comment); err != nil {
+		return zero, err
+	}
+	return comment, nil
+}
+
+// List returns one task's comments ordered oldest first.
+func (s *TaskCommentService) List(ctx context.Context, taskID string) ([]models.TaskComment, error) {
+	if s == nil || s.taskStore == nil || s.commentStore == nil {
+		return nil, internalError(fmt.Errorf("task comment service is not configured"))
+	}
+
+	project, err := s.ensureTaskExists(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.commentStore.ListTaskComments(ctx, project, strings.TrimSpace(taskID))
+}
+
+func (s *TaskCommentService) ensureTaskExists(ctx context.Context, taskID string) (string, error) {
+	taskID = strings.TrimSpace(taskID)
+	if !validateID(taskID) {
+		return "", badRequestCode(fmt.Errorf("invalid task_id"), ErrCodeInvalidID)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !taskIDBelongsToProject(taskID, project) {
+		return "", notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	exists, err := s.taskStore.TaskExists(taskID)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	return project, nil
+}
+
+func (s *TaskCommentService) project(ctx context.Context) (string, error) {
+	if project, ok := projectFromContext(ctx); ok {
+		return project, nil
+	}
+	return normalizePrefix(s.projectPrefix)
+}
+
+func normalizeCommentInput(req api.TaskCommentCreateRequest) (string, string, error) {
+	author := strings.TrimSpace(req.Author)
+	if author == "" {
+		return "", "", badRequestCode(fmt.Errorf("author is required"), ErrCodeMissingRequired)
+	}
+	if utf8.RuneCountInString(author) > maxCommentAuthorLength {
+		return "", "", badRequestCode(fmt.Errorf("author exceeds %d characters", maxCommentAuthorLength), ErrCodeInvalidArgument)
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return "", "", badRequestCode(fmt.Errorf("body is required"), ErrCodeMissingRequired)
+	}
+	if len(body) > maxCommentBodyBytes {
+		return "", "", badRequestCode(fmt.Errorf("body exceeds %d bytes", maxCommentBodyBytes), ErrCodeInvalidArgument)
+	}
+	return author, body, nil
+}
@@ -15,8 +15,10 @@ type taskListFilter struct {
 	PriorityMin      *int
 	PriorityMax      *int
 	ParentID         string
+	MilestoneID      string
 	Labels           []string
 	LabelsAny        []string
+	DepTypes         []string
 	SpecRegex        string
 	Assignee         string
 	NoAssignee       bool
@@ -32,40 +34,62 @@ type taskListFilter struct {
 	ClosedBefore     *time.Time
 	EmptyDescription bool
 	NoLabels         bool
-	SearchQuery      string
-	Limit            int
-	Offset           int
+	GitRefRelations  []string
+	HasGitRefs       bool
+	NoGitRefs        bool
+	Custom           map[string]string
+	CustomExists     []string
+	// ExcludeTombstones hides tombstoned tasks unless a status filter is given.
+	ExcludeTombstones bool
+	SearchQuery       string
+	Limit             int
+	Offset            int
+	AfterID           string
+	OrderByID         bool
+	Sort              []store.SortKey
+	Includes          taskIncludes
 }
 
 func (f taskListFilter) toStoreListFilter() store.ListFilter {
 	return store.ListFilter{
-		Project:          f.Project,
-		Statuses:         f.Statuses,
-		Types:            f.Types,
-		Priority:         f.Priority,
-		PriorityMin:      f.PriorityMin,
-		PriorityMax:      f.PriorityMax,
-		ParentID:         f.ParentID,
-		Labels:           f.Labels,
-		LabelsAny:        f.LabelsAny,
-		SpecRegex:        f.SpecRegex,
-		Assignee:         f.Assignee,
-		NoAssignee:       f.NoAssignee,
-		IDs:              f.IDs,
-		TitleContains:    f.TitleContains,
-		DescContains:     f.DescContains,
-		NotesContains:    f.NotesContains,
-		CreatedAfter:     f.CreatedAfter,
-		CreatedBefore:    f.CreatedBefore,
-		UpdatedAfter:     f.UpdatedAfter,
-		UpdatedBefore:    f.UpdatedBefore,
-		ClosedAfter:      f.ClosedAfter,
-		ClosedBefore:     f.ClosedBefore,
-		EmptyDescription: f.EmptyDescription,
-		NoLabels:         f.NoLabels,
-		SearchQuery:      f.SearchQuery,
-		Limit:            f.Limit,
-		Offset:           f.Offset,
+		Project:           f.Project,
+		Statuses:          f.Statuses,
+		Types:             f.Types,
+		Priority:          f.Priority,
+		PriorityMin:       f.PriorityMin,
+		PriorityMax:       f.PriorityMax,
+		ParentID:          f.ParentID,
+		MilestoneID:       f.MilestoneID,
+		Labels:            f.Labels,
+		LabelsAny:         f.LabelsAny,
+		DepTypes:          f.DepTypes,
+		SpecRegex:         f.SpecRegex,
+		Assignee:          f.Assignee,
+		NoAssignee:        f.NoAssignee,
+		IDs:               f.IDs,
+		TitleContains:     f.TitleContains,
+		DescContains:      f.DescContains,
+		NotesContains:     f.NotesContains,
+		CreatedAfter:      f.CreatedAfter,
+		CreatedBefore:     f.CreatedBefore,
+		UpdatedAfter:      f.UpdatedAfter,
+		UpdatedBefore:     f.UpdatedBefore,
+		ClosedAfter:       f.ClosedAfter,
+		ClosedBefore:      f.ClosedBefore,
+		EmptyDescription:  f.EmptyDescription,
+		NoLabels:          f.NoLabels,
+		GitRefRelations:   f.GitRefRelations,
+		HasGitRefs:        f.HasGitRefs,
+		NoGitRefs:         f.NoGitRefs,
+		Custom:            f.Custom,
+		CustomExists:      f.CustomExists,
+		ExcludeTombstones: f.ExcludeTombstones,
+		SearchQuery:       f.SearchQuery,
+		Limit:             f.Limit,
+		Offset:            f.Offset,
+		AfterID:           f.AfterID,
+		OrderByID:         f.OrderByID,
+		Sort:              f.Sort,
 	}
 }
 
@@ -83,11 +107,24 @@ type taskUpdatePatch struct {
 	Design             *string
 	AcceptanceCriteria *string
 	SourceRepo         *string
+	MilestoneID        *string
 	ClosedAt           *time.Time
+	DeletedAt          *time.Time
+	MergedInto         *string
+	BlockedReason      *string
+	BlockedOn          *string
 	Custom             *map[string]any
 	UpdatedAt          time.Time
 }
 
+// hasFieldChanges reports whether the patch sets any task field beyond updated_at.
+func (p taskUpdatePatch) hasFieldChanges() bool {
+	return p.Title != nil || p.Status != nil || p.Type != nil || p.Priority != nil ||
+		p.Description != nil || p.SpecID != nil || p.ParentID != nil || p.Assignee != nil ||
+		p.Notes != nil || p.Design != nil || p.AcceptanceCriteria != nil || p.SourceRepo != nil ||
+		p.MilestoneID != nil || p.BlockedReason != nil || p.BlockedOn != nil || p.Custom != nil
+}
+
 func (p taskUpdatePatch) toStoreTaskUpdate() store.TaskUpdate {
 	return store.TaskUpdate{
 		Title:              p.Title,
@@ -102,7 +139,12 @@ func (p taskUpdatePatch) toStoreTaskUpdate() store.TaskUpdate {
 		Design:             p.Design,
 		AcceptanceCriteria: p.AcceptanceCriteria,
 		SourceRepo:         p.SourceRepo,
+		MilestoneID:        p.MilestoneID,
 		ClosedAt:           p.ClosedAt,
+		DeletedAt:          p.DeletedAt,
+		MergedInto:         p.MergedInto,
+		BlockedReason:      p.BlockedReason,
+		BlockedOn:          p.BlockedOn,
 		Custom:             p.Custom,
 		UpdatedAt:          p.UpdatedAt,
 	}
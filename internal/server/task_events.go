@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+// maxActorLength caps the caller-declared X-Actor header recorded on task events.
+const maxActorLength = 128
+
+// History returns the recorded mutation events for one task, oldest first.
+func (s *TaskService) History(ctx context.Context, id string) ([]models.TaskEvent, error) {
+	if s.events == nil {
+		return nil, internalError(fmt.Errorf("task history is not configured"))
+	}
+	if !validateID(id) {
+		return nil, badRequestCode(fmt.Errorf("invalid id"), ErrCodeInvalidID)
+	}
+	if err := s.ensureTaskExists(ctx, id); err != nil {
+		return nil, err
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.events.ListTaskEvents(ctx, project, id)
+}
+
+// recordEvents appends one event of eventType per task id through m, so the
+// events commit or roll back with the mutation they describe.
+func (s *TaskService) recordEvents(ctx context.Context, m store.ImportMutator, eventType models.TaskEventType, ids []string, changes []models.TaskFieldChange) error {
+	events := s.taskEvents(ctx, eventType, ids, changes)
+	if len(events) == 0 {
+		return nil
+	}
+	return m.AppendTaskEvents(ctx, events)
+}
+
+// taskEvents builds one event of eventType per task id, attributed to the
+// request actor. It returns nil when task history is not configured.
+func (s *TaskService) taskEvents(ctx context.Context, eventType models.TaskEventType, ids []string, changes []models.TaskFieldChange) []models.TaskEvent {
+	if s.events == nil || len(ids) == 0 {
+		return nil
+	}
+	actor := actorFromContext(ctx)
+	now := time.Now().UTC()
+	events := make([]models.TaskEvent, 0, len(ids))
+	for _, id := range uniqueStrings(ids) {
+		events = append(events, models.TaskEvent{
+			TaskID:    id,
+			Type:      eventType,
+			Actor:     actor,
+			Changes:   changes,
+			CreatedAt: now,
+		})
+	}
+	return events
+}
+
+// taskFieldChanges lists the user-visible fields that differ between two task snapshots.
+func taskFieldChanges(before, after models.Task) []models.TaskFieldChange {
+	var changes []models.TaskFieldChange
+	add := func(field string, old, next any) {
+		if !reflect.DeepEqual(old, next) {
+			changes = append(changes, models.TaskFieldChange{Field: field, Old: old, New: next})
+		}
+	}
+	add("title", before.Title, after.Title)
+	add("status", before.Status, after.Status)
+	add("type", before.Type, after.Type)
+	add("priority", before.Priority, after.Priority)
+	add("description", before.Description, after.Description)
+	add("spec_id", before.SpecID, after.SpecID)
+	add("parent_id", before.ParentID, after.ParentID)
+	add("assignee", before.Assignee, after.Assignee)
+	add("notes", before.Notes, after.Notes)
+	add("design", before.Design, after.Design)
+	add("acceptance_criteria", before.AcceptanceCriteria, after.AcceptanceCriteria)
+	add("blocked_reason", before.BlockedReason, after.BlockedReason)
+	add("blocked_on", before.BlockedOn, after.BlockedOn)
+	add("source_repo", before.SourceRepo, after.SourceRepo)
+	add("milestone_id", before.MilestoneID, after.MilestoneID)
+	if len(before.Custom) > 0 || len(after.Custom) > 0 {
+		add("custom", before.Custom, after.Custom)
+	}
+	return changes
+}
+
+// labelChanges returns a labels diff, or nil when the label set is unchanged.
+func labelChanges(before, after []string) []models.TaskFieldChange {
+	if strings.Join(before, ",") == strings.Join(after, ",") {
+		return nil
+	}
+	return []models.TaskFieldChange{{Field: "labels", Old: before, New: after}}
+}
@@ -14,16 +14,25 @@ import (
 	"grns/internal/store"
 )
 
+const (
+	maxGitRefLookupCommits = 10000
+	// gitRelationMergedBy marks the commit that merged a task's branches.
+	gitRelationMergedBy = "merged_by"
+)
+
 var (
 	gitHashRegex        = regexp.MustCompile(`^[0-9a-f]{40}$`)
 	gitRelationRegex    = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
 	gitBuiltinRelations = map[string]struct{}{
-		"design_doc":    {},
-		"implements":    {},
-		"fix_commit":    {},
-		"closed_by":     {},
-		"introduced_by": {},
-		"related":       {},
+		"design_doc":     {},
+		"implements":     {},
+		"fix_commit":     {},
+		"closed_by":      {},
+		"introduced_by":  {},
+		"related":        {},
+		"mentioned_by":   {},
+		"working_branch": {},
+		"merged_by":      {},
 	}
 )
 
@@ -32,6 +41,7 @@ type TaskGitRefService struct {
 	taskStore     store.TaskServiceStore
 	gitRefStore   store.GitRefStore
 	projectPrefix string
+	resolver      GitResolver
 }
 
 // NewTaskGitRefService constructs a TaskGitRefService.
@@ -220,6 +230,46 @@ func (s *TaskGitRefService) Delete(ctx context.Context, id string) error {
 	return s.gitRefStore.DeleteTaskGitRef(ctx, project, id)
 }
 
+// TaskIDsByCommits returns distinct task IDs linked to any of the commits by relation (default closed_by).
+func (s *TaskGitRefService) TaskIDsByCommits(ctx context.Context, req api.TaskGitRefByCommitsRequest) ([]string, error) {
+	if s == nil || s.gitRefStore == nil {
+		return nil, internalError(fmt.Errorf("task git ref service is not configured"))
+	}
+
+	if len(req.Commits) == 0 {
+		return nil, badRequestCode(fmt.Errorf("commits are required"), ErrCodeMissingRequired)
+	}
+	if len(req.Commits) > maxGitRefLookupCommits {
+		return nil, badRequestCode(fmt.Errorf("too many commits (max %d)", maxGitRefLookupCommits), ErrCodeInvalidArgument)
+	}
+	commits := make([]string, 0, len(req.Commits))
+	for _, raw := range req.Commits {
+		commit, err := normalizeGitHash(raw, "commits")
+		if err != nil {
+			return nil, err
+		}
+		if commit == "" {
+			return nil, badRequestCode(fmt.Errorf("commits must not contain empty values"), ErrCodeInvalidArgument)
+		}
+		commits = append(commits, commit)
+	}
+
+	relation := "closed_by"
+	if strings.TrimSpace(req.Relation) != "" {
+		normalized, err := normalizeGitRelation(req.Relation)
+		if err != nil {
+			return nil, err
+		}
+		relation = normalized
+	}
+
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.gitRefStore.ListTaskIDsByCommits(ctx, project, relation, commits)
+}
+
 func (s *TaskGitRefService) ensureTaskExists(ctx context.Context, id string) error {
 	project, err := s.project(ctx)
 	if err != nil {
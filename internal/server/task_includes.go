@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	includeDeps       = "deps"
+	includeDependents = "dependents"
+	includeReadiness  = "readiness"
+	includeComments   = "comments"
+	includeWatchers   = "watchers"
+	includeGit        = "git"
+)
+
+// taskIncludes selects optional sections hydrated onto task responses.
+type taskIncludes struct {
+	Deps       bool
+	Dependents bool
+	Readiness  bool
+	Comments   bool
+	Watchers   bool
+	Git        bool
+}
+
+func parseTaskIncludes(values []string) (taskIncludes, error) {
+	var includes taskIncludes
+	for _, value := range values {
+		switch strings.ToLower(value) {
+		case includeDeps:
+			includes.Deps = true
+		case includeDependents:
+			includes.Dependents = true
+		case includeReadiness:
+			includes.Readiness = true
+		case includeComments:
+			includes.Comments = true
+		case includeWatchers:
+			includes.Watchers = true
+		case includeGit:
+			includes.Git = true
+		default:
+			return taskIncludes{}, badRequestCode(fmt.Errorf("invalid include: %s", value), ErrCodeInvalidQuery)
+		}
+	}
+	return includes, nil
+}
+
+// requestIncludes returns the sections named by ?include=, or defaults when the parameter is absent.
+// An explicit empty ?include= selects no optional sections.
+func requestIncludes(r *http.Request, defaults taskIncludes) (taskIncludes, error) {
+	if !r.URL.Query().Has("include") {
+		return defaults, nil
+	}
+	return parseTaskIncludes(splitCSV(r.URL.Query().Get("include")))
+}
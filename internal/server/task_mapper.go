@@ -2,6 +2,7 @@ package server
 
 import (
 	"fmt"
+	"maps"
 	"strings"
 	"time"
 
@@ -9,9 +10,49 @@ import (
 	"grns/internal/models"
 )
 
+// taskFieldLimits caps free-text field sizes in bytes. Zero disables a limit.
+type taskFieldLimits struct {
+	MaxDescriptionBytes int
+	MaxNotesBytes       int
+}
+
+func (l taskFieldLimits) validate(description, notes *string) error {
+	if err := checkFieldSize("description", description, l.MaxDescriptionBytes); err != nil {
+		return err
+	}
+	return checkFieldSize("notes", notes, l.MaxNotesBytes)
+}
+
+func checkFieldSize(field string, value *string, max int) error {
+	if value == nil || max <= 0 || len(*value) <= max {
+		return nil
+	}
+	return badRequestCode(fmt.Errorf("%s exceeds %d bytes; store large content as an attachment instead", field, max), ErrCodeRequestTooLarge)
+}
+
+// createPayloadLimits caps per-request label and dependency counts on create. Zero disables a limit.
+type createPayloadLimits struct {
+	MaxLabels int
+	MaxDeps   int
+}
+
+// validate rejects oversized create payloads before any per-item validation or existence checks.
+func (l createPayloadLimits) validate(req api.TaskCreateRequest) error {
+	if l.MaxLabels > 0 && len(req.Labels) > l.MaxLabels {
+		return badRequestCode(fmt.Errorf("too many labels: %d exceeds limit of %d", len(req.Labels), l.MaxLabels), ErrCodeInvalidArgument)
+	}
+	if l.MaxDeps > 0 && len(req.Deps) > l.MaxDeps {
+		return badRequestCode(fmt.Errorf("too many deps: %d exceeds limit of %d", len(req.Deps), l.MaxDeps), ErrCodeInvalidArgument)
+	}
+	return nil
+}
+
 // buildTaskUpdateFromRequest maps an API update request to a service patch model.
-func buildTaskUpdateFromRequest(req api.TaskUpdateRequest, updatedAt time.Time) (taskUpdatePatch, error) {
+func buildTaskUpdateFromRequest(req api.TaskUpdateRequest, updatedAt time.Time, limits taskFieldLimits) (taskUpdatePatch, error) {
 	update := taskUpdatePatch{UpdatedAt: updatedAt}
+	if err := limits.validate(req.Description, req.Notes); err != nil {
+		return taskUpdatePatch{}, err
+	}
 
 	if req.Title != nil {
 		trimmed := strings.TrimSpace(*req.Title)
@@ -26,12 +67,27 @@ func buildTaskUpdateFromRequest(req api.TaskUpdateRequest, updatedAt time.Time)
 			return taskUpdatePatch{}, badRequest(err)
 		}
 		update.Status = &status
-		if status == string(models.StatusClosed) {
+		zero := time.Time{}
+		noMerge := ""
+		switch status {
+		case string(models.StatusClosed):
 			closedAt := updatedAt
 			update.ClosedAt = &closedAt
-		} else {
-			zero := time.Time{}
+			update.DeletedAt = &zero
+			update.MergedInto = &noMerge
+		case string(models.StatusTombstone):
+			deletedAt := updatedAt
+			update.DeletedAt = &deletedAt
+		default:
 			update.ClosedAt = &zero
+			update.DeletedAt = &zero
+			update.MergedInto = &noMerge
+		}
+		// Leaving blocked drops the explanation unless the request sets it.
+		if status != string(models.StatusBlocked) {
+			noReason := ""
+			update.BlockedReason = &noReason
+			update.BlockedOn = &noReason
 		}
 	}
 	if req.Type != nil {
@@ -75,6 +131,21 @@ func buildTaskUpdateFromRequest(req api.TaskUpdateRequest, updatedAt time.Time)
 	if req.SourceRepo != nil {
 		update.SourceRepo = req.SourceRepo
 	}
+	if req.MilestoneID != nil {
+		milestoneID := strings.TrimSpace(*req.MilestoneID)
+		if milestoneID != "" && !validateMilestoneID(milestoneID) {
+			return taskUpdatePatch{}, badRequestCode(fmt.Errorf("invalid milestone_id"), ErrCodeInvalidMilestone)
+		}
+		update.MilestoneID = &milestoneID
+	}
+	if req.BlockedReason != nil {
+		reason := strings.TrimSpace(*req.BlockedReason)
+		update.BlockedReason = &reason
+	}
+	if req.BlockedOn != nil {
+		blockedOn := strings.TrimSpace(*req.BlockedOn)
+		update.BlockedOn = &blockedOn
+	}
 	if req.Custom != nil {
 		custom := req.Custom
 		update.Custom = &custom
@@ -97,6 +168,7 @@ func buildTaskUpdateFromImport(rec api.TaskImportRecord) taskUpdatePatch {
 	design := rec.Design
 	acceptanceCriteria := rec.AcceptanceCriteria
 	sourceRepo := rec.SourceRepo
+	milestoneID := rec.MilestoneID
 
 	update := taskUpdatePatch{
 		Title:              &title,
@@ -111,6 +183,7 @@ func buildTaskUpdateFromImport(rec api.TaskImportRecord) taskUpdatePatch {
 		Design:             &design,
 		AcceptanceCriteria: &acceptanceCriteria,
 		SourceRepo:         &sourceRepo,
+		MilestoneID:        &milestoneID,
 		UpdatedAt:          rec.UpdatedAt,
 	}
 
@@ -124,6 +197,22 @@ func buildTaskUpdateFromImport(rec api.TaskImportRecord) taskUpdatePatch {
 		zero := time.Time{}
 		update.ClosedAt = &zero
 	}
+	if rec.Status == string(models.StatusTombstone) {
+		deletedAt := rec.UpdatedAt
+		if rec.DeletedAt != nil {
+			deletedAt = rec.DeletedAt.UTC()
+		}
+		update.DeletedAt = &deletedAt
+	} else {
+		zero := time.Time{}
+		update.DeletedAt = &zero
+	}
+	mergedInto := rec.MergedInto
+	update.MergedInto = &mergedInto
+	blockedReason := rec.BlockedReason
+	update.BlockedReason = &blockedReason
+	blockedOn := rec.BlockedOn
+	update.BlockedOn = &blockedOn
 
 	if rec.Custom != nil {
 		custom := rec.Custom
@@ -132,3 +221,52 @@ func buildTaskUpdateFromImport(rec api.TaskImportRecord) taskUpdatePatch {
 
 	return update
 }
+
+// buildTaskMergeFromImport maps a normalized import record onto an existing task
+// for dedupe=merge. Non-empty text fields from the record replace existing ones;
+// status, type, priority and the lifecycle fields are only taken when the record
+// is newer. Custom keys are merged and updated_at keeps the later timestamp.
+func buildTaskMergeFromImport(existing models.Task, rec api.TaskImportRecord) taskUpdatePatch {
+	update := taskUpdatePatch{UpdatedAt: existing.UpdatedAt}
+	if rec.UpdatedAt.After(existing.UpdatedAt) {
+		newer := buildTaskUpdateFromImport(rec)
+		update.Status = newer.Status
+		update.Type = newer.Type
+		update.Priority = newer.Priority
+		update.ClosedAt = newer.ClosedAt
+		update.DeletedAt = newer.DeletedAt
+		update.MergedInto = newer.MergedInto
+		update.BlockedReason = newer.BlockedReason
+		update.BlockedOn = newer.BlockedOn
+		update.UpdatedAt = rec.UpdatedAt
+	}
+
+	for _, field := range []struct {
+		dst   **string
+		value string
+	}{
+		{&update.Title, rec.Title},
+		{&update.Description, rec.Description},
+		{&update.SpecID, rec.SpecID},
+		{&update.ParentID, rec.ParentID},
+		{&update.Assignee, rec.Assignee},
+		{&update.Notes, rec.Notes},
+		{&update.Design, rec.Design},
+		{&update.AcceptanceCriteria, rec.AcceptanceCriteria},
+		{&update.SourceRepo, rec.SourceRepo},
+		{&update.MilestoneID, rec.MilestoneID},
+	} {
+		if field.value != "" {
+			value := field.value
+			*field.dst = &value
+		}
+	}
+
+	if len(rec.Custom) > 0 {
+		custom := make(map[string]any, len(existing.Custom)+len(rec.Custom))
+		maps.Copy(custom, existing.Custom)
+		maps.Copy(custom, rec.Custom)
+		update.Custom = &custom
+	}
+	return update
+}
@@ -24,7 +24,7 @@ func TestBuildTaskUpdateFromRequest(t *testing.T) {
 			Priority:    &priority,
 			ParentID:    &parentID,
 			Description: &description,
-		}, now)
+		}, now, taskFieldLimits{})
 		if err != nil {
 			t.Fatalf("build update: %v", err)
 		}
@@ -52,7 +52,7 @@ func TestBuildTaskUpdateFromRequest(t *testing.T) {
 	t.Run("rejects invalid parent id", func(t *testing.T) {
 		now := time.Now().UTC()
 		parentID := "bad-id"
-		_, err := buildTaskUpdateFromRequest(api.TaskUpdateRequest{ParentID: &parentID}, now)
+		_, err := buildTaskUpdateFromRequest(api.TaskUpdateRequest{ParentID: &parentID}, now, taskFieldLimits{})
 		if err == nil {
 			t.Fatal("expected error")
 		}
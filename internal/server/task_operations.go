@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+// maxListedOperations caps how many journal entries Operations returns.
+const maxListedOperations = 50
+
+// journalSnapshot loads the tasks an operation is about to change, or nil when
+// the undo journal is not configured.
+func (s *TaskService) journalSnapshot(ctx context.Context, project string, ids []string) ([]models.Task, error) {
+	if s.operations == nil || len(ids) == 0 {
+		return nil, nil
+	}
+	ids = uniqueStrings(ids)
+	return s.store.ListTasks(ctx, store.ListFilter{Project: project, IDs: ids, Limit: len(ids)})
+}
+
+// journal records a completed operation and drops entries that have left the undo window.
+// at must be the updated_at the operation wrote, so undo can detect later edits.
+func (s *TaskService) journal(ctx context.Context, project string, kind models.OperationKind, before []models.Task, label string, ids []string, at time.Time) error {
+	return s.journalOperation(ctx, &models.Operation{
+		Project:   project,
+		Kind:      kind,
+		TaskIDs:   ids,
+		Label:     label,
+		Before:    before,
+		CreatedAt: at,
+	})
+}
+
+// journalOperation records op with the context's actor; see journal.
+func (s *TaskService) journalOperation(ctx context.Context, op *models.Operation) error {
+	if s.operations == nil || len(op.TaskIDs) == 0 {
+		return nil
+	}
+	op.Actor = actorFromContext(ctx)
+	op.TaskIDs = uniqueStrings(op.TaskIDs)
+	if err := s.operations.AppendOperation(ctx, op); err != nil {
+		return err
+	}
+	_, err := s.operations.PruneOperations(ctx, op.CreatedAt.Add(-s.undoWindow))
+	return err
+}
+
+// Operations lists the project's journaled operations that are still inside the undo window, newest first.
+func (s *TaskService) Operations(ctx context.Context) ([]models.Operation, error) {
+	if s.operations == nil {
+		return nil, internalError(fmt.Errorf("undo journal is not configured"))
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.operations.ListOperations(ctx, project, time.Now().UTC().Add(-s.undoWindow), maxListedOperations)
+}
+
+// Undo reverses one journaled operation, or the newest one not yet undone when id is 0.
+// It refuses when the operation has left the undo window, was already undone,
+// or touched a task that has changed since.
+func (s *TaskService) Undo(ctx context.Context, id int64) (models.Operation, error) {
+	if s.operations == nil {
+		return models.Operation{}, internalError(fmt.Errorf("undo journal is not configured"))
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return models.Operation{}, err
+	}
+	op, err := s.findOperation(ctx, project, id)
+	if err != nil {
+		return models.Operation{}, err
+	}
+	now := time.Now().UTC()
+	if op.UndoneAt != nil {
+		return models.Operation{}, conflictCode(fmt.Errorf("operation %d was already undone", op.ID), ErrCodeConflict)
+	}
+	if now.Sub(op.CreatedAt) > s.undoWindow {
+		return models.Operation{}, conflictCode(fmt.Errorf("operation %d is older than the undo window (%s)", op.ID, s.undoWindow), ErrCodeConflict)
+	}
+
+	current, err := s.journalSnapshot(ctx, project, taskIDs(op.Before))
+	if err != nil {
+		return models.Operation{}, err
+	}
+	err = s.operations.UndoOperation(ctx, op, now, s.undoEvents(ctx, op, current))
+	switch {
+	case errors.Is(err, store.ErrOperationUndone):
+		return models.Operation{}, conflictCode(fmt.Errorf("operation %d was already undone", op.ID), ErrCodeConflict)
+	case errors.Is(err, store.ErrOperationConflict):
+		return models.Operation{}, conflictCode(fmt.Errorf("tasks from operation %d changed since; refusing to undo", op.ID), ErrCodeConflict)
+	case errors.Is(err, store.ErrTaskNotFound):
+		return models.Operation{}, conflictCode(fmt.Errorf("a task from operation %d no longer exists", op.ID), ErrCodeConflict)
+	case err != nil:
+		return models.Operation{}, err
+	}
+	op.UndoneAt = &now
+	return *op, nil
+}
+
+func (s *TaskService) findOperation(ctx context.Context, project string, id int64) (*models.Operation, error) {
+	if id < 0 {
+		return nil, badRequestCode(fmt.Errorf("invalid operation id"), ErrCodeInvalidID)
+	}
+	if id > 0 {
+		op, err := s.operations.GetOperation(ctx, project, id)
+		if err != nil {
+			return nil, err
+		}
+		if op == nil {
+			return nil, notFoundCode(fmt.Errorf("operation not found"), ErrCodeOperationNotFound)
+		}
+		return op, nil
+	}
+
+	ops, err := s.operations.ListOperations(ctx, project, time.Now().UTC().Add(-s.undoWindow), maxListedOperations)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ops {
+		if ops[i].UndoneAt == nil {
+			return &ops[i], nil
+		}
+	}
+	return nil, notFoundCode(fmt.Errorf("no operation to undo within %s", s.undoWindow), ErrCodeOperationNotFound)
+}
+
+// undoEvents builds one undone event per task with the fields the undo changes back.
+func (s *TaskService) undoEvents(ctx context.Context, op *models.Operation, current []models.Task) []models.TaskEvent {
+	switch op.Kind {
+	case models.OperationLabelDelete:
+		change := models.TaskFieldChange{Field: "labels", New: []string{op.Label}}
+		return s.taskEvents(ctx, models.TaskEventUndone, op.TaskIDs, []models.TaskFieldChange{change})
+	case models.OperationLabelRename:
+		change := models.TaskFieldChange{Field: "labels", Old: []string{op.RenamedTo}, New: []string{op.Label}}
+		return s.taskEvents(ctx, models.TaskEventUndone, op.TaskIDs, []models.TaskFieldChange{change})
+	}
+	previous := make(map[string]models.Task, len(current))
+	for _, task := range current {
+		previous[task.ID] = task
+	}
+	var events []models.TaskEvent
+	for _, task := range op.Before {
+		events = append(events, s.taskEvents(ctx, models.TaskEventUndone, []string{task.ID}, taskFieldChanges(previous[task.ID], task))...)
+	}
+	return events
+}
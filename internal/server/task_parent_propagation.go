@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+// propagateStatusChange propagates tasks closed or reopened at at to their
+// parent epics and returns the epics it changed, as they were before.
+func (s *TaskService) propagateStatusChange(ctx context.Context, project string, closed, reopened []string, at time.Time) ([]models.Task, error) {
+	parents, err := s.propagateParentClose(ctx, project, closed, at)
+	if err != nil {
+		return nil, err
+	}
+	reopenedParents, err := s.propagateParentReopen(ctx, project, reopened, at)
+	if err != nil {
+		return nil, err
+	}
+	return append(parents, reopenedParents...), nil
+}
+
+// propagateParentClose closes parent epics whose children are now all closed,
+// walking up the parent chain, and returns the closed epics as they were
+// before so callers can journal them. It is a no-op unless
+// auto_close_parents is enabled.
+func (s *TaskService) propagateParentClose(ctx context.Context, project string, ids []string, at time.Time) ([]models.Task, error) {
+	if !s.autoCloseParents {
+		return nil, nil
+	}
+	var closed []models.Task
+	seen := map[string]bool{}
+	for len(ids) > 0 {
+		epics, err := s.parentEpics(ctx, project, ids, seen)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make(map[string]models.Task, len(epics))
+		candidateIDs := make([]string, 0, len(epics))
+		for _, epic := range epics {
+			if epic.Status != string(models.StatusClosed) && epic.Status != string(models.StatusTombstone) {
+				candidates[epic.ID] = epic
+				candidateIDs = append(candidateIDs, epic.ID)
+			}
+		}
+		if len(candidateIDs) == 0 {
+			return closed, nil
+		}
+
+		childCounts, err := s.store.ChildStatusCountsForTasks(ctx, candidateIDs)
+		if err != nil {
+			return nil, err
+		}
+		toClose := make([]string, 0, len(candidateIDs))
+		for _, id := range candidateIDs {
+			summary := childrenSummary(childCounts[id])
+			if summary.Total > 0 && summary.Closed == summary.Total {
+				toClose = append(toClose, id)
+			}
+		}
+		if len(toClose) == 0 {
+			return closed, nil
+		}
+
+		change := models.TaskFieldChange{Field: "reason", New: "all children closed"}
+		err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+			if err := m.CloseTasks(ctx, project, toClose, at); err != nil {
+				return err
+			}
+			return s.recordEvents(ctx, m, models.TaskEventClosed, toClose, []models.TaskFieldChange{change})
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range toClose {
+			closed = append(closed, candidates[id])
+		}
+		ids = toClose
+	}
+	return closed, nil
+}
+
+// propagateParentReopen reopens closed parent epics of reopened tasks, walking
+// up the parent chain, and returns the reopened epics as they were before. It
+// is a no-op unless auto_close_parents is enabled.
+func (s *TaskService) propagateParentReopen(ctx context.Context, project string, ids []string, at time.Time) ([]models.Task, error) {
+	if !s.autoCloseParents {
+		return nil, nil
+	}
+	var reopened []models.Task
+	seen := map[string]bool{}
+	for len(ids) > 0 {
+		epics, err := s.parentEpics(ctx, project, ids, seen)
+		if err != nil {
+			return nil, err
+		}
+		toReopen := make([]string, 0, len(epics))
+		for _, epic := range epics {
+			if epic.Status == string(models.StatusClosed) {
+				toReopen = append(toReopen, epic.ID)
+				reopened = append(reopened, epic)
+			}
+		}
+		if len(toReopen) == 0 {
+			return reopened, nil
+		}
+
+		change := models.TaskFieldChange{Field: "reason", New: "child reopened"}
+		err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+			if err := m.ReopenTasks(ctx, project, toReopen, at); err != nil {
+				return err
+			}
+			return s.recordEvents(ctx, m, models.TaskEventReopened, toReopen, []models.TaskFieldChange{change})
+		})
+		if err != nil {
+			return nil, err
+		}
+		ids = toReopen
+	}
+	return reopened, nil
+}
+
+// parentEpics returns the epic parents of ids within project, skipping parents
+// already visited so a malformed hierarchy cannot loop.
+func (s *TaskService) parentEpics(ctx context.Context, project string, ids []string, seen map[string]bool) ([]models.Task, error) {
+	tasks, err := s.store.ListTasks(ctx, taskListFilter{Project: project, IDs: uniqueStrings(ids)}.toStoreListFilter())
+	if err != nil {
+		return nil, err
+	}
+	parentIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if task.ParentID == "" || seen[task.ParentID] || !taskIDBelongsToProject(task.ParentID, project) {
+			continue
+		}
+		seen[task.ParentID] = true
+		parentIDs = append(parentIDs, task.ParentID)
+	}
+	if len(parentIDs) == 0 {
+		return nil, nil
+	}
+
+	parents, err := s.store.ListTasks(ctx, taskListFilter{Project: project, IDs: parentIDs}.toStoreListFilter())
+	if err != nil {
+		return nil, err
+	}
+	epics := make([]models.Task, 0, len(parents))
+	for _, parent := range parents {
+		if parent.Type == string(models.TypeEpic) {
+			epics = append(epics, parent)
+		}
+	}
+	return epics, nil
+}
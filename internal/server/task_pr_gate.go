@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"grns/internal/models"
+)
+
+// gitRefResolver refreshes a git ref from its hosting provider; TaskGitRefService implements it.
+type gitRefResolver interface {
+	Resolve(ctx context.Context, id string) (models.TaskGitRef, error)
+}
+
+// checkMergedPRs rejects closing tasks with a git ref whose linked pull
+// requests have not merged, when workflow.require_merged_pr is on. A ref links
+// a pull request when meta.pull_requests is non-empty and counts as merged when
+// meta.pr_merged is true. Unmerged refs are re-resolved first if a resolver is
+// configured, so a merge on the provider is picked up without a manual resolve;
+// refs the provider cannot find keep their stored meta.
+func (s *TaskService) checkMergedPRs(ctx context.Context, ids []string) error {
+	if !s.requireMergedPR || len(ids) == 0 {
+		return nil
+	}
+	if s.gitRefs == nil {
+		return internalError(fmt.Errorf("git refs are not configured"))
+	}
+	refMap, err := s.gitRefs.ListTaskGitRefsForTasks(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var unmerged []string
+	for _, id := range ids {
+		for _, ref := range refMap[id] {
+			if !gitRefPRUnmerged(ref) {
+				continue
+			}
+			if s.gitRefResolver != nil && gitRefResolvable(ref) {
+				resolved, err := s.gitRefResolver.Resolve(ctx, ref.ID)
+				switch {
+				case err == nil:
+					ref = resolved
+				case httpStatusFromError(err) >= http.StatusInternalServerError:
+					return err
+				}
+				if !gitRefPRUnmerged(ref) {
+					continue
+				}
+			}
+			unmerged = append(unmerged, fmt.Sprintf("%s (%s)", id, strings.Join(gitRefPullRequestURLs(ref), ", ")))
+		}
+	}
+	if len(unmerged) > 0 {
+		return prNotMerged(fmt.Errorf("pull request not merged for %s", strings.Join(unmerged, "; ")))
+	}
+	return nil
+}
+
+// checkMergedPRStatus applies checkMergedPRs to updates that move tasks to closed.
+func (s *TaskService) checkMergedPRStatus(ctx context.Context, status *string, ids []string) error {
+	if status == nil || *status != string(models.StatusClosed) {
+		return nil
+	}
+	return s.checkMergedPRs(ctx, ids)
+}
+
+func gitRefPRUnmerged(ref models.TaskGitRef) bool {
+	merged, _ := ref.Meta[gitRefPRMerged].(bool)
+	return !merged && len(gitRefPullRequestURLs(ref)) > 0
+}
+
+// gitRefPullRequestURLs reads meta.pull_requests, which is []string when set
+// in-process and []any once it has been stored.
+func gitRefPullRequestURLs(ref models.TaskGitRef) []string {
+	switch urls := ref.Meta[gitRefPullRequests].(type) {
+	case []string:
+		return urls
+	case []any:
+		out := make([]string, 0, len(urls))
+		for _, raw := range urls {
+			if url, ok := raw.(string); ok && url != "" {
+				out = append(out, url)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,414 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"grns/internal/api"
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+// maxRPCOps caps the number of operations accepted in one RPC batch.
+const maxRPCOps = 500
+
+// rpcBatch carries state shared by the ops of one RPC batch: tasks created so
+// far, schema loaded before the transaction opened, and events to record before
+// the batch commits.
+type rpcBatch struct {
+	project      string
+	now          time.Time
+	customFields []models.CustomField
+	created      map[string]bool
+	events       []rpcEvent
+	closed       []string
+	reopened     []string
+}
+
+type rpcEvent struct {
+	eventType models.TaskEventType
+	id        string
+	changes   []models.TaskFieldChange
+}
+
+// RunBatch applies ops in order inside one store transaction and returns one
+// result per op. A failing op rolls back the whole batch; its error names the
+// op index. Checks that read other stores (milestones, custom field schema,
+// merged pull requests) run before the transaction opens.
+func (s *TaskService) RunBatch(ctx context.Context, ops []api.RPCOp) ([]api.RPCResult, error) {
+	if len(ops) == 0 {
+		return nil, badRequestCode(fmt.Errorf("ops array is required"), ErrCodeMissingRequired)
+	}
+	if len(ops) > maxRPCOps {
+		return nil, badRequestCode(fmt.Errorf("too many ops: %d exceeds limit of %d", len(ops), maxRPCOps), ErrCodeInvalidArgument)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &rpcBatch{project: project, now: time.Now().UTC(), created: map[string]bool{}}
+	if s.customFields != nil {
+		batch.customFields, err = s.customFields.ListCustomFields(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var touched []string
+	for i, op := range ops {
+		if err := s.precheckRPCOp(ctx, project, op); err != nil {
+			return nil, rpcOpError(i, op.Op, err)
+		}
+		if op.Op == api.RPCOpUpdate || op.Op == api.RPCOpClose {
+			touched = append(touched, op.ID)
+		}
+	}
+	before, err := s.journalSnapshot(ctx, project, touched)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]api.RPCResult, len(ops))
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		for i, op := range ops {
+			id, labels, err := s.applyRPCOp(ctx, m, batch, op)
+			if err != nil {
+				return rpcOpError(i, op.Op, err)
+			}
+			results[i] = api.RPCResult{Index: i, Op: op.Op, ID: id, Labels: labels}
+		}
+		for _, event := range batch.events {
+			if err := s.recordEvents(ctx, m, event.eventType, []string{event.id}, event.changes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	parents, err := s.propagateStatusChange(ctx, project, batch.closed, batch.reopened, batch.now)
+	if err != nil {
+		return nil, err
+	}
+	journaled := append(before, parents...)
+	if err := s.journal(ctx, project, models.OperationBatch, journaled, "", taskIDs(journaled), batch.now); err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		switch results[i].Op {
+		case api.RPCOpCreate, api.RPCOpUpdate, api.RPCOpClose:
+			resp, err := s.Get(ctx, results[i].ID)
+			if err != nil {
+				return nil, err
+			}
+			results[i].Task = &resp
+		}
+	}
+	return results, nil
+}
+
+// precheckRPCOp validates op shape and runs the checks that need stores outside
+// the batch transaction.
+func (s *TaskService) precheckRPCOp(ctx context.Context, project string, op api.RPCOp) error {
+	switch op.Op {
+	case api.RPCOpCreate:
+		if op.Task == nil {
+			return badRequestCode(fmt.Errorf("task is required"), ErrCodeMissingRequired)
+		}
+		return s.checkMilestone(ctx, project, strings.TrimSpace(valueOrEmpty(op.Task.MilestoneID)))
+	case api.RPCOpUpdate:
+		if op.Update == nil {
+			return badRequestCode(fmt.Errorf("update is required"), ErrCodeMissingRequired)
+		}
+		if err := requireRPCTaskID(op.ID); err != nil {
+			return err
+		}
+		if op.Update.MilestoneID != nil {
+			if err := s.checkMilestone(ctx, project, strings.TrimSpace(*op.Update.MilestoneID)); err != nil {
+				return err
+			}
+		}
+		return s.checkMergedPRStatus(ctx, op.Update.Status, []string{op.ID})
+	case api.RPCOpClose:
+		if err := requireRPCTaskID(op.ID); err != nil {
+			return err
+		}
+		return s.checkMergedPRs(ctx, []string{op.ID})
+	case api.RPCOpAddLabel:
+		if err := requireRPCTaskID(op.ID); err != nil {
+			return err
+		}
+		if len(op.Labels) == 0 {
+			return badRequestCode(fmt.Errorf("labels are required"), ErrCodeMissingRequired)
+		}
+		return nil
+	case api.RPCOpAddDep:
+		if !validateID(op.ID) || !validateID(op.ParentID) {
+			return badRequestCode(fmt.Errorf("invalid dependency ids"), ErrCodeInvalidDependency)
+		}
+		if op.ID == op.ParentID {
+			return badRequestCode(fmt.Errorf("task cannot depend on itself"), ErrCodeInvalidDependency)
+		}
+		return nil
+	case "":
+		return badRequestCode(fmt.Errorf("op is required"), ErrCodeMissingRequired)
+	default:
+		return badRequestCode(fmt.Errorf("unknown op %q", op.Op), ErrCodeInvalidArgument)
+	}
+}
+
+func requireRPCTaskID(id string) error {
+	if strings.TrimSpace(id) == "" {
+		return badRequestCode(fmt.Errorf("id is required"), ErrCodeMissingRequired)
+	}
+	if !validateID(id) {
+		return badRequestCode(fmt.Errorf("invalid id"), ErrCodeInvalidID)
+	}
+	return nil
+}
+
+// applyRPCOp applies one prechecked op through m and returns the affected task
+// id and, for add_label, the resulting label set.
+func (s *TaskService) applyRPCOp(ctx context.Context, m store.ImportMutator, batch *rpcBatch, op api.RPCOp) (string, []string, error) {
+	switch op.Op {
+	case api.RPCOpCreate:
+		id, err := s.applyRPCCreate(ctx, m, batch, *op.Task)
+		return id, nil, err
+	case api.RPCOpUpdate:
+		return op.ID, nil, s.applyRPCUpdate(ctx, m, batch, op.ID, *op.Update)
+	case api.RPCOpClose:
+		return op.ID, nil, s.applyRPCClose(ctx, m, batch, op.ID)
+	case api.RPCOpAddLabel:
+		labels, err := s.applyRPCAddLabel(ctx, m, batch, op.ID, op.Labels)
+		return op.ID, labels, err
+	case api.RPCOpAddDep:
+		return op.ID, nil, s.applyRPCAddDep(ctx, m, batch, op.ID, op.ParentID, op.DepType)
+	default:
+		return "", nil, badRequestCode(fmt.Errorf("unknown op %q", op.Op), ErrCodeInvalidArgument)
+	}
+}
+
+func (s *TaskService) applyRPCCreate(ctx context.Context, m store.ImportMutator, batch *rpcBatch, req api.TaskCreateRequest) (string, error) {
+	exists := func(id string) (bool, error) {
+		if batch.created[id] {
+			return true, nil
+		}
+		return m.TaskExists(id)
+	}
+	prepared, err := s.prepareCreateRequest(batch.project, req, exists, batch.now)
+	if err != nil {
+		return "", err
+	}
+	if parentID := prepared.task.ParentID; parentID != "" {
+		if parentID == prepared.task.ID {
+			return "", badRequestCode(fmt.Errorf("task cannot be its own parent"), ErrCodeInvalidArgument)
+		}
+		if err := rpcRequireTask(m, batch, parentID, badRequestCode(fmt.Errorf("invalid parent_id"), ErrCodeInvalidParentID)); err != nil {
+			return "", err
+		}
+	}
+	if s.customFields != nil {
+		custom, err := applyCustomFieldSchema(batch.customFields, prepared.task.Custom, true)
+		if err != nil {
+			return "", err
+		}
+		prepared.task.Custom = custom
+	}
+	if err := s.validateDependencyParents(prepared.deps, batch.created, m.TaskExists); err != nil {
+		return "", err
+	}
+
+	if err := m.CreateTask(ctx, prepared.task, prepared.labels, prepared.deps); err != nil {
+		if isUniqueConstraint(err) {
+			return "", conflictCode(fmt.Errorf("id already exists"), ErrCodeTaskIDExists)
+		}
+		if isForeignKeyConstraint(err) {
+			return "", badRequestCode(fmt.Errorf("invalid dependency parent_id"), ErrCodeInvalidDependency)
+		}
+		return "", err
+	}
+	batch.created[prepared.task.ID] = true
+	batch.events = append(batch.events, rpcEvent{eventType: models.TaskEventCreated, id: prepared.task.ID})
+	return prepared.task.ID, nil
+}
+
+func (s *TaskService) applyRPCUpdate(ctx context.Context, m store.ImportMutator, batch *rpcBatch, id string, req api.TaskUpdateRequest) error {
+	current, err := rpcCurrentTask(ctx, m, batch.project, id)
+	if err != nil {
+		return err
+	}
+	update, err := buildTaskUpdateFromRequest(req, batch.now, s.fieldLimits)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkTaskUpdate(*current, update); err != nil {
+		return err
+	}
+	if update.ParentID != nil {
+		if err := rpcCheckParentHierarchy(ctx, m, batch, id, *update.ParentID); err != nil {
+			return err
+		}
+	}
+	if update.Custom != nil && s.customFields != nil {
+		custom, err := applyCustomFieldSchema(batch.customFields, *update.Custom, true)
+		if err != nil {
+			return err
+		}
+		update.Custom = &custom
+	}
+
+	if err := s.writeTaskUpdate(ctx, m, *current, update, req.Force); err != nil {
+		return err
+	}
+	closed, reopened := statusTransition(*current, update)
+	batch.closed = append(batch.closed, closed...)
+	batch.reopened = append(batch.reopened, reopened...)
+
+	after, err := m.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if changes := taskFieldChanges(*current, *after); len(changes) > 0 {
+		batch.events = append(batch.events, rpcEvent{eventType: models.TaskEventUpdated, id: id, changes: changes})
+	}
+	return nil
+}
+
+func (s *TaskService) applyRPCClose(ctx context.Context, m store.ImportMutator, batch *rpcBatch, id string) error {
+	if _, err := rpcCurrentTask(ctx, m, batch.project, id); err != nil {
+		return err
+	}
+	if err := m.UpdateTask(ctx, id, closeTaskUpdate(batch.now)); err != nil {
+		return err
+	}
+	batch.closed = append(batch.closed, id)
+	batch.events = append(batch.events, rpcEvent{eventType: models.TaskEventClosed, id: id})
+	return nil
+}
+
+func (s *TaskService) applyRPCAddLabel(ctx context.Context, m store.ImportMutator, batch *rpcBatch, id string, labels []string) ([]string, error) {
+	if _, err := rpcCurrentTask(ctx, m, batch.project, id); err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeLabels(labels)
+	if err != nil {
+		return nil, badRequest(err)
+	}
+	before, err := m.ListLabels(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	merged := uniqueStrings(append(slices.Clone(before), normalized...))
+	slices.Sort(merged)
+	if err := m.ReplaceLabels(ctx, id, merged); err != nil {
+		return nil, err
+	}
+	after, err := m.ListLabels(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if changes := labelChanges(before, after); changes != nil {
+		batch.events = append(batch.events, rpcEvent{eventType: models.TaskEventLabelsAdded, id: id, changes: changes})
+	}
+	return after, nil
+}
+
+func (s *TaskService) applyRPCAddDep(ctx context.Context, m store.ImportMutator, batch *rpcBatch, childID, parentID, depType string) error {
+	if !taskIDBelongsToProject(childID, batch.project) {
+		return badRequestCode(fmt.Errorf("invalid dependency ids"), ErrCodeInvalidDependency)
+	}
+	if _, err := rpcCurrentTask(ctx, m, batch.project, childID); err != nil {
+		return err
+	}
+	if err := rpcRequireTask(m, batch, parentID, notFoundCode(fmt.Errorf("dependency parent not found"), ErrCodeTaskNotFound)); err != nil {
+		return err
+	}
+	depType, err := normalizeDependencyType(depType)
+	if err != nil {
+		return err
+	}
+	if err := m.AddDependency(ctx, childID, parentID, depType); err != nil {
+		return err
+	}
+	change := models.TaskFieldChange{Field: "deps", New: models.Dependency{ParentID: parentID, Type: depType}}
+	batch.events = append(batch.events, rpcEvent{eventType: models.TaskEventDepAdded, id: childID, changes: []models.TaskFieldChange{change}})
+	return nil
+}
+
+// rpcCurrentTask loads a task of project as seen inside the batch transaction.
+func rpcCurrentTask(ctx context.Context, m store.ImportMutator, project, id string) (*models.Task, error) {
+	if !taskIDBelongsToProject(id, project) {
+		return nil, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	task, err := m.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	return task, nil
+}
+
+// rpcRequireTask returns missing unless id was created earlier in the batch or already exists.
+func rpcRequireTask(m store.ImportMutator, batch *rpcBatch, id string, missing error) error {
+	if batch.created[id] {
+		return nil
+	}
+	exists, err := m.TaskExists(id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return missing
+	}
+	return nil
+}
+
+// rpcCheckParentHierarchy is checkParentHierarchy evaluated against the batch
+// transaction, so parents created or re-parented earlier in the batch count.
+func rpcCheckParentHierarchy(ctx context.Context, m store.ImportMutator, batch *rpcBatch, id, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+	if parentID == id {
+		return badRequestCode(fmt.Errorf("task cannot be its own parent"), ErrCodeInvalidArgument)
+	}
+	if err := rpcRequireTask(m, batch, parentID, badRequestCode(fmt.Errorf("invalid parent_id"), ErrCodeInvalidParentID)); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for ancestor := parentID; ancestor != "" && !seen[ancestor]; {
+		if ancestor == id {
+			return badRequestCode(fmt.Errorf("parent_id %s is a descendant of %s; parent hierarchy must be acyclic", parentID, id), ErrCodeInvalidArgument)
+		}
+		seen[ancestor] = true
+		task, err := m.GetTask(ctx, ancestor)
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			break
+		}
+		ancestor = task.ParentID
+	}
+	return nil
+}
+
+// rpcOpError prefixes err with the failing op's index, keeping its status and code.
+func rpcOpError(index int, op string, err error) error {
+	var apiErr apiError
+	if errors.As(err, &apiErr) && apiErr.status != 0 {
+		apiErr.err = fmt.Errorf("ops[%d] %s: %w", index, op, apiErr.err)
+		return apiErr
+	}
+	return err
+}
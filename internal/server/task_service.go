@@ -1,9 +1,12 @@
 package server
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,6 +20,33 @@ type TaskService struct {
 	store         store.TaskServiceStore
 	projectPrefix string
 	importer      *Importer
+	comments      store.CommentStore
+	worklogs      store.WorklogStore
+	watchers      store.WatcherStore
+	events        store.EventStore
+	milestones    store.MilestoneStore
+	leases        store.LeaseStore
+	customFields  store.CustomFieldStore
+	operations    store.OperationStore
+	changes       store.ChangeFeedStore
+	attachments   store.AttachmentStore
+	gitRefs       store.GitRefStore
+	importReports store.ImportReportStore
+
+	wipLimits            map[string]int
+	wipLimitsPerAssignee bool
+	assigneeWIPLimit     int
+	parentImpliesBlocks  bool
+	autoCloseParents     bool
+	requireBlockedReason bool
+	requireMergedPR      bool
+	gitRefResolver       gitRefResolver
+	fieldLimits          taskFieldLimits
+	createLimits         createPayloadLimits
+	maxCloseByFilter     int
+	undoWindow           time.Duration
+
+	requireAssigneeStatuses map[string]bool
 }
 
 // NewTaskService constructs a TaskService.
@@ -25,9 +55,221 @@ func NewTaskService(store store.TaskServiceStore, projectPrefix string) *TaskSer
 		store:         store,
 		projectPrefix: projectPrefix,
 		importer:      NewImporter(store),
+		fieldLimits: taskFieldLimits{
+			MaxDescriptionBytes: defaultMaxDescriptionBytes,
+			MaxNotesBytes:       defaultMaxNotesBytes,
+		},
+		createLimits: createPayloadLimits{
+			MaxLabels: defaultCreateMaxLabels,
+			MaxDeps:   defaultCreateMaxDeps,
+		},
+		maxCloseByFilter: defaultCloseMaxByFilter,
+		undoWindow:       defaultUndoWindow,
 	}
 }
 
+// ConfigureFieldLimits overrides free-text field size limits. Non-positive values keep defaults.
+func (s *TaskService) ConfigureFieldLimits(maxDescriptionBytes, maxNotesBytes int) {
+	if s == nil {
+		return
+	}
+	if maxDescriptionBytes > 0 {
+		s.fieldLimits.MaxDescriptionBytes = maxDescriptionBytes
+	}
+	if maxNotesBytes > 0 {
+		s.fieldLimits.MaxNotesBytes = maxNotesBytes
+	}
+}
+
+// ConfigureCreateLimits overrides per-request label and dependency caps on create. Non-positive values keep defaults.
+func (s *TaskService) ConfigureCreateLimits(maxLabels, maxDeps int) {
+	if s == nil {
+		return
+	}
+	if maxLabels > 0 {
+		s.createLimits.MaxLabels = maxLabels
+	}
+	if maxDeps > 0 {
+		s.createLimits.MaxDeps = maxDeps
+	}
+}
+
+// ConfigureCloseLimits overrides the close-by-filter cap. Non-positive values keep the default.
+func (s *TaskService) ConfigureCloseLimits(maxByFilter int) {
+	if s == nil {
+		return
+	}
+	if maxByFilter > 0 {
+		s.maxCloseByFilter = maxByFilter
+	}
+}
+
+// ConfigureUndoWindow overrides how long journaled operations stay undoable. Non-positive values keep the default.
+func (s *TaskService) ConfigureUndoWindow(window time.Duration) {
+	if s == nil {
+		return
+	}
+	if window > 0 {
+		s.undoWindow = window
+	}
+}
+
+// ConfigureWIPLimits sets per-status WIP caps enforced on status transitions.
+func (s *TaskService) ConfigureWIPLimits(limits map[string]int, perAssignee bool) {
+	if s == nil {
+		return
+	}
+	normalized := map[string]int{}
+	for rawStatus, max := range limits {
+		status, err := normalizeStatus(rawStatus)
+		if err != nil || max <= 0 {
+			continue
+		}
+		normalized[status] = max
+	}
+	if len(normalized) == 0 {
+		normalized = nil
+	}
+	s.wipLimits = normalized
+	s.wipLimitsPerAssignee = perAssignee
+}
+
+// ConfigureAssigneeWIPLimit caps how many in_progress tasks one assignee may
+// hold. Non-positive values disable the cap.
+func (s *TaskService) ConfigureAssigneeWIPLimit(limit int) {
+	if s == nil {
+		return
+	}
+	s.assigneeWIPLimit = max(limit, 0)
+}
+
+// ConfigureParentImpliesBlocks toggles automatic parent→child blocks edges.
+func (s *TaskService) ConfigureParentImpliesBlocks(enabled bool) {
+	if s == nil {
+		return
+	}
+	s.parentImpliesBlocks = enabled
+}
+
+// ConfigureAutoCloseParents toggles closing epics whose children are all closed
+// and reopening them when a child reopens.
+func (s *TaskService) ConfigureAutoCloseParents(enabled bool) {
+	if s == nil {
+		return
+	}
+	s.autoCloseParents = enabled
+}
+
+// ConfigureRequireBlockedReason toggles rejecting blocked tasks without a blocked_reason.
+func (s *TaskService) ConfigureRequireBlockedReason(enabled bool) {
+	if s == nil {
+		return
+	}
+	s.requireBlockedReason = enabled
+}
+
+// ConfigureRequireMergedPR toggles rejecting closes of tasks whose linked pull request has not merged.
+func (s *TaskService) ConfigureRequireMergedPR(enabled bool) {
+	if s == nil {
+		return
+	}
+	s.requireMergedPR = enabled
+}
+
+// ConfigureRequireAssigneeStatuses sets statuses that tasks may only enter with an assignee.
+// Unknown statuses are ignored.
+func (s *TaskService) ConfigureRequireAssigneeStatuses(statuses []string) {
+	if s == nil {
+		return
+	}
+	normalized := make(map[string]bool, len(statuses))
+	for _, raw := range statuses {
+		status, err := normalizeStatus(raw)
+		if err != nil {
+			continue
+		}
+		normalized[status] = true
+	}
+	if len(normalized) == 0 {
+		normalized = nil
+	}
+	s.requireAssigneeStatuses = normalized
+}
+
+// checkBlockedReason rejects a blocked explanation on a task that is not blocked
+// and, when required, a blocked task without a blocked_reason.
+func (s *TaskService) checkBlockedReason(status, reason, blockedOn string) error {
+	if status != string(models.StatusBlocked) {
+		if reason != "" || blockedOn != "" {
+			return badRequestCode(fmt.Errorf("blocked_reason and blocked_on require status blocked"), ErrCodeInvalidArgument)
+		}
+		return nil
+	}
+	if s.requireBlockedReason && reason == "" {
+		return badRequestCode(fmt.Errorf("blocked_reason is required for status blocked"), ErrCodeMissingRequired)
+	}
+	return nil
+}
+
+// checkBlockedUpdate applies checkBlockedReason to the task as update would leave it.
+func (s *TaskService) checkBlockedUpdate(current models.Task, update taskUpdatePatch) error {
+	status, reason, blockedOn := current.Status, current.BlockedReason, current.BlockedOn
+	if update.Status != nil {
+		status = *update.Status
+	}
+	if update.BlockedReason != nil {
+		reason = *update.BlockedReason
+	}
+	if update.BlockedOn != nil {
+		blockedOn = *update.BlockedOn
+	}
+	return s.checkBlockedReason(status, reason, blockedOn)
+}
+
+// checkRequiredAssignee rejects entering a status that requires an assignee without one.
+func (s *TaskService) checkRequiredAssignee(status, assignee string) error {
+	if !s.requireAssigneeStatuses[status] || strings.TrimSpace(assignee) != "" {
+		return nil
+	}
+	return badRequestCode(fmt.Errorf("assignee is required for status %s", status), ErrCodeMissingRequired)
+}
+
+// wipLimitsFor returns the WIP limits that apply to moving a task into status:
+// the configured per-status limit and, for in_progress, the per-assignee cap.
+func (s *TaskService) wipLimitsFor(status *string) []store.WIPLimit {
+	if status == nil {
+		return nil
+	}
+	var limits []store.WIPLimit
+	if max, ok := s.wipLimits[*status]; ok {
+		limits = append(limits, store.WIPLimit{Status: *status, Max: max, PerAssignee: s.wipLimitsPerAssignee})
+	}
+	if *status == string(models.StatusInProgress) && s.assigneeWIPLimit > 0 {
+		limits = append(limits, store.WIPLimit{Status: *status, Max: s.assigneeWIPLimit, PerAssignee: true, AssigneeCap: true})
+	}
+	return limits
+}
+
+// wipLimitsForUpdate returns the WIP limits an update must respect. A status
+// change uses wipLimitsFor; an assignee-only change moves the task into
+// another assignee's slot of its current status, so every per-assignee limit
+// applies and the store skips those for other statuses.
+func (s *TaskService) wipLimitsForUpdate(update taskUpdatePatch) []store.WIPLimit {
+	if update.Status != nil || update.Assignee == nil {
+		return s.wipLimitsFor(update.Status)
+	}
+	var limits []store.WIPLimit
+	if s.wipLimitsPerAssignee {
+		for _, status := range slices.Sorted(maps.Keys(s.wipLimits)) {
+			limits = append(limits, store.WIPLimit{Status: status, Max: s.wipLimits[status], PerAssignee: true})
+		}
+	}
+	if s.assigneeWIPLimit > 0 {
+		limits = append(limits, store.WIPLimit{Status: string(models.StatusInProgress), Max: s.assigneeWIPLimit, PerAssignee: true, AssigneeCap: true})
+	}
+	return limits
+}
+
 // Create creates a task from a request.
 func (s *TaskService) Create(ctx context.Context, req api.TaskCreateRequest) (api.TaskResponse, error) {
 	prefix, err := s.project(ctx)
@@ -40,16 +282,35 @@ func (s *TaskService) Create(ctx context.Context, req api.TaskCreateRequest) (ap
 		return api.TaskResponse{}, err
 	}
 
+	if err := s.checkParentHierarchy(ctx, prepared.task.ID, prepared.task.ParentID); err != nil {
+		return api.TaskResponse{}, err
+	}
+	if err := s.checkMilestone(ctx, prefix, prepared.task.MilestoneID); err != nil {
+		return api.TaskResponse{}, err
+	}
+	custom, err := s.applyCustomFields(ctx, prefix, prepared.task.Custom, true)
+	if err != nil {
+		return api.TaskResponse{}, err
+	}
+	prepared.task.Custom = custom
+	prepared.response.Custom = custom
+
 	createdIDs := map[string]bool{prepared.task.ID: true}
 	if err := s.validateDependencyParents(prepared.deps, createdIDs, s.store.TaskExists); err != nil {
 		return api.TaskResponse{}, err
 	}
 
-	if err := s.store.CreateTask(ctx, prepared.task, prepared.labels, prepared.deps); err != nil {
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.CreateTask(ctx, prepared.task, prepared.labels, prepared.deps); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventCreated, []string{prepared.task.ID}, nil)
+	})
+	if err != nil {
 		if isUniqueConstraint(err) {
 			return api.TaskResponse{}, conflictCode(fmt.Errorf("id already exists"), ErrCodeTaskIDExists)
 		}
-		if isForeignKeyConstraint(err) || errors.Is(err, store.ErrProjectMismatch) {
+		if isForeignKeyConstraint(err) {
 			return api.TaskResponse{}, badRequestCode(fmt.Errorf("invalid dependency parent_id"), ErrCodeInvalidDependency)
 		}
 		return api.TaskResponse{}, err
@@ -60,70 +321,152 @@ func (s *TaskService) Create(ctx context.Context, req api.TaskCreateRequest) (ap
 
 // BatchCreate creates tasks in a single transaction.
 func (s *TaskService) BatchCreate(ctx context.Context, reqs []api.TaskCreateRequest) ([]api.TaskResponse, error) {
-	if len(reqs) == 0 {
-		return nil, badRequestCode(fmt.Errorf("tasks array is required"), ErrCodeMissingRequired)
+	batch, err := s.newTaskBatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range reqs {
+		if err := batch.add(req); err != nil {
+			return nil, err
+		}
 	}
+	return batch.commit()
+}
+
+// taskBatch validates create requests one at a time as they arrive, so a
+// streamed batch fails on its first bad item, and creates them all at once on
+// commit. Checks that need the whole batch, such as parent cycles and
+// dependencies on later items, run on commit.
+type taskBatch struct {
+	s               *TaskService
+	ctx             context.Context
+	prefix          string
+	reservedIDs     map[string]bool
+	taskExistsCache map[string]bool
+	// customFields is the project's schema, loaded once for the batch.
+	customFields      []models.CustomField
+	checkedMilestones map[string]bool
+	prepared          []preparedTaskCreate
+}
 
+func (s *TaskService) newTaskBatch(ctx context.Context) (*taskBatch, error) {
 	prefix, err := s.project(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	reservedIDs := make(map[string]bool, len(reqs))
-	taskExistsCache := make(map[string]bool)
-	exists := func(id string) (bool, error) {
-		if reservedIDs[id] {
-			return true, nil
-		}
-		if cached, ok := taskExistsCache[id]; ok {
-			return cached, nil
-		}
-		found, err := s.store.TaskExists(id)
+	batch := &taskBatch{
+		s:                 s,
+		ctx:               ctx,
+		prefix:            prefix,
+		reservedIDs:       map[string]bool{},
+		taskExistsCache:   map[string]bool{},
+		checkedMilestones: map[string]bool{},
+	}
+	if s.customFields != nil {
+		batch.customFields, err = s.customFields.ListCustomFields(ctx, prefix)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
-		taskExistsCache[id] = found
-		return found, nil
 	}
+	return batch, nil
+}
 
-	existsInStore := func(id string) (bool, error) {
-		if cached, ok := taskExistsCache[id]; ok {
-			return cached, nil
-		}
-		found, err := s.store.TaskExists(id)
-		if err != nil {
-			return false, err
+// existsInStore caches store lookups for the batch.
+func (b *taskBatch) existsInStore(id string) (bool, error) {
+	if cached, ok := b.taskExistsCache[id]; ok {
+		return cached, nil
+	}
+	found, err := b.s.store.TaskExists(id)
+	if err != nil {
+		return false, err
+	}
+	b.taskExistsCache[id] = found
+	return found, nil
+}
+
+// exists also treats ids taken earlier in the batch as existing.
+func (b *taskBatch) exists(id string) (bool, error) {
+	if b.reservedIDs[id] {
+		return true, nil
+	}
+	return b.existsInStore(id)
+}
+
+// addAt adds req and prefixes a validation error with its position.
+func (b *taskBatch) addAt(index int, req api.TaskCreateRequest) error {
+	if err := b.add(req); err != nil {
+		var apiErr apiError
+		if errors.As(err, &apiErr) && apiErr.status != 0 {
+			apiErr.err = fmt.Errorf("tasks[%d]: %w", index, apiErr.err)
+			return apiErr
 		}
-		taskExistsCache[id] = found
-		return found, nil
+		return err
 	}
+	return nil
+}
 
-	preparedBatch := make([]preparedTaskCreate, 0, len(reqs))
-	for _, req := range reqs {
-		prepared, err := s.prepareCreateRequest(prefix, req, exists, time.Now().UTC())
+func (b *taskBatch) add(req api.TaskCreateRequest) error {
+	prepared, err := b.s.prepareCreateRequest(b.prefix, req, b.exists, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if milestoneID := prepared.task.MilestoneID; !b.checkedMilestones[milestoneID] {
+		if err := b.s.checkMilestone(b.ctx, b.prefix, milestoneID); err != nil {
+			return err
+		}
+		b.checkedMilestones[milestoneID] = true
+	}
+	if b.s.customFields != nil {
+		custom, err := applyCustomFieldSchema(b.customFields, prepared.task.Custom, true)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		reservedIDs[prepared.task.ID] = true
-		taskExistsCache[prepared.task.ID] = true
-		preparedBatch = append(preparedBatch, prepared)
+		prepared.task.Custom = custom
+		prepared.response.Custom = custom
+	}
+
+	b.reservedIDs[prepared.task.ID] = true
+	b.taskExistsCache[prepared.task.ID] = true
+	b.prepared = append(b.prepared, prepared)
+	return nil
+}
+
+func (b *taskBatch) commit() ([]api.TaskResponse, error) {
+	if len(b.prepared) == 0 {
+		return nil, badRequestCode(fmt.Errorf("tasks array is required"), ErrCodeMissingRequired)
+	}
+	if err := checkBatchParentCycles(b.prepared); err != nil {
+		return nil, err
 	}
 
-	batch := make([]store.TaskCreateInput, 0, len(reqs))
-	responses := make([]api.TaskResponse, 0, len(reqs))
-	for _, prepared := range preparedBatch {
-		if err := s.validateDependencyParents(prepared.deps, reservedIDs, existsInStore); err != nil {
+	batch := make([]store.TaskCreateInput, 0, len(b.prepared))
+	responses := make([]api.TaskResponse, 0, len(b.prepared))
+	for _, prepared := range b.prepared {
+		if err := b.s.checkParentHierarchy(b.ctx, prepared.task.ID, prepared.task.ParentID); err != nil {
+			return nil, err
+		}
+		if err := b.s.validateDependencyParents(prepared.deps, b.reservedIDs, b.existsInStore); err != nil {
 			return nil, err
 		}
 		batch = append(batch, store.TaskCreateInput{Task: prepared.task, Labels: prepared.labels, Deps: prepared.deps})
 		responses = append(responses, prepared.response)
 	}
 
-	if err := s.store.CreateTasks(ctx, batch); err != nil {
+	createdIDs := make([]string, 0, len(batch))
+	for _, input := range batch {
+		createdIDs = append(createdIDs, input.Task.ID)
+	}
+	err := b.s.store.RunInTx(b.ctx, func(m store.ImportMutator) error {
+		if err := m.CreateTasks(b.ctx, batch); err != nil {
+			return err
+		}
+		return b.s.recordEvents(b.ctx, m, models.TaskEventCreated, createdIDs, nil)
+	})
+	if err != nil {
 		if isUniqueConstraint(err) {
 			return nil, conflictCode(fmt.Errorf("id already exists"), ErrCodeTaskIDExists)
 		}
-		if isForeignKeyConstraint(err) || errors.Is(err, store.ErrProjectMismatch) {
+		if isForeignKeyConstraint(err) {
 			return nil, badRequestCode(fmt.Errorf("invalid dependency parent_id"), ErrCodeInvalidDependency)
 		}
 		return nil, err
@@ -143,10 +486,15 @@ func (s *TaskService) prepareCreateRequest(prefix string, req api.TaskCreateRequ
 	if strings.TrimSpace(req.Title) == "" {
 		return preparedTaskCreate{}, badRequestCode(fmt.Errorf("title is required"), ErrCodeMissingRequired)
 	}
+	if err := s.createLimits.validate(req); err != nil {
+		return preparedTaskCreate{}, err
+	}
+
+	validation := &lenientValidation{enabled: req.Lenient}
 
 	status := string(models.StatusOpen)
 	if req.Status != nil {
-		value, err := normalizeStatus(*req.Status)
+		value, err := validation.status(*req.Status, status)
 		if err != nil {
 			return preparedTaskCreate{}, badRequest(err)
 		}
@@ -155,7 +503,7 @@ func (s *TaskService) prepareCreateRequest(prefix string, req api.TaskCreateRequ
 
 	taskType := string(models.TypeTask)
 	if req.Type != nil {
-		value, err := normalizeType(*req.Type)
+		value, err := validation.taskType(*req.Type, taskType)
 		if err != nil {
 			return preparedTaskCreate{}, badRequest(err)
 		}
@@ -164,17 +512,30 @@ func (s *TaskService) prepareCreateRequest(prefix string, req api.TaskCreateRequ
 
 	priority := models.DefaultPriority
 	if req.Priority != nil {
-		if !models.IsValidPriority(*req.Priority) {
-			return preparedTaskCreate{}, badRequestCode(fmt.Errorf("priority must be between %d and %d", models.PriorityMin, models.PriorityMax), ErrCodeInvalidPriority)
+		value, err := validation.priority(*req.Priority)
+		if err != nil {
+			return preparedTaskCreate{}, err
 		}
-		priority = *req.Priority
+		priority = value
 	}
 
-	labels, err := normalizeLabels(req.Labels)
+	labels, err := validation.labels(req.Labels)
 	if err != nil {
 		return preparedTaskCreate{}, badRequest(err)
 	}
 
+	if err := s.fieldLimits.validate(req.Description, req.Notes); err != nil {
+		return preparedTaskCreate{}, err
+	}
+	if err := s.checkRequiredAssignee(status, valueOrEmpty(req.Assignee)); err != nil {
+		return preparedTaskCreate{}, err
+	}
+	blockedReason := strings.TrimSpace(valueOrEmpty(req.BlockedReason))
+	blockedOn := strings.TrimSpace(valueOrEmpty(req.BlockedOn))
+	if err := s.checkBlockedReason(status, blockedReason, blockedOn); err != nil {
+		return preparedTaskCreate{}, err
+	}
+
 	id := strings.TrimSpace(req.ID)
 	if id != "" {
 		if !validateID(id) || !strings.HasPrefix(id, prefix+"-") {
@@ -210,12 +571,20 @@ func (s *TaskService) prepareCreateRequest(prefix string, req api.TaskCreateRequ
 		if parent == "" || !validateID(parent) {
 			return preparedTaskCreate{}, badRequestCode(fmt.Errorf("invalid dependency parent_id"), ErrCodeInvalidDependency)
 		}
-		depType := strings.TrimSpace(dep.Type)
-		if depType == "" {
-			depType = string(models.DependencyBlocks)
+		depType, err := normalizeDependencyType(dep.Type)
+		if err != nil {
+			return preparedTaskCreate{}, err
 		}
 		deps = append(deps, models.Dependency{ParentID: parent, Type: depType})
 	}
+	if s.parentImpliesBlocks && parentID != "" && parentID != id {
+		deps = appendBlocksDependency(deps, parentID)
+	}
+
+	milestoneID := strings.TrimSpace(valueOrEmpty(req.MilestoneID))
+	if milestoneID != "" && !validateMilestoneID(milestoneID) {
+		return preparedTaskCreate{}, badRequestCode(fmt.Errorf("invalid milestone_id"), ErrCodeInvalidMilestone)
+	}
 
 	task := &models.Task{
 		Project:            prefix,
@@ -232,6 +601,9 @@ func (s *TaskService) prepareCreateRequest(prefix string, req api.TaskCreateRequ
 		Design:             valueOrEmpty(req.Design),
 		AcceptanceCriteria: valueOrEmpty(req.AcceptanceCriteria),
 		SourceRepo:         valueOrEmpty(req.SourceRepo),
+		MilestoneID:        milestoneID,
+		BlockedReason:      blockedReason,
+		BlockedOn:          blockedOn,
 		Custom:             req.Custom,
 		CreatedAt:          now,
 		UpdatedAt:          now,
@@ -239,15 +611,112 @@ func (s *TaskService) prepareCreateRequest(prefix string, req api.TaskCreateRequ
 	if status == string(models.StatusClosed) {
 		task.ClosedAt = &now
 	}
+	if status == string(models.StatusTombstone) {
+		task.DeletedAt = &now
+	}
 
 	return preparedTaskCreate{
 		task:     task,
 		labels:   labels,
 		deps:     deps,
-		response: api.TaskResponse{Task: *task, Labels: labels, Deps: deps},
+		response: api.TaskResponse{Task: *task, Labels: labels, Deps: deps, Warnings: validation.warnings},
 	}, nil
 }
 
+func (s *TaskService) validateParentForBlocks(id, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+	if parentID == id || taskIDProjectPrefix(parentID) != taskIDProjectPrefix(id) {
+		return badRequestCode(fmt.Errorf("invalid parent_id"), ErrCodeInvalidParentID)
+	}
+	exists, err := s.store.TaskExists(parentID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return badRequestCode(fmt.Errorf("invalid parent_id"), ErrCodeInvalidParentID)
+	}
+	return nil
+}
+
+// checkParentHierarchy rejects a parent_id that would make the task its own ancestor.
+func (s *TaskService) checkParentHierarchy(ctx context.Context, id, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+	if parentID == id {
+		return badRequestCode(fmt.Errorf("task cannot be its own parent"), ErrCodeInvalidArgument)
+	}
+	descendant, err := s.store.IsTaskDescendant(ctx, id, parentID)
+	if err != nil {
+		return err
+	}
+	if descendant {
+		return badRequestCode(fmt.Errorf("parent_id %s is a descendant of %s; parent hierarchy must be acyclic", parentID, id), ErrCodeInvalidArgument)
+	}
+	return nil
+}
+
+// checkMilestone rejects a milestone_id that does not name a milestone in the project.
+func (s *TaskService) checkMilestone(ctx context.Context, project, milestoneID string) error {
+	if milestoneID == "" {
+		return nil
+	}
+	if s.milestones == nil {
+		return badRequestCode(fmt.Errorf("milestones are not configured"), ErrCodeInvalidMilestone)
+	}
+	milestone, err := s.milestones.GetMilestone(ctx, project, milestoneID)
+	if err != nil {
+		return err
+	}
+	if milestone == nil {
+		return badRequestCode(fmt.Errorf("milestone %s not found", milestoneID), ErrCodeInvalidMilestone)
+	}
+	return nil
+}
+
+// applyCustomFields validates a full custom map against the project's custom
+// field schema and returns it with values coerced to their declared types.
+func (s *TaskService) applyCustomFields(ctx context.Context, project string, custom map[string]any, requireAll bool) (map[string]any, error) {
+	if s.customFields == nil {
+		return custom, nil
+	}
+	fields, err := s.customFields.ListCustomFields(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	return applyCustomFieldSchema(fields, custom, requireAll)
+}
+
+// checkBatchParentCycles rejects parent_id cycles formed entirely among tasks in one batch,
+// which the store-backed check cannot see before insert.
+func checkBatchParentCycles(batch []preparedTaskCreate) error {
+	parentOf := make(map[string]string, len(batch))
+	for _, prepared := range batch {
+		parentOf[prepared.task.ID] = prepared.task.ParentID
+	}
+	for _, prepared := range batch {
+		seen := map[string]bool{prepared.task.ID: true}
+		for parent := parentOf[prepared.task.ID]; parent != ""; parent = parentOf[parent] {
+			if seen[parent] {
+				return badRequestCode(fmt.Errorf("parent_id cycle in batch at %s; parent hierarchy must be acyclic", prepared.task.ID), ErrCodeInvalidArgument)
+			}
+			seen[parent] = true
+		}
+	}
+	return nil
+}
+
+func appendBlocksDependency(deps []models.Dependency, parentID string) []models.Dependency {
+	for _, dep := range deps {
+		if dep.ParentID == parentID && dep.Type == string(models.DependencyBlocks) {
+			return deps
+		}
+	}
+	return append(deps, models.Dependency{ParentID: parentID, Type: string(models.DependencyBlocks)})
+}
+
 func (s *TaskService) validateDependencyParents(deps []models.Dependency, createdIDs map[string]bool, taskExists func(string) (bool, error)) error {
 	for _, dep := range deps {
 		if createdIDs != nil && createdIDs[dep.ParentID] {
@@ -279,170 +748,919 @@ func (s *TaskService) Update(ctx context.Context, id string, req api.TaskUpdateR
 		return resp, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
 	}
 
-	update, err := buildTaskUpdateFromRequest(req, time.Now().UTC())
+	update, err := buildTaskUpdateFromRequest(req, time.Now().UTC(), s.fieldLimits)
 	if err != nil {
 		return resp, err
 	}
 
-	if err := s.store.UpdateTask(ctx, id, update.toStoreTaskUpdate()); err != nil {
-		return resp, err
-	}
-
-	return s.Get(ctx, id)
-}
-
-// Get returns a task response by id.
-func (s *TaskService) Get(ctx context.Context, id string) (api.TaskResponse, error) {
-	var resp api.TaskResponse
-
-	project, err := s.project(ctx)
+	current, err := s.store.GetTask(ctx, id)
 	if err != nil {
 		return resp, err
 	}
-	if !taskIDBelongsToProject(id, project) {
+	if current == nil {
 		return resp, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
 	}
-
-	task, err := s.store.GetTask(ctx, id)
-	if err != nil {
+	if err := s.checkTaskUpdate(*current, update); err != nil {
 		return resp, err
 	}
-	if task == nil {
-		return resp, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	if err := s.checkMergedPRStatus(ctx, update.Status, []string{id}); err != nil {
+		return resp, err
 	}
 
-	labels, err := s.store.ListLabels(ctx, id)
-	if err != nil {
-		return resp, err
+	if update.ParentID != nil {
+		if err := s.checkParentHierarchy(ctx, id, *update.ParentID); err != nil {
+			return resp, err
+		}
 	}
-	deps, err := s.store.ListDependencies(ctx, id)
-	if err != nil {
-		return resp, err
+	if update.MilestoneID != nil {
+		if err := s.checkMilestone(ctx, project, *update.MilestoneID); err != nil {
+			return resp, err
+		}
 	}
-
-	resp = api.TaskResponse{Task: *task, Labels: labels, Deps: deps}
-	return resp, nil
-}
-
-// GetMany returns multiple task responses, preserving request order (including duplicates).
-func (s *TaskService) GetMany(ctx context.Context, ids []string) ([]api.TaskResponse, error) {
-	if len(ids) == 0 {
-		return nil, badRequestCode(fmt.Errorf("ids are required"), ErrCodeMissingRequired)
+	if update.Custom != nil {
+		custom, err := s.applyCustomFields(ctx, project, *update.Custom, true)
+		if err != nil {
+			return resp, err
+		}
+		update.Custom = &custom
 	}
 
-	project, err := s.project(ctx)
-	if err != nil {
-		return nil, err
-	}
-	for _, id := range ids {
-		if !taskIDBelongsToProject(id, project) {
-			return nil, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	if s.parentImpliesBlocks && update.ParentID != nil {
+		if err := s.validateParentForBlocks(id, *update.ParentID); err != nil {
+			return resp, err
 		}
 	}
 
-	uniqueIDs := uniqueStrings(ids)
-	tasks, err := s.store.ListTasks(ctx, taskListFilter{Project: project, IDs: uniqueIDs}.toStoreListFilter())
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := s.writeTaskUpdate(ctx, m, *current, update, req.Force); err != nil {
+			return err
+		}
+		after, err := m.GetTask(ctx, id)
+		if err != nil {
+			return err
+		}
+		if after == nil {
+			return notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+		}
+		if changes := taskFieldChanges(*current, *after); len(changes) > 0 {
+			return s.recordEvents(ctx, m, models.TaskEventUpdated, []string{id}, changes)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		return resp, err
 	}
-	if len(tasks) != len(uniqueIDs) {
-		return nil, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	closed, reopened := statusTransition(*current, update)
+	if _, err := s.propagateStatusChange(ctx, project, closed, reopened, update.UpdatedAt); err != nil {
+		return resp, err
 	}
-
+	return s.Get(ctx, id)
+}
+
+// checkTaskUpdate applies the workflow rules an update must satisfy given the
+// task's current state. Update and RPC batches share it.
+func (s *TaskService) checkTaskUpdate(current models.Task, update taskUpdatePatch) error {
+	if update.Status != nil && s.requireAssigneeStatuses[*update.Status] {
+		assignee := current.Assignee
+		if update.Assignee != nil {
+			assignee = *update.Assignee
+		}
+		if err := s.checkRequiredAssignee(*update.Status, assignee); err != nil {
+			return err
+		}
+	}
+	if update.Status != nil || update.BlockedReason != nil || update.BlockedOn != nil {
+		return s.checkBlockedUpdate(current, update)
+	}
+	return nil
+}
+
+// writeTaskUpdate writes update through m, enforcing WIP limits unless force,
+// and moves the parent blocks edge when parent_implies_blocks is on. Update
+// and RPC batches share it.
+func (s *TaskService) writeTaskUpdate(ctx context.Context, m store.ImportMutator, current models.Task, update taskUpdatePatch, force bool) error {
+	if limits := s.wipLimitsForUpdate(update); len(limits) > 0 && !force {
+		if err := m.UpdateTaskWithinWIPLimits(ctx, current.ID, update.toStoreTaskUpdate(), limits); err != nil {
+			var wipErr *store.WIPLimitExceededError
+			if errors.As(err, &wipErr) {
+				return wipLimitError(wipErr)
+			}
+			return err
+		}
+	} else if err := m.UpdateTask(ctx, current.ID, update.toStoreTaskUpdate()); err != nil {
+		return err
+	}
+
+	if !s.parentImpliesBlocks || update.ParentID == nil || current.ParentID == *update.ParentID {
+		return nil
+	}
+	depType := string(models.DependencyBlocks)
+	if current.ParentID != "" {
+		if err := m.RemoveDependency(ctx, current.ID, current.ParentID, depType); err != nil {
+			return err
+		}
+	}
+	if *update.ParentID != "" {
+		return m.AddDependency(ctx, current.ID, *update.ParentID, depType)
+	}
+	return nil
+}
+
+// statusTransition reports whether update closes or reopens current.
+func statusTransition(current models.Task, update taskUpdatePatch) (closed, reopened []string) {
+	if update.Status == nil || *update.Status == current.Status {
+		return nil, nil
+	}
+	switch {
+	case *update.Status == string(models.StatusClosed):
+		return []string{current.ID}, nil
+	case current.Status == string(models.StatusClosed) && *update.Status != string(models.StatusTombstone):
+		return nil, []string{current.ID}
+	}
+	return nil, nil
+}
+
+// Upsert creates task id from req when it does not exist, or applies req's
+// fields to it as an update when it does. Labels and deps apply only on create.
+func (s *TaskService) Upsert(ctx context.Context, id string, req api.TaskCreateRequest) (api.TaskUpsertResponse, error) {
+	if req.ID != "" && req.ID != id {
+		return api.TaskUpsertResponse{}, badRequestCode(fmt.Errorf("id in body must match path"), ErrCodeInvalidID)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return api.TaskUpsertResponse{}, err
+	}
+	if !taskIDBelongsToProject(id, project) {
+		return api.TaskUpsertResponse{}, badRequestCode(fmt.Errorf("id prefix must match project %s", project), ErrCodeInvalidID)
+	}
+
+	exists, err := s.store.TaskExists(id)
+	if err != nil {
+		return api.TaskUpsertResponse{}, err
+	}
+	if !exists {
+		req.ID = id
+		resp, err := s.Create(ctx, req)
+		if err == nil {
+			return api.TaskUpsertResponse{Created: true, Task: resp}, nil
+		}
+		// A concurrent create won the race; fall through to update it.
+		var apiErr apiError
+		if !errors.As(err, &apiErr) || apiErr.errCode != ErrCodeTaskIDExists {
+			return api.TaskUpsertResponse{}, err
+		}
+	}
+
+	resp, err := s.Update(ctx, id, upsertUpdateRequest(req))
+	if err != nil {
+		return api.TaskUpsertResponse{}, err
+	}
+	return api.TaskUpsertResponse{Task: resp}, nil
+}
+
+// upsertUpdateRequest maps the fields of a create request onto an update.
+func upsertUpdateRequest(req api.TaskCreateRequest) api.TaskUpdateRequest {
+	update := api.TaskUpdateRequest{
+		Status:             req.Status,
+		Type:               req.Type,
+		Priority:           req.Priority,
+		Description:        req.Description,
+		SpecID:             req.SpecID,
+		ParentID:           req.ParentID,
+		Assignee:           req.Assignee,
+		Notes:              req.Notes,
+		Design:             req.Design,
+		AcceptanceCriteria: req.AcceptanceCriteria,
+		SourceRepo:         req.SourceRepo,
+		MilestoneID:        req.MilestoneID,
+		BlockedReason:      req.BlockedReason,
+		BlockedOn:          req.BlockedOn,
+		Custom:             req.Custom,
+	}
+	if strings.TrimSpace(req.Title) != "" {
+		title := req.Title
+		update.Title = &title
+	}
+	return update
+}
+
+// Get returns a task response by id with labels and dependencies.
+func (s *TaskService) Get(ctx context.Context, id string) (api.TaskResponse, error) {
+	return s.GetWithIncludes(ctx, id, taskIncludes{Deps: true})
+}
+
+// GetWithIncludes returns a task response by id with labels and the requested optional sections.
+func (s *TaskService) GetWithIncludes(ctx context.Context, id string, includes taskIncludes) (api.TaskResponse, error) {
+	var resp api.TaskResponse
+
+	project, err := s.project(ctx)
+	if err != nil {
+		return resp, err
+	}
+	if !taskIDBelongsToProject(id, project) {
+		return resp, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+
+	task, err := s.store.GetTask(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+	if task == nil {
+		return resp, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+
+	labels, err := s.store.ListLabels(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+
+	responses := []api.TaskResponse{{Task: *task, Labels: labels}}
+	if err := s.applyIncludes(ctx, responses, includes); err != nil {
+		return resp, err
+	}
+	return responses[0], nil
+}
+
+// GetMany returns multiple task responses, preserving request order (including duplicates).
+func (s *TaskService) GetMany(ctx context.Context, ids []string) ([]api.TaskResponse, error) {
+	if len(ids) == 0 {
+		return nil, badRequestCode(fmt.Errorf("ids are required"), ErrCodeMissingRequired)
+	}
+
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if !taskIDBelongsToProject(id, project) {
+			return nil, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+		}
+	}
+
+	uniqueIDs := uniqueStrings(ids)
+	tasks, err := s.store.ListTasks(ctx, taskListFilter{Project: project, IDs: uniqueIDs}.toStoreListFilter())
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) != len(uniqueIDs) {
+		return nil, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+
 	responsesByTaskOrder, err := s.attachLabelsAndDeps(ctx, tasks)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.applyIncludes(ctx, responsesByTaskOrder, taskIncludes{}); err != nil {
+		return nil, err
+	}
 
 	byID := make(map[string]api.TaskResponse, len(responsesByTaskOrder))
 	for _, resp := range responsesByTaskOrder {
 		byID[resp.Task.ID] = resp
 	}
-
-	responses := make([]api.TaskResponse, 0, len(ids))
-	for _, id := range ids {
-		resp, ok := byID[id]
-		if !ok {
+
+	responses := make([]api.TaskResponse, 0, len(ids))
+	for _, id := range ids {
+		resp, ok := byID[id]
+		if !ok {
+			return nil, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+		}
+		responses = append(responses, resp)
+	}
+
+	return responses, nil
+}
+
+// List returns tasks with labels.
+func (s *TaskService) List(ctx context.Context, filter taskListFilter) ([]api.TaskResponse, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter.Project = project
+	tasks, err := s.store.ListTasks(ctx, filter.toStoreListFilter())
+	if err != nil {
+		if filter.SearchQuery != "" && isInvalidSearchQuery(err) {
+			return nil, badRequestCode(fmt.Errorf("invalid search query"), ErrCodeInvalidSearchQuery)
+		}
+		return nil, err
+	}
+	responses, err := s.attachLabels(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyIncludes(ctx, responses, filter.Includes); err != nil {
+		return nil, err
+	}
+	if filter.SearchQuery != "" {
+		if err := s.applySearchHits(ctx, responses, filter.SearchQuery); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+// applySearchHits sets the match snippet and rank on each search result.
+func (s *TaskService) applySearchHits(ctx context.Context, responses []api.TaskResponse, query string) error {
+	if len(responses) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(responses))
+	for _, resp := range responses {
+		ids = append(ids, resp.ID)
+	}
+	hits, err := s.store.ListSearchHits(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	for i := range responses {
+		hit, ok := hits[responses[i].ID]
+		if !ok {
+			continue
+		}
+		rank := hit.Rank
+		responses[i].Snippet = hit.Snippet
+		responses[i].Rank = &rank
+	}
+	return nil
+}
+
+// Count returns how many tasks match filter, ignoring paging.
+func (s *TaskService) Count(ctx context.Context, filter taskListFilter) (int, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return 0, err
+	}
+	filter.Project = project
+	count, err := s.store.CountTasks(ctx, filter.toStoreListFilter())
+	if err != nil {
+		if filter.SearchQuery != "" && isInvalidSearchQuery(err) {
+			return 0, badRequestCode(fmt.Errorf("invalid search query"), ErrCodeInvalidSearchQuery)
+		}
+		return 0, err
+	}
+	return count, nil
+}
+
+// applyIncludes hydrates the requested optional sections, plus children summaries and
+// worklog totals, with one batched query per section.
+func (s *TaskService) applyIncludes(ctx context.Context, responses []api.TaskResponse, includes taskIncludes) error {
+	if len(responses) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(responses))
+	for _, resp := range responses {
+		ids = append(ids, resp.ID)
+	}
+	if includes.Deps {
+		depMap, err := s.store.ListDependenciesForTasks(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for i := range responses {
+			responses[i].Deps = depMap[responses[i].ID]
+		}
+	}
+	if includes.Dependents {
+		dependentMap, err := s.store.ListDependentsForTasks(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for i := range responses {
+			responses[i].Dependents = dependentMap[responses[i].ID]
+		}
+	}
+	if includes.Comments {
+		if s.comments == nil {
+			return internalError(fmt.Errorf("comments are not configured"))
+		}
+		commentMap, err := s.comments.ListCommentsForTasks(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for i := range responses {
+			responses[i].Comments = commentMap[responses[i].ID]
+		}
+	}
+	if includes.Watchers {
+		if s.watchers == nil {
+			return internalError(fmt.Errorf("watchers are not configured"))
+		}
+		watcherMap, err := s.watchers.ListWatchersForTasks(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for i := range responses {
+			responses[i].Watchers = watcherMap[responses[i].ID]
+		}
+	}
+	if includes.Git {
+		if s.gitRefs == nil {
+			return internalError(fmt.Errorf("git refs are not configured"))
+		}
+		refMap, err := s.gitRefs.ListTaskGitRefsForTasks(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for i := range responses {
+			responses[i].GitSummary = gitSummary(refMap[responses[i].ID])
+		}
+	}
+	childCounts, err := s.store.ChildStatusCountsForTasks(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range responses {
+		counts, ok := childCounts[responses[i].ID]
+		if !ok && responses[i].Type != string(models.TypeEpic) {
+			continue
+		}
+		responses[i].ChildrenSummary = childrenSummary(counts)
+	}
+	if s.worklogs != nil {
+		totals, err := s.worklogs.SumWorklogSecondsForTasks(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for i := range responses {
+			responses[i].TotalTimeSeconds = totals[responses[i].ID]
+		}
+	}
+	if includes.Readiness {
+		return s.annotateReadiness(ctx, responses)
+	}
+	return nil
+}
+
+func childrenSummary(counts map[string]int) *api.ChildrenSummary {
+	if counts == nil {
+		counts = map[string]int{}
+	}
+	summary := &api.ChildrenSummary{ByStatus: counts}
+	for status, count := range counts {
+		summary.Total += count
+		if status == string(models.StatusClosed) {
+			summary.Closed += count
+		}
+	}
+	if summary.Total > 0 {
+		summary.PercentComplete = summary.Closed * 100 / summary.Total
+	}
+	return summary
+}
+
+// gitSummary rolls up one task's refs, or returns nil when it has none.
+func gitSummary(refs []models.TaskGitRef) *api.TaskGitSummary {
+	if len(refs) == 0 {
+		return nil
+	}
+	summary := &api.TaskGitSummary{Total: len(refs), ByRelation: map[string]int{}}
+	mergedBy := map[string]string{}
+	seenCommits := map[string]bool{}
+	for _, ref := range refs {
+		summary.ByRelation[ref.Relation]++
+		if ref.Relation == gitRelationMergedBy {
+			mergedBy[ref.Repo] = cmp.Or(ref.ResolvedCommit, ref.ObjectValue)
+		}
+		if ref.ObjectType == string(models.GitObjectTypeCommit) && !seenCommits[ref.ObjectValue] {
+			seenCommits[ref.ObjectValue] = true
+			summary.Commits = append(summary.Commits, ref.ObjectValue)
+		}
+	}
+	for _, ref := range refs {
+		if ref.ObjectType != string(models.GitObjectTypeBranch) {
+			continue
+		}
+		branch := api.GitBranchStatus{Repo: ref.Repo, Branch: ref.ObjectValue, Relation: ref.Relation, Status: "open"}
+		if commit, ok := mergedBy[ref.Repo]; ok {
+			branch.Status = "merged"
+			branch.MergedBy = commit
+		}
+		summary.Branches = append(summary.Branches, branch)
+	}
+	return summary
+}
+
+// annotateReadiness sets readiness fields on responses using one batched blocker query.
+func (s *TaskService) annotateReadiness(ctx context.Context, responses []api.TaskResponse) error {
+	ids := make([]string, 0, len(responses))
+	for _, resp := range responses {
+		ids = append(ids, resp.ID)
+	}
+	counts, err := s.store.ListOpenBlockerCounts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range responses {
+		blockers := counts[responses[i].ID]
+		blocked := blockers > 0
+		ready := !blocked && models.IsReadyTaskStatus(responses[i].Status)
+		responses[i].OpenBlockers = &blockers
+		responses[i].IsBlocked = &blocked
+		responses[i].IsReady = &ready
+	}
+	return nil
+}
+
+// DependencyGraph returns the blocks edges among tasks matching filter.
+func (s *TaskService) DependencyGraph(ctx context.Context, filter taskListFilter) (dependencyGraph, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return dependencyGraph{}, err
+	}
+	filter.Project = project
+	tasks, err := s.store.ListTasks(ctx, filter.toStoreListFilter())
+	if err != nil {
+		if filter.SearchQuery != "" && isInvalidSearchQuery(err) {
+			return dependencyGraph{}, badRequestCode(fmt.Errorf("invalid search query"), ErrCodeInvalidSearchQuery)
+		}
+		return dependencyGraph{}, err
+	}
+	deps, err := s.store.ListDependenciesForTasks(ctx, taskIDs(tasks))
+	if err != nil {
+		return dependencyGraph{}, err
+	}
+	return buildDependencyGraph(tasks, deps), nil
+}
+
+// DependencyTreeGraph returns the edges of every type among id and the nodes of its
+// dependency tree walk.
+func (s *TaskService) DependencyTreeGraph(ctx context.Context, id string, tree []models.DepTreeNode) (dependencyGraph, error) {
+	root, err := s.store.GetTask(ctx, id)
+	if err != nil {
+		return dependencyGraph{}, err
+	}
+	if root == nil {
+		return dependencyGraph{}, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	ids := make([]string, 0, len(tree)+1)
+	ids = append(ids, id)
+	for _, node := range tree {
+		ids = append(ids, node.ID)
+	}
+	deps, err := s.store.ListDependenciesForTasks(ctx, ids)
+	if err != nil {
+		return dependencyGraph{}, err
+	}
+	return buildDependencyTreeGraph(*root, tree, deps), nil
+}
+
+// ExportPage returns the export page of tasks ordered by id after afterID, hydrated
+// with labels and dependencies. An empty afterID starts from the first task.
+func (s *TaskService) ExportPage(ctx context.Context, limit int, afterID string) ([]api.TaskResponse, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.ListTasks(ctx, taskListFilter{Project: project, Limit: limit, AfterID: afterID, OrderByID: true}.toStoreListFilter())
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.attachLabelsAndDeps(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	return records, s.attachArchiveSections(ctx, project, records)
+}
+
+// Ready returns ready tasks with labels.
+func (s *TaskService) Ready(ctx context.Context, order string, limit int) ([]api.TaskResponse, error) {
+	readyOrder, err := parseReadyOrder(order)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.ListReadyTasks(ctx, project, readyOrder, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.attachLabels(ctx, tasks)
+}
+
+// parseReadyOrder maps the ready endpoint's order param to a store order.
+func parseReadyOrder(value string) (store.ReadyOrder, error) {
+	switch order := store.ReadyOrder(strings.ToLower(strings.TrimSpace(value))); order {
+	case "", store.ReadyOrderUpdated:
+		return store.ReadyOrderUpdated, nil
+	case store.ReadyOrderPriority, store.ReadyOrderScore:
+		return order, nil
+	default:
+		return "", badRequestCode(fmt.Errorf("order must be one of: updated_at, priority, score"), ErrCodeInvalidQuery)
+	}
+}
+
+// Stale returns stale tasks with labels.
+func (s *TaskService) Stale(ctx context.Context, cutoff time.Time, statuses []string, limit int) ([]api.TaskResponse, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.ListStaleTasks(ctx, project, cutoff, statuses, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.attachLabels(ctx, tasks)
+}
+
+// Close closes tasks by ids. prMerged skips the merged pull request check.
+func (s *TaskService) Close(ctx context.Context, ids []string, prMerged bool) error {
+	project, err := s.project(ctx)
+	if err != nil {
+		return err
+	}
+	if !prMerged {
+		if err := s.checkMergedPRs(ctx, ids); err != nil {
+			return err
+		}
+	}
+	before, err := s.journalSnapshot(ctx, project, ids)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.CloseTasks(ctx, project, ids, now); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventClosed, ids, nil)
+	})
+	if errors.Is(err, store.ErrTaskNotFound) {
+		return notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	parents, err := s.propagateStatusChange(ctx, project, ids, nil, now)
+	if err != nil {
+		return err
+	}
+	journaled := append(before, parents...)
+	return s.journal(ctx, project, models.OperationClose, journaled, "", taskIDs(journaled), now)
+}
+
+// closeTaskUpdate is the change Close makes to each task, for callers that
+// close through a transaction mutator.
+func closeTaskUpdate(at time.Time) store.TaskUpdate {
+	status := string(models.StatusClosed)
+	empty := ""
+	return store.TaskUpdate{
+		Status:        &status,
+		ClosedAt:      &at,
+		BlockedReason: &empty,
+		BlockedOn:     &empty,
+		UpdatedAt:     at,
+	}
+}
+
+// CloseByFilter closes every non-closed task matching filter in one store call.
+// It refuses when the filter matches more than the configured cap; with dryRun it only resolves IDs.
+// prMerged skips the merged pull request check.
+func (s *TaskService) CloseByFilter(ctx context.Context, filter taskListFilter, dryRun, prMerged bool) ([]string, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Statuses = openStatusesFor(filter.Statuses)
+	if len(filter.Statuses) == 0 {
+		return []string{}, nil
+	}
+	filter.Project = project
+	tasks, err := s.listTasksCapped(ctx, filter, s.maxCloseByFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := taskIDs(tasks)
+	if dryRun || len(ids) == 0 {
+		return ids, nil
+	}
+	if !prMerged {
+		if err := s.checkMergedPRs(ctx, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.CloseTasks(ctx, project, ids, now); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventClosed, ids, nil)
+	})
+	if errors.Is(err, store.ErrTaskNotFound) {
+		return nil, conflictCode(fmt.Errorf("matching tasks changed during close; retry"), ErrCodeConflict)
+	}
+	if err != nil {
+		return nil, err
+	}
+	parents, err := s.propagateStatusChange(ctx, project, ids, nil, now)
+	if err != nil {
+		return nil, err
+	}
+	journaled := append(tasks, parents...)
+	if err := s.journal(ctx, project, models.OperationClose, journaled, "", taskIDs(journaled), now); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// BulkUpdate applies one update to the tasks named by ids, or to those matching filter when ids is empty.
+// All tasks change in one store transaction; it returns the updated IDs.
+func (s *TaskService) BulkUpdate(ctx context.Context, ids []string, filter *taskListFilter, req api.TaskUpdateRequest) ([]string, error) {
+	project, err := s.project(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.ParentID != nil {
+		return nil, badRequestCode(fmt.Errorf("parent_id cannot be bulk updated"), ErrCodeInvalidArgument)
+	}
+	update, err := buildTaskUpdateFromRequest(req, time.Now().UTC(), s.fieldLimits)
+	if err != nil {
+		return nil, err
+	}
+	if !update.hasFieldChanges() {
+		return nil, badRequestCode(fmt.Errorf("update has no fields"), ErrCodeMissingRequired)
+	}
+	if update.MilestoneID != nil {
+		if err := s.checkMilestone(ctx, project, *update.MilestoneID); err != nil {
+			return nil, err
+		}
+	}
+	if update.Custom != nil {
+		custom, err := s.applyCustomFields(ctx, project, *update.Custom, true)
+		if err != nil {
+			return nil, err
+		}
+		update.Custom = &custom
+	}
+	var before []models.Task
+	if filter != nil {
+		filter.Project = project
+		before, err = s.listTasksCapped(ctx, *filter, maxBulkUpdateTasks)
+		if err != nil {
+			return nil, err
+		}
+		ids = taskIDs(before)
+	} else {
+		ids = uniqueStrings(ids)
+		if len(ids) > maxBulkUpdateTasks {
+			return nil, badRequestCode(fmt.Errorf("too many ids (max %d)", maxBulkUpdateTasks), ErrCodeInvalidArgument)
+		}
+		for _, id := range ids {
+			if !taskIDBelongsToProject(id, project) {
+				return nil, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+			}
+		}
+		before, err = s.store.ListTasks(ctx, store.ListFilter{Project: project, IDs: ids, Limit: len(ids)})
+		if err != nil {
+			return nil, err
+		}
+		if len(before) != len(ids) {
 			return nil, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
 		}
-		responses = append(responses, resp)
+	}
+	if len(ids) == 0 {
+		return []string{}, nil
 	}
 
-	return responses, nil
-}
-
-// List returns tasks with labels.
-func (s *TaskService) List(ctx context.Context, filter taskListFilter) ([]api.TaskResponse, error) {
-	project, err := s.project(ctx)
-	if err != nil {
-		return nil, err
+	if update.Status != nil && s.requireAssigneeStatuses[*update.Status] {
+		for _, task := range before {
+			assignee := task.Assignee
+			if update.Assignee != nil {
+				assignee = *update.Assignee
+			}
+			if err := s.checkRequiredAssignee(*update.Status, assignee); err != nil {
+				return nil, err
+			}
+		}
 	}
-	filter.Project = project
-	tasks, err := s.store.ListTasks(ctx, filter.toStoreListFilter())
-	if err != nil {
-		if filter.SearchQuery != "" && isInvalidSearchQuery(err) {
-			return nil, badRequestCode(fmt.Errorf("invalid search query"), ErrCodeInvalidSearchQuery)
+	if update.Status != nil || update.BlockedReason != nil || update.BlockedOn != nil {
+		for _, task := range before {
+			if err := s.checkBlockedUpdate(task, update); err != nil {
+				return nil, err
+			}
 		}
+	}
+	if err := s.checkMergedPRStatus(ctx, update.Status, ids); err != nil {
 		return nil, err
 	}
-	return s.attachLabels(ctx, tasks)
-}
 
-// ExportPage returns one export page hydrated with labels and dependencies.
-func (s *TaskService) ExportPage(ctx context.Context, limit, offset int) ([]api.TaskResponse, error) {
-	project, err := s.project(ctx)
-	if err != nil {
+	var limits []store.WIPLimit
+	if !req.Force {
+		limits = s.wipLimitsForUpdate(update)
+	}
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.UpdateTasks(ctx, project, ids, update.toStoreTaskUpdate(), limits); err != nil {
+			return err
+		}
+		return s.recordBulkUpdateEvents(ctx, m, before, ids)
+	})
+	var wipErr *store.WIPLimitExceededError
+	switch {
+	case errors.Is(err, store.ErrTaskNotFound):
+		return nil, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	case errors.As(err, &wipErr):
+		return nil, wipLimitError(wipErr)
+	case err != nil:
 		return nil, err
 	}
-	tasks, err := s.store.ListTasks(ctx, taskListFilter{Project: project, Limit: limit, Offset: offset}.toStoreListFilter())
-	if err != nil {
+
+	if err := s.journal(ctx, project, models.OperationBulkUpdate, before, "", ids, update.UpdatedAt); err != nil {
 		return nil, err
 	}
-	return s.attachLabelsAndDeps(ctx, tasks)
+	return ids, nil
 }
 
-// Ready returns ready tasks with labels.
-func (s *TaskService) Ready(ctx context.Context, limit int) ([]api.TaskResponse, error) {
-	project, err := s.project(ctx)
-	if err != nil {
-		return nil, err
+// recordBulkUpdateEvents records an updated event for each task in ids whose
+// fields differ from its snapshot in before.
+func (s *TaskService) recordBulkUpdateEvents(ctx context.Context, m store.ImportMutator, before []models.Task, ids []string) error {
+	if s.events == nil {
+		return nil
 	}
-	tasks, err := s.store.ListReadyTasks(ctx, project, limit)
-	if err != nil {
-		return nil, err
+	previous := make(map[string]models.Task, len(before))
+	for _, task := range before {
+		previous[task.ID] = task
 	}
-	return s.attachLabels(ctx, tasks)
+	for _, id := range ids {
+		after, err := m.GetTask(ctx, id)
+		if err != nil {
+			return err
+		}
+		if after == nil {
+			return store.ErrTaskNotFound
+		}
+		if changes := taskFieldChanges(previous[id], *after); len(changes) > 0 {
+			if err := s.recordEvents(ctx, m, models.TaskEventUpdated, []string{id}, changes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-// Stale returns stale tasks with labels.
-func (s *TaskService) Stale(ctx context.Context, cutoff time.Time, statuses []string, limit int) ([]api.TaskResponse, error) {
-	project, err := s.project(ctx)
+// listTasksCapped lists every task matching filter, refusing when more than max match.
+func (s *TaskService) listTasksCapped(ctx context.Context, filter taskListFilter, max int) ([]models.Task, error) {
+	filter.Limit = max + 1
+	filter.Offset = 0
+	filter.Includes = taskIncludes{}
+
+	tasks, err := s.store.ListTasks(ctx, filter.toStoreListFilter())
 	if err != nil {
+		if filter.SearchQuery != "" && isInvalidSearchQuery(err) {
+			return nil, badRequestCode(fmt.Errorf("invalid search query"), ErrCodeInvalidSearchQuery)
+		}
 		return nil, err
 	}
-	tasks, err := s.store.ListStaleTasks(ctx, project, cutoff, statuses, limit)
-	if err != nil {
-		return nil, err
+	if len(tasks) > max {
+		return nil, badRequestCode(fmt.Errorf("filter matches more than %d tasks; narrow the filter", max), ErrCodeInvalidArgument)
 	}
-	return s.attachLabels(ctx, tasks)
+	return tasks, nil
+}
+
+// openStatusesFor narrows requested statuses to the non-closed set, defaulting to all of them.
+func openStatusesFor(requested []string) []string {
+	open := models.ReadyTaskStatusStrings()
+	if len(requested) == 0 {
+		return open
+	}
+	statuses := make([]string, 0, len(requested))
+	for _, status := range requested {
+		if slices.Contains(open, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
 }
 
-// Close closes tasks by ids.
-func (s *TaskService) Close(ctx context.Context, ids []string) error {
+// Touch bumps updated_at for tasks, marking them as reviewed without other changes.
+func (s *TaskService) Touch(ctx context.Context, ids []string) error {
 	project, err := s.project(ctx)
 	if err != nil {
 		return err
 	}
 	now := time.Now().UTC()
-	err = s.store.CloseTasks(ctx, project, ids, now)
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.TouchTasks(ctx, project, ids, now); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventTouched, ids, nil)
+	})
 	if errors.Is(err, store.ErrTaskNotFound) {
 		return notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return nil
 }
 
 // CloseWithCommit closes tasks and atomically records closed_by git refs for each task.
-func (s *TaskService) CloseWithCommit(ctx context.Context, ids []string, commit, repo string) (int, error) {
+// prMerged skips the merged pull request check.
+func (s *TaskService) CloseWithCommit(ctx context.Context, ids []string, commit, repo string, prMerged bool) (int, error) {
 	ids = uniqueStrings(ids)
 	if len(ids) == 0 {
 		return 0, badRequestCode(fmt.Errorf("ids are required"), ErrCodeMissingRequired)
@@ -499,15 +1717,24 @@ func (s *TaskService) CloseWithCommit(ctx context.Context, ids []string, commit,
 			ObjectValue: commit,
 		})
 	}
+	if !prMerged {
+		if err := s.checkMergedPRs(ctx, ids); err != nil {
+			return 0, err
+		}
+	}
 
 	now := time.Now().UTC()
-	created, err := gitRefStore.CloseTasksWithGitRefs(ctx, project, ids, now, refs)
+	events := s.taskEvents(ctx, models.TaskEventClosed, ids, []models.TaskFieldChange{{Field: "closed_by", New: commit}})
+	created, err := gitRefStore.CloseTasksWithGitRefs(ctx, project, ids, now, refs, events)
 	if errors.Is(err, store.ErrTaskNotFound) {
 		return 0, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
 	}
 	if err != nil {
 		return 0, err
 	}
+	if _, err := s.propagateParentClose(ctx, project, ids, now); err != nil {
+		return 0, err
+	}
 	return created, nil
 }
 
@@ -525,43 +1752,216 @@ func (s *TaskService) Reopen(ctx context.Context, ids []string) error {
 	if err != nil {
 		return err
 	}
+	before, err := s.journalSnapshot(ctx, project, ids)
+	if err != nil {
+		return err
+	}
 	now := time.Now().UTC()
-	err = s.store.ReopenTasks(ctx, project, ids, now)
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.ReopenTasks(ctx, project, ids, now); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventReopened, ids, nil)
+	})
+	if errors.Is(err, store.ErrTaskNotFound) {
+		return notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	parents, err := s.propagateStatusChange(ctx, project, nil, ids, now)
+	if err != nil {
+		return err
+	}
+	journaled := append(before, parents...)
+	return s.journal(ctx, project, models.OperationReopen, journaled, "", taskIDs(journaled), now)
+}
+
+// Delete soft-deletes tasks by marking them tombstone; Restore undoes it and
+// the admin purge removes them for good.
+func (s *TaskService) Delete(ctx context.Context, ids []string) error {
+	project, err := s.project(ctx)
+	if err != nil {
+		return err
+	}
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.DeleteTasks(ctx, project, ids, time.Now().UTC()); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventDeleted, ids, nil)
+	})
+	if errors.Is(err, store.ErrTaskNotFound) {
+		return notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// PurgeTombstoned permanently removes tasks tombstoned before cutoff, in
+// project or, when project is empty, in every project.
+func (s *TaskService) PurgeTombstoned(ctx context.Context, project string, cutoff time.Time, dryRun bool) (*store.CleanupResult, error) {
+	return s.store.PurgeTombstonedTasks(ctx, project, cutoff, dryRun)
+}
+
+// Restore brings tombstoned tasks back to closed (if they were closed) or open.
+func (s *TaskService) Restore(ctx context.Context, ids []string) error {
+	project, err := s.project(ctx)
+	if err != nil {
+		return err
+	}
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.RestoreTasks(ctx, project, ids, time.Now().UTC()); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventRestored, ids, nil)
+	})
 	if errors.Is(err, store.ErrTaskNotFound) {
 		return notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
 	}
-	return err
+	if errors.Is(err, store.ErrTaskNotTombstoned) {
+		return conflictCode(fmt.Errorf("only tombstoned tasks can be restored"), ErrCodeConflict)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Merge folds the duplicate task into the canonical task named by req.Into and
+// returns the canonical task. The duplicate is tombstoned with merged_into set.
+func (s *TaskService) Merge(ctx context.Context, duplicateID string, req api.TaskMergeRequest) (api.TaskResponse, error) {
+	var resp api.TaskResponse
+
+	canonicalID := strings.TrimSpace(req.Into)
+	if canonicalID == "" {
+		return resp, badRequestCode(fmt.Errorf("into is required"), ErrCodeMissingRequired)
+	}
+	if !validateID(duplicateID) || !validateID(canonicalID) {
+		return resp, badRequestCode(fmt.Errorf("invalid id"), ErrCodeInvalidID)
+	}
+	if duplicateID == canonicalID {
+		return resp, badRequestCode(fmt.Errorf("task cannot be merged into itself"), ErrCodeInvalidArgument)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return resp, err
+	}
+	for _, id := range []string{duplicateID, canonicalID} {
+		if !taskIDBelongsToProject(id, project) {
+			return resp, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+		}
+		task, err := s.store.GetTask(ctx, id)
+		if err != nil {
+			return resp, err
+		}
+		if task == nil {
+			return resp, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+		}
+		if task.Status == string(models.StatusTombstone) {
+			return resp, conflictCode(fmt.Errorf("task %s is tombstoned", id), ErrCodeConflict)
+		}
+	}
+
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.MergeTask(ctx, project, duplicateID, canonicalID, time.Now().UTC()); err != nil {
+			return err
+		}
+		if err := s.recordEvents(ctx, m, models.TaskEventMerged, []string{duplicateID}, []models.TaskFieldChange{{Field: "merged_into", New: canonicalID}}); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventMerged, []string{canonicalID}, []models.TaskFieldChange{{Field: "merged_from", New: duplicateID}})
+	})
+	if errors.Is(err, store.ErrTaskNotFound) {
+		return resp, notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	return s.Get(ctx, canonicalID)
 }
 
-// AddDependency adds a dependency edge between tasks.
+// AddDependency adds a dependency edge between tasks. The child must belong to the
+// request project; the parent may live in any project on the server.
 func (s *TaskService) AddDependency(ctx context.Context, childID, parentID, depType string) error {
 	if !validateID(childID) || !validateID(parentID) {
 		return badRequestCode(fmt.Errorf("invalid dependency ids"), ErrCodeInvalidDependency)
 	}
+	if childID == parentID {
+		return badRequestCode(fmt.Errorf("task cannot depend on itself"), ErrCodeInvalidDependency)
+	}
 	project, err := s.project(ctx)
 	if err != nil {
 		return err
 	}
-	if !taskIDBelongsToProject(childID, project) || !taskIDBelongsToProject(parentID, project) {
+	if !taskIDBelongsToProject(childID, project) {
 		return badRequestCode(fmt.Errorf("invalid dependency ids"), ErrCodeInvalidDependency)
 	}
 	if err := s.ensureTaskExists(ctx, childID); err != nil {
 		return err
 	}
-	if err := s.ensureTaskExists(ctx, parentID); err != nil {
+	exists, err := s.store.TaskExists(parentID)
+	if err != nil {
 		return err
 	}
-	depType = strings.TrimSpace(depType)
-	if depType == "" {
-		depType = string(models.DependencyBlocks)
+	if !exists {
+		return notFoundCode(fmt.Errorf("dependency parent not found"), ErrCodeTaskNotFound)
 	}
-	if err := s.store.AddDependency(ctx, childID, parentID, depType); err != nil {
-		if errors.Is(err, store.ErrProjectMismatch) {
-			return badRequestCode(fmt.Errorf("invalid dependency parent_id"), ErrCodeInvalidDependency)
-		}
+	depType, err = normalizeDependencyType(depType)
+	if err != nil {
 		return err
 	}
-	return nil
+	change := models.TaskFieldChange{Field: "deps", New: models.Dependency{ParentID: parentID, Type: depType}}
+	return s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.AddDependency(ctx, childID, parentID, depType); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventDepAdded, []string{childID}, []models.TaskFieldChange{change})
+	})
+}
+
+// RemoveDependency removes one dependency edge. It reports whether the edge existed;
+// removing a missing edge is not an error.
+func (s *TaskService) RemoveDependency(ctx context.Context, childID, parentID, depType string) (bool, error) {
+	if !validateID(childID) || !validateID(parentID) {
+		return false, badRequestCode(fmt.Errorf("invalid dependency ids"), ErrCodeInvalidDependency)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !taskIDBelongsToProject(childID, project) {
+		return false, badRequestCode(fmt.Errorf("invalid dependency ids"), ErrCodeInvalidDependency)
+	}
+	if err := s.ensureTaskExists(ctx, childID); err != nil {
+		return false, err
+	}
+	depType, err = normalizeDependencyType(depType)
+	if err != nil {
+		return false, err
+	}
+
+	deps, err := s.store.ListDependencies(ctx, childID)
+	if err != nil {
+		return false, err
+	}
+	dep := models.Dependency{ParentID: parentID, Type: depType}
+	if !slices.Contains(deps, dep) {
+		return false, nil
+	}
+	change := models.TaskFieldChange{Field: "deps", Old: dep}
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		if err := m.RemoveDependency(ctx, childID, parentID, depType); err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventDepRemoved, []string{childID}, []models.TaskFieldChange{change})
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // AddLabels adds labels to a task and returns the updated label set.
@@ -576,10 +1976,28 @@ func (s *TaskService) AddLabels(ctx context.Context, id string, labels []string)
 	if err != nil {
 		return nil, badRequest(err)
 	}
-	if err := s.store.AddLabels(ctx, id, normalized); err != nil {
+	var after []string
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		before, err := m.ListLabels(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := m.AddLabels(ctx, id, normalized); err != nil {
+			return err
+		}
+		after, err = m.ListLabels(ctx, id)
+		if err != nil {
+			return err
+		}
+		if changes := labelChanges(before, after); changes != nil {
+			return s.recordEvents(ctx, m, models.TaskEventLabelsAdded, []string{id}, changes)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
-	return s.store.ListLabels(ctx, id)
+	return after, nil
 }
 
 // RemoveLabels removes labels from a task and returns the updated label set.
@@ -594,10 +2012,112 @@ func (s *TaskService) RemoveLabels(ctx context.Context, id string, labels []stri
 	if err != nil {
 		return nil, badRequest(err)
 	}
-	if err := s.store.RemoveLabels(ctx, id, normalized); err != nil {
+	var after []string
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		before, err := m.ListLabels(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := m.RemoveLabels(ctx, id, normalized); err != nil {
+			return err
+		}
+		after, err = m.ListLabels(ctx, id)
+		if err != nil {
+			return err
+		}
+		if changes := labelChanges(before, after); changes != nil {
+			return s.recordEvents(ctx, m, models.TaskEventLabelsRemoved, []string{id}, changes)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// DeleteLabel removes a label from every task in the project and returns the
+// normalized label and the affected task ids.
+func (s *TaskService) DeleteLabel(ctx context.Context, label string) (string, []string, error) {
+	label, err := normalizeLabel(label)
+	if err != nil {
+		return "", nil, err
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	var ids []string
+	change := models.TaskFieldChange{Field: "labels", Old: []string{label}}
+	err = s.store.RunInTx(ctx, func(m store.ImportMutator) error {
+		var err error
+		ids, err = m.DeleteLabel(ctx, project, label)
+		if err != nil {
+			return err
+		}
+		return s.recordEvents(ctx, m, models.TaskEventLabelsRemoved, ids, []models.TaskFieldChange{change})
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	if err := s.journal(ctx, project, models.OperationLabelDelete, nil, label, ids, time.Now().UTC()); err != nil {
+		return "", nil, err
+	}
+	return label, ids, nil
+}
+
+// RenameLabel renames label from to to on every task in project, or in all
+// projects when project is empty, and journals one label_rename operation per
+// affected project. It returns the ids of the tasks that carried from.
+func (s *TaskService) RenameLabel(ctx context.Context, project, from, to string) ([]string, error) {
+	var kept map[string]bool
+	if s.operations != nil {
+		both, err := s.store.ListTasks(ctx, store.ListFilter{Project: project, Labels: []string{from, to}})
+		if err != nil {
+			return nil, err
+		}
+		kept = make(map[string]bool, len(both))
+		for _, task := range both {
+			kept[task.ID] = true
+		}
+	}
+
+	ids, err := s.store.RenameLabel(ctx, project, from, to)
+	if err != nil {
 		return nil, err
 	}
-	return s.store.ListLabels(ctx, id)
+
+	now := time.Now().UTC()
+	ops := map[string]*models.Operation{}
+	var order []string
+	for _, id := range ids {
+		prefix := taskIDProjectPrefix(id)
+		op, ok := ops[prefix]
+		if !ok {
+			op = &models.Operation{Project: prefix, Kind: models.OperationLabelRename, Label: from, RenamedTo: to, CreatedAt: now}
+			ops[prefix] = op
+			order = append(order, prefix)
+		}
+		op.TaskIDs = append(op.TaskIDs, id)
+		if !kept[id] {
+			op.Added = append(op.Added, id)
+		}
+	}
+	for _, prefix := range order {
+		if err := s.journalOperation(ctx, ops[prefix]); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// Recompute recomputes store counts and validates the search index against
+// tasks, rebuilding drifted index rows unless dryRun.
+func (s *TaskService) Recompute(ctx context.Context, dryRun bool) (*store.RecomputeResult, error) {
+	return s.store.Recompute(ctx, dryRun)
 }
 
 // Import processes an import request.
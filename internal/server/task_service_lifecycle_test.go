@@ -2,12 +2,17 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"grns/internal/api"
 	"grns/internal/models"
+	"grns/internal/store"
 )
 
 func TestTaskServiceCreate_ValidationMatrix(t *testing.T) {
@@ -167,3 +172,625 @@ func assertAPIErrorStatusAndCode(t *testing.T, err error, wantStatus, wantCode i
 }
 
 func intPtrRef(v int) *int { return &v }
+
+func TestTaskServiceUpdate_WIPLimits(t *testing.T) {
+	svc, st := newTaskServiceForTest(t)
+	svc.ConfigureWIPLimits(map[string]int{"in_progress": 1}, true)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, id := range []string{"gr-wp11", "gr-wp22", "gr-wp33"} {
+		mustCreateTask(t, st, &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, Assignee: "alice", CreatedAt: now, UpdatedAt: now}, nil, nil)
+	}
+	inProgress := "in_progress"
+
+	if _, err := svc.Update(ctx, "gr-wp11", api.TaskUpdateRequest{Status: &inProgress}); err != nil {
+		t.Fatalf("first transition should pass: %v", err)
+	}
+
+	t.Run("second task for same assignee is rejected", func(t *testing.T) {
+		_, err := svc.Update(ctx, "gr-wp22", api.TaskUpdateRequest{Status: &inProgress})
+		if err == nil {
+			t.Fatal("expected wip limit conflict")
+		}
+		assertAPIErrorStatusAndCode(t, err, 409, ErrCodeConflict)
+		if !strings.Contains(err.Error(), "gr-wp11") {
+			t.Fatalf("expected conflicting task in error, got %q", err.Error())
+		}
+		got, err := st.GetTask(ctx, "gr-wp22")
+		if err != nil {
+			t.Fatalf("get task: %v", err)
+		}
+		if got.Status != "open" {
+			t.Fatalf("expected rejected task to stay open, got %s", got.Status)
+		}
+	})
+
+	t.Run("other assignee has separate slot", func(t *testing.T) {
+		bob := "bob"
+		if _, err := svc.Update(ctx, "gr-wp22", api.TaskUpdateRequest{Status: &inProgress, Assignee: &bob}); err != nil {
+			t.Fatalf("expected separate assignee slot: %v", err)
+		}
+	})
+
+	t.Run("force bypasses limit", func(t *testing.T) {
+		resp, err := svc.Update(ctx, "gr-wp33", api.TaskUpdateRequest{Status: &inProgress, Force: true})
+		if err != nil {
+			t.Fatalf("forced transition: %v", err)
+		}
+		if resp.Status != "in_progress" {
+			t.Fatalf("expected in_progress, got %s", resp.Status)
+		}
+	})
+}
+
+func TestTaskServiceUpdate_WIPLimitsOnReassign(t *testing.T) {
+	svc, st := newTaskServiceForTest(t)
+	svc.ConfigureWIPLimits(map[string]int{"in_progress": 1}, true)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	mustCreateTask(t, st, &models.Task{ID: "gr-wr11", Title: "alice's", Status: "in_progress", Type: "task", Priority: 2, Assignee: "alice", CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-wr22", Title: "bob's", Status: "in_progress", Type: "task", Priority: 2, Assignee: "bob", CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-wr33", Title: "open", Status: "open", Type: "task", Priority: 2, Assignee: "bob", CreatedAt: now, UpdatedAt: now}, nil, nil)
+	alice := "alice"
+
+	_, err := svc.Update(ctx, "gr-wr22", api.TaskUpdateRequest{Assignee: &alice})
+	assertAPIErrorStatusAndCode(t, err, 409, ErrCodeConflict)
+	if got, err := st.GetTask(ctx, "gr-wr22"); err != nil || got.Assignee != "bob" {
+		t.Fatalf("expected rejected reassignment to keep bob, got %+v (%v)", got, err)
+	}
+
+	// Tasks outside the limited status can be reassigned freely.
+	if _, err := svc.Update(ctx, "gr-wr33", api.TaskUpdateRequest{Assignee: &alice}); err != nil {
+		t.Fatalf("reassign open task: %v", err)
+	}
+	if _, err := svc.Update(ctx, "gr-wr22", api.TaskUpdateRequest{Assignee: &alice, Force: true}); err != nil {
+		t.Fatalf("forced reassignment: %v", err)
+	}
+}
+
+func TestTaskServiceUpdate_AssigneeWIPLimit(t *testing.T) {
+	svc, st := newTaskServiceForTest(t)
+	svc.ConfigureAssigneeWIPLimit(2)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, id := range []string{"gr-aw11", "gr-aw22", "gr-aw33", "gr-aw44"} {
+		mustCreateTask(t, st, &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	}
+	inProgress := "in_progress"
+	alice := "alice"
+
+	for _, id := range []string{"gr-aw11", "gr-aw22"} {
+		if _, err := svc.Update(ctx, id, api.TaskUpdateRequest{Status: &inProgress, Assignee: &alice}); err != nil {
+			t.Fatalf("transition %s within limit: %v", id, err)
+		}
+	}
+
+	_, err := svc.Update(ctx, "gr-aw33", api.TaskUpdateRequest{Status: &inProgress, Assignee: &alice})
+	assertAPIErrorStatusAndCode(t, err, 409, ErrCodeWIPLimitExceeded)
+	if errorCode(409, err) != "wip_limit_exceeded" {
+		t.Fatalf("expected wip_limit_exceeded code, got %q", errorCode(409, err))
+	}
+
+	// The cap is per assignee and ignores unassigned tasks.
+	if _, err := svc.Update(ctx, "gr-aw44", api.TaskUpdateRequest{Status: &inProgress}); err != nil {
+		t.Fatalf("unassigned transition: %v", err)
+	}
+	if _, err := svc.Update(ctx, "gr-aw33", api.TaskUpdateRequest{Status: &inProgress, Assignee: &alice, Force: true}); err != nil {
+		t.Fatalf("forced transition: %v", err)
+	}
+}
+
+func TestTaskServiceBulkUpdate_WIPLimits(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	alice := "alice"
+
+	t.Run("assignee cap on reassignment", func(t *testing.T) {
+		svc, st := newTaskServiceForTest(t)
+		svc.ConfigureAssigneeWIPLimit(2)
+		mustCreateTask(t, st, &models.Task{ID: "gr-bw11", Title: "alice's", Status: "in_progress", Type: "task", Priority: 2, Assignee: "alice", CreatedAt: now, UpdatedAt: now}, nil, nil)
+		for _, id := range []string{"gr-bw22", "gr-bw33"} {
+			mustCreateTask(t, st, &models.Task{ID: id, Title: "bob's", Status: "in_progress", Type: "task", Priority: 2, Assignee: "bob", CreatedAt: now, UpdatedAt: now}, nil, nil)
+		}
+
+		// The first task fills alice's last slot; the second one exceeds it.
+		_, err := svc.BulkUpdate(ctx, []string{"gr-bw22", "gr-bw33"}, nil, api.TaskUpdateRequest{Assignee: &alice})
+		assertAPIErrorStatusAndCode(t, err, 409, ErrCodeWIPLimitExceeded)
+		if errorCode(409, err) != "wip_limit_exceeded" {
+			t.Fatalf("expected wip_limit_exceeded code, got %q", errorCode(409, err))
+		}
+		for _, id := range []string{"gr-bw22", "gr-bw33"} {
+			if got, err := st.GetTask(ctx, id); err != nil || got.Assignee != "bob" {
+				t.Fatalf("expected rejected bulk update to keep %s with bob, got %+v (%v)", id, got, err)
+			}
+		}
+
+		if _, err := svc.BulkUpdate(ctx, []string{"gr-bw22", "gr-bw33"}, nil, api.TaskUpdateRequest{Assignee: &alice, Force: true}); err != nil {
+			t.Fatalf("forced bulk reassignment: %v", err)
+		}
+	})
+
+	t.Run("per-status limit", func(t *testing.T) {
+		svc, st := newTaskServiceForTest(t)
+		svc.ConfigureWIPLimits(map[string]int{"in_progress": 1}, false)
+		for _, id := range []string{"gr-bs11", "gr-bs22"} {
+			mustCreateTask(t, st, &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+		}
+		inProgress := "in_progress"
+
+		_, err := svc.BulkUpdate(ctx, []string{"gr-bs11", "gr-bs22"}, nil, api.TaskUpdateRequest{Status: &inProgress})
+		assertAPIErrorStatusAndCode(t, err, 409, ErrCodeConflict)
+		if errorCode(409, err) != "conflict" {
+			t.Fatalf("expected conflict code, got %q", errorCode(409, err))
+		}
+		if _, err := svc.BulkUpdate(ctx, []string{"gr-bs11"}, nil, api.TaskUpdateRequest{Status: &inProgress}); err != nil {
+			t.Fatalf("bulk update within limit: %v", err)
+		}
+	})
+}
+
+func TestTaskServiceEventsCommitWithMutation(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.db")
+	st, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	svc := NewTaskService(st, "gr")
+	svc.events = st
+
+	now := time.Now().UTC()
+	mustCreateTask(t, st, &models.Task{ID: "gr-ev11", Title: "audited", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	if err := svc.Touch(ctx, []string{"gr-ev11"}); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+	events, err := st.ListTaskEvents(ctx, "gr", "gr-ev11")
+	if err != nil || len(events) != 1 || events[0].Type != models.TaskEventTouched {
+		t.Fatalf("expected one touched event, got %+v (%v)", events, err)
+	}
+
+	raw, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open raw db: %v", err)
+	}
+	_, err = raw.Exec(`CREATE TRIGGER task_events_unavailable BEFORE INSERT ON task_events
+		BEGIN SELECT RAISE(ABORT, 'task events unavailable'); END`)
+	_ = raw.Close()
+	if err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	if err := svc.Close(ctx, []string{"gr-ev11"}, true); err == nil {
+		t.Fatal("expected close to fail when its event cannot be written")
+	}
+	title := "renamed"
+	if _, err := svc.Update(ctx, "gr-ev11", api.TaskUpdateRequest{Title: &title}); err == nil {
+		t.Fatal("expected update to fail when its event cannot be written")
+	}
+	if _, err := svc.AddLabels(ctx, "gr-ev11", []string{"audit"}); err == nil {
+		t.Fatal("expected add labels to fail when its event cannot be written")
+	}
+
+	got, err := st.GetTask(ctx, "gr-ev11")
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if got.Status != "open" || got.Title != "audited" {
+		t.Fatalf("expected failed writes to roll back, got status %q title %q", got.Status, got.Title)
+	}
+	if labels, err := st.ListLabels(ctx, "gr-ev11"); err != nil || len(labels) != 0 {
+		t.Fatalf("expected no labels after rolled back add, got %v (%v)", labels, err)
+	}
+}
+
+func TestTaskServiceBlockedReason(t *testing.T) {
+	svc, st := newTaskServiceForTest(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	mustCreateTask(t, st, &models.Task{ID: "gr-br11", Title: "waiting", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	blocked := "blocked"
+	inProgress := "in_progress"
+	reason := "  needs vendor fix "
+	blockedOn := "gr-zz99"
+
+	_, err := svc.Update(ctx, "gr-br11", api.TaskUpdateRequest{BlockedReason: &reason})
+	assertAPIErrorStatusAndCode(t, err, 400, ErrCodeInvalidArgument)
+
+	resp, err := svc.Update(ctx, "gr-br11", api.TaskUpdateRequest{Status: &blocked, BlockedReason: &reason, BlockedOn: &blockedOn})
+	if err != nil {
+		t.Fatalf("block task: %v", err)
+	}
+	if resp.BlockedReason != "needs vendor fix" || resp.BlockedOn != "gr-zz99" {
+		t.Fatalf("unexpected blocked fields: %q %q", resp.BlockedReason, resp.BlockedOn)
+	}
+	stored, err := st.GetTask(ctx, "gr-br11")
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if stored.BlockedReason != "needs vendor fix" || stored.BlockedOn != "gr-zz99" {
+		t.Fatalf("blocked fields not persisted: %#v", stored)
+	}
+
+	resp, err = svc.Update(ctx, "gr-br11", api.TaskUpdateRequest{Status: &inProgress})
+	if err != nil {
+		t.Fatalf("unblock task: %v", err)
+	}
+	if resp.BlockedReason != "" || resp.BlockedOn != "" {
+		t.Fatalf("expected blocked fields cleared, got %q %q", resp.BlockedReason, resp.BlockedOn)
+	}
+
+	svc.ConfigureRequireBlockedReason(true)
+	_, err = svc.Update(ctx, "gr-br11", api.TaskUpdateRequest{Status: &blocked, BlockedOn: &blockedOn})
+	assertAPIErrorStatusAndCode(t, err, 400, ErrCodeMissingRequired)
+	_, err = svc.Create(ctx, api.TaskCreateRequest{Title: "blocked at birth", Status: &blocked})
+	assertAPIErrorStatusAndCode(t, err, 400, ErrCodeMissingRequired)
+	if _, err := svc.Update(ctx, "gr-br11", api.TaskUpdateRequest{Status: &blocked, BlockedReason: &reason}); err != nil {
+		t.Fatalf("block with reason: %v", err)
+	}
+
+	if err := svc.Close(ctx, []string{"gr-br11"}, false); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	stored, err = st.GetTask(ctx, "gr-br11")
+	if err != nil {
+		t.Fatalf("get closed task: %v", err)
+	}
+	if stored.BlockedReason != "" || stored.BlockedOn != "" {
+		t.Fatalf("expected close to clear blocked fields, got %#v", stored)
+	}
+}
+
+func TestTaskServiceParentImpliesBlocks(t *testing.T) {
+	svc, st := newTaskServiceForTest(t)
+	svc.ConfigureParentImpliesBlocks(true)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	mustCreateTask(t, st, &models.Task{ID: "gr-pb11", Title: "epic one", Status: "open", Type: "epic", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-pb22", Title: "epic two", Status: "open", Type: "epic", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+
+	assertDeps := func(t *testing.T, id string, want ...string) {
+		t.Helper()
+		deps, err := st.ListDependencies(ctx, id)
+		if err != nil {
+			t.Fatalf("list deps: %v", err)
+		}
+		if len(deps) != len(want) {
+			t.Fatalf("expected deps %v, got %#v", want, deps)
+		}
+		for i, dep := range deps {
+			if dep.ParentID != want[i] || dep.Type != string(models.DependencyBlocks) {
+				t.Fatalf("expected deps %v, got %#v", want, deps)
+			}
+		}
+	}
+
+	parent := "gr-pb11"
+	resp, err := svc.Create(ctx, api.TaskCreateRequest{
+		ID:       "gr-pc11",
+		Title:    "child",
+		ParentID: &parent,
+		Deps:     []models.Dependency{{ParentID: "gr-pb11", Type: "blocks"}},
+	})
+	if err != nil {
+		t.Fatalf("create child: %v", err)
+	}
+	if len(resp.Deps) != 1 {
+		t.Fatalf("expected explicit and implied edge to dedupe, got %#v", resp.Deps)
+	}
+	assertDeps(t, "gr-pc11", "gr-pb11")
+
+	t.Run("parent change moves edge", func(t *testing.T) {
+		next := "gr-pb22"
+		if _, err := svc.Update(ctx, "gr-pc11", api.TaskUpdateRequest{ParentID: &next}); err != nil {
+			t.Fatalf("update parent: %v", err)
+		}
+		assertDeps(t, "gr-pc11", "gr-pb22")
+	})
+
+	t.Run("clearing parent removes edge", func(t *testing.T) {
+		empty := ""
+		if _, err := svc.Update(ctx, "gr-pc11", api.TaskUpdateRequest{ParentID: &empty}); err != nil {
+			t.Fatalf("clear parent: %v", err)
+		}
+		assertDeps(t, "gr-pc11")
+	})
+
+	t.Run("missing parent is rejected", func(t *testing.T) {
+		missing := "gr-zz99"
+		_, err := svc.Update(ctx, "gr-pc11", api.TaskUpdateRequest{ParentID: &missing})
+		if err == nil {
+			t.Fatal("expected invalid parent error")
+		}
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeInvalidParentID)
+	})
+}
+
+func TestTaskServiceFieldLimits(t *testing.T) {
+	svc, st := newTaskServiceForTest(t)
+	svc.ConfigureFieldLimits(16, 8)
+	ctx := context.Background()
+
+	t.Run("over-long description is rejected on create", func(t *testing.T) {
+		description := strings.Repeat("x", 17)
+		_, err := svc.Create(ctx, api.TaskCreateRequest{Title: "Too long", Description: &description})
+		if err == nil {
+			t.Fatal("expected description limit error")
+		}
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeRequestTooLarge)
+		if !strings.Contains(err.Error(), "attachment") {
+			t.Fatalf("expected attachment hint in error, got %q", err.Error())
+		}
+	})
+
+	t.Run("description at limit is accepted", func(t *testing.T) {
+		description := strings.Repeat("x", 16)
+		resp, err := svc.Create(ctx, api.TaskCreateRequest{Title: "At limit", Description: &description})
+		if err != nil {
+			t.Fatalf("create at limit: %v", err)
+		}
+		if resp.Description != description {
+			t.Fatalf("expected description to be stored, got %q", resp.Description)
+		}
+	})
+
+	t.Run("over-long notes are rejected on update", func(t *testing.T) {
+		now := time.Now().UTC()
+		mustCreateTask(t, st, &models.Task{ID: "gr-fl11", Title: "Notes", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+		notes := strings.Repeat("n", 9)
+		_, err := svc.Update(ctx, "gr-fl11", api.TaskUpdateRequest{Notes: &notes})
+		if err == nil {
+			t.Fatal("expected notes limit error")
+		}
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeRequestTooLarge)
+	})
+}
+
+func TestTaskServiceCreateLimits(t *testing.T) {
+	svc, _ := newTaskServiceForTest(t)
+	svc.ConfigureCreateLimits(2, 1)
+	ctx := context.Background()
+
+	t.Run("over-cap labels are rejected before label validation", func(t *testing.T) {
+		// The invalid label would fail with ErrCodeInvalidLabel if validated first.
+		_, err := svc.Create(ctx, api.TaskCreateRequest{Title: "Labels", Labels: []string{"a", "b", "not valid"}})
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeInvalidArgument)
+		if !strings.Contains(err.Error(), "too many labels") {
+			t.Fatalf("expected label cap error, got %q", err.Error())
+		}
+	})
+
+	t.Run("over-cap deps are rejected before existence checks", func(t *testing.T) {
+		// Neither parent exists, so an existence check would fail differently.
+		_, err := svc.Create(ctx, api.TaskCreateRequest{Title: "Deps", Deps: []models.Dependency{{ParentID: "gr-zz01"}, {ParentID: "gr-zz02"}}})
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeInvalidArgument)
+		if !strings.Contains(err.Error(), "too many deps") {
+			t.Fatalf("expected deps cap error, got %q", err.Error())
+		}
+	})
+
+	t.Run("payload at caps is accepted", func(t *testing.T) {
+		if _, err := svc.Create(ctx, api.TaskCreateRequest{Title: "At caps", Labels: []string{"a", "b"}}); err != nil {
+			t.Fatalf("create at caps: %v", err)
+		}
+	})
+}
+
+func TestTaskServiceRejectsParentCycles(t *testing.T) {
+	svc, _ := newTaskServiceForTest(t)
+	ctx := context.Background()
+
+	parentOf := func(id string) *string { return &id }
+	for _, req := range []api.TaskCreateRequest{
+		{ID: "gr-pc0a", Title: "A"},
+		{ID: "gr-pc0b", Title: "B", ParentID: parentOf("gr-pc0a")},
+		{ID: "gr-pc0c", Title: "C", ParentID: parentOf("gr-pc0b")},
+		{ID: "gr-pc0d", Title: "D", ParentID: parentOf("gr-pc0c")},
+	} {
+		if _, err := svc.Create(ctx, req); err != nil {
+			t.Fatalf("create %s: %v", req.ID, err)
+		}
+	}
+
+	t.Run("direct child cannot become parent", func(t *testing.T) {
+		_, err := svc.Update(ctx, "gr-pc0a", api.TaskUpdateRequest{ParentID: parentOf("gr-pc0b")})
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeInvalidArgument)
+	})
+
+	t.Run("deep descendant cannot become parent", func(t *testing.T) {
+		_, err := svc.Update(ctx, "gr-pc0a", api.TaskUpdateRequest{ParentID: parentOf("gr-pc0d")})
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeInvalidArgument)
+		if !strings.Contains(err.Error(), "acyclic") {
+			t.Fatalf("expected cycle error, got %q", err.Error())
+		}
+	})
+
+	t.Run("self parenting is rejected", func(t *testing.T) {
+		_, err := svc.Update(ctx, "gr-pc0c", api.TaskUpdateRequest{ParentID: parentOf("gr-pc0c")})
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeInvalidArgument)
+		_, err = svc.Create(ctx, api.TaskCreateRequest{ID: "gr-pc0e", Title: "E", ParentID: parentOf("gr-pc0e")})
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeInvalidArgument)
+	})
+
+	t.Run("cycle within one batch is rejected", func(t *testing.T) {
+		_, err := svc.BatchCreate(ctx, []api.TaskCreateRequest{
+			{ID: "gr-pc1a", Title: "X", ParentID: parentOf("gr-pc1b")},
+			{ID: "gr-pc1b", Title: "Y", ParentID: parentOf("gr-pc1a")},
+		})
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeInvalidArgument)
+	})
+
+	t.Run("moving within the tree stays allowed", func(t *testing.T) {
+		if _, err := svc.Update(ctx, "gr-pc0d", api.TaskUpdateRequest{ParentID: parentOf("gr-pc0a")}); err != nil {
+			t.Fatalf("reparent to ancestor: %v", err)
+		}
+		if _, err := svc.Update(ctx, "gr-pc0c", api.TaskUpdateRequest{ParentID: parentOf("gr-pc0d")}); err != nil {
+			t.Fatalf("reparent to former descendant after move: %v", err)
+		}
+	})
+}
+
+func TestTaskServiceRequireAssigneeForStatuses(t *testing.T) {
+	svc, st := newTaskServiceForTest(t)
+	svc.ConfigureRequireAssigneeStatuses([]string{"in_progress"})
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	mustCreateTask(t, st, &models.Task{ID: "gr-ra11", Title: "Unassigned", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	inProgress := "in_progress"
+
+	t.Run("unassigned task is rejected", func(t *testing.T) {
+		_, err := svc.Update(ctx, "gr-ra11", api.TaskUpdateRequest{Status: &inProgress})
+		if err == nil {
+			t.Fatal("expected missing assignee error")
+		}
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeMissingRequired)
+	})
+
+	t.Run("clearing assignee in the same request is rejected", func(t *testing.T) {
+		empty := " "
+		_, err := svc.Update(ctx, "gr-ra11", api.TaskUpdateRequest{Status: &inProgress, Assignee: &empty})
+		if err == nil {
+			t.Fatal("expected missing assignee error")
+		}
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeMissingRequired)
+	})
+
+	t.Run("setting assignee in the same request is allowed", func(t *testing.T) {
+		assignee := "alice"
+		resp, err := svc.Update(ctx, "gr-ra11", api.TaskUpdateRequest{Status: &inProgress, Assignee: &assignee})
+		if err != nil {
+			t.Fatalf("update with assignee: %v", err)
+		}
+		if resp.Status != "in_progress" || resp.Assignee != "alice" {
+			t.Fatalf("unexpected task after update: %s/%s", resp.Status, resp.Assignee)
+		}
+	})
+
+	t.Run("create into required status needs assignee", func(t *testing.T) {
+		_, err := svc.Create(ctx, api.TaskCreateRequest{Title: "Started", Status: &inProgress})
+		if err == nil {
+			t.Fatal("expected missing assignee error on create")
+		}
+		assertAPIErrorStatusAndCode(t, err, 400, ErrCodeMissingRequired)
+	})
+}
+
+func TestTaskServiceGet_ChildrenSummary(t *testing.T) {
+	svc, st := newTaskServiceForTest(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	mustCreateTask(t, st, &models.Task{ID: "gr-cs01", Title: "epic", Status: "open", Type: "epic", Priority: 1, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-cs02", Title: "empty epic", Status: "open", Type: "epic", Priority: 1, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-cs03", Title: "parent task", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-cs04", Title: "leaf", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	for i, status := range []string{"closed", "closed", "open"} {
+		task := &models.Task{ID: fmt.Sprintf("gr-cc%02d", i), Title: "child", Status: status, Type: "task", Priority: 2, ParentID: "gr-cs01", CreatedAt: now, UpdatedAt: now}
+		if status == "closed" {
+			task.ClosedAt = &now
+		}
+		mustCreateTask(t, st, task, nil, nil)
+	}
+	mustCreateTask(t, st, &models.Task{ID: "gr-cc10", Title: "sub", Status: "in_progress", Type: "task", Priority: 2, ParentID: "gr-cs03", CreatedAt: now, UpdatedAt: now}, nil, nil)
+
+	responses, err := svc.GetMany(ctx, []string{"gr-cs01", "gr-cs02", "gr-cs03", "gr-cs04"})
+	if err != nil {
+		t.Fatalf("get many: %v", err)
+	}
+
+	epic := responses[0].ChildrenSummary
+	if epic == nil || epic.Total != 3 || epic.Closed != 2 || epic.PercentComplete != 66 {
+		t.Fatalf("unexpected epic summary: %#v", epic)
+	}
+	if empty := responses[1].ChildrenSummary; empty == nil || empty.Total != 0 || empty.PercentComplete != 0 {
+		t.Fatalf("expected zero summary for childless epic, got %#v", empty)
+	}
+	if parent := responses[2].ChildrenSummary; parent == nil || parent.Total != 1 || parent.ByStatus["in_progress"] != 1 {
+		t.Fatalf("unexpected parent task summary: %#v", parent)
+	}
+	if responses[3].ChildrenSummary != nil {
+		t.Fatalf("expected no summary for leaf task, got %#v", responses[3].ChildrenSummary)
+	}
+}
+
+func TestTaskServiceAutoCloseParents(t *testing.T) {
+	svc, st := newTaskServiceForTest(t)
+	svc.ConfigureAutoCloseParents(true)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	mustCreateTask(t, st, &models.Task{ID: "gr-ac01", Title: "initiative", Status: "open", Type: "epic", Priority: 1, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-ac02", Title: "epic", Status: "open", Type: "epic", Priority: 1, ParentID: "gr-ac01", CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-ac03", Title: "child one", Status: "open", Type: "task", Priority: 2, ParentID: "gr-ac02", CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-ac04", Title: "child two", Status: "open", Type: "task", Priority: 2, ParentID: "gr-ac02", CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-ac05", Title: "plain parent", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+	mustCreateTask(t, st, &models.Task{ID: "gr-ac06", Title: "subtask", Status: "open", Type: "task", Priority: 2, ParentID: "gr-ac05", CreatedAt: now, UpdatedAt: now}, nil, nil)
+
+	status := func(id string) string {
+		t.Helper()
+		task, err := st.GetTask(ctx, id)
+		if err != nil || task == nil {
+			t.Fatalf("get %s: %v", id, err)
+		}
+		return task.Status
+	}
+
+	if err := svc.Close(ctx, []string{"gr-ac03"}, false); err != nil {
+		t.Fatalf("close first child: %v", err)
+	}
+	if got := status("gr-ac02"); got != "open" {
+		t.Fatalf("expected epic open while a child is open, got %s", got)
+	}
+
+	if err := svc.Close(ctx, []string{"gr-ac04", "gr-ac06"}, false); err != nil {
+		t.Fatalf("close remaining children: %v", err)
+	}
+	if got := status("gr-ac02"); got != "closed" {
+		t.Fatalf("expected epic auto-closed, got %s", got)
+	}
+	if got := status("gr-ac01"); got != "closed" {
+		t.Fatalf("expected grandparent epic auto-closed, got %s", got)
+	}
+	if got := status("gr-ac05"); got != "open" {
+		t.Fatalf("expected non-epic parent untouched, got %s", got)
+	}
+
+	if err := svc.Reopen(ctx, []string{"gr-ac03"}); err != nil {
+		t.Fatalf("reopen child: %v", err)
+	}
+	if got := status("gr-ac02"); got != "open" {
+		t.Fatalf("expected epic reopened, got %s", got)
+	}
+	if got := status("gr-ac01"); got != "open" {
+		t.Fatalf("expected grandparent epic reopened, got %s", got)
+	}
+
+	// Status changes through update propagate the same way.
+	closed, open := "closed", "open"
+	if _, err := svc.Update(ctx, "gr-ac03", api.TaskUpdateRequest{Status: &closed}); err != nil {
+		t.Fatalf("close child by update: %v", err)
+	}
+	if got := status("gr-ac01"); got != "closed" {
+		t.Fatalf("expected grandparent epic auto-closed by update, got %s", got)
+	}
+	if _, err := svc.Update(ctx, "gr-ac03", api.TaskUpdateRequest{Status: &open}); err != nil {
+		t.Fatalf("reopen child by update: %v", err)
+	}
+	if got := status("gr-ac02"); got != "open" {
+		t.Fatalf("expected epic reopened by update, got %s", got)
+	}
+
+	svc.ConfigureAutoCloseParents(false)
+	if err := svc.Close(ctx, []string{"gr-ac03"}, false); err != nil {
+		t.Fatalf("close child with rule disabled: %v", err)
+	}
+	if got := status("gr-ac02"); got != "open" {
+		t.Fatalf("expected epic untouched with rule disabled, got %s", got)
+	}
+}
@@ -39,6 +39,48 @@ func TestTaskServiceImportSemantics(t *testing.T) {
 		}
 	})
 
+	t.Run("configured source label is added to created tasks", func(t *testing.T) {
+		svc, st := newTaskServiceForTest(t)
+		ctx := context.Background()
+		svc.importer.defaultSourceLabel = "source:jira"
+
+		req := api.ImportRequest{
+			Tasks: []api.TaskImportRecord{
+				{Task: models.Task{ID: "gr-sl11", Title: "Labeled", Status: "open", Type: "task", Priority: 2}, Labels: []string{"backend", "source:jira"}},
+				{Task: models.Task{ID: "gr-sl22", Title: "Unlabeled", Status: "open", Type: "task", Priority: 2}},
+			},
+		}
+		if _, err := svc.Import(ctx, req); err != nil {
+			t.Fatalf("import: %v", err)
+		}
+
+		want := map[string][]string{"gr-sl11": {"backend", "source:jira"}, "gr-sl22": {"source:jira"}}
+		for id, wantLabels := range want {
+			labels, err := st.ListLabels(ctx, id)
+			if err != nil {
+				t.Fatalf("list labels %s: %v", id, err)
+			}
+			if strings.Join(labels, ",") != strings.Join(wantLabels, ",") {
+				t.Fatalf("expected %s labels %v, got %v", id, wantLabels, labels)
+			}
+		}
+
+		resp, err := svc.Import(ctx, req)
+		if err != nil {
+			t.Fatalf("re-import: %v", err)
+		}
+		if resp.Skipped != 2 {
+			t.Fatalf("expected re-import to skip both tasks, got %+v", resp)
+		}
+		labels, err := st.ListLabels(ctx, "gr-sl22")
+		if err != nil {
+			t.Fatalf("list labels after re-import: %v", err)
+		}
+		if len(labels) != 1 {
+			t.Fatalf("expected re-import to leave labels unchanged, got %v", labels)
+		}
+	})
+
 	t.Run("dedupe skip and error do not rewrite dependencies", func(t *testing.T) {
 		tests := []struct {
 			name       string
@@ -145,6 +187,85 @@ func TestTaskServiceImportSemantics(t *testing.T) {
 		})
 	})
 
+	t.Run("merge folds fields, labels and deps into existing tasks", func(t *testing.T) {
+		svc, st := newTaskServiceForTest(t)
+		ctx := context.Background()
+		now := time.Now().UTC().Truncate(time.Second)
+
+		mustCreateTask(t, st, &models.Task{ID: "gr-pa11", Title: "Parent one", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+		mustCreateTask(t, st, &models.Task{ID: "gr-pa22", Title: "Parent two", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil)
+		mustCreateTask(t, st, &models.Task{
+			ID: "gr-ch11", Title: "Child", Status: "in_progress", Type: "task", Priority: 2,
+			Description: "keep me", Notes: "old notes", Custom: map[string]any{"team": "core"}, CreatedAt: now, UpdatedAt: now,
+		}, []string{"local"}, []models.Dependency{{ParentID: "gr-pa11", Type: "blocks"}})
+
+		// An older upstream record: text fields merge, status does not.
+		older := now.Add(-time.Hour)
+		resp, err := svc.Import(ctx, api.ImportRequest{
+			Dedupe: "merge",
+			Tasks: []api.TaskImportRecord{{
+				Task: models.Task{
+					ID: "gr-ch11", Title: "Child renamed", Status: "closed", Type: "bug", Priority: 0,
+					Notes: "upstream notes", Custom: map[string]any{"jira": "ABC-1"}, CreatedAt: older, UpdatedAt: older,
+				},
+				Labels: []string{"upstream"},
+				Deps:   []models.Dependency{{ParentID: "gr-pa22", Type: "blocks"}},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("import merge: %v", err)
+		}
+		if resp.Updated != 1 {
+			t.Fatalf("expected updated=1, got %#v", resp)
+		}
+
+		task, err := st.GetTask(ctx, "gr-ch11")
+		if err != nil {
+			t.Fatalf("get task: %v", err)
+		}
+		if task.Title != "Child renamed" || task.Notes != "upstream notes" || task.Description != "keep me" {
+			t.Fatalf("unexpected merged text fields: %+v", task)
+		}
+		if task.Status != "in_progress" || task.Type != "task" || task.Priority != 2 || !task.UpdatedAt.Equal(now) {
+			t.Fatalf("expected the older record to leave status fields and updated_at alone, got %+v", task)
+		}
+		if task.Custom["team"] != "core" || task.Custom["jira"] != "ABC-1" {
+			t.Fatalf("expected custom keys to merge, got %v", task.Custom)
+		}
+		labels, err := st.ListLabels(ctx, "gr-ch11")
+		if err != nil {
+			t.Fatalf("list labels: %v", err)
+		}
+		if len(labels) != 2 || labels[0] != "local" || labels[1] != "upstream" {
+			t.Fatalf("expected labels to be unioned, got %v", labels)
+		}
+		deps, err := st.ListDependencies(ctx, "gr-ch11")
+		if err != nil {
+			t.Fatalf("list deps: %v", err)
+		}
+		if len(deps) != 2 {
+			t.Fatalf("expected the new dep to be added to the existing one, got %+v", deps)
+		}
+
+		// A newer record also brings its status and timestamp.
+		newer := now.Add(time.Hour)
+		if _, err := svc.Import(ctx, api.ImportRequest{
+			Dedupe: "merge",
+			Tasks: []api.TaskImportRecord{{
+				Task: models.Task{ID: "gr-ch11", Title: "Child renamed", Status: "closed", Type: "task", Priority: 1, CreatedAt: newer, UpdatedAt: newer},
+			}},
+		}); err != nil {
+			t.Fatalf("import newer merge: %v", err)
+		}
+		task, err = st.GetTask(ctx, "gr-ch11")
+		if err != nil {
+			t.Fatalf("get task after newer merge: %v", err)
+		}
+		if task.Status != "closed" || task.Priority != 1 || task.ClosedAt == nil || !task.UpdatedAt.Equal(newer) || task.Notes != "upstream notes" {
+			t.Fatalf("expected the newer record to close the task and keep notes, got %+v", task)
+		}
+	})
+
 	t.Run("status and closed_at are normalized on overwrite", func(t *testing.T) {
 		svc, st := newTaskServiceForTest(t)
 		ctx := context.Background()
@@ -290,7 +411,7 @@ func TestTaskServiceProjectScopedImportExport(t *testing.T) {
 	ctxGR := contextWithProject(context.Background(), "gr")
 	ctxXY := contextWithProject(context.Background(), "xy")
 
-	records, err := svc.ExportPage(ctxGR, 100, 0)
+	records, err := svc.ExportPage(ctxGR, 100, "")
 	if err != nil {
 		t.Fatalf("export page gr: %v", err)
 	}
@@ -298,7 +419,7 @@ func TestTaskServiceProjectScopedImportExport(t *testing.T) {
 		t.Fatalf("expected only gr-pe11 in gr export, got %+v", records)
 	}
 
-	records, err = svc.ExportPage(ctxXY, 100, 0)
+	records, err = svc.ExportPage(ctxXY, 100, "")
 	if err != nil {
 		t.Fatalf("export page xy: %v", err)
 	}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+// TaskWatcherService manages per-user subscriptions to tasks.
+type TaskWatcherService struct {
+	taskStore     store.TaskServiceStore
+	watcherStore  store.WatcherStore
+	projectPrefix string
+}
+
+// NewTaskWatcherService constructs a TaskWatcherService.
+func NewTaskWatcherService(taskStore store.TaskServiceStore, watcherStore store.WatcherStore, projectPrefix string) *TaskWatcherService {
+	return &TaskWatcherService{taskStore: taskStore, watcherStore: watcherStore, projectPrefix: projectPrefix}
+}
+
+// Watch subscribes user to a task and returns the task's watchers.
+func (s *TaskWatcherService) Watch(ctx context.Context, taskID, user string) ([]models.TaskWatcher, error) {
+	if s == nil || s.taskStore == nil || s.watcherStore == nil {
+		return nil, internalError(fmt.Errorf("task watcher service is not configured"))
+	}
+	user, err := normalizeWatcherUser(user)
+	if err != nil {
+		return nil, err
+	}
+	taskID, err = s.ensureTaskExists(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.watcherStore.AddTaskWatcher(ctx, taskID, user, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.watcherStore.ListTaskWatchers(ctx, taskID)
+}
+
+// Unwatch unsubscribes user from a task and returns the remaining watchers.
+// Unwatching a task the user does not watch is a no-op.
+func (s *TaskWatcherService) Unwatch(ctx context.Context, taskID, user string) ([]models.TaskWatcher, error) {
+	if s == nil || s.taskStore == nil || s.watcherStore == nil {
+		return nil, internalError(fmt.Errorf("task watcher service is not configured"))
+	}
+	user, err := normalizeWatcherUser(user)
+	if err != nil {
+		return nil, err
+	}
+	taskID, err = s.ensureTaskExists(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.watcherStore.RemoveTaskWatcher(ctx, taskID, user); err != nil {
+		return nil, err
+	}
+	return s.watcherStore.ListTaskWatchers(ctx, taskID)
+}
+
+// List returns one task's watchers ordered by user.
+func (s *TaskWatcherService) List(ctx context.Context, taskID string) ([]models.TaskWatcher, error) {
+	if s == nil || s.taskStore == nil || s.watcherStore == nil {
+		return nil, internalError(fmt.Errorf("task watcher service is not configured"))
+	}
+	taskID, err := s.ensureTaskExists(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.watcherStore.ListTaskWatchers(ctx, taskID)
+}
+
+func (s *TaskWatcherService) ensureTaskExists(ctx context.Context, taskID string) (string, error) {
+	taskID = strings.TrimSpace(taskID)
+	if !validateID(taskID) {
+		return "", badRequestCode(fmt.Errorf("invalid task_id"), ErrCodeInvalidID)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !taskIDBelongsToProject(taskID, project) {
+		return "", notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	exists, err := s.taskStore.TaskExists(taskID)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	return taskID, nil
+}
+
+func (s *TaskWatcherService) project(ctx context.Context) (string, error) {
+	if project, ok := projectFromContext(ctx); ok {
+		return project, nil
+	}
+	return normalizePrefix(s.projectPrefix)
+}
+
+func normalizeWatcherUser(user string) (string, error) {
+	user = strings.TrimSpace(user)
+	if user == "" {
+		return "", badRequestCode(fmt.Errorf("user is required"), ErrCodeMissingRequired)
+	}
+	if utf8.RuneCountInString(user) > maxActorLength {
+		return "", badRequestCode(fmt.Errorf("user exceeds %d characters", maxActorLength), ErrCodeInvalidArgument)
+	}
+	return user, nil
+}
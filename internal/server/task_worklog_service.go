@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"grns/internal/api"
+	"grns/internal/models"
+	"grns/internal/store"
+)
+
+const (
+	maxWorklogSeconds   = 24 * 60 * 60
+	maxWorklogNoteBytes = 4 << 10 // 4 KiB
+)
+
+// TaskWorklogService orchestrates time tracking entries on tasks.
+type TaskWorklogService struct {
+	taskStore     store.TaskServiceStore
+	worklogStore  store.WorklogStore
+	projectPrefix string
+}
+
+// NewTaskWorklogService constructs a TaskWorklogService.
+func NewTaskWorklogService(taskStore store.TaskServiceStore, worklogStore store.WorklogStore, projectPrefix string) *TaskWorklogService {
+	return &TaskWorklogService{taskStore: taskStore, worklogStore: worklogStore, projectPrefix: projectPrefix}
+}
+
+// Create records one span of effort on a task.
+func (s *TaskWorklogService) Create(ctx context.Context, taskID string, req api.TaskWorklogCreateRequest) (models.TaskWorklog, error) {
+	var zero models.TaskWorklog
+	if s == nil || s.taskStore == nil || s.worklogStore == nil {
+		return zero, internalError(fmt.Errorf("task worklog service is not configured"))
+	}
+
+	actor := strings.TrimSpace(req.Actor)
+	if actor == "" {
+		actor = actorFromContext(ctx)
+	}
+	if actor == "" {
+		return zero, badRequestCode(fmt.Errorf("actor is required"), ErrCodeMissingRequired)
+	}
+	if utf8.RuneCountInString(actor) > maxActorLength {
+		return zero, badRequestCode(fmt.Errorf("actor exceeds %d characters", maxActorLength), ErrCodeInvalidArgument)
+	}
+	if req.Seconds <= 0 || req.Seconds > maxWorklogSeconds {
+		return zero, badRequestCode(fmt.Errorf("seconds must be between 1 and %d", maxWorklogSeconds), ErrCodeInvalidArgument)
+	}
+	note := strings.TrimSpace(req.Note)
+	if len(note) > maxWorklogNoteBytes {
+		return zero, badRequestCode(fmt.Errorf("note exceeds %d bytes", maxWorklogNoteBytes), ErrCodeInvalidArgument)
+	}
+
+	project, err := s.ensureTaskExists(ctx, taskID)
+	if err != nil {
+		return zero, err
+	}
+
+	id, err := store.GenerateTaskWorklogID(func(id string) (bool, error) {
+		return s.worklogStore.TaskWorklogIDExists(ctx, id)
+	})
+	if err != nil {
+		return zero, err
+	}
+
+	now := time.Now().UTC()
+	startedAt := now.Add(-time.Duration(req.Seconds) * time.Second)
+	if req.StartedAt != nil {
+		startedAt = req.StartedAt.UTC()
+	}
+
+	worklog := models.TaskWorklog{
+		Project:   project,
+		ID:        id,
+		TaskID:    strings.TrimSpace(taskID),
+		Actor:     actor,
+		Seconds:   req.Seconds,
+		Note:      note,
+		StartedAt: startedAt,
+		CreatedAt: now,
+	}
+	if err := s.worklogStore.CreateTaskWorklog(ctx, &worklog); err != nil {
+		return zero, err
+	}
+	return worklog, nil
+}
+
+// List returns one task's worklogs ordered by start time.
+func (s *TaskWorklogService) List(ctx context.Context, taskID string) ([]models.TaskWorklog, error) {
+	if s == nil || s.taskStore == nil || s.worklogStore == nil {
+		return nil, internalError(fmt.Errorf("task worklog service is not configured"))
+	}
+
+	project, err := s.ensureTaskExists(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.worklogStore.ListTaskWorklogs(ctx, project, strings.TrimSpace(taskID))
+}
+
+func (s *TaskWorklogService) ensureTaskExists(ctx context.Context, taskID string) (string, error) {
+	taskID = strings.TrimSpace(taskID)
+	if !validateID(taskID) {
+		return "", badRequestCode(fmt.Errorf("invalid task_id"), ErrCodeInvalidID)
+	}
+	project, err := s.project(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !taskIDBelongsToProject(taskID, project) {
+		return "", notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	exists, err := s.taskStore.TaskExists(taskID)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", notFoundCode(fmt.Errorf("task not found"), ErrCodeTaskNotFound)
+	}
+	return project, nil
+}
+
+func (s *TaskWorklogService) project(ctx context.Context) (string, error) {
+	if project, ok := projectFromContext(ctx); ok {
+		return project, nil
+	}
+	return normalizePrefix(s.projectPrefix)
+}
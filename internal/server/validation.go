@@ -16,12 +16,23 @@ var (
 	blobIDRegex       = regexp.MustCompile(`^bl-[0-9a-z]{4}$`)
 	gitRepoIDRegex    = regexp.MustCompile(`^rp-[0-9a-z]{4}$`)
 	gitRefIDRegex     = regexp.MustCompile(`^gf-[0-9a-z]{4}$`)
+	milestoneIDRegex  = regexp.MustCompile(`^ms-[0-9a-z]{4}$`)
+	sha256HexRegex    = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	customFieldRegex  = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_-]{0,63}$`)
 )
 
 func validateID(id string) bool {
 	return idRegex.MatchString(id)
 }
 
+func validateMilestoneID(id string) bool {
+	return milestoneIDRegex.MatchString(id)
+}
+
+func validateCustomFieldName(name string) bool {
+	return customFieldRegex.MatchString(name)
+}
+
 func validateAttachmentID(id string) bool {
 	return attachmentIDRegex.MatchString(id)
 }
@@ -30,6 +41,10 @@ func validateBlobID(id string) bool {
 	return blobIDRegex.MatchString(id)
 }
 
+func validateSHA256Hex(value string) bool {
+	return sha256HexRegex.MatchString(value)
+}
+
 func validateGitRepoID(id string) bool {
 	return gitRepoIDRegex.MatchString(id)
 }
@@ -54,6 +69,18 @@ func normalizeType(value string) (string, error) {
 	return string(taskType), nil
 }
 
+// normalizeDependencyType defaults an empty type to blocks.
+func normalizeDependencyType(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return string(models.DependencyBlocks), nil
+	}
+	depType, err := models.ParseDependencyType(value)
+	if err != nil {
+		return "", badRequestCode(err, ErrCodeInvalidDependency)
+	}
+	return string(depType), nil
+}
+
 func normalizeLabel(value string) (string, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
@@ -85,6 +112,61 @@ func normalizeLabels(values []string) ([]string, error) {
 	return labels, nil
 }
 
+// lenientValidation coerces recoverable validation failures to defaults and
+// records a warning for each. A nil or disabled value validates strictly.
+type lenientValidation struct {
+	enabled  bool
+	warnings []string
+}
+
+func (v *lenientValidation) recover(err error, format string, args ...any) error {
+	if v == nil || !v.enabled {
+		return err
+	}
+	v.warnings = append(v.warnings, fmt.Sprintf(format, args...))
+	return nil
+}
+
+func (v *lenientValidation) status(value, fallback string) (string, error) {
+	status, err := normalizeStatus(value)
+	if err == nil {
+		return status, nil
+	}
+	return fallback, v.recover(err, "invalid status %q; using %s", value, fallback)
+}
+
+func (v *lenientValidation) taskType(value, fallback string) (string, error) {
+	taskType, err := normalizeType(value)
+	if err == nil {
+		return taskType, nil
+	}
+	return fallback, v.recover(err, "invalid type %q; using %s", value, fallback)
+}
+
+func (v *lenientValidation) priority(value int) (int, error) {
+	if models.IsValidPriority(value) {
+		return value, nil
+	}
+	clamped := min(max(value, models.PriorityMin), models.PriorityMax)
+	err := badRequestCode(fmt.Errorf("priority must be between %d and %d", models.PriorityMin, models.PriorityMax), ErrCodeInvalidPriority)
+	return clamped, v.recover(err, "priority %d out of range; clamped to %d", value, clamped)
+}
+
+func (v *lenientValidation) labels(values []string) ([]string, error) {
+	if v == nil || !v.enabled {
+		return normalizeLabels(values)
+	}
+	valid := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, err := normalizeLabel(value); err != nil {
+			v.warnings = append(v.warnings, fmt.Sprintf("invalid label %q dropped", value))
+			continue
+		}
+		valid = append(valid, value)
+	}
+	return normalizeLabels(valid)
+}
+
 func normalizePrefix(prefix string) (string, error) {
 	prefix = strings.ToLower(strings.TrimSpace(prefix))
 	if len(prefix) != 2 {
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"grns/internal/models"
+)
+
+const adminAuditColumns = "id, action, actor, params_json, affected, dry_run, created_at"
+
+// AppendAdminAudit inserts one admin audit entry and sets its ID.
+func (s *Store) AppendAdminAudit(ctx context.Context, entry *models.AdminAuditEntry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry is required")
+	}
+	if strings.TrimSpace(entry.Action) == "" {
+		return fmt.Errorf("audit action is required")
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	var paramsJSON any
+	if len(entry.Params) > 0 {
+		data, err := json.Marshal(entry.Params)
+		if err != nil {
+			return err
+		}
+		paramsJSON = string(data)
+	}
+
+	return s.db.QueryRowContext(ctx, `
+		INSERT INTO admin_audit (action, actor, params_json, affected, dry_run, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`, entry.Action, nullIfEmpty(strings.TrimSpace(entry.Actor)), paramsJSON, entry.Affected, entry.DryRun, dbFormatTime(entry.CreatedAt)).Scan(&entry.ID)
+}
+
+// ListAdminAudit lists admin audit entries newest first.
+func (s *Store) ListAdminAudit(ctx context.Context, filter AdminAuditFilter) ([]models.AdminAuditEntry, error) {
+	conditions := []string{}
+	args := []any{}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.Since != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, dbFormatTime(*filter.Since))
+	}
+
+	query := "SELECT " + adminAuditColumns + " FROM admin_audit"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AdminAuditEntry{}
+	for rows.Next() {
+		entry := models.AdminAuditEntry{}
+		var actor, paramsJSON sql.NullString
+		var dryRun int
+		var createdAt string
+		if err := rows.Scan(&entry.ID, &entry.Action, &actor, &paramsJSON, &entry.Affected, &dryRun, &createdAt); err != nil {
+			return nil, err
+		}
+		entry.Actor = actor.String
+		entry.DryRun = dryRun != 0
+		if paramsJSON.Valid && paramsJSON.String != "" {
+			if err := json.Unmarshal([]byte(paramsJSON.String), &entry.Params); err != nil {
+				return nil, fmt.Errorf("decode admin audit params_json: %w", err)
+			}
+		}
+		parsed, err := dbParseTime(createdAt)
+		if err != nil {
+			return nil, err
+		}
+		entry.CreatedAt = parsed
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"grns/internal/models"
+)
+
+// AdminAuditFilter narrows admin audit listings. Zero values match everything.
+type AdminAuditFilter struct {
+	Action string
+	Actor  string
+	Since  *time.Time
+	Limit  int
+	Offset int
+}
+
+// AdminAuditStore persists the admin operation audit trail.
+type AdminAuditStore interface {
+	AppendAdminAudit(ctx context.Context, entry *models.AdminAuditEntry) error
+	ListAdminAudit(ctx context.Context, filter AdminAuditFilter) ([]models.AdminAuditEntry, error)
+}
+
+var _ AdminAuditStore = (*Store)(nil)
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"grns/internal/models"
+)
+
+func TestAdminAuditAppendAndList(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	base := time.Now().UTC().Truncate(time.Second)
+
+	for i, entry := range []*models.AdminAuditEntry{
+		{Action: "cleanup", Actor: "alice", Params: map[string]any{"older_than_days": 30}, Affected: 4, DryRun: true},
+		{Action: "cleanup", Actor: "alice", Params: map[string]any{"older_than_days": 30}, Affected: 4},
+		{Action: "user.add", Actor: "bob", Params: map[string]any{"username": "carol"}, Affected: 1},
+	} {
+		entry.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if err := st.AppendAdminAudit(ctx, entry); err != nil {
+			t.Fatalf("append audit %d: %v", i, err)
+		}
+		if entry.ID == 0 {
+			t.Fatalf("expected id set on entry %d", i)
+		}
+	}
+
+	all, err := st.ListAdminAudit(ctx, AdminAuditFilter{})
+	if err != nil {
+		t.Fatalf("list audit: %v", err)
+	}
+	if len(all) != 3 || all[0].Action != "user.add" || all[2].DryRun != true {
+		t.Fatalf("expected newest-first entries, got %#v", all)
+	}
+	if all[0].Params["username"] != "carol" {
+		t.Fatalf("expected params round-trip, got %#v", all[0].Params)
+	}
+
+	cleanups, err := st.ListAdminAudit(ctx, AdminAuditFilter{Action: "cleanup", Limit: 1})
+	if err != nil {
+		t.Fatalf("list cleanup audit: %v", err)
+	}
+	if len(cleanups) != 1 || cleanups[0].DryRun || cleanups[0].Affected != 4 {
+		t.Fatalf("unexpected cleanup entries: %#v", cleanups)
+	}
+
+	since := base.Add(90 * time.Second)
+	recent, err := st.ListAdminAudit(ctx, AdminAuditFilter{Since: &since, Actor: "bob"})
+	if err != nil {
+		t.Fatalf("list recent audit: %v", err)
+	}
+	if len(recent) != 1 || recent[0].Actor != "bob" {
+		t.Fatalf("unexpected recent entries: %#v", recent)
+	}
+}
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const apiTokenColumns = "id, name, token_hash, scopes_json, expires_at, created_at, rotated_at"
+
+// CreateAPIToken inserts one named token. ID is generated when empty.
+func (s *Store) CreateAPIToken(ctx context.Context, token *APIToken) error {
+	if token == nil {
+		return fmt.Errorf("api token is required")
+	}
+	if strings.TrimSpace(token.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(token.TokenHash) == "" {
+		return fmt.Errorf("token hash is required")
+	}
+	if token.ID == "" {
+		id, err := generateAuthID("tk")
+		if err != nil {
+			return err
+		}
+		token.ID = id
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now().UTC()
+	}
+	if token.Scopes == nil {
+		token.Scopes = []string{}
+	}
+	scopesJSON, err := json.Marshal(token.Scopes)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO api_tokens (id, name, token_hash, scopes_json, expires_at, created_at, rotated_at)
+		VALUES (?, ?, ?, ?, ?, ?, NULL)
+	`, token.ID, token.Name, token.TokenHash, string(scopesJSON), nullTime(token.ExpiresAt), dbFormatTime(token.CreatedAt))
+	return err
+}
+
+// GetAPIToken returns one token by id, or nil when it does not exist.
+func (s *Store) GetAPIToken(ctx context.Context, id string) (*APIToken, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE id = ?`, strings.TrimSpace(id))
+	return scanAPIToken(row)
+}
+
+// ListAPITokens returns all tokens, including expired ones, ordered by name.
+func (s *Store) ListAPITokens(ctx context.Context) ([]APIToken, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// RotateAPIToken replaces a token's secret hash, invalidating the old secret
+// immediately. It returns nil when the token does not exist.
+func (s *Store) RotateAPIToken(ctx context.Context, id, tokenHash string, now time.Time) (*APIToken, error) {
+	if strings.TrimSpace(tokenHash) == "" {
+		return nil, fmt.Errorf("token hash is required")
+	}
+	result, err := s.db.ExecContext(ctx, "UPDATE api_tokens SET token_hash = ?, rotated_at = ? WHERE id = ?", tokenHash, dbFormatTime(now), strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, nil
+	}
+	return s.GetAPIToken(ctx, id)
+}
+
+// DeleteAPIToken revokes one token by deleting it and reports whether it existed.
+func (s *Store) DeleteAPIToken(ctx context.Context, id string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM api_tokens WHERE id = ?", strings.TrimSpace(id))
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// GetAPITokenByHash returns the unexpired token matching tokenHash, or nil.
+func (s *Store) GetAPITokenByHash(ctx context.Context, tokenHash string, now time.Time) (*APIToken, error) {
+	tokenHash = strings.TrimSpace(tokenHash)
+	if tokenHash == "" {
+		return nil, nil
+	}
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+apiTokenColumns+`
+		FROM api_tokens
+		WHERE token_hash = ?
+		  AND (expires_at IS NULL OR expires_at > ?)
+		LIMIT 1
+	`, tokenHash, dbFormatTime(now))
+	return scanAPIToken(row)
+}
+
+// CountActiveAPITokens returns the number of unexpired tokens.
+func (s *Store) CountActiveAPITokens(ctx context.Context, now time.Time) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM api_tokens WHERE expires_at IS NULL OR expires_at > ?", dbFormatTime(now)).Scan(&count)
+	return count, err
+}
+
+func scanAPIToken(scanner interface {
+	Scan(dest ...any) error
+}) (*APIToken, error) {
+	var token APIToken
+	var scopesJSON string
+	var expiresAt, rotatedAt sql.NullString
+	var createdAt string
+	if err := scanner.Scan(&token.ID, &token.Name, &token.TokenHash, &scopesJSON, &expiresAt, &createdAt, &rotatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(scopesJSON), &token.Scopes); err != nil {
+		return nil, fmt.Errorf("decode scopes for %s: %w", token.ID, err)
+	}
+	parsedCreated, err := dbParseTime(createdAt)
+	if err != nil {
+		return nil, err
+	}
+	token.CreatedAt = parsedCreated
+	if expiresAt.Valid {
+		parsedExpires, err := dbParseTime(expiresAt.String)
+		if err != nil {
+			return nil, err
+		}
+		token.ExpiresAt = &parsedExpires
+	}
+	if rotatedAt.Valid {
+		parsedRotated, err := dbParseTime(rotatedAt.String)
+		if err != nil {
+			return nil, err
+		}
+		token.RotatedAt = &parsedRotated
+	}
+	return &token, nil
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// APIToken is a named bearer token. Only the SHA-256 hash of the secret is
+// persisted.
+type APIToken struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	TokenHash string     `json:"-"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+}
+
+// Expired reports whether the token is past its expiry at now.
+func (t APIToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// APITokenStore persists named API tokens used for bearer authentication.
+type APITokenStore interface {
+	CreateAPIToken(ctx context.Context, token *APIToken) error
+	GetAPIToken(ctx context.Context, id string) (*APIToken, error)
+	ListAPITokens(ctx context.Context) ([]APIToken, error)
+	RotateAPIToken(ctx context.Context, id, tokenHash string, now time.Time) (*APIToken, error)
+	DeleteAPIToken(ctx context.Context, id string) (bool, error)
+	GetAPITokenByHash(ctx context.Context, tokenHash string, now time.Time) (*APIToken, error)
+	CountActiveAPITokens(ctx context.Context, now time.Time) (int, error)
+}
+
+var _ APITokenStore = (*Store)(nil)
@@ -14,7 +14,7 @@ import (
 
 const attachmentColumns = "id, task_id, kind, source_type, title, filename, media_type, media_type_source, blob_id, external_url, repo_path, meta_json, created_at, updated_at, expires_at"
 const qualifiedAttachmentColumns = "a.id, a.task_id, a.kind, a.source_type, a.title, a.filename, a.media_type, a.media_type_source, a.blob_id, a.external_url, a.repo_path, a.meta_json, a.created_at, a.updated_at, a.expires_at"
-const blobColumns = "id, sha256, size_bytes, storage_backend, blob_key, created_at"
+const blobColumns = "id, sha256, size_bytes, storage_backend, blob_key, created_at, quarantined_at"
 
 // CreateAttachment inserts one attachment row and optional labels.
 func (s *Store) CreateAttachment(ctx context.Context, attachment *models.Attachment) (err error) {
@@ -91,29 +91,54 @@ func (s *Store) GetAttachment(ctx context.Context, project, id string) (*models.
 
 // ListAttachmentsByTask lists attachments for a task ordered by created_at descending.
 func (s *Store) ListAttachmentsByTask(ctx context.Context, project, taskID string) ([]models.Attachment, error) {
+	byTask, err := s.ListAttachmentsForTasks(ctx, project, []string{taskID})
+	if err != nil {
+		return nil, err
+	}
+	attachments := byTask[taskID]
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+	return attachments, nil
+}
+
+// ListAttachmentsForTasks returns attachments with labels keyed by task id,
+// each list ordered by created_at descending. Labels are loaded with one
+// joined query for all tasks rather than one query per attachment.
+func (s *Store) ListAttachmentsForTasks(ctx context.Context, project string, taskIDs []string) (map[string][]models.Attachment, error) {
+	attachments := make(map[string][]models.Attachment)
+	if len(taskIDs) == 0 {
+		return attachments, nil
+	}
 	project = normalizeProject(project)
 
-	var (
-		rows *sql.Rows
-		err  error
-	)
-	if project == "" {
-		rows, err = s.db.QueryContext(ctx, `SELECT `+attachmentColumns+` FROM attachments WHERE task_id = ? ORDER BY created_at DESC`, taskID)
-	} else {
-		rows, err = s.db.QueryContext(ctx, `
+	args := make([]any, 0, len(taskIDs)+1)
+	for _, id := range taskIDs {
+		args = append(args, id)
+	}
+	query := fmt.Sprintf(`
+		SELECT `+qualifiedAttachmentColumns+`
+		FROM attachments a
+		WHERE a.task_id IN (%s)
+		ORDER BY a.created_at DESC
+	`, placeholders(len(taskIDs)))
+	if project != "" {
+		query = fmt.Sprintf(`
 			SELECT `+qualifiedAttachmentColumns+`
 			FROM attachments a
 			JOIN tasks t ON t.id = a.task_id
-			WHERE a.task_id = ? AND t.project_id = ?
+			WHERE a.task_id IN (%s) AND t.project_id = ?
 			ORDER BY a.created_at DESC
-		`, taskID, project)
+		`, placeholders(len(taskIDs)))
+		args = append(args, project)
 	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	attachments := []models.Attachment{}
+	found := 0
 	for rows.Next() {
 		attachment, err := scanAttachment(rows)
 		if err != nil {
@@ -122,20 +147,29 @@ func (s *Store) ListAttachmentsByTask(ctx context.Context, project, taskID strin
 		if attachment == nil {
 			continue
 		}
-		attachments = append(attachments, *attachment)
+		attachments[attachment.TaskID] = append(attachments[attachment.TaskID], *attachment)
+		found++
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	if found == 0 {
+		return attachments, nil
+	}
 
-	for i := range attachments {
-		labels, err := s.ListAttachmentLabels(ctx, attachments[i].ID)
-		if err != nil {
-			return nil, err
+	labels, err := s.listAttachmentLabelsForTasks(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	for taskID, list := range attachments {
+		for i := range list {
+			list[i].Labels = labels[list[i].ID]
+			if list[i].Labels == nil {
+				list[i].Labels = []string{}
+			}
 		}
-		attachments[i].Labels = labels
+		attachments[taskID] = list
 	}
-
 	return attachments, nil
 }
 
@@ -176,6 +210,62 @@ func (s *Store) ReplaceAttachmentLabels(ctx context.Context, attachmentID string
 	return tx.Commit()
 }
 
+// AttachmentUpdate describes attachment metadata to update. A zero ExpiresAt
+// clears the expiry; Labels replaces all labels.
+type AttachmentUpdate struct {
+	Title     *string
+	Labels    *[]string
+	Meta      *map[string]any
+	ExpiresAt *time.Time
+	UpdatedAt time.Time
+}
+
+// UpdateAttachment applies update to one attachment and its labels in one transaction.
+func (s *Store) UpdateAttachment(ctx context.Context, id string, update AttachmentUpdate) (err error) {
+	set := []string{"updated_at = ?"}
+	args := []any{dbFormatTime(update.UpdatedAt)}
+	if update.Title != nil {
+		set = append(set, "title = ?")
+		args = append(args, nullIfEmpty(strings.TrimSpace(*update.Title)))
+	}
+	if update.Meta != nil {
+		metaJSON, err := attachmentMetaToJSON(*update.Meta)
+		if err != nil {
+			return err
+		}
+		set = append(set, "meta_json = ?")
+		args = append(args, metaJSON)
+	}
+	if update.ExpiresAt != nil {
+		set = append(set, "expires_at = ?")
+		args = append(args, nullTime(update.ExpiresAt))
+	}
+	args = append(args, id)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE attachments SET "+strings.Join(set, ", ")+" WHERE id = ?", args...); err != nil {
+		return err
+	}
+	if update.Labels != nil {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM attachment_labels WHERE attachment_id = ?", id); err != nil {
+			return err
+		}
+		if err := insertAttachmentLabelsTx(ctx, tx, id, normalizeAttachmentLabels(*update.Labels)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 // ListAttachmentLabels lists labels for one attachment.
 func (s *Store) ListAttachmentLabels(ctx context.Context, attachmentID string) ([]string, error) {
 	rows, err := s.db.QueryContext(ctx, "SELECT label FROM attachment_labels WHERE attachment_id = ? ORDER BY label ASC", attachmentID)
@@ -195,6 +285,59 @@ func (s *Store) ListAttachmentLabels(ctx context.Context, attachmentID string) (
 	return labels, rows.Err()
 }
 
+// ListAttachmentLabelsForAttachments returns labels keyed by attachment id,
+// each list sorted. Attachments without labels are absent.
+func (s *Store) ListAttachmentLabelsForAttachments(ctx context.Context, ids []string) (map[string][]string, error) {
+	if len(ids) == 0 {
+		return map[string][]string{}, nil
+	}
+	query := fmt.Sprintf(`
+		SELECT attachment_id, label FROM attachment_labels
+		WHERE attachment_id IN (%s)
+		ORDER BY attachment_id, label
+	`, placeholders(len(ids)))
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	return s.queryAttachmentLabels(ctx, query, args...)
+}
+
+// listAttachmentLabelsForTasks loads the labels of every attachment on the
+// given tasks by joining through attachments, keyed by attachment id.
+func (s *Store) listAttachmentLabelsForTasks(ctx context.Context, taskIDs []string) (map[string][]string, error) {
+	query := fmt.Sprintf(`
+		SELECT al.attachment_id, al.label
+		FROM attachment_labels al
+		JOIN attachments a ON a.id = al.attachment_id
+		WHERE a.task_id IN (%s)
+		ORDER BY al.attachment_id, al.label
+	`, placeholders(len(taskIDs)))
+	args := make([]any, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		args = append(args, id)
+	}
+	return s.queryAttachmentLabels(ctx, query, args...)
+}
+
+func (s *Store) queryAttachmentLabels(ctx context.Context, query string, args ...any) (map[string][]string, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := make(map[string][]string)
+	for rows.Next() {
+		var attachmentID, label string
+		if err := rows.Scan(&attachmentID, &label); err != nil {
+			return nil, err
+		}
+		labels[attachmentID] = append(labels[attachmentID], label)
+	}
+	return labels, rows.Err()
+}
+
 // UpsertBlob inserts a blob if absent and returns the canonical row by sha256.
 func (s *Store) UpsertBlob(ctx context.Context, blob *models.Blob) (*models.Blob, error) {
 	if blob == nil {
@@ -236,6 +379,9 @@ func (s *Store) UpsertBlob(ctx context.Context, blob *models.Blob) (*models.Blob
 	if err != nil {
 		return nil, err
 	}
+	if err := quarantineBlob(ctx, s.db, blob); err != nil {
+		return nil, err
+	}
 
 	return s.GetBlobBySHA256(ctx, blob.SHA256)
 }
@@ -310,6 +456,9 @@ func (s *Store) CreateManagedAttachmentWithBlob(ctx context.Context, blob *model
 	`, blob.ID, blob.SHA256, blob.SizeBytes, blob.StorageBackend, blob.BlobKey, dbFormatTime(blob.CreatedAt)); err != nil {
 		return nil, err
 	}
+	if err = quarantineBlob(ctx, tx, blob); err != nil {
+		return nil, err
+	}
 
 	canonicalBlob, err := scanBlob(tx.QueryRowContext(ctx, `SELECT `+blobColumns+` FROM blobs WHERE sha256 = ?`, blob.SHA256))
 	if err != nil {
@@ -349,7 +498,7 @@ func (s *Store) GetBlobBySHA256(ctx context.Context, sha string) (*models.Blob,
 // ListUnreferencedBlobs returns blobs that are not referenced by attachments.
 func (s *Store) ListUnreferencedBlobs(ctx context.Context, limit int) ([]models.Blob, error) {
 	query := `
-		SELECT b.id, b.sha256, b.size_bytes, b.storage_backend, b.blob_key, b.created_at
+		SELECT b.id, b.sha256, b.size_bytes, b.storage_backend, b.blob_key, b.created_at, b.quarantined_at
 		FROM blobs b
 		LEFT JOIN attachments a ON a.blob_id = b.id
 		WHERE a.id IS NULL
@@ -382,6 +531,117 @@ func (s *Store) ListUnreferencedBlobs(ctx context.Context, limit int) ([]models.
 	return blobs, nil
 }
 
+// BlobUsageStats counts stored blobs and bytes, the managed attachments that
+// reference them, and the blobs no attachment references.
+func (s *Store) BlobUsageStats(ctx context.Context) (models.BlobUsageStats, error) {
+	stats := models.BlobUsageStats{}
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(SUM(size_bytes), 0) FROM blobs`).Scan(&stats.BlobCount, &stats.TotalBytes); err != nil {
+		return stats, err
+	}
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(b.size_bytes), 0)
+		FROM attachments a
+		JOIN blobs b ON b.id = a.blob_id
+	`).Scan(&stats.ManagedAttachmentCount, &stats.LogicalBytes); err != nil {
+		return stats, err
+	}
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(b.size_bytes), 0)
+		FROM blobs b
+		WHERE NOT EXISTS (SELECT 1 FROM attachments a WHERE a.blob_id = b.id)
+	`).Scan(&stats.UnreferencedCount, &stats.UnreferencedBytes); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// ListLargestBlobs returns up to limit blobs by descending size, each with the
+// number of attachments that reference it.
+func (s *Store) ListLargestBlobs(ctx context.Context, limit int) ([]models.BlobUsage, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT b.id, b.sha256, b.size_bytes, b.storage_backend, b.blob_key, b.created_at, b.quarantined_at, COUNT(a.id)
+		FROM blobs b
+		LEFT JOIN attachments a ON a.blob_id = b.id
+		GROUP BY b.id
+		ORDER BY b.size_bytes DESC, b.id ASC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := []models.BlobUsage{}
+	for rows.Next() {
+		var entry models.BlobUsage
+		var createdAt string
+		var quarantinedAt sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.SHA256, &entry.SizeBytes, &entry.StorageBackend, &entry.BlobKey, &createdAt, &quarantinedAt, &entry.AttachmentCount); err != nil {
+			return nil, err
+		}
+		if entry.CreatedAt, err = dbParseTime(createdAt); err != nil {
+			return nil, err
+		}
+		if entry.QuarantinedAt, err = dbParseNullTime(quarantinedAt); err != nil {
+			return nil, err
+		}
+		usage = append(usage, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// ListAttachmentsWithMissingBlob returns managed attachments whose blob_id has no blobs row.
+// This is the inverse of ListUnreferencedBlobs and only finds rows left by out-of-band deletes.
+func (s *Store) ListAttachmentsWithMissingBlob(ctx context.Context) ([]models.Attachment, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+qualifiedAttachmentColumns+`
+		FROM attachments a
+		LEFT JOIN blobs b ON b.id = a.blob_id
+		WHERE a.source_type = ? AND b.id IS NULL
+		ORDER BY a.created_at ASC, a.id ASC
+	`, string(models.AttachmentSourceManagedBlob))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []models.Attachment{}
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		if attachment != nil {
+			attachments = append(attachments, *attachment)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// MarkAttachmentsBlobMissing sets meta.blob_missing=true on the given attachments.
+func (s *Store) MarkAttachmentsBlobMissing(ctx context.Context, ids []string, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	setMissing, args := s.dialect.jsonSetTrue("meta_json", "blob_missing")
+	args = append(args, dbFormatTime(now))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE attachments
+		SET meta_json = %s, updated_at = ?
+		WHERE id IN (%s)
+	`, setMissing, placeholders(len(ids))), args...)
+	return err
+}
+
 // DeleteBlob deletes one blob row by id.
 func (s *Store) DeleteBlob(ctx context.Context, id string) error {
 	_, err := s.db.ExecContext(ctx, "DELETE FROM blobs WHERE id = ?", id)
@@ -509,13 +769,27 @@ func scanAttachment(scanner interface {
 	return &attachment, nil
 }
 
+// quarantineBlob marks the stored blob with blob's digest quarantined when
+// blob.QuarantinedAt is set. The mark is never cleared, so an upload that
+// reuses the content cannot lift it.
+func quarantineBlob(ctx context.Context, execer interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+}, blob *models.Blob) error {
+	if blob.QuarantinedAt == nil {
+		return nil
+	}
+	_, err := execer.ExecContext(ctx, "UPDATE blobs SET quarantined_at = ? WHERE sha256 = ? AND quarantined_at IS NULL", dbFormatTime(*blob.QuarantinedAt), blob.SHA256)
+	return err
+}
+
 func scanBlob(scanner interface {
 	Scan(dest ...any) error
 }) (*models.Blob, error) {
 	blob := models.Blob{}
 	var createdAt string
+	var quarantinedAt sql.NullString
 
-	err := scanner.Scan(&blob.ID, &blob.SHA256, &blob.SizeBytes, &blob.StorageBackend, &blob.BlobKey, &createdAt)
+	err := scanner.Scan(&blob.ID, &blob.SHA256, &blob.SizeBytes, &blob.StorageBackend, &blob.BlobKey, &createdAt, &quarantinedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -528,6 +802,9 @@ func scanBlob(scanner interface {
 		return nil, err
 	}
 	blob.CreatedAt = parsedCreated
+	if blob.QuarantinedAt, err = dbParseNullTime(quarantinedAt); err != nil {
+		return nil, err
+	}
 
 	return &blob, nil
 }
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"grns/internal/models"
 )
@@ -14,17 +15,24 @@ type AttachmentStore interface {
 	CreateAttachment(ctx context.Context, attachment *models.Attachment) error
 	GetAttachment(ctx context.Context, project, id string) (*models.Attachment, error)
 	ListAttachmentsByTask(ctx context.Context, project, taskID string) ([]models.Attachment, error)
+	ListAttachmentsForTasks(ctx context.Context, project string, taskIDs []string) (map[string][]models.Attachment, error)
+	UpdateAttachment(ctx context.Context, id string, update AttachmentUpdate) error
 	DeleteAttachment(ctx context.Context, project, id string) error
 
 	ReplaceAttachmentLabels(ctx context.Context, attachmentID string, labels []string) error
 	ListAttachmentLabels(ctx context.Context, attachmentID string) ([]string, error)
+	ListAttachmentLabelsForAttachments(ctx context.Context, ids []string) (map[string][]string, error)
 
 	UpsertBlob(ctx context.Context, blob *models.Blob) (*models.Blob, error)
 	CreateManagedAttachmentWithBlob(ctx context.Context, blob *models.Blob, attachment *models.Attachment) (*models.Blob, error)
 	GetBlob(ctx context.Context, id string) (*models.Blob, error)
 	GetBlobBySHA256(ctx context.Context, sha string) (*models.Blob, error)
 	ListUnreferencedBlobs(ctx context.Context, limit int) ([]models.Blob, error)
+	BlobUsageStats(ctx context.Context) (models.BlobUsageStats, error)
+	ListLargestBlobs(ctx context.Context, limit int) ([]models.BlobUsage, error)
 	DeleteBlob(ctx context.Context, id string) error
+	ListAttachmentsWithMissingBlob(ctx context.Context) ([]models.Attachment, error)
+	MarkAttachmentsBlobMissing(ctx context.Context, ids []string, now time.Time) error
 }
 
 var _ AttachmentStore = (*Store)(nil)
@@ -2,7 +2,9 @@ package store
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -304,3 +306,136 @@ func TestListUnreferencedBlobs_OnlyReturnsUnattachedBlobs(t *testing.T) {
 		t.Fatalf("attached blob %s must not appear in limited unreferenced results", attached.ID)
 	}
 }
+
+func TestListAttachmentsWithMissingBlob_ReportsOutOfBandBlobDelete(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	task := &models.Task{ID: "gr-bm11", Title: "Blob task", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	for _, id := range []string{"bl-f111", "bl-f222"} {
+		blob, err := st.UpsertBlob(ctx, &models.Blob{ID: id, SHA256: strings.Repeat(id[len(id)-1:], 64), SizeBytes: 4, StorageBackend: "local_cas", BlobKey: "sha256/" + id, CreatedAt: now})
+		if err != nil {
+			t.Fatalf("upsert blob %s: %v", id, err)
+		}
+		if err := st.CreateAttachment(ctx, &models.Attachment{
+			ID:              "at-" + id[3:],
+			TaskID:          task.ID,
+			Kind:            string(models.AttachmentKindArtifact),
+			SourceType:      string(models.AttachmentSourceManagedBlob),
+			MediaTypeSource: string(models.MediaTypeSourceUnknown),
+			BlobID:          blob.ID,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}); err != nil {
+			t.Fatalf("create attachment for %s: %v", id, err)
+		}
+	}
+
+	// Simulate an out-of-band delete, e.g. via the sqlite shell where foreign keys are off.
+	conn, err := st.db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("conn: %v", err)
+	}
+	for _, stmt := range []string{"PRAGMA foreign_keys = OFF", "DELETE FROM blobs WHERE id = 'bl-f111'", "PRAGMA foreign_keys = ON"} {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("exec %q: %v", stmt, err)
+		}
+	}
+	_ = conn.Close()
+
+	dangling, err := st.ListAttachmentsWithMissingBlob(ctx)
+	if err != nil {
+		t.Fatalf("list dangling attachments: %v", err)
+	}
+	if len(dangling) != 1 || dangling[0].ID != "at-f111" {
+		t.Fatalf("expected only at-f111 to be dangling, got %+v", dangling)
+	}
+
+	if err := st.MarkAttachmentsBlobMissing(ctx, []string{"at-f111"}, now.Add(time.Minute)); err != nil {
+		t.Fatalf("mark dangling attachment: %v", err)
+	}
+	marked, err := st.GetAttachment(ctx, "", "at-f111")
+	if err != nil {
+		t.Fatalf("get marked attachment: %v", err)
+	}
+	if marked.Meta["blob_missing"] != true {
+		t.Fatalf("expected blob_missing meta, got %+v", marked.Meta)
+	}
+}
+
+func TestListAttachmentsForTasks_BatchesLabelHydration(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	taskIDs := []string{"gr-ab01", "gr-ab02", "gr-ab03"}
+	for _, id := range taskIDs {
+		task := &models.Task{ID: id, Title: "Task " + id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create task %s: %v", id, err)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		taskID := taskIDs[i%2]
+		attachment := &models.Attachment{
+			ID:              fmt.Sprintf("at-b%03d", i),
+			TaskID:          taskID,
+			Kind:            string(models.AttachmentKindSpec),
+			SourceType:      string(models.AttachmentSourceExternalURL),
+			ExternalURL:     fmt.Sprintf("https://example.com/%d", i),
+			MediaType:       "text/html",
+			MediaTypeSource: string(models.MediaTypeSourceDeclared),
+			Labels:          []string{"zeta", fmt.Sprintf("n%d", i)},
+			CreatedAt:       now.Add(time.Duration(i) * time.Second),
+			UpdatedAt:       now.Add(time.Duration(i) * time.Second),
+		}
+		if err := st.CreateAttachment(ctx, attachment); err != nil {
+			t.Fatalf("create attachment %d: %v", i, err)
+		}
+	}
+
+	var queries atomic.Int64
+	st.SetQueryObserver(func(op string, _ time.Duration) {
+		if op == "query" {
+			queries.Add(1)
+		}
+	})
+	byTask, err := st.ListAttachmentsForTasks(ctx, "gr", taskIDs)
+	st.SetQueryObserver(nil)
+	if err != nil {
+		t.Fatalf("list attachments for tasks: %v", err)
+	}
+	if got := queries.Load(); got != 2 {
+		t.Fatalf("expected 2 queries for 20 attachments, got %d", got)
+	}
+	if len(byTask["gr-ab01"]) != 10 || len(byTask["gr-ab02"]) != 10 || len(byTask["gr-ab03"]) != 0 {
+		t.Fatalf("unexpected grouping: %d/%d/%d", len(byTask["gr-ab01"]), len(byTask["gr-ab02"]), len(byTask["gr-ab03"]))
+	}
+	first := byTask["gr-ab02"][0]
+	if first.ID != "at-b019" {
+		t.Fatalf("expected newest attachment first, got %s", first.ID)
+	}
+	if len(first.Labels) != 2 || first.Labels[0] != "n19" || first.Labels[1] != "zeta" {
+		t.Fatalf("expected sorted labels [n19 zeta], got %v", first.Labels)
+	}
+
+	single, err := st.ListAttachmentsByTask(ctx, "gr", "gr-ab03")
+	if err != nil {
+		t.Fatalf("list attachments by task: %v", err)
+	}
+	if single == nil || len(single) != 0 {
+		t.Fatalf("expected empty non-nil list, got %v", single)
+	}
+
+	labels, err := st.ListAttachmentLabelsForAttachments(ctx, []string{"at-b000", "at-b001", "at-missing"})
+	if err != nil {
+		t.Fatalf("list labels for attachments: %v", err)
+	}
+	if len(labels) != 2 || len(labels["at-b001"]) != 2 || labels["at-b001"][0] != "n1" {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+}
@@ -158,3 +158,68 @@ func TestAuthUserManagementLifecycle(t *testing.T) {
 		t.Fatalf("expected only alice to remain, got %+v", users)
 	}
 }
+
+func TestAPITokenLifecycle(t *testing.T) {
+	st, ctx := openAuthTestStore(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	past := now.Add(-time.Hour)
+
+	ci := &APIToken{Name: "ci", TokenHash: "hash-ci", Scopes: []string{"read", "write"}, CreatedAt: now}
+	if err := st.CreateAPIToken(ctx, ci); err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if ci.ID == "" {
+		t.Fatal("expected generated token id")
+	}
+	if err := st.CreateAPIToken(ctx, &APIToken{Name: "old", TokenHash: "hash-old", ExpiresAt: &past, CreatedAt: now}); err != nil {
+		t.Fatalf("create expired token: %v", err)
+	}
+	if err := st.CreateAPIToken(ctx, &APIToken{Name: "ci", TokenHash: "hash-dup", CreatedAt: now}); err == nil {
+		t.Fatal("expected duplicate name to fail")
+	}
+
+	count, err := st.CountActiveAPITokens(ctx, now)
+	if err != nil {
+		t.Fatalf("count active tokens: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 active token, got %d", count)
+	}
+	if got, err := st.GetAPITokenByHash(ctx, "hash-old", now); err != nil || got != nil {
+		t.Fatalf("expected expired token lookup to miss, got %#v (%v)", got, err)
+	}
+	got, err := st.GetAPITokenByHash(ctx, "hash-ci", now)
+	if err != nil {
+		t.Fatalf("lookup token: %v", err)
+	}
+	if got == nil || got.ID != ci.ID || len(got.Scopes) != 2 {
+		t.Fatalf("unexpected token lookup: %#v", got)
+	}
+
+	rotated, err := st.RotateAPIToken(ctx, ci.ID, "hash-ci-2", now)
+	if err != nil {
+		t.Fatalf("rotate token: %v", err)
+	}
+	if rotated == nil || rotated.RotatedAt == nil {
+		t.Fatalf("expected rotated_at set, got %#v", rotated)
+	}
+	if got, _ := st.GetAPITokenByHash(ctx, "hash-ci", now); got != nil {
+		t.Fatal("expected old secret to stop working after rotation")
+	}
+
+	tokens, err := st.ListAPITokens(ctx)
+	if err != nil {
+		t.Fatalf("list tokens: %v", err)
+	}
+	if len(tokens) != 2 || tokens[0].Name != "ci" || !tokens[1].Expired(now) {
+		t.Fatalf("unexpected tokens: %#v", tokens)
+	}
+
+	deleted, err := st.DeleteAPIToken(ctx, ci.ID)
+	if err != nil || !deleted {
+		t.Fatalf("delete token: deleted=%v err=%v", deleted, err)
+	}
+	if deleted, _ := st.DeleteAPIToken(ctx, ci.ID); deleted {
+		t.Fatal("expected second delete to report missing")
+	}
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"modernc.org/sqlite"
+)
+
+// backupStepPages is how many pages one backup step copies. Between steps
+// the source lock is released so writers are not stalled by large copies.
+const backupStepPages = 256
+
+type sqliteBackuper interface {
+	NewBackup(dstURI string) (*sqlite.Backup, error)
+}
+
+// BackupTo writes a consistent snapshot of the database to path using
+// SQLite's online backup API. Copying the live file directly can capture a
+// torn write or miss WAL contents. The destination must not exist yet.
+func (s *Store) BackupTo(ctx context.Context, path string) error {
+	if err := s.requireSQLite("backup"); err != nil {
+		return err
+	}
+	ctx = WithoutQueryTimeout(ctx)
+	if path == "" {
+		return fmt.Errorf("backup path is required")
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("backup destination %s already exists", path)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		if observed, ok := driverConn.(*observedConn); ok {
+			driverConn = observed.Conn
+		}
+		backuper, ok := driverConn.(sqliteBackuper)
+		if !ok {
+			return fmt.Errorf("sqlite driver does not support online backup")
+		}
+		backup, err := backuper.NewBackup(path)
+		if err != nil {
+			return err
+		}
+		for {
+			more, err := backup.Step(backupStepPages)
+			if err == nil && more {
+				err = ctx.Err()
+			}
+			if err != nil {
+				_ = backup.Finish()
+				_ = os.Remove(path)
+				return err
+			}
+			if !more {
+				break
+			}
+		}
+		return backup.Finish()
+	})
+}
@@ -0,0 +1,11 @@
+package store
+
+import "context"
+
+// BackupStore produces consistent snapshots of the live database.
+type BackupStore interface {
+	// BackupTo copies the database into a new SQLite file at path.
+	BackupTo(ctx context.Context, path string) error
+}
+
+var _ BackupStore = (*Store)(nil)
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"grns/internal/models"
+)
+
+func TestBackupToProducesOpenableSnapshot(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	if err := st.AppendAdminAudit(ctx, &models.AdminAuditEntry{Action: "cleanup", CreatedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("append audit: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "backup.db")
+	if err := st.BackupTo(ctx, path); err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	if err := st.BackupTo(ctx, path); err == nil {
+		t.Fatal("expected error when backup destination exists")
+	}
+
+	restored, err := Open(path)
+	if err != nil {
+		t.Fatalf("open backup: %v", err)
+	}
+	defer restored.Close()
+	entries, err := restored.ListAdminAudit(ctx, AdminAuditFilter{})
+	if err != nil {
+		t.Fatalf("list restored audit: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != "cleanup" {
+		t.Fatalf("expected snapshot to contain audit entry, got %+v", entries)
+	}
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"grns/internal/models"
+)
+
+// LatestChangeSeq returns the newest change sequence number, or 0 when nothing has changed.
+func (s *Store) LatestChangeSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(seq), 0) FROM task_changes").Scan(&seq)
+	return seq, err
+}
+
+// ListTaskChanges lists tasks whose latest change falls in (AfterSeq, ThroughSeq], oldest change first.
+// Tasks deleted since are listed as tombstones.
+func (s *Store) ListTaskChanges(ctx context.Context, filter ChangeFilter) ([]TaskChange, error) {
+	where := []string{"task_changes.project_id = ?", "task_changes.seq > ?"}
+	args := []any{normalizeProject(filter.Project), filter.AfterSeq}
+	if filter.ThroughSeq > 0 {
+		where = append(where, "task_changes.seq <= ?")
+		args = append(args, filter.ThroughSeq)
+	}
+	if filter.UpdatedSince != nil {
+		where = append(where, "COALESCE(task_changes.deleted_at, tasks.updated_at) >= ?")
+		args = append(args, dbFormatTime(*filter.UpdatedSince))
+	}
+	query := "SELECT task_changes.seq, task_changes.task_id, task_changes.deleted_at FROM task_changes LEFT JOIN tasks ON tasks.id = task_changes.task_id WHERE " +
+		strings.Join(where, " AND ") + " ORDER BY task_changes.seq"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []TaskChange{}
+	var liveIDs []any
+	for rows.Next() {
+		var change TaskChange
+		var deletedAt sql.NullString
+		if err := rows.Scan(&change.Seq, &change.Task.ID, &deletedAt); err != nil {
+			return nil, err
+		}
+		if deletedAt.Valid {
+			deleted, err := dbParseTime(deletedAt.String)
+			if err != nil {
+				return nil, err
+			}
+			change.Deleted = true
+			change.Task.Project = normalizeProject(filter.Project)
+			change.Task.Status = string(models.StatusTombstone)
+			change.Task.UpdatedAt = deleted
+			change.Task.DeletedAt = &deleted
+		} else {
+			liveIDs = append(liveIDs, change.Task.ID)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(liveIDs) == 0 {
+		return changes, nil
+	}
+
+	tasks, err := s.tasksByID(ctx, liveIDs)
+	if err != nil {
+		return nil, err
+	}
+	// A task deleted since the first query is skipped; its tombstone comes
+	// later in the sequence.
+	live := changes[:0]
+	for _, change := range changes {
+		if !change.Deleted {
+			task, ok := tasks[change.Task.ID]
+			if !ok {
+				continue
+			}
+			change.Task = *task
+		}
+		live = append(live, change)
+	}
+	return live, nil
+}
+
+func (s *Store) tasksByID(ctx context.Context, ids []any) (map[string]*models.Task, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE id IN ("+placeholders(len(ids))+")", ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := make(map[string]*models.Task, len(ids))
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks[task.ID] = task
+	}
+	return tasks, rows.Err()
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"grns/internal/models"
+)
+
+// ChangeFilter selects tasks by their position in the change sequence.
+// Zero ThroughSeq means no upper bound; UpdatedSince additionally keeps tasks
+// whose updated_at is at or after it.
+type ChangeFilter struct {
+	Project      string
+	AfterSeq     int64
+	ThroughSeq   int64
+	UpdatedSince *time.Time
+	Limit        int
+}
+
+// TaskChange is a task with the sequence number of its latest change. For a
+// task that was since deleted outright, Deleted is set and Task carries only
+// its ID, project, tombstone status, and deletion time.
+type TaskChange struct {
+	Seq     int64
+	Task    models.Task
+	Deleted bool
+}
+
+// ChangeFeedStore reads tasks in the order they last changed. Every task,
+// label, or dependency write moves the task to the end of the sequence, and
+// so does deleting it.
+type ChangeFeedStore interface {
+	LatestChangeSeq(ctx context.Context) (int64, error)
+	ListTaskChanges(ctx context.Context, filter ChangeFilter) ([]TaskChange, error)
+}
+
+var _ ChangeFeedStore = (*Store)(nil)
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"grns/internal/models"
+)
+
+const taskCommentColumns = "c.id, c.task_id, c.author, c.body, c.created_at"
+
+// CreateTaskComment inserts one task comment row.
+func (s *Store) CreateTaskComment(ctx context.Context, comment *models.TaskComment) error {
+	if comment == nil {
+		return fmt.Errorf("task comment is required")
+	}
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now().UTC()
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO task_comments (id, task_id, author, body, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`,
+		comment.ID,
+		comment.TaskID,
+		comment.Author,
+		comment.Body,
+		dbFormatTime(comment.CreatedAt),
+	)
+	return err
+}
+
+// ListTaskComments lists comments for one task ordered oldest first.
+func (s *Store) ListTaskComments(ctx context.Context, project, taskID string) ([]models.TaskComment, error) {
+	project = normalizeProject(project)
+
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if project == "" {
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT `+taskCommentColumns+`
+			FROM task_comments c
+			WHERE c.task_id = ?
+			ORDER BY c.created_at, c.rowid
+		`, taskID)
+	} else {
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT `+taskCommentColumns+`
+			FROM task_comments c
+			JOIN tasks t ON t.id = c.task_id
+			WHERE c.task_id = ? AND t.project_id = ?
+			ORDER BY c.created_at, c.rowid
+		`, taskID, project)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []models.TaskComment{}
+	for rows.Next() {
+		comment, err := scanTaskComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+// ListCommentsForTasks returns comments keyed by task id, each thread ordered oldest first.
+func (s *Store) ListCommentsForTasks(ctx context.Context, ids []string) (map[string][]models.TaskComment, error) {
+	comments := make(map[string][]models.TaskComment)
+	if len(ids) == 0 {
+		return comments, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT `+taskCommentColumns+`
+		FROM task_comments c
+		WHERE c.task_id IN (%s)
+		ORDER BY c.task_id, c.created_at, c.rowid
+	`, placeholders(len(ids)))
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		comment, err := scanTaskComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments[comment.TaskID] = append(comments[comment.TaskID], comment)
+	}
+	return comments, rows.Err()
+}
+
+// TaskCommentIDExists reports whether a comment id is already taken.
+func (s *Store) TaskCommentIDExists(ctx context.Context, id string) (bool, error) {
+	var exists int
+	err := s.db.QueryRowContext(ctx, "SELECT 1 FROM task_comments WHERE id = ?", id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func scanTaskComment(scanner interface {
+	Scan(dest ...any) error
+}) (models.TaskComment, error) {
+	comment := models.TaskComment{}
+	var createdAt string
+	if err := scanner.Scan(&comment.ID, &comment.TaskID, &comment.Author, &comment.Body, &createdAt); err != nil {
+		return models.TaskComment{}, err
+	}
+	parsed, err := dbParseTime(createdAt)
+	if err != nil {
+		return models.TaskComment{}, err
+	}
+	comment.Project = projectFromTaskID(comment.TaskID)
+	comment.CreatedAt = parsed
+	return comment, nil
+}
@@ -0,0 +1,17 @@
+package store
+
+import (
+	"context"
+
+	"grns/internal/models"
+)
+
+// CommentStore is the persistence surface for task comment threads.
+type CommentStore interface {
+	CreateTaskComment(ctx context.Context, comment *models.TaskComment) error
+	ListTaskComments(ctx context.Context, project, taskID string) ([]models.TaskComment, error)
+	ListCommentsForTasks(ctx context.Context, ids []string) (map[string][]models.TaskComment, error)
+	TaskCommentIDExists(ctx context.Context, id string) (bool, error)
+}
+
+var _ CommentStore = (*Store)(nil)
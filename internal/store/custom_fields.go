@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"grns/internal/models"
+)
+
+const customFieldColumns = "project_id, name, type, required, values_json, created_at, updated_at"
+
+// PutCustomField creates or replaces one custom field definition, keeping the
+// original created_at when the field already exists.
+func (s *Store) PutCustomField(ctx context.Context, field *models.CustomField) error {
+	if field == nil {
+		return fmt.Errorf("custom field is required")
+	}
+	now := time.Now().UTC()
+	if field.CreatedAt.IsZero() {
+		field.CreatedAt = now
+	}
+	if field.UpdatedAt.IsZero() {
+		field.UpdatedAt = field.CreatedAt
+	}
+	valuesJSON, err := customFieldValuesToJSON(field.Values)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO custom_fields (project_id, name, type, required, values_json, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (project_id, name) DO UPDATE SET
+			type = excluded.type,
+			required = excluded.required,
+			values_json = excluded.values_json,
+			updated_at = excluded.updated_at
+	`,
+		normalizeProject(field.Project),
+		field.Name,
+		field.Type,
+		field.Required,
+		valuesJSON,
+		dbFormatTime(field.CreatedAt),
+		dbFormatTime(field.UpdatedAt),
+	)
+	return err
+}
+
+// ListCustomFields lists a project's custom field definitions ordered by name.
+func (s *Store) ListCustomFields(ctx context.Context, project string) ([]models.CustomField, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+customFieldColumns+` FROM custom_fields WHERE project_id = ? ORDER BY name`, normalizeProject(project))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := []models.CustomField{}
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, rows.Err()
+}
+
+// DeleteCustomField deletes one custom field definition and reports whether it
+// existed. Values already stored on tasks are left untouched.
+func (s *Store) DeleteCustomField(ctx context.Context, project, name string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM custom_fields WHERE project_id = ? AND name = ?", normalizeProject(project), name)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func scanCustomField(scanner interface {
+	Scan(dest ...any) error
+}) (models.CustomField, error) {
+	field := models.CustomField{}
+	var valuesJSON *string
+	var createdAt, updatedAt string
+	if err := scanner.Scan(&field.Project, &field.Name, &field.Type, &field.Required, &valuesJSON, &createdAt, &updatedAt); err != nil {
+		return models.CustomField{}, err
+	}
+	if valuesJSON != nil && *valuesJSON != "" {
+		if err := json.Unmarshal([]byte(*valuesJSON), &field.Values); err != nil {
+			return models.CustomField{}, fmt.Errorf("decode custom field values_json: %w", err)
+		}
+	}
+	parsedCreated, err := dbParseTime(createdAt)
+	if err != nil {
+		return models.CustomField{}, err
+	}
+	parsedUpdated, err := dbParseTime(updatedAt)
+	if err != nil {
+		return models.CustomField{}, err
+	}
+	field.CreatedAt = parsedCreated
+	field.UpdatedAt = parsedUpdated
+	return field, nil
+}
+
+func customFieldValuesToJSON(values []string) (any, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("marshal custom field values_json: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,16 @@
+package store
+
+import (
+	"context"
+
+	"grns/internal/models"
+)
+
+// CustomFieldStore is the persistence surface for per-project custom field schemas.
+type CustomFieldStore interface {
+	PutCustomField(ctx context.Context, field *models.CustomField) error
+	ListCustomFields(ctx context.Context, project string) ([]models.CustomField, error)
+	DeleteCustomField(ctx context.Context, project, name string) (bool, error)
+}
+
+var _ CustomFieldStore = (*Store)(nil)
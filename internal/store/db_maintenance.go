@@ -0,0 +1,214 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxIntegrityErrors caps how many problems integrity_check reports.
+const maxIntegrityErrors = 100
+
+// Maintain runs SQLite's integrity check and the FTS5 index check, then
+// optionally rebuilds tasks_fts from tasks and VACUUMs the file. The
+// mutating steps are skipped when the integrity check fails, since VACUUM
+// on a damaged file can make things worse.
+func (s *Store) Maintain(ctx context.Context, opts MaintenanceOptions) (*MaintenanceResult, error) {
+	if err := s.requireSQLite("maintenance"); err != nil {
+		return nil, err
+	}
+	// Integrity checks and VACUUM scan the whole file; db.query_timeout is for requests.
+	ctx = WithoutQueryTimeout(ctx)
+	result := &MaintenanceResult{}
+	var err error
+	if result.SizeBeforeBytes, result.FreePagesBefore, err = s.fileStats(ctx); err != nil {
+		return nil, err
+	}
+
+	if result.IntegrityErrors, err = s.integrityErrors(ctx); err != nil {
+		return nil, err
+	}
+	result.IntegrityOK = len(result.IntegrityErrors) == 0
+
+	if result.IntegrityOK && opts.RebuildFTS {
+		if result.FTSRows, err = s.rebuildFTS(ctx); err != nil {
+			return nil, err
+		}
+		result.FTSRebuilt = true
+	}
+	if result.IntegrityOK && opts.Vacuum {
+		if _, err := s.db.ExecContext(ctx, "VACUUM"); err != nil {
+			return nil, fmt.Errorf("vacuum: %w", err)
+		}
+		// Shrink the WAL too; VACUUM writes the whole database through it.
+		if _, err := s.db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+			return nil, fmt.Errorf("wal checkpoint: %w", err)
+		}
+		result.Vacuumed = true
+	}
+
+	if result.SizeAfterBytes, result.FreePagesAfter, err = s.fileStats(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Store) integrityErrors(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf("PRAGMA integrity_check(%d)", maxIntegrityErrors))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	problems := []string{}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// FTS5 reports index/content mismatches as an error from this command.
+	if _, err := s.db.ExecContext(ctx, "INSERT INTO tasks_fts(tasks_fts) VALUES('integrity-check')"); err != nil {
+		problems = append(problems, "tasks_fts: "+err.Error())
+	}
+	return problems, nil
+}
+
+// rebuildFTS repopulates tasks_fts from tasks and merges its index segments.
+func (s *Store) rebuildFTS(ctx context.Context) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM tasks_fts"); err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, ftsInsertFromTasks(s.dialect))
+	if err != nil {
+		return 0, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO tasks_fts(tasks_fts) VALUES('optimize')"); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
+
+const defaultReindexBatchSize = 500
+
+// ReindexFTS rebuilds tasks_fts from tasks in id order, one short transaction
+// per batch, so writers interleave between batches instead of waiting for
+// the whole rebuild. Triggers keep already reindexed tasks current while it
+// runs. Index rows left for deleted tasks are removed at the end.
+func (s *Store) ReindexFTS(ctx context.Context, opts ReindexOptions) (*ReindexResult, error) {
+	ctx = WithoutQueryTimeout(ctx)
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultReindexBatchSize
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks").Scan(&total); err != nil {
+		return nil, err
+	}
+
+	result := &ReindexResult{}
+	afterID := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		ids, err := s.reindexFTSBatch(ctx, afterID, batchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+		result.Reindexed += len(ids)
+		afterID = ids[len(ids)-1]
+		if opts.Progress != nil {
+			opts.Progress(ReindexProgress{Total: total, Processed: result.Reindexed, LastID: afterID})
+		}
+		if len(ids) < batchSize {
+			break
+		}
+	}
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM tasks_fts WHERE task_id NOT IN (SELECT id FROM tasks)")
+	if err != nil {
+		return result, err
+	}
+	orphaned, err := res.RowsAffected()
+	if err != nil {
+		return result, err
+	}
+	result.Orphaned = int(orphaned)
+	return result, nil
+}
+
+// reindexFTSBatch replaces the index rows of the next batchSize tasks after
+// afterID and returns their ids.
+func (s *Store) reindexFTSBatch(ctx context.Context, afterID string, batchSize int) (ids []string, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	ids, err = queryIDs(ctx, tx, "SELECT id FROM tasks WHERE id > ? ORDER BY id LIMIT ?", afterID, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, tx.Rollback()
+	}
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	in := placeholders(len(ids))
+	if _, err = tx.ExecContext(ctx, "DELETE FROM tasks_fts WHERE task_id IN ("+in+")", args...); err != nil {
+		return nil, err
+	}
+	if _, err = tx.ExecContext(ctx, ftsInsertFromTasks(s.dialect)+" WHERE id IN ("+in+")", args...); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// fileStats returns the database size in bytes and its free page count.
+func (s *Store) fileStats(ctx context.Context) (int64, int64, error) {
+	var pageCount, pageSize, freePages int64
+	if err := s.db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, 0, err
+	}
+	if err := s.db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, 0, err
+	}
+	if err := s.db.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&freePages); err != nil {
+		return 0, 0, err
+	}
+	return pageCount * pageSize, freePages, nil
+}
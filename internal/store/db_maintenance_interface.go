@@ -0,0 +1,54 @@
+package store
+
+import "context"
+
+// MaintenanceOptions selects the optional steps of Maintain. The integrity
+// check always runs.
+type MaintenanceOptions struct {
+	Vacuum     bool
+	RebuildFTS bool
+}
+
+// MaintenanceResult reports what Maintain checked and changed.
+type MaintenanceResult struct {
+	IntegrityOK     bool
+	IntegrityErrors []string
+	FTSRebuilt      bool
+	FTSRows         int
+	Vacuumed        bool
+	SizeBeforeBytes int64
+	SizeAfterBytes  int64
+	FreePagesBefore int64
+	FreePagesAfter  int64
+}
+
+// ReindexOptions tunes ReindexFTS.
+type ReindexOptions struct {
+	// BatchSize is how many tasks each transaction reindexes; defaults to 500.
+	BatchSize int
+	// Progress, when set, is called after each committed batch.
+	Progress func(ReindexProgress)
+}
+
+// ReindexProgress reports how far ReindexFTS has come. Total is the task
+// count when the reindex started; tasks created since may push Processed past it.
+type ReindexProgress struct {
+	Total     int
+	Processed int
+	LastID    string
+}
+
+// ReindexResult reports what ReindexFTS rewrote.
+type ReindexResult struct {
+	Reindexed int
+	// Orphaned counts index rows removed because their task no longer exists.
+	Orphaned int
+}
+
+// MaintenanceStore verifies and compacts the database file.
+type MaintenanceStore interface {
+	Maintain(ctx context.Context, opts MaintenanceOptions) (*MaintenanceResult, error)
+	ReindexFTS(ctx context.Context, opts ReindexOptions) (*ReindexResult, error)
+}
+
+var _ MaintenanceStore = (*Store)(nil)
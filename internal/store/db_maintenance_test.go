@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"grns/internal/models"
+)
+
+func TestMaintainVacuumsAndRebuildsFTS(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	padding := strings.Repeat("bloat ", 2000)
+	for i := range 40 {
+		task := &models.Task{ID: fmt.Sprintf("gr-mt%02d", i), Title: "Maintained", Description: padding, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %d: %v", i, err)
+		}
+	}
+	if _, err := st.db.ExecContext(ctx, "DELETE FROM tasks WHERE id != 'gr-mt00'"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	checked, err := st.Maintain(ctx, MaintenanceOptions{})
+	if err != nil {
+		t.Fatalf("check only: %v", err)
+	}
+	if !checked.IntegrityOK || checked.Vacuumed || checked.FTSRebuilt {
+		t.Fatalf("unexpected check-only result: %+v", checked)
+	}
+	if checked.FreePagesBefore == 0 {
+		t.Fatalf("expected free pages after bulk delete, got %+v", checked)
+	}
+
+	result, err := st.Maintain(ctx, MaintenanceOptions{Vacuum: true, RebuildFTS: true})
+	if err != nil {
+		t.Fatalf("maintain: %v", err)
+	}
+	if !result.IntegrityOK || !result.Vacuumed || !result.FTSRebuilt || result.FTSRows != 1 {
+		t.Fatalf("unexpected maintenance result: %+v", result)
+	}
+	if result.FreePagesAfter != 0 || result.SizeAfterBytes >= result.SizeBeforeBytes {
+		t.Fatalf("expected vacuum to reclaim space, got %+v", result)
+	}
+
+	tasks, err := st.ListTasks(ctx, ListFilter{SearchQuery: "maintained"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "gr-mt00" {
+		t.Fatalf("expected rebuilt index to find gr-mt00, got %+v", tasks)
+	}
+}
+
+func TestMaintainReportsFTSIntegrityProblems(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	if err := st.CreateTask(ctx, &models.Task{ID: "gr-mt99", Title: "Checked", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// Corrupt the FTS index shadow table directly.
+	if _, err := st.db.ExecContext(ctx, "DELETE FROM tasks_fts_data WHERE id > 10"); err != nil {
+		t.Fatalf("corrupt fts index: %v", err)
+	}
+
+	result, err := st.Maintain(ctx, MaintenanceOptions{Vacuum: true})
+	if err != nil {
+		t.Fatalf("maintain: %v", err)
+	}
+	if result.IntegrityOK || len(result.IntegrityErrors) == 0 || result.Vacuumed {
+		t.Fatalf("expected integrity failure and skipped vacuum, got %+v", result)
+	}
+}
+
+func TestReindexFTSBatchesAndDropsOrphans(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for i := range 7 {
+		task := &models.Task{ID: fmt.Sprintf("gr-rf%02d", i), Title: "Reindexed", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %d: %v", i, err)
+		}
+	}
+	// Simulate drift: lose one row, leave one for a missing task.
+	if _, err := st.db.ExecContext(ctx, "DELETE FROM tasks_fts WHERE task_id = 'gr-rf03'"); err != nil {
+		t.Fatalf("drop fts row: %v", err)
+	}
+	if _, err := st.db.ExecContext(ctx, "INSERT INTO tasks_fts(task_id, title) VALUES ('gr-gone', 'Reindexed')"); err != nil {
+		t.Fatalf("insert orphan: %v", err)
+	}
+
+	var progress []ReindexProgress
+	result, err := st.ReindexFTS(ctx, ReindexOptions{BatchSize: 3, Progress: func(p ReindexProgress) {
+		progress = append(progress, p)
+	}})
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+	if result.Reindexed != 7 || result.Orphaned != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(progress) != 3 || progress[2].Processed != 7 || progress[2].Total != 7 || progress[0].LastID != "gr-rf02" {
+		t.Fatalf("unexpected progress: %+v", progress)
+	}
+
+	var rows int
+	if err := st.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks_fts").Scan(&rows); err != nil {
+		t.Fatalf("count fts: %v", err)
+	}
+	if rows != 7 {
+		t.Fatalf("expected 7 index rows, got %d", rows)
+	}
+	tasks, err := st.ListTasks(ctx, ListFilter{SearchQuery: "reindexed"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(tasks) != 7 {
+		t.Fatalf("expected 7 search hits, got %d", len(tasks))
+	}
+}
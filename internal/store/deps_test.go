@@ -31,7 +31,7 @@ func TestDependencyTree(t *testing.T) {
 	}
 
 	t.Run("from middle node", func(t *testing.T) {
-		nodes, err := st.DependencyTree(ctx, "gr", "gr-dt02")
+		nodes, err := st.DependencyTree(ctx, "gr-dt02")
 		if err != nil {
 			t.Fatalf("tree: %v", err)
 		}
@@ -54,7 +54,7 @@ func TestDependencyTree(t *testing.T) {
 	})
 
 	t.Run("from leaf node", func(t *testing.T) {
-		nodes, err := st.DependencyTree(ctx, "gr", "gr-dt03")
+		nodes, err := st.DependencyTree(ctx, "gr-dt03")
 		if err != nil {
 			t.Fatalf("tree: %v", err)
 		}
@@ -76,7 +76,7 @@ func TestDependencyTree(t *testing.T) {
 			t.Fatalf("create: %v", err)
 		}
 
-		nodes, err := st.DependencyTree(ctx, "gr", "gr-dt04")
+		nodes, err := st.DependencyTree(ctx, "gr-dt04")
 		if err != nil {
 			t.Fatalf("tree: %v", err)
 		}
@@ -139,7 +139,105 @@ func TestReplaceLabels(t *testing.T) {
 	}
 }
 
-func TestAddDependencyRejectsCrossProject(t *testing.T) {
+func TestLabelCooccurrence(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	seeds := map[string][]string{
+		"gr-lc01": {"auth", "backend", "security"},
+		"gr-lc02": {"auth", "backend"},
+		"gr-lc03": {"auth", "frontend"},
+		"gr-lc04": {"backend", "frontend"},
+		"xy-lc01": {"auth", "frontend"},
+	}
+	for id, labels := range seeds {
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := st.CreateTask(ctx, task, labels, nil); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+
+	related, err := st.LabelCooccurrence(ctx, "gr", "auth", 0, 0)
+	if err != nil {
+		t.Fatalf("label cooccurrence: %v", err)
+	}
+	want := []RelatedLabel{{Label: "backend", Count: 2}, {Label: "frontend", Count: 1}, {Label: "security", Count: 1}}
+	if len(related) != len(want) {
+		t.Fatalf("expected %v, got %v", want, related)
+	}
+	for i := range want {
+		if related[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, related)
+		}
+	}
+
+	limited, err := st.LabelCooccurrence(ctx, "gr", "auth", 1, 0)
+	if err != nil {
+		t.Fatalf("limited label cooccurrence: %v", err)
+	}
+	if len(limited) != 1 || limited[0].Label != "backend" {
+		t.Fatalf("expected top related label backend, got %v", limited)
+	}
+}
+
+func TestRenameAndDeleteLabel(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	seeds := map[string][]string{
+		"gr-rn01": {"bakend"},
+		"gr-rn02": {"bakend", "backend"},
+		"gr-rn03": {"frontend"},
+		"xy-rn01": {"bakend"},
+	}
+	for id, labels := range seeds {
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := st.CreateTask(ctx, task, labels, nil); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+
+	renamed, err := st.RenameLabel(ctx, "gr", "bakend", "backend")
+	if err != nil {
+		t.Fatalf("rename label: %v", err)
+	}
+	if len(renamed) != 2 || renamed[0] != "gr-rn01" || renamed[1] != "gr-rn02" {
+		t.Fatalf("expected [gr-rn01 gr-rn02], got %v", renamed)
+	}
+	merged, err := st.ListLabels(ctx, "gr-rn02")
+	if err != nil {
+		t.Fatalf("list labels: %v", err)
+	}
+	if len(merged) != 1 || merged[0] != "backend" {
+		t.Fatalf("expected merged [backend], got %v", merged)
+	}
+	untouched, err := st.ListLabels(ctx, "xy-rn01")
+	if err != nil {
+		t.Fatalf("list labels: %v", err)
+	}
+	if len(untouched) != 1 || untouched[0] != "bakend" {
+		t.Fatalf("expected other project untouched, got %v", untouched)
+	}
+
+	deleted, err := st.DeleteLabel(ctx, "gr", "backend")
+	if err != nil {
+		t.Fatalf("delete label: %v", err)
+	}
+	if len(deleted) != 2 {
+		t.Fatalf("expected 2 tasks, got %v", deleted)
+	}
+	remaining, err := st.ListLabels(ctx, "gr-rn01")
+	if err != nil {
+		t.Fatalf("list labels: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("expected no labels, got %v", remaining)
+	}
+}
+
+func TestCrossProjectDependency(t *testing.T) {
 	st := testStore(t)
 	ctx := context.Background()
 	now := time.Now().UTC().Truncate(time.Millisecond)
@@ -153,12 +251,36 @@ func TestAddDependencyRejectsCrossProject(t *testing.T) {
 		}
 	}
 
-	err := st.AddDependency(ctx, "gr-cp01", "xy-cp01", "blocks")
-	if err == nil {
-		t.Fatal("expected cross-project dependency to fail")
+	if err := st.AddDependency(ctx, "gr-cp01", "xy-cp01", "blocks"); err != nil {
+		t.Fatalf("add cross-project dependency: %v", err)
+	}
+
+	ready, err := st.ListReadyTasks(ctx, "gr", ReadyOrderUpdated, 0)
+	if err != nil {
+		t.Fatalf("list ready: %v", err)
+	}
+	if len(ready) != 0 {
+		t.Fatalf("expected gr-cp01 blocked by open xy parent, got %#v", ready)
+	}
+
+	nodes, err := st.DependencyTree(ctx, "gr-cp01")
+	if err != nil {
+		t.Fatalf("dependency tree: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].ID != "xy-cp01" || nodes[0].Project != "xy" || nodes[0].Direction != "upstream" {
+		t.Fatalf("unexpected tree nodes: %#v", nodes)
+	}
+
+	closedAt := now.Add(time.Minute)
+	if err := st.CloseTasks(ctx, "xy", []string{"xy-cp01"}, closedAt); err != nil {
+		t.Fatalf("close parent: %v", err)
+	}
+	ready, err = st.ListReadyTasks(ctx, "gr", ReadyOrderUpdated, 0)
+	if err != nil {
+		t.Fatalf("list ready after close: %v", err)
 	}
-	if err != ErrProjectMismatch {
-		t.Fatalf("expected ErrProjectMismatch, got %v", err)
+	if len(ready) != 1 || ready[0].ID != "gr-cp01" {
+		t.Fatalf("expected gr-cp01 ready after parent closed, got %#v", ready)
 	}
 }
 
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+const (
+	dialectSQLite   = "sqlite"
+	dialectPostgres = "postgres"
+)
+
+// dialect supplies the SQL fragments that differ between backends. Store
+// queries are otherwise written once, in SQLite's syntax with ? placeholders;
+// a dialect's connection wrapper adapts that syntax for its driver.
+type dialect interface {
+	name() string
+	// conn adapts a driver connection to the store's SQL.
+	conn(c *observedConn) driver.Conn
+	// migrations returns the dialect's ordered schema migrations.
+	migrations() []Migration
+
+	// searchJoin joins tasks_fts to tasks on a full-text match of one
+	// query argument.
+	searchJoin() string
+	// searchOrder orders matches best first; usesQuery reports whether it
+	// takes the search query as an argument.
+	searchOrder() (order string, usesQuery bool)
+	// searchHitsQuery returns snippet and rank of each matching task among n
+	// ids; the search query is its first argument, then the ids.
+	searchHitsQuery(n int) string
+	// customSearchText flattens the scalar values of a custom JSON column
+	// into the text indexed for search.
+	customSearchText(column string) string
+	// customFieldEquals matches one custom field's value rendered as text.
+	customFieldEquals(key, value string) (string, []any)
+	// customFieldExists matches tasks whose custom field is set and not null.
+	customFieldExists(key string) (string, []any)
+	// containsText matches column containing one argument, ignoring ASCII case.
+	containsText(column string) string
+	// matchesRegexp matches column against one regexp argument.
+	matchesRegexp(column string) string
+	// clamp bounds expr to [lo, hi].
+	clamp(expr, lo, hi string) string
+	// truncate converts a real expr to an integer, rounding toward zero.
+	truncate(expr string) string
+	// jsonSetTrue returns a JSON object column with key set to true; a NULL
+	// column counts as an empty object. The key is bound as an argument.
+	jsonSetTrue(column, key string) (string, []any)
+	// quoteIdent quotes a column name that is reserved in the dialect.
+	quoteIdent(name string) string
+	// forUpdate is the locking clause for a SELECT whose rows the
+	// transaction is about to change; skipLocked passes over rows other
+	// transactions hold.
+	forUpdate(skipLocked bool) string
+	// migrationLock is a statement that makes concurrent migration
+	// transactions wait for each other, or "" when transactions already do.
+	migrationLock() string
+	// noLimit is the LIMIT clause for an OFFSET without a limit.
+	noLimit() string
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) name() string { return dialectSQLite }
+
+func (sqliteDialect) conn(c *observedConn) driver.Conn { return c }
+
+func (sqliteDialect) migrations() []Migration { return migrations }
+
+func (sqliteDialect) searchJoin() string {
+	return "JOIN tasks_fts ON tasks.id = tasks_fts.task_id AND tasks_fts MATCH ?"
+}
+
+func (sqliteDialect) searchOrder() (string, bool) {
+	return "tasks_fts.rank", false
+}
+
+func (sqliteDialect) searchHitsQuery(n int) string {
+	return fmt.Sprintf(`
+		SELECT task_id, snippet(tasks_fts, -1, '[', ']', '…', 12), rank
+		FROM tasks_fts
+		WHERE tasks_fts MATCH ? AND task_id IN (%s)
+	`, placeholders(n))
+}
+
+func (sqliteDialect) customSearchText(column string) string {
+	return fmt.Sprintf("COALESCE(CASE WHEN json_valid(%[1]s) THEN (SELECT group_concat(value, ' ') FROM json_tree(%[1]s) WHERE type NOT IN ('object', 'array')) END, '')", column)
+}
+
+func (sqliteDialect) customFieldEquals(key, value string) (string, []any) {
+	path := customJSONPath(key)
+	return "(CASE json_type(tasks.custom, ?) WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' ELSE CAST(json_extract(tasks.custom, ?) AS TEXT) END) = ?", []any{path, path, value}
+}
+
+func (sqliteDialect) customFieldExists(key string) (string, []any) {
+	return "COALESCE(json_type(tasks.custom, ?), 'null') != 'null'", []any{customJSONPath(key)}
+}
+
+func (sqliteDialect) containsText(column string) string {
+	return column + " LIKE '%' || ? || '%'"
+}
+
+func (sqliteDialect) matchesRegexp(column string) string {
+	return column + " REGEXP ?"
+}
+
+func (sqliteDialect) clamp(expr, lo, hi string) string {
+	return fmt.Sprintf("MIN(MAX(%s, %s), %s)", expr, lo, hi)
+}
+
+func (sqliteDialect) truncate(expr string) string {
+	return "CAST(" + expr + " AS INTEGER)"
+}
+
+func (sqliteDialect) jsonSetTrue(column, key string) (string, []any) {
+	return "json_set(COALESCE(" + column + ", '{}'), ?, json('true'))", []any{customJSONPath(key)}
+}
+
+func (sqliteDialect) quoteIdent(name string) string { return name }
+
+// forUpdate is empty: SQLite write transactions begin IMMEDIATE and so
+// already exclude each other.
+func (sqliteDialect) forUpdate(bool) string { return "" }
+
+func (sqliteDialect) migrationLock() string { return "" }
+
+func (sqliteDialect) noLimit() string { return "LIMIT -1" }
+
+func customJSONPath(key string) string {
+	return `$."` + key + `"`
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"grns/internal/models"
+)
+
+const taskEventColumns = "e.id, e.task_id, e.event_type, e.actor, e.changes_json, e.created_at"
+
+// AppendTaskEvents inserts audit events in a single transaction.
+func (s *Store) AppendTaskEvents(ctx context.Context, events []models.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return s.withTx(ctx, func(m *txImportMutator) error {
+		return m.AppendTaskEvents(ctx, events)
+	})
+}
+
+func (m *txImportMutator) AppendTaskEvents(ctx context.Context, events []models.TaskEvent) error {
+	return appendTaskEventsTx(ctx, m.tx, events)
+}
+
+// appendTaskEventsTx inserts events in tx, so they commit or roll back with
+// the mutation they describe.
+func appendTaskEventsTx(ctx context.Context, tx *sql.Tx, events []models.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO task_events (task_id, event_type, actor, changes_json, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	for _, event := range events {
+		changesJSON, err := taskEventChangesToJSON(event.Changes)
+		if err != nil {
+			return err
+		}
+		createdAt := event.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = now
+		}
+		if _, err := stmt.ExecContext(ctx,
+			event.TaskID,
+			string(event.Type),
+			nullIfEmpty(strings.TrimSpace(event.Actor)),
+			changesJSON,
+			dbFormatTime(createdAt),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListTaskEvents lists audit events for one task in the order they were recorded.
+func (s *Store) ListTaskEvents(ctx context.Context, project, taskID string) ([]models.TaskEvent, error) {
+	project = normalizeProject(project)
+
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if project == "" {
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT `+taskEventColumns+`
+			FROM task_events e
+			WHERE e.task_id = ?
+			ORDER BY e.id
+		`, taskID)
+	} else {
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT `+taskEventColumns+`
+			FROM task_events e
+			JOIN tasks t ON t.id = e.task_id
+			WHERE e.task_id = ? AND t.project_id = ?
+			ORDER BY e.id
+		`, taskID, project)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.TaskEvent{}
+	for rows.Next() {
+		event := models.TaskEvent{}
+		var eventType, createdAt string
+		var actor, changesJSON sql.NullString
+		if err := rows.Scan(&event.ID, &event.TaskID, &eventType, &actor, &changesJSON, &createdAt); err != nil {
+			return nil, err
+		}
+		event.Project = projectFromTaskID(event.TaskID)
+		event.Type = models.TaskEventType(eventType)
+		event.Actor = actor.String
+		if changesJSON.Valid && changesJSON.String != "" {
+			if err := json.Unmarshal([]byte(changesJSON.String), &event.Changes); err != nil {
+				return nil, fmt.Errorf("decode task event changes_json: %w", err)
+			}
+		}
+		parsed, err := dbParseTime(createdAt)
+		if err != nil {
+			return nil, err
+		}
+		event.CreatedAt = parsed
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func taskEventChangesToJSON(changes []models.TaskFieldChange) (any, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("marshal task event changes_json: %w", err)
+	}
+	return string(data), nil
+}
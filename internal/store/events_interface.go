@@ -0,0 +1,15 @@
+package store
+
+import (
+	"context"
+
+	"grns/internal/models"
+)
+
+// EventStore is the persistence surface for the task audit history.
+type EventStore interface {
+	AppendTaskEvents(ctx context.Context, events []models.TaskEvent) error
+	ListTaskEvents(ctx context.Context, project, taskID string) ([]models.TaskEvent, error)
+}
+
+var _ EventStore = (*Store)(nil)
@@ -5,12 +5,15 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"grns/internal/models"
 )
 
+const commitLookupChunkSize = 400
+
 const gitRepoColumns = "id, slug, default_branch, created_at, updated_at"
 const taskGitRefColumns = "r.id, r.task_id, r.repo_id, g.slug, r.relation, r.object_type, r.object_value, r.resolved_commit, r.note, r.meta_json, r.created_at, r.updated_at"
 
@@ -179,6 +182,110 @@ func (s *Store) ListTaskGitRefs(ctx context.Context, project, taskID string) ([]
 	return refs, nil
 }
 
+// ListTaskGitRefsForTasks returns the git refs of each task, keyed by task ID, oldest first.
+func (s *Store) ListTaskGitRefsForTasks(ctx context.Context, ids []string) (map[string][]models.TaskGitRef, error) {
+	refs := make(map[string][]models.TaskGitRef)
+	if len(ids) == 0 {
+		return refs, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT `+taskGitRefColumns+`
+		FROM task_git_refs r
+		JOIN git_repos g ON g.id = r.repo_id
+		WHERE r.task_id IN (%s)
+		ORDER BY r.task_id, r.created_at, r.id
+	`, placeholders(len(ids)))
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ref, err := scanTaskGitRef(rows)
+		if err != nil {
+			return nil, err
+		}
+		if ref != nil {
+			refs[ref.TaskID] = append(refs[ref.TaskID], *ref)
+		}
+	}
+	return refs, rows.Err()
+}
+
+// ListTaskIDsByCommits returns distinct task IDs with a relation ref matching any commit.
+// Commits are matched against resolved_commit and, for commit refs, object_value.
+func (s *Store) ListTaskIDsByCommits(ctx context.Context, project, relation string, commits []string) ([]string, error) {
+	project = normalizeProject(project)
+	commits = uniqueStrings(commits)
+
+	seen := map[string]bool{}
+	ids := []string{}
+	for start := 0; start < len(commits); start += commitLookupChunkSize {
+		end := min(start+commitLookupChunkSize, len(commits))
+		chunk := commits[start:end]
+
+		query := fmt.Sprintf(`
+			SELECT DISTINCT r.task_id
+			FROM task_git_refs r
+			JOIN tasks t ON t.id = r.task_id
+			WHERE t.project_id = ?
+			AND r.relation = ?
+			AND (r.resolved_commit IN (%s) OR (r.object_type = 'commit' AND r.object_value IN (%s)))
+		`, placeholders(len(chunk)), placeholders(len(chunk)))
+		args := make([]any, 0, 2+len(chunk)*2)
+		args = append(args, project, relation)
+		for _, commit := range chunk {
+			args = append(args, commit)
+		}
+		for _, commit := range chunk {
+			args = append(args, commit)
+		}
+
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// UpdateTaskGitRefResolution sets the resolved commit and meta of one task git reference.
+func (s *Store) UpdateTaskGitRefResolution(ctx context.Context, id, resolvedCommit string, meta map[string]any, updatedAt time.Time) error {
+	metaJSON, err := taskGitRefMetaToJSON(meta)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE task_git_refs
+		SET resolved_commit = ?, meta_json = ?, updated_at = ?
+		WHERE id = ?
+	`, nullIfEmpty(strings.TrimSpace(resolvedCommit)), metaJSON, dbFormatTime(updatedAt), id)
+	return err
+}
+
 // DeleteTaskGitRef deletes one task git reference row.
 func (s *Store) DeleteTaskGitRef(ctx context.Context, project, id string) error {
 	project = normalizeProject(project)
@@ -196,7 +303,8 @@ func (s *Store) DeleteTaskGitRef(ctx context.Context, project, id string) error
 
 // CloseTasksWithGitRefs closes tasks and adds one git ref annotation per task in a single transaction.
 // Duplicate annotations (same task/repo/relation/object/resolved_commit) are ignored.
-func (s *Store) CloseTasksWithGitRefs(ctx context.Context, project string, ids []string, closedAt time.Time, refs []CloseTaskGitRefInput) (created int, err error) {
+// events are recorded in the same transaction.
+func (s *Store) CloseTasksWithGitRefs(ctx context.Context, project string, ids []string, closedAt time.Time, refs []CloseTaskGitRefInput, events []models.TaskEvent) (created int, err error) {
 	project = normalizeProject(project)
 	ids = uniqueStrings(ids)
 	if len(ids) == 0 {
@@ -247,7 +355,7 @@ func (s *Store) CloseTasksWithGitRefs(ctx context.Context, project string, ids [
 	for _, id := range ids {
 		args = append(args, id)
 	}
-	query := fmt.Sprintf("UPDATE tasks SET status = ?, closed_at = ?, updated_at = ? WHERE project_id = ? AND id IN (%s)", placeholders(len(ids)))
+	query := fmt.Sprintf("UPDATE tasks SET status = ?, closed_at = ?, blocked_reason = NULL, blocked_on = NULL, updated_at = ? WHERE project_id = ? AND id IN (%s)", placeholders(len(ids)))
 	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 		return 0, err
 	}
@@ -275,11 +383,14 @@ func (s *Store) CloseTasksWithGitRefs(ctx context.Context, project string, ids [
 			return 0, err
 		}
 
-		_, err = tx.ExecContext(ctx, `
+		// A ref the task already carries is skipped; a failed statement would
+		// abort the whole transaction on PostgreSQL.
+		result, err := tx.ExecContext(ctx, `
 			INSERT INTO task_git_refs (
 				id, task_id, repo_id, relation, object_type, object_value,
 				resolved_commit, note, meta_json, created_at, updated_at
 			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT DO NOTHING
 		`,
 			refID,
 			id,
@@ -294,14 +405,18 @@ func (s *Store) CloseTasksWithGitRefs(ctx context.Context, project string, ids [
 			dbFormatTime(closedAt),
 		)
 		if err != nil {
-			if isStoreUniqueConstraint(err) {
-				continue
-			}
 			return 0, err
 		}
-		created++
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		created += int(affected)
 	}
 
+	if err := appendTaskEventsTx(ctx, tx, events); err != nil {
+		return 0, err
+	}
 	if err := tx.Commit(); err != nil {
 		return 0, err
 	}
@@ -389,13 +504,6 @@ func taskGitRefIDExistsTx(ctx context.Context, tx *sql.Tx, id string) (bool, err
 	return true, nil
 }
 
-func isStoreUniqueConstraint(err error) bool {
-	if err == nil {
-		return false
-	}
-	return strings.Contains(strings.ToLower(err.Error()), "unique constraint failed")
-}
-
 func scanGitRepo(scanner interface {
 	Scan(dest ...any) error
 }) (*models.GitRepo, error) {
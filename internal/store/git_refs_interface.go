@@ -27,9 +27,13 @@ type GitRefStore interface {
 	CreateTaskGitRef(ctx context.Context, ref *models.TaskGitRef) error
 	GetTaskGitRef(ctx context.Context, project, id string) (*models.TaskGitRef, error)
 	ListTaskGitRefs(ctx context.Context, project, taskID string) ([]models.TaskGitRef, error)
+	ListTaskGitRefsForTasks(ctx context.Context, ids []string) (map[string][]models.TaskGitRef, error)
+	UpdateTaskGitRefResolution(ctx context.Context, id, resolvedCommit string, meta map[string]any, updatedAt time.Time) error
 	DeleteTaskGitRef(ctx context.Context, project, id string) error
 
-	CloseTasksWithGitRefs(ctx context.Context, project string, ids []string, closedAt time.Time, refs []CloseTaskGitRefInput) (int, error)
+	ListTaskIDsByCommits(ctx context.Context, project, relation string, commits []string) ([]string, error)
+
+	CloseTasksWithGitRefs(ctx context.Context, project string, ids []string, closedAt time.Time, refs []CloseTaskGitRefInput, events []models.TaskEvent) (int, error)
 }
 
 var _ GitRefStore = (*Store)(nil)
@@ -34,7 +34,7 @@ func TestCloseTasksWithGitRefsRollsBackOnRefInsertError(t *testing.T) {
 			ObjectType:  "invalid_type",
 			ObjectValue: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
 		},
-	})
+	}, nil)
 	if err == nil {
 		t.Fatal("expected close with invalid git ref to fail")
 	}
@@ -145,7 +145,7 @@ func TestCloseTasksWithGitRefsIgnoresDuplicateAnnotations(t *testing.T) {
 		ObjectValue: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
 	}
 
-	created, err := st.CloseTasksWithGitRefs(ctx, "gr", []string{task.ID}, now, []CloseTaskGitRefInput{ref})
+	created, err := st.CloseTasksWithGitRefs(ctx, "gr", []string{task.ID}, now, []CloseTaskGitRefInput{ref}, nil)
 	if err != nil {
 		t.Fatalf("first close with git refs: %v", err)
 	}
@@ -153,7 +153,7 @@ func TestCloseTasksWithGitRefsIgnoresDuplicateAnnotations(t *testing.T) {
 		t.Fatalf("expected created=1, got %d", created)
 	}
 
-	created, err = st.CloseTasksWithGitRefs(ctx, "gr", []string{task.ID}, now.Add(time.Second), []CloseTaskGitRefInput{ref})
+	created, err = st.CloseTasksWithGitRefs(ctx, "gr", []string{task.ID}, now.Add(time.Second), []CloseTaskGitRefInput{ref}, nil)
 	if err != nil {
 		t.Fatalf("second close with duplicate git refs: %v", err)
 	}
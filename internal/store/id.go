@@ -59,6 +59,21 @@ func GenerateTaskGitRefID(exists func(string) (bool, error)) (string, error) {
 	return GenerateID("gf", exists)
 }
 
+// GenerateTaskCommentID returns a new task comment id using the cm- prefix.
+func GenerateTaskCommentID(exists func(string) (bool, error)) (string, error) {
+	return GenerateID("cm", exists)
+}
+
+// GenerateTaskWorklogID returns a new task worklog id using the wl- prefix.
+func GenerateTaskWorklogID(exists func(string) (bool, error)) (string, error) {
+	return GenerateID("wl", exists)
+}
+
+// GenerateMilestoneID returns a new milestone id using the ms- prefix.
+func GenerateMilestoneID(exists func(string) (bool, error)) (string, error) {
+	return GenerateID("ms", exists)
+}
+
 func randomBase36(length int) (string, error) {
 	b := make([]byte, length)
 	if _, err := rand.Read(b); err != nil {
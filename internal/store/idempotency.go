@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetIdempotencyRecord returns the record for key on scope, or nil when none is stored.
+func (s *Store) GetIdempotencyRecord(ctx context.Context, key, scope string) (*IdempotencyRecord, error) {
+	record := &IdempotencyRecord{}
+	var createdAt string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT idem_key, scope, request_hash, status, response_body, created_at
+		FROM idempotency_keys WHERE idem_key = ? AND scope = ?
+	`, key, scope).Scan(&record.Key, &record.Scope, &record.RequestHash, &record.Status, &record.Body, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.CreatedAt, err = dbParseTime(createdAt); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// SaveIdempotencyRecord stores record, replacing any earlier record for the
+// same key and scope.
+func (s *Store) SaveIdempotencyRecord(ctx context.Context, record *IdempotencyRecord) error {
+	if record == nil {
+		return fmt.Errorf("idempotency record is required")
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now().UTC()
+	}
+	body := record.Body
+	if body == nil {
+		body = []byte{}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (idem_key, scope, request_hash, status, response_body, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(idem_key, scope) DO UPDATE SET
+			request_hash = excluded.request_hash,
+			status = excluded.status,
+			response_body = excluded.response_body,
+			created_at = excluded.created_at
+	`, record.Key, record.Scope, record.RequestHash, record.Status, body, dbFormatTime(record.CreatedAt))
+	return err
+}
+
+// PruneIdempotencyRecords deletes records created before the cutoff and returns how many were removed.
+func (s *Store) PruneIdempotencyRecords(ctx context.Context, before time.Time) (int, error) {
+	return s.pruneCreatedBefore(ctx, "idempotency_keys", before)
+}
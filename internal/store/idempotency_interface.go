@@ -0,0 +1,26 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRecord is the stored response of a write sent with an
+// Idempotency-Key. Scope is the method and path the key was used on.
+type IdempotencyRecord struct {
+	Key         string
+	Scope       string
+	RequestHash string
+	Status      int
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// IdempotencyStore persists responses replayed for retried writes.
+type IdempotencyStore interface {
+	GetIdempotencyRecord(ctx context.Context, key, scope string) (*IdempotencyRecord, error)
+	SaveIdempotencyRecord(ctx context.Context, record *IdempotencyRecord) error
+	PruneIdempotencyRecords(ctx context.Context, before time.Time) (int, error)
+}
+
+var _ IdempotencyStore = (*Store)(nil)
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"grns/internal/models"
+)
+
+// SaveImportReport inserts a conflict report and sets its ID.
+func (s *Store) SaveImportReport(ctx context.Context, report *models.ImportReport) error {
+	if report == nil {
+		return fmt.Errorf("import report is required")
+	}
+	if report.CreatedAt.IsZero() {
+		report.CreatedAt = time.Now().UTC()
+	}
+	conflicts := report.Conflicts
+	if conflicts == nil {
+		conflicts = []models.ImportConflict{}
+	}
+	conflictsJSON, err := json.Marshal(conflicts)
+	if err != nil {
+		return fmt.Errorf("marshal import report conflicts_json: %w", err)
+	}
+
+	return s.db.QueryRowContext(ctx, `
+		INSERT INTO import_reports (project_id, dry_run, conflicts_json, created_at)
+		VALUES (?, ?, ?, ?)
+		RETURNING id
+	`, normalizeProject(report.Project), report.DryRun, string(conflictsJSON), dbFormatTime(report.CreatedAt)).Scan(&report.ID)
+}
+
+// GetImportReport returns one conflict report, or nil when it does not exist in project.
+func (s *Store) GetImportReport(ctx context.Context, project string, id int64) (*models.ImportReport, error) {
+	report := &models.ImportReport{}
+	var conflictsJSON, createdAt string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, project_id, dry_run, conflicts_json, created_at
+		FROM import_reports WHERE project_id = ? AND id = ?
+	`, normalizeProject(project), id).Scan(&report.ID, &report.Project, &report.DryRun, &conflictsJSON, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(conflictsJSON), &report.Conflicts); err != nil {
+		return nil, fmt.Errorf("decode import report conflicts_json: %w", err)
+	}
+	if report.CreatedAt, err = dbParseTime(createdAt); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// PruneImportReports deletes reports created before the cutoff and returns how many were removed.
+func (s *Store) PruneImportReports(ctx context.Context, before time.Time) (int, error) {
+	return s.pruneCreatedBefore(ctx, "import_reports", before)
+}
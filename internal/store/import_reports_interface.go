@@ -0,0 +1,17 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"grns/internal/models"
+)
+
+// ImportReportStore persists import conflict reports for later download.
+type ImportReportStore interface {
+	SaveImportReport(ctx context.Context, report *models.ImportReport) error
+	GetImportReport(ctx context.Context, project string, id int64) (*models.ImportReport, error)
+	PruneImportReports(ctx context.Context, before time.Time) (int, error)
+}
+
+var _ ImportReportStore = (*Store)(nil)
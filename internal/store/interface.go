@@ -8,11 +8,13 @@ import (
 	"grns/internal/models"
 )
 
-// StoreInfo holds metadata about the database.
+// StoreInfo holds metadata about the database, or about one project when Project is set.
 type StoreInfo struct {
+	Project       string         `json:"project,omitempty"`
 	SchemaVersion int            `json:"schema_version"`
 	TaskCounts    map[string]int `json:"task_counts"`
 	TotalTasks    int            `json:"total_tasks"`
+	Projects      []string       `json:"projects,omitempty"`
 }
 
 // CleanupResult reports on a cleanup operation.
@@ -36,6 +38,9 @@ type AuthUser struct {
 // ErrTaskNotFound indicates no matching tasks were found for a mutation.
 var ErrTaskNotFound = errors.New("task not found")
 
+// ErrTaskNotTombstoned indicates a restore named a task that is not tombstoned.
+var ErrTaskNotTombstoned = errors.New("task is not tombstoned")
+
 // ErrProjectMismatch indicates resources belong to different projects.
 var ErrProjectMismatch = errors.New("project mismatch")
 
@@ -46,14 +51,31 @@ type TaskCreateInput struct {
 	Deps   []models.Dependency
 }
 
-// ImportMutator is the transactional mutation subset used by import atomic mode.
+// ImportMutator is the transactional mutation subset used by import atomic mode,
+// RPC batches, and task writes that record audit events in the same transaction.
 type ImportMutator interface {
 	TaskExists(id string) (bool, error)
+	GetTask(ctx context.Context, id string) (*models.Task, error)
+	ListLabels(ctx context.Context, id string) ([]string, error)
 	CreateTask(ctx context.Context, task *models.Task, labels []string, deps []models.Dependency) error
+	CreateTasks(ctx context.Context, tasks []TaskCreateInput) error
 	UpdateTask(ctx context.Context, id string, update TaskUpdate) error
+	UpdateTaskWithinWIPLimits(ctx context.Context, id string, update TaskUpdate, limits []WIPLimit) error
+	UpdateTasks(ctx context.Context, project string, ids []string, update TaskUpdate, limits []WIPLimit) error
+	CloseTasks(ctx context.Context, project string, ids []string, closedAt time.Time) error
+	ReopenTasks(ctx context.Context, project string, ids []string, reopenedAt time.Time) error
+	DeleteTasks(ctx context.Context, project string, ids []string, deletedAt time.Time) error
+	RestoreTasks(ctx context.Context, project string, ids []string, restoredAt time.Time) error
+	TouchTasks(ctx context.Context, project string, ids []string, now time.Time) error
+	MergeTask(ctx context.Context, project, duplicateID, canonicalID string, mergedAt time.Time) error
 	AddDependency(ctx context.Context, childID, parentID, depType string) error
 	ReplaceLabels(ctx context.Context, id string, labels []string) error
+	AddLabels(ctx context.Context, id string, labels []string) error
+	RemoveLabels(ctx context.Context, id string, labels []string) error
+	DeleteLabel(ctx context.Context, project, label string) ([]string, error)
 	RemoveDependencies(ctx context.Context, childID string) error
+	RemoveDependency(ctx context.Context, childID, parentID, depType string) error
+	AppendTaskEvents(ctx context.Context, events []models.TaskEvent) error
 }
 
 // ImportStore is the narrowed import capability dependency used by the importer.
@@ -68,16 +90,31 @@ type TaskServiceStore interface {
 	CreateTasks(ctx context.Context, tasks []TaskCreateInput) error
 	GetTask(ctx context.Context, id string) (*models.Task, error)
 	ListTasks(ctx context.Context, filter ListFilter) ([]models.Task, error)
-	ListReadyTasks(ctx context.Context, project string, limit int) ([]models.Task, error)
+	CountTasks(ctx context.Context, filter ListFilter) (int, error)
+	ListReadyTasks(ctx context.Context, project string, order ReadyOrder, limit int) ([]models.Task, error)
 	ListStaleTasks(ctx context.Context, project string, cutoff time.Time, statuses []string, limit int) ([]models.Task, error)
 	AddLabels(ctx context.Context, id string, labels []string) error
 	RemoveLabels(ctx context.Context, id string, labels []string) error
+	DeleteLabel(ctx context.Context, project, label string) ([]string, error)
+	RenameLabel(ctx context.Context, project, from, to string) ([]string, error)
 	ListLabels(ctx context.Context, id string) ([]string, error)
 	ListDependencies(ctx context.Context, id string) ([]models.Dependency, error)
 	ListLabelsForTasks(ctx context.Context, ids []string) (map[string][]string, error)
 	ListDependenciesForTasks(ctx context.Context, ids []string) (map[string][]models.Dependency, error)
+	ChildStatusCountsForTasks(ctx context.Context, ids []string) (map[string]map[string]int, error)
+	ListDependentsForTasks(ctx context.Context, ids []string) (map[string][]string, error)
+	ListOpenBlockerCounts(ctx context.Context, ids []string) (map[string]int, error)
+	ListSearchHits(ctx context.Context, query string, ids []string) (map[string]SearchHit, error)
+	IsTaskDescendant(ctx context.Context, ancestorID, candidateID string) (bool, error)
 	CloseTasks(ctx context.Context, project string, ids []string, closedAt time.Time) error
 	ReopenTasks(ctx context.Context, project string, ids []string, reopenedAt time.Time) error
+	DeleteTasks(ctx context.Context, project string, ids []string, deletedAt time.Time) error
+	RestoreTasks(ctx context.Context, project string, ids []string, restoredAt time.Time) error
+	MergeTask(ctx context.Context, project, duplicateID, canonicalID string, mergedAt time.Time) error
+	TouchTasks(ctx context.Context, project string, ids []string, now time.Time) error
+	UpdateTasks(ctx context.Context, project string, ids []string, update TaskUpdate, limits []WIPLimit) error
+	Recompute(ctx context.Context, dryRun bool) (*RecomputeResult, error)
+	PurgeTombstonedTasks(ctx context.Context, project string, cutoff time.Time, dryRun bool) (*CleanupResult, error)
 }
 
 // AuthStore exposes admin-user and browser-session persistence used by auth handlers.
@@ -97,9 +134,10 @@ type AuthStore interface {
 // TaskStore abstracts task storage backends.
 type TaskStore interface {
 	TaskServiceStore
-	StoreInfo(ctx context.Context) (*StoreInfo, error)
+	StoreInfo(ctx context.Context, project string) (*StoreInfo, error)
 	ListAllLabels(ctx context.Context, project string) ([]string, error)
-	DependencyTree(ctx context.Context, project string, id string) ([]models.DepTreeNode, error)
+	LabelCooccurrence(ctx context.Context, project, label string, limit, offset int) ([]RelatedLabel, error)
+	DependencyTree(ctx context.Context, id string) ([]models.DepTreeNode, error)
 	CleanupClosedTasks(ctx context.Context, project string, cutoff time.Time, dryRun bool) (*CleanupResult, error)
 }
 
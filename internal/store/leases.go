@@ -0,0 +1,231 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"grns/internal/models"
+)
+
+// ReadyClaim selects and leases the top ready task for one holder.
+type ReadyClaim struct {
+	Project     string
+	Order       ReadyOrder
+	Types       []string
+	Labels      []string
+	MaxPriority *int
+	Holder      string
+	ClaimedAt   time.Time
+	ExpiresAt   time.Time
+	// WIPLimits cap the in_progress slot the claim moves into.
+	WIPLimits []WIPLimit
+	// Events builds the audit events recorded with the claim from the
+	// claimed task.
+	Events func(task *models.Task) []models.TaskEvent
+}
+
+// ClaimReadyTask atomically picks the top open, ready task matching claim that
+// is unassigned or already assigned to the holder, moves it to in_progress
+// assigned to the holder, and records a lease expiring at claim.ExpiresAt.
+// It returns nil when no task matches.
+func (s *Store) ClaimReadyTask(ctx context.Context, claim ReadyClaim) (task *models.Task, err error) {
+	where, args := readyTasksWhere(normalizeProject(claim.Project))
+	where += " AND t.status = ? AND (t.assignee IS NULL OR t.assignee = '' OR t.assignee = ?)"
+	args = append(args, string(models.StatusOpen), claim.Holder)
+	if len(claim.Types) > 0 {
+		where += fmt.Sprintf(" AND t.type IN (%s)", placeholders(len(claim.Types)))
+		for _, taskType := range claim.Types {
+			args = append(args, taskType)
+		}
+	}
+	if len(claim.Labels) > 0 {
+		where += fmt.Sprintf(" AND t.id IN (SELECT task_id FROM task_labels WHERE label IN (%s) GROUP BY task_id HAVING COUNT(DISTINCT label) = %d)", placeholders(len(claim.Labels)), len(claim.Labels))
+		for _, label := range claim.Labels {
+			args = append(args, label)
+		}
+	}
+	if claim.MaxPriority != nil {
+		where += " AND t.priority <= ?"
+		args = append(args, *claim.MaxPriority)
+	}
+	orderBy, orderArgs, err := readyTasksOrderBy(s.dialect, claim.Order, claim.ClaimedAt)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, orderArgs...)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// Selecting inside the UPDATE takes the write lock before the candidate is
+	// read, so concurrent claims serialize instead of picking the same task.
+	// Where writers run concurrently, the candidate row is locked instead and
+	// rows other claims hold are passed over.
+	claimedAt := dbFormatTime(claim.ClaimedAt)
+	updateArgs := append([]any{string(models.StatusInProgress), claim.Holder, claimedAt}, args...)
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE tasks SET status = ?, assignee = ?, updated_at = ?
+		WHERE id = (SELECT t.id FROM tasks t WHERE `+where+` ORDER BY `+orderBy+` LIMIT 1`+s.dialect.forUpdate(true)+`)
+		RETURNING id
+	`, updateArgs...).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = tx.Rollback()
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status := string(models.StatusInProgress)
+	update := TaskUpdate{Status: &status, Assignee: &claim.Holder}
+	if len(claim.WIPLimits) > 0 {
+		if err = lockProjectTx(ctx, tx, s.dialect, projectFromTaskID(id)); err != nil {
+			return nil, err
+		}
+	}
+	for _, limit := range claim.WIPLimits {
+		if err = checkWIPLimit(ctx, tx, projectFromTaskID(id), id, string(models.StatusOpen), "", update, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO task_leases (task_id, holder, claimed_at, expires_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(task_id) DO UPDATE SET holder = excluded.holder, claimed_at = excluded.claimed_at, expires_at = excluded.expires_at
+	`, id, claim.Holder, claimedAt, dbFormatTime(claim.ExpiresAt)); err != nil {
+		return nil, err
+	}
+
+	task, err = scanTask(tx.QueryRowContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE id = ?", id))
+	if err != nil {
+		return nil, err
+	}
+	if claim.Events != nil {
+		if err = appendTaskEventsTx(ctx, tx, claim.Events(task)); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// GetTaskLease returns the lease on a task, or nil when it has none.
+func (s *Store) GetTaskLease(ctx context.Context, taskID string) (*models.TaskLease, error) {
+	var (
+		lease                models.TaskLease
+		claimedAt, expiresAt string
+	)
+	err := s.db.QueryRowContext(ctx, `
+		SELECT task_id, holder, claimed_at, expires_at
+		FROM task_leases WHERE task_id = ?
+	`, taskID).Scan(&lease.TaskID, &lease.Holder, &claimedAt, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lease.ClaimedAt, err = dbParseTime(claimedAt); err != nil {
+		return nil, err
+	}
+	if lease.ExpiresAt, err = dbParseTime(expiresAt); err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// ReleaseExpiredLeases drops leases that expired at or before now. Tasks still
+// in_progress under the lease holder go back to open and unassigned; those are
+// the leases returned. Leases on tasks that moved on are dropped silently.
+// When events is set, the audit events it builds for each released lease are
+// recorded in the same transaction.
+func (s *Store) ReleaseExpiredLeases(ctx context.Context, now time.Time, events func(lease models.TaskLease) []models.TaskEvent) (released []models.TaskLease, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// Clear the expired rows first so the transaction holds the write lock
+	// before reading which tasks to return to the pool.
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM task_leases WHERE expires_at <= ?
+		RETURNING task_id, holder, claimed_at, expires_at
+	`, dbFormatTime(now))
+	if err != nil {
+		return nil, err
+	}
+	var expired []models.TaskLease
+	for rows.Next() {
+		var (
+			lease                models.TaskLease
+			claimedAt, expiresAt string
+		)
+		if err = rows.Scan(&lease.TaskID, &lease.Holder, &claimedAt, &expiresAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if lease.ClaimedAt, err = dbParseTime(claimedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if lease.ExpiresAt, err = dbParseTime(expiresAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		expired = append(expired, lease)
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	updatedAt := dbFormatTime(now)
+	for _, lease := range expired {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE tasks SET status = ?, assignee = NULL, updated_at = ?
+			WHERE id = ? AND status = ? AND assignee = ?
+		`, string(models.StatusOpen), updatedAt, lease.TaskID, string(models.StatusInProgress), lease.Holder)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected > 0 {
+			released = append(released, lease)
+		}
+	}
+	if events != nil {
+		for _, lease := range released {
+			if err = appendTaskEventsTx(ctx, tx, events(lease)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return released, nil
+}
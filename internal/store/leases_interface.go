@@ -0,0 +1,17 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"grns/internal/models"
+)
+
+// LeaseStore is the persistence surface for agent task leases.
+type LeaseStore interface {
+	ClaimReadyTask(ctx context.Context, claim ReadyClaim) (*models.Task, error)
+	GetTaskLease(ctx context.Context, taskID string) (*models.TaskLease, error)
+	ReleaseExpiredLeases(ctx context.Context, now time.Time, events func(lease models.TaskLease) []models.TaskEvent) ([]models.TaskLease, error)
+}
+
+var _ LeaseStore = (*Store)(nil)
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"grns/internal/models"
+)
+
+func TestClaimReadyTaskAndReleaseExpiredLeases(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, task := range []*models.Task{
+		{ID: "gr-cl01", Title: "low", Status: "open", Type: "task", Priority: 3, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-cl02", Title: "high", Status: "open", Type: "bug", Priority: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-cl03", Title: "taken", Status: "open", Type: "task", Priority: 0, Assignee: "other", CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-cl04", Title: "busy", Status: "in_progress", Type: "task", Priority: 0, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+	if err := st.AddLabels(ctx, "gr-cl01", []string{"backend"}); err != nil {
+		t.Fatalf("add labels: %v", err)
+	}
+
+	claim := func(holder string, mutate func(*ReadyClaim)) *models.Task {
+		t.Helper()
+		c := ReadyClaim{Project: "gr", Order: ReadyOrderPriority, Holder: holder, ClaimedAt: now, ExpiresAt: now.Add(time.Minute)}
+		if mutate != nil {
+			mutate(&c)
+		}
+		task, err := st.ClaimReadyTask(ctx, c)
+		if err != nil {
+			t.Fatalf("claim: %v", err)
+		}
+		return task
+	}
+
+	if task := claim("agent-a", func(c *ReadyClaim) { c.Labels = []string{"backend"} }); task == nil || task.ID != "gr-cl01" {
+		t.Fatalf("expected label filter to claim gr-cl01, got %#v", task)
+	}
+	task := claim("agent-b", nil)
+	if task == nil || task.ID != "gr-cl02" || task.Status != "in_progress" || task.Assignee != "agent-b" {
+		t.Fatalf("expected gr-cl02 claimed by agent-b, got %#v", task)
+	}
+	if task := claim("agent-c", nil); task != nil {
+		t.Fatalf("expected nothing left to claim, got %s", task.ID)
+	}
+
+	lease, err := st.GetTaskLease(ctx, "gr-cl02")
+	if err != nil {
+		t.Fatalf("get lease: %v", err)
+	}
+	if lease == nil || lease.Holder != "agent-b" || !lease.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected lease: %#v", lease)
+	}
+
+	// gr-cl01 moved on before its lease expired, so only gr-cl02 returns to the pool.
+	if err := st.CloseTasks(ctx, "gr", []string{"gr-cl01"}, now); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	released, err := st.ReleaseExpiredLeases(ctx, now.Add(30*time.Second), nil)
+	if err != nil {
+		t.Fatalf("release early: %v", err)
+	}
+	if len(released) != 0 {
+		t.Fatalf("expected no releases before expiry, got %#v", released)
+	}
+	released, err = st.ReleaseExpiredLeases(ctx, now.Add(2*time.Minute), nil)
+	if err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if len(released) != 1 || released[0].TaskID != "gr-cl02" || released[0].Holder != "agent-b" {
+		t.Fatalf("expected gr-cl02 released, got %#v", released)
+	}
+	got, err := st.GetTask(ctx, "gr-cl02")
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if got.Status != "open" || got.Assignee != "" {
+		t.Fatalf("expected gr-cl02 back in the pool, got status=%s assignee=%q", got.Status, got.Assignee)
+	}
+	if lease, err := st.GetTaskLease(ctx, "gr-cl01"); err != nil || lease != nil {
+		t.Fatalf("expected expired lease dropped, got %#v (%v)", lease, err)
+	}
+
+	limit := WIPLimit{Status: "in_progress", Max: 1}
+	_, err = st.ClaimReadyTask(ctx, ReadyClaim{Project: "gr", Holder: "agent-c", ClaimedAt: now, ExpiresAt: now.Add(time.Minute), WIPLimits: []WIPLimit{limit}})
+	if _, ok := err.(*WIPLimitExceededError); !ok {
+		t.Fatalf("expected WIP limit error, got %v", err)
+	}
+	got, err = st.GetTask(ctx, "gr-cl02")
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if got.Status != "open" {
+		t.Fatalf("expected WIP-limited claim rolled back, got status %s", got.Status)
+	}
+}
+
+func TestClaimReadyTaskConcurrentClaimsGetDistinctTasks(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	const agents = 8
+	for i := 0; i < agents; i++ {
+		task := &models.Task{ID: fmt.Sprintf("gr-cc%02d", i), Title: "work", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	claimed := make(chan string, agents)
+	errs := make(chan error, agents)
+	for i := 0; i < agents; i++ {
+		wg.Add(1)
+		go func(holder string) {
+			defer wg.Done()
+			task, err := st.ClaimReadyTask(ctx, ReadyClaim{Project: "gr", Holder: holder, ClaimedAt: now, ExpiresAt: now.Add(time.Minute)})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if task != nil {
+				claimed <- task.ID
+			}
+		}(fmt.Sprintf("agent-%d", i))
+	}
+	wg.Wait()
+	close(claimed)
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("claim: %v", err)
+	}
+	seen := map[string]bool{}
+	for id := range claimed {
+		if seen[id] {
+			t.Fatalf("task %s claimed twice", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != agents {
+		t.Fatalf("expected %d distinct claims, got %d", agents, len(seen))
+	}
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+)
+
+// ftsInsertFromTasks copies search fields from tasks into tasks_fts. Callers
+// append a WHERE clause to limit the rows.
+func ftsInsertFromTasks(d dialect) string {
+	return `
+	INSERT INTO tasks_fts(task_id, title, description, notes, design, acceptance_criteria, assignee, custom)
+	SELECT id, title, COALESCE(description, ''), COALESCE(notes, ''), COALESCE(design, ''),
+		COALESCE(acceptance_criteria, ''), COALESCE(assignee, ''), ` + d.customSearchText("custom") + `
+	FROM tasks`
+}
+
+// RecomputeResult reports derived-data drift found (and optionally corrected) by Recompute.
+type RecomputeResult struct {
+	Info        *StoreInfo `json:"info"`
+	TaskRows    int        `json:"task_rows"`
+	FTSRows     int        `json:"fts_rows"`
+	FTSMissing  []string   `json:"fts_missing"`
+	FTSOrphaned []string   `json:"fts_orphaned"`
+	FTSStale    []string   `json:"fts_stale"`
+	Corrected   int        `json:"corrected"`
+	DryRun      bool       `json:"dry_run"`
+}
+
+// Recompute recomputes store counts and validates tasks_fts against tasks.
+// Unless dryRun is set, drifted FTS rows are rebuilt from the tasks table.
+func (s *Store) Recompute(ctx context.Context, dryRun bool) (result *RecomputeResult, err error) {
+	ctx = WithoutQueryTimeout(ctx)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	result = &RecomputeResult{DryRun: dryRun}
+	if err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks").Scan(&result.TaskRows); err != nil {
+		return nil, err
+	}
+	if err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks_fts").Scan(&result.FTSRows); err != nil {
+		return nil, err
+	}
+
+	if result.FTSMissing, err = queryIDs(ctx, tx, `
+		SELECT t.id FROM tasks t
+		WHERE NOT EXISTS (SELECT 1 FROM tasks_fts f WHERE f.task_id = t.id)
+		ORDER BY t.id`); err != nil {
+		return nil, err
+	}
+	if result.FTSOrphaned, err = queryIDs(ctx, tx, `
+		SELECT DISTINCT f.task_id FROM tasks_fts f
+		WHERE NOT EXISTS (SELECT 1 FROM tasks t WHERE t.id = f.task_id)
+		ORDER BY f.task_id`); err != nil {
+		return nil, err
+	}
+	if result.FTSStale, err = queryIDs(ctx, tx, `
+		SELECT t.id FROM tasks t
+		JOIN tasks_fts f ON f.task_id = t.id
+		WHERE f.title IS DISTINCT FROM t.title
+			OR f.description IS DISTINCT FROM COALESCE(t.description, '')
+			OR f.notes IS DISTINCT FROM COALESCE(t.notes, '')
+			OR f.design IS DISTINCT FROM COALESCE(t.design, '')
+			OR f.acceptance_criteria IS DISTINCT FROM COALESCE(t.acceptance_criteria, '')
+			OR f.assignee IS DISTINCT FROM COALESCE(t.assignee, '')
+			OR f.custom IS DISTINCT FROM `+s.dialect.customSearchText("t.custom")+`
+		UNION
+		SELECT t.id FROM tasks t
+		JOIN tasks_fts f ON f.task_id = t.id
+		GROUP BY t.id
+		HAVING COUNT(*) > 1
+		ORDER BY 1`); err != nil {
+		return nil, err
+	}
+
+	drifted := uniqueStrings(append(append(append([]string{}, result.FTSMissing...), result.FTSOrphaned...), result.FTSStale...))
+	sort.Strings(drifted)
+	if !dryRun {
+		for _, id := range drifted {
+			if _, err = tx.ExecContext(ctx, "DELETE FROM tasks_fts WHERE task_id = ?", id); err != nil {
+				return nil, err
+			}
+			if _, err = tx.ExecContext(ctx, ftsInsertFromTasks(s.dialect)+" WHERE id = ?", id); err != nil {
+				return nil, err
+			}
+		}
+		result.Corrected = len(drifted)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	result.Info, err = s.StoreInfo(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func queryIDs(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
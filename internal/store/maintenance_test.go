@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -13,7 +14,7 @@ func TestStoreInfo(t *testing.T) {
 	ctx := context.Background()
 	now := time.Now().UTC().Truncate(time.Millisecond)
 
-	info, err := st.StoreInfo(ctx)
+	info, err := st.StoreInfo(ctx, "")
 	if err != nil {
 		t.Fatalf("info: %v", err)
 	}
@@ -34,7 +35,7 @@ func TestStoreInfo(t *testing.T) {
 		}
 	}
 
-	info, err = st.StoreInfo(ctx)
+	info, err = st.StoreInfo(ctx, "")
 	if err != nil {
 		t.Fatalf("info: %v", err)
 	}
@@ -49,6 +50,38 @@ func TestStoreInfo(t *testing.T) {
 	}
 }
 
+func TestStoreInfoProjectScope(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, task := range []*models.Task{
+		{ID: "gr-ip01", Title: "gr open", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+		{ID: "xy-ip01", Title: "xy open", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+		{ID: "xy-ip02", Title: "xy closed", Status: "closed", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+
+	info, err := st.StoreInfo(ctx, "gr")
+	if err != nil {
+		t.Fatalf("project info: %v", err)
+	}
+	if info.Project != "gr" || info.TotalTasks != 1 || info.TaskCounts["closed"] != 0 || info.Projects != nil {
+		t.Fatalf("unexpected project info: %#v", info)
+	}
+
+	info, err = st.StoreInfo(ctx, "")
+	if err != nil {
+		t.Fatalf("global info: %v", err)
+	}
+	if info.TotalTasks != 3 || len(info.Projects) != 2 || info.Projects[0] != "gr" || info.Projects[1] != "xy" {
+		t.Fatalf("unexpected global info: %#v", info)
+	}
+}
+
 func TestCleanupClosedTasksProjectFilter(t *testing.T) {
 	st := testStore(t)
 	ctx := context.Background()
@@ -177,3 +210,157 @@ func TestCleanupClosedTasks(t *testing.T) {
 		}
 	})
 }
+
+func TestRecomputeRepairsFTSDrift(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	if err := st.CreateTask(ctx, &models.Task{ID: "gr-rc01", Title: "Indexed", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// Simulate an out-of-band write that bypasses the FTS insert trigger.
+	if _, err := st.db.ExecContext(ctx, "DROP TRIGGER tasks_fts_insert"); err != nil {
+		t.Fatalf("drop trigger: %v", err)
+	}
+	if _, err := st.db.ExecContext(ctx, `INSERT INTO tasks (id, project_id, title, status, type, priority, created_at, updated_at)
+		VALUES ('gr-rc02', 'gr', 'Bypassed searchable', 'open', 'task', 2, ?, ?)`, dbFormatTime(now), dbFormatTime(now)); err != nil {
+		t.Fatalf("raw insert: %v", err)
+	}
+	if _, err := st.db.ExecContext(ctx, "INSERT INTO tasks_fts(task_id, title, description, notes) VALUES ('gr-gone', 'orphan', '', '')"); err != nil {
+		t.Fatalf("orphan fts insert: %v", err)
+	}
+
+	report, err := st.Recompute(ctx, true)
+	if err != nil {
+		t.Fatalf("recompute dry run: %v", err)
+	}
+	if report.TaskRows != 2 || report.FTSRows != 2 {
+		t.Fatalf("expected 2 task rows and 2 fts rows, got %d/%d", report.TaskRows, report.FTSRows)
+	}
+	if len(report.FTSMissing) != 1 || report.FTSMissing[0] != "gr-rc02" {
+		t.Fatalf("expected gr-rc02 missing from fts, got %#v", report.FTSMissing)
+	}
+	if len(report.FTSOrphaned) != 1 || report.FTSOrphaned[0] != "gr-gone" {
+		t.Fatalf("expected gr-gone orphaned, got %#v", report.FTSOrphaned)
+	}
+	if report.Corrected != 0 {
+		t.Fatalf("dry run should not correct, got %d", report.Corrected)
+	}
+	if report.Info == nil || report.Info.TotalTasks != 2 {
+		t.Fatalf("expected info total 2, got %#v", report.Info)
+	}
+
+	report, err = st.Recompute(ctx, false)
+	if err != nil {
+		t.Fatalf("recompute: %v", err)
+	}
+	if report.Corrected != 2 {
+		t.Fatalf("expected 2 corrected, got %d", report.Corrected)
+	}
+
+	report, err = st.Recompute(ctx, true)
+	if err != nil {
+		t.Fatalf("recompute after repair: %v", err)
+	}
+	if report.TaskRows != report.FTSRows || len(report.FTSMissing)+len(report.FTSOrphaned)+len(report.FTSStale) != 0 {
+		t.Fatalf("expected no drift after repair, got %#v", report)
+	}
+
+	tasks, err := st.ListTasks(ctx, ListFilter{SearchQuery: "bypassed"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "gr-rc02" {
+		t.Fatalf("expected repaired task to be searchable, got %#v", tasks)
+	}
+}
+
+func TestRecomputeDetectsStaleFTSContent(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	if err := st.CreateTask(ctx, &models.Task{ID: "gr-rc03", Title: "Fresh", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := st.db.ExecContext(ctx, "UPDATE tasks_fts SET title = 'Stale' WHERE task_id = 'gr-rc03'"); err != nil {
+		t.Fatalf("corrupt fts: %v", err)
+	}
+
+	report, err := st.Recompute(ctx, false)
+	if err != nil {
+		t.Fatalf("recompute: %v", err)
+	}
+	if len(report.FTSStale) != 1 || report.FTSStale[0] != "gr-rc03" || report.Corrected != 1 {
+		t.Fatalf("expected gr-rc03 stale and corrected, got %#v", report)
+	}
+}
+
+func TestDeleteRestoreAndPurgeTombstonedTasks(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	old := now.Add(-60 * 24 * time.Hour)
+
+	for _, task := range []*models.Task{
+		{ID: "gr-tb01", Title: "open", Status: "open", Type: "task", Priority: 2, CreatedAt: old, UpdatedAt: old},
+		{ID: "gr-tb02", Title: "closed", Status: "closed", Type: "task", Priority: 2, CreatedAt: old, UpdatedAt: old, ClosedAt: &old},
+		{ID: "gr-tb03", Title: "recent", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+
+	if err := st.DeleteTasks(ctx, "gr", []string{"gr-tb01", "gr-zzzz"}, old); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+	if err := st.DeleteTasks(ctx, "gr", []string{"gr-tb01", "gr-tb02"}, old); err != nil {
+		t.Fatalf("delete old: %v", err)
+	}
+	if err := st.DeleteTasks(ctx, "gr", []string{"gr-tb03"}, now); err != nil {
+		t.Fatalf("delete recent: %v", err)
+	}
+
+	listed, err := st.ListTasks(ctx, ListFilter{Project: "gr", ExcludeTombstones: true})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(listed) != 0 {
+		t.Fatalf("expected tombstones hidden, got %d tasks", len(listed))
+	}
+
+	if err := st.RestoreTasks(ctx, "gr", []string{"gr-tb02"}, now); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	restored, err := st.GetTask(ctx, "gr-tb02")
+	if err != nil {
+		t.Fatalf("get restored: %v", err)
+	}
+	if restored.Status != "closed" || restored.DeletedAt != nil || restored.ClosedAt == nil {
+		t.Fatalf("expected restored task closed without deleted_at, got %#v", restored)
+	}
+	if err := st.RestoreTasks(ctx, "gr", []string{"gr-tb02"}, now); !errors.Is(err, ErrTaskNotTombstoned) {
+		t.Fatalf("expected ErrTaskNotTombstoned, got %v", err)
+	}
+
+	cutoff := now.Add(-30 * 24 * time.Hour)
+	result, err := st.PurgeTombstonedTasks(ctx, "gr", cutoff, false)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if result.Count != 1 || result.TaskIDs[0] != "gr-tb01" {
+		t.Fatalf("expected to purge only gr-tb01, got %#v", result)
+	}
+	for id, wantExists := range map[string]bool{"gr-tb01": false, "gr-tb02": true, "gr-tb03": true} {
+		task, err := st.GetTask(ctx, id)
+		if err != nil {
+			t.Fatalf("get %s: %v", id, err)
+		}
+		if (task != nil) != wantExists {
+			t.Fatalf("%s: expected exists=%v", id, wantExists)
+		}
+	}
+}
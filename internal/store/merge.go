@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"grns/internal/models"
+)
+
+// MergeTask folds duplicateID into canonicalID in one transaction. Labels,
+// dependencies, attachments, and git refs move to the canonical task, the
+// duplicate's notes are appended to the canonical notes, and the duplicate is
+// tombstoned with merged_into pointing at the canonical task.
+func (s *Store) MergeTask(ctx context.Context, project, duplicateID, canonicalID string, mergedAt time.Time) error {
+	return s.withTx(ctx, func(m *txImportMutator) error {
+		return m.MergeTask(ctx, project, duplicateID, canonicalID, mergedAt)
+	})
+}
+
+func (m *txImportMutator) MergeTask(ctx context.Context, project, duplicateID, canonicalID string, mergedAt time.Time) error {
+	project = normalizeProject(project)
+
+	// Lock both tasks, in id order, so that a concurrent merge or update
+	// cannot change either one between the reads and writes below.
+	locked, err := queryIDs(ctx, m.tx,
+		"SELECT id FROM tasks WHERE project_id = ? AND id IN (?, ?) ORDER BY id"+m.dialect.forUpdate(false),
+		project, duplicateID, canonicalID)
+	if err != nil {
+		return err
+	}
+	if len(locked) != 2 {
+		return ErrTaskNotFound
+	}
+
+	statements := []struct {
+		query string
+		args  []any
+	}{
+		{"INSERT OR IGNORE INTO task_labels (task_id, label) SELECT ?, label FROM task_labels WHERE task_id = ?", []any{canonicalID, duplicateID}},
+		{"DELETE FROM task_labels WHERE task_id = ?", []any{duplicateID}},
+		{"INSERT OR IGNORE INTO task_deps (child_id, parent_id, type) SELECT ?, parent_id, type FROM task_deps WHERE child_id = ? AND parent_id != ?", []any{canonicalID, duplicateID, canonicalID}},
+		{"INSERT OR IGNORE INTO task_deps (child_id, parent_id, type) SELECT child_id, ?, type FROM task_deps WHERE parent_id = ? AND child_id != ?", []any{canonicalID, duplicateID, canonicalID}},
+		{"DELETE FROM task_deps WHERE child_id = ? OR parent_id = ?", []any{duplicateID, duplicateID}},
+		{"UPDATE attachments SET task_id = ? WHERE task_id = ?", []any{canonicalID, duplicateID}},
+		// Refs the canonical task already carries stay behind and are dropped.
+		{`UPDATE task_git_refs SET task_id = ? WHERE task_id = ? AND NOT EXISTS (
+			SELECT 1 FROM task_git_refs c
+			WHERE c.task_id = ? AND c.repo_id = task_git_refs.repo_id AND c.relation = task_git_refs.relation
+				AND c.object_type = task_git_refs.object_type AND c.object_value = task_git_refs.object_value
+				AND COALESCE(c.resolved_commit, '') = COALESCE(task_git_refs.resolved_commit, ''))`, []any{canonicalID, duplicateID, canonicalID}},
+		{"DELETE FROM task_git_refs WHERE task_id = ?", []any{duplicateID}},
+	}
+	for _, stmt := range statements {
+		if _, err := m.tx.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+			return err
+		}
+	}
+
+	notes, err := mergedNotes(ctx, m.tx, duplicateID, canonicalID)
+	if err != nil {
+		return err
+	}
+	now := dbFormatTime(mergedAt)
+	if _, err := m.tx.ExecContext(ctx, "UPDATE tasks SET notes = ?, updated_at = ? WHERE id = ?", nullIfEmpty(notes), now, canonicalID); err != nil {
+		return err
+	}
+	if _, err := m.tx.ExecContext(ctx,
+		"UPDATE tasks SET status = ?, deleted_at = ?, merged_into = ?, updated_at = ? WHERE id = ?",
+		string(models.StatusTombstone), now, canonicalID, now, duplicateID,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+// mergedNotes returns the canonical notes with the duplicate's notes appended
+// under a "Merged from" header.
+func mergedNotes(ctx context.Context, tx *sql.Tx, duplicateID, canonicalID string) (string, error) {
+	var duplicateNotes, canonicalNotes sql.NullString
+	if err := tx.QueryRowContext(ctx, "SELECT notes FROM tasks WHERE id = ?", duplicateID).Scan(&duplicateNotes); err != nil {
+		return "", err
+	}
+	if err := tx.QueryRowContext(ctx, "SELECT notes FROM tasks WHERE id = ?", canonicalID).Scan(&canonicalNotes); err != nil {
+		return "", err
+	}
+	appended := strings.TrimSpace(duplicateNotes.String)
+	if appended == "" {
+		return canonicalNotes.String, nil
+	}
+	section := fmt.Sprintf("Merged from %s:\n%s", duplicateID, appended)
+	if strings.TrimSpace(canonicalNotes.String) == "" {
+		return section, nil
+	}
+	return strings.TrimRight(canonicalNotes.String, "\n") + "\n\n" + section, nil
+}
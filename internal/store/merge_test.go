@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"grns/internal/models"
+)
+
+func TestMergeTaskMovesRelatedRows(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, task := range []*models.Task{
+		{ID: "gr-mg01", Title: "canonical", Status: "open", Type: "bug", Priority: 1, Notes: "seen on linux", CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-mg02", Title: "duplicate", Status: "open", Type: "bug", Priority: 2, Notes: "also on mac", CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-mg03", Title: "blocker", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-mg04", Title: "blocked", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+	if err := st.AddLabels(ctx, "gr-mg01", []string{"crash"}); err != nil {
+		t.Fatalf("label canonical: %v", err)
+	}
+	if err := st.AddLabels(ctx, "gr-mg02", []string{"crash", "macos"}); err != nil {
+		t.Fatalf("label duplicate: %v", err)
+	}
+	for _, dep := range [][2]string{{"gr-mg02", "gr-mg03"}, {"gr-mg04", "gr-mg02"}, {"gr-mg02", "gr-mg01"}} {
+		if err := st.AddDependency(ctx, dep[0], dep[1], "blocks"); err != nil {
+			t.Fatalf("add dep %v: %v", dep, err)
+		}
+	}
+	if err := st.CreateAttachment(ctx, &models.Attachment{
+		ID:              "at-mg01",
+		TaskID:          "gr-mg02",
+		Kind:            string(models.AttachmentKindDiagnostic),
+		SourceType:      string(models.AttachmentSourceExternalURL),
+		ExternalURL:     "https://example.com/crash.log",
+		MediaTypeSource: string(models.MediaTypeSourceUnknown),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}); err != nil {
+		t.Fatalf("create attachment: %v", err)
+	}
+	repo, err := st.UpsertGitRepo(ctx, &models.GitRepo{Slug: "github.com/acme/repo"})
+	if err != nil {
+		t.Fatalf("upsert repo: %v", err)
+	}
+	if err := st.CreateTaskGitRef(ctx, &models.TaskGitRef{
+		ID: "gf-mg01", TaskID: "gr-mg02", RepoID: repo.ID, Relation: "related",
+		ObjectType: "path", ObjectValue: "src/crash.go", CreatedAt: now, UpdatedAt: now,
+	}); err != nil {
+		t.Fatalf("create git ref: %v", err)
+	}
+
+	if err := st.MergeTask(ctx, "gr", "gr-mg02", "gr-zzzz", now); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+	if err := st.MergeTask(ctx, "gr", "gr-mg02", "gr-mg01", now); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	labels, err := st.ListLabels(ctx, "gr-mg01")
+	if err != nil {
+		t.Fatalf("list labels: %v", err)
+	}
+	if len(labels) != 2 || labels[0] != "crash" || labels[1] != "macos" {
+		t.Fatalf("expected merged labels [crash macos], got %v", labels)
+	}
+
+	deps, err := st.ListDependencies(ctx, "gr-mg01")
+	if err != nil {
+		t.Fatalf("list canonical deps: %v", err)
+	}
+	if len(deps) != 1 || deps[0].ParentID != "gr-mg03" {
+		t.Fatalf("expected canonical blocked by gr-mg03 only, got %#v", deps)
+	}
+	deps, err = st.ListDependencies(ctx, "gr-mg04")
+	if err != nil {
+		t.Fatalf("list dependent deps: %v", err)
+	}
+	if len(deps) != 1 || deps[0].ParentID != "gr-mg01" {
+		t.Fatalf("expected gr-mg04 repointed at canonical, got %#v", deps)
+	}
+
+	attachments, err := st.ListAttachmentsByTask(ctx, "gr", "gr-mg01")
+	if err != nil {
+		t.Fatalf("list attachments: %v", err)
+	}
+	if len(attachments) != 1 || attachments[0].ID != "at-mg01" {
+		t.Fatalf("expected attachment moved, got %#v", attachments)
+	}
+	refs, err := st.ListTaskGitRefs(ctx, "gr", "gr-mg01")
+	if err != nil {
+		t.Fatalf("list git refs: %v", err)
+	}
+	if len(refs) != 1 || refs[0].ID != "gf-mg01" {
+		t.Fatalf("expected git ref moved, got %#v", refs)
+	}
+
+	canonical, err := st.GetTask(ctx, "gr-mg01")
+	if err != nil {
+		t.Fatalf("get canonical: %v", err)
+	}
+	if canonical.Notes != "seen on linux\n\nMerged from gr-mg02:\nalso on mac" {
+		t.Fatalf("unexpected merged notes: %q", canonical.Notes)
+	}
+	duplicate, err := st.GetTask(ctx, "gr-mg02")
+	if err != nil {
+		t.Fatalf("get duplicate: %v", err)
+	}
+	if duplicate.Status != "tombstone" || duplicate.MergedInto != "gr-mg01" || duplicate.DeletedAt == nil {
+		t.Fatalf("expected duplicate tombstoned into gr-mg01, got %#v", duplicate)
+	}
+}
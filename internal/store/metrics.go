@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+// SetQueryObserver installs fn to receive SQL statement durations. A nil fn
+// disables observation.
+func (s *Store) SetQueryObserver(fn QueryObserver) {
+	if fn == nil {
+		s.queryObserver.Store(nil)
+		return
+	}
+	s.queryObserver.Store(&fn)
+}
+
+// BlobStorageBytes returns the total size of all stored blobs.
+func (s *Store) BlobStorageBytes(ctx context.Context) (int64, error) {
+	var total int64
+	err := s.db.QueryRowContext(ctx, "SELECT COALESCE(SUM(size_bytes), 0) FROM blobs").Scan(&total)
+	return total, err
+}
+
+func (s *Store) observeQuery(op string, start time.Time) {
+	if fn := s.queryObserver.Load(); fn != nil {
+		(*fn)(op, time.Since(start))
+	}
+}
+
+// observedConnector opens connections that report statement timings to the
+// owning store's query observer, adapted to the store's dialect.
+type observedConnector struct {
+	dsn    string
+	driver driver.Driver
+	store  *Store
+}
+
+func (c *observedConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return c.store.dialect.conn(&observedConn{Conn: conn, store: c.store}), nil
+}
+
+func (c *observedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// observedConn forwards to the driver connection, timing direct exec and
+// query calls. Optional interfaces the driver lacks fall back to
+// database/sql's default behavior.
+type observedConn struct {
+	driver.Conn
+	store *Store
+}
+
+func (c *observedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return c.store.timedExec(ctx, func(ctx context.Context) (driver.Result, error) {
+		return execer.ExecContext(ctx, query, args)
+	})
+}
+
+func (c *observedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return c.store.timedQuery(ctx, func(ctx context.Context) (driver.Rows, error) {
+		return queryer.QueryContext(ctx, query, args)
+	})
+}
+
+func (c *observedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &observedStmt{Stmt: stmt, store: c.store}, nil
+}
+
+// observedStmt times prepared statements, which skip the connection's
+// ExecContext and QueryContext.
+type observedStmt struct {
+	driver.Stmt
+	store *Store
+}
+
+func (s *observedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.store.timedExec(ctx, func(ctx context.Context) (driver.Result, error) {
+		if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+			return execer.ExecContext(ctx, args)
+		}
+		values, err := namedValuesToValues(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.Stmt.Exec(values)
+	})
+}
+
+func (s *observedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.store.timedQuery(ctx, func(ctx context.Context) (driver.Rows, error) {
+		if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+			return queryer.QueryContext(ctx, args)
+		}
+		values, err := namedValuesToValues(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.Stmt.Query(values)
+	})
+}
+
+// timedExec runs exec under the query timeout and reports its duration.
+func (s *Store) timedExec(ctx context.Context, exec func(context.Context) (driver.Result, error)) (driver.Result, error) {
+	defer s.observeQuery("exec", time.Now())
+	ctx, cancel, ok := s.statementContext(ctx)
+	if !ok {
+		return exec(ctx)
+	}
+	defer cancel()
+	result, err := exec(ctx)
+	return result, queryTimeoutError(ctx, s.queryTimeout, err)
+}
+
+// timedQuery runs query under the query timeout, which keeps running until
+// the returned rows are closed, and reports how long the query took to start.
+func (s *Store) timedQuery(ctx context.Context, query func(context.Context) (driver.Rows, error)) (driver.Rows, error) {
+	defer s.observeQuery("query", time.Now())
+	ctx, cancel, ok := s.statementContext(ctx)
+	if !ok {
+		return query(ctx)
+	}
+	rows, err := query(ctx)
+	if err != nil {
+		cancel()
+		return nil, queryTimeoutError(ctx, s.queryTimeout, err)
+	}
+	return &timeoutRows{Rows: rows, ctx: ctx, cancel: cancel, timeout: s.queryTimeout}, nil
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sqlite driver does not support named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+func (c *observedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *observedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *observedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *observedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
@@ -0,0 +1,18 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// QueryObserver receives the duration of each SQL statement. Op is "exec" or
+// "query".
+type QueryObserver func(op string, duration time.Duration)
+
+// MetricsStore exposes database-level observations for the metrics endpoint.
+type MetricsStore interface {
+	SetQueryObserver(fn QueryObserver)
+	BlobStorageBytes(ctx context.Context) (int64, error)
+}
+
+var _ MetricsStore = (*Store)(nil)
@@ -4,13 +4,18 @@ import (
 	"database/sql"
 	"fmt"
 	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
-// Migration represents a schema migration step.
+// Migration represents a schema migration step. Down reverses SQL; an empty
+// Down marks the step as irreversible.
 type Migration struct {
 	Version     int
 	Description string
 	SQL         string
+	Down        string
 }
 
 // MigrationStatus reports the current and available migration versions.
@@ -26,6 +31,49 @@ type MigrationInfo struct {
 	Description string `json:"description"`
 }
 
+// Migration directions reported by MigrationStep.
+const (
+	MigrationUp   = "up"
+	MigrationDown = "down"
+)
+
+// MigrationStep is one migration applied or reverted by MigrateTo.
+type MigrationStep struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	Direction   string `json:"direction"`
+}
+
+// tasksFTSv3SQL creates the original title/description/notes search index.
+// Migration 12 rolls back to it.
+const tasksFTSv3SQL = `
+CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
+	task_id UNINDEXED,
+	title,
+	description,
+	notes
+);
+
+INSERT INTO tasks_fts(task_id, title, description, notes)
+	SELECT id, title, COALESCE(description, ''), COALESCE(notes, '')
+	FROM tasks;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
+	INSERT INTO tasks_fts(task_id, title, description, notes)
+		VALUES (new.id, new.title, COALESCE(new.description, ''), COALESCE(new.notes, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE ON tasks BEGIN
+	DELETE FROM tasks_fts WHERE task_id = old.id;
+	INSERT INTO tasks_fts(task_id, title, description, notes)
+		VALUES (new.id, new.title, COALESCE(new.description, ''), COALESCE(new.notes, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
+	DELETE FROM tasks_fts WHERE task_id = old.id;
+END;
+`
+
 // migrations is the ordered list of all schema migrations.
 var migrations = []Migration{
 	{
@@ -83,37 +131,27 @@ ALTER TABLE tasks ADD COLUMN source_repo TEXT;
 
 CREATE INDEX IF NOT EXISTS idx_tasks_assignee ON tasks(assignee);
 CREATE INDEX IF NOT EXISTS idx_tasks_source_repo ON tasks(source_repo);
+`,
+		Down: `
+DROP INDEX IF EXISTS idx_tasks_assignee;
+DROP INDEX IF EXISTS idx_tasks_source_repo;
+
+ALTER TABLE tasks DROP COLUMN source_repo;
+ALTER TABLE tasks DROP COLUMN acceptance_criteria;
+ALTER TABLE tasks DROP COLUMN design;
+ALTER TABLE tasks DROP COLUMN notes;
+ALTER TABLE tasks DROP COLUMN assignee;
 `,
 	},
 	{
 		Version:     3,
 		Description: "FTS5 full-text search on tasks",
-		SQL: `
-CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
-	task_id UNINDEXED,
-	title,
-	description,
-	notes
-);
-
-INSERT INTO tasks_fts(task_id, title, description, notes)
-	SELECT id, title, COALESCE(description, ''), COALESCE(notes, '')
-	FROM tasks;
-
-CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
-	INSERT INTO tasks_fts(task_id, title, description, notes)
-		VALUES (new.id, new.title, COALESCE(new.description, ''), COALESCE(new.notes, ''));
-END;
-
-CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE ON tasks BEGIN
-	DELETE FROM tasks_fts WHERE task_id = old.id;
-	INSERT INTO tasks_fts(task_id, title, description, notes)
-		VALUES (new.id, new.title, COALESCE(new.description, ''), COALESCE(new.notes, ''));
-END;
-
-CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
-	DELETE FROM tasks_fts WHERE task_id = old.id;
-END;
+		SQL:         tasksFTSv3SQL,
+		Down: `
+DROP TRIGGER IF EXISTS tasks_fts_insert;
+DROP TRIGGER IF EXISTS tasks_fts_update;
+DROP TRIGGER IF EXISTS tasks_fts_delete;
+DROP TABLE IF EXISTS tasks_fts;
 `,
 	},
 	{
@@ -123,6 +161,11 @@ END;
 CREATE INDEX IF NOT EXISTS idx_tasks_updated_at_desc ON tasks(updated_at DESC);
 CREATE INDEX IF NOT EXISTS idx_tasks_type_updated_desc ON tasks(type, updated_at DESC);
 CREATE INDEX IF NOT EXISTS idx_tasks_assignee_updated_desc ON tasks(assignee, updated_at DESC);
+`,
+		Down: `
+DROP INDEX IF EXISTS idx_tasks_updated_at_desc;
+DROP INDEX IF EXISTS idx_tasks_type_updated_desc;
+DROP INDEX IF EXISTS idx_tasks_assignee_updated_desc;
 `,
 	},
 	{
@@ -181,6 +224,11 @@ CREATE INDEX IF NOT EXISTS idx_attachments_source_type ON attachments(source_typ
 CREATE INDEX IF NOT EXISTS idx_attachments_blob_id ON attachments(blob_id);
 CREATE INDEX IF NOT EXISTS idx_attachments_expires_at ON attachments(expires_at);
 CREATE INDEX IF NOT EXISTS idx_attachment_labels_label ON attachment_labels(label);
+`,
+		Down: `
+DROP TABLE IF EXISTS attachment_labels;
+DROP TABLE IF EXISTS attachments;
+DROP TABLE IF EXISTS blobs;
 `,
 	},
 	{
@@ -223,6 +271,10 @@ CREATE UNIQUE INDEX IF NOT EXISTS uq_task_git_refs_dedupe
 CREATE INDEX IF NOT EXISTS idx_task_git_refs_task_created ON task_git_refs(task_id, created_at);
 CREATE INDEX IF NOT EXISTS idx_task_git_refs_repo_object ON task_git_refs(repo_id, object_type, object_value);
 CREATE INDEX IF NOT EXISTS idx_task_git_refs_relation ON task_git_refs(relation);
+`,
+		Down: `
+DROP TABLE IF EXISTS task_git_refs;
+DROP TABLE IF EXISTS git_repos;
 `,
 	},
 	{
@@ -250,6 +302,15 @@ WHERE project_id IS NOT NULL AND project_id GLOB '[a-z][a-z]';
 CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks(project_id);
 CREATE INDEX IF NOT EXISTS idx_tasks_project_updated_desc ON tasks(project_id, updated_at DESC);
 CREATE INDEX IF NOT EXISTS idx_tasks_project_status_updated_desc ON tasks(project_id, status, updated_at DESC);
+`,
+		Down: `
+DROP INDEX IF EXISTS idx_tasks_project_id;
+DROP INDEX IF EXISTS idx_tasks_project_updated_desc;
+DROP INDEX IF EXISTS idx_tasks_project_status_updated_desc;
+
+ALTER TABLE tasks DROP COLUMN project_id;
+
+DROP TABLE IF EXISTS projects;
 `,
 	},
 	{
@@ -283,6 +344,593 @@ CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
 CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
 CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
 CREATE INDEX IF NOT EXISTS idx_sessions_token_hash ON sessions(token_hash);
+`,
+		Down: `
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
+`,
+	},
+	{
+		Version:     9,
+		Description: "comments: add task_comments table",
+		SQL: `
+CREATE TABLE IF NOT EXISTS task_comments (
+  id TEXT PRIMARY KEY,
+  task_id TEXT NOT NULL,
+  author TEXT NOT NULL,
+  body TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+  CHECK (length(trim(author)) > 0),
+  CHECK (length(trim(body)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_created ON task_comments(task_id, created_at);
+`,
+		Down: `
+DROP TABLE IF EXISTS task_comments;
+`,
+	},
+	{
+		Version:     10,
+		Description: "history: add task_events audit table",
+		SQL: `
+CREATE TABLE IF NOT EXISTS task_events (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  task_id TEXT NOT NULL,
+  event_type TEXT NOT NULL,
+  actor TEXT,
+  changes_json TEXT,
+  created_at TEXT NOT NULL,
+  FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_events_task_id ON task_events(task_id, id);
+`,
+		Down: `
+DROP TABLE IF EXISTS task_events;
+`,
+	},
+	{
+		Version:     11,
+		Description: "views: add saved_filters table",
+		SQL: `
+CREATE TABLE IF NOT EXISTS saved_filters (
+  project_id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  filter_json TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  PRIMARY KEY (project_id, name)
+);
+`,
+		Down: `
+DROP TABLE IF EXISTS saved_filters;
+`,
+	},
+	{
+		Version:     12,
+		Description: "search: index design, acceptance criteria, assignee, and custom values in tasks_fts",
+		SQL: `
+DROP TRIGGER IF EXISTS tasks_fts_insert;
+DROP TRIGGER IF EXISTS tasks_fts_update;
+DROP TRIGGER IF EXISTS tasks_fts_delete;
+DROP TABLE IF EXISTS tasks_fts;
+
+CREATE VIRTUAL TABLE tasks_fts USING fts5(
+	task_id UNINDEXED,
+	title,
+	description,
+	notes,
+	design,
+	acceptance_criteria,
+	assignee,
+	custom
+);
+
+INSERT INTO tasks_fts(task_id, title, description, notes, design, acceptance_criteria, assignee, custom)
+	SELECT id, title, COALESCE(description, ''), COALESCE(notes, ''), COALESCE(design, ''),
+		COALESCE(acceptance_criteria, ''), COALESCE(assignee, ''),
+		COALESCE(CASE WHEN json_valid(custom) THEN (SELECT group_concat(value, ' ') FROM json_tree(custom) WHERE type NOT IN ('object', 'array')) END, '')
+	FROM tasks;
+
+CREATE TRIGGER tasks_fts_insert AFTER INSERT ON tasks BEGIN
+	INSERT INTO tasks_fts(task_id, title, description, notes, design, acceptance_criteria, assignee, custom)
+		VALUES (new.id, new.title, COALESCE(new.description, ''), COALESCE(new.notes, ''), COALESCE(new.design, ''),
+			COALESCE(new.acceptance_criteria, ''), COALESCE(new.assignee, ''),
+			COALESCE(CASE WHEN json_valid(new.custom) THEN (SELECT group_concat(value, ' ') FROM json_tree(new.custom) WHERE type NOT IN ('object', 'array')) END, ''));
+END;
+
+CREATE TRIGGER tasks_fts_update AFTER UPDATE ON tasks BEGIN
+	DELETE FROM tasks_fts WHERE task_id = old.id;
+	INSERT INTO tasks_fts(task_id, title, description, notes, design, acceptance_criteria, assignee, custom)
+		VALUES (new.id, new.title, COALESCE(new.description, ''), COALESCE(new.notes, ''), COALESCE(new.design, ''),
+			COALESCE(new.acceptance_criteria, ''), COALESCE(new.assignee, ''),
+			COALESCE(CASE WHEN json_valid(new.custom) THEN (SELECT group_concat(value, ' ') FROM json_tree(new.custom) WHERE type NOT IN ('object', 'array')) END, ''));
+END;
+
+CREATE TRIGGER tasks_fts_delete AFTER DELETE ON tasks BEGIN
+	DELETE FROM tasks_fts WHERE task_id = old.id;
+END;
+`,
+		Down: `
+DROP TRIGGER IF EXISTS tasks_fts_insert;
+DROP TRIGGER IF EXISTS tasks_fts_update;
+DROP TRIGGER IF EXISTS tasks_fts_delete;
+DROP TABLE IF EXISTS tasks_fts;
+` + tasksFTSv3SQL,
+	},
+	{
+		Version:     13,
+		Description: "milestones: add milestones table and tasks.milestone_id",
+		SQL: `
+CREATE TABLE IF NOT EXISTS milestones (
+  id TEXT PRIMARY KEY,
+  project_id TEXT NOT NULL,
+  title TEXT NOT NULL,
+  due_date TEXT,
+  state TEXT NOT NULL DEFAULT 'open',
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_milestones_project ON milestones(project_id, state);
+
+ALTER TABLE tasks ADD COLUMN milestone_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_milestone_id ON tasks(milestone_id);
+`,
+		Down: `
+DROP INDEX IF EXISTS idx_tasks_milestone_id;
+
+ALTER TABLE tasks DROP COLUMN milestone_id;
+
+DROP TABLE IF EXISTS milestones;
+`,
+	},
+	{
+		Version:     14,
+		Description: "worklogs: add task_worklogs table",
+		SQL: `
+CREATE TABLE IF NOT EXISTS task_worklogs (
+  id TEXT PRIMARY KEY,
+  task_id TEXT NOT NULL,
+  actor TEXT NOT NULL,
+  seconds INTEGER NOT NULL,
+  note TEXT,
+  started_at TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+  CHECK (length(trim(actor)) > 0),
+  CHECK (seconds > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_worklogs_task_started ON task_worklogs(task_id, started_at);
+`,
+		Down: `
+DROP TABLE IF EXISTS task_worklogs;
+`,
+	},
+	{
+		Version:     15,
+		Description: "watchers: add task_watchers table",
+		SQL: `
+CREATE TABLE IF NOT EXISTS task_watchers (
+  task_id TEXT NOT NULL,
+  user TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  PRIMARY KEY (task_id, user),
+  FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+  CHECK (length(trim(user)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_watchers_user ON task_watchers(user);
+`,
+		Down: `
+DROP TABLE IF EXISTS task_watchers;
+`,
+	},
+	{
+		Version:     16,
+		Description: "tombstones: add tasks.deleted_at",
+		SQL: `
+ALTER TABLE tasks ADD COLUMN deleted_at TEXT;
+
+UPDATE tasks SET deleted_at = updated_at WHERE status = 'tombstone';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_status_deleted_at ON tasks(status, deleted_at);
+`,
+		Down: `
+DROP INDEX IF EXISTS idx_tasks_status_deleted_at;
+
+ALTER TABLE tasks DROP COLUMN deleted_at;
+`,
+	},
+	{
+		Version:     17,
+		Description: "merge: add tasks.merged_into",
+		SQL: `
+ALTER TABLE tasks ADD COLUMN merged_into TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_merged_into ON tasks(merged_into);
+`,
+		Down: `
+DROP INDEX IF EXISTS idx_tasks_merged_into;
+
+ALTER TABLE tasks DROP COLUMN merged_into;
+`,
+	},
+	{
+		Version:     18,
+		Description: "leases: add task_leases table",
+		SQL: `
+CREATE TABLE IF NOT EXISTS task_leases (
+  task_id TEXT PRIMARY KEY,
+  holder TEXT NOT NULL,
+  claimed_at TEXT NOT NULL,
+  expires_at TEXT NOT NULL,
+  FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+  CHECK (length(trim(holder)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_leases_expires_at ON task_leases(expires_at);
+`,
+		Down: `
+DROP TABLE IF EXISTS task_leases;
+`,
+	},
+	{
+		Version:     19,
+		Description: "blocked: add tasks.blocked_reason and tasks.blocked_on",
+		SQL: `
+ALTER TABLE tasks ADD COLUMN blocked_reason TEXT;
+ALTER TABLE tasks ADD COLUMN blocked_on TEXT;
+`,
+		Down: `
+ALTER TABLE tasks DROP COLUMN blocked_on;
+ALTER TABLE tasks DROP COLUMN blocked_reason;
+`,
+	},
+	{
+		Version:     20,
+		Description: "custom fields: add custom_fields schema table",
+		SQL: `
+CREATE TABLE IF NOT EXISTS custom_fields (
+  project_id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  type TEXT NOT NULL,
+  required INTEGER NOT NULL DEFAULT 0,
+  values_json TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  PRIMARY KEY (project_id, name)
+);
+`,
+		Down: `
+DROP TABLE IF EXISTS custom_fields;
+`,
+	},
+	{
+		Version:     21,
+		Description: "api tokens: add hashed named api_tokens table",
+		SQL: `
+CREATE TABLE IF NOT EXISTS api_tokens (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  token_hash TEXT NOT NULL UNIQUE,
+  scopes_json TEXT NOT NULL,
+  expires_at TEXT,
+  created_at TEXT NOT NULL,
+  rotated_at TEXT
+);
+`,
+		Down: `
+DROP TABLE IF EXISTS api_tokens;
+`,
+	},
+	{
+		Version:     22,
+		Description: "audit: add admin_audit table",
+		SQL: `
+CREATE TABLE IF NOT EXISTS admin_audit (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  action TEXT NOT NULL,
+  actor TEXT,
+  params_json TEXT,
+  affected INTEGER NOT NULL DEFAULT 0,
+  dry_run INTEGER NOT NULL DEFAULT 0,
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_action ON admin_audit(action, id);
+`,
+		Down: `
+DROP TABLE IF EXISTS admin_audit;
+`,
+	},
+	{
+		Version:     23,
+		Description: "undo: add operations journal table",
+		SQL: `
+CREATE TABLE IF NOT EXISTS operations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  project_id TEXT NOT NULL,
+  kind TEXT NOT NULL,
+  actor TEXT,
+  payload_json TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  undone_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_operations_project_id ON operations(project_id, id);
+`,
+		Down: `
+DROP TABLE IF EXISTS operations;
+`,
+	},
+	{
+		Version:     24,
+		Description: "changes: add task_changes sequence for incremental export",
+		SQL: `
+CREATE TABLE IF NOT EXISTS task_changes (
+  task_id TEXT PRIMARY KEY,
+  seq INTEGER NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_task_changes_seq ON task_changes(seq);
+
+INSERT INTO task_changes (task_id, seq)
+SELECT id, ROW_NUMBER() OVER (ORDER BY updated_at, id) FROM tasks WHERE true
+ON CONFLICT(task_id) DO NOTHING;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_task_insert AFTER INSERT ON tasks BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT NEW.id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) WHERE true
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_task_update AFTER UPDATE ON tasks BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT NEW.id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) WHERE true
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_label_insert AFTER INSERT ON task_labels BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = NEW.task_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_label_delete AFTER DELETE ON task_labels BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = OLD.task_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_dep_insert AFTER INSERT ON task_deps BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = NEW.child_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_dep_delete AFTER DELETE ON task_deps BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = OLD.child_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+`,
+		Down: `
+DROP TRIGGER IF EXISTS task_changes_task_insert;
+DROP TRIGGER IF EXISTS task_changes_task_update;
+DROP TRIGGER IF EXISTS task_changes_label_insert;
+DROP TRIGGER IF EXISTS task_changes_label_delete;
+DROP TRIGGER IF EXISTS task_changes_dep_insert;
+DROP TRIGGER IF EXISTS task_changes_dep_delete;
+DROP TABLE IF EXISTS task_changes;
+`,
+	},
+	{
+		Version:     25,
+		Description: "import: add import_reports table for conflict reports",
+		SQL: `
+CREATE TABLE IF NOT EXISTS import_reports (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  project_id TEXT NOT NULL,
+  dry_run INTEGER NOT NULL DEFAULT 0,
+  conflicts_json TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_reports_created_at ON import_reports(created_at);
+`,
+		Down: `
+DROP TABLE IF EXISTS import_reports;
+`,
+	},
+	{
+		Version:     26,
+		Description: "stats: add task_status_snapshots table for cumulative flow",
+		SQL: `
+CREATE TABLE IF NOT EXISTS task_status_snapshots (
+  project_id TEXT NOT NULL,
+  day TEXT NOT NULL,
+  status TEXT NOT NULL,
+  count INTEGER NOT NULL,
+  PRIMARY KEY (project_id, day, status)
+);
+`,
+		Down: `
+DROP TABLE IF EXISTS task_status_snapshots;
+`,
+	},
+	{
+		Version:     27,
+		Description: "api: add idempotency_keys table for replaying retried writes",
+		SQL: `
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  idem_key TEXT NOT NULL,
+  scope TEXT NOT NULL,
+  request_hash TEXT NOT NULL,
+  status INTEGER NOT NULL,
+  response_body BLOB NOT NULL,
+  created_at TEXT NOT NULL,
+  UNIQUE(idem_key, scope)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+`,
+		Down: `
+DROP TABLE IF EXISTS idempotency_keys;
+`,
+	},
+	{
+		Version:     28,
+		Description: "perf: add composite indexes for common list filters",
+		SQL: `
+CREATE INDEX IF NOT EXISTS idx_tasks_project_assignee_status ON tasks(project_id, assignee, status, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tasks_project_parent ON tasks(project_id, parent_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tasks_project_closed_at ON tasks(project_id, closed_at);
+CREATE INDEX IF NOT EXISTS idx_task_labels_label_task ON task_labels(label, task_id);
+`,
+		Down: `
+DROP INDEX IF EXISTS idx_tasks_project_assignee_status;
+DROP INDEX IF EXISTS idx_tasks_project_parent;
+DROP INDEX IF EXISTS idx_tasks_project_closed_at;
+DROP INDEX IF EXISTS idx_task_labels_label_task;
+`,
+	},
+	{
+		Version:     29,
+		Description: "changes: record hard-deleted tasks in task_changes as tombstones",
+		SQL: `
+ALTER TABLE task_changes ADD COLUMN project_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE task_changes ADD COLUMN deleted_at TEXT;
+
+UPDATE task_changes SET project_id = COALESCE(
+  (SELECT tasks.project_id FROM tasks WHERE tasks.id = task_changes.task_id),
+  lower(substr(task_id, 1, instr(task_id, '-') - 1))
+);
+-- Rows left behind by earlier deletes become tombstones, dated now.
+UPDATE task_changes SET deleted_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE task_id NOT IN (SELECT id FROM tasks);
+
+CREATE INDEX IF NOT EXISTS idx_task_changes_project_seq ON task_changes(project_id, seq);
+
+DROP TRIGGER IF EXISTS task_changes_task_insert;
+DROP TRIGGER IF EXISTS task_changes_task_update;
+DROP TRIGGER IF EXISTS task_changes_label_insert;
+DROP TRIGGER IF EXISTS task_changes_label_delete;
+DROP TRIGGER IF EXISTS task_changes_dep_insert;
+DROP TRIGGER IF EXISTS task_changes_dep_delete;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_task_insert AFTER INSERT ON tasks BEGIN
+  INSERT INTO task_changes (task_id, project_id, seq, deleted_at)
+  SELECT NEW.id, COALESCE(NEW.project_id, ''), (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes), NULL WHERE true
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq, project_id = excluded.project_id, deleted_at = NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_task_update AFTER UPDATE ON tasks BEGIN
+  INSERT INTO task_changes (task_id, project_id, seq, deleted_at)
+  SELECT NEW.id, COALESCE(NEW.project_id, ''), (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes), NULL WHERE true
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq, project_id = excluded.project_id, deleted_at = NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_task_delete AFTER DELETE ON tasks BEGIN
+  INSERT INTO task_changes (task_id, project_id, seq, deleted_at)
+  SELECT OLD.id, COALESCE(OLD.project_id, ''), (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes), strftime('%Y-%m-%dT%H:%M:%fZ', 'now') WHERE true
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq, project_id = excluded.project_id, deleted_at = excluded.deleted_at;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_label_insert AFTER INSERT ON task_labels BEGIN
+  INSERT INTO task_changes (task_id, project_id, seq)
+  SELECT id, COALESCE(project_id, ''), (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = NEW.task_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_label_delete AFTER DELETE ON task_labels BEGIN
+  INSERT INTO task_changes (task_id, project_id, seq)
+  SELECT id, COALESCE(project_id, ''), (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = OLD.task_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_dep_insert AFTER INSERT ON task_deps BEGIN
+  INSERT INTO task_changes (task_id, project_id, seq)
+  SELECT id, COALESCE(project_id, ''), (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = NEW.child_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_dep_delete AFTER DELETE ON task_deps BEGIN
+  INSERT INTO task_changes (task_id, project_id, seq)
+  SELECT id, COALESCE(project_id, ''), (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = OLD.child_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+`,
+		Down: `
+DROP TRIGGER IF EXISTS task_changes_task_insert;
+DROP TRIGGER IF EXISTS task_changes_task_update;
+DROP TRIGGER IF EXISTS task_changes_task_delete;
+DROP TRIGGER IF EXISTS task_changes_label_insert;
+DROP TRIGGER IF EXISTS task_changes_label_delete;
+DROP TRIGGER IF EXISTS task_changes_dep_insert;
+DROP TRIGGER IF EXISTS task_changes_dep_delete;
+
+DELETE FROM task_changes WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_task_changes_project_seq;
+ALTER TABLE task_changes DROP COLUMN deleted_at;
+ALTER TABLE task_changes DROP COLUMN project_id;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_task_insert AFTER INSERT ON tasks BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT NEW.id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) WHERE true
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_task_update AFTER UPDATE ON tasks BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT NEW.id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) WHERE true
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_label_insert AFTER INSERT ON task_labels BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = NEW.task_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_label_delete AFTER DELETE ON task_labels BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = OLD.task_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_dep_insert AFTER INSERT ON task_deps BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = NEW.child_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_changes_dep_delete AFTER DELETE ON task_deps BEGIN
+  INSERT INTO task_changes (task_id, seq)
+  SELECT id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = OLD.child_id
+  ON CONFLICT(task_id) DO UPDATE SET seq = excluded.seq;
+END;
+`,
+	},
+	{
+		Version:     30,
+		Description: "attachments: record upload scan quarantine on blobs",
+		SQL: `
+ALTER TABLE blobs ADD COLUMN quarantined_at TEXT;
+
+UPDATE blobs SET quarantined_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id IN (
+  SELECT blob_id FROM attachments
+  WHERE blob_id IS NOT NULL AND json_valid(meta_json) AND json_extract(meta_json, '$.scan.status') = 'quarantined'
+);
+`,
+		Down: `
+ALTER TABLE blobs DROP COLUMN quarantined_at;
 `,
 	},
 }
@@ -313,6 +961,9 @@ func currentVersion(db *sql.DB) (int, error) {
 // detectPreMigrationDB checks if the tasks table exists but no migrations have been recorded.
 // This indicates a database created before the migration framework was added.
 func detectPreMigrationDB(db *sql.DB) (bool, error) {
+	if dialectOf(db).name() != dialectSQLite {
+		return false, nil
+	}
 	var tasksExist int
 	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='tasks'").Scan(&tasksExist)
 	if err != nil {
@@ -343,60 +994,165 @@ func detectPreMigrationDB(db *sql.DB) (bool, error) {
 
 // runMigrations applies all pending migrations in order.
 func runMigrations(db *sql.DB) error {
+	if _, err := prepareMigrations(db); err != nil {
+		return err
+	}
+	_, err := migrateTo(db, latestMigrationVersion(dialectOf(db)), false)
+	return err
+}
+
+// MigrateTo moves the schema to version target: pending migrations up to
+// target are applied, and applied migrations above target are reverted
+// newest first. Each step runs in its own transaction. When dryRun is set,
+// the steps are returned without touching the schema.
+func MigrateTo(db *sql.DB, target int, dryRun bool) ([]MigrationStep, error) {
+	latest := latestMigrationVersion(dialectOf(db))
+	if target < 1 || target > latest {
+		return nil, fmt.Errorf("target version must be between 1 and %d", latest)
+	}
+	if _, err := prepareMigrations(db); err != nil {
+		return nil, err
+	}
+	return migrateTo(db, target, dryRun)
+}
+
+// prepareMigrations creates the migrations table and stamps databases created
+// before the migration framework as version 1. It reports whether the
+// database was such a pre-migration database.
+func prepareMigrations(db *sql.DB) (bool, error) {
 	// Detect pre-migration databases BEFORE creating the migrations table.
 	preMigration, err := detectPreMigrationDB(db)
 	if err != nil {
-		return fmt.Errorf("detect pre-migration db: %w", err)
+		return false, fmt.Errorf("detect pre-migration db: %w", err)
 	}
 
 	if err := ensureMigrationsTable(db); err != nil {
-		return fmt.Errorf("create migrations table: %w", err)
+		return false, fmt.Errorf("create migrations table: %w", err)
 	}
 
 	if preMigration {
 		// Mark migration 1 as applied since the schema already exists.
 		if _, err := db.Exec("INSERT OR IGNORE INTO schema_migrations (version, applied_at) VALUES (?, datetime('now'))", 1); err != nil {
-			return fmt.Errorf("stamp pre-migration db: %w", err)
+			return false, fmt.Errorf("stamp pre-migration db: %w", err)
 		}
 	}
+	return preMigration, nil
+}
 
+func migrateTo(db *sql.DB, target int, dryRun bool) ([]MigrationStep, error) {
 	current, err := currentVersion(db)
 	if err != nil {
-		return fmt.Errorf("get current version: %w", err)
+		return nil, fmt.Errorf("get current version: %w", err)
 	}
 
-	sorted := make([]Migration, len(migrations))
-	copy(sorted, migrations)
-	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
-
-	for _, m := range sorted {
-		if m.Version <= current {
-			continue
+	sorted := sortedMigrations(dialectOf(db))
+	var plan []Migration
+	direction := MigrationUp
+	if target < current {
+		direction = MigrationDown
+		for i := len(sorted) - 1; i >= 0; i-- {
+			m := sorted[i]
+			if m.Version <= target || m.Version > current {
+				continue
+			}
+			if m.Down == "" {
+				return nil, fmt.Errorf("migration %d (%s) cannot be rolled back", m.Version, m.Description)
+			}
+			plan = append(plan, m)
+		}
+	} else {
+		for _, m := range sorted {
+			if m.Version > current && m.Version <= target {
+				plan = append(plan, m)
+			}
 		}
+	}
 
-		tx, err := db.Begin()
-		if err != nil {
-			return fmt.Errorf("begin migration %d: %w", m.Version, err)
+	steps := make([]MigrationStep, 0, len(plan))
+	for _, m := range plan {
+		if !dryRun {
+			if err := applyMigration(db, m, direction); err != nil {
+				return steps, err
+			}
 		}
+		steps = append(steps, MigrationStep{Version: m.Version, Description: m.Description, Direction: direction})
+	}
+	return steps, nil
+}
+
+// applyMigration runs one migration step and records it in schema_migrations
+// within a single transaction.
+func applyMigration(db *sql.DB, m Migration, direction string) error {
+	script, record := m.SQL, "INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)"
+	args := []any{m.Version, time.Now().UTC().Format(time.DateTime)}
+	verb := "apply"
+	if direction == MigrationDown {
+		script, record = m.Down, "DELETE FROM schema_migrations WHERE version = ?"
+		args = args[:1]
+		verb = "revert"
+	}
 
-		if _, err := tx.Exec(m.SQL); err != nil {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin migration %d: %w", m.Version, err)
+	}
+
+	// Servers sharing a database may migrate it at the same time; once the
+	// lock is held, a step another server already took is skipped.
+	if lock := dialectOf(db).migrationLock(); lock != "" {
+		if _, err := tx.Exec(lock); err != nil {
 			_ = tx.Rollback()
-			return fmt.Errorf("apply migration %d (%s): %w", m.Version, m.Description, err)
+			return fmt.Errorf("lock migration %d: %w", m.Version, err)
 		}
-
-		if _, err := tx.Exec("INSERT INTO schema_migrations (version, applied_at) VALUES (?, datetime('now'))", m.Version); err != nil {
+		var applied int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE version = ?", m.Version).Scan(&applied); err != nil {
 			_ = tx.Rollback()
-			return fmt.Errorf("record migration %d: %w", m.Version, err)
+			return fmt.Errorf("check migration %d: %w", m.Version, err)
 		}
-
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit migration %d: %w", m.Version, err)
+		if (applied > 0) == (direction == MigrationUp) {
+			return tx.Rollback()
 		}
 	}
 
+	if _, err := tx.Exec(script); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("%s migration %d (%s): %w", verb, m.Version, m.Description, err)
+	}
+
+	if _, err := tx.Exec(record, args...); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("record migration %d: %w", m.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %d: %w", m.Version, err)
+	}
 	return nil
 }
 
+// dialectOf reports the dialect of a database opened by this package.
+func dialectOf(db *sql.DB) dialect {
+	if _, ok := db.Driver().(*stdlib.Driver); ok {
+		return postgresDialect{}
+	}
+	return sqliteDialect{}
+}
+
+func sortedMigrations(d dialect) []Migration {
+	sorted := make([]Migration, len(d.migrations()))
+	copy(sorted, d.migrations())
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return sorted
+}
+
+func latestMigrationVersion(d dialect) int {
+	latest := 0
+	for _, m := range d.migrations() {
+		latest = max(latest, m.Version)
+	}
+	return latest
+}
+
 // MigrationPlan returns the current migration status without applying anything.
 func MigrationPlan(db *sql.DB) (*MigrationStatus, error) {
 	// Detect pre-migration databases BEFORE creating the migrations table.
@@ -420,14 +1176,8 @@ func MigrationPlan(db *sql.DB) (*MigrationStatus, error) {
 		effective = 1
 	}
 
-	sorted := make([]Migration, len(migrations))
-	copy(sorted, migrations)
-	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
-
-	available := 0
-	if len(sorted) > 0 {
-		available = sorted[len(sorted)-1].Version
-	}
+	sorted := sortedMigrations(dialectOf(db))
+	available := latestMigrationVersion(dialectOf(db))
 
 	var pending []MigrationInfo
 	for _, m := range sorted {
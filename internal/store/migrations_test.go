@@ -31,8 +31,8 @@ func TestRunMigrationsFreshDB(t *testing.T) {
 	if err != nil {
 		t.Fatalf("current version: %v", err)
 	}
-	if version != 8 {
-		t.Fatalf("expected version 8, got %d", version)
+	if latest := latestMigrationVersion(sqliteDialect{}); version != latest {
+		t.Fatalf("expected version %d, got %d", latest, version)
 	}
 
 	// Verify tasks table exists.
@@ -59,8 +59,8 @@ func TestRunMigrationsIdempotent(t *testing.T) {
 	if err != nil {
 		t.Fatalf("current version: %v", err)
 	}
-	if version != 8 {
-		t.Fatalf("expected version 8, got %d", version)
+	if latest := latestMigrationVersion(sqliteDialect{}); version != latest {
+		t.Fatalf("expected version %d, got %d", latest, version)
 	}
 }
 
@@ -85,6 +85,12 @@ func TestDetectPreMigrationDB(t *testing.T) {
 	)`); err != nil {
 		t.Fatalf("create tasks: %v", err)
 	}
+	if _, err := db.Exec(`
+		CREATE TABLE task_labels (task_id TEXT NOT NULL, label TEXT NOT NULL, UNIQUE(task_id, label));
+		CREATE TABLE task_deps (child_id TEXT NOT NULL, parent_id TEXT NOT NULL, type TEXT NOT NULL, UNIQUE(child_id, parent_id, type));
+	`); err != nil {
+		t.Fatalf("create labels and deps: %v", err)
+	}
 
 	pre, err = detectPreMigrationDB(db)
 	if err != nil {
@@ -111,8 +117,8 @@ func TestDetectPreMigrationDB(t *testing.T) {
 	if err != nil {
 		t.Fatalf("current version: %v", err)
 	}
-	if version != 8 {
-		t.Fatalf("expected version 8, got %d", version)
+	if latest := latestMigrationVersion(sqliteDialect{}); version != latest {
+		t.Fatalf("expected version %d, got %d", latest, version)
 	}
 }
 
@@ -126,11 +132,12 @@ func TestMigrationPlan(t *testing.T) {
 	if plan.CurrentVersion != 0 {
 		t.Fatalf("expected current 0, got %d", plan.CurrentVersion)
 	}
-	if plan.AvailableVersion != 8 {
-		t.Fatalf("expected available 8, got %d", plan.AvailableVersion)
+	latest := latestMigrationVersion(sqliteDialect{})
+	if plan.AvailableVersion != latest {
+		t.Fatalf("expected available %d, got %d", latest, plan.AvailableVersion)
 	}
-	if len(plan.Pending) != 8 {
-		t.Fatalf("expected 8 pending, got %d", len(plan.Pending))
+	if len(plan.Pending) != len(migrations) {
+		t.Fatalf("expected %d pending, got %d", len(migrations), len(plan.Pending))
 	}
 }
 
@@ -146,8 +153,8 @@ func TestMigration002UpgradePath(t *testing.T) {
 	if err != nil {
 		t.Fatalf("current version: %v", err)
 	}
-	if version != 8 {
-		t.Fatalf("expected version 8, got %d", version)
+	if latest := latestMigrationVersion(sqliteDialect{}); version != latest {
+		t.Fatalf("expected version %d, got %d", latest, version)
 	}
 
 	// Verify new columns exist by inserting a row that uses them.
@@ -530,3 +537,119 @@ func TestMigration008AuthUsersAndSessions(t *testing.T) {
 func containsPlan(plan, needle string) bool {
 	return strings.Contains(plan, needle)
 }
+
+func TestMigrateToRollbackRoundTrip(t *testing.T) {
+	schemaAt := func(db *sql.DB) map[string]string {
+		t.Helper()
+		rows, err := db.Query(`SELECT type, name FROM sqlite_master
+			WHERE name NOT LIKE 'sqlite_%' AND name NOT LIKE 'tasks_fts_%' AND name != 'schema_migrations'`)
+		if err != nil {
+			t.Fatalf("read schema: %v", err)
+		}
+		defer rows.Close()
+		schema := map[string]string{}
+		for rows.Next() {
+			var typ, name string
+			if err := rows.Scan(&typ, &name); err != nil {
+				t.Fatalf("scan schema: %v", err)
+			}
+			schema[name] = typ
+		}
+		for _, table := range []string{"tasks", "tasks_fts"} {
+			if _, ok := schema[table]; !ok {
+				continue
+			}
+			var columns []string
+			colRows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+			if err != nil {
+				t.Fatalf("read columns: %v", err)
+			}
+			for colRows.Next() {
+				var name string
+				if err := colRows.Scan(&name); err != nil {
+					t.Fatalf("scan column: %v", err)
+				}
+				columns = append(columns, name)
+			}
+			colRows.Close()
+			schema[table] = strings.Join(columns, ",")
+		}
+		return schema
+	}
+
+	db := testRawDB(t)
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO tasks (id, title, status, type, priority, notes, project_id, created_at, updated_at)
+		VALUES ('gr-a1b2', 'keep me', 'open', 'task', 2, 'rollback note', 'gr', '2026-01-01T00:00:00Z', '2026-01-01T00:00:00Z')`); err != nil {
+		t.Fatalf("insert task: %v", err)
+	}
+
+	latest := latestMigrationVersion(sqliteDialect{})
+	for target := latest - 1; target >= 1; target-- {
+		steps, err := MigrateTo(db, target, false)
+		if err != nil {
+			t.Fatalf("roll back to %d: %v", target, err)
+		}
+		if len(steps) != 1 || steps[0].Version != target+1 || steps[0].Direction != MigrationDown {
+			t.Fatalf("unexpected steps rolling back to %d: %+v", target, steps)
+		}
+
+		fresh := testRawDB(t)
+		if _, err := MigrateTo(fresh, target, false); err != nil {
+			t.Fatalf("fresh migrate to %d: %v", target, err)
+		}
+		got, want := schemaAt(db), schemaAt(fresh)
+		if len(got) != len(want) {
+			t.Fatalf("version %d: schema mismatch\n got: %v\nwant: %v", target, got, want)
+		}
+		for name, def := range want {
+			if got[name] != def {
+				t.Fatalf("version %d: %s = %q, want %q", target, name, got[name], def)
+			}
+		}
+	}
+
+	var title string
+	if err := db.QueryRow("SELECT title FROM tasks WHERE id = 'gr-a1b2'").Scan(&title); err != nil || title != "keep me" {
+		t.Fatalf("task lost during rollback: %q, %v", title, err)
+	}
+
+	steps, err := MigrateTo(db, latest, false)
+	if err != nil {
+		t.Fatalf("re-apply: %v", err)
+	}
+	if len(steps) != latest-1 || steps[0].Direction != MigrationUp {
+		t.Fatalf("unexpected re-apply steps: %+v", steps)
+	}
+	var hits int
+	if err := db.QueryRow("SELECT COUNT(*) FROM tasks_fts WHERE tasks_fts MATCH 'keep'").Scan(&hits); err != nil || hits != 1 {
+		t.Fatalf("expected search index rebuilt after re-apply, got %d (%v)", hits, err)
+	}
+}
+
+func TestMigrateToDryRunAndLimits(t *testing.T) {
+	db := testRawDB(t)
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	latest := latestMigrationVersion(sqliteDialect{})
+	steps, err := MigrateTo(db, latest-2, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(steps) != 2 || steps[0].Version != latest || steps[1].Version != latest-1 {
+		t.Fatalf("expected newest-first rollback plan, got %+v", steps)
+	}
+	if version, _ := currentVersion(db); version != latest {
+		t.Fatalf("dry run changed version to %d", version)
+	}
+
+	for _, target := range []int{0, latest + 1} {
+		if _, err := MigrateTo(db, target, true); err == nil {
+			t.Fatalf("expected error for target %d", target)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"grns/internal/models"
+)
+
+const milestoneColumns = "id, project_id, title, due_date, state, created_at, updated_at"
+
+// MilestoneUpdate describes milestone fields to update. An empty DueDate clears it.
+type MilestoneUpdate struct {
+	Title     *string
+	DueDate   *string
+	State     *string
+	UpdatedAt time.Time
+}
+
+// CreateMilestone inserts one milestone row.
+func (s *Store) CreateMilestone(ctx context.Context, milestone *models.Milestone) error {
+	if milestone == nil {
+		return fmt.Errorf("milestone is required")
+	}
+	now := time.Now().UTC()
+	if milestone.CreatedAt.IsZero() {
+		milestone.CreatedAt = now
+	}
+	if milestone.UpdatedAt.IsZero() {
+		milestone.UpdatedAt = milestone.CreatedAt
+	}
+	if milestone.State == "" {
+		milestone.State = string(models.MilestoneOpen)
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO milestones (id, project_id, title, due_date, state, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`,
+		milestone.ID,
+		normalizeProject(milestone.Project),
+		milestone.Title,
+		nullIfEmpty(milestone.DueDate),
+		milestone.State,
+		dbFormatTime(milestone.CreatedAt),
+		dbFormatTime(milestone.UpdatedAt),
+	)
+	return err
+}
+
+// GetMilestone returns one milestone in a project, or nil when it does not exist.
+func (s *Store) GetMilestone(ctx context.Context, project, id string) (*models.Milestone, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+milestoneColumns+` FROM milestones WHERE id = ? AND project_id = ?`, id, normalizeProject(project))
+	milestone, err := scanMilestone(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+// ListMilestones lists a project's milestones by due date (undated last), then id.
+// An empty state lists milestones in every state.
+func (s *Store) ListMilestones(ctx context.Context, project, state string) ([]models.Milestone, error) {
+	query := `SELECT ` + milestoneColumns + ` FROM milestones WHERE project_id = ?`
+	args := []any{normalizeProject(project)}
+	if state != "" {
+		query += " AND state = ?"
+		args = append(args, state)
+	}
+	query += " ORDER BY due_date IS NULL, due_date, id"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	milestones := []models.Milestone{}
+	for rows.Next() {
+		milestone, err := scanMilestone(rows)
+		if err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, milestone)
+	}
+	return milestones, rows.Err()
+}
+
+// UpdateMilestone updates mutable milestone fields and reports whether the milestone exists.
+func (s *Store) UpdateMilestone(ctx context.Context, project, id string, update MilestoneUpdate) (bool, error) {
+	set := []string{}
+	args := []any{}
+	if update.Title != nil {
+		set = append(set, "title = ?")
+		args = append(args, *update.Title)
+	}
+	if update.DueDate != nil {
+		set = append(set, "due_date = ?")
+		args = append(args, nullIfEmpty(*update.DueDate))
+	}
+	if update.State != nil {
+		set = append(set, "state = ?")
+		args = append(args, *update.State)
+	}
+	if update.UpdatedAt.IsZero() {
+		update.UpdatedAt = time.Now().UTC()
+	}
+	set = append(set, "updated_at = ?")
+	args = append(args, dbFormatTime(update.UpdatedAt))
+	args = append(args, id, normalizeProject(project))
+
+	result, err := s.db.ExecContext(ctx, "UPDATE milestones SET "+strings.Join(set, ", ")+" WHERE id = ? AND project_id = ?", args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// DeleteMilestone deletes one milestone and detaches its tasks in one transaction.
+// It reports whether the milestone existed.
+func (s *Store) DeleteMilestone(ctx context.Context, project, id string) (found bool, err error) {
+	project = normalizeProject(project)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM milestones WHERE id = ? AND project_id = ?", id, project)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		_ = tx.Rollback()
+		return false, nil
+	}
+	if _, err = tx.ExecContext(ctx, "UPDATE tasks SET milestone_id = NULL WHERE milestone_id = ? AND project_id = ?", id, project); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// MilestoneIDExists reports whether a milestone id is already taken.
+func (s *Store) MilestoneIDExists(ctx context.Context, id string) (bool, error) {
+	var exists int
+	err := s.db.QueryRowContext(ctx, "SELECT 1 FROM milestones WHERE id = ?", id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// MilestoneStatusCounts counts a milestone's tasks by status.
+func (s *Store) MilestoneStatusCounts(ctx context.Context, project, id string) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT status, COUNT(*) FROM tasks
+		WHERE milestone_id = ? AND project_id = ?
+		GROUP BY status
+	`, id, normalizeProject(project))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+func scanMilestone(scanner interface {
+	Scan(dest ...any) error
+}) (models.Milestone, error) {
+	milestone := models.Milestone{}
+	var dueDate sql.NullString
+	var createdAt, updatedAt string
+	if err := scanner.Scan(&milestone.ID, &milestone.Project, &milestone.Title, &dueDate, &milestone.State, &createdAt, &updatedAt); err != nil {
+		return models.Milestone{}, err
+	}
+	milestone.DueDate = dueDate.String
+	parsedCreated, err := dbParseTime(createdAt)
+	if err != nil {
+		return models.Milestone{}, err
+	}
+	parsedUpdated, err := dbParseTime(updatedAt)
+	if err != nil {
+		return models.Milestone{}, err
+	}
+	milestone.CreatedAt = parsedCreated
+	milestone.UpdatedAt = parsedUpdated
+	return milestone, nil
+}
@@ -0,0 +1,20 @@
+package store
+
+import (
+	"context"
+
+	"grns/internal/models"
+)
+
+// MilestoneStore is the persistence surface for project milestones.
+type MilestoneStore interface {
+	CreateMilestone(ctx context.Context, milestone *models.Milestone) error
+	GetMilestone(ctx context.Context, project, id string) (*models.Milestone, error)
+	ListMilestones(ctx context.Context, project, state string) ([]models.Milestone, error)
+	UpdateMilestone(ctx context.Context, project, id string, update MilestoneUpdate) (bool, error)
+	DeleteMilestone(ctx context.Context, project, id string) (bool, error)
+	MilestoneIDExists(ctx context.Context, id string) (bool, error)
+	MilestoneStatusCounts(ctx context.Context, project, id string) (map[string]int, error)
+}
+
+var _ MilestoneStore = (*Store)(nil)
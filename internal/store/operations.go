@@ -0,0 +1,327 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"grns/internal/models"
+)
+
+const operationColumns = "id, project_id, kind, actor, payload_json, created_at, undone_at"
+
+// operationPayload is the JSON stored in operations.payload_json.
+type operationPayload struct {
+	TaskIDs   []string      `json:"task_ids"`
+	Label     string        `json:"label,omitempty"`
+	RenamedTo string        `json:"renamed_to,omitempty"`
+	Added     []string      `json:"added,omitempty"`
+	Before    []models.Task `json:"before,omitempty"`
+}
+
+// AppendOperation inserts one journal entry and sets its ID.
+func (s *Store) AppendOperation(ctx context.Context, op *models.Operation) error {
+	if op == nil {
+		return fmt.Errorf("operation is required")
+	}
+	if strings.TrimSpace(string(op.Kind)) == "" {
+		return fmt.Errorf("operation kind is required")
+	}
+	if op.CreatedAt.IsZero() {
+		op.CreatedAt = time.Now().UTC()
+	}
+	payload, err := json.Marshal(operationPayload{TaskIDs: op.TaskIDs, Label: op.Label, RenamedTo: op.RenamedTo, Added: op.Added, Before: op.Before})
+	if err != nil {
+		return fmt.Errorf("marshal operation payload_json: %w", err)
+	}
+
+	return s.db.QueryRowContext(ctx, `
+		INSERT INTO operations (project_id, kind, actor, payload_json, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`, normalizeProject(op.Project), string(op.Kind), nullIfEmpty(strings.TrimSpace(op.Actor)), string(payload), dbFormatTime(op.CreatedAt)).Scan(&op.ID)
+}
+
+// GetOperation returns one journal entry, or nil when it does not exist in project.
+func (s *Store) GetOperation(ctx context.Context, project string, id int64) (*models.Operation, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+operationColumns+` FROM operations WHERE project_id = ? AND id = ?`, normalizeProject(project), id)
+	op, err := scanOperation(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// ListOperations lists a project's journal entries created at or after since, newest first.
+func (s *Store) ListOperations(ctx context.Context, project string, since time.Time, limit int) ([]models.Operation, error) {
+	query := `SELECT ` + operationColumns + ` FROM operations WHERE project_id = ? ORDER BY id DESC`
+	args := []any{normalizeProject(project)}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ops := []models.Operation{}
+	for rows.Next() {
+		op, err := scanOperation(rows)
+		if err != nil {
+			return nil, err
+		}
+		if op.CreatedAt.Before(since) {
+			break
+		}
+		ops = append(ops, op)
+	}
+	return ops, rows.Err()
+}
+
+// UndoOperation restores the journaled state of op and marks it undone in one
+// transaction. Task snapshots are written back in full; a label_delete entry
+// re-adds its label to the tasks that still exist, and a label_rename entry
+// re-adds the old label and drops the new one where the rename added it. It returns
+// ErrOperationConflict without changing anything if a snapshotted task was
+// updated after the operation, and ErrOperationUndone if op was already undone.
+// events are recorded in the same transaction.
+func (s *Store) UndoOperation(ctx context.Context, op *models.Operation, undoneAt time.Time, events []models.TaskEvent) (err error) {
+	if op == nil {
+		return fmt.Errorf("operation is required")
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx, "UPDATE operations SET undone_at = ? WHERE id = ? AND undone_at IS NULL", dbFormatTime(undoneAt), op.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrOperationUndone
+	}
+
+	if op.Kind == models.OperationLabelDelete {
+		if err = restoreLabelTx(ctx, tx, op.Project, op.Label, op.TaskIDs); err != nil {
+			return err
+		}
+		if err = appendTaskEventsTx(ctx, tx, events); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+	if op.Kind == models.OperationLabelRename {
+		if err = restoreLabelTx(ctx, tx, op.Project, op.Label, op.TaskIDs); err != nil {
+			return err
+		}
+		if err = removeLabelTx(ctx, tx, op.Project, op.RenamedTo, op.Added); err != nil {
+			return err
+		}
+		if err = appendTaskEventsTx(ctx, tx, events); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	for _, task := range op.Before {
+		if err = checkUnchangedSinceTx(ctx, tx, task.ID, op.CreatedAt); err != nil {
+			return err
+		}
+		if err = updateTaskExec(ctx, tx, task.ID, restoreTaskUpdate(task, undoneAt)); err != nil {
+			return err
+		}
+	}
+	if err = appendTaskEventsTx(ctx, tx, events); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PruneOperations deletes journal entries created before the cutoff and returns how many were removed.
+func (s *Store) PruneOperations(ctx context.Context, before time.Time) (int, error) {
+	return s.pruneCreatedBefore(ctx, "operations", before)
+}
+
+// pruneCreatedBefore deletes rows of table whose created_at is before the
+// cutoff. Stored times have variable precision, so they are compared parsed.
+func (s *Store) pruneCreatedBefore(ctx context.Context, table string, before time.Time) (int, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, created_at FROM "+table)
+	if err != nil {
+		return 0, err
+	}
+	var expired []any
+	for rows.Next() {
+		var id int64
+		var createdAt string
+		if err := rows.Scan(&id, &createdAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		parsed, err := dbParseTime(createdAt)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if parsed.Before(before) {
+			expired = append(expired, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	result, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", table, placeholders(len(expired))), expired...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
+
+func checkUnchangedSinceTx(ctx context.Context, tx *sql.Tx, id string, since time.Time) error {
+	var updatedAt string
+	err := tx.QueryRowContext(ctx, "SELECT updated_at FROM tasks WHERE id = ?", id).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		return ErrTaskNotFound
+	}
+	if err != nil {
+		return err
+	}
+	parsed, err := dbParseTime(updatedAt)
+	if err != nil {
+		return err
+	}
+	if parsed.After(since) {
+		return ErrOperationConflict
+	}
+	return nil
+}
+
+func restoreLabelTx(ctx context.Context, tx *sql.Tx, project, label string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := []any{label, normalizeProject(project)}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT OR IGNORE INTO task_labels (task_id, label)
+		SELECT id, ? FROM tasks WHERE project_id = ? AND id IN (%s)`, placeholders(len(ids))), args...)
+	return err
+}
+
+func removeLabelTx(ctx context.Context, tx *sql.Tx, project, label string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := []any{label, normalizeProject(project)}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		DELETE FROM task_labels WHERE label = ? AND task_id IN (
+			SELECT id FROM tasks WHERE project_id = ? AND id IN (%s))`, placeholders(len(ids))), args...)
+	return err
+}
+
+// restoreTaskUpdate sets every mutable column back to the snapshot. Zero
+// times clear closed_at and deleted_at.
+func restoreTaskUpdate(task models.Task, updatedAt time.Time) TaskUpdate {
+	closedAt, deletedAt := time.Time{}, time.Time{}
+	if task.ClosedAt != nil {
+		closedAt = *task.ClosedAt
+	}
+	if task.DeletedAt != nil {
+		deletedAt = *task.DeletedAt
+	}
+	custom := task.Custom
+	return TaskUpdate{
+		Title:              &task.Title,
+		Status:             &task.Status,
+		Type:               &task.Type,
+		Priority:           &task.Priority,
+		Description:        &task.Description,
+		SpecID:             &task.SpecID,
+		ParentID:           &task.ParentID,
+		Assignee:           &task.Assignee,
+		Notes:              &task.Notes,
+		Design:             &task.Design,
+		AcceptanceCriteria: &task.AcceptanceCriteria,
+		SourceRepo:         &task.SourceRepo,
+		MilestoneID:        &task.MilestoneID,
+		ClosedAt:           &closedAt,
+		DeletedAt:          &deletedAt,
+		MergedInto:         &task.MergedInto,
+		BlockedReason:      &task.BlockedReason,
+		BlockedOn:          &task.BlockedOn,
+		Custom:             &custom,
+		UpdatedAt:          updatedAt,
+	}
+}
+
+func scanOperation(scanner interface {
+	Scan(dest ...any) error
+}) (models.Operation, error) {
+	op := models.Operation{}
+	var kind, payloadJSON, createdAt string
+	var actor, undoneAt sql.NullString
+	if err := scanner.Scan(&op.ID, &op.Project, &kind, &actor, &payloadJSON, &createdAt, &undoneAt); err != nil {
+		return models.Operation{}, err
+	}
+	op.Kind = models.OperationKind(kind)
+	op.Actor = actor.String
+
+	var payload operationPayload
+	if err := json.Unmarshal([]byte(payloadJSON), &payload); err != nil {
+		return models.Operation{}, fmt.Errorf("decode operation payload_json: %w", err)
+	}
+	op.TaskIDs = payload.TaskIDs
+	if op.TaskIDs == nil {
+		op.TaskIDs = []string{}
+	}
+	op.Label = payload.Label
+	op.RenamedTo = payload.RenamedTo
+	op.Added = payload.Added
+	op.Before = payload.Before
+
+	parsedCreated, err := dbParseTime(createdAt)
+	if err != nil {
+		return models.Operation{}, err
+	}
+	op.CreatedAt = parsedCreated
+	if undoneAt.Valid {
+		parsedUndone, err := dbParseTime(undoneAt.String)
+		if err != nil {
+			return models.Operation{}, err
+		}
+		op.UndoneAt = &parsedUndone
+	}
+	return op, nil
+}
@@ -0,0 +1,27 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"grns/internal/models"
+)
+
+// ErrOperationUndone is returned when an operation has already been undone.
+var ErrOperationUndone = errors.New("operation already undone")
+
+// ErrOperationConflict is returned when a task changed after the operation
+// being undone, so restoring its snapshot would discard newer edits.
+var ErrOperationConflict = errors.New("task changed after operation")
+
+// OperationStore persists the undo journal of recent destructive operations.
+type OperationStore interface {
+	AppendOperation(ctx context.Context, op *models.Operation) error
+	GetOperation(ctx context.Context, project string, id int64) (*models.Operation, error)
+	ListOperations(ctx context.Context, project string, since time.Time, limit int) ([]models.Operation, error)
+	UndoOperation(ctx context.Context, op *models.Operation, undoneAt time.Time, events []models.TaskEvent) error
+	PruneOperations(ctx context.Context, before time.Time) (int, error)
+}
+
+var _ OperationStore = (*Store)(nil)
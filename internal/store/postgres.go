@@ -0,0 +1,345 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	postgresMaxOpenConns = 16
+	// postgresMigrationLockKey names the advisory lock taken by migrations
+	// ("grns" in ASCII). The task_changes triggers in postgresMigrations take
+	// the same lock to allocate change sequence numbers in commit order.
+	postgresMigrationLockKey = 0x67726e73
+)
+
+// ErrUnsupported is returned by operations the database backend cannot
+// perform, such as file backups and VACUUM on PostgreSQL.
+var ErrUnsupported = errors.New("not supported by this database backend")
+
+// IsPostgresURL reports whether dsn selects the PostgreSQL backend.
+func IsPostgresURL(dsn string) bool {
+	u, err := url.Parse(strings.TrimSpace(dsn))
+	return err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql")
+}
+
+// OpenPostgres opens the PostgreSQL database at dsn, a postgres:// URL, and
+// bootstraps the schema. BusyTimeoutMS and JournalMode do not apply.
+func OpenPostgres(dsn string, opts Options) (*Store, error) {
+	s := &Store{dialect: postgresDialect{}}
+	db, err := openPostgresDB(dsn, s)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(intFromEnv(maxOpenConnsEnvKey, postgresMaxOpenConns))
+	db.SetMaxIdleConns(intFromEnv(maxIdleConnsEnvKey, maxIdleConns))
+	db.SetConnMaxLifetime(durationFromEnv(connMaxLifetimeEnvKey, connMaxLifetime))
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s.init(db, opts)
+}
+
+// OpenPostgresDB opens dsn without migrating it, for inspecting and moving
+// the schema with MigrationPlan and MigrateTo.
+func OpenPostgresDB(dsn string) (*sql.DB, error) {
+	return openPostgresDB(dsn, &Store{dialect: postgresDialect{}})
+}
+
+func openPostgresDB(dsn string, s *Store) (*sql.DB, error) {
+	connString, err := postgresConnString(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(&observedConnector{dsn: connString, driver: stdlib.GetDefaultDriver(), store: s}), nil
+}
+
+// postgresConnString selects pgx's simple protocol: arguments are bound
+// client side, so loosely typed SQLite-style arguments such as an integer
+// compared with a text column need no server-side type inference.
+func postgresConnString(dsn string) (string, error) {
+	if !IsPostgresURL(dsn) {
+		return "", fmt.Errorf("db url must be a postgres:// or postgresql:// URL")
+	}
+	u, err := url.Parse(strings.TrimSpace(dsn))
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("default_query_exec_mode", "simple_protocol")
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) name() string { return dialectPostgres }
+
+func (postgresDialect) conn(c *observedConn) driver.Conn { return &postgresConn{observedConn: c} }
+
+func (postgresDialect) migrations() []Migration { return postgresMigrations }
+
+func (postgresDialect) searchJoin() string {
+	return "JOIN tasks_fts ON tasks.id = tasks_fts.task_id AND tasks_fts.document @@ websearch_to_tsquery('simple', ?)"
+}
+
+func (postgresDialect) searchOrder() (string, bool) {
+	return "ts_rank(tasks_fts.document, websearch_to_tsquery('simple', ?)) DESC", true
+}
+
+// searchHitsQuery negates ts_rank so that, as with FTS5, lower ranks are
+// better matches.
+func (postgresDialect) searchHitsQuery(n int) string {
+	return fmt.Sprintf(`
+		WITH q AS (SELECT websearch_to_tsquery('simple', ?) AS query)
+		SELECT f.task_id,
+			ts_headline('simple', concat_ws(' ', f.title, f.description, f.notes, f.design, f.acceptance_criteria, f.assignee, f.custom), q.query,
+				'StartSel=[, StopSel=], MaxWords=12, MinWords=4, MaxFragments=1, FragmentDelimiter=…'),
+			-ts_rank(f.document, q.query)
+		FROM tasks_fts f, q
+		WHERE f.document @@ q.query AND f.task_id IN (%s)
+	`, placeholders(n))
+}
+
+func (postgresDialect) customSearchText(column string) string {
+	return "json_search_text(" + column + ")"
+}
+
+func (postgresDialect) customFieldEquals(key, value string) (string, []any) {
+	return "jsonb_extract_path_text(CAST(NULLIF(tasks.custom, '') AS jsonb), ?) = ?", []any{key, value}
+}
+
+func (postgresDialect) customFieldExists(key string) (string, []any) {
+	return "COALESCE(jsonb_typeof(jsonb_extract_path(CAST(NULLIF(tasks.custom, '') AS jsonb), ?)), 'null') != 'null'", []any{key}
+}
+
+func (postgresDialect) containsText(column string) string {
+	return column + " ILIKE '%' || ? || '%'"
+}
+
+// matchesRegexp uses POSIX regular expressions, which accept the leading
+// (?i) that spec filters add and most RE2 syntax.
+func (postgresDialect) matchesRegexp(column string) string {
+	return column + " ~ ?"
+}
+
+func (postgresDialect) clamp(expr, lo, hi string) string {
+	return fmt.Sprintf("LEAST(GREATEST(%s, %s), %s)", expr, lo, hi)
+}
+
+func (postgresDialect) truncate(expr string) string {
+	return "CAST(trunc(" + expr + ") AS INTEGER)"
+}
+
+func (postgresDialect) jsonSetTrue(column, key string) (string, []any) {
+	return "CAST(jsonb_set(CAST(COALESCE(" + column + ", '{}') AS jsonb), ARRAY[CAST(? AS TEXT)], 'true') AS TEXT)", []any{key}
+}
+
+func (postgresDialect) quoteIdent(name string) string { return `"` + name + `"` }
+
+func (postgresDialect) forUpdate(skipLocked bool) string {
+	if skipLocked {
+		return " FOR UPDATE SKIP LOCKED"
+	}
+	return " FOR UPDATE"
+}
+
+func (postgresDialect) migrationLock() string {
+	return "SELECT pg_advisory_xact_lock(" + strconv.Itoa(postgresMigrationLockKey) + ")"
+}
+
+func (postgresDialect) noLimit() string { return "LIMIT ALL" }
+
+// postgresConn rewrites the store's SQL and arguments for PostgreSQL.
+// Transactions run concurrently; checks that SQLite's single writer used to
+// keep consistent, such as WIP limits and claims, lock the rows they depend
+// on with dialect.forUpdate.
+type postgresConn struct {
+	*observedConn
+}
+
+func (c *postgresConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.observedConn.ExecContext(ctx, rebindPostgres(query), postgresArgs(args))
+}
+
+func (c *postgresConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.observedConn.QueryContext(ctx, rebindPostgres(query), postgresArgs(args))
+}
+
+// PrepareContext defers to exec and query at run time: with the simple
+// protocol there is nothing to prepare on the server.
+func (c *postgresConn) PrepareContext(_ context.Context, query string) (driver.Stmt, error) {
+	return &observedStmt{Stmt: &postgresStmt{conn: c.Conn, query: rebindPostgres(query)}, store: c.store}, nil
+}
+
+// postgresStmt runs an already rewritten query on the driver connection.
+type postgresStmt struct {
+	conn  driver.Conn
+	query string
+}
+
+func (s *postgresStmt) Close() error { return nil }
+
+func (s *postgresStmt) NumInput() int { return -1 }
+
+func (s *postgresStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("postgres statement requires ExecContext")
+}
+
+func (s *postgresStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("postgres statement requires QueryContext")
+}
+
+func (s *postgresStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := s.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return execer.ExecContext(ctx, s.query, postgresArgs(args))
+}
+
+func (s *postgresStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := s.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return queryer.QueryContext(ctx, s.query, postgresArgs(args))
+}
+
+// postgresArgs stores booleans as 0 and 1, as SQLite does, since flag
+// columns are integers in both schemas.
+func postgresArgs(args []driver.NamedValue) []driver.NamedValue {
+	var out []driver.NamedValue
+	for i, arg := range args {
+		b, ok := arg.Value.(bool)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = append([]driver.NamedValue(nil), args...)
+		}
+		out[i].Value = int64(0)
+		if b {
+			out[i].Value = int64(1)
+		}
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
+
+var insertOrIgnorePattern = regexp.MustCompile(`(?i)\bINSERT\s+OR\s+IGNORE\b`)
+
+// rebindPostgres rewrites SQLite syntax outside literals and comments: ?
+// placeholders become $1, $2, ...; and INSERT OR IGNORE becomes INSERT ...
+// ON CONFLICT DO NOTHING.
+func rebindPostgres(query string) string {
+	var (
+		out    strings.Builder
+		n      int
+		ignore bool
+	)
+	out.Grow(len(query) + 16)
+	rewrite := func(code string) {
+		if insertOrIgnorePattern.MatchString(code) {
+			code = insertOrIgnorePattern.ReplaceAllString(code, "INSERT")
+			ignore = true
+		}
+		for {
+			i := strings.IndexByte(code, '?')
+			if i < 0 {
+				break
+			}
+			n++
+			out.WriteString(code[:i])
+			out.WriteString("$" + strconv.Itoa(n))
+			code = code[i+1:]
+		}
+		out.WriteString(code)
+	}
+
+	start := 0
+	for i := 0; i < len(query); {
+		end := sqlLiteralEnd(query, i)
+		if end == i {
+			i++
+			continue
+		}
+		rewrite(query[start:i])
+		out.WriteString(query[i:end])
+		start, i = end, end
+	}
+	rewrite(query[start:])
+
+	if !ignore {
+		return out.String()
+	}
+	return strings.TrimRight(out.String(), " \t\r\n;") + " ON CONFLICT DO NOTHING"
+}
+
+// sqlLiteralEnd returns the end of the quoted string, quoted identifier,
+// dollar-quoted body, or comment starting at i, or i when none starts there.
+func sqlLiteralEnd(query string, i int) int {
+	switch {
+	case query[i] == '\'' || query[i] == '"':
+		quote := query[i]
+		for j := i + 1; j < len(query); j++ {
+			if query[j] != quote {
+				continue
+			}
+			if j+1 < len(query) && query[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+		return len(query)
+	case strings.HasPrefix(query[i:], "--"):
+		if j := strings.IndexByte(query[i:], '\n'); j >= 0 {
+			return i + j + 1
+		}
+		return len(query)
+	case strings.HasPrefix(query[i:], "/*"):
+		if j := strings.Index(query[i+2:], "*/"); j >= 0 {
+			return i + 2 + j + 2
+		}
+		return len(query)
+	case query[i] == '$':
+		tag := dollarQuoteTag(query[i:])
+		if tag == "" {
+			return i
+		}
+		if j := strings.Index(query[i+len(tag):], tag); j >= 0 {
+			return i + len(tag) + j + len(tag)
+		}
+		return len(query)
+	}
+	return i
+}
+
+// dollarQuoteTag returns the $tag$ opening a dollar-quoted string at the
+// start of s, or "".
+func dollarQuoteTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		switch {
+		case c == '$':
+			return s[:j+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || j > 1 && c >= '0' && c <= '9':
+		default:
+			return ""
+		}
+	}
+	return ""
+}
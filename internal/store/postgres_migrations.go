@@ -0,0 +1,478 @@
+package store
+
+// postgresMigrations is the ordered list of PostgreSQL schema migrations. The
+// schema starts at the SQLite schema's version 30, so that both backends
+// report the same version for the same schema; later changes must be added
+// to both lists under the same version.
+var postgresMigrations = []Migration{
+	{
+		Version:     30,
+		Description: "baseline schema matching SQLite migration 30",
+		SQL: `
+CREATE TABLE tasks (
+  id TEXT PRIMARY KEY,
+  title TEXT NOT NULL,
+  status TEXT NOT NULL,
+  type TEXT NOT NULL,
+  priority INTEGER NOT NULL,
+  description TEXT,
+  spec_id TEXT,
+  parent_id TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  closed_at TEXT,
+  custom TEXT,
+  assignee TEXT,
+  notes TEXT,
+  design TEXT,
+  acceptance_criteria TEXT,
+  source_repo TEXT,
+  project_id TEXT,
+  milestone_id TEXT,
+  deleted_at TEXT,
+  merged_into TEXT,
+  blocked_reason TEXT,
+  blocked_on TEXT
+);
+
+CREATE INDEX idx_tasks_status_updated ON tasks(status, updated_at);
+CREATE INDEX idx_tasks_spec_id ON tasks(spec_id);
+CREATE INDEX idx_tasks_parent_id ON tasks(parent_id);
+CREATE INDEX idx_tasks_assignee ON tasks(assignee);
+CREATE INDEX idx_tasks_source_repo ON tasks(source_repo);
+CREATE INDEX idx_tasks_updated_at_desc ON tasks(updated_at DESC);
+CREATE INDEX idx_tasks_type_updated_desc ON tasks(type, updated_at DESC);
+CREATE INDEX idx_tasks_assignee_updated_desc ON tasks(assignee, updated_at DESC);
+CREATE INDEX idx_tasks_project_id ON tasks(project_id);
+CREATE INDEX idx_tasks_project_updated_desc ON tasks(project_id, updated_at DESC);
+CREATE INDEX idx_tasks_project_status_updated_desc ON tasks(project_id, status, updated_at DESC);
+CREATE INDEX idx_tasks_milestone_id ON tasks(milestone_id);
+CREATE INDEX idx_tasks_status_deleted_at ON tasks(status, deleted_at);
+CREATE INDEX idx_tasks_merged_into ON tasks(merged_into);
+CREATE INDEX idx_tasks_project_assignee_status ON tasks(project_id, assignee, status, updated_at DESC);
+CREATE INDEX idx_tasks_project_parent ON tasks(project_id, parent_id, updated_at DESC);
+CREATE INDEX idx_tasks_project_closed_at ON tasks(project_id, closed_at);
+
+CREATE TABLE task_labels (
+  task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  label TEXT NOT NULL,
+  UNIQUE (task_id, label)
+);
+
+CREATE INDEX idx_task_labels_label ON task_labels(label);
+CREATE INDEX idx_task_labels_label_task ON task_labels(label, task_id);
+
+CREATE TABLE task_deps (
+  child_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  parent_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  type TEXT NOT NULL,
+  UNIQUE (child_id, parent_id, type)
+);
+
+CREATE INDEX idx_task_deps_child ON task_deps(child_id);
+CREATE INDEX idx_task_deps_parent ON task_deps(parent_id);
+
+CREATE TABLE blobs (
+  id TEXT PRIMARY KEY,
+  sha256 TEXT NOT NULL UNIQUE,
+  size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+  storage_backend TEXT NOT NULL,
+  blob_key TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  quarantined_at TEXT
+);
+
+CREATE TABLE attachments (
+  id TEXT PRIMARY KEY,
+  task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL,
+  source_type TEXT NOT NULL,
+  title TEXT,
+  filename TEXT,
+  media_type TEXT,
+  media_type_source TEXT NOT NULL DEFAULT 'unknown',
+  blob_id TEXT REFERENCES blobs(id) ON DELETE RESTRICT,
+  external_url TEXT,
+  repo_path TEXT,
+  meta_json TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  expires_at TEXT,
+  CHECK (kind IN ('spec', 'diagram', 'artifact', 'diagnostic', 'archive', 'other')),
+  CHECK (source_type IN ('managed_blob', 'external_url', 'repo_path')),
+  CHECK (media_type_source IN ('sniffed', 'declared', 'inferred', 'unknown')),
+  CHECK (
+    (source_type = 'managed_blob' AND blob_id IS NOT NULL AND external_url IS NULL AND repo_path IS NULL) OR
+    (source_type = 'external_url' AND blob_id IS NULL AND external_url IS NOT NULL AND repo_path IS NULL) OR
+    (source_type = 'repo_path' AND blob_id IS NULL AND external_url IS NULL AND repo_path IS NOT NULL)
+  ),
+  CHECK (expires_at IS NULL OR expires_at >= created_at)
+);
+
+CREATE INDEX idx_attachments_task_created ON attachments(task_id, created_at);
+CREATE INDEX idx_attachments_kind ON attachments(kind);
+CREATE INDEX idx_attachments_media_type ON attachments(media_type);
+CREATE INDEX idx_attachments_source_type ON attachments(source_type);
+CREATE INDEX idx_attachments_blob_id ON attachments(blob_id);
+CREATE INDEX idx_attachments_expires_at ON attachments(expires_at);
+
+CREATE TABLE attachment_labels (
+  attachment_id TEXT NOT NULL REFERENCES attachments(id) ON DELETE CASCADE,
+  label TEXT NOT NULL,
+  PRIMARY KEY (attachment_id, label)
+);
+
+CREATE INDEX idx_attachment_labels_label ON attachment_labels(label);
+
+CREATE TABLE git_repos (
+  id TEXT PRIMARY KEY,
+  slug TEXT NOT NULL UNIQUE,
+  default_branch TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+CREATE TABLE task_git_refs (
+  id TEXT PRIMARY KEY,
+  task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  repo_id TEXT NOT NULL REFERENCES git_repos(id) ON DELETE RESTRICT,
+  relation TEXT NOT NULL,
+  object_type TEXT NOT NULL,
+  object_value TEXT NOT NULL,
+  resolved_commit TEXT,
+  note TEXT,
+  meta_json TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  CHECK (object_type IN ('commit', 'tag', 'branch', 'path', 'blob', 'tree')),
+  CHECK (length(trim(object_value)) > 0),
+  CHECK (resolved_commit IS NULL OR resolved_commit ~ '^[0-9a-f]{40}$')
+);
+
+CREATE UNIQUE INDEX uq_task_git_refs_dedupe
+  ON task_git_refs(task_id, repo_id, relation, object_type, object_value, COALESCE(resolved_commit, ''));
+CREATE INDEX idx_task_git_refs_task_created ON task_git_refs(task_id, created_at);
+CREATE INDEX idx_task_git_refs_repo_object ON task_git_refs(repo_id, object_type, object_value);
+CREATE INDEX idx_task_git_refs_relation ON task_git_refs(relation);
+
+CREATE TABLE projects (
+  id TEXT PRIMARY KEY,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  CHECK (id ~ '^[a-z][a-z]$')
+);
+
+CREATE TABLE users (
+  id TEXT PRIMARY KEY,
+  username TEXT NOT NULL UNIQUE,
+  password_hash TEXT NOT NULL,
+  role TEXT NOT NULL,
+  disabled INTEGER NOT NULL DEFAULT 0,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  CHECK (role IN ('admin')),
+  CHECK (disabled IN (0, 1))
+);
+
+CREATE INDEX idx_users_username ON users(username);
+
+CREATE TABLE sessions (
+  id TEXT PRIMARY KEY,
+  user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token_hash TEXT NOT NULL UNIQUE,
+  expires_at TEXT NOT NULL,
+  revoked_at TEXT,
+  created_at TEXT NOT NULL,
+  CHECK (expires_at >= created_at)
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
+CREATE INDEX idx_sessions_token_hash ON sessions(token_hash);
+
+-- rowid keeps insertion order, which queries use to break created_at ties
+-- as they do on SQLite.
+CREATE TABLE task_comments (
+  rowid BIGINT GENERATED ALWAYS AS IDENTITY,
+  id TEXT PRIMARY KEY,
+  task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  author TEXT NOT NULL,
+  body TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  CHECK (length(trim(author)) > 0),
+  CHECK (length(trim(body)) > 0)
+);
+
+CREATE INDEX idx_task_comments_task_created ON task_comments(task_id, created_at);
+
+CREATE TABLE task_events (
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  event_type TEXT NOT NULL,
+  actor TEXT,
+  changes_json TEXT,
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_task_events_task_id ON task_events(task_id, id);
+
+CREATE TABLE saved_filters (
+  project_id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  filter_json TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  PRIMARY KEY (project_id, name)
+);
+
+CREATE TABLE milestones (
+  id TEXT PRIMARY KEY,
+  project_id TEXT NOT NULL,
+  title TEXT NOT NULL,
+  due_date TEXT,
+  state TEXT NOT NULL DEFAULT 'open',
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_milestones_project ON milestones(project_id, state);
+
+CREATE TABLE task_worklogs (
+  rowid BIGINT GENERATED ALWAYS AS IDENTITY,
+  id TEXT PRIMARY KEY,
+  task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  actor TEXT NOT NULL,
+  seconds BIGINT NOT NULL,
+  note TEXT,
+  started_at TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  CHECK (length(trim(actor)) > 0),
+  CHECK (seconds > 0)
+);
+
+CREATE INDEX idx_task_worklogs_task_started ON task_worklogs(task_id, started_at);
+
+CREATE TABLE task_watchers (
+  task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  "user" TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  PRIMARY KEY (task_id, "user"),
+  CHECK (length(trim("user")) > 0)
+);
+
+CREATE INDEX idx_task_watchers_user ON task_watchers("user");
+
+CREATE TABLE task_leases (
+  task_id TEXT PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+  holder TEXT NOT NULL,
+  claimed_at TEXT NOT NULL,
+  expires_at TEXT NOT NULL,
+  CHECK (length(trim(holder)) > 0)
+);
+
+CREATE INDEX idx_task_leases_expires_at ON task_leases(expires_at);
+
+CREATE TABLE custom_fields (
+  project_id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  type TEXT NOT NULL,
+  required INTEGER NOT NULL DEFAULT 0,
+  values_json TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  PRIMARY KEY (project_id, name)
+);
+
+CREATE TABLE api_tokens (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  token_hash TEXT NOT NULL UNIQUE,
+  scopes_json TEXT NOT NULL,
+  expires_at TEXT,
+  created_at TEXT NOT NULL,
+  rotated_at TEXT
+);
+
+CREATE TABLE admin_audit (
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  action TEXT NOT NULL,
+  actor TEXT,
+  params_json TEXT,
+  affected BIGINT NOT NULL DEFAULT 0,
+  dry_run INTEGER NOT NULL DEFAULT 0,
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_admin_audit_action ON admin_audit(action, id);
+
+CREATE TABLE operations (
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  project_id TEXT NOT NULL,
+  kind TEXT NOT NULL,
+  actor TEXT,
+  payload_json TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  undone_at TEXT
+);
+
+CREATE INDEX idx_operations_project_id ON operations(project_id, id);
+
+CREATE TABLE task_changes (
+  task_id TEXT PRIMARY KEY,
+  seq BIGINT NOT NULL,
+  project_id TEXT NOT NULL DEFAULT '',
+  deleted_at TEXT
+);
+
+CREATE UNIQUE INDEX idx_task_changes_seq ON task_changes(seq);
+CREATE INDEX idx_task_changes_project_seq ON task_changes(project_id, seq);
+
+CREATE TABLE import_reports (
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  project_id TEXT NOT NULL,
+  dry_run INTEGER NOT NULL DEFAULT 0,
+  conflicts_json TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_import_reports_created_at ON import_reports(created_at);
+
+CREATE TABLE task_status_snapshots (
+  project_id TEXT NOT NULL,
+  day TEXT NOT NULL,
+  status TEXT NOT NULL,
+  count BIGINT NOT NULL,
+  PRIMARY KEY (project_id, day, status)
+);
+
+CREATE TABLE idempotency_keys (
+  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  idem_key TEXT NOT NULL,
+  scope TEXT NOT NULL,
+  request_hash TEXT NOT NULL,
+  status INTEGER NOT NULL,
+  response_body BYTEA NOT NULL,
+  created_at TEXT NOT NULL,
+  UNIQUE (idem_key, scope)
+);
+
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+
+-- julianday mirrors SQLite's for the RFC 3339 timestamps the store writes.
+CREATE FUNCTION julianday(value TEXT) RETURNS DOUBLE PRECISION
+LANGUAGE sql STABLE AS $$
+  SELECT CAST(extract(epoch FROM CAST(NULLIF(value, '') AS timestamptz)) AS DOUBLE PRECISION) / 86400 + 2440587.5
+$$;
+
+-- json_search_text joins the scalar values of a JSON document with spaces,
+-- as the SQLite schema does with json_tree; invalid JSON yields ''.
+CREATE FUNCTION json_search_text(value TEXT) RETURNS TEXT
+LANGUAGE plpgsql IMMUTABLE AS $$
+DECLARE
+  doc jsonb;
+BEGIN
+  IF value IS NULL OR value = '' THEN
+    RETURN '';
+  END IF;
+  BEGIN
+    doc := CAST(value AS jsonb);
+  EXCEPTION WHEN others THEN
+    RETURN '';
+  END;
+  RETURN COALESCE((
+    SELECT string_agg(item #>> '{}', ' ')
+    FROM jsonb_path_query(doc, 'strict $.**') AS item
+    WHERE jsonb_typeof(item) NOT IN ('object', 'array')
+  ), '');
+END
+$$;
+
+-- tasks_fts holds the same columns as the SQLite FTS5 table, plus the
+-- indexed document.
+CREATE TABLE tasks_fts (
+  task_id TEXT NOT NULL,
+  title TEXT NOT NULL DEFAULT '',
+  description TEXT NOT NULL DEFAULT '',
+  notes TEXT NOT NULL DEFAULT '',
+  design TEXT NOT NULL DEFAULT '',
+  acceptance_criteria TEXT NOT NULL DEFAULT '',
+  assignee TEXT NOT NULL DEFAULT '',
+  custom TEXT NOT NULL DEFAULT '',
+  document tsvector GENERATED ALWAYS AS (to_tsvector('simple',
+    title || ' ' || description || ' ' || notes || ' ' || design || ' ' ||
+    acceptance_criteria || ' ' || assignee || ' ' || custom)) STORED
+);
+
+CREATE INDEX idx_tasks_fts_task_id ON tasks_fts(task_id);
+CREATE INDEX idx_tasks_fts_document ON tasks_fts USING GIN (document);
+
+INSERT INTO tasks_fts(task_id, title, description, notes, design, acceptance_criteria, assignee, custom)
+  SELECT id, title, COALESCE(description, ''), COALESCE(notes, ''), COALESCE(design, ''),
+    COALESCE(acceptance_criteria, ''), COALESCE(assignee, ''), json_search_text(custom)
+  FROM tasks;
+
+CREATE FUNCTION tasks_fts_sync() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    DELETE FROM tasks_fts WHERE task_id = OLD.id;
+  END IF;
+  IF TG_OP IN ('INSERT', 'UPDATE') THEN
+    INSERT INTO tasks_fts(task_id, title, description, notes, design, acceptance_criteria, assignee, custom)
+      VALUES (NEW.id, NEW.title, COALESCE(NEW.description, ''), COALESCE(NEW.notes, ''), COALESCE(NEW.design, ''),
+        COALESCE(NEW.acceptance_criteria, ''), COALESCE(NEW.assignee, ''), json_search_text(NEW.custom));
+  END IF;
+  RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER tasks_fts_sync AFTER INSERT OR UPDATE OR DELETE ON tasks
+  FOR EACH ROW EXECUTE FUNCTION tasks_fts_sync();
+
+-- The task_changes triggers take an advisory lock (postgresMigrationLockKey)
+-- before reading MAX(seq) and hold it to commit, so that concurrent writers
+-- never share a seq and changes commit in seq order.
+CREATE FUNCTION task_changes_task() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  PERFORM pg_advisory_xact_lock(1735552627);
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO task_changes (task_id, project_id, seq, deleted_at)
+      VALUES (OLD.id, COALESCE(OLD.project_id, ''), (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes),
+        to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.MS"Z"'))
+      ON CONFLICT (task_id) DO UPDATE SET seq = excluded.seq, project_id = excluded.project_id, deleted_at = excluded.deleted_at;
+  ELSE
+    INSERT INTO task_changes (task_id, project_id, seq, deleted_at)
+      VALUES (NEW.id, COALESCE(NEW.project_id, ''), (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes), NULL)
+      ON CONFLICT (task_id) DO UPDATE SET seq = excluded.seq, project_id = excluded.project_id, deleted_at = NULL;
+  END IF;
+  RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER task_changes_task AFTER INSERT OR UPDATE OR DELETE ON tasks
+  FOR EACH ROW EXECUTE FUNCTION task_changes_task();
+
+-- task_changes_related bumps the task named by the column in TG_ARGV[0].
+CREATE FUNCTION task_changes_related() RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+  changed_id TEXT;
+BEGIN
+  changed_id := COALESCE(to_jsonb(NEW), to_jsonb(OLD)) ->> TG_ARGV[0];
+  PERFORM pg_advisory_xact_lock(1735552627);
+  INSERT INTO task_changes (task_id, project_id, seq)
+    SELECT id, COALESCE(project_id, ''), (SELECT COALESCE(MAX(seq), 0) + 1 FROM task_changes) FROM tasks WHERE id = changed_id
+    ON CONFLICT (task_id) DO UPDATE SET seq = excluded.seq;
+  RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER task_changes_label AFTER INSERT OR DELETE ON task_labels
+  FOR EACH ROW EXECUTE FUNCTION task_changes_related('task_id');
+
+CREATE TRIGGER task_changes_dep AFTER INSERT OR DELETE ON task_deps
+  FOR EACH ROW EXECUTE FUNCTION task_changes_related('child_id');
+`,
+	},
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"grns/internal/models"
+)
+
+func TestRebindPostgres(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "placeholders",
+			query: "SELECT id FROM tasks WHERE id = ? AND project_id = ? LIMIT ?",
+			want:  "SELECT id FROM tasks WHERE id = $1 AND project_id = $2 LIMIT $3",
+		},
+		{
+			name:  "literals and comments",
+			query: "SELECT '?', \"a?\" -- why?\nFROM t /* ? */ WHERE x = ? AND y = 'it''s ?'",
+			want:  "SELECT '?', \"a?\" -- why?\nFROM t /* ? */ WHERE x = $1 AND y = 'it''s ?'",
+		},
+		{
+			name:  "dollar quoted body",
+			query: "CREATE FUNCTION f() RETURNS int AS $fn$ SELECT 1 WHERE 'a' ? 'b' $fn$ LANGUAGE sql; SELECT ?",
+			want:  "CREATE FUNCTION f() RETURNS int AS $fn$ SELECT 1 WHERE 'a' ? 'b' $fn$ LANGUAGE sql; SELECT $1",
+		},
+		{
+			name:  "insert or ignore",
+			query: "INSERT OR IGNORE INTO task_labels (task_id, label) VALUES (?, ?);\n",
+			want:  "INSERT INTO task_labels (task_id, label) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rebindPostgres(tc.query); got != tc.want {
+				t.Fatalf("rebindPostgres:\n got %q\nwant %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDialectJSONSetTrueBindsKey(t *testing.T) {
+	for _, d := range []dialect{sqliteDialect{}, postgresDialect{}} {
+		expr, args := d.jsonSetTrue("meta_json", "it's")
+		if strings.Contains(expr, "it's") {
+			t.Fatalf("%s: key spliced into SQL: %s", d.name(), expr)
+		}
+		if len(args) != 1 || !strings.Contains(args[0].(string), "it's") {
+			t.Fatalf("%s: expected key bound as the only argument, got %#v", d.name(), args)
+		}
+	}
+}
+
+func TestPostgresArgs(t *testing.T) {
+	args := []driver.NamedValue{{Ordinal: 1, Value: true}, {Ordinal: 2, Value: "x"}, {Ordinal: 3, Value: false}}
+	got := postgresArgs(args)
+	if got[0].Value != int64(1) || got[1].Value != "x" || got[2].Value != int64(0) {
+		t.Fatalf("unexpected args: %#v", got)
+	}
+	if args[0].Value != true {
+		t.Fatalf("expected input args to be left alone, got %#v", args)
+	}
+
+	plain := []driver.NamedValue{{Ordinal: 1, Value: "x"}}
+	if got := postgresArgs(plain); &got[0] != &plain[0] {
+		t.Fatalf("expected args without booleans to be reused")
+	}
+}
+
+func TestPostgresMigrationsMatchSQLiteVersion(t *testing.T) {
+	if got, want := latestMigrationVersion(postgresDialect{}), latestMigrationVersion(sqliteDialect{}); got != want {
+		t.Fatalf("postgres schema version %d, sqlite %d; add the matching postgres migration", got, want)
+	}
+}
+
+func TestIsPostgresURL(t *testing.T) {
+	for _, value := range []string{"postgres://localhost/grns", "postgresql://u:p@db/grns?sslmode=require"} {
+		if !IsPostgresURL(value) {
+			t.Fatalf("expected %q to be a postgres url", value)
+		}
+	}
+	for _, value := range []string{"", ".grns.db", ":memory:", "mysql://localhost/grns"} {
+		if IsPostgresURL(value) {
+			t.Fatalf("expected %q not to be a postgres url", value)
+		}
+	}
+}
+
+// TestPostgresStoreRoundTrip runs against a real server when
+// GRNS_TEST_POSTGRES_URL names a scratch database.
+func TestPostgresStoreRoundTrip(t *testing.T) {
+	dsn := os.Getenv("GRNS_TEST_POSTGRES_URL")
+	if dsn == "" {
+		t.Skip("GRNS_TEST_POSTGRES_URL not set")
+	}
+	st, err := OpenPostgres(dsn, Options{})
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+	defer st.Close()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	id := fmt.Sprintf("gr-%04x", now.UnixNano()&0xffff)
+	task := &models.Task{
+		ID:        id,
+		Title:     "postgres round trip",
+		Status:    "open",
+		Type:      "task",
+		Priority:  2,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := st.CreateTask(ctx, task, []string{"pg"}, nil); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	defer func() { _ = st.DeleteTasks(ctx, "gr", []string{id}, now) }()
+
+	got, err := st.GetTask(ctx, id)
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if got.Title != task.Title {
+		t.Fatalf("expected title %q, got %q", task.Title, got.Title)
+	}
+	tasks, err := st.ListTasks(ctx, ListFilter{Labels: []string{"pg"}, SearchQuery: "round trip"})
+	if err != nil {
+		t.Fatalf("list tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != id {
+		t.Fatalf("expected search to find %s, got %#v", id, tasks)
+	}
+}
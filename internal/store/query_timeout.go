@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+)
+
+// ErrQueryTimeout is returned when a statement runs past Options.QueryTimeout.
+// The driver interrupts SQLite when the deadline passes, so a pathological
+// search or regex filter releases its connection instead of holding it.
+var ErrQueryTimeout = errors.New("query timed out")
+
+type noQueryTimeoutKey struct{}
+
+// WithoutQueryTimeout exempts statements run with ctx from the store's query
+// timeout, for admin work such as VACUUM or reindexing that is expected to
+// run long. The caller's own deadline still applies.
+func WithoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noQueryTimeoutKey{}, true)
+}
+
+// statementContext bounds one statement by the query timeout. ok is false,
+// and ctx returned as is, when no timeout applies.
+func (s *Store) statementContext(ctx context.Context) (_ context.Context, cancel context.CancelFunc, ok bool) {
+	if s.queryTimeout <= 0 {
+		return ctx, nil, false
+	}
+	if exempt, _ := ctx.Value(noQueryTimeoutKey{}).(bool); exempt {
+		return ctx, nil, false
+	}
+	ctx, cancel = context.WithTimeoutCause(ctx, s.queryTimeout, ErrQueryTimeout)
+	return ctx, cancel, true
+}
+
+// queryTimeoutError marks err as ErrQueryTimeout when ctx hit the query
+// timeout, as opposed to the caller cancelling or another failure.
+func queryTimeoutError(ctx context.Context, timeout time.Duration, err error) error {
+	if err == nil || !errors.Is(context.Cause(ctx), ErrQueryTimeout) {
+		return err
+	}
+	return fmt.Errorf("%w after %s: %v", ErrQueryTimeout, timeout, err)
+}
+
+// timeoutRows keeps a query's timeout running while its rows are read. The
+// driver only interrupts the first step, so Next checks the deadline between
+// rows; Close releases the timer. The optional column type and result set
+// interfaces are forwarded, falling back to what database/sql assumes when
+// the driver's rows lack them.
+type timeoutRows struct {
+	driver.Rows
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+}
+
+func (r *timeoutRows) Next(dest []driver.Value) error {
+	if err := r.ctx.Err(); err != nil {
+		return queryTimeoutError(r.ctx, r.timeout, err)
+	}
+	return queryTimeoutError(r.ctx, r.timeout, r.Rows.Next(dest))
+}
+
+func (r *timeoutRows) Close() error {
+	defer r.cancel()
+	return r.Rows.Close()
+}
+
+func (r *timeoutRows) HasNextResultSet() bool {
+	if sets, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return sets.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *timeoutRows) NextResultSet() error {
+	if sets, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return queryTimeoutError(r.ctx, r.timeout, sets.NextResultSet())
+	}
+	return io.EOF
+}
+
+func (r *timeoutRows) ColumnTypeScanType(index int) reflect.Type {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return typed.ColumnTypeScanType(index)
+	}
+	return reflect.TypeFor[any]()
+}
+
+func (r *timeoutRows) ColumnTypeDatabaseTypeName(index int) string {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return typed.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *timeoutRows) ColumnTypeLength(index int) (int64, bool) {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return typed.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *timeoutRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if typed, isTyped := r.Rows.(driver.RowsColumnTypeNullable); isTyped {
+		return typed.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *timeoutRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	if typed, isTyped := r.Rows.(driver.RowsColumnTypePrecisionScale); isTyped {
+		return typed.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"grns/internal/models"
+)
+
+const savedFilterColumns = "project_id, name, filter_json, created_at, updated_at"
+
+// CreateSavedFilter inserts one named filter. Duplicate names fail with a unique constraint error.
+func (s *Store) CreateSavedFilter(ctx context.Context, filter *models.SavedFilter) error {
+	if filter == nil {
+		return fmt.Errorf("saved filter is required")
+	}
+	now := time.Now().UTC()
+	if filter.CreatedAt.IsZero() {
+		filter.CreatedAt = now
+	}
+	if filter.UpdatedAt.IsZero() {
+		filter.UpdatedAt = filter.CreatedAt
+	}
+	filterJSON, err := savedFilterToJSON(filter.Filter)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO saved_filters (project_id, name, filter_json, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`,
+		normalizeProject(filter.Project),
+		filter.Name,
+		filterJSON,
+		dbFormatTime(filter.CreatedAt),
+		dbFormatTime(filter.UpdatedAt),
+	)
+	return err
+}
+
+// GetSavedFilter returns one named filter, or nil when it does not exist.
+func (s *Store) GetSavedFilter(ctx context.Context, project, name string) (*models.SavedFilter, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+savedFilterColumns+` FROM saved_filters WHERE project_id = ? AND name = ?`, normalizeProject(project), name)
+	filter, err := scanSavedFilter(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+// ListSavedFilters lists a project's named filters ordered by name.
+func (s *Store) ListSavedFilters(ctx context.Context, project string) ([]models.SavedFilter, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+savedFilterColumns+` FROM saved_filters WHERE project_id = ? ORDER BY name`, normalizeProject(project))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	filters := []models.SavedFilter{}
+	for rows.Next() {
+		filter, err := scanSavedFilter(rows)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, rows.Err()
+}
+
+// UpdateSavedFilter replaces the filter payload of an existing named filter.
+// It reports false when no filter with that name exists.
+func (s *Store) UpdateSavedFilter(ctx context.Context, filter *models.SavedFilter) (bool, error) {
+	if filter == nil {
+		return false, fmt.Errorf("saved filter is required")
+	}
+	if filter.UpdatedAt.IsZero() {
+		filter.UpdatedAt = time.Now().UTC()
+	}
+	filterJSON, err := savedFilterToJSON(filter.Filter)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE saved_filters SET filter_json = ?, updated_at = ?
+		WHERE project_id = ? AND name = ?
+	`, filterJSON, dbFormatTime(filter.UpdatedAt), normalizeProject(filter.Project), filter.Name)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// DeleteSavedFilter deletes one named filter and reports whether it existed.
+func (s *Store) DeleteSavedFilter(ctx context.Context, project, name string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM saved_filters WHERE project_id = ? AND name = ?", normalizeProject(project), name)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func scanSavedFilter(scanner interface {
+	Scan(dest ...any) error
+}) (models.SavedFilter, error) {
+	filter := models.SavedFilter{}
+	var filterJSON, createdAt, updatedAt string
+	if err := scanner.Scan(&filter.Project, &filter.Name, &filterJSON, &createdAt, &updatedAt); err != nil {
+		return models.SavedFilter{}, err
+	}
+	if err := json.Unmarshal([]byte(filterJSON), &filter.Filter); err != nil {
+		return models.SavedFilter{}, fmt.Errorf("decode saved filter filter_json: %w", err)
+	}
+	parsedCreated, err := dbParseTime(createdAt)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+	parsedUpdated, err := dbParseTime(updatedAt)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+	filter.CreatedAt = parsedCreated
+	filter.UpdatedAt = parsedUpdated
+	return filter, nil
+}
+
+func savedFilterToJSON(filter map[string]string) (string, error) {
+	if filter == nil {
+		filter = map[string]string{}
+	}
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return "", fmt.Errorf("marshal saved filter filter_json: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,18 @@
+package store
+
+import (
+	"context"
+
+	"grns/internal/models"
+)
+
+// SavedFilterStore is the persistence surface for named list filters.
+type SavedFilterStore interface {
+	CreateSavedFilter(ctx context.Context, filter *models.SavedFilter) error
+	GetSavedFilter(ctx context.Context, project, name string) (*models.SavedFilter, error)
+	ListSavedFilters(ctx context.Context, project string) ([]models.SavedFilter, error)
+	UpdateSavedFilter(ctx context.Context, filter *models.SavedFilter) (bool, error)
+	DeleteSavedFilter(ctx context.Context, project, name string) (bool, error)
+}
+
+var _ SavedFilterStore = (*Store)(nil)
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"modernc.org/sqlite"
+)
+
+// The REGEXP operator calls regexp(pattern, value); SQLite ships no
+// implementation, so the store registers one backed by Go's regexp package.
+// Patterns therefore use Go RE2 syntax, e.g. "(?i)docs/.*".
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("regexp", 2, sqlRegexp)
+}
+
+// sqlRegexp matches value against pattern. A NULL value never matches.
+func sqlRegexp(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	pattern, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("regexp: pattern must be text")
+	}
+	var value string
+	switch v := args[1].(type) {
+	case nil:
+		return int64(0), nil
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		value = fmt.Sprint(v)
+	}
+	re, err := regexpCache.compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if re.MatchString(value) {
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
+
+// regexpCacheSize bounds the compiled patterns kept; a full cache is reset
+// rather than evicted piecemeal, since filters rarely use many patterns.
+const regexpCacheSize = 64
+
+var regexpCache = &compiledRegexps{patterns: map[string]*regexp.Regexp{}}
+
+// compiledRegexps saves recompiling a pattern for every row it is matched against.
+type compiledRegexps struct {
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+func (c *compiledRegexps) compile(pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if re, ok := c.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.patterns) >= regexpCacheSize {
+		clear(c.patterns)
+	}
+	c.patterns[pattern] = re
+	return re, nil
+}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"grns/internal/models"
+)
+
+// TaskStats returns grouped task counts for filter.
+func (s *Store) TaskStats(ctx context.Context, filter StatsFilter) (*TaskStats, error) {
+	project := normalizeProject(filter.Project)
+	stats := &TaskStats{}
+
+	created, createdArgs := statsWhere(project, "t.created_at", filter)
+	closed, closedArgs := statsWhere(project, "t.closed_at", filter)
+	closed += " AND t.status = ? AND t.closed_at IS NOT NULL"
+	closedArgs = append(closedArgs, string(models.StatusClosed))
+
+	var err error
+	if stats.CreatedPerDay, err = s.countStrings(ctx, "SELECT substr(t.created_at, 1, 10), COUNT(*) FROM tasks t WHERE "+created+" GROUP BY 1", createdArgs); err != nil {
+		return nil, err
+	}
+	if stats.ClosedPerDay, err = s.countStrings(ctx, "SELECT substr(t.closed_at, 1, 10), COUNT(*) FROM tasks t WHERE "+closed+" GROUP BY 1", closedArgs); err != nil {
+		return nil, err
+	}
+	var avg sql.NullFloat64
+	if err := s.db.QueryRowContext(ctx,
+		"SELECT COUNT(*), AVG((julianday(t.closed_at) - julianday(t.created_at)) * 86400) FROM tasks t WHERE "+closed,
+		closedArgs...,
+	).Scan(&stats.ClosedCount, &avg); err != nil {
+		return nil, err
+	}
+	stats.AvgCloseSeconds = avg.Float64
+
+	if stats.ByType, err = s.countStrings(ctx, "SELECT t.type, COUNT(*) FROM tasks t WHERE "+created+" GROUP BY 1", createdArgs); err != nil {
+		return nil, err
+	}
+	if stats.ByAssignee, err = s.countStrings(ctx, "SELECT COALESCE(t.assignee, ''), COUNT(*) FROM tasks t WHERE "+created+" GROUP BY 1", createdArgs); err != nil {
+		return nil, err
+	}
+	if stats.ByLabel, err = s.countStrings(ctx, "SELECT l.label, COUNT(*) FROM tasks t JOIN task_labels l ON l.task_id = t.id WHERE "+created+" GROUP BY 1", createdArgs); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+s.dialect.truncate("julianday(?) - julianday(t.created_at)")+", COUNT(*) FROM tasks t WHERE (? = '' OR t.project_id = ?) AND t.status NOT IN (?, ?) GROUP BY 1",
+		dbFormatTime(filter.Now), project, project, string(models.StatusClosed), string(models.StatusTombstone),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stats.OpenAgeDays = map[int]int{}
+	for rows.Next() {
+		var days, count int
+		if err := rows.Scan(&days, &count); err != nil {
+			return nil, err
+		}
+		stats.OpenAgeDays[max(days, 0)] += count
+	}
+	return stats, rows.Err()
+}
+
+// statsWhere scopes tasks to project, excludes tombstones, and limits column to the filter range.
+func statsWhere(project, column string, filter StatsFilter) (string, []any) {
+	where := []string{"(? = '' OR t.project_id = ?)", "t.status != ?"}
+	args := []any{project, project, string(models.StatusTombstone)}
+	if !filter.From.IsZero() {
+		where = append(where, column+" >= ?")
+		args = append(args, dbFormatTime(filter.From))
+	}
+	if !filter.To.IsZero() {
+		where = append(where, column+" < ?")
+		args = append(args, dbFormatTime(filter.To))
+	}
+	return strings.Join(where, " AND "), args
+}
+
+func (s *Store) countStrings(ctx context.Context, query string, args []any) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := map[string]int{}
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, err
+		}
+		counts[key] = count
+	}
+	return counts, rows.Err()
+}
+
+// RecordStatusSnapshots replaces day's status counts with the current counts.
+func (s *Store) RecordStatusSnapshots(ctx context.Context, day string) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM task_status_snapshots WHERE day = ?", day); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO task_status_snapshots (project_id, day, status, count)
+		SELECT project_id, ?, status, COUNT(*) FROM tasks WHERE status != ? GROUP BY project_id, status
+	`, day, string(models.StatusTombstone)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListStatusSnapshots lists status counts per day, summed over projects when project is empty.
+func (s *Store) ListStatusSnapshots(ctx context.Context, project, fromDay, toDay string) ([]StatusSnapshot, error) {
+	project = normalizeProject(project)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT day, status, SUM(count) FROM task_status_snapshots
+		WHERE (? = '' OR project_id = ?) AND day >= ? AND day <= ?
+		GROUP BY day, status
+		ORDER BY day, status
+	`, project, project, fromDay, toDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := []StatusSnapshot{}
+	for rows.Next() {
+		var snapshot StatusSnapshot
+		if err := rows.Scan(&snapshot.Day, &snapshot.Status, &snapshot.Count); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
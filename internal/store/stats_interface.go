@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// StatsFilter scopes task statistics. An empty Project spans all projects.
+// Zero From/To leave the range open; From is inclusive and To exclusive.
+type StatsFilter struct {
+	Project string
+	From    time.Time
+	To      time.Time
+	// Now is the reference time for open task ages.
+	Now time.Time
+}
+
+// TaskStats holds grouped task counts. Tombstoned tasks are never counted.
+type TaskStats struct {
+	// CreatedPerDay and ClosedPerDay map a UTC date (YYYY-MM-DD) to the number
+	// of tasks created or closed that day within the range.
+	CreatedPerDay map[string]int
+	ClosedPerDay  map[string]int
+	// ClosedCount tasks closed within the range took AvgCloseSeconds on average
+	// from creation to close.
+	ClosedCount     int
+	AvgCloseSeconds float64
+	// OpenAgeDays maps whole days since creation to the number of tasks not
+	// closed at Now; the range does not apply.
+	OpenAgeDays map[int]int
+	// ByType, ByAssignee, and ByLabel count tasks created within the range.
+	// Unassigned tasks count under "".
+	ByType     map[string]int
+	ByAssignee map[string]int
+	ByLabel    map[string]int
+}
+
+// StatusSnapshot is the number of tasks in one status at the end of a UTC day
+// (YYYY-MM-DD), summed over the projects it was listed for.
+type StatusSnapshot struct {
+	Day    string
+	Status string
+	Count  int
+}
+
+// StatsStore computes task statistics with grouped queries.
+type StatsStore interface {
+	TaskStats(ctx context.Context, filter StatsFilter) (*TaskStats, error)
+	// RecordStatusSnapshots replaces day's per-project status counts with the
+	// current ones. Tombstoned tasks are not counted.
+	RecordStatusSnapshots(ctx context.Context, day string) error
+	// ListStatusSnapshots lists snapshots for days in [fromDay, toDay], ordered
+	// by day. An empty project sums all projects.
+	ListStatusSnapshots(ctx context.Context, project, fromDay, toDay string) ([]StatusSnapshot, error)
+}
+
+var _ StatsStore = (*Store)(nil)
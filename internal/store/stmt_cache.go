@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// stmtCache prepares hot-path statements once per store and closes them
+// with it. database/sql re-prepares a cached statement on whichever pooled
+// connection runs it, so entries stay valid as connections are recycled.
+type stmtCache struct {
+	mu     sync.Mutex
+	db     *sql.DB
+	stmts  map[string]*sql.Stmt
+	closed bool
+}
+
+func newStmtCache(db *sql.DB) *stmtCache {
+	return &stmtCache{db: db, stmts: map[string]*sql.Stmt{}}
+}
+
+// prepare returns the cached statement for query, preparing it on first
+// use. It needs a free connection, so it must not be called while the
+// caller holds a transaction; use cached there instead.
+func (c *stmtCache) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	if c == nil {
+		return nil, errors.New("statement cache unavailable")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stmt, ok := c.stmts[query]; ok {
+		return stmt, nil
+	}
+	if c.closed {
+		return nil, errors.New("statement cache closed")
+	}
+	// The context bounds only the prepare; the statement outlives it.
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.stmts[query] = stmt
+	return stmt, nil
+}
+
+// cached returns an already prepared statement for query, or nil.
+func (c *stmtCache) cached(query string) *sql.Stmt {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stmts[query]
+}
+
+func (c *stmtCache) close() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	var errs []error
+	for query, stmt := range c.stmts {
+		errs = append(errs, stmt.Close())
+		delete(c.stmts, query)
+	}
+	return errors.Join(errs...)
+}
+
+// warm prepares queries up front, so that transactions, which never
+// prepare, can reuse them from the first call.
+func (c *stmtCache) warm(ctx context.Context, queries ...string) error {
+	for _, query := range queries {
+		if _, err := c.prepare(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hotStatements are prepared when the store opens: the lookups agents poll
+// most and the single-task insert.
+var hotStatements = []string{taskExistsSQL, getTaskSQL, listLabelsSQL, listDependenciesSQL, insertTaskSQL}
+
+// cachedQuerier runs queries through the store's statement cache, falling
+// back to a direct query when a statement cannot be prepared.
+type cachedQuerier struct {
+	s *Store
+}
+
+func (q cachedQuerier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	stmt, err := q.s.stmts.prepare(ctx, query)
+	if err != nil {
+		return q.s.db.QueryRowContext(ctx, query, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+func (q cachedQuerier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := q.s.stmts.prepare(ctx, query)
+	if err != nil {
+		return q.s.db.QueryContext(ctx, query, args...)
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// txQuerier runs queries in tx, binding statements the cache already holds.
+// It never prepares, so it cannot wait on a second connection while tx
+// holds one.
+type txQuerier struct {
+	tx    *sql.Tx
+	stmts *stmtCache
+}
+
+func (q txQuerier) stmt(ctx context.Context, query string) *sql.Stmt {
+	stmt := q.stmts.cached(query)
+	if stmt == nil {
+		return nil
+	}
+	return q.tx.StmtContext(ctx, stmt)
+}
+
+func (q txQuerier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	if stmt := q.stmt(ctx, query); stmt != nil {
+		return stmt.QueryRowContext(ctx, args...)
+	}
+	return q.tx.QueryRowContext(ctx, query, args...)
+}
+
+func (q txQuerier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if stmt := q.stmt(ctx, query); stmt != nil {
+		return stmt.QueryContext(ctx, args...)
+	}
+	return q.tx.QueryContext(ctx, query, args...)
+}
+
+func (q txQuerier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if stmt := q.stmt(ctx, query); stmt != nil {
+		return stmt.ExecContext(ctx, args...)
+	}
+	return q.tx.ExecContext(ctx, query, args...)
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"grns/internal/models"
@@ -16,8 +18,10 @@ import (
 )
 
 const (
-	busyTimeoutMS         = 5000
-	maxOpenConns          = 1
+	defaultBusyTimeoutMS  = 5000
+	defaultJournalMode    = JournalModeWAL
+	walMaxOpenConns       = 4
+	rollbackMaxOpenConns  = 1
 	maxIdleConns          = 1
 	connMaxLifetime       = 5 * time.Minute
 	maxOpenConnsEnvKey    = "GRNS_DB_MAX_OPEN_CONNS"
@@ -25,36 +29,80 @@ const (
 	connMaxLifetimeEnvKey = "GRNS_DB_CONN_MAX_LIFETIME"
 )
 
-// Store wraps the SQLite database.
-type Store struct {
-	db *sql.DB
+// SQLite journal modes accepted by Options.JournalMode.
+const (
+	JournalModeWAL      = "wal"
+	JournalModeDelete   = "delete"
+	JournalModeTruncate = "truncate"
+	JournalModePersist  = "persist"
+)
+
+// MemoryPath selects a private in-memory database instead of a file.
+const MemoryPath = ":memory:"
+
+// IsMemoryPath reports whether path selects an in-memory database.
+func IsMemoryPath(path string) bool {
+	return strings.TrimSpace(path) == MemoryPath
 }
 
-type txImportMutator struct {
-	tx *sql.Tx
+// memoryDBSeq names each in-memory database so that separate stores in one
+// process never share data.
+var memoryDBSeq atomic.Uint64
+
+// JournalModes lists the supported journal modes.
+var JournalModes = []string{JournalModeWAL, JournalModeDelete, JournalModeTruncate, JournalModePersist}
+
+// Options tunes the SQLite connection. Zero values select the defaults:
+// WAL journaling and a 5s busy timeout.
+type Options struct {
+	BusyTimeoutMS int
+	JournalMode   string
+	// QueryTimeout bounds each SQL statement; zero disables it. See ErrQueryTimeout.
+	QueryTimeout time.Duration
 }
 
-func (m *txImportMutator) TaskExists(id string) (bool, error) {
-	project := projectFromTaskID(id)
-	if project == "" {
-		return false, nil
+func (o Options) withDefaults() (Options, error) {
+	if o.BusyTimeoutMS <= 0 {
+		o.BusyTimeoutMS = defaultBusyTimeoutMS
 	}
-	var exists int
-	err := m.tx.QueryRow("SELECT 1 FROM tasks WHERE id = ? AND project_id = ? LIMIT 1", id, project).Scan(&exists)
-	if err == sql.ErrNoRows {
-		return false, nil
+	o.JournalMode = strings.ToLower(strings.TrimSpace(o.JournalMode))
+	if o.JournalMode == "" {
+		o.JournalMode = defaultJournalMode
 	}
-	if err != nil {
-		return false, err
+	if !slices.Contains(JournalModes, o.JournalMode) {
+		return o, fmt.Errorf("invalid journal mode %q (allowed: %s)", o.JournalMode, strings.Join(JournalModes, ", "))
 	}
-	return true, nil
+	return o, nil
+}
+
+// Store wraps the SQLite or PostgreSQL database.
+type Store struct {
+	db            *sql.DB
+	dialect       dialect
+	stmts         *stmtCache
+	queryTimeout  time.Duration
+	queryObserver atomic.Pointer[QueryObserver]
+}
+
+type txImportMutator struct {
+	tx      *sql.Tx
+	stmts   *stmtCache
+	dialect dialect
+}
+
+func (m *txImportMutator) querier() txQuerier {
+	return txQuerier{tx: m.tx, stmts: m.stmts}
+}
+
+func (m *txImportMutator) TaskExists(id string) (bool, error) {
+	return taskExistsQuery(context.Background(), m.querier(), id)
 }
 
 func (m *txImportMutator) CreateTask(ctx context.Context, task *models.Task, labels []string, deps []models.Dependency) error {
 	if task == nil {
 		return fmt.Errorf("task is required")
 	}
-	if err := insertTaskRow(ctx, m.tx, task); err != nil {
+	if err := insertTaskRow(ctx, m.querier(), task); err != nil {
 		return err
 	}
 	if err := insertLabels(ctx, m.tx, task.ID, labels); err != nil {
@@ -66,10 +114,26 @@ func (m *txImportMutator) CreateTask(ctx context.Context, task *models.Task, lab
 	return nil
 }
 
+func (m *txImportMutator) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	return getTaskQuery(ctx, m.querier(), id)
+}
+
+func (m *txImportMutator) ListLabels(ctx context.Context, id string) ([]string, error) {
+	return listLabelsQuery(ctx, m.querier(), id)
+}
+
 func (m *txImportMutator) UpdateTask(ctx context.Context, id string, update TaskUpdate) error {
 	return updateTaskExec(ctx, m.tx, id, update)
 }
 
+func (m *txImportMutator) UpdateTaskWithinWIPLimits(ctx context.Context, id string, update TaskUpdate, limits []WIPLimit) error {
+	project := projectFromTaskID(id)
+	if project == "" {
+		return fmt.Errorf("invalid task id")
+	}
+	return updateTaskWithinWIPLimitsTx(ctx, m.tx, m.dialect, project, id, update, limits)
+}
+
 func (m *txImportMutator) AddDependency(ctx context.Context, childID, parentID, depType string) error {
 	return addDependencyExec(ctx, m.tx, childID, parentID, depType)
 }
@@ -82,14 +146,26 @@ func (m *txImportMutator) RemoveDependencies(ctx context.Context, childID string
 	return removeDependenciesExec(ctx, m.tx, childID)
 }
 
+func (m *txImportMutator) RemoveDependency(ctx context.Context, childID, parentID, depType string) error {
+	return removeDependencyExec(ctx, m.tx, childID, parentID, depType)
+}
+
 // TaskExists checks whether a task exists by id.
 func (s *Store) TaskExists(id string) (bool, error) {
+	return taskExistsQuery(context.Background(), cachedQuerier{s: s}, id)
+}
+
+const taskExistsSQL = "SELECT 1 FROM tasks WHERE id = ? AND project_id = ? LIMIT 1"
+
+func taskExistsQuery(ctx context.Context, querier interface {
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}, id string) (bool, error) {
 	project := projectFromTaskID(id)
 	if project == "" {
 		return false, nil
 	}
 	var exists int
-	err := s.db.QueryRow("SELECT 1 FROM tasks WHERE id = ? AND project_id = ? LIMIT 1", id, project).Scan(&exists)
+	err := querier.QueryRowContext(ctx, taskExistsSQL, id, project).Scan(&exists)
 	if err == sql.ErrNoRows {
 		return false, nil
 	}
@@ -99,27 +175,70 @@ func (s *Store) TaskExists(id string) (bool, error) {
 	return true, nil
 }
 
-// Open opens the SQLite database and bootstraps the schema.
+// Open opens the SQLite database with default options and bootstraps the schema.
 func Open(path string) (*Store, error) {
-	dsn, err := sqliteDSN(path)
+	return OpenWithOptions(path, Options{})
+}
+
+// OpenMemory opens a fresh in-memory database with the full schema. Its
+// contents vanish when the store is closed.
+func OpenMemory() (*Store, error) {
+	return OpenWithOptions(MemoryPath, Options{})
+}
+
+// OpenWithOptions opens the SQLite database and bootstraps the schema. A path
+// of MemoryPath opens an in-memory database; see OpenMemory.
+func OpenWithOptions(path string, opts Options) (*Store, error) {
+	opts, err := opts.withDefaults()
 	if err != nil {
 		return nil, err
 	}
-	db, err := sql.Open("sqlite", dsn)
+	dsn, err := sqliteDSN(path, opts)
 	if err != nil {
 		return nil, err
 	}
+	base, err := sql.Open("sqlite", "")
+	if err != nil {
+		return nil, err
+	}
+	sqliteDriver := base.Driver()
+	_ = base.Close()
+
+	s := &Store{dialect: sqliteDialect{}}
+	db := sql.OpenDB(&observedConnector{dsn: dsn, driver: sqliteDriver, store: s})
 
-	if err := configureDB(db); err != nil {
+	if err := configureDB(db, opts, IsMemoryPath(path)); err != nil {
 		_ = db.Close()
 		return nil, err
 	}
+	return s.init(db, opts)
+}
+
+// init migrates db and takes ownership of it.
+func (s *Store) init(db *sql.DB, opts Options) (*Store, error) {
 	if err := runMigrations(db); err != nil {
 		_ = db.Close()
 		return nil, err
 	}
 
-	return &Store{db: db}, nil
+	s.db = db
+	s.stmts = newStmtCache(db)
+	if err := s.stmts.warm(context.Background(), hotStatements...); err != nil {
+		_ = s.stmts.close()
+		_ = db.Close()
+		return nil, err
+	}
+	// Set last so that migrations run unbounded.
+	s.queryTimeout = opts.QueryTimeout
+	return s, nil
+}
+
+// requireSQLite fails op with ErrUnsupported on other backends.
+func (s *Store) requireSQLite(op string) error {
+	if s.dialect.name() == dialectSQLite {
+		return nil
+	}
+	return fmt.Errorf("%s on %s: %w", op, s.dialect.name(), ErrUnsupported)
 }
 
 // Close closes the underlying database connection.
@@ -127,11 +246,23 @@ func (s *Store) Close() error {
 	if s == nil || s.db == nil {
 		return nil
 	}
-	return s.db.Close()
+	stmtErr := s.stmts.close()
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+	return stmtErr
+}
+
+// RunInTx executes fn in a single database transaction for atomic imports,
+// RPC batches, and task writes that record their audit events.
+func (s *Store) RunInTx(ctx context.Context, fn func(ImportMutator) error) error {
+	return s.withTx(ctx, func(m *txImportMutator) error {
+		return fn(m)
+	})
 }
 
-// RunInTx executes fn in a single database transaction for atomic import operations.
-func (s *Store) RunInTx(ctx context.Context, fn func(ImportMutator) error) (err error) {
+// withTx runs fn in one transaction, committing when fn returns nil.
+func (s *Store) withTx(ctx context.Context, fn func(*txImportMutator) error) (err error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -142,32 +273,38 @@ func (s *Store) RunInTx(ctx context.Context, fn func(ImportMutator) error) (err
 		}
 	}()
 
-	mutator := &txImportMutator{tx: tx}
-	if err := fn(mutator); err != nil {
+	if err = fn(&txImportMutator{tx: tx, stmts: s.stmts, dialect: s.dialect}); err != nil {
 		return err
 	}
 	return tx.Commit()
 }
 
-func configureDB(db *sql.DB) error {
-	pragmas := []string{
-		"PRAGMA journal_mode = WAL;",
-		"PRAGMA synchronous = NORMAL;",
-		"PRAGMA foreign_keys = ON;",
-		fmt.Sprintf("PRAGMA busy_timeout = %d;", busyTimeoutMS),
+// configureDB tunes the connection pool. Pragmas travel in the DSN so that
+// every pooled connection gets them, not only the first one.
+func configureDB(db *sql.DB, opts Options, memory bool) error {
+	if memory {
+		// An in-memory database lives only as long as a connection to it, and
+		// shared-cache connections lock whole tables instead of waiting on
+		// busy_timeout. Pin exactly one connection that never expires.
+		db.SetMaxOpenConns(1)
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxLifetime(0)
+		return db.Ping()
 	}
-	for _, stmt := range pragmas {
-		if _, err := db.Exec(stmt); err != nil {
-			return err
-		}
+
+	// WAL lets readers proceed alongside the single writer, so a few
+	// connections help; rollback journals serialize everything anyway.
+	defaultMaxOpen := rollbackMaxOpenConns
+	if opts.JournalMode == JournalModeWAL {
+		defaultMaxOpen = walMaxOpenConns
 	}
 
 	// Tune connection pool for local usage (configurable via env for benchmarks/tuning).
-	db.SetMaxOpenConns(intFromEnv(maxOpenConnsEnvKey, maxOpenConns))
+	db.SetMaxOpenConns(intFromEnv(maxOpenConnsEnvKey, defaultMaxOpen))
 	db.SetMaxIdleConns(intFromEnv(maxIdleConnsEnvKey, maxIdleConns))
 	db.SetConnMaxLifetime(durationFromEnv(connMaxLifetimeEnvKey, connMaxLifetime))
 
-	return nil
+	return db.Ping()
 }
 
 func intFromEnv(key string, def int) int {
@@ -196,10 +333,24 @@ func durationFromEnv(key string, def time.Duration) time.Duration {
 	return def
 }
 
-func sqliteDSN(path string) (string, error) {
+// sqliteDSN builds the driver DSN. Write transactions begin IMMEDIATE so
+// that concurrent writers wait on busy_timeout instead of failing with
+// SQLITE_BUSY when a deferred read lock cannot be upgraded.
+func sqliteDSN(path string, opts Options) (string, error) {
 	if path == "" {
 		return "", fmt.Errorf("db path is required")
 	}
-	u := url.URL{Scheme: "file", Path: path}
+	query := url.Values{}
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", opts.BusyTimeoutMS))
+	query.Add("_pragma", fmt.Sprintf("journal_mode(%s)", opts.JournalMode))
+	query.Add("_pragma", "synchronous(NORMAL)")
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Set("_txlock", "immediate")
+	if IsMemoryPath(path) {
+		query.Set("mode", "memory")
+		query.Set("cache", "shared")
+		return fmt.Sprintf("file:grns-memory-%d?%s", memoryDBSeq.Add(1), query.Encode()), nil
+	}
+	u := url.URL{Scheme: "file", Path: path, RawQuery: query.Encode()}
 	return u.String(), nil
 }
@@ -1,8 +1,16 @@
 package store
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"grns/internal/models"
 )
 
 func TestIntFromEnv(t *testing.T) {
@@ -48,3 +56,218 @@ func TestDurationFromEnv(t *testing.T) {
 		t.Fatalf("expected default on invalid duration, got %v", got)
 	}
 }
+
+func TestOpenWithOptionsAppliesPragmasToEveryConnection(t *testing.T) {
+	t.Setenv(maxOpenConnsEnvKey, "")
+	st, err := OpenWithOptions(filepath.Join(t.TempDir(), "grns.db"), Options{BusyTimeoutMS: 1234})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer st.Close()
+
+	ctx := context.Background()
+	// Hold two connections at once so the second is a fresh pool connection.
+	for i := range 2 {
+		conn, err := st.db.Conn(ctx)
+		if err != nil {
+			t.Fatalf("conn %d: %v", i, err)
+		}
+		defer conn.Close()
+
+		var busyTimeout, foreignKeys int
+		var journalMode string
+		if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout); err != nil {
+			t.Fatalf("busy_timeout: %v", err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+			t.Fatalf("foreign_keys: %v", err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journalMode); err != nil {
+			t.Fatalf("journal_mode: %v", err)
+		}
+		if busyTimeout != 1234 || foreignKeys != 1 || journalMode != JournalModeWAL {
+			t.Fatalf("conn %d: busy_timeout=%d foreign_keys=%d journal_mode=%s", i, busyTimeout, foreignKeys, journalMode)
+		}
+	}
+}
+
+func TestOpenWithOptionsRejectsUnknownJournalMode(t *testing.T) {
+	if _, err := OpenWithOptions(filepath.Join(t.TempDir(), "grns.db"), Options{JournalMode: "off"}); err == nil {
+		t.Fatal("expected error for journal mode off")
+	}
+}
+
+func TestConcurrentWritersAcrossStoresDoNotFailBusy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grns.db")
+	stores := make([]*Store, 2)
+	for i := range stores {
+		st, err := Open(path)
+		if err != nil {
+			t.Fatalf("open store %d: %v", i, err)
+		}
+		defer st.Close()
+		stores[i] = st
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 80)
+	for _, st := range stores {
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 5 {
+					errs <- st.AppendAdminAudit(ctx, &models.AdminAuditEntry{Action: "cleanup", CreatedAt: time.Now().UTC()})
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent write failed: %v", err)
+		}
+	}
+
+	entries, err := stores[0].ListAdminAudit(ctx, AdminAuditFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(entries) != 80 {
+		t.Fatalf("expected 80 entries, got %d", len(entries))
+	}
+}
+
+func TestOpenMemoryIsMigratedSearchableAndIsolated(t *testing.T) {
+	first, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("open memory: %v", err)
+	}
+	defer first.Close()
+	second, err := OpenMemory()
+	if err != nil {
+		t.Fatalf("open second memory: %v", err)
+	}
+	defer second.Close()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	task := &models.Task{ID: "gr-me01", Title: "Ephemeral sandbox", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := first.CreateTask(ctx, task, nil, nil); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// Idle time must not drop the only connection and with it the data.
+	time.Sleep(10 * time.Millisecond)
+	tasks, err := first.ListTasks(ctx, ListFilter{SearchQuery: "sandbox"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "gr-me01" {
+		t.Fatalf("expected FTS hit in memory store, got %+v", tasks)
+	}
+
+	if exists, err := second.TaskExists("gr-me01"); err != nil || exists {
+		t.Fatalf("expected separate memory stores to be isolated, exists=%v err=%v", exists, err)
+	}
+	if version, err := currentVersion(first.db); err != nil || version != latestMigrationVersion(sqliteDialect{}) {
+		t.Fatalf("expected memory store at latest schema, got %d (%v)", version, err)
+	}
+}
+
+func TestQueryTimeoutInterruptsLongStatements(t *testing.T) {
+	st, err := OpenWithOptions(filepath.Join(t.TempDir(), "grns.db"), Options{QueryTimeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer st.Close()
+	ctx := context.Background()
+	const countTo = "WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < ?) "
+
+	// An aggregate does all its work in the first step, which SQLite interrupts.
+	var total int64
+	start := time.Now()
+	err = st.db.QueryRowContext(ctx, countTo+"SELECT COUNT(*) FROM n", 1_000_000_000).Scan(&total)
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("expected ErrQueryTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("interrupt took %s", elapsed)
+	}
+
+	// Rows streamed past the deadline fail between steps.
+	rows, err := st.db.QueryContext(ctx, countTo+"SELECT i FROM n", 1_000_000_000)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	for rows.Next() {
+	}
+	if err := rows.Err(); !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("expected ErrQueryTimeout from rows, got %v", err)
+	}
+	rows.Close()
+
+	// The store stays usable, and exempt contexts run to completion.
+	now := time.Now().UTC()
+	task := &models.Task{ID: "gr-qt01", Title: "After timeout", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+		t.Fatalf("create after timeout: %v", err)
+	}
+	if err := st.db.QueryRowContext(WithoutQueryTimeout(ctx), countTo+"SELECT COUNT(*) FROM n", 500_000).Scan(&total); err != nil {
+		t.Fatalf("exempt query: %v", err)
+	}
+	if total != 500_000 {
+		t.Fatalf("expected 500000, got %d", total)
+	}
+
+	// A caller's own cancellation is not reported as a timeout.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := st.db.QueryRowContext(cancelled, "SELECT 1").Scan(&total); err == nil || errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("expected plain cancellation error, got %v", err)
+	}
+}
+
+func TestQueryTimeoutRowsKeepColumnTypes(t *testing.T) {
+	ctx := context.Background()
+	columnTypes := func(opts Options) []string {
+		t.Helper()
+		st, err := OpenWithOptions(filepath.Join(t.TempDir(), "grns.db"), opts)
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		defer st.Close()
+		now := time.Now().UTC()
+		if err := st.CreateTask(ctx, &models.Task{ID: "gr-ct01", Title: "typed", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}, nil, nil); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+		rows, err := st.db.QueryContext(ctx, "SELECT id, priority, created_at FROM tasks")
+		if err != nil {
+			t.Fatalf("query: %v", err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatalf("column types: %v", err)
+		}
+		var got []string
+		for _, columnType := range types {
+			got = append(got, fmt.Sprintf("%s %v", columnType.DatabaseTypeName(), columnType.ScanType()))
+		}
+		if rows.NextResultSet() {
+			t.Fatalf("expected no further result set")
+		}
+		return got
+	}
+
+	plain := columnTypes(Options{})
+	timed := columnTypes(Options{QueryTimeout: time.Minute})
+	if strings.Join(timed, ",") != strings.Join(plain, ",") {
+		t.Fatalf("expected column types %v through the query timeout, got %v", plain, timed)
+	}
+	if strings.HasPrefix(plain[0], " ") {
+		t.Fatalf("expected the driver to report column types, got %v", plain)
+	}
+}
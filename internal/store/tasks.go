@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -13,13 +12,27 @@ import (
 	"grns/internal/models"
 )
 
-const taskColumns = "id, title, status, type, priority, description, spec_id, parent_id, assignee, notes, design, acceptance_criteria, source_repo, created_at, updated_at, closed_at, custom"
-const qualifiedTaskColumns = "tasks.id, tasks.title, tasks.status, tasks.type, tasks.priority, tasks.description, tasks.spec_id, tasks.parent_id, tasks.assignee, tasks.notes, tasks.design, tasks.acceptance_criteria, tasks.source_repo, tasks.created_at, tasks.updated_at, tasks.closed_at, tasks.custom"
+const taskColumns = "id, title, status, type, priority, description, spec_id, parent_id, assignee, notes, design, acceptance_criteria, source_repo, milestone_id, created_at, updated_at, closed_at, deleted_at, merged_into, blocked_reason, blocked_on, custom"
+const qualifiedTaskColumns = "tasks.id, tasks.title, tasks.status, tasks.type, tasks.priority, tasks.description, tasks.spec_id, tasks.parent_id, tasks.assignee, tasks.notes, tasks.design, tasks.acceptance_criteria, tasks.source_repo, tasks.milestone_id, tasks.created_at, tasks.updated_at, tasks.closed_at, tasks.deleted_at, tasks.merged_into, tasks.blocked_reason, tasks.blocked_on, tasks.custom"
 
 var readyStatuses = models.ReadyTaskStatusStrings()
 
 var staleExcludedStatuses = models.StaleDefaultExcludedStatusStrings()
 
+// SortKey orders list results by one column.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// SortFields maps the accepted list sort fields to their columns.
+var SortFields = map[string]string{
+	"priority":   "tasks.priority",
+	"created_at": "tasks.created_at",
+	"updated_at": "tasks.updated_at",
+	"title":      "tasks.title",
+}
+
 type ListFilter struct {
 	Project          string
 	Statuses         []string
@@ -28,8 +41,10 @@ type ListFilter struct {
 	PriorityMin      *int
 	PriorityMax      *int
 	ParentID         string
+	MilestoneID      string
 	Labels           []string
 	LabelsAny        []string
+	DepTypes         []string
 	SpecRegex        string
 	Assignee         string
 	NoAssignee       bool
@@ -45,9 +60,23 @@ type ListFilter struct {
 	ClosedBefore     *time.Time
 	EmptyDescription bool
 	NoLabels         bool
-	SearchQuery      string
-	Limit            int
-	Offset           int
+	// GitRefRelations keeps tasks with a git ref of any of the relations.
+	GitRefRelations []string
+	// HasGitRefs and NoGitRefs keep tasks with at least one or without any git ref.
+	HasGitRefs bool
+	NoGitRefs  bool
+	// Custom matches Task.Custom values by key, compared as text.
+	Custom map[string]string
+	// CustomExists keeps tasks whose Task.Custom holds a non-null value for every key.
+	CustomExists []string
+	// ExcludeTombstones hides tombstoned tasks when Statuses is empty.
+	ExcludeTombstones bool
+	SearchQuery       string
+	Limit             int
+	Offset            int
+	AfterID           string
+	OrderByID         bool
+	Sort              []SortKey
 }
 
 // CreateTask inserts a task with optional labels and dependencies.
@@ -56,55 +85,93 @@ func (s *Store) CreateTask(ctx context.Context, task *models.Task, labels []stri
 }
 
 // CreateTasks inserts multiple tasks in a single transaction.
-func (s *Store) CreateTasks(ctx context.Context, tasks []TaskCreateInput) (err error) {
+func (s *Store) CreateTasks(ctx context.Context, tasks []TaskCreateInput) error {
+	return s.withTx(ctx, func(m *txImportMutator) error {
+		return m.CreateTasks(ctx, tasks)
+	})
+}
+
+func (m *txImportMutator) CreateTasks(ctx context.Context, tasks []TaskCreateInput) error {
 	if len(tasks) == 0 {
 		return nil
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
+	if len(tasks) == 1 {
+		// The common single create reuses the cached statement.
+		if err := insertTaskRow(ctx, m.querier(), tasks[0].Task); err != nil {
+			return err
 		}
-	}()
+	} else {
+		for start := 0; start < len(tasks); start += createTasksChunkSize {
+			end := min(start+createTasksChunkSize, len(tasks))
+			if err := insertTaskRows(ctx, m.tx, tasks[start:end]); err != nil {
+				return err
+			}
+		}
+	}
 
 	for _, create := range tasks {
-		if create.Task == nil {
-			return fmt.Errorf("task is required")
+		if err := insertLabels(ctx, m.tx, create.Task.ID, create.Labels); err != nil {
+			return err
 		}
-		if err = insertTaskRow(ctx, tx, create.Task); err != nil {
+		if err := insertDeps(ctx, m.tx, create.Task.ID, create.Deps); err != nil {
 			return err
 		}
 	}
 
+	return nil
+}
+
+const insertTaskColumns = `id, project_id, title, status, type, priority, description, spec_id, parent_id,
+			assignee, notes, design, acceptance_criteria, source_repo, milestone_id,
+			created_at, updated_at, closed_at, deleted_at, merged_into, blocked_reason, blocked_on, custom`
+
+// insertTaskPlaceholders matches the 23 columns of insertTaskColumns.
+const insertTaskPlaceholders = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+const insertTaskSQL = `INSERT INTO tasks (` + insertTaskColumns + `) VALUES ` + insertTaskPlaceholders
+
+// createTasksChunkSize bounds the rows per multi-row INSERT, keeping a full
+// chunk well under SQLite's bound-parameter limit.
+const createTasksChunkSize = 200
+
+// insertTaskRow inserts one task with the cached single-row statement.
+func insertTaskRow(ctx context.Context, execer interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+}, task *models.Task) error {
+	args, err := taskRowArgs(task)
+	if err != nil {
+		return err
+	}
+	_, err = execer.ExecContext(ctx, insertTaskSQL, args...)
+	return err
+}
+
+// insertTaskRows inserts tasks with one multi-row INSERT.
+func insertTaskRows(ctx context.Context, tx *sql.Tx, tasks []TaskCreateInput) error {
+	placeholders := make([]string, 0, len(tasks))
+	args := make([]any, 0, len(tasks)*23)
 	for _, create := range tasks {
-		if err = insertLabels(ctx, tx, create.Task.ID, create.Labels); err != nil {
-			return err
-		}
-		if err = insertDeps(ctx, tx, create.Task.ID, create.Deps); err != nil {
+		row, err := taskRowArgs(create.Task)
+		if err != nil {
 			return err
 		}
+		placeholders = append(placeholders, insertTaskPlaceholders)
+		args = append(args, row...)
 	}
-
-	return tx.Commit()
+	_, err := tx.ExecContext(ctx, `INSERT INTO tasks (`+insertTaskColumns+`) VALUES `+strings.Join(placeholders, ", "), args...)
+	return err
 }
 
-func insertTaskRow(ctx context.Context, tx *sql.Tx, task *models.Task) error {
+func taskRowArgs(task *models.Task) ([]any, error) {
+	if task == nil {
+		return nil, fmt.Errorf("task is required")
+	}
 	projectID := projectFromTaskID(task.ID)
 	if projectID == "" {
-		return fmt.Errorf("invalid task id")
+		return nil, fmt.Errorf("invalid task id")
 	}
-
-	_, err := tx.ExecContext(ctx, `
-		INSERT INTO tasks (
-			id, project_id, title, status, type, priority, description, spec_id, parent_id,
-			assignee, notes, design, acceptance_criteria, source_repo,
-			created_at, updated_at, closed_at, custom
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`,
+	return []any{
 		task.ID,
 		projectID,
 		task.Title,
@@ -119,24 +186,33 @@ func insertTaskRow(ctx context.Context, tx *sql.Tx, task *models.Task) error {
 		nullIfEmpty(task.Design),
 		nullIfEmpty(task.AcceptanceCriteria),
 		nullIfEmpty(task.SourceRepo),
+		nullIfEmpty(task.MilestoneID),
 		dbFormatTime(task.CreatedAt),
 		dbFormatTime(task.UpdatedAt),
 		nullTime(task.ClosedAt),
+		nullTime(task.DeletedAt),
+		nullIfEmpty(task.MergedInto),
+		nullIfEmpty(task.BlockedReason),
+		nullIfEmpty(task.BlockedOn),
 		customToJSON(task.Custom),
-	)
-	return err
+	}, nil
 }
 
 // GetTask returns a task by id.
 func (s *Store) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	return getTaskQuery(ctx, cachedQuerier{s: s}, id)
+}
+
+const getTaskSQL = `SELECT ` + taskColumns + ` FROM tasks WHERE id = ? AND project_id = ?`
+
+func getTaskQuery(ctx context.Context, querier interface {
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}, id string) (*models.Task, error) {
 	project := projectFromTaskID(id)
 	if project == "" {
 		return nil, nil
 	}
-	row := s.db.QueryRowContext(ctx, `
-		SELECT `+taskColumns+`
-		FROM tasks WHERE id = ? AND project_id = ?
-	`, id, project)
+	row := querier.QueryRowContext(ctx, getTaskSQL, id, project)
 	return scanTask(row)
 }
 
@@ -203,10 +279,30 @@ func updateTaskExec(ctx context.Context, execer interface {
 		set = append(set, "source_repo = ?")
 		args = append(args, nullIfEmpty(*update.SourceRepo))
 	}
+	if update.MilestoneID != nil {
+		set = append(set, "milestone_id = ?")
+		args = append(args, nullIfEmpty(*update.MilestoneID))
+	}
 	if update.ClosedAt != nil {
 		set = append(set, "closed_at = ?")
 		args = append(args, nullTime(update.ClosedAt))
 	}
+	if update.DeletedAt != nil {
+		set = append(set, "deleted_at = ?")
+		args = append(args, nullTime(update.DeletedAt))
+	}
+	if update.MergedInto != nil {
+		set = append(set, "merged_into = ?")
+		args = append(args, nullIfEmpty(*update.MergedInto))
+	}
+	if update.BlockedReason != nil {
+		set = append(set, "blocked_reason = ?")
+		args = append(args, nullIfEmpty(*update.BlockedReason))
+	}
+	if update.BlockedOn != nil {
+		set = append(set, "blocked_on = ?")
+		args = append(args, nullIfEmpty(*update.BlockedOn))
+	}
 	if update.Custom != nil {
 		set = append(set, "custom = ?")
 		args = append(args, customToJSON(*update.Custom))
@@ -228,7 +324,7 @@ func updateTaskExec(ctx context.Context, execer interface {
 
 // ListTasks returns tasks matching the provided filter.
 func (s *Store) ListTasks(ctx context.Context, filter ListFilter) ([]models.Task, error) {
-	query, args := buildListQuery(filter)
+	query, args := buildListQuery(s.dialect, filter)
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -236,10 +332,6 @@ func (s *Store) ListTasks(ctx context.Context, filter ListFilter) ([]models.Task
 	}
 	defer rows.Close()
 
-	if filter.SpecRegex != "" {
-		return filterRowsBySpecRegex(rows, filter.SpecRegex, filter.Limit, filter.Offset)
-	}
-
 	var tasks []models.Task
 	for rows.Next() {
 		task, err := scanTask(rows)
@@ -255,25 +347,105 @@ func (s *Store) ListTasks(ctx context.Context, filter ListFilter) ([]models.Task
 	return tasks, nil
 }
 
-// ListReadyTasks returns tasks with no open blockers.
-func (s *Store) ListReadyTasks(ctx context.Context, project string, limit int) ([]models.Task, error) {
-	project = normalizeProject(project)
-	args := make([]any, 0, len(readyStatuses)*2+4)
-	query := fmt.Sprintf(`
-		SELECT `+taskColumns+`
-		FROM tasks t
-		WHERE t.project_id = ?
+// CountTasks returns how many tasks match filter, ignoring limit, offset, and after_id.
+func (s *Store) CountTasks(ctx context.Context, filter ListFilter) (int, error) {
+	filter.AfterID = ""
+	query, args := buildCountQuery(s.dialect, filter)
+	var count int
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+// ReadyOrder selects how ListReadyTasks orders its results.
+type ReadyOrder string
+
+const (
+	// ReadyOrderUpdated lists the most recently updated tasks first (the default).
+	ReadyOrderUpdated ReadyOrder = "updated_at"
+	// ReadyOrderPriority lists the highest priority first, oldest first within a priority.
+	ReadyOrderPriority ReadyOrder = "priority"
+	// ReadyOrderScore lists the highest readyScore first.
+	ReadyOrderScore ReadyOrder = "score"
+)
+
+// Ready score weights: each priority step above the lowest, each day of age
+// (capped), and each open task the candidate blocks.
+const (
+	readyScorePriorityWeight  = 10
+	readyScoreMaxAgeDays      = 30
+	readyScoreDependentWeight = 5
+)
+
+// readyScoreExpr scores a ready task t. Callers append the score args from
+// readyScoreArgs, in that order.
+func readyScoreExpr(d dialect) string {
+	return fmt.Sprintf(`(
+			(? - t.priority) * ?
+			+ %s
+			+ ? * (
+				SELECT COUNT(*) FROM task_deps dd
+				JOIN tasks c ON c.id = dd.child_id
+				WHERE dd.parent_id = t.id AND dd.type = ? AND c.status IN (%s)
+			)
+		)`, d.clamp("julianday(?) - julianday(t.created_at)", "0", "?"), placeholders(len(readyStatuses)))
+}
+
+func readyScoreArgs(now time.Time) []any {
+	args := []any{
+		models.PriorityMax, readyScorePriorityWeight,
+		dbFormatTime(now), readyScoreMaxAgeDays,
+		readyScoreDependentWeight, string(models.DependencyBlocks),
+	}
+	for _, status := range readyStatuses {
+		args = append(args, status)
+	}
+	return args
+}
+
+// ListReadyTasks returns tasks with no open blockers in the requested order.
+// An empty order means ReadyOrderUpdated.
+func (s *Store) ListReadyTasks(ctx context.Context, project string, order ReadyOrder, limit int) ([]models.Task, error) {
+	where, args := readyTasksWhere(normalizeProject(project))
+	orderBy, orderArgs, err := readyTasksOrderBy(s.dialect, order, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	query := "SELECT " + taskColumns + " FROM tasks t WHERE " + where + " ORDER BY " + orderBy
+	args = append(args, orderArgs...)
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, rows.Err()
+}
+
+// readyTasksWhere matches tasks t in project that sit in a ready status with
+// no open blockers.
+func readyTasksWhere(project string) (string, []any) {
+	where := fmt.Sprintf(`t.project_id = ?
 		AND t.status IN (%s)
 		AND NOT EXISTS (
 			SELECT 1 FROM task_deps d
 			JOIN tasks p ON p.id = d.parent_id
 			WHERE d.child_id = t.id
-			AND p.project_id = t.project_id
-			AND d.type = ?
-			AND p.status IN (%s)
-		)
-		ORDER BY updated_at DESC
-	`, placeholders(len(readyStatuses)), placeholders(len(readyStatuses)))
+			AND %s
+		)`, placeholders(len(readyStatuses)), openBlockerPredicate())
+	args := make([]any, 0, len(readyStatuses)*2+2)
 	args = append(args, project)
 	for _, status := range readyStatuses {
 		args = append(args, status)
@@ -282,9 +454,92 @@ func (s *Store) ListReadyTasks(ctx context.Context, project string, limit int) (
 	for _, status := range readyStatuses {
 		args = append(args, status)
 	}
-	if limit > 0 {
-		query += " LIMIT ?"
-		args = append(args, limit)
+	return where, args
+}
+
+// readyTasksOrderBy returns the ORDER BY clause for a ready order.
+func readyTasksOrderBy(d dialect, order ReadyOrder, now time.Time) (string, []any, error) {
+	switch order {
+	case ReadyOrderPriority:
+		return "t.priority ASC, t.created_at ASC, t.id ASC", nil, nil
+	case ReadyOrderScore:
+		return readyScoreExpr(d) + " DESC, t.priority ASC, t.created_at ASC, t.id ASC", readyScoreArgs(now), nil
+	case "", ReadyOrderUpdated:
+		return "t.updated_at DESC", nil, nil
+	default:
+		return "", nil, fmt.Errorf("unknown ready order %q", order)
+	}
+}
+
+// openBlockerPredicate matches task_deps rows d whose parent p still blocks child t.
+// Parents in other projects block too. Callers append the blocks type and
+// readyStatuses as args, in that order.
+func openBlockerPredicate() string {
+	return fmt.Sprintf("d.type = ? AND p.status IN (%s)", placeholders(len(readyStatuses)))
+}
+
+// SearchHit describes why a task matched a full-text query.
+type SearchHit struct {
+	Snippet string
+	Rank    float64
+}
+
+// ListSearchHits returns the best-matching snippet and search rank of each task id for
+// query in one query. Lower ranks are better matches; ids that do not match are omitted.
+func (s *Store) ListSearchHits(ctx context.Context, query string, ids []string) (map[string]SearchHit, error) {
+	hits := make(map[string]SearchHit)
+	if len(ids) == 0 {
+		return hits, nil
+	}
+
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, query)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx, s.dialect.searchHitsQuery(len(ids)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id  string
+			hit SearchHit
+		)
+		if err := rows.Scan(&id, &hit.Snippet, &hit.Rank); err != nil {
+			return nil, err
+		}
+		hits[id] = hit
+	}
+	return hits, rows.Err()
+}
+
+// ListOpenBlockerCounts returns the number of open blockers for each task id in one query.
+// Tasks without open blockers are omitted from the map.
+func (s *Store) ListOpenBlockerCounts(ctx context.Context, ids []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT d.child_id, COUNT(*)
+		FROM task_deps d
+		JOIN tasks t ON t.id = d.child_id
+		JOIN tasks p ON p.id = d.parent_id
+		WHERE d.child_id IN (%s)
+		AND %s
+		GROUP BY d.child_id
+	`, placeholders(len(ids)), openBlockerPredicate())
+	args := make([]any, 0, len(ids)+len(readyStatuses)+1)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, string(models.DependencyBlocks))
+	for _, status := range readyStatuses {
+		args = append(args, status)
 	}
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
@@ -293,15 +548,17 @@ func (s *Store) ListReadyTasks(ctx context.Context, project string, limit int) (
 	}
 	defer rows.Close()
 
-	var tasks []models.Task
 	for rows.Next() {
-		task, err := scanTask(rows)
-		if err != nil {
+		var (
+			childID string
+			count   int
+		)
+		if err := rows.Scan(&childID, &count); err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, *task)
+		counts[childID] = count
 	}
-	return tasks, rows.Err()
+	return counts, rows.Err()
 }
 
 // ListStaleTasks returns tasks not updated since cutoff.
@@ -356,27 +613,134 @@ func (s *Store) AddLabels(ctx context.Context, id string, labels []string) error
 	if len(labels) == 0 {
 		return nil
 	}
-	_, err := s.db.ExecContext(ctx, "INSERT OR IGNORE INTO task_labels (task_id, label) VALUES "+labelValues(len(labels)), labelArgs(id, labels)...)
+	_, err := s.db.ExecContext(ctx, addLabelsQuery(len(labels)), labelArgs(id, labels)...)
 	return err
 }
 
+func (m *txImportMutator) AddLabels(ctx context.Context, id string, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	_, err := m.tx.ExecContext(ctx, addLabelsQuery(len(labels)), labelArgs(id, labels)...)
+	return err
+}
+
+func addLabelsQuery(count int) string {
+	return "INSERT OR IGNORE INTO task_labels (task_id, label) VALUES " + labelValues(count)
+}
+
 // RemoveLabels removes labels from a task.
 func (s *Store) RemoveLabels(ctx context.Context, id string, labels []string) error {
 	if len(labels) == 0 {
 		return nil
 	}
+	_, err := s.db.ExecContext(ctx, removeLabelsQuery(len(labels)), removeLabelsArgs(id, labels)...)
+	return err
+}
+
+func (m *txImportMutator) RemoveLabels(ctx context.Context, id string, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	_, err := m.tx.ExecContext(ctx, removeLabelsQuery(len(labels)), removeLabelsArgs(id, labels)...)
+	return err
+}
+
+func removeLabelsQuery(count int) string {
+	return fmt.Sprintf("DELETE FROM task_labels WHERE task_id = ? AND label IN (%s)", placeholders(count))
+}
+
+func removeLabelsArgs(id string, labels []string) []any {
 	args := []any{id}
 	for _, label := range labels {
 		args = append(args, label)
 	}
-	query := fmt.Sprintf("DELETE FROM task_labels WHERE task_id = ? AND label IN (%s)", placeholders(len(labels)))
-	_, err := s.db.ExecContext(ctx, query, args...)
+	return args
+}
+
+// RenameLabel replaces label from with to on every task in project, or in all projects
+// when project is empty, in one transaction. Tasks that already carry to keep a single
+// row. It returns the affected task ids.
+func (s *Store) RenameLabel(ctx context.Context, project, from, to string) (ids []string, err error) {
+	err = s.withTx(ctx, func(m *txImportMutator) error {
+		ids, err = m.RenameLabel(ctx, project, from, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (m *txImportMutator) RenameLabel(ctx context.Context, project, from, to string) ([]string, error) {
+	project = normalizeProject(project)
+	ids, err := labeledTaskIDs(ctx, m.tx, project, from)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO task_labels (task_id, label)
+		SELECT l.task_id, ? FROM task_labels l
+		JOIN tasks t ON t.id = l.task_id
+		WHERE l.label = ? AND (? = '' OR t.project_id = ?)`, to, from, project, project); err != nil {
+		return nil, err
+	}
+	if err := deleteLabelExec(ctx, m.tx, project, from); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// DeleteLabel removes label from every task in project and returns the affected task ids.
+func (s *Store) DeleteLabel(ctx context.Context, project, label string) (ids []string, err error) {
+	err = s.withTx(ctx, func(m *txImportMutator) error {
+		ids, err = m.DeleteLabel(ctx, project, label)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (m *txImportMutator) DeleteLabel(ctx context.Context, project, label string) ([]string, error) {
+	project = normalizeProject(project)
+	ids, err := labeledTaskIDs(ctx, m.tx, project, label)
+	if err != nil {
+		return nil, err
+	}
+	if err := deleteLabelExec(ctx, m.tx, project, label); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func labeledTaskIDs(ctx context.Context, tx *sql.Tx, project, label string) ([]string, error) {
+	return queryIDs(ctx, tx, `
+		SELECT l.task_id FROM task_labels l
+		JOIN tasks t ON t.id = l.task_id
+		WHERE l.label = ? AND (? = '' OR t.project_id = ?)
+		ORDER BY l.task_id`, label, project, project)
+}
+
+func deleteLabelExec(ctx context.Context, tx *sql.Tx, project, label string) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM task_labels
+		WHERE label = ? AND task_id IN (SELECT id FROM tasks WHERE ? = '' OR project_id = ?)`, label, project, project)
 	return err
 }
 
 // ListLabels returns labels for a task.
 func (s *Store) ListLabels(ctx context.Context, id string) ([]string, error) {
-	rows, err := s.db.QueryContext(ctx, "SELECT label FROM task_labels WHERE task_id = ? ORDER BY label ASC", id)
+	return listLabelsQuery(ctx, cachedQuerier{s: s}, id)
+}
+
+const listLabelsSQL = "SELECT label FROM task_labels WHERE task_id = ? ORDER BY label ASC"
+
+func listLabelsQuery(ctx context.Context, querier interface {
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+}, id string) ([]string, error) {
+	rows, err := querier.QueryContext(ctx, listLabelsSQL, id)
 	if err != nil {
 		return nil, err
 	}
@@ -419,6 +783,51 @@ func (s *Store) ListAllLabels(ctx context.Context, project string) ([]string, er
 	return labels, rows.Err()
 }
 
+// RelatedLabel is a label that co-occurs with another label on the same tasks.
+type RelatedLabel struct {
+	Label string
+	Count int
+}
+
+// LabelCooccurrence returns labels ranked by how many tasks in project carry both them and label.
+// The input label itself is excluded. A non-positive limit returns all related labels.
+func (s *Store) LabelCooccurrence(ctx context.Context, project, label string, limit, offset int) ([]RelatedLabel, error) {
+	project = normalizeProject(project)
+	query := `
+		SELECT o.label, COUNT(*) AS shared
+		FROM task_labels l
+		JOIN task_labels o ON o.task_id = l.task_id AND o.label != l.label
+		JOIN tasks t ON t.id = l.task_id
+		WHERE t.project_id = ? AND l.label = ?
+		GROUP BY o.label
+		ORDER BY shared DESC, o.label ASC`
+	args := []any{project, label}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+		if offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, offset)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []RelatedLabel{}
+	for rows.Next() {
+		var item RelatedLabel
+		if err := rows.Scan(&item.Label, &item.Count); err != nil {
+			return nil, err
+		}
+		related = append(related, item)
+	}
+	return related, rows.Err()
+}
+
 // AddDependency adds a dependency edge between tasks.
 func (s *Store) AddDependency(ctx context.Context, childID, parentID, depType string) error {
 	return addDependencyExec(ctx, s.db, childID, parentID, depType)
@@ -427,16 +836,15 @@ func (s *Store) AddDependency(ctx context.Context, childID, parentID, depType st
 func addDependencyExec(ctx context.Context, execer interface {
 	ExecContext(context.Context, string, ...any) (sql.Result, error)
 }, childID, parentID, depType string) error {
-	if !sameTaskProject(childID, parentID) {
-		return ErrProjectMismatch
-	}
 	_, err := execer.ExecContext(ctx, "INSERT OR IGNORE INTO task_deps (child_id, parent_id, type) VALUES (?, ?, ?)", childID, parentID, depType)
 	return err
 }
 
+const listDependenciesSQL = "SELECT parent_id, type FROM task_deps WHERE child_id = ? ORDER BY parent_id"
+
 // ListDependencies returns dependencies where the task is the child.
 func (s *Store) ListDependencies(ctx context.Context, id string) ([]models.Dependency, error) {
-	rows, err := s.db.QueryContext(ctx, "SELECT parent_id, type FROM task_deps WHERE child_id = ? ORDER BY parent_id", id)
+	rows, err := cachedQuerier{s: s}.QueryContext(ctx, listDependenciesSQL, id)
 	if err != nil {
 		return nil, err
 	}
@@ -486,6 +894,44 @@ func (s *Store) ListLabelsForTasks(ctx context.Context, ids []string) (map[strin
 	return labels, rows.Err()
 }
 
+// ChildStatusCountsForTasks counts the non-tombstoned children (tasks whose
+// parent_id names the task) of each id by status. Tasks without children are absent.
+func (s *Store) ChildStatusCountsForTasks(ctx context.Context, ids []string) (map[string]map[string]int, error) {
+	counts := make(map[string]map[string]int)
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT parent_id, status, COUNT(*) FROM tasks
+		WHERE parent_id IN (%s) AND status != ?
+		GROUP BY parent_id, status
+	`, placeholders(len(ids)))
+	args := make([]any, 0, len(ids)+1)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, string(models.StatusTombstone))
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentID, status string
+		var count int
+		if err := rows.Scan(&parentID, &status, &count); err != nil {
+			return nil, err
+		}
+		if counts[parentID] == nil {
+			counts[parentID] = map[string]int{}
+		}
+		counts[parentID][status] = count
+	}
+	return counts, rows.Err()
+}
+
 // ListDependenciesForTasks returns dependencies keyed by child task id.
 func (s *Store) ListDependenciesForTasks(ctx context.Context, ids []string) (map[string][]models.Dependency, error) {
 	deps := make(map[string][]models.Dependency)
@@ -514,6 +960,34 @@ func (s *Store) ListDependenciesForTasks(ctx context.Context, ids []string) (map
 	return deps, rows.Err()
 }
 
+// ListDependentsForTasks returns child task ids keyed by parent task id.
+func (s *Store) ListDependentsForTasks(ctx context.Context, ids []string) (map[string][]string, error) {
+	dependents := make(map[string][]string)
+	if len(ids) == 0 {
+		return dependents, nil
+	}
+
+	query := fmt.Sprintf("SELECT parent_id, child_id FROM task_deps WHERE parent_id IN (%s) ORDER BY parent_id, child_id", placeholders(len(ids)))
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentID, childID string
+		if err := rows.Scan(&parentID, &childID); err != nil {
+			return nil, err
+		}
+		dependents[parentID] = append(dependents[parentID], childID)
+	}
+	return dependents, rows.Err()
+}
+
 // ReplaceLabels replaces all labels for a task.
 func (s *Store) ReplaceLabels(ctx context.Context, id string, labels []string) error {
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -554,25 +1028,67 @@ func removeDependenciesExec(ctx context.Context, execer interface {
 	return err
 }
 
+// RemoveDependency removes one dependency edge.
+func (s *Store) RemoveDependency(ctx context.Context, childID, parentID, depType string) error {
+	return removeDependencyExec(ctx, s.db, childID, parentID, depType)
+}
+
+func removeDependencyExec(ctx context.Context, execer interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+}, childID, parentID, depType string) error {
+	_, err := execer.ExecContext(ctx, "DELETE FROM task_deps WHERE child_id = ? AND parent_id = ? AND type = ?", childID, parentID, depType)
+	return err
+}
+
 // CloseTasks closes tasks and sets closed_at.
-func (s *Store) CloseTasks(ctx context.Context, project string, ids []string, closedAt time.Time) (err error) {
+func (s *Store) CloseTasks(ctx context.Context, project string, ids []string, closedAt time.Time) error {
+	return s.withTx(ctx, func(m *txImportMutator) error {
+		return m.CloseTasks(ctx, project, ids, closedAt)
+	})
+}
+
+func (m *txImportMutator) CloseTasks(ctx context.Context, project string, ids []string, closedAt time.Time) error {
 	project = normalizeProject(project)
 	ids = uniqueStrings(ids)
 	if len(ids) == 0 {
 		return nil
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	existsCount, err := countExistingTasksInProject(ctx, m.tx, project, ids)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
+	if existsCount != len(ids) {
+		return ErrTaskNotFound
+	}
 
-	existsCount, err := countExistingTasksInProject(ctx, tx, project, ids)
+	args := []any{string(models.StatusClosed), dbFormatTime(closedAt), dbFormatTime(closedAt), project}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query := fmt.Sprintf("UPDATE tasks SET status = ?, closed_at = ?, blocked_reason = NULL, blocked_on = NULL, updated_at = ? WHERE project_id = ? AND id IN (%s)", placeholders(len(ids)))
+	if _, err := m.tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TouchTasks bumps updated_at for tasks without other changes.
+// It is all-or-nothing: ErrTaskNotFound is returned if any id is missing from project.
+func (s *Store) TouchTasks(ctx context.Context, project string, ids []string, now time.Time) error {
+	return s.withTx(ctx, func(m *txImportMutator) error {
+		return m.TouchTasks(ctx, project, ids, now)
+	})
+}
+
+func (m *txImportMutator) TouchTasks(ctx context.Context, project string, ids []string, now time.Time) error {
+	project = normalizeProject(project)
+	ids = uniqueStrings(ids)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	existsCount, err := countExistingTasksInProject(ctx, m.tx, project, ids)
 	if err != nil {
 		return err
 	}
@@ -580,36 +1096,65 @@ func (s *Store) CloseTasks(ctx context.Context, project string, ids []string, cl
 		return ErrTaskNotFound
 	}
 
-	args := []any{string(models.StatusClosed), dbFormatTime(closedAt), dbFormatTime(closedAt), project}
+	args := []any{dbFormatTime(now), project}
 	for _, id := range ids {
 		args = append(args, id)
 	}
-	query := fmt.Sprintf("UPDATE tasks SET status = ?, closed_at = ?, updated_at = ? WHERE project_id = ? AND id IN (%s)", placeholders(len(ids)))
-	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+	query := fmt.Sprintf("UPDATE tasks SET updated_at = ? WHERE project_id = ? AND id IN (%s)", placeholders(len(ids)))
+	if _, err := m.tx.ExecContext(ctx, query, args...); err != nil {
 		return err
 	}
-	return tx.Commit()
+	return nil
 }
 
-// ReopenTasks reopens tasks and clears closed_at.
-func (s *Store) ReopenTasks(ctx context.Context, project string, ids []string, reopenedAt time.Time) (err error) {
+// UpdateTasks applies one update to every task in ids within a single transaction.
+// It returns ErrTaskNotFound without changing anything if any id is missing from project.
+// Each task is checked against limits before it is updated, as in
+// UpdateTaskWithinWIPLimits, so tasks updated earlier in ids count toward them.
+func (s *Store) UpdateTasks(ctx context.Context, project string, ids []string, update TaskUpdate, limits []WIPLimit) error {
+	return s.withTx(ctx, func(m *txImportMutator) error {
+		return m.UpdateTasks(ctx, project, ids, update, limits)
+	})
+}
+
+func (m *txImportMutator) UpdateTasks(ctx context.Context, project string, ids []string, update TaskUpdate, limits []WIPLimit) error {
 	project = normalizeProject(project)
 	ids = uniqueStrings(ids)
 	if len(ids) == 0 {
 		return nil
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	existsCount, err := countExistingTasksInProject(ctx, m.tx, project, ids)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
+	if existsCount != len(ids) {
+		return ErrTaskNotFound
+	}
+
+	for _, id := range ids {
+		if err := updateTaskWithinWIPLimitsTx(ctx, m.tx, m.dialect, project, id, update, limits); err != nil {
+			return err
 		}
-	}()
+	}
+	return nil
+}
+
+// ReopenTasks reopens tasks and clears closed_at.
+func (s *Store) ReopenTasks(ctx context.Context, project string, ids []string, reopenedAt time.Time) error {
+	return s.withTx(ctx, func(m *txImportMutator) error {
+		return m.ReopenTasks(ctx, project, ids, reopenedAt)
+	})
+}
+
+func (m *txImportMutator) ReopenTasks(ctx context.Context, project string, ids []string, reopenedAt time.Time) error {
+	project = normalizeProject(project)
+	ids = uniqueStrings(ids)
+	if len(ids) == 0 {
+		return nil
+	}
 
-	existsCount, err := countExistingTasksInProject(ctx, tx, project, ids)
+	existsCount, err := countExistingTasksInProject(ctx, m.tx, project, ids)
 	if err != nil {
 		return err
 	}
@@ -621,11 +1166,92 @@ func (s *Store) ReopenTasks(ctx context.Context, project string, ids []string, r
 	for _, id := range ids {
 		args = append(args, id)
 	}
-	query := fmt.Sprintf("UPDATE tasks SET status = ?, closed_at = NULL, updated_at = ? WHERE project_id = ? AND id IN (%s)", placeholders(len(ids)))
-	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+	query := fmt.Sprintf("UPDATE tasks SET status = ?, closed_at = NULL, blocked_reason = NULL, blocked_on = NULL, updated_at = ? WHERE project_id = ? AND id IN (%s)", placeholders(len(ids)))
+	if _, err := m.tx.ExecContext(ctx, query, args...); err != nil {
 		return err
 	}
-	return tx.Commit()
+	return nil
+}
+
+// DeleteTasks tombstones tasks and sets deleted_at. closed_at is kept so a
+// restore can return closed tasks to closed.
+func (s *Store) DeleteTasks(ctx context.Context, project string, ids []string, deletedAt time.Time) error {
+	return s.withTx(ctx, func(m *txImportMutator) error {
+		return m.DeleteTasks(ctx, project, ids, deletedAt)
+	})
+}
+
+func (m *txImportMutator) DeleteTasks(ctx context.Context, project string, ids []string, deletedAt time.Time) error {
+	project = normalizeProject(project)
+	ids = uniqueStrings(ids)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	existsCount, err := countExistingTasksInProject(ctx, m.tx, project, ids)
+	if err != nil {
+		return err
+	}
+	if existsCount != len(ids) {
+		return ErrTaskNotFound
+	}
+
+	args := []any{string(models.StatusTombstone), dbFormatTime(deletedAt), dbFormatTime(deletedAt), project}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query := fmt.Sprintf("UPDATE tasks SET status = ?, deleted_at = ?, updated_at = ? WHERE project_id = ? AND id IN (%s)", placeholders(len(ids)))
+	if _, err := m.tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestoreTasks brings tombstoned tasks back: tasks with closed_at return to
+// closed, others to open. Every id must name a tombstoned task in project.
+func (s *Store) RestoreTasks(ctx context.Context, project string, ids []string, restoredAt time.Time) error {
+	return s.withTx(ctx, func(m *txImportMutator) error {
+		return m.RestoreTasks(ctx, project, ids, restoredAt)
+	})
+}
+
+func (m *txImportMutator) RestoreTasks(ctx context.Context, project string, ids []string, restoredAt time.Time) error {
+	project = normalizeProject(project)
+	ids = uniqueStrings(ids)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	existsCount, err := countExistingTasksInProject(ctx, m.tx, project, ids)
+	if err != nil {
+		return err
+	}
+	if existsCount != len(ids) {
+		return ErrTaskNotFound
+	}
+
+	args := []any{string(models.StatusClosed), string(models.StatusOpen), dbFormatTime(restoredAt), project}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, string(models.StatusTombstone))
+	query := fmt.Sprintf(`
+		UPDATE tasks
+		SET status = CASE WHEN closed_at IS NOT NULL THEN ? ELSE ? END, deleted_at = NULL, merged_into = NULL, blocked_reason = NULL, blocked_on = NULL, updated_at = ?
+		WHERE project_id = ? AND id IN (%s) AND status = ?
+	`, placeholders(len(ids)))
+	result, err := m.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	restored, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(restored) != len(ids) {
+		return ErrTaskNotTombstoned
+	}
+	return nil
 }
 
 // TaskUpdate describes fields to update.
@@ -642,51 +1268,24 @@ type TaskUpdate struct {
 	Design             *string
 	AcceptanceCriteria *string
 	SourceRepo         *string
+	MilestoneID        *string
 	ClosedAt           *time.Time
+	DeletedAt          *time.Time
+	MergedInto         *string
+	BlockedReason      *string
+	BlockedOn          *string
 	Custom             *map[string]any
 	UpdatedAt          time.Time
 }
 
-func filterRowsBySpecRegex(rows *sql.Rows, pattern string, limit, offset int) ([]models.Task, error) {
-	re, err := regexp.Compile(pattern)
-	if err != nil {
-		return nil, err
-	}
-
-	filtered := []models.Task{}
-	skipped := 0
-	for rows.Next() {
-		task, err := scanTask(rows)
-		if err != nil {
-			return nil, err
-		}
-		if task.SpecID == "" || !re.MatchString(task.SpecID) {
-			continue
-		}
-		if skipped < offset {
-			skipped++
-			continue
-		}
-		filtered = append(filtered, *task)
-		if limit > 0 && len(filtered) >= limit {
-			break
-		}
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return filtered, nil
-}
-
 func scanTask(scanner interface {
 	Scan(dest ...any) error
 }) (*models.Task, error) {
 	var task models.Task
 	var description, specID, parentID sql.NullString
-	var assignee, notes, design, acceptanceCriteria, sourceRepo sql.NullString
+	var assignee, notes, design, acceptanceCriteria, sourceRepo, milestoneID sql.NullString
 	var createdAt, updatedAt string
-	var closedAt, customJSON sql.NullString
+	var closedAt, deletedAt, mergedInto, blockedReason, blockedOn, customJSON sql.NullString
 
 	if err := scanner.Scan(
 		&task.ID,
@@ -702,9 +1301,14 @@ func scanTask(scanner interface {
 		&design,
 		&acceptanceCriteria,
 		&sourceRepo,
+		&milestoneID,
 		&createdAt,
 		&updatedAt,
 		&closedAt,
+		&deletedAt,
+		&mergedInto,
+		&blockedReason,
+		&blockedOn,
 		&customJSON,
 	); err != nil {
 		if err == sql.ErrNoRows {
@@ -722,6 +1326,10 @@ func scanTask(scanner interface {
 	task.Design = design.String
 	task.AcceptanceCriteria = acceptanceCriteria.String
 	task.SourceRepo = sourceRepo.String
+	task.MilestoneID = milestoneID.String
+	task.MergedInto = mergedInto.String
+	task.BlockedReason = blockedReason.String
+	task.BlockedOn = blockedOn.String
 
 	parsedCreated, err := dbParseTime(createdAt)
 	if err != nil {
@@ -740,6 +1348,13 @@ func scanTask(scanner interface {
 		}
 		task.ClosedAt = &parsedClosed
 	}
+	if deletedAt.Valid {
+		parsedDeleted, err := dbParseTime(deletedAt.String)
+		if err != nil {
+			return nil, err
+		}
+		task.DeletedAt = &parsedDeleted
+	}
 	if customJSON.Valid && customJSON.String != "" {
 		if err := json.Unmarshal([]byte(customJSON.String), &task.Custom); err != nil {
 			return nil, fmt.Errorf("parse custom JSON: %w", err)
@@ -818,12 +1433,6 @@ func normalizeProject(project string) string {
 	return project
 }
 
-func sameTaskProject(childID, parentID string) bool {
-	childProject := projectFromTaskID(childID)
-	parentProject := projectFromTaskID(parentID)
-	return childProject != "" && childProject == parentProject
-}
-
 func nullIfEmpty(value string) any {
 	if value == "" {
 		return nil
@@ -860,6 +1469,17 @@ func dbParseTime(value string) (time.Time, error) {
 	return time.Parse(time.RFC3339Nano, value)
 }
 
+func dbParseNullTime(value sql.NullString) (*time.Time, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	parsed, err := dbParseTime(value.String)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
 func labelValues(count int) string {
 	values := make([]string, count)
 	for i := 0; i < count; i++ {
@@ -884,9 +1504,30 @@ func insertLabels(ctx context.Context, tx *sql.Tx, id string, labels []string) e
 	return err
 }
 
-// DependencyTree returns the full dependency graph for a task in one project.
-func (s *Store) DependencyTree(ctx context.Context, project string, id string) ([]models.DepTreeNode, error) {
-	project = normalizeProject(project)
+// IsTaskDescendant reports whether candidateID is in the parent_id subtree below ancestorID.
+// UNION (not UNION ALL) keeps the walk finite even if a cycle already exists.
+func (s *Store) IsTaskDescendant(ctx context.Context, ancestorID, candidateID string) (bool, error) {
+	var found int
+	err := s.db.QueryRowContext(ctx, `
+		WITH RECURSIVE subtree(id) AS (
+			SELECT id FROM tasks WHERE parent_id = ?
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree st ON t.parent_id = st.id
+		)
+		SELECT 1 FROM subtree WHERE id = ? LIMIT 1
+	`, ancestorID, candidateID).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DependencyTree returns the full dependency graph for a task, following edges
+// into other projects.
+func (s *Store) DependencyTree(ctx context.Context, id string) ([]models.DepTreeNode, error) {
 	query := fmt.Sprintf(`
 		WITH RECURSIVE
 		upstream(id, depth, dep_type, path) AS (
@@ -897,7 +1538,7 @@ func (s *Store) DependencyTree(ctx context.Context, project string, id string) (
 			FROM task_deps d
 			JOIN upstream u ON d.child_id = u.id
 			WHERE u.depth < %d
-			AND INSTR(u.path, ',' || d.parent_id || ',') = 0
+			AND u.path NOT LIKE '%%,' || d.parent_id || ',%%'
 		),
 		downstream(id, depth, dep_type, path) AS (
 			SELECT child_id, 1, type, ',' || ? || ',' || child_id || ','
@@ -907,19 +1548,19 @@ func (s *Store) DependencyTree(ctx context.Context, project string, id string) (
 			FROM task_deps d
 			JOIN downstream dn ON d.parent_id = dn.id
 			WHERE dn.depth < %d
-			AND INSTR(dn.path, ',' || d.child_id || ',') = 0
+			AND dn.path NOT LIKE '%%,' || d.child_id || ',%%'
 		)
 		SELECT t.id, t.title, t.status, t.type, u.depth, 'upstream' AS direction, u.dep_type
 		FROM upstream u
-		JOIN tasks t ON t.id = u.id AND t.project_id = ?
+		JOIN tasks t ON t.id = u.id
 		UNION ALL
 		SELECT t.id, t.title, t.status, t.type, d.depth, 'downstream' AS direction, d.dep_type
 		FROM downstream d
-		JOIN tasks t ON t.id = d.id AND t.project_id = ?
+		JOIN tasks t ON t.id = d.id
 		ORDER BY 6, 5, 1
 	`, models.DependencyTreeMaxDepth, models.DependencyTreeMaxDepth)
 
-	rows, err := s.db.QueryContext(ctx, query, id, id, id, id, project, project)
+	rows, err := s.db.QueryContext(ctx, query, id, id, id, id)
 	if err != nil {
 		return nil, err
 	}
@@ -931,14 +1572,19 @@ func (s *Store) DependencyTree(ctx context.Context, project string, id string) (
 		if err := rows.Scan(&node.ID, &node.Title, &node.Status, &node.Type, &node.Depth, &node.Direction, &node.DepType); err != nil {
 			return nil, err
 		}
+		node.Project = projectFromTaskID(node.ID)
 		nodes = append(nodes, node)
 	}
 	return nodes, rows.Err()
 }
 
-// StoreInfo returns metadata about the database.
-func (s *Store) StoreInfo(ctx context.Context) (*StoreInfo, error) {
+// StoreInfo returns metadata about the database. A non-empty project restricts
+// task counts to that project; otherwise counts span all projects and Projects
+// lists every project that owns tasks.
+func (s *Store) StoreInfo(ctx context.Context, project string) (*StoreInfo, error) {
+	project = normalizeProject(project)
 	info := &StoreInfo{
+		Project:    project,
 		TaskCounts: make(map[string]int),
 	}
 
@@ -948,7 +1594,15 @@ func (s *Store) StoreInfo(ctx context.Context) (*StoreInfo, error) {
 	}
 	info.SchemaVersion = version
 
-	rows, err := s.db.QueryContext(ctx, "SELECT status, COUNT(*) FROM tasks GROUP BY status")
+	if project == "" {
+		projects, err := s.listTaskProjects(ctx)
+		if err != nil {
+			return nil, err
+		}
+		info.Projects = projects
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT status, COUNT(*) FROM tasks WHERE ? = '' OR project_id = ? GROUP BY status", project, project)
 	if err != nil {
 		return nil, err
 	}
@@ -969,19 +1623,46 @@ func (s *Store) StoreInfo(ctx context.Context) (*StoreInfo, error) {
 	return info, rows.Err()
 }
 
+func (s *Store) listTaskProjects(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT project_id FROM tasks WHERE project_id IS NOT NULL ORDER BY project_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []string
+	for rows.Next() {
+		var project string
+		if err := rows.Scan(&project); err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	return projects, rows.Err()
+}
+
 // CleanupClosedTasks removes (or reports) closed tasks older than cutoff.
 func (s *Store) CleanupClosedTasks(ctx context.Context, project string, cutoff time.Time, dryRun bool) (*CleanupResult, error) {
+	return s.removeTasksWhere(ctx, project, "status = ? AND updated_at < ?", []any{string(models.StatusClosed), dbFormatTime(cutoff)}, dryRun)
+}
+
+// PurgeTombstonedTasks permanently removes (or reports) tombstoned tasks deleted before cutoff.
+func (s *Store) PurgeTombstonedTasks(ctx context.Context, project string, cutoff time.Time, dryRun bool) (*CleanupResult, error) {
+	return s.removeTasksWhere(ctx, project, "status = ? AND deleted_at < ?", []any{string(models.StatusTombstone), dbFormatTime(cutoff)}, dryRun)
+}
+
+// removeTasksWhere deletes the tasks matching where, optionally restricted to one project.
+// Labels, deps, and other task-owned rows go with them through ON DELETE CASCADE.
+func (s *Store) removeTasksWhere(ctx context.Context, project, where string, whereArgs []any, dryRun bool) (*CleanupResult, error) {
 	project = normalizeProject(project)
 
-	var (
-		rows *sql.Rows
-		err  error
-	)
-	if project == "" {
-		rows, err = s.db.QueryContext(ctx, "SELECT id FROM tasks WHERE status = ? AND updated_at < ?", string(models.StatusClosed), dbFormatTime(cutoff))
-	} else {
-		rows, err = s.db.QueryContext(ctx, "SELECT id FROM tasks WHERE project_id = ? AND status = ? AND updated_at < ?", project, string(models.StatusClosed), dbFormatTime(cutoff))
+	query := "SELECT id FROM tasks WHERE " + where
+	args := whereArgs
+	if project != "" {
+		query = "SELECT id FROM tasks WHERE project_id = ? AND " + where
+		args = append([]any{project}, whereArgs...)
 	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -1009,12 +1690,12 @@ func (s *Store) CleanupClosedTasks(ctx context.Context, project string, cutoff t
 		return result, nil
 	}
 
-	args := make([]any, len(ids))
+	deleteArgs := make([]any, len(ids))
 	for i, id := range ids {
-		args[i] = id
+		deleteArgs[i] = id
 	}
-	query := fmt.Sprintf("DELETE FROM tasks WHERE id IN (%s)", placeholders(len(ids)))
-	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+	deleteQuery := fmt.Sprintf("DELETE FROM tasks WHERE id IN (%s)", placeholders(len(ids)))
+	if _, err := s.db.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
 		return nil, err
 	}
 
@@ -1029,9 +1710,6 @@ func insertDeps(ctx context.Context, tx *sql.Tx, childID string, deps []models.D
 	values := make([]string, len(deps))
 	args := make([]any, 0, len(deps)*3)
 	for i, dep := range deps {
-		if !sameTaskProject(childID, dep.ParentID) {
-			return ErrProjectMismatch
-		}
 		values[i] = "(?, ?, ?)"
 		args = append(args, childID, dep.ParentID, dep.Type)
 	}
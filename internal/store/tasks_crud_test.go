@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -104,6 +105,42 @@ func TestCreateTasksAtomicOnError(t *testing.T) {
 	}
 }
 
+func TestCreateTasksSpansChunks(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	count := createTasksChunkSize*2 + 7
+	tasks := make([]TaskCreateInput, 0, count)
+	for i := 0; i < count; i++ {
+		id := fmt.Sprintf("gr-%04d", i)
+		tasks = append(tasks, TaskCreateInput{
+			Task:   &models.Task{ID: id, Title: "Chunked " + id, Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+			Labels: []string{"bulk"},
+		})
+	}
+	// A duplicate in the last chunk rolls back the earlier chunks too.
+	tasks[count-1].Task.ID = tasks[0].Task.ID
+	if err := st.CreateTasks(ctx, tasks); err == nil {
+		t.Fatal("expected duplicate-id error")
+	}
+	if got, err := st.CountTasks(ctx, ListFilter{}); err != nil || got != 0 {
+		t.Fatalf("expected rollback across chunks, got %d (%v)", got, err)
+	}
+
+	tasks[count-1].Task.ID = fmt.Sprintf("gr-%04d", count-1)
+	if err := st.CreateTasks(ctx, tasks); err != nil {
+		t.Fatalf("create tasks: %v", err)
+	}
+	got, err := st.CountTasks(ctx, ListFilter{Labels: []string{"bulk"}})
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if got != count {
+		t.Fatalf("expected %d tasks, got %d", count, got)
+	}
+}
+
 func TestUpdateTask(t *testing.T) {
 	st := testStore(t)
 	ctx := context.Background()
@@ -399,3 +436,85 @@ func TestUpdateTaskCustom(t *testing.T) {
 		t.Fatalf("expected empty custom, got %v", got.Custom)
 	}
 }
+
+func TestChildStatusCountsForTasks(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, task := range []*models.Task{
+		{ID: "gr-ep01", Title: "epic", Status: "open", Type: "epic", Priority: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-ch01", Title: "done", Status: "closed", Type: "task", Priority: 2, ParentID: "gr-ep01", CreatedAt: now, UpdatedAt: now, ClosedAt: &now},
+		{ID: "gr-ch02", Title: "doing", Status: "in_progress", Type: "task", Priority: 2, ParentID: "gr-ep01", CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-ch03", Title: "dropped", Status: "tombstone", Type: "task", Priority: 2, ParentID: "gr-ep01", CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-ch04", Title: "leaf", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+
+	counts, err := st.ChildStatusCountsForTasks(ctx, []string{"gr-ep01", "gr-ch04"})
+	if err != nil {
+		t.Fatalf("child status counts: %v", err)
+	}
+	if len(counts) != 1 {
+		t.Fatalf("expected counts only for the epic, got %#v", counts)
+	}
+	epic := counts["gr-ep01"]
+	if epic["closed"] != 1 || epic["in_progress"] != 1 || epic["tombstone"] != 0 {
+		t.Fatalf("unexpected epic counts: %#v", epic)
+	}
+}
+
+func TestStatementCacheServesHotPathsAndClosesWithStore(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, query := range hotStatements {
+		if st.stmts.cached(query) == nil {
+			t.Fatalf("expected %q to be prepared at open", query)
+		}
+	}
+
+	task := &models.Task{ID: "gr-sc01", Title: "Cached", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := st.CreateTask(ctx, task, []string{"hot"}, nil); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if exists, err := st.TaskExists("gr-sc01"); err != nil || !exists {
+		t.Fatalf("task exists: %v, %v", exists, err)
+	}
+
+	err := st.RunInTx(ctx, func(m ImportMutator) error {
+		exists, err := m.TaskExists("gr-sc01")
+		if err != nil || !exists {
+			return fmt.Errorf("tx task exists: %v, %v", exists, err)
+		}
+		got, err := m.GetTask(ctx, "gr-sc01")
+		if err != nil || got == nil || got.Title != "Cached" {
+			return fmt.Errorf("tx get: %+v, %v", got, err)
+		}
+		labels, err := m.ListLabels(ctx, "gr-sc01")
+		if err != nil || len(labels) != 1 || labels[0] != "hot" {
+			return fmt.Errorf("tx labels: %v, %v", labels, err)
+		}
+		next := &models.Task{ID: "gr-sc02", Title: "Cached too", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now}
+		return m.CreateTask(ctx, next, nil, nil)
+	})
+	if err != nil {
+		t.Fatalf("run in tx: %v", err)
+	}
+	if got, err := st.GetTask(ctx, "gr-sc02"); err != nil || got == nil {
+		t.Fatalf("get task created in tx: %+v, %v", got, err)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	for _, query := range hotStatements {
+		if st.stmts.cached(query) != nil {
+			t.Fatalf("expected %q to be released on close", query)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -68,7 +69,7 @@ func TestReadyTasks(t *testing.T) {
 		t.Fatalf("add dep: %v", err)
 	}
 
-	ready, err := st.ListReadyTasks(ctx, "gr", 0)
+	ready, err := st.ListReadyTasks(ctx, "gr", ReadyOrderUpdated, 0)
 	if err != nil {
 		t.Fatalf("ready: %v", err)
 	}
@@ -83,6 +84,58 @@ func TestReadyTasks(t *testing.T) {
 	}
 }
 
+func TestListReadyTasksOrders(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	old := now.Add(-25 * 24 * time.Hour)
+
+	for _, task := range []*models.Task{
+		{ID: "gr-ro01", Title: "important", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now.Add(-time.Minute)},
+		{ID: "gr-ro02", Title: "aging", Status: "open", Type: "task", Priority: 3, CreatedAt: old, UpdatedAt: now.Add(-time.Hour)},
+		{ID: "gr-ro03", Title: "unblocker", Status: "open", Type: "task", Priority: 3, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-ro04", Title: "waiting 1", Status: "open", Type: "task", Priority: 4, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-ro05", Title: "waiting 2", Status: "open", Type: "task", Priority: 4, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-ro06", Title: "waiting 3", Status: "open", Type: "task", Priority: 4, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+	for _, child := range []string{"gr-ro04", "gr-ro05", "gr-ro06"} {
+		if err := st.AddDependency(ctx, child, "gr-ro03", "blocks"); err != nil {
+			t.Fatalf("add dep: %v", err)
+		}
+	}
+
+	tests := []struct {
+		order ReadyOrder
+		want  []string
+	}{
+		{ReadyOrderUpdated, []string{"gr-ro03", "gr-ro01", "gr-ro02"}},
+		{ReadyOrderPriority, []string{"gr-ro01", "gr-ro02", "gr-ro03"}},
+		// Scores: gr-ro02 10+25 (age), gr-ro03 10+15 (three dependents), gr-ro01 20.
+		{ReadyOrderScore, []string{"gr-ro02", "gr-ro03", "gr-ro01"}},
+	}
+	for _, tc := range tests {
+		ready, err := st.ListReadyTasks(ctx, "gr", tc.order, 0)
+		if err != nil {
+			t.Fatalf("ready %s: %v", tc.order, err)
+		}
+		got := make([]string, 0, len(ready))
+		for _, task := range ready {
+			got = append(got, task.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("order %s: expected %v, got %v", tc.order, tc.want, got)
+		}
+	}
+
+	if _, err := st.ListReadyTasks(ctx, "gr", ReadyOrder("random"), 0); err == nil {
+		t.Fatal("expected error for unknown order")
+	}
+}
+
 func TestListTasksExtendedFilters(t *testing.T) {
 	st := testStore(t)
 	ctx := context.Background()
@@ -311,4 +364,260 @@ func TestListTasksWithSpecRegexLimitOffset(t *testing.T) {
 	if result[0].ID != "gr-rx01" {
 		t.Fatalf("expected gr-rx01 after offset, got %s", result[0].ID)
 	}
+
+	count, err := st.CountTasks(ctx, ListFilter{SpecRegex: "(?i)DOCS/specs/", Limit: 1})
+	if err != nil {
+		t.Fatalf("count with spec regex: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 matching tasks, got %d", count)
+	}
+
+	if _, err := st.ListTasks(ctx, ListFilter{SpecRegex: "("}); err == nil {
+		t.Fatal("expected invalid spec regex to fail")
+	}
+}
+
+func TestListTasksAfterIDKeyset(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	// Updated-at order is the reverse of id order, so pages must ignore it.
+	for i, id := range []string{"gr-ks03", "gr-ks01", "gr-ks02"} {
+		at := now.Add(time.Duration(i) * time.Minute)
+		task := &models.Task{ID: id, Title: id, Status: "open", Type: "task", Priority: 2, CreatedAt: at, UpdatedAt: at}
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+
+	var got []string
+	afterID := ""
+	for page := 0; page < 3; page++ {
+		result, err := st.ListTasks(ctx, ListFilter{Limit: 2, AfterID: afterID, OrderByID: true})
+		if err != nil {
+			t.Fatalf("list page %d: %v", page, err)
+		}
+		if len(result) == 0 {
+			break
+		}
+		for _, task := range result {
+			got = append(got, task.ID)
+		}
+		afterID = result[len(result)-1].ID
+	}
+	if strings.Join(got, ",") != "gr-ks01,gr-ks02,gr-ks03" {
+		t.Fatalf("unexpected keyset order: %v", got)
+	}
+}
+
+func TestListTasksSortKeys(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	tasks := []*models.Task{
+		{ID: "gr-so01", Title: "b", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now.Add(-2 * time.Minute)},
+		{ID: "gr-so02", Title: "c", Status: "open", Type: "task", Priority: 1, CreatedAt: now, UpdatedAt: now.Add(-1 * time.Minute)},
+		{ID: "gr-so03", Title: "a", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+	}
+	for _, task := range tasks {
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		sort []SortKey
+		want string
+	}{
+		{"priority then newest", []SortKey{{Field: "priority"}, {Field: "updated_at", Desc: true}}, "gr-so02,gr-so03,gr-so01"},
+		{"title descending", []SortKey{{Field: "title", Desc: true}}, "gr-so02,gr-so01,gr-so03"},
+		{"ties break on id", []SortKey{{Field: "created_at"}}, "gr-so01,gr-so02,gr-so03"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := st.ListTasks(ctx, ListFilter{Sort: tt.sort})
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			ids := make([]string, len(result))
+			for i, task := range result {
+				ids[i] = task.ID
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestListTasksSearchCoversExtendedFields(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	tasks := []*models.Task{
+		{ID: "gr-sx01", Title: "Deploy", Status: "open", Type: "task", Priority: 2, AcceptanceCriteria: "A rollback plan is documented", CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-sx02", Title: "Schema", Status: "open", Type: "task", Priority: 2, Design: "Use a sidecar table", Assignee: "marguerite", CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-sx03", Title: "Ticket", Status: "open", Type: "task", Priority: 2, Custom: map[string]any{"jira": map[string]any{"key": "OPS-4711"}}, CreatedAt: now, UpdatedAt: now},
+	}
+	for _, task := range tasks {
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: `"rollback plan"`, want: "gr-sx01"},
+		{query: "sidecar", want: "gr-sx02"},
+		{query: "marguerite", want: "gr-sx02"},
+		{query: `"OPS-4711"`, want: "gr-sx03"},
+	}
+	for _, tt := range tests {
+		result, err := st.ListTasks(ctx, ListFilter{SearchQuery: tt.query})
+		if err != nil {
+			t.Fatalf("search %s: %v", tt.query, err)
+		}
+		if len(result) != 1 || result[0].ID != tt.want {
+			t.Fatalf("search %s: expected %s, got %v", tt.query, tt.want, result)
+		}
+	}
+
+	recompute, err := st.Recompute(ctx, true)
+	if err != nil {
+		t.Fatalf("recompute: %v", err)
+	}
+	if len(recompute.FTSStale) != 0 {
+		t.Fatalf("expected freshly indexed rows to be current, got stale %v", recompute.FTSStale)
+	}
+}
+
+func TestListTasksCustomFilter(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, task := range []*models.Task{
+		{ID: "gr-cf01", Title: "infra", Status: "open", Type: "task", Priority: 2, Custom: map[string]any{"team": "infra", "points": float64(3), "urgent": true}, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-cf02", Title: "platform", Status: "open", Type: "task", Priority: 2, Custom: map[string]any{"team": "platform", "points": 2.5, "urgent": false}, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-cf03", Title: "none", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		custom map[string]string
+		want   []string
+	}{
+		{"string", map[string]string{"team": "infra"}, []string{"gr-cf01"}},
+		{"integer number", map[string]string{"points": "3"}, []string{"gr-cf01"}},
+		{"fractional number", map[string]string{"points": "2.5"}, []string{"gr-cf02"}},
+		{"bool", map[string]string{"urgent": "false"}, []string{"gr-cf02"}},
+		{"all keys must match", map[string]string{"team": "infra", "urgent": "false"}, nil},
+		{"missing key", map[string]string{"owner": "alice"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := st.ListTasks(ctx, ListFilter{Custom: tt.custom, OrderByID: true})
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			got := make([]string, 0, len(tasks))
+			for _, task := range tasks {
+				got = append(got, task.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestListTasksCustomExistsFilter(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	for _, task := range []*models.Task{
+		{ID: "gr-ce01", Title: "both", Status: "open", Type: "task", Priority: 2, Custom: map[string]any{"team": "infra", "points": float64(1)}, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-ce02", Title: "team only", Status: "open", Type: "task", Priority: 2, Custom: map[string]any{"team": "infra", "points": nil}, CreatedAt: now, UpdatedAt: now},
+		{ID: "gr-ce03", Title: "none", Status: "open", Type: "task", Priority: 2, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := st.CreateTask(ctx, task, nil, nil); err != nil {
+			t.Fatalf("create %s: %v", task.ID, err)
+		}
+	}
+
+	for _, tt := range []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"team"}, "gr-ce01,gr-ce02"},
+		{[]string{"team", "points"}, "gr-ce01"},
+		{[]string{"owner"}, ""},
+	} {
+		tasks, err := st.ListTasks(ctx, ListFilter{CustomExists: tt.keys, OrderByID: true})
+		if err != nil {
+			t.Fatalf("list %v: %v", tt.keys, err)
+		}
+		got := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			got = append(got, task.ID)
+		}
+		if strings.Join(got, ",") != tt.want {
+			t.Fatalf("custom_exists %v: expected %q, got %v", tt.keys, tt.want, got)
+		}
+	}
+}
+
+func TestListQueryPlansUseFilterIndexes(t *testing.T) {
+	st := testStore(t)
+	ctx := context.Background()
+	since := time.Now().UTC().Add(-24 * time.Hour)
+
+	cases := []struct {
+		name   string
+		filter ListFilter
+		index  string
+	}{
+		{"status", ListFilter{Project: "gr", Statuses: []string{"open"}, Limit: 20}, "idx_tasks_project_status_updated_desc"},
+		{"assignee and status", ListFilter{Project: "gr", Assignee: "alice", Statuses: []string{"in_progress"}, Limit: 20}, "idx_tasks_project_assignee_status"},
+		{"parent", ListFilter{Project: "gr", ParentID: "gr-pa01", Limit: 20}, "idx_tasks_project_parent"},
+		{"closed after", ListFilter{Project: "gr", ClosedAfter: &since, Limit: 20}, "idx_tasks_project_closed_at"},
+		{"labels", ListFilter{Project: "gr", Labels: []string{"backend"}, Limit: 20}, "idx_task_labels_label_task"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, args := buildListQuery(st.dialect, tc.filter)
+			rows, err := st.db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+			if err != nil {
+				t.Fatalf("explain: %v", err)
+			}
+			defer rows.Close()
+			var plan strings.Builder
+			for rows.Next() {
+				var id, parent, notused int
+				var detail string
+				if err := rows.Scan(&id, &parent, &notused, &detail); err != nil {
+					t.Fatalf("scan explain row: %v", err)
+				}
+				plan.WriteString(detail + "\n")
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatalf("explain rows: %v", err)
+			}
+			if !strings.Contains(plan.String(), tc.index) {
+				t.Fatalf("expected plan to use %s, got:\n%s", tc.index, plan.String())
+			}
+		})
+	}
 }
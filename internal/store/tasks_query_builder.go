@@ -2,18 +2,22 @@ package store
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"grns/internal/models"
 )
 
 type listQueryBuilder struct {
-	filter ListFilter
-	query  string
-	args   []any
-	where  []string
+	dialect dialect
+	filter  ListFilter
+	query   string
+	args    []any
+	where   []string
 }
 
-func buildListQuery(filter ListFilter) (string, []any) {
-	builder := &listQueryBuilder{filter: filter}
+func buildListQuery(d dialect, filter ListFilter) (string, []any) {
+	builder := &listQueryBuilder{dialect: d, filter: filter}
 	builder.buildSelect()
 	builder.buildWhere()
 	builder.buildOrder()
@@ -21,12 +25,25 @@ func buildListQuery(filter ListFilter) (string, []any) {
 	return builder.query, builder.args
 }
 
+// buildCountQuery counts what buildListQuery would match, without ordering or
+// pagination.
+func buildCountQuery(d dialect, filter ListFilter) (string, []any) {
+	builder := &listQueryBuilder{dialect: d, filter: filter}
+	builder.buildSelectOf("COUNT(*)", "COUNT(*)")
+	builder.buildWhere()
+	return builder.query, builder.args
+}
+
 func (b *listQueryBuilder) buildSelect() {
-	b.query = "SELECT " + taskColumns + " FROM tasks"
+	b.buildSelectOf(taskColumns, qualifiedTaskColumns)
+}
+
+func (b *listQueryBuilder) buildSelectOf(columns, qualifiedColumns string) {
+	b.query = "SELECT " + columns + " FROM tasks"
 	if b.filter.SearchQuery == "" {
 		return
 	}
-	b.query = "SELECT " + qualifiedTaskColumns + " FROM tasks JOIN tasks_fts ON tasks.id = tasks_fts.task_id AND tasks_fts MATCH ?"
+	b.query = "SELECT " + qualifiedColumns + " FROM tasks " + b.dialect.searchJoin()
 	b.args = append(b.args, b.filter.SearchQuery)
 }
 
@@ -36,13 +53,20 @@ func (b *listQueryBuilder) buildWhere() {
 	b.appendTypes()
 	b.appendPriority()
 	b.appendParentID()
+	b.appendMilestoneID()
 	b.appendLabels()
+	b.appendDepTypes()
 	b.appendAssignee()
 	b.appendIDs()
 	b.appendContainsFilters()
+	b.appendSpecRegex()
 	b.appendTimeFilters()
 	b.appendEmptyDescription()
 	b.appendNoLabels()
+	b.appendGitRefs()
+	b.appendCustom()
+	b.appendCustomExists()
+	b.appendAfterID()
 
 	if len(b.where) == 0 {
 		return
@@ -51,18 +75,40 @@ func (b *listQueryBuilder) buildWhere() {
 }
 
 func (b *listQueryBuilder) buildOrder() {
+	if b.filter.OrderByID || b.filter.AfterID != "" {
+		b.query += " ORDER BY tasks.id"
+		return
+	}
+	if len(b.filter.Sort) > 0 {
+		terms := make([]string, 0, len(b.filter.Sort)+1)
+		for _, key := range b.filter.Sort {
+			column, ok := SortFields[key.Field]
+			if !ok {
+				continue
+			}
+			direction := "ASC"
+			if key.Desc {
+				direction = "DESC"
+			}
+			terms = append(terms, column+" "+direction)
+		}
+		if len(terms) > 0 {
+			b.query += " ORDER BY " + strings.Join(append(terms, "tasks.id ASC"), ", ")
+			return
+		}
+	}
 	if b.filter.SearchQuery != "" {
-		b.query += " ORDER BY tasks_fts.rank"
+		order, usesQuery := b.dialect.searchOrder()
+		b.query += " ORDER BY " + order
+		if usesQuery {
+			b.args = append(b.args, b.filter.SearchQuery)
+		}
 		return
 	}
 	b.query += " ORDER BY updated_at DESC"
 }
 
 func (b *listQueryBuilder) buildPagination() {
-	if b.filter.SpecRegex != "" {
-		return
-	}
-
 	hasLimit := false
 	if b.filter.Limit > 0 {
 		b.query += " LIMIT ?"
@@ -71,7 +117,7 @@ func (b *listQueryBuilder) buildPagination() {
 	}
 	if b.filter.Offset > 0 {
 		if !hasLimit {
-			b.query += " LIMIT -1"
+			b.query += " " + b.dialect.noLimit()
 		}
 		b.query += " OFFSET ?"
 		b.args = append(b.args, b.filter.Offset)
@@ -88,6 +134,10 @@ func (b *listQueryBuilder) appendProject() {
 
 func (b *listQueryBuilder) appendStatuses() {
 	if len(b.filter.Statuses) == 0 {
+		if b.filter.ExcludeTombstones {
+			b.where = append(b.where, "status != ?")
+			b.args = append(b.args, string(models.StatusTombstone))
+		}
 		return
 	}
 	b.where = append(b.where, fmt.Sprintf("status IN (%s)", placeholders(len(b.filter.Statuses))))
@@ -129,6 +179,24 @@ func (b *listQueryBuilder) appendParentID() {
 	b.args = append(b.args, b.filter.ParentID)
 }
 
+func (b *listQueryBuilder) appendMilestoneID() {
+	if b.filter.MilestoneID == "" {
+		return
+	}
+	b.where = append(b.where, "milestone_id = ?")
+	b.args = append(b.args, b.filter.MilestoneID)
+}
+
+// appendSpecRegex matches spec_id with the dialect's regexp operator, so that
+// LIMIT and OFFSET apply to matching rows in SQL. Tasks without a spec never match.
+func (b *listQueryBuilder) appendSpecRegex() {
+	if b.filter.SpecRegex == "" {
+		return
+	}
+	b.where = append(b.where, "tasks.spec_id IS NOT NULL AND tasks.spec_id != '' AND "+b.dialect.matchesRegexp("tasks.spec_id"))
+	b.args = append(b.args, b.filter.SpecRegex)
+}
+
 func (b *listQueryBuilder) appendLabels() {
 	if len(b.filter.Labels) > 0 {
 		b.where = append(b.where, fmt.Sprintf("id IN (SELECT task_id FROM task_labels WHERE label IN (%s) GROUP BY task_id HAVING COUNT(DISTINCT label) = %d)", placeholders(len(b.filter.Labels)), len(b.filter.Labels)))
@@ -144,6 +212,26 @@ func (b *listQueryBuilder) appendLabels() {
 	}
 }
 
+// appendAfterID is the keyset cursor for id-ordered pages.
+func (b *listQueryBuilder) appendAfterID() {
+	if b.filter.AfterID == "" {
+		return
+	}
+	b.where = append(b.where, "tasks.id > ?")
+	b.args = append(b.args, b.filter.AfterID)
+}
+
+// appendDepTypes keeps tasks that depend on some parent through one of the types.
+func (b *listQueryBuilder) appendDepTypes() {
+	if len(b.filter.DepTypes) == 0 {
+		return
+	}
+	b.where = append(b.where, fmt.Sprintf("id IN (SELECT child_id FROM task_deps WHERE type IN (%s))", placeholders(len(b.filter.DepTypes))))
+	for _, depType := range b.filter.DepTypes {
+		b.args = append(b.args, depType)
+	}
+}
+
 func (b *listQueryBuilder) appendAssignee() {
 	if b.filter.Assignee != "" {
 		b.where = append(b.where, "assignee = ?")
@@ -166,15 +254,15 @@ func (b *listQueryBuilder) appendIDs() {
 
 func (b *listQueryBuilder) appendContainsFilters() {
 	if b.filter.TitleContains != "" {
-		b.where = append(b.where, "tasks.title LIKE '%' || ? || '%'")
+		b.where = append(b.where, b.dialect.containsText("tasks.title"))
 		b.args = append(b.args, b.filter.TitleContains)
 	}
 	if b.filter.DescContains != "" {
-		b.where = append(b.where, "tasks.description LIKE '%' || ? || '%'")
+		b.where = append(b.where, b.dialect.containsText("tasks.description"))
 		b.args = append(b.args, b.filter.DescContains)
 	}
 	if b.filter.NotesContains != "" {
-		b.where = append(b.where, "tasks.notes LIKE '%' || ? || '%'")
+		b.where = append(b.where, b.dialect.containsText("tasks.notes"))
 		b.args = append(b.args, b.filter.NotesContains)
 	}
 }
@@ -219,3 +307,46 @@ func (b *listQueryBuilder) appendNoLabels() {
 	}
 	b.where = append(b.where, "id NOT IN (SELECT task_id FROM task_labels)")
 }
+
+func (b *listQueryBuilder) appendGitRefs() {
+	if b.filter.HasGitRefs {
+		b.where = append(b.where, "id IN (SELECT task_id FROM task_git_refs)")
+	}
+	if b.filter.NoGitRefs {
+		b.where = append(b.where, "id NOT IN (SELECT task_id FROM task_git_refs)")
+	}
+	if len(b.filter.GitRefRelations) == 0 {
+		return
+	}
+	b.where = append(b.where, fmt.Sprintf("id IN (SELECT task_id FROM task_git_refs WHERE relation IN (%s))", placeholders(len(b.filter.GitRefRelations))))
+	for _, relation := range b.filter.GitRefRelations {
+		b.args = append(b.args, relation)
+	}
+}
+
+// appendCustom matches custom values as text: JSON booleans compare as
+// "true"/"false" and numbers in their shortest form, so "3" matches 3.
+func (b *listQueryBuilder) appendCustom() {
+	if len(b.filter.Custom) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(b.filter.Custom))
+	for key := range b.filter.Custom {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		where, args := b.dialect.customFieldEquals(key, b.filter.Custom[key])
+		b.where = append(b.where, where)
+		b.args = append(b.args, args...)
+	}
+}
+
+// appendCustomExists treats a JSON null like a missing key.
+func (b *listQueryBuilder) appendCustomExists() {
+	for _, key := range b.filter.CustomExists {
+		where, args := b.dialect.customFieldExists(key)
+		b.where = append(b.where, where)
+		b.args = append(b.args, args...)
+	}
+}
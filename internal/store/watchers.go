@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"grns/internal/models"
+)
+
+// AddTaskWatcher subscribes user to a task. Watching an already watched task is a no-op.
+func (s *Store) AddTaskWatcher(ctx context.Context, taskID, user string, createdAt time.Time) error {
+	if createdAt.IsZero() {
+		createdAt = time.Now().UTC()
+	}
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO task_watchers (task_id, %[1]s, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(task_id, %[1]s) DO NOTHING
+	`, s.dialect.quoteIdent("user")), taskID, user, dbFormatTime(createdAt))
+	return err
+}
+
+// RemoveTaskWatcher unsubscribes user from a task and reports whether they were watching.
+func (s *Store) RemoveTaskWatcher(ctx context.Context, taskID, user string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM task_watchers WHERE task_id = ? AND "+s.dialect.quoteIdent("user")+" = ?", taskID, user)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ListTaskWatchers lists one task's watchers ordered by user.
+func (s *Store) ListTaskWatchers(ctx context.Context, taskID string) ([]models.TaskWatcher, error) {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT task_id, %[1]s, created_at
+		FROM task_watchers
+		WHERE task_id = ?
+		ORDER BY %[1]s
+	`, s.dialect.quoteIdent("user")), taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	watchers := []models.TaskWatcher{}
+	for rows.Next() {
+		var (
+			watcher   models.TaskWatcher
+			createdAt string
+		)
+		if err := rows.Scan(&watcher.TaskID, &watcher.User, &createdAt); err != nil {
+			return nil, err
+		}
+		parsed, err := dbParseTime(createdAt)
+		if err != nil {
+			return nil, err
+		}
+		watcher.CreatedAt = parsed
+		watchers = append(watchers, watcher)
+	}
+	return watchers, rows.Err()
+}
+
+// ListWatchersForTasks returns watcher user names keyed by task id, each list ordered by user.
+func (s *Store) ListWatchersForTasks(ctx context.Context, ids []string) (map[string][]string, error) {
+	watchers := make(map[string][]string)
+	if len(ids) == 0 {
+		return watchers, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT task_id, %[1]s
+		FROM task_watchers
+		WHERE task_id IN (%[2]s)
+		ORDER BY task_id, %[1]s
+	`, s.dialect.quoteIdent("user"), placeholders(len(ids)))
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, user string
+		if err := rows.Scan(&taskID, &user); err != nil {
+			return nil, err
+		}
+		watchers[taskID] = append(watchers[taskID], user)
+	}
+	return watchers, rows.Err()
+}
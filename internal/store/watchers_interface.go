@@ -0,0 +1,18 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"grns/internal/models"
+)
+
+// WatcherStore is the persistence surface for task watchers.
+type WatcherStore interface {
+	AddTaskWatcher(ctx context.Context, taskID, user string, createdAt time.Time) error
+	RemoveTaskWatcher(ctx context.Context, taskID, user string) (bool, error)
+	ListTaskWatchers(ctx context.Context, taskID string) ([]models.TaskWatcher, error)
+	ListWatchersForTasks(ctx context.Context, ids []string) (map[string][]string, error)
+}
+
+var _ WatcherStore = (*Store)(nil)
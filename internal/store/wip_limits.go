@@ -0,0 +1,146 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WIPLimit caps how many tasks may occupy one status, optionally per assignee.
+// AssigneeCap marks the global per-assignee in_progress cap so callers can
+// report it apart from per-status limits; it does not change the check.
+type WIPLimit struct {
+	Status      string
+	Max         int
+	PerAssignee bool
+	AssigneeCap bool
+}
+
+// WIPLimitExceededError reports the tasks already occupying a capped status.
+type WIPLimitExceededError struct {
+	Status      string
+	Max         int
+	Assignee    string
+	TaskIDs     []string
+	AssigneeCap bool
+}
+
+func (e *WIPLimitExceededError) Error() string {
+	scope := ""
+	if e.Assignee != "" {
+		scope = fmt.Sprintf(" for assignee %s", e.Assignee)
+	}
+	return fmt.Sprintf("wip limit %d reached for status %s%s: %s", e.Max, e.Status, scope, strings.Join(e.TaskIDs, ", "))
+}
+
+// UpdateTaskWithinWIPLimits applies update after checking limits in the same transaction.
+// Each check only applies when the update moves the task into the limited status
+// (or, for per-assignee limits, into another assignee's slot in that status,
+// including by reassigning a task already there).
+func (s *Store) UpdateTaskWithinWIPLimits(ctx context.Context, id string, update TaskUpdate, limits []WIPLimit) (err error) {
+	project := projectFromTaskID(id)
+	if project == "" {
+		return fmt.Errorf("invalid task id")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = updateTaskWithinWIPLimitsTx(ctx, tx, s.dialect, project, id, update, limits); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func updateTaskWithinWIPLimitsTx(ctx context.Context, tx *sql.Tx, d dialect, project, id string, update TaskUpdate, limits []WIPLimit) error {
+	if len(limits) > 0 {
+		if err := lockProjectTx(ctx, tx, d, project); err != nil {
+			return err
+		}
+	}
+	var (
+		currentStatus   string
+		currentAssignee sql.NullString
+	)
+	err := tx.QueryRowContext(ctx, "SELECT status, assignee FROM tasks WHERE id = ? AND project_id = ?", id, project).Scan(&currentStatus, &currentAssignee)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return err
+	default:
+		for _, limit := range limits {
+			if err := checkWIPLimit(ctx, tx, project, id, currentStatus, currentAssignee.String, update, limit); err != nil {
+				return err
+			}
+		}
+	}
+	return updateTaskExec(ctx, tx, id, update)
+}
+
+// lockProjectTx locks the project's row until the transaction ends, so that
+// transactions checking the project's WIP limits run one after another
+// instead of each counting a free slot. SQLite transactions already do.
+func lockProjectTx(ctx context.Context, tx *sql.Tx, d dialect, project string) error {
+	lock := d.forUpdate(false)
+	if lock == "" {
+		return nil
+	}
+	now := dbFormatTime(time.Now().UTC())
+	if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO projects (id, created_at, updated_at) VALUES (?, ?, ?)", project, now, now); err != nil {
+		return err
+	}
+	var locked string
+	return tx.QueryRowContext(ctx, "SELECT id FROM projects WHERE id = ?"+lock, project).Scan(&locked)
+}
+
+func checkWIPLimit(ctx context.Context, tx *sql.Tx, project, id, currentStatus, currentAssignee string, update TaskUpdate, limit WIPLimit) error {
+	status := currentStatus
+	if update.Status != nil {
+		status = *update.Status
+	}
+	if limit.Max <= 0 || status != limit.Status {
+		return nil
+	}
+
+	assignee := strings.TrimSpace(currentAssignee)
+	if update.Assignee != nil {
+		assignee = strings.TrimSpace(*update.Assignee)
+	}
+	if currentStatus == limit.Status && (!limit.PerAssignee || assignee == strings.TrimSpace(currentAssignee)) {
+		return nil
+	}
+	if limit.PerAssignee && assignee == "" {
+		return nil
+	}
+
+	query := "SELECT id FROM tasks WHERE project_id = ? AND status = ? AND id != ?"
+	args := []any{project, limit.Status, id}
+	if limit.PerAssignee {
+		query += " AND assignee = ?"
+		args = append(args, assignee)
+	}
+	query += " ORDER BY id"
+
+	ids, err := queryIDs(ctx, tx, query, args...)
+	if err != nil {
+		return err
+	}
+	if len(ids) < limit.Max {
+		return nil
+	}
+
+	exceeded := &WIPLimitExceededError{Status: limit.Status, Max: limit.Max, TaskIDs: ids, AssigneeCap: limit.AssigneeCap}
+	if limit.PerAssignee {
+		exceeded.Assignee = assignee
+	}
+	return exceeded
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"grns/internal/models"
+)
+
+const taskWorklogColumns = "w.id, w.task_id, w.actor, w.seconds, w.note, w.started_at, w.created_at"
+
+// CreateTaskWorklog inserts one task worklog row.
+func (s *Store) CreateTaskWorklog(ctx context.Context, worklog *models.TaskWorklog) error {
+	if worklog == nil {
+		return fmt.Errorf("task worklog is required")
+	}
+	if worklog.CreatedAt.IsZero() {
+		worklog.CreatedAt = time.Now().UTC()
+	}
+	if worklog.StartedAt.IsZero() {
+		worklog.StartedAt = worklog.CreatedAt
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO task_worklogs (id, task_id, actor, seconds, note, started_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`,
+		worklog.ID,
+		worklog.TaskID,
+		worklog.Actor,
+		worklog.Seconds,
+		nullIfEmpty(worklog.Note),
+		dbFormatTime(worklog.StartedAt),
+		dbFormatTime(worklog.CreatedAt),
+	)
+	return err
+}
+
+// ListTaskWorklogs lists worklogs for one task ordered by start time.
+func (s *Store) ListTaskWorklogs(ctx context.Context, project, taskID string) ([]models.TaskWorklog, error) {
+	project = normalizeProject(project)
+
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if project == "" {
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT `+taskWorklogColumns+`
+			FROM task_worklogs w
+			WHERE w.task_id = ?
+			ORDER BY w.started_at, w.rowid
+		`, taskID)
+	} else {
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT `+taskWorklogColumns+`
+			FROM task_worklogs w
+			JOIN tasks t ON t.id = w.task_id
+			WHERE w.task_id = ? AND t.project_id = ?
+			ORDER BY w.started_at, w.rowid
+		`, taskID, project)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	worklogs := []models.TaskWorklog{}
+	for rows.Next() {
+		worklog, err := scanTaskWorklog(rows)
+		if err != nil {
+			return nil, err
+		}
+		worklogs = append(worklogs, worklog)
+	}
+	return worklogs, rows.Err()
+}
+
+// SumWorklogSecondsForTasks returns total logged seconds keyed by task id.
+// Tasks without worklogs are absent from the map.
+func (s *Store) SumWorklogSecondsForTasks(ctx context.Context, ids []string) (map[string]int64, error) {
+	totals := make(map[string]int64)
+	if len(ids) == 0 {
+		return totals, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT task_id, SUM(seconds)
+		FROM task_worklogs
+		WHERE task_id IN (%s)
+		GROUP BY task_id
+	`, placeholders(len(ids)))
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID string
+		var total int64
+		if err := rows.Scan(&taskID, &total); err != nil {
+			return nil, err
+		}
+		totals[taskID] = total
+	}
+	return totals, rows.Err()
+}
+
+// TaskWorklogIDExists reports whether a worklog id is already taken.
+func (s *Store) TaskWorklogIDExists(ctx context.Context, id string) (bool, error) {
+	var exists int
+	err := s.db.QueryRowContext(ctx, "SELECT 1 FROM task_worklogs WHERE id = ?", id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func scanTaskWorklog(scanner interface {
+	Scan(dest ...any) error
+}) (models.TaskWorklog, error) {
+	worklog := models.TaskWorklog{}
+	var note sql.NullString
+	var startedAt, createdAt string
+	if err := scanner.Scan(&worklog.ID, &worklog.TaskID, &worklog.Actor, &worklog.Seconds, &note, &startedAt, &createdAt); err != nil {
+		return models.TaskWorklog{}, err
+	}
+	parsedStarted, err := dbParseTime(startedAt)
+	if err != nil {
+		return models.TaskWorklog{}, err
+	}
+	parsedCreated, err := dbParseTime(createdAt)
+	if err != nil {
+		return models.TaskWorklog{}, err
+	}
+	worklog.Project = projectFromTaskID(worklog.TaskID)
+	worklog.Note = note.String
+	worklog.StartedAt = parsedStarted
+	worklog.CreatedAt = parsedCreated
+	return worklog, nil
+}
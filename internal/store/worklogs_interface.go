@@ -0,0 +1,17 @@
+package store
+
+import (
+	"context"
+
+	"grns/internal/models"
+)
+
+// WorklogStore is the persistence surface for task work log entries.
+type WorklogStore interface {
+	CreateTaskWorklog(ctx context.Context, worklog *models.TaskWorklog) error
+	ListTaskWorklogs(ctx context.Context, project, taskID string) ([]models.TaskWorklog, error)
+	SumWorklogSecondsForTasks(ctx context.Context, ids []string) (map[string]int64, error)
+	TaskWorklogIDExists(ctx context.Context, id string) (bool, error)
+}
+
+var _ WorklogStore = (*Store)(nil)